@@ -0,0 +1,192 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/deemkeen/stegodon/activitypub"
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+// ParseAccountRef splits @user, @user@domain, user@domain or a profile URL into username and domain
+func ParseAccountRef(ref string) (string, string, error) {
+	ref = strings.TrimSpace(ref)
+	if username, userDomain, ok := util.ParseActivityPubURL(ref); ok {
+		return username, userDomain, nil
+	}
+	username, userDomain, _ := strings.Cut(strings.TrimPrefix(ref, "@"), "@")
+	if username == "" || strings.Contains(username, "/") || strings.Contains(userDomain, "@") {
+		return "", "", fmt.Errorf("invalid account: %s (use @user, @user@domain or a profile URL)", ref)
+	}
+	return username, userDomain, nil
+}
+
+// IsLocalDomain reports whether the domain of a handle refers to this server
+func IsLocalDomain(conf *util.AppConfig, userDomain string) bool {
+	return userDomain == "" || strings.EqualFold(userDomain, conf.Conf.SslDomain)
+}
+
+// Follow follows a local or known remote account by id. Following again is not an error.
+func Follow(database db.Store, conf *util.AppConfig, account *domain.Account, targetId uuid.UUID) error {
+	if targetId == account.Id {
+		return fmt.Errorf("you cannot follow yourself")
+	}
+
+	if err, target := database.ReadAccById(targetId); err == nil && target != nil {
+		isFollowing, err := database.IsFollowingLocal(account.Id, target.Id)
+		if err != nil || isFollowing {
+			return err
+		}
+		if err := database.CreateLocalFollow(account.Id, target.Id); err != nil {
+			return err
+		}
+		notification := &domain.Notification{
+			Id:               uuid.New(),
+			AccountId:        target.Id,
+			NotificationType: domain.NotificationFollow,
+			ActorId:          account.Id,
+			ActorUsername:    account.Username,
+			Read:             false,
+			CreatedAt:        time.Now(),
+		}
+		if err := database.CreateNotification(notification); err != nil {
+			log.Printf("Failed to create follow notification: %v", err)
+		}
+		return nil
+	}
+
+	err, remote := database.ReadRemoteAccountById(targetId)
+	if err != nil || remote == nil {
+		return ErrNotFound
+	}
+	if !conf.Conf.WithAp {
+		return fmt.Errorf("federation is disabled on this server")
+	}
+	if err := activitypub.SendFollow(account, remote.ActorURI, conf); err != nil {
+		if errors.Is(err, activitypub.ErrAlreadyFollowing) || errors.Is(err, activitypub.ErrFollowPending) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// Unfollow unfollows a local or remote account by id. Not following it is not an error.
+func Unfollow(database db.Store, conf *util.AppConfig, account *domain.Account, targetId uuid.UUID) error {
+	err, follow := database.ReadFollowByAccountIds(account.Id, targetId)
+	if err != nil || follow == nil {
+		return nil
+	}
+
+	if err, target := database.ReadAccById(targetId); err == nil && target != nil {
+		return database.DeleteLocalFollow(account.Id, target.Id)
+	}
+
+	err, remote := database.ReadRemoteAccountById(targetId)
+	if err != nil || remote == nil {
+		return database.DeleteFollowByAccountIds(account.Id, targetId)
+	}
+	if conf.Conf.WithAp {
+		if err := activitypub.SendUndo(account, follow, remote, conf); err != nil {
+			// Continue with the local delete even if the remote server is not told
+			log.Printf("Warning: Failed to send Undo activity: %v", err)
+		}
+	}
+	if follow.URI != "" {
+		return database.DeleteFollowByURI(follow.URI)
+	}
+	return database.DeleteFollowByAccountIds(account.Id, targetId)
+}
+
+// FollowAccount follows an account by reference (see ParseAccountRef). Remote accounts
+// are looked up with WebFinger, so they need not be known yet. It reports whether the
+// follow is pending until the remote server accepts it.
+func FollowAccount(database db.Store, conf *util.AppConfig, account *domain.Account, ref string) (bool, error) {
+	username, userDomain, err := ParseAccountRef(ref)
+	if err != nil {
+		return false, err
+	}
+
+	if IsLocalDomain(conf, userDomain) {
+		err, target := database.ReadAccByUsername(username)
+		if err != nil || target == nil {
+			return false, fmt.Errorf("user not found: %s", username)
+		}
+		return false, Follow(database, conf, account, target.Id)
+	}
+
+	if !conf.Conf.WithAp {
+		return false, fmt.Errorf("federation is disabled on this server")
+	}
+	actorURI, err := activitypub.ResolveHandle(username, userDomain)
+	if err != nil {
+		return false, fmt.Errorf("webfinger resolution failed: %w", err)
+	}
+	if err := activitypub.SendFollow(account, actorURI, conf); err != nil {
+		// Following again is not an error for scripts
+		if errors.Is(err, activitypub.ErrAlreadyFollowing) {
+			return false, nil
+		}
+		if errors.Is(err, activitypub.ErrFollowPending) {
+			return true, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// accountIdByRef looks up the id of a local or known remote account
+func accountIdByRef(database db.Store, conf *util.AppConfig, ref string) (uuid.UUID, error) {
+	username, userDomain, err := ParseAccountRef(ref)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if IsLocalDomain(conf, userDomain) {
+		err, acc := database.ReadAccByUsername(username)
+		if err != nil || acc == nil {
+			return uuid.Nil, fmt.Errorf("user not found: %s", username)
+		}
+		return acc.Id, nil
+	}
+	err, remote := database.ReadRemoteAccountByHandle(username, userDomain)
+	if err != nil || remote == nil {
+		return uuid.Nil, fmt.Errorf("account not found: @%s@%s", username, userDomain)
+	}
+	return remote.Id, nil
+}
+
+// UnfollowAccount unfollows an account by reference. Not following it is not an error.
+func UnfollowAccount(database db.Store, conf *util.AppConfig, account *domain.Account, ref string) error {
+	targetId, err := accountIdByRef(database, conf, ref)
+	if err != nil {
+		return err
+	}
+	return Unfollow(database, conf, account, targetId)
+}
+
+// ReadFollows returns the accounts and hashtags followed by accountId, which decide
+// what events can change its home timeline
+func ReadFollows(database db.Store, accountId uuid.UUID) (*events.Follows, error) {
+	err, following := database.ReadFollowingByAccountId(accountId)
+	if err != nil {
+		return nil, err
+	}
+	err, hashtags := database.ReadFollowedHashtags(accountId)
+	if err != nil {
+		return nil, err
+	}
+	follows := &events.Follows{Accounts: map[uuid.UUID]bool{}, Hashtags: len(hashtags) > 0}
+	if following != nil {
+		for _, follow := range *following {
+			follows.Accounts[follow.TargetAccountId] = true
+		}
+	}
+	return follows, nil
+}
@@ -0,0 +1,47 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/deemkeen/stegodon/util"
+)
+
+func TestParseAccountRef(t *testing.T) {
+	tests := []struct {
+		ref, username, domain string
+		ok                    bool
+	}{
+		{"alice", "alice", "", true},
+		{"@alice", "alice", "", true},
+		{"bob@remote.example", "bob", "remote.example", true},
+		{"@bob@remote.example", "bob", "remote.example", true},
+		{"https://remote.example/@bob", "bob", "remote.example", true},
+		{"https://remote.example/users/bob", "bob", "remote.example", true},
+		{"@", "", "", false},
+		{"@bob@remote@example", "", "", false},
+		{"remote.example/nothing", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			username, userDomain, err := ParseAccountRef(tt.ref)
+			if (err == nil) != tt.ok {
+				t.Fatalf("ParseAccountRef(%q) error = %v, want ok %v", tt.ref, err, tt.ok)
+			}
+			if username != tt.username || userDomain != tt.domain {
+				t.Errorf("ParseAccountRef(%q) = %q, %q, want %q, %q", tt.ref, username, userDomain, tt.username, tt.domain)
+			}
+		})
+	}
+}
+
+func TestIsLocalDomain(t *testing.T) {
+	conf := &util.AppConfig{}
+	conf.Conf.SslDomain = "stegodon.example"
+
+	if !IsLocalDomain(conf, "") || !IsLocalDomain(conf, "Stegodon.Example") {
+		t.Error("Expected empty and own domains to be local")
+	}
+	if IsLocalDomain(conf, "remote.example") {
+		t.Error("Expected other domains to be remote")
+	}
+}
@@ -0,0 +1,218 @@
+package actions
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/deemkeen/stegodon/activitypub"
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+// activityURI returns a new URI for an outgoing Like or Announce, empty without federation
+func activityURI(conf *util.AppConfig) string {
+	if !conf.Conf.WithAp {
+		return ""
+	}
+	return fmt.Sprintf("https://%s/activities/%s", conf.Conf.SslDomain, uuid.New().String())
+}
+
+// notifyNoteAuthor notifies the local author of a note about an interaction by actor
+func notifyNoteAuthor(database db.Store, note *domain.Note, actor *domain.Account, notificationType domain.NotificationType) {
+	err, author := database.ReadAccByUsername(note.CreatedBy)
+	if err != nil || author == nil || author.Id == actor.Id {
+		return
+	}
+	notification := &domain.Notification{
+		Id:               uuid.New(),
+		AccountId:        author.Id,
+		NotificationType: notificationType,
+		ActorId:          actor.Id,
+		ActorUsername:    actor.Username,
+		ActorDomain:      "", // Empty for local users
+		NoteId:           note.Id,
+		NoteURI:          note.ObjectURI,
+		NotePreview:      activitypub.NotePreview(note.Message),
+		Read:             false,
+		CreatedAt:        time.Now(),
+	}
+	if err := database.CreateNotification(notification); err != nil {
+		log.Printf("Failed to create %s notification: %v", notificationType, err)
+	}
+}
+
+// HasLike reports whether the account likes the target
+func HasLike(database db.Store, accountId uuid.UUID, target *Target) (bool, error) {
+	if target.IsRemote() {
+		return database.HasLikeByObjectURI(accountId, target.ObjectURI)
+	}
+	return database.HasLike(accountId, target.Note.Id)
+}
+
+// HasBoost reports whether the account boosted the target
+func HasBoost(database db.Store, accountId uuid.UUID, target *Target) (bool, error) {
+	if target.IsRemote() {
+		return database.HasBoostByObjectURI(accountId, target.ObjectURI)
+	}
+	return database.HasBoost(accountId, target.Note.Id)
+}
+
+// SetLike likes or unlikes a post. Setting the current state again is a no-op.
+func SetLike(database db.Store, conf *util.AppConfig, account *domain.Account, target *Target, like bool) error {
+	hasLike, err := HasLike(database, account.Id, target)
+	if err != nil || hasLike == like {
+		return err
+	}
+
+	if !like {
+		var existing *domain.Like
+		if target.IsRemote() {
+			err, existing = database.ReadLikeByAccountAndObjectURI(account.Id, target.ObjectURI)
+			if err == nil {
+				err = database.DeleteLikeByAccountAndObjectURI(account.Id, target.ObjectURI)
+			}
+			if err == nil {
+				if err := database.DecrementLikeCountByObjectURI(target.ObjectURI); err != nil {
+					log.Printf("Failed to decrement activity like count: %v", err)
+				}
+			}
+		} else {
+			err, existing = database.ReadLikeByAccountAndNote(account.Id, target.Note.Id)
+			if err == nil {
+				err = database.DeleteLikeByAccountAndNote(account.Id, target.Note.Id)
+			}
+			if err == nil {
+				if err := database.DecrementLikeCountByNoteId(target.Note.Id); err != nil {
+					log.Printf("Failed to decrement like count: %v", err)
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if conf.Conf.WithAp && target.ObjectURI != "" && existing != nil {
+			go func() {
+				if err := activitypub.SendUndoLike(account, target.ObjectURI, existing.URI, conf); err != nil {
+					log.Printf("Failed to federate unlike: %v", err)
+				}
+			}()
+		}
+		return nil
+	}
+
+	newLike := &domain.Like{
+		Id:        uuid.New(),
+		AccountId: account.Id,
+		URI:       activityURI(conf),
+		CreatedAt: time.Now(),
+	}
+	if target.IsRemote() {
+		if err := database.CreateLikeByObjectURI(newLike, target.ObjectURI); err != nil {
+			return err
+		}
+		if err := database.IncrementLikeCountByObjectURI(target.ObjectURI); err != nil {
+			log.Printf("Failed to increment activity like count: %v", err)
+		}
+	} else {
+		newLike.NoteId = target.Note.Id
+		if err := database.CreateLike(newLike); err != nil {
+			return err
+		}
+		if err := database.IncrementLikeCountByNoteId(target.Note.Id); err != nil {
+			log.Printf("Failed to increment like count: %v", err)
+		}
+		notifyNoteAuthor(database, target.Note, account, domain.NotificationLike)
+	}
+
+	if conf.Conf.WithAp && target.ObjectURI != "" {
+		go func() {
+			if err := activitypub.SendLike(account, target.ObjectURI, conf); err != nil {
+				log.Printf("Failed to federate like: %v", err)
+			}
+		}()
+	}
+	return nil
+}
+
+// SetBoost boosts or unboosts a post. Setting the current state again is a no-op.
+func SetBoost(database db.Store, conf *util.AppConfig, account *domain.Account, target *Target, boost bool) error {
+	hasBoost, err := HasBoost(database, account.Id, target)
+	if err != nil || hasBoost == boost {
+		return err
+	}
+
+	if !boost {
+		var existing *domain.Boost
+		if target.IsRemote() {
+			err, existing = database.ReadBoostByAccountAndObjectURI(account.Id, target.ObjectURI)
+			if err == nil {
+				err = database.DeleteBoostByAccountAndObjectURI(account.Id, target.ObjectURI)
+			}
+			if err == nil {
+				if err := database.DecrementBoostCountByObjectURI(target.ObjectURI); err != nil {
+					log.Printf("Failed to decrement activity boost count: %v", err)
+				}
+			}
+		} else {
+			err, existing = database.ReadBoostByAccountAndNote(account.Id, target.Note.Id)
+			if err == nil {
+				err = database.DeleteBoostByAccountAndNote(account.Id, target.Note.Id)
+			}
+			if err == nil {
+				if err := database.DecrementBoostCountByNoteId(target.Note.Id); err != nil {
+					log.Printf("Failed to decrement boost count: %v", err)
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if conf.Conf.WithAp && target.ObjectURI != "" && existing != nil {
+			go func() {
+				if err := activitypub.SendUndoAnnounce(account, target.ObjectURI, existing.URI, conf); err != nil {
+					log.Printf("Failed to federate unboost: %v", err)
+				}
+			}()
+		}
+		return nil
+	}
+
+	newBoost := &domain.Boost{
+		Id:        uuid.New(),
+		AccountId: account.Id,
+		URI:       activityURI(conf),
+		CreatedAt: time.Now(),
+	}
+	if target.IsRemote() {
+		if err := database.CreateBoostByObjectURI(newBoost, target.ObjectURI); err != nil {
+			return err
+		}
+		if err := database.IncrementBoostCountByObjectURI(target.ObjectURI); err != nil {
+			log.Printf("Failed to increment activity boost count: %v", err)
+		}
+	} else {
+		newBoost.NoteId = target.Note.Id
+		if err := database.CreateBoost(newBoost); err != nil {
+			return err
+		}
+		if err := database.IncrementBoostCountByNoteId(target.Note.Id); err != nil {
+			log.Printf("Failed to increment boost count: %v", err)
+		}
+		notifyNoteAuthor(database, target.Note, account, domain.NotificationBoost)
+	}
+
+	if conf.Conf.WithAp && target.ObjectURI != "" {
+		boostURI := newBoost.URI
+		go func() {
+			if err := activitypub.SendAnnounce(account, target.ObjectURI, boostURI, conf); err != nil {
+				log.Printf("Failed to federate boost: %v", err)
+			}
+		}()
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package actions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/util"
+)
+
+func TestSetLikeIsIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "database.db")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatalf("Failed to create database file: %v", err)
+	}
+	database, err := db.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+	if err := database.CreateDB(); err != nil {
+		t.Fatalf("CreateDB failed: %v", err)
+	}
+	if err := database.RunMigrations(); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+	for _, username := range []string{"alice", "bob"} {
+		if err := database.CreateAccountWithPublicKey(username, "ssh-ed25519 AAAA"+username); err != nil {
+			t.Fatalf("Failed to create account %s: %v", username, err)
+		}
+	}
+	_, alice := database.ReadAccByUsername("alice")
+	_, bob := database.ReadAccByUsername("bob")
+	noteId, err := database.CreateNote(alice.Id, "hello")
+	if err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+
+	conf := &util.AppConfig{}
+	conf.Conf.SslDomain = "stegodon.example"
+	target, err := ReadTarget(database, noteId)
+	if err != nil {
+		t.Fatalf("ReadTarget failed: %v", err)
+	}
+
+	likeCount := func() int {
+		_, note := database.ReadNoteId(noteId)
+		return note.LikeCount
+	}
+	for i := 0; i < 2; i++ {
+		if err := SetLike(database, conf, bob, target, true); err != nil {
+			t.Fatalf("SetLike(true) failed: %v", err)
+		}
+	}
+	if count := likeCount(); count != 1 {
+		t.Errorf("Expected 1 like after liking twice, got %d", count)
+	}
+	if has, _ := HasLike(database, bob.Id, target); !has {
+		t.Error("Expected bob to like the note")
+	}
+	if count, _ := database.ReadUnreadNotificationCount(alice.Id); count != 1 {
+		t.Errorf("Expected 1 notification for the author, got %d", count)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := SetLike(database, conf, bob, target, false); err != nil {
+			t.Fatalf("SetLike(false) failed: %v", err)
+		}
+	}
+	if count := likeCount(); count != 0 {
+		t.Errorf("Expected no likes after unliking twice, got %d", count)
+	}
+}
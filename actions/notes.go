@@ -0,0 +1,99 @@
+package actions
+
+import (
+	"log"
+
+	"github.com/deemkeen/stegodon/activitypub"
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+// CreateNote posts a note, optionally as a reply, the same way the TUI editor does,
+// and returns its id
+func CreateNote(database db.Store, conf *util.AppConfig, account *domain.Account, message string, inReplyToURI string) (uuid.UUID, error) {
+	noteId, err := database.CreateNoteWithReply(account.Id, message, inReplyToURI)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	activitypub.NoteCreated(noteId, account, message, inReplyToURI, conf)
+
+	// Federate the note via ActivityPub (background task)
+	if conf.Conf.WithAp {
+		go func() {
+			err, createdNote := database.ReadNoteIdWithReplyInfo(noteId)
+			if err != nil {
+				log.Printf("Failed to read created note for federation: %v", err)
+				return
+			}
+			if err := activitypub.SendCreate(createdNote, account, conf); err != nil {
+				log.Printf("Failed to federate note: %v", err)
+			}
+		}()
+	}
+
+	return noteId, nil
+}
+
+// Reply posts a reply to a local or remote post and returns the id of the new note
+func Reply(database db.Store, conf *util.AppConfig, account *domain.Account, ref string, message string) (uuid.UUID, error) {
+	target, err := ReadTargetByRef(database, conf, ref)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return CreateNote(database, conf, account, message, target.ReplyURI())
+}
+
+// EditNote replaces the text of one of the account's notes, or the body of an article,
+// and federates the update like the TUI editor
+func EditNote(database db.Store, conf *util.AppConfig, account *domain.Account, noteId uuid.UUID, message string) error {
+	note, err := ownNote(database, account, noteId)
+	if err != nil {
+		return err
+	}
+	if note.Title != "" {
+		err = database.UpdateArticle(noteId, note.Title, message)
+	} else {
+		err = database.UpdateNote(noteId, message)
+	}
+	if err != nil {
+		return err
+	}
+
+	activitypub.LinkHashtags(noteId, message)
+
+	// Federate the update via ActivityPub (background task)
+	if conf.Conf.WithAp {
+		go func() {
+			err, updated := database.ReadNoteId(noteId)
+			if err != nil {
+				log.Printf("Failed to read note for federation: %v", err)
+				return
+			}
+			if err := activitypub.SendUpdate(updated, account, conf); err != nil {
+				log.Printf("Failed to federate note update: %v", err)
+			}
+		}()
+	}
+	return nil
+}
+
+// DeleteNote deletes one of the account's notes and federates the deletion
+func DeleteNote(database db.Store, conf *util.AppConfig, account *domain.Account, noteId uuid.UUID) error {
+	if _, err := ownNote(database, account, noteId); err != nil {
+		return err
+	}
+	if err := database.DeleteNoteById(noteId); err != nil {
+		return err
+	}
+	if conf.Conf.WithAp {
+		go func() {
+			if err := activitypub.SendDelete(noteId, account, conf); err != nil {
+				log.Printf("Failed to federate note deletion: %v", err)
+			}
+		}()
+	}
+	return nil
+}
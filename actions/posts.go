@@ -0,0 +1,132 @@
+// Package actions holds what users do to posts and accounts: posting, editing, deleting,
+// liking, boosting and following. The TUI, the SSH CLI and the Mastodon client API all
+// call it, so every front end makes the same database changes, sends the same
+// notifications and federates the same activities.
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+// ErrNotFound is returned when the post or account an action refers to does not exist
+var ErrNotFound = errors.New("record not found")
+
+// Target is the post an action applies to
+type Target struct {
+	Note      *domain.Note // Local note (nil for remote posts)
+	ObjectURI string       // ActivityPub object id, empty for local notes that were never federated
+}
+
+// IsRemote reports whether the target is a remote post
+func (t *Target) IsRemote() bool {
+	return t.Note == nil
+}
+
+// ReplyURI returns the URI a reply to the target refers to
+func (t *Target) ReplyURI() string {
+	if t.ObjectURI != "" {
+		return t.ObjectURI
+	}
+	return "local:" + t.Note.Id.String()
+}
+
+// ReadTarget looks up a post by id, which is either a local note id or a remote activity id
+func ReadTarget(database db.Store, postId uuid.UUID) (*Target, error) {
+	if err, note := database.ReadNoteIdWithReplyInfo(postId); err == nil && note != nil {
+		return &Target{Note: note, ObjectURI: note.ObjectURI}, nil
+	}
+
+	err, activity := database.ReadActivityById(postId)
+	if err != nil || activity == nil || activity.ActivityType != "Create" || activity.ObjectURI == "" {
+		return nil, ErrNotFound
+	}
+	// A local post that was federated back is handled as the local note
+	if err, note := database.ReadNoteByURI(activity.ObjectURI); err == nil && note != nil {
+		return &Target{Note: note, ObjectURI: activity.ObjectURI}, nil
+	}
+	return &Target{ObjectURI: activity.ObjectURI}, nil
+}
+
+// ReadTargetByURI looks up a post by the URI the TUI keeps for it: "local:<note id>"
+// for local notes that were never federated, the ActivityPub object id otherwise
+func ReadTargetByURI(database db.Store, uri string) (*Target, error) {
+	postId, ok := postIdByURI(database, uri)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return ReadTarget(database, postId)
+}
+
+// ReadTargetByRef looks up a post by a reference as users type it: a local note id,
+// a remote activity id, an ActivityPub object id or the URL of a local post
+func ReadTargetByRef(database db.Store, conf *util.AppConfig, ref string) (*Target, error) {
+	postId, err := postRefId(database, conf, ref)
+	if err != nil {
+		return nil, err
+	}
+	target, err := ReadTarget(database, postId)
+	if err != nil {
+		return nil, fmt.Errorf("post not found: %s", ref)
+	}
+	return target, nil
+}
+
+// postRefId maps a post reference to a local note id or remote activity id
+func postRefId(database db.Store, conf *util.AppConfig, ref string) (uuid.UUID, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := uuid.Parse(ref); err == nil {
+		return id, nil
+	}
+	if parsed, err := url.Parse(ref); err == nil && parsed.Host != "" && strings.EqualFold(parsed.Host, conf.Conf.SslDomain) {
+		// Web UI (/u/<user>/<id>) and ActivityPub (/notes/<id>) URLs of local posts
+		parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+		if (len(parts) == 2 && parts[0] == "notes") || (len(parts) == 3 && parts[0] == "u") {
+			if id, err := uuid.Parse(parts[len(parts)-1]); err == nil {
+				return id, nil
+			}
+		}
+	}
+	if id, ok := postIdByURI(database, ref); ok {
+		return id, nil
+	}
+	return uuid.Nil, fmt.Errorf("post not found: %s", ref)
+}
+
+// postIdByURI maps an inReplyTo style URI to the id of a local note or stored remote activity
+func postIdByURI(database db.Store, uri string) (uuid.UUID, bool) {
+	if strings.HasPrefix(uri, "local:") {
+		noteId, err := uuid.Parse(strings.TrimPrefix(uri, "local:"))
+		if err != nil {
+			return uuid.Nil, false
+		}
+		err, note := database.ReadNoteId(noteId)
+		return noteId, err == nil && note != nil
+	}
+	if err, note := database.ReadNoteByURI(uri); err == nil && note != nil {
+		return note.Id, true
+	}
+	if err, activity := database.ReadActivityByObjectURI(uri); err == nil && activity != nil {
+		return activity.Id, true
+	}
+	return uuid.Nil, false
+}
+
+// ownNote reads a note of the account by id
+func ownNote(database db.Store, account *domain.Account, noteId uuid.UUID) (*domain.Note, error) {
+	err, note := database.ReadNoteIdWithReplyInfo(noteId)
+	if err != nil || note == nil {
+		return nil, fmt.Errorf("post not found: %s", noteId)
+	}
+	if note.CreatedBy != account.Username {
+		return nil, fmt.Errorf("you can only change your own posts")
+	}
+	return note, nil
+}
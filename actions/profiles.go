@@ -0,0 +1,179 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/deemkeen/stegodon/activitypub"
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+// Profile is a local or remote account with its relationship to the viewer
+type Profile struct {
+	Id          uuid.UUID
+	Username    string
+	Domain      string // Empty for local accounts
+	DisplayName string
+	Summary     string // Bio as plain text
+	URL         string
+	CreatedAt   time.Time // Zero for remote accounts
+
+	// Counts are only known for local accounts
+	Local     bool
+	Followers int
+	Following int
+	Posts     int
+
+	IsFollowing bool // The viewer follows the account
+	Requested   bool // The viewer's follow request is pending
+	FollowsYou  bool // The account follows the viewer
+}
+
+// Handle returns @user for local accounts and @user@domain for remote ones
+func (p *Profile) Handle() string {
+	if p.Domain == "" {
+		return "@" + p.Username
+	}
+	return "@" + p.Username + "@" + p.Domain
+}
+
+// localProfile converts a local account, with counts
+func localProfile(database db.Store, conf *util.AppConfig, acc *domain.Account) *Profile {
+	profile := &Profile{
+		Id:          acc.Id,
+		Username:    acc.Username,
+		DisplayName: acc.DisplayName,
+		Summary:     acc.Summary,
+		URL:         "https://" + conf.Conf.SslDomain + "/u/" + acc.Username,
+		CreatedAt:   acc.CreatedAt,
+		Local:       true,
+	}
+	if err, followers := database.ReadFollowersByAccountId(acc.Id); err == nil && followers != nil {
+		profile.Followers = countAccepted(*followers)
+	}
+	if err, following := database.ReadFollowingByAccountId(acc.Id); err == nil && following != nil {
+		profile.Following = countAccepted(*following)
+	}
+	if err, notes := database.ReadNotesByUsername(acc.Username); err == nil && notes != nil {
+		profile.Posts = len(*notes)
+	}
+	return profile
+}
+
+// remoteProfile converts a cached remote account
+func remoteProfile(acc *domain.RemoteAccount) *Profile {
+	return &Profile{
+		Id:          acc.Id,
+		Username:    acc.Username,
+		Domain:      acc.Domain,
+		DisplayName: acc.DisplayName,
+		Summary:     util.StripHTMLTags(acc.Summary),
+		URL:         acc.ActorURI,
+	}
+}
+
+// countAccepted counts the accepted follows
+func countAccepted(follows []domain.Follow) int {
+	count := 0
+	for _, follow := range follows {
+		if follow.Accepted {
+			count++
+		}
+	}
+	return count
+}
+
+// ReadProfile looks up a local or remote account and its relationship to the viewer.
+// An empty ref is the viewer. Remote accounts that are not cached yet are fetched.
+func ReadProfile(database db.Store, conf *util.AppConfig, viewer *domain.Account, ref string) (*Profile, error) {
+	if strings.TrimSpace(ref) == "" {
+		err, acc := database.ReadAccById(viewer.Id)
+		if err != nil || acc == nil {
+			return nil, fmt.Errorf("user not found: %s", viewer.Username)
+		}
+		return localProfile(database, conf, acc), nil
+	}
+
+	username, userDomain, err := ParseAccountRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	var profile *Profile
+	if IsLocalDomain(conf, userDomain) {
+		err, acc := database.ReadAccByUsername(username)
+		if err != nil || acc == nil {
+			return nil, fmt.Errorf("user not found: %s", username)
+		}
+		profile = localProfile(database, conf, acc)
+	} else if err, remote := database.ReadRemoteAccountByHandle(username, userDomain); err == nil && remote != nil {
+		profile = remoteProfile(remote)
+	} else {
+		if !conf.Conf.WithAp {
+			return nil, fmt.Errorf("federation is disabled on this server")
+		}
+		actorURI, err := activitypub.ResolveHandle(username, userDomain)
+		if err != nil {
+			return nil, fmt.Errorf("webfinger resolution failed: %w", err)
+		}
+		actor, err := activitypub.GetOrFetchActor(actorURI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch account %s: %w", actorURI, err)
+		}
+		profile = remoteProfile(actor)
+	}
+
+	if profile.Id != viewer.Id {
+		if err, follow := database.ReadFollowByAccountIds(viewer.Id, profile.Id); err == nil && follow != nil {
+			profile.IsFollowing = follow.Accepted
+			profile.Requested = !follow.Accepted
+		}
+		if err, follow := database.ReadFollowByAccountIds(profile.Id, viewer.Id); err == nil && follow != nil {
+			profile.FollowsYou = follow.Accepted
+		}
+	}
+	return profile, nil
+}
+
+// ReadFollowProfiles lists the accepted followers of a local account, or the accounts it follows
+func ReadFollowProfiles(database db.Store, conf *util.AppConfig, accountId uuid.UUID, followers bool) ([]Profile, error) {
+	var err error
+	var follows *[]domain.Follow
+	if followers {
+		err, follows = database.ReadFollowersByAccountId(accountId)
+	} else {
+		err, follows = database.ReadFollowingByAccountId(accountId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := []Profile{}
+	if follows == nil {
+		return profiles, nil
+	}
+	for _, follow := range *follows {
+		if !follow.Accepted {
+			continue
+		}
+		other := follow.TargetAccountId
+		if followers {
+			other = follow.AccountId
+		}
+		if err, acc := database.ReadAccById(other); err == nil && acc != nil {
+			profiles = append(profiles, Profile{
+				Id:          acc.Id,
+				Username:    acc.Username,
+				DisplayName: acc.DisplayName,
+				URL:         "https://" + conf.Conf.SslDomain + "/u/" + acc.Username,
+				Local:       true,
+			})
+		} else if err, remote := database.ReadRemoteAccountById(other); err == nil && remote != nil {
+			profiles = append(profiles, *remoteProfile(remote))
+		}
+	}
+	return profiles, nil
+}
@@ -0,0 +1,12 @@
+package actions
+
+import "testing"
+
+func TestProfileHandle(t *testing.T) {
+	local := &Profile{Username: "alice"}
+	remote := &Profile{Username: "bob", Domain: "remote.example"}
+
+	if local.Handle() != "@alice" || remote.Handle() != "@bob@remote.example" {
+		t.Errorf("Unexpected handles: %s, %s", local.Handle(), remote.Handle())
+	}
+}
@@ -0,0 +1,232 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxAncestors bounds how far up a reply chain a thread is followed
+	maxAncestors = 40
+	// maxReplies bounds how many replies of a thread are collected
+	maxReplies = 400
+)
+
+// Thread is a post with the posts it replies to and the replies to it
+type Thread struct {
+	Ancestors []domain.HomePost // Oldest first
+	Post      domain.HomePost
+	Replies   []domain.HomePost // Breadth first, direct replies first
+}
+
+// ReplyPost is a reply with the URI it refers to its parent by
+type ReplyPost struct {
+	Post         domain.HomePost
+	InReplyToURI string
+}
+
+// ActivityContent extracts the plain text content and the author of a stored remote activity.
+// Returns content, username, domain and profile URL.
+func ActivityContent(activity *domain.Activity, database db.Store) (content, username, userDomain, profileURL string) {
+	// Default to actor URI as fallback
+	username = activity.ActorURI
+	userDomain = ""
+	profileURL = activity.ActorURI
+
+	// Try to get better author info from cached remote account
+	err, remoteAcc := database.ReadRemoteAccountByActorURI(activity.ActorURI)
+	if err == nil && remoteAcc != nil {
+		username = remoteAcc.Username
+		userDomain = remoteAcc.Domain
+		profileURL = fmt.Sprintf("https://%s/@%s", remoteAcc.Domain, remoteAcc.Username)
+	} else {
+		// Parse username and domain from actor URI as fallback
+		// Format: https://domain.com/users/username or https://domain.com/@username
+		if strings.Contains(activity.ActorURI, "/users/") {
+			parts := strings.Split(activity.ActorURI, "/users/")
+			if len(parts) == 2 {
+				domainPart := strings.TrimPrefix(parts[0], "https://")
+				userDomain = domainPart
+				username = parts[1]
+				profileURL = fmt.Sprintf("https://%s/@%s", domainPart, parts[1])
+			}
+		} else if strings.Contains(activity.ActorURI, "/@") {
+			parts := strings.Split(activity.ActorURI, "/@")
+			if len(parts) == 2 {
+				domainPart := strings.TrimPrefix(parts[0], "https://")
+				userDomain = domainPart
+				username = parts[1]
+			}
+		}
+	}
+
+	// Parse content from raw JSON
+	if activity.RawJSON != "" {
+		var activityWrapper struct {
+			Type   string `json:"type"`
+			Object struct {
+				ID      string `json:"id"`
+				Content string `json:"content"`
+			} `json:"object"`
+		}
+
+		if err := json.Unmarshal([]byte(activity.RawJSON), &activityWrapper); err == nil {
+			content = util.StripHTMLTags(activityWrapper.Object.Content)
+		}
+	}
+
+	return content, username, userDomain, profileURL
+}
+
+// ActivityInReplyTo extracts object.inReplyTo from a raw Create activity
+func ActivityInReplyTo(rawJSON string) string {
+	var activity struct {
+		Object struct {
+			InReplyTo any `json:"inReplyTo"`
+		} `json:"object"`
+	}
+	if err := json.Unmarshal([]byte(rawJSON), &activity); err != nil {
+		return ""
+	}
+	inReplyTo, _ := activity.Object.InReplyTo.(string)
+	return inReplyTo
+}
+
+// CountReplies counts both local and remote replies to a note
+// When ActivityPub is enabled, it also counts remote activities that reply to this note
+func CountReplies(database db.Store, noteId uuid.UUID, sslDomain string, withAp bool) int {
+	// Count local replies first
+	localCount := 0
+	if count, err := database.CountRepliesByNoteId(noteId); err == nil {
+		localCount = count
+	}
+
+	// If ActivityPub is enabled, also count remote replies
+	if withAp && sslDomain != "" {
+		canonicalURI := fmt.Sprintf("https://%s/notes/%s", sslDomain, noteId.String())
+		if remoteCount, err := database.CountActivitiesByInReplyTo(canonicalURI); err == nil {
+			localCount += remoteCount
+		}
+	}
+
+	return localCount
+}
+
+// NotePost converts a local note into a timeline post
+func NotePost(database db.Store, conf *util.AppConfig, note *domain.Note) domain.HomePost {
+	return domain.HomePost{
+		ID:         note.Id,
+		Author:     note.CreatedBy,
+		Content:    note.Message,
+		Time:       note.CreatedAt,
+		ObjectURI:  note.ObjectURI,
+		IsLocal:    true,
+		NoteID:     note.Id,
+		ReplyCount: CountReplies(database, note.Id, conf.Conf.SslDomain, conf.Conf.WithAp),
+		LikeCount:  note.LikeCount,
+		BoostCount: note.BoostCount,
+	}
+}
+
+// ActivityPost converts a stored remote Create activity into a timeline post
+func ActivityPost(database db.Store, activity *domain.Activity) domain.HomePost {
+	content, username, userDomain, _ := ActivityContent(activity, database)
+	if content == "" && activity.ObjectURL != "" {
+		content = activity.ObjectURL
+	}
+	return domain.HomePost{
+		ID:         activity.Id,
+		Author:     "@" + username + "@" + userDomain,
+		Content:    content,
+		Time:       activity.CreatedAt,
+		ObjectURI:  activity.ObjectURI,
+		ObjectURL:  activity.ObjectURL,
+		ReplyCount: activity.ReplyCount,
+		LikeCount:  activity.LikeCount,
+		BoostCount: activity.BoostCount,
+		Emojis:     util.ExtractEmojiTagsFromJSON(activity.RawJSON),
+	}
+}
+
+// ReadPost returns the post for a local note id or a remote activity id and the URI it replies to
+func ReadPost(database db.Store, conf *util.AppConfig, postId uuid.UUID) (*domain.HomePost, string) {
+	if err, note := database.ReadNoteIdWithReplyInfo(postId); err == nil && note != nil {
+		post := NotePost(database, conf, note)
+		return &post, note.InReplyToURI
+	}
+	if err, activity := database.ReadActivityById(postId); err == nil && activity != nil && activity.ActivityType == "Create" {
+		post := ActivityPost(database, activity)
+		return &post, ActivityInReplyTo(activity.RawJSON)
+	}
+	return nil, ""
+}
+
+// ReadReplies returns the direct local and remote replies to the post with the given id and URI
+func ReadReplies(database db.Store, conf *util.AppConfig, postId uuid.UUID, uri string) []ReplyPost {
+	result := []ReplyPost{}
+
+	var notes *[]domain.Note
+	if err, note := database.ReadNoteId(postId); err == nil && note != nil {
+		_, notes = database.ReadRepliesByNoteId(postId)
+	} else {
+		_, notes = database.ReadRepliesByURI(uri)
+	}
+	if notes != nil {
+		for _, note := range *notes {
+			result = append(result, ReplyPost{Post: NotePost(database, conf, &note), InReplyToURI: note.InReplyToURI})
+		}
+	}
+
+	if uri != "" {
+		if err, activities := database.ReadActivitiesByInReplyTo(uri); err == nil && activities != nil {
+			for _, activity := range *activities {
+				result = append(result, ReplyPost{Post: ActivityPost(database, &activity), InReplyToURI: uri})
+			}
+		}
+	}
+	return result
+}
+
+// ReadThread returns a post with the chain of posts it replies to and all replies below it
+func ReadThread(database db.Store, conf *util.AppConfig, ref string) (*Thread, error) {
+	postId, err := postRefId(database, conf, ref)
+	if err != nil {
+		return nil, err
+	}
+	post, inReplyToURI := ReadPost(database, conf, postId)
+	if post == nil {
+		return nil, fmt.Errorf("post not found: %s", ref)
+	}
+	thread := &Thread{Post: *post, Ancestors: []domain.HomePost{}, Replies: []domain.HomePost{}}
+
+	// Walk up the reply chain
+	for inReplyToURI != "" && len(thread.Ancestors) < maxAncestors {
+		parentId, ok := postIdByURI(database, inReplyToURI)
+		if !ok {
+			break
+		}
+		var parent *domain.HomePost
+		if parent, inReplyToURI = ReadPost(database, conf, parentId); parent == nil {
+			break
+		}
+		thread.Ancestors = append([]domain.HomePost{*parent}, thread.Ancestors...)
+	}
+
+	// Walk down the replies, breadth first
+	queue := []domain.HomePost{*post}
+	for len(queue) > 0 && len(thread.Replies) < maxReplies {
+		current := queue[0]
+		queue = queue[1:]
+		for _, reply := range ReadReplies(database, conf, current.ID, current.ObjectURI) {
+			thread.Replies = append(thread.Replies, reply.Post)
+			queue = append(queue, reply.Post)
+		}
+	}
+	return thread, nil
+}
@@ -0,0 +1,131 @@
+package activitypub
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/deemkeen/stegodon/util"
+)
+
+const (
+	// actorRefreshBatchSize is the maximum number of actors refetched per round
+	actorRefreshBatchSize = 50
+	// actorGoneAfterFailures is the number of consecutive 404/410 responses after which an actor is gone
+	actorGoneAfterFailures = 3
+	// actorRetryAfter is the delay before an actor whose refetch failed is tried again
+	actorRetryAfter = 24 * time.Hour
+)
+
+// ActorRefreshResult summarizes one round of the actor refresh job
+type ActorRefreshResult struct {
+	RanAt     time.Time
+	Refreshed int // Refetched successfully
+	Failed    int // Refetch failed, will be retried
+	Gone      int // Marked gone; their follows were removed
+}
+
+var (
+	lastActorRefreshMu sync.Mutex
+	lastActorRefresh   *ActorRefreshResult
+)
+
+// LastActorRefresh returns the result of the most recent refresh round, or nil if none ran yet
+func LastActorRefresh() *ActorRefreshResult {
+	lastActorRefreshMu.Lock()
+	defer lastActorRefreshMu.Unlock()
+	return lastActorRefresh
+}
+
+// ActorRefreshTTL returns how long a cached remote actor is used before it is refetched
+func ActorRefreshTTL(conf *util.AppConfig) time.Duration {
+	return time.Duration(conf.Conf.ActorRefreshHours) * time.Hour
+}
+
+// StartActorRefreshWorker starts a background worker that refetches stale remote actors.
+// Returns a stop function that can be called to gracefully stop the worker.
+func StartActorRefreshWorker(conf *util.AppConfig) func() {
+	log.Println("Starting ActivityPub actor refresh worker...")
+
+	ticker := time.NewTicker(time.Hour)
+	stop := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				RefreshStaleActors(conf)
+			case <-stop:
+				ticker.Stop()
+				log.Println("ActivityPub actor refresh worker stopped")
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+	}
+}
+
+// RefreshStaleActors runs one refresh round.
+// This is the production wrapper that uses the default HTTP client and database.
+func RefreshStaleActors(conf *util.AppConfig) ActorRefreshResult {
+	return refreshStaleActorsWithDeps(ActorRefreshTTL(conf), defaultHTTPClient, NewDBWrapper())
+}
+
+// refreshStaleActorsWithDeps refetches actors not fetched within ttl through FetchRemoteActorWithDeps,
+// which updates display names, avatars and keys. Actors answering 404/410 on
+// actorGoneAfterFailures consecutive rounds are marked gone and their follows are removed.
+// This version accepts dependencies for testing.
+func refreshStaleActorsWithDeps(ttl time.Duration, client HTTPClient, database Database) ActorRefreshResult {
+	now := time.Now()
+	result := ActorRefreshResult{RanAt: now}
+
+	err, actors := database.ReadStaleRemoteAccounts(now.Add(-ttl), now.Add(-actorRetryAfter), actorRefreshBatchSize)
+	if err != nil {
+		log.Printf("ActorRefresh: Failed to read stale actors: %v", err)
+		return result
+	}
+
+	for _, actor := range *actors {
+		_, err := FetchRemoteActorWithDeps(actor.ActorURI, client, database)
+		if err == nil {
+			result.Refreshed++
+			continue
+		}
+
+		notFound := errors.Is(err, ErrActorGone)
+		failures, recordErr := database.RecordRemoteAccountFetchFailure(actor.Id, notFound)
+		if recordErr != nil {
+			log.Printf("ActorRefresh: Failed to record failure for %s: %v", actor.ActorURI, recordErr)
+		}
+		if !notFound || failures < actorGoneAfterFailures {
+			log.Printf("ActorRefresh: Failed to refetch %s: %v", actor.ActorURI, err)
+			result.Failed++
+			continue
+		}
+
+		if err := database.MarkRemoteAccountGone(actor.Id); err != nil {
+			log.Printf("ActorRefresh: Failed to mark %s gone: %v", actor.ActorURI, err)
+			result.Failed++
+			continue
+		}
+		if err := database.DeleteFollowsByRemoteAccountId(actor.Id); err != nil {
+			log.Printf("ActorRefresh: Failed to remove follows of %s: %v", actor.ActorURI, err)
+		}
+		log.Printf("ActorRefresh: %s is gone after %d failed fetches, removed its follows", actor.ActorURI, failures)
+		result.Gone++
+	}
+
+	if len(*actors) > 0 {
+		log.Printf("ActorRefresh: Refreshed %d, failed %d, gone %d", result.Refreshed, result.Failed, result.Gone)
+	}
+
+	lastActorRefreshMu.Lock()
+	lastActorRefresh = &result
+	lastActorRefreshMu.Unlock()
+
+	return result
+}
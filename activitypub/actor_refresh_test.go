@@ -0,0 +1,127 @@
+package activitypub
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+func TestRefreshStaleActorsWithDeps_Refreshes(t *testing.T) {
+	mockDB := NewMockDatabase()
+	stale := time.Now().Add(-100 * time.Hour)
+	actor := CreateTestRemoteAccount("https://remote.example.com", "alice", "")
+	actor.LastFetchedAt = stale
+	flaky := CreateTestRemoteAccount("https://remote.example.com", "flaky", "")
+	flaky.LastFetchedAt = stale
+	fresh := CreateTestRemoteAccount("https://remote.example.com", "fresh", "")
+	for _, acc := range []*domain.RemoteAccount{actor, flaky, fresh} {
+		mockDB.AddRemoteAccount(acc)
+	}
+
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetJSONResponse(actor.ActorURI, 200, map[string]any{
+		"id":                actor.ActorURI,
+		"type":              "Person",
+		"preferredUsername": "alice",
+		"name":              "New Name",
+		"inbox":             actor.InboxURI,
+		"publicKey":         map[string]any{"publicKeyPem": "-----BEGIN PUBLIC KEY-----"},
+	})
+	mockHTTP.SetJSONResponse("https://remote.example.com/users/flaky", 503, map[string]any{})
+
+	result := refreshStaleActorsWithDeps(72*time.Hour, mockHTTP, mockDB)
+	if result.Refreshed != 1 || result.Failed != 1 || result.Gone != 0 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	_, updated := mockDB.ReadRemoteAccountByURI(actor.ActorURI)
+	if updated.DisplayName != "New Name" || time.Since(updated.LastFetchedAt) > time.Minute {
+		t.Errorf("Expected actor to be refreshed, got %+v", updated)
+	}
+	if len(mockHTTP.Requests) != 2 {
+		t.Errorf("Expected fresh actor to be skipped, got %d requests", len(mockHTTP.Requests))
+	}
+	if last := LastActorRefresh(); last == nil || last.Refreshed != 1 {
+		t.Errorf("Expected last result to be recorded, got %+v", last)
+	}
+}
+
+func TestRefreshStaleActorsWithDeps_MarksGone(t *testing.T) {
+	mockDB := NewMockDatabase()
+	actor := CreateTestRemoteAccount("https://remote.example.com", "vanished", "")
+	actor.LastFetchedAt = time.Now().Add(-100 * time.Hour)
+	mockDB.AddRemoteAccount(actor)
+	mockDB.AddFollow(&domain.Follow{
+		Id:              uuid.New(),
+		AccountId:       uuid.New(),
+		TargetAccountId: actor.Id,
+		URI:             "https://local.example.com/follows/1",
+		Accepted:        true,
+	})
+	mockHTTP := NewMockHTTPClient() // Answers 404 for everything
+
+	for round := 1; round <= actorGoneAfterFailures; round++ {
+		// Skip the retry delay between rounds
+		delete(mockDB.RemoteFailedAt, actor.Id)
+
+		result := refreshStaleActorsWithDeps(72*time.Hour, mockHTTP, mockDB)
+		if round < actorGoneAfterFailures {
+			if result.Failed != 1 || result.Gone != 0 || mockDB.RemoteGone[actor.Id] {
+				t.Fatalf("Round %d: expected a failure, got %+v", round, result)
+			}
+			if len(mockDB.Follows) != 1 {
+				t.Fatalf("Round %d: expected follow to be kept", round)
+			}
+			continue
+		}
+		if result.Gone != 1 || !mockDB.RemoteGone[actor.Id] {
+			t.Fatalf("Expected actor to be gone after %d rounds, got %+v", round, result)
+		}
+	}
+
+	if len(mockDB.Follows) != 0 {
+		t.Errorf("Expected follows to gone actor to be removed, got %d", len(mockDB.Follows))
+	}
+
+	// Gone actors are not refetched
+	requests := len(mockHTTP.Requests)
+	delete(mockDB.RemoteFailedAt, actor.Id)
+	refreshStaleActorsWithDeps(72*time.Hour, mockHTTP, mockDB)
+	if len(mockHTTP.Requests) != requests {
+		t.Error("Expected gone actor to be skipped")
+	}
+}
+
+func TestRefreshStaleActorsWithDeps_WaitsAfterFailure(t *testing.T) {
+	mockDB := NewMockDatabase()
+	actor := CreateTestRemoteAccount("https://remote.example.com", "down", "")
+	actor.LastFetchedAt = time.Now().Add(-100 * time.Hour)
+	mockDB.AddRemoteAccount(actor)
+	mockHTTP := NewMockHTTPClient()
+
+	refreshStaleActorsWithDeps(72*time.Hour, mockHTTP, mockDB)
+	result := refreshStaleActorsWithDeps(72*time.Hour, mockHTTP, mockDB)
+	if result.Failed != 0 || len(mockHTTP.Requests) != 1 {
+		t.Errorf("Expected failed actor to wait before the next attempt, got %+v after %d requests", result, len(mockHTTP.Requests))
+	}
+}
+
+func TestFetchRemoteActorWithDeps_GoneError(t *testing.T) {
+	mockDB := NewMockDatabase()
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetJSONResponse("https://remote.example.com/users/deleted", 410, map[string]any{})
+
+	_, err := FetchRemoteActorWithDeps("https://remote.example.com/users/deleted", mockHTTP, mockDB)
+	if !errors.Is(err, ErrActorGone) {
+		t.Errorf("Expected ErrActorGone for 410, got %v", err)
+	}
+
+	mockHTTP.SetJSONResponse("https://remote.example.com/users/busy", 503, map[string]any{})
+	_, err = FetchRemoteActorWithDeps("https://remote.example.com/users/busy", mockHTTP, mockDB)
+	if err == nil || errors.Is(err, ErrActorGone) {
+		t.Errorf("Expected a non-gone error for 503, got %v", err)
+	}
+}
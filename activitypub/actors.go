@@ -2,6 +2,7 @@ package activitypub
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,6 +17,9 @@ import (
 // defaultHTTPClient is the default HTTP client for production use
 var defaultHTTPClient HTTPClient = NewDefaultHTTPClient(10 * time.Second)
 
+// ErrActorGone is returned when an actor's server answers 404 Not Found or 410 Gone
+var ErrActorGone = errors.New("actor gone")
+
 // ActorResponse represents the JSON structure of an ActivityPub actor
 type ActorResponse struct {
 	Context           any    `json:"@context"`
@@ -62,6 +66,9 @@ func FetchRemoteActorWithDeps(actorURI string, client HTTPClient, database Datab
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return nil, fmt.Errorf("actor fetch failed with status: %d: %w", resp.StatusCode, ErrActorGone)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("actor fetch failed with status: %d", resp.StatusCode)
 	}
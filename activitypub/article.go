@@ -0,0 +1,19 @@
+package activitypub
+
+import (
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+)
+
+// ApplyArticle turns a built Note object into an Article when the note has a title.
+// The rendered markdown replaces the short-note content, and a plain-text
+// summary is added for servers such as Mastodon that show Articles as a preview.
+func ApplyArticle(obj map[string]any, note *domain.Note) {
+	if !note.IsArticle() {
+		return
+	}
+	obj["type"] = "Article"
+	obj["name"] = note.Title
+	obj["content"] = util.ArticleToHTML(note.Message)
+	obj["summary"] = util.ArticleSummary(note.Message, util.ArticleSummaryLength)
+}
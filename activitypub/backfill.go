@@ -0,0 +1,138 @@
+package activitypub
+
+import (
+	"fmt"
+	"log"
+)
+
+const (
+	// backfillLimit is the number of recent outbox items read after a follow is accepted
+	backfillLimit = 20
+	// backfillMaxPages is the maximum number of outbox pages fetched per backfill
+	backfillMaxPages = 3
+)
+
+// backfillOutbox stores the recent posts of a newly followed account in the background,
+// so following a quiet account does not leave the home timeline empty
+func backfillOutbox(actorURI string, deps *InboxDeps) {
+	go func() {
+		stored, err := backfillOutboxWithDeps(actorURI, backfillLimit, deps.HTTPClient, deps.Database)
+		if err != nil {
+			log.Printf("Backfill: Failed to backfill %s: %v", actorURI, err)
+			return
+		}
+		log.Printf("Backfill: Stored %d posts from %s", stored, actorURI)
+	}()
+}
+
+// backfillOutboxWithDeps reads the last limit items of the actor's outbox and stores
+// their top-level posts as backfilled Create activities. Posts are stored with their
+// published time so they sort chronologically in the home timeline, and they bypass
+// the inbox so they don't create notifications.
+// Returns the number of newly stored posts.
+func backfillOutboxWithDeps(actorURI string, limit int, client HTTPClient, database Database) (int, error) {
+	actor, err := GetOrFetchActorWithDeps(actorURI, client, database)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch actor: %w", err)
+	}
+	if actor.OutboxURI == "" {
+		return 0, fmt.Errorf("actor has no outbox")
+	}
+
+	items, err := fetchOutboxItems(actor.OutboxURI, limit, client)
+	if err != nil {
+		return 0, err
+	}
+
+	stored := 0
+	for _, item := range items {
+		activity, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		// Boosts and other activities are skipped, only the actor's own posts are stored
+		if activityType, _ := activity["type"].(string); activityType != "Create" {
+			continue
+		}
+
+		var object map[string]any
+		switch obj := activity["object"].(type) {
+		case map[string]any:
+			object = obj
+		case string:
+			if object, err = fetchActivityPubObject(obj, client); err != nil {
+				log.Printf("Backfill: Failed to fetch %s: %v", obj, err)
+				continue
+			}
+		default:
+			continue
+		}
+
+		objectType, _ := object["type"].(string)
+		objectURI, _ := object["id"].(string)
+		if !postTypes[objectType] || objectURI == "" {
+			continue
+		}
+		// Replies are not shown in the home timeline
+		if inReplyTo, _ := object["inReplyTo"].(string); inReplyTo != "" {
+			continue
+		}
+		// Never store posts attributed to someone else
+		if attributedToURI(object) != actor.ActorURI {
+			continue
+		}
+		if err, existing := database.ReadActivityByObjectURI(objectURI); err == nil && existing != nil {
+			continue
+		}
+
+		if _, err := storeRemotePost(objectURI, object, true, database); err != nil {
+			log.Printf("Backfill: Failed to store %s: %v", objectURI, err)
+			continue
+		}
+		stored++
+	}
+
+	return stored, nil
+}
+
+// fetchOutboxItems returns up to limit items of an outbox, newest first.
+// Items may be listed on the collection itself or on its pages (first, then next).
+func fetchOutboxItems(outboxURI string, limit int, client HTTPClient) ([]any, error) {
+	collection, err := fetchActivityPubObject(outboxURI, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch outbox: %w", err)
+	}
+
+	items := collectionItems(collection)
+	next := collection["first"]
+	for pages := 0; len(items) < limit && pages < backfillMaxPages; pages++ {
+		var page map[string]any
+		switch p := next.(type) {
+		case map[string]any:
+			page = p
+		case string:
+			if page, err = fetchActivityPubObject(p, client); err != nil {
+				return nil, fmt.Errorf("failed to fetch outbox page: %w", err)
+			}
+		}
+		if page == nil {
+			break
+		}
+		items = append(items, collectionItems(page)...)
+		next = page["next"]
+	}
+
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// collectionItems returns the orderedItems (or items) of a collection or collection page
+func collectionItems(collection map[string]any) []any {
+	if items, ok := collection["orderedItems"].([]any); ok {
+		return items
+	}
+	items, _ := collection["items"].([]any)
+	return items
+}
@@ -0,0 +1,147 @@
+package activitypub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+func backfillCreate(actorURI, id string, object any) map[string]any {
+	return map[string]any{
+		"id":     id + "/activity",
+		"type":   "Create",
+		"actor":  actorURI,
+		"object": object,
+	}
+}
+
+func TestBackfillOutboxWithDeps(t *testing.T) {
+	mockDB := NewMockDatabase()
+	actor := CreateTestRemoteAccount("https://remote.example.com", "bob", "")
+	mockDB.AddRemoteAccount(actor)
+	mockHTTP := NewMockHTTPClient()
+
+	post := func(id, published string) map[string]any {
+		return map[string]any{
+			"id":           id,
+			"type":         "Note",
+			"attributedTo": actor.ActorURI,
+			"content":      "<p>" + id + "</p>",
+			"published":    published,
+		}
+	}
+	newer := "https://remote.example.com/users/bob/statuses/2"
+	older := "https://remote.example.com/users/bob/statuses/1"
+	byReference := "https://remote.example.com/users/bob/statuses/3"
+	reply := post("https://remote.example.com/users/bob/statuses/4", "2024-05-03T10:00:00Z")
+	reply["inReplyTo"] = "https://other.example/notes/1"
+	foreign := post("https://other.example/notes/2", "2024-05-03T10:00:00Z")
+	foreign["attributedTo"] = "https://other.example/users/eve"
+
+	mockHTTP.SetJSONResponse(actor.OutboxURI, 200, map[string]any{
+		"type":       "OrderedCollection",
+		"totalItems": 6,
+		"first":      actor.OutboxURI + "?page=true",
+	})
+	mockHTTP.SetJSONResponse(actor.OutboxURI+"?page=true", 200, map[string]any{
+		"type": "OrderedCollectionPage",
+		"orderedItems": []any{
+			backfillCreate(actor.ActorURI, newer, post(newer, "2024-05-02T10:00:00Z")),
+			map[string]any{"type": "Announce", "actor": actor.ActorURI, "object": "https://other.example/notes/3"},
+			backfillCreate(actor.ActorURI, reply["id"].(string), reply),
+			backfillCreate(actor.ActorURI, foreign["id"].(string), foreign),
+			backfillCreate(actor.ActorURI, byReference, byReference),
+			backfillCreate(actor.ActorURI, older, post(older, "2024-05-01T10:00:00Z")),
+		},
+	})
+	mockHTTP.SetJSONResponse(byReference, 200, post(byReference, "2024-05-01T12:00:00Z"))
+
+	stored, err := backfillOutboxWithDeps(actor.ActorURI, 20, mockHTTP, mockDB)
+	if err != nil {
+		t.Fatalf("backfillOutboxWithDeps failed: %v", err)
+	}
+	if stored != 3 {
+		t.Fatalf("Expected 3 stored posts, got %d", stored)
+	}
+
+	for _, uri := range []string{newer, older, byReference} {
+		_, activity := mockDB.ReadActivityByObjectURI(uri)
+		if activity == nil {
+			t.Fatalf("Expected %s to be stored", uri)
+		}
+		if !activity.Backfilled || activity.ActivityType != "Create" || activity.ActorURI != actor.ActorURI {
+			t.Errorf("Unexpected stored activity: %+v", activity)
+		}
+	}
+	_, activity := mockDB.ReadActivityByObjectURI(newer)
+	if !activity.CreatedAt.Equal(time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected published time, got %v", activity.CreatedAt)
+	}
+	for _, uri := range []string{reply["id"].(string), foreign["id"].(string)} {
+		if _, activity := mockDB.ReadActivityByObjectURI(uri); activity != nil {
+			t.Errorf("Expected %s to be skipped", uri)
+		}
+	}
+	if len(mockDB.Notifications) != 0 {
+		t.Errorf("Expected no notifications, got %d", len(mockDB.Notifications))
+	}
+}
+
+func TestBackfillOutboxWithDeps_SkipsStoredAndLimits(t *testing.T) {
+	mockDB := NewMockDatabase()
+	actor := CreateTestRemoteAccount("https://remote.example.com", "bob", "")
+	mockDB.AddRemoteAccount(actor)
+	mockHTTP := NewMockHTTPClient()
+
+	var items []any
+	for _, n := range []string{"1", "2", "3"} {
+		id := "https://remote.example.com/users/bob/statuses/" + n
+		items = append(items, backfillCreate(actor.ActorURI, id, map[string]any{
+			"id":           id,
+			"type":         "Note",
+			"attributedTo": actor.ActorURI,
+		}))
+	}
+	// Items embedded in the collection itself
+	mockHTTP.SetJSONResponse(actor.OutboxURI, 200, map[string]any{
+		"type":         "OrderedCollection",
+		"orderedItems": items,
+	})
+
+	existing := &domain.Activity{
+		Id:           uuid.New(),
+		ActivityURI:  "https://remote.example.com/users/bob/statuses/1/activity",
+		ActivityType: "Create",
+		ObjectURI:    "https://remote.example.com/users/bob/statuses/1",
+	}
+	mockDB.CreateActivity(existing)
+
+	stored, err := backfillOutboxWithDeps(actor.ActorURI, 2, mockHTTP, mockDB)
+	if err != nil {
+		t.Fatalf("backfillOutboxWithDeps failed: %v", err)
+	}
+	if stored != 1 {
+		t.Errorf("Expected only the second post to be stored, got %d", stored)
+	}
+	if _, activity := mockDB.ReadActivityByObjectURI("https://remote.example.com/users/bob/statuses/3"); activity != nil {
+		t.Error("Expected items beyond the limit to be skipped")
+	}
+}
+
+func TestBackfillOutboxWithDeps_Errors(t *testing.T) {
+	mockDB := NewMockDatabase()
+	actor := CreateTestRemoteAccount("https://remote.example.com", "bob", "")
+	mockDB.AddRemoteAccount(actor)
+	mockHTTP := NewMockHTTPClient()
+
+	if _, err := backfillOutboxWithDeps(actor.ActorURI, 20, mockHTTP, mockDB); err == nil {
+		t.Error("Expected error when the outbox cannot be fetched")
+	}
+
+	actor.OutboxURI = ""
+	if _, err := backfillOutboxWithDeps(actor.ActorURI, 20, mockHTTP, mockDB); err == nil {
+		t.Error("Expected error for an actor without outbox")
+	}
+}
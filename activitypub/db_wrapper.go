@@ -12,7 +12,7 @@ import (
 // This adapter allows the production code to use the existing db.GetDB() singleton
 // while also supporting dependency injection for tests.
 type DBWrapper struct {
-	db *db.DB
+	db db.Store
 }
 
 // NewDBWrapper creates a new database wrapper around the singleton database
@@ -48,6 +48,18 @@ func (w *DBWrapper) CreateRemoteAccount(acc *domain.RemoteAccount) error {
 	return w.db.CreateRemoteAccount(acc)
 }
 
+func (w *DBWrapper) ReadStaleRemoteAccounts(fetchedBefore, retryBefore time.Time, limit int) (error, *[]domain.RemoteAccount) {
+	return w.db.ReadStaleRemoteAccounts(fetchedBefore, retryBefore, limit)
+}
+
+func (w *DBWrapper) RecordRemoteAccountFetchFailure(id uuid.UUID, notFound bool) (int, error) {
+	return w.db.RecordRemoteAccountFetchFailure(id, notFound)
+}
+
+func (w *DBWrapper) MarkRemoteAccountGone(id uuid.UUID) error {
+	return w.db.MarkRemoteAccountGone(id)
+}
+
 func (w *DBWrapper) UpdateRemoteAccount(acc *domain.RemoteAccount) error {
 	return w.db.UpdateRemoteAccount(acc)
 }
@@ -114,6 +126,20 @@ func (w *DBWrapper) ReadNoteByURI(objectURI string) (error, *domain.Note) {
 	return w.db.ReadNoteByURI(objectURI)
 }
 
+func (w *DBWrapper) ReadNoteId(id uuid.UUID) (error, *domain.Note) {
+	return w.db.ReadNoteId(id)
+}
+
+// Hashtag operations
+
+func (w *DBWrapper) CreateOrUpdateHashtag(name string) (int64, error) {
+	return w.db.CreateOrUpdateHashtag(name)
+}
+
+func (w *DBWrapper) LinkNoteHashtags(noteId uuid.UUID, hashtagIds []int64) error {
+	return w.db.LinkNoteHashtags(noteId, hashtagIds)
+}
+
 // Mention operations
 
 func (w *DBWrapper) CreateNoteMention(mention *domain.NoteMention) error {
@@ -156,6 +182,28 @@ func (w *DBWrapper) DecrementLikeCountByNoteId(noteId uuid.UUID) error {
 	return w.db.DecrementLikeCountByNoteId(noteId)
 }
 
+// Reaction operations
+
+func (w *DBWrapper) CreateReaction(reaction *domain.Reaction) error {
+	return w.db.CreateReaction(reaction)
+}
+
+func (w *DBWrapper) ReadReactionByURI(uri string) (error, *domain.Reaction) {
+	return w.db.ReadReactionByURI(uri)
+}
+
+func (w *DBWrapper) ReadReactionByAccountAndNote(accountId, noteId uuid.UUID) (error, *domain.Reaction) {
+	return w.db.ReadReactionByAccountAndNote(accountId, noteId)
+}
+
+func (w *DBWrapper) DeleteReactionByURI(uri string) error {
+	return w.db.DeleteReactionByURI(uri)
+}
+
+func (w *DBWrapper) DeleteReactionById(id uuid.UUID) error {
+	return w.db.DeleteReactionById(id)
+}
+
 // Boost operations
 
 func (w *DBWrapper) CreateBoost(boost *domain.Boost) error {
@@ -218,6 +266,28 @@ func (w *DBWrapper) DeleteDelivery(id uuid.UUID) error {
 	return w.db.DeleteDelivery(id)
 }
 
+// Inbox queue operations
+
+func (w *DBWrapper) EnqueueInboxItem(item *domain.InboxQueueItem) error {
+	return w.db.EnqueueInboxItem(item)
+}
+
+func (w *DBWrapper) ReadPendingInboxItems(limit int) (error, *[]domain.InboxQueueItem) {
+	return w.db.ReadPendingInboxItems(limit)
+}
+
+func (w *DBWrapper) UpdateInboxItemAttempt(id uuid.UUID, attempts int, nextRetry time.Time, lastError string) error {
+	return w.db.UpdateInboxItemAttempt(id, attempts, nextRetry, lastError)
+}
+
+func (w *DBWrapper) HoldInboxItem(id uuid.UUID, attempts int, lastError string) error {
+	return w.db.HoldInboxItem(id, attempts, lastError)
+}
+
+func (w *DBWrapper) DeleteInboxItem(id uuid.UUID) error {
+	return w.db.DeleteInboxItem(id)
+}
+
 // Relay operations
 
 func (w *DBWrapper) CreateRelay(relay *domain.Relay) error {
@@ -250,5 +320,21 @@ func (w *DBWrapper) CreateNotification(notification *domain.Notification) error
 	return w.db.CreateNotification(notification)
 }
 
+// Custom emoji operations
+
+func (w *DBWrapper) ReadAllCustomEmojis() (error, *[]domain.CustomEmoji) {
+	return w.db.ReadAllCustomEmojis()
+}
+
+// Retention operations
+
+func (w *DBWrapper) PruneFederatedContent(cutoff, accountsBefore time.Time, localNotePrefix string, dryRun bool) (*domain.RetentionStats, error) {
+	return w.db.PruneFederatedContent(cutoff, accountsBefore, localNotePrefix, dryRun)
+}
+
+func (w *DBWrapper) IncrementalVacuum() error {
+	return w.db.IncrementalVacuum()
+}
+
 // Ensure DBWrapper implements Database interface
 var _ Database = (*DBWrapper)(nil)
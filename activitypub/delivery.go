@@ -9,9 +9,11 @@ import (
 	"log"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/metrics"
 	"github.com/deemkeen/stegodon/util"
 )
 
@@ -21,6 +23,19 @@ type DeliveryDeps struct {
 	HTTPClient HTTPClient
 }
 
+var (
+	lastDeliveryMu sync.Mutex
+	lastDelivery   time.Time
+)
+
+// LastSuccessfulDelivery returns when the delivery worker last delivered an activity,
+// or the zero time if nothing was delivered since the server started
+func LastSuccessfulDelivery() time.Time {
+	lastDeliveryMu.Lock()
+	defer lastDeliveryMu.Unlock()
+	return lastDelivery
+}
+
 // StartDeliveryWorker starts a background worker that processes the delivery queue.
 // Returns a stop function that can be called to gracefully stop the worker.
 func StartDeliveryWorker(conf *util.AppConfig) func() {
@@ -85,16 +100,23 @@ func processDeliveryQueueWithDeps(conf *util.AppConfig, deps *DeliveryDeps) {
 			if item.Attempts >= 10 {
 				// Give up after 10 attempts
 				log.Printf("DeliveryWorker: Giving up on delivery to %s after %d attempts", item.InboxURI, item.Attempts)
+				metrics.Delivery(item.InboxURI, metrics.DeliveryDropped)
 				database.DeleteDelivery(item.Id)
 			} else {
 				log.Printf("DeliveryWorker: Delivery to %s failed (attempt %d), retry in %dm: %v",
 					item.InboxURI, item.Attempts, backoffMinutes, err)
+				metrics.Delivery(item.InboxURI, metrics.DeliveryFailed)
 				database.UpdateDeliveryAttempt(item.Id, item.Attempts, item.NextRetryAt)
 			}
 		} else {
 			// Successful delivery - remove from queue
 			log.Printf("DeliveryWorker: Successfully delivered to %s", item.InboxURI)
+			metrics.Delivery(item.InboxURI, metrics.DeliverySucceeded)
 			database.DeleteDelivery(item.Id)
+
+			lastDeliveryMu.Lock()
+			lastDelivery = time.Now()
+			lastDeliveryMu.Unlock()
 		}
 	}
 }
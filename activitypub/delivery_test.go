@@ -363,12 +363,16 @@ func TestProcessDeliveryQueueWithDeps_SuccessfulDelivery(t *testing.T) {
 	mockDB.AddDeliveryQueueItem(item)
 
 	// Process queue
+	before := time.Now()
 	processDeliveryQueueWithDeps(conf, deps)
 
 	// Verify item was removed from queue after successful delivery
 	if len(mockDB.DeliveryQueue) != 0 {
 		t.Errorf("Expected delivery queue to be empty after successful delivery, got %d items", len(mockDB.DeliveryQueue))
 	}
+	if LastSuccessfulDelivery().Before(before) {
+		t.Errorf("Expected the last successful delivery to be recorded, got %v", LastSuccessfulDelivery())
+	}
 }
 
 // TestProcessDeliveryQueueWithDeps_FailedDeliveryRetry tests retry logic for failed deliveries
@@ -22,6 +22,9 @@ type Database interface {
 	CreateRemoteAccount(acc *domain.RemoteAccount) error
 	UpdateRemoteAccount(acc *domain.RemoteAccount) error
 	DeleteRemoteAccount(id uuid.UUID) error
+	ReadStaleRemoteAccounts(fetchedBefore, retryBefore time.Time, limit int) (error, *[]domain.RemoteAccount)
+	RecordRemoteAccountFetchFailure(id uuid.UUID, notFound bool) (int, error)
+	MarkRemoteAccountGone(id uuid.UUID) error
 
 	// Follow operations
 	CreateFollow(follow *domain.Follow) error
@@ -41,6 +44,11 @@ type Database interface {
 
 	// Note operations (for replies)
 	ReadNoteByURI(objectURI string) (error, *domain.Note)
+	ReadNoteId(id uuid.UUID) (error, *domain.Note)
+
+	// Hashtag operations
+	CreateOrUpdateHashtag(name string) (int64, error)
+	LinkNoteHashtags(noteId uuid.UUID, hashtagIds []int64) error
 
 	// Mention operations
 	CreateNoteMention(mention *domain.NoteMention) error
@@ -57,6 +65,13 @@ type Database interface {
 	IncrementLikeCountByNoteId(noteId uuid.UUID) error
 	DecrementLikeCountByNoteId(noteId uuid.UUID) error
 
+	// Reaction operations
+	CreateReaction(reaction *domain.Reaction) error
+	ReadReactionByURI(uri string) (error, *domain.Reaction)
+	ReadReactionByAccountAndNote(accountId, noteId uuid.UUID) (error, *domain.Reaction)
+	DeleteReactionByURI(uri string) error
+	DeleteReactionById(id uuid.UUID) error
+
 	// Boost operations
 	CreateBoost(boost *domain.Boost) error
 	HasBoost(accountId, noteId uuid.UUID) (bool, error)
@@ -77,6 +92,13 @@ type Database interface {
 	UpdateDeliveryAttempt(id uuid.UUID, attempts int, nextRetry time.Time) error
 	DeleteDelivery(id uuid.UUID) error
 
+	// Inbox queue operations
+	EnqueueInboxItem(item *domain.InboxQueueItem) error
+	ReadPendingInboxItems(limit int) (error, *[]domain.InboxQueueItem)
+	UpdateInboxItemAttempt(id uuid.UUID, attempts int, nextRetry time.Time, lastError string) error
+	HoldInboxItem(id uuid.UUID, attempts int, lastError string) error
+	DeleteInboxItem(id uuid.UUID) error
+
 	// Relay operations
 	CreateRelay(relay *domain.Relay) error
 	ReadActiveRelays() (error, *[]domain.Relay)
@@ -87,6 +109,13 @@ type Database interface {
 
 	// Notification operations
 	CreateNotification(notification *domain.Notification) error
+
+	// Custom emoji operations
+	ReadAllCustomEmojis() (error, *[]domain.CustomEmoji)
+
+	// Retention operations
+	PruneFederatedContent(cutoff, accountsBefore time.Time, localNotePrefix string, dryRun bool) (*domain.RetentionStats, error)
+	IncrementalVacuum() error
 }
 
 // HTTPClient defines the HTTP client operations required by the ActivityPub package.
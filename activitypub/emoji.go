@@ -0,0 +1,75 @@
+package activitypub
+
+import (
+	"log"
+	"strings"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+)
+
+// EmojiTags builds ActivityPub Emoji tags for the local custom emoji used in a message.
+// The emojis map is keyed by shortcode and holds the (relative) image URL.
+func EmojiTags(message string, baseURL string, emojis map[string]string) []map[string]any {
+	tags := make([]map[string]any, 0)
+	if len(emojis) == 0 {
+		return tags
+	}
+
+	for _, shortcode := range util.ParseEmojiShortcodes(message) {
+		imageURL, ok := emojis[shortcode]
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(imageURL, "/") {
+			imageURL = baseURL + imageURL
+		}
+		tags = append(tags, map[string]any{
+			"id":   imageURL,
+			"type": "Emoji",
+			"name": ":" + shortcode + ":",
+			"icon": map[string]any{
+				"type":      "Image",
+				"mediaType": "image/png",
+				"url":       imageURL,
+			},
+		})
+	}
+
+	return tags
+}
+
+// NoteContext returns the JSON-LD @context for a note or activity.
+// Hashtag and Emoji definitions are only added when the note uses them.
+func NoteContext(hasHashtags, hasEmojis bool) any {
+	if !hasHashtags && !hasEmojis {
+		return "https://www.w3.org/ns/activitystreams"
+	}
+
+	extensions := map[string]any{}
+	if hasHashtags {
+		extensions["Hashtag"] = "as:Hashtag"
+	}
+	if hasEmojis {
+		extensions["toot"] = "http://joinmastodon.org/ns#"
+		extensions["Emoji"] = "toot:Emoji"
+	}
+
+	return []any{
+		"https://www.w3.org/ns/activitystreams",
+		extensions,
+	}
+}
+
+// readLocalEmojiMap loads the local custom emoji as a shortcode -> image URL map
+func readLocalEmojiMap(database Database) map[string]string {
+	err, emojis := database.ReadAllCustomEmojis()
+	if err != nil {
+		log.Printf("Outbox: Failed to read custom emoji: %v", err)
+		return nil
+	}
+	if emojis == nil {
+		return nil
+	}
+	return domain.EmojiMap(*emojis)
+}
@@ -0,0 +1,52 @@
+package activitypub
+
+import "testing"
+
+func TestEmojiTags(t *testing.T) {
+	emojis := map[string]string{
+		"blobcat": "/emojis/blobcat.png",
+		"remote":  "https://cdn.example/remote.png",
+	}
+
+	tags := EmojiTags("hi :blobcat: :remote: :unknown:", "https://example.com", emojis)
+	if len(tags) != 2 {
+		t.Fatalf("Expected 2 emoji tags, got %d", len(tags))
+	}
+
+	if tags[0]["type"] != "Emoji" || tags[0]["name"] != ":blobcat:" {
+		t.Errorf("Unexpected first tag: %v", tags[0])
+	}
+	icon := tags[0]["icon"].(map[string]any)
+	if icon["url"] != "https://example.com/emojis/blobcat.png" {
+		t.Errorf("Expected relative URL to be made absolute, got %v", icon["url"])
+	}
+
+	icon = tags[1]["icon"].(map[string]any)
+	if icon["url"] != "https://cdn.example/remote.png" {
+		t.Errorf("Expected absolute URL to be kept, got %v", icon["url"])
+	}
+}
+
+func TestEmojiTags_NoEmojis(t *testing.T) {
+	if tags := EmojiTags("hi :blobcat:", "https://example.com", nil); len(tags) != 0 {
+		t.Errorf("Expected no tags, got %v", tags)
+	}
+}
+
+func TestNoteContext(t *testing.T) {
+	if ctx := NoteContext(false, false); ctx != "https://www.w3.org/ns/activitystreams" {
+		t.Errorf("Expected plain activitystreams context, got %v", ctx)
+	}
+
+	ctx, ok := NoteContext(true, true).([]any)
+	if !ok || len(ctx) != 2 {
+		t.Fatalf("Expected extended context, got %v", ctx)
+	}
+	extensions := ctx[1].(map[string]any)
+	if extensions["Hashtag"] != "as:Hashtag" {
+		t.Errorf("Expected Hashtag extension, got %v", extensions)
+	}
+	if extensions["Emoji"] != "toot:Emoji" {
+		t.Errorf("Expected Emoji extension, got %v", extensions)
+	}
+}
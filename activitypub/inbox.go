@@ -10,7 +10,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/deemkeen/stegodon/db"
 	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
+	"github.com/deemkeen/stegodon/metrics"
 	"github.com/deemkeen/stegodon/util"
 	"github.com/google/uuid"
 )
@@ -71,6 +74,7 @@ func HandleInboxWithDeps(w http.ResponseWriter, r *http.Request, username string
 	signature := r.Header.Get("Signature")
 	if signature == "" {
 		log.Printf("Inbox: Missing HTTP signature")
+		metrics.SignatureFailure(metrics.SignatureMissing)
 		http.Error(w, "Missing signature", http.StatusUnauthorized)
 		return
 	}
@@ -80,6 +84,7 @@ func HandleInboxWithDeps(w http.ResponseWriter, r *http.Request, username string
 	signerKeyId := extractKeyIdFromSignature(signature)
 	if signerKeyId == "" {
 		log.Printf("Inbox: Could not extract keyId from signature")
+		metrics.SignatureFailure(metrics.SignatureMalformed)
 		http.Error(w, "Invalid signature format", http.StatusUnauthorized)
 		return
 	}
@@ -106,6 +111,7 @@ func HandleInboxWithDeps(w http.ResponseWriter, r *http.Request, username string
 	var activity Activity
 	if err := json.Unmarshal(body, &activity); err != nil {
 		log.Printf("Inbox: Failed to parse activity: %v", err)
+		metrics.InboxActivity("", metrics.InboxRejected)
 		http.Error(w, "Invalid activity", http.StatusBadRequest)
 		return
 	}
@@ -116,6 +122,7 @@ func HandleInboxWithDeps(w http.ResponseWriter, r *http.Request, username string
 	signerActor, err := GetOrFetchActorWithDeps(signerActorURI, deps.HTTPClient, deps.Database)
 	if err != nil {
 		log.Printf("Inbox: Failed to fetch signer actor %s: %v", signerActorURI, err)
+		metrics.SignatureFailure(metrics.SignatureUnknownKey)
 		http.Error(w, "Failed to verify signer", http.StatusBadRequest)
 		return
 	}
@@ -127,14 +134,76 @@ func HandleInboxWithDeps(w http.ResponseWriter, r *http.Request, username string
 	_, err = VerifyRequest(r, signerActor.PublicKeyPem)
 	if err != nil {
 		log.Printf("Inbox: Signature verification failed: %v", err)
+		metrics.SignatureFailure(metrics.SignatureInvalid)
 		http.Error(w, "Invalid signature", http.StatusUnauthorized)
 		return
 	}
 
-	// If signer is different from activity actor, also fetch/cache the activity actor
+	// Set FromRelay if the signer is different from the activity actor (relay forwarding)
+	isFromRelay := signerActorURI != activity.Actor
+
+	// If signer differs from actor, check if it's from a paused relay subscription
+	// Only block if signer matches a relay we have paused - otherwise allow through
+	// (could be a regular boost from a followed user, not relay content)
+	if isFromRelay {
+		relay := findRelayByActorDomain(signerActorURI, deps.Database)
+		if relay != nil {
+			// Signer matches a relay subscription
+			if relay.Paused {
+				log.Printf("Inbox: [RELAY] Blocking content from paused relay %s (signer: %s)", relay.ActorURI, signerActorURI)
+				metrics.InboxActivity(activity.Type, metrics.InboxBlocked)
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			log.Printf("Inbox: [RELAY] Accepted content via active relay %s", relay.ActorURI)
+		}
+		// No relay match - not relay content, allow through (likely a regular boost)
+	}
+
+	// Queue the verified activity; the inbox workers process it (with retries) after we respond
+	now := time.Now()
+	item := &domain.InboxQueueItem{
+		Id:           uuid.New(),
+		Username:     username,
+		ActivityJSON: string(body),
+		ActivityType: activity.Type,
+		ActorURI:     activity.Actor,
+		SignerURI:    signerActorURI,
+		Status:       domain.InboxQueuePending,
+		NextRetryAt:  now,
+		CreatedAt:    now,
+	}
+	if err := deps.Database.EnqueueInboxItem(item); err != nil {
+		log.Printf("Inbox: Failed to queue activity: %v", err)
+		metrics.InboxActivity(activity.Type, metrics.InboxRejected)
+		http.Error(w, "Failed to queue activity", http.StatusInternalServerError)
+		return
+	}
+	metrics.InboxActivity(activity.Type, metrics.InboxQueued)
+	wakeInboxWorker()
+
+	// Return 202 Accepted
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// processInboxItemWithDeps runs the activity handlers for a queued inbox item.
+// A returned error means the item should be retried.
+func processInboxItemWithDeps(item *domain.InboxQueueItem, conf *util.AppConfig, deps *InboxDeps) error {
+	body := []byte(item.ActivityJSON)
+	username := item.Username
+
+	var activity Activity
+	if err := json.Unmarshal(body, &activity); err != nil {
+		// Was valid when queued, so this can't be fixed by retrying
+		log.Printf("Inbox: Dropping unparsable queued activity %s: %v", item.Id, err)
+		return nil
+	}
+
+	// Resolve the activity actor (cached in remote_accounts, fetched when stale)
 	var remoteActor *domain.RemoteAccount
-	if signerActorURI != activity.Actor {
-		log.Printf("Inbox: Activity signed by %s on behalf of %s", signerActorURI, activity.Actor)
+	var err error
+	if item.SignerURI != activity.Actor {
+		log.Printf("Inbox: Activity signed by %s on behalf of %s", item.SignerURI, activity.Actor)
 		remoteActor, err = GetOrFetchActorWithDeps(activity.Actor, deps.HTTPClient, deps.Database)
 		if err != nil {
 			log.Printf("Inbox: Failed to fetch activity actor %s: %v", activity.Actor, err)
@@ -142,7 +211,10 @@ func HandleInboxWithDeps(w http.ResponseWriter, r *http.Request, username string
 			// The activity will still be processed
 		}
 	} else {
-		remoteActor = signerActor
+		remoteActor, err = GetOrFetchActorWithDeps(activity.Actor, deps.HTTPClient, deps.Database)
+		if err != nil {
+			return fmt.Errorf("failed to fetch actor %s: %w", activity.Actor, err)
+		}
 	}
 
 	// Store activity in database (except for Announce which may need special handling for relays)
@@ -173,26 +245,7 @@ func HandleInboxWithDeps(w http.ResponseWriter, r *http.Request, username string
 		}
 	}
 
-	// For Announce activities, defer storage to the handler (relay vs regular boost have different storage needs)
-	// Set FromRelay if the signer is different from the activity actor (relay forwarding)
-	isFromRelay := signerActorURI != activity.Actor
-
-	// If signer differs from actor, check if it's from a paused relay subscription
-	// Only block if signer matches a relay we have paused - otherwise allow through
-	// (could be a regular boost from a followed user, not relay content)
-	if isFromRelay {
-		relay := findRelayByActorDomain(signerActorURI, database)
-		if relay != nil {
-			// Signer matches a relay subscription
-			if relay.Paused {
-				log.Printf("Inbox: [RELAY] Blocking content from paused relay %s (signer: %s)", relay.ActorURI, signerActorURI)
-				w.WriteHeader(http.StatusAccepted)
-				return
-			}
-			log.Printf("Inbox: [RELAY] Accepted content via active relay %s", relay.ActorURI)
-		}
-		// No relay match - not relay content, allow through (likely a regular boost)
-	}
+	isFromRelay := item.SignerURI != activity.Actor
 
 	// Store activity record for non-Create/non-Announce types immediately.
 	// Create activities are stored in handleCreateActivityWithDeps AFTER acceptance check.
@@ -215,20 +268,23 @@ func HandleInboxWithDeps(w http.ResponseWriter, r *http.Request, username string
 		}
 
 		if err := database.CreateActivity(activityRecord); err != nil {
-			// Check if this is a duplicate (already processed)
-			if strings.Contains(err.Error(), "UNIQUE constraint failed") {
-				// For Accept activities, still process them - relay status needs updating on re-subscribe
+			// Check if this is a duplicate (already processed, or a retry of a failed attempt)
+			if db.IsUniqueViolation(err) {
+				_, existing := database.ReadActivityByURI(activity.ID)
 				if activity.Type == "Accept" {
+					// For Accept activities, still process them - relay status needs updating on re-subscribe
 					log.Printf("Inbox: Activity %s is duplicate, but processing Accept anyway for relay status", activity.ID)
+				} else if existing != nil && !existing.Processed {
+					log.Printf("Inbox: Retrying unprocessed activity %s", activity.ID)
+					activityRecord = existing
 				} else {
-					log.Printf("Inbox: Activity %s already processed, returning success", activity.ID)
-					w.WriteHeader(http.StatusAccepted)
-					return
+					log.Printf("Inbox: Activity %s already processed, skipping", activity.ID)
+					return nil
 				}
 			} else {
 				log.Printf("Inbox: Failed to store activity: %v", err)
 			}
-			// Don't fail the request, we'll process it anyway
+			// Don't fail the item, we'll process it anyway
 		}
 	}
 
@@ -236,51 +292,41 @@ func HandleInboxWithDeps(w http.ResponseWriter, r *http.Request, username string
 	switch activity.Type {
 	case "Follow":
 		if err := handleFollowActivityWithDeps(body, username, remoteActor, conf, deps); err != nil {
-			log.Printf("Inbox: Failed to handle Follow: %v", err)
-			http.Error(w, "Failed to process Follow", http.StatusInternalServerError)
-			return
+			return fmt.Errorf("failed to process Follow: %w", err)
 		}
 	case "Undo":
 		if err := handleUndoActivityWithDeps(body, username, remoteActor, deps); err != nil {
-			log.Printf("Inbox: Failed to handle Undo: %v", err)
-			http.Error(w, "Failed to process Undo", http.StatusInternalServerError)
-			return
+			return fmt.Errorf("failed to process Undo: %w", err)
 		}
 	case "Create":
 		if err := handleCreateActivityWithDeps(body, username, isFromRelay, deps); err != nil {
-			log.Printf("Inbox: Failed to handle Create: %v", err)
-			http.Error(w, "Failed to process Create", http.StatusInternalServerError)
-			return
+			return fmt.Errorf("failed to process Create: %w", err)
 		}
 	case "Like":
 		if err := handleLikeActivityWithDeps(body, username, deps); err != nil {
-			log.Printf("Inbox: Failed to handle Like: %v", err)
-			http.Error(w, "Failed to process Like", http.StatusInternalServerError)
-			return
+			return fmt.Errorf("failed to process Like: %w", err)
+		}
+	case "EmojiReact":
+		if err := handleEmojiReactActivityWithDeps(body, username, deps); err != nil {
+			return fmt.Errorf("failed to process EmojiReact: %w", err)
 		}
 	case "Announce":
 		if err := handleAnnounceActivityWithDeps(body, username, deps); err != nil {
-			log.Printf("Inbox: Failed to handle Announce: %v", err)
-			http.Error(w, "Failed to process Announce", http.StatusInternalServerError)
-			return
+			return fmt.Errorf("failed to process Announce: %w", err)
 		}
 	case "Accept":
 		// Accept activities are confirmations of Follow requests
 		if err := handleAcceptActivityWithDeps(body, username, deps); err != nil {
 			log.Printf("Inbox: Failed to handle Accept: %v", err)
-			// Don't fail the request
+			// Don't retry
 		}
 	case "Update":
 		if err := handleUpdateActivityWithDeps(body, username, deps); err != nil {
-			log.Printf("Inbox: Failed to handle Update: %v", err)
-			http.Error(w, "Failed to process Update", http.StatusInternalServerError)
-			return
+			return fmt.Errorf("failed to process Update: %w", err)
 		}
 	case "Delete":
 		if err := handleDeleteActivityWithDeps(body, username, deps); err != nil {
-			log.Printf("Inbox: Failed to handle Delete: %v", err)
-			http.Error(w, "Failed to process Delete", http.StatusInternalServerError)
-			return
+			return fmt.Errorf("failed to process Delete: %w", err)
 		}
 	default:
 		log.Printf("Inbox: Unsupported activity type: %s", activity.Type)
@@ -295,8 +341,7 @@ func HandleInboxWithDeps(w http.ResponseWriter, r *http.Request, username string
 		}
 	}
 
-	// Return 202 Accepted
-	w.WriteHeader(http.StatusAccepted)
+	return nil
 }
 
 // handleFollowActivity processes a Follow activity
@@ -435,6 +480,25 @@ func handleUndoActivityWithDeps(body []byte, username string, remoteActor *domai
 			return fmt.Errorf("failed to delete follow: %w", err)
 		}
 		log.Printf("Inbox: Removed follow from %s@%s", remoteActor.Username, remoteActor.Domain)
+	} else if obj.Type == "EmojiReact" || (obj.Type == "Like" && isStoredReaction(obj.ID, database)) {
+		// Handle Undo EmojiReact (also Misskey-style Like reactions)
+		err, reaction := database.ReadReactionByURI(obj.ID)
+		if err != nil || reaction == nil {
+			log.Printf("Inbox: Reaction not found for Undo %s object %s", obj.Type, obj.ID)
+			return nil
+		}
+
+		// Verify the actor matches (they can only undo their own reactions)
+		if remoteActor.ActorURI != undo.Actor || reaction.AccountId != remoteActor.Id {
+			return fmt.Errorf("unauthorized: actor %s cannot undo reaction", undo.Actor)
+		}
+
+		if err := database.DeleteReactionByURI(obj.ID); err != nil {
+			log.Printf("Inbox: Failed to delete reaction: %v", err)
+			return nil
+		}
+
+		log.Printf("Inbox: Removed reaction %s from %s@%s", reaction.Emoji, remoteActor.Username, remoteActor.Domain)
 	} else if obj.Type == "Like" {
 		// Handle Undo Like
 		// Find the note being unliked
@@ -635,7 +699,7 @@ func handleCreateActivityWithDeps(body []byte, username string, isFromRelay bool
 	}
 
 	if err := database.CreateActivity(activityRecord); err != nil {
-		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		if db.IsUniqueViolation(err) {
 			log.Printf("Inbox: Create activity %s already processed", create.ID)
 			return nil
 		}
@@ -669,10 +733,7 @@ func handleCreateActivityWithDeps(body []byte, username string, isFromRelay bool
 				err, parentAuthor := database.ReadAccByUsername(parentNote.CreatedBy)
 				if err == nil && parentAuthor != nil {
 					// Extract plain text preview from HTML content
-					preview := util.StripHTMLTags(create.Object.Content)
-					if len(preview) > 100 {
-						preview = preview[:100] + "..."
-					}
+					preview := util.TruncatePreview(util.StripHTMLTags(create.Object.Content), 100)
 					notification := &domain.Notification{
 						Id:               uuid.New(),
 						AccountId:        parentAuthor.Id,
@@ -710,10 +771,7 @@ func handleCreateActivityWithDeps(body []byte, username string, isFromRelay bool
 					if confErr == nil && conf != nil && parts[1] == conf.Conf.SslDomain {
 						err, mentionedUser := database.ReadAccByUsername(parts[0])
 						if err == nil && mentionedUser != nil {
-							preview := util.StripHTMLTags(create.Object.Content)
-							if len(preview) > 100 {
-								preview = preview[:100] + "..."
-							}
+							preview := util.TruncatePreview(util.StripHTMLTags(create.Object.Content), 100)
 							notification := &domain.Notification{
 								Id:               uuid.New(),
 								AccountId:        mentionedUser.Id,
@@ -735,6 +793,9 @@ func handleCreateActivityWithDeps(body []byte, username string, isFromRelay bool
 			case "Hashtag":
 				// Hashtags are already included in the stored activity raw JSON
 				log.Printf("Inbox: Post contains hashtag %s", tag.Name)
+			case "Emoji":
+				// Custom emoji are rendered from the Emoji tags in the stored activity raw JSON
+				log.Printf("Inbox: Post contains custom emoji %s", tag.Name)
 			}
 		}
 	}
@@ -757,16 +818,23 @@ func handleLikeActivityWithDeps(body []byte, username string, deps *InboxDeps) e
 	log.Printf("Inbox: Processing Like activity for %s", username)
 
 	var likeActivity struct {
-		ID     string `json:"id"`
-		Type   string `json:"type"`
-		Actor  string `json:"actor"`
-		Object string `json:"object"` // URI of the liked object (note)
+		ID              string `json:"id"`
+		Type            string `json:"type"`
+		Actor           string `json:"actor"`
+		Object          string `json:"object"` // URI of the liked object (note)
+		Content         string `json:"content"`
+		MisskeyReaction string `json:"_misskey_reaction"`
 	}
 
 	if err := json.Unmarshal(body, &likeActivity); err != nil {
 		return fmt.Errorf("failed to parse Like activity: %w", err)
 	}
 
+	// Misskey and friends send emoji reactions as Like activities with content
+	if likeActivity.Content != "" || likeActivity.MisskeyReaction != "" {
+		return handleEmojiReactActivityWithDeps(body, username, deps)
+	}
+
 	if likeActivity.ID == "" {
 		return fmt.Errorf("Like activity missing id")
 	}
@@ -824,10 +892,7 @@ func handleLikeActivityWithDeps(body []byte, username string, deps *InboxDeps) e
 	// Create notification for the note author
 	err, noteAuthor := database.ReadAccByUsername(note.CreatedBy)
 	if err == nil && noteAuthor != nil {
-		preview := note.Message
-		if len(preview) > 100 {
-			preview = preview[:100] + "..."
-		}
+		preview := util.TruncatePreview(note.Message, 100)
 		notification := &domain.Notification{
 			Id:               uuid.New(),
 			AccountId:        noteAuthor.Id,
@@ -850,6 +915,124 @@ func handleLikeActivityWithDeps(body []byte, username string, deps *InboxDeps) e
 	return nil
 }
 
+// isStoredReaction reports whether an activity URI belongs to a stored emoji reaction
+func isStoredReaction(uri string, database Database) bool {
+	if uri == "" {
+		return false
+	}
+	err, reaction := database.ReadReactionByURI(uri)
+	return err == nil && reaction != nil
+}
+
+// handleEmojiReactActivityWithDeps processes an EmojiReact activity, or a Like carrying
+// an emoji in content/_misskey_reaction. Each remote account keeps at most one reaction
+// per note; a new reaction replaces the previous one.
+// This version accepts dependencies for testing.
+func handleEmojiReactActivityWithDeps(body []byte, username string, deps *InboxDeps) error {
+	log.Printf("Inbox: Processing emoji reaction for %s", username)
+
+	var reactActivity struct {
+		ID              string `json:"id"`
+		Type            string `json:"type"`
+		Actor           string `json:"actor"`
+		Object          string `json:"object"` // URI of the reacted object (note)
+		Content         string `json:"content"`
+		MisskeyReaction string `json:"_misskey_reaction"`
+	}
+
+	if err := json.Unmarshal(body, &reactActivity); err != nil {
+		return fmt.Errorf("failed to parse reaction activity: %w", err)
+	}
+
+	if reactActivity.ID == "" {
+		return fmt.Errorf("%s activity missing id", reactActivity.Type)
+	}
+	if reactActivity.Actor == "" {
+		return fmt.Errorf("%s activity missing actor", reactActivity.Type)
+	}
+	if reactActivity.Object == "" {
+		return fmt.Errorf("%s activity missing object", reactActivity.Type)
+	}
+
+	emoji := strings.TrimSpace(reactActivity.Content)
+	if emoji == "" {
+		emoji = strings.TrimSpace(reactActivity.MisskeyReaction)
+	}
+	if emoji == "" {
+		return fmt.Errorf("%s activity missing emoji content", reactActivity.Type)
+	}
+
+	database := deps.Database
+
+	// Find the note being reacted to by its object_uri
+	err, note := database.ReadNoteByURI(reactActivity.Object)
+	if err != nil || note == nil {
+		log.Printf("Inbox: Note not found for reaction object %s: %v", reactActivity.Object, err)
+		return nil // Not an error - the note might not exist locally
+	}
+
+	remoteAcc, fetchErr := GetOrFetchActorWithDeps(reactActivity.Actor, deps.HTTPClient, database)
+	if fetchErr != nil {
+		log.Printf("Inbox: Could not fetch actor %s for reaction: %v", reactActivity.Actor, fetchErr)
+		return nil // Not a fatal error
+	}
+
+	// Replace any previous reaction from this account on this note
+	err, existing := database.ReadReactionByAccountAndNote(remoteAcc.Id, note.Id)
+	if err != nil {
+		log.Printf("Inbox: Error checking for existing reaction: %v", err)
+	}
+	if existing != nil {
+		if existing.URI == reactActivity.ID || existing.Emoji == emoji {
+			log.Printf("Inbox: Reaction from %s on note %s already exists, skipping", reactActivity.Actor, note.Id)
+			return nil
+		}
+		if err := database.DeleteReactionById(existing.Id); err != nil {
+			log.Printf("Inbox: Failed to replace previous reaction: %v", err)
+		}
+	}
+
+	reaction := &domain.Reaction{
+		Id:        uuid.New(),
+		AccountId: remoteAcc.Id,
+		NoteId:    note.Id,
+		ObjectURI: reactActivity.Object,
+		Emoji:     emoji,
+		URI:       reactActivity.ID,
+		CreatedAt: time.Now(),
+	}
+
+	if err := database.CreateReaction(reaction); err != nil {
+		return fmt.Errorf("failed to store reaction: %w", err)
+	}
+
+	// Create notification for the note author
+	err, noteAuthor := database.ReadAccByUsername(note.CreatedBy)
+	if err == nil && noteAuthor != nil {
+		preview := util.TruncatePreview(note.Message, 100)
+		notification := &domain.Notification{
+			Id:               uuid.New(),
+			AccountId:        noteAuthor.Id,
+			NotificationType: domain.NotificationReaction,
+			ActorId:          remoteAcc.Id,
+			ActorUsername:    remoteAcc.Username,
+			ActorDomain:      remoteAcc.Domain,
+			NoteId:           note.Id,
+			NoteURI:          note.ObjectURI,
+			NotePreview:      preview,
+			Emoji:            emoji,
+			Read:             false,
+			CreatedAt:        time.Now(),
+		}
+		if err := database.CreateNotification(notification); err != nil {
+			log.Printf("Inbox: Failed to create reaction notification: %v", err)
+		}
+	}
+
+	log.Printf("Inbox: Stored reaction %s from %s on note %s", emoji, reactActivity.Actor, note.Id)
+	return nil
+}
+
 // handleAnnounceActivity processes an Announce (boost/reblog) activity
 func handleAnnounceActivity(body []byte, username string) error {
 	deps := &InboxDeps{
@@ -1000,10 +1183,7 @@ func handleAnnounceActivityWithDeps(body []byte, username string, deps *InboxDep
 	// Create notification for the note author
 	err, noteAuthor := database.ReadAccByUsername(note.CreatedBy)
 	if err == nil && noteAuthor != nil {
-		preview := note.Message
-		if len(preview) > 100 {
-			preview = preview[:100] + "..."
-		}
+		preview := util.TruncatePreview(note.Message, 100)
 		notification := &domain.Notification{
 			Id:               uuid.New(),
 			AccountId:        noteAuthor.Id,
@@ -1127,7 +1307,7 @@ func handleFollowedUserBoost(announceID, objectURI string, embeddedObject map[st
 		}
 
 		if err := database.CreateActivity(activity); err != nil {
-			if !strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			if !db.IsUniqueViolation(err) {
 				return fmt.Errorf("failed to store boosted activity: %w", err)
 			}
 			// Activity already exists, that's fine
@@ -1258,7 +1438,7 @@ func handleRelayAnnounce(announceID, objectURI string, embeddedObject map[string
 	}
 
 	if err := database.CreateActivity(activity); err != nil {
-		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		if db.IsUniqueViolation(err) {
 			log.Printf("Inbox: Relay-forwarded activity %s already exists", announceID)
 			return nil
 		}
@@ -1422,6 +1602,13 @@ func handleAcceptActivityWithDeps(body []byte, username string, deps *InboxDeps)
 	}
 
 	log.Printf("Inbox: Follow %s was accepted by %s", followID, accept.Actor)
+
+	// Show the account's recent posts right away instead of waiting for new ones
+	backfillOutbox(accept.Actor, deps)
+
+	if err, follow := database.ReadFollowByURI(followID); err == nil && follow != nil {
+		events.Publish(events.Event{Kind: events.FollowAccepted, AccountId: follow.AccountId})
+	}
 	return nil
 }
 
@@ -1499,7 +1686,7 @@ func handleUpdateActivityWithDeps(body []byte, username string, deps *InboxDeps)
 
 			if err := database.CreateActivity(newActivity); err != nil {
 				// Check if this is a duplicate (already processed this Update)
-				if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+				if db.IsUniqueViolation(err) {
 					log.Printf("Inbox: Update activity %s already processed", update.ID)
 					return nil
 				}
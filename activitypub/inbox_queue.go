@@ -0,0 +1,170 @@
+package activitypub
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
+	"github.com/deemkeen/stegodon/metrics"
+	"github.com/deemkeen/stegodon/util"
+)
+
+const (
+	// inboxWorkerCount is the number of actors whose activities are processed concurrently
+	inboxWorkerCount = 4
+	// inboxBatchSize is the maximum number of queue items read per round
+	inboxBatchSize = 100
+	// inboxMaxAttempts is the number of failed attempts after which an item is held for admins
+	inboxMaxAttempts = 8
+)
+
+// inboxBackoff is the delay before retry n (the last value repeats)
+var inboxBackoff = []time.Duration{
+	10 * time.Second,
+	30 * time.Second,
+	2 * time.Minute,
+	10 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+}
+
+// inboxWake lets the inbox handler start a round right away instead of waiting for the ticker
+var inboxWake = make(chan struct{}, 1)
+
+// wakeInboxWorker signals the inbox worker that new items were queued
+func wakeInboxWorker() {
+	select {
+	case inboxWake <- struct{}{}:
+	default:
+		// A wake-up is already pending
+	}
+}
+
+// StartInboxWorker starts a background worker that processes the inbox queue.
+// Returns a stop function that can be called to gracefully stop the worker.
+func StartInboxWorker(conf *util.AppConfig) func() {
+	log.Println("Starting ActivityPub inbox worker...")
+
+	ticker := time.NewTicker(5 * time.Second)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-ticker.C:
+				processInboxQueue(conf)
+			case <-inboxWake:
+				processInboxQueue(conf)
+			case <-stop:
+				ticker.Stop()
+				log.Println("ActivityPub inbox worker stopped")
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		// Let the current round finish so no item is left half-processed
+		<-done
+	}
+}
+
+// processInboxQueue processes ready items from the inbox queue.
+// This is the production wrapper that uses the default database.
+func processInboxQueue(conf *util.AppConfig) {
+	deps := &InboxDeps{
+		Database:   NewDBWrapper(),
+		HTTPClient: defaultHTTPClient,
+	}
+	processInboxQueueWithDeps(conf, deps)
+}
+
+// processInboxQueueWithDeps processes ready items from the inbox queue.
+// Items are grouped by actor: each actor's items run in order on one worker,
+// while different actors are processed in parallel by up to inboxWorkerCount workers.
+// This version accepts dependencies for testing.
+func processInboxQueueWithDeps(conf *util.AppConfig, deps *InboxDeps) {
+	err, items := deps.Database.ReadPendingInboxItems(inboxBatchSize)
+	if err != nil {
+		log.Printf("InboxWorker: Failed to read queue: %v", err)
+		return
+	}
+	if items == nil || len(*items) == 0 {
+		return
+	}
+
+	// Group by actor, keeping queue order within each group
+	var actors []string
+	byActor := map[string][]domain.InboxQueueItem{}
+	for _, item := range *items {
+		if _, ok := byActor[item.ActorURI]; !ok {
+			actors = append(actors, item.ActorURI)
+		}
+		byActor[item.ActorURI] = append(byActor[item.ActorURI], item)
+	}
+
+	jobs := make(chan []domain.InboxQueueItem)
+	var wg sync.WaitGroup
+	for i := 0; i < min(inboxWorkerCount, len(actors)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range jobs {
+				processActorInboxItems(group, conf, deps)
+			}
+		}()
+	}
+	for _, actor := range actors {
+		jobs <- byActor[actor]
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// activityReceivedEvent describes a processed inbox item for the event bus, with the sender
+// so that sessions can skip activities of accounts they do not follow
+func activityReceivedEvent(item *domain.InboxQueueItem, database Database) events.Event {
+	e := events.Event{Kind: events.ActivityReceived, ActivityType: item.ActivityType}
+	if err, actor := database.ReadRemoteAccountByActorURI(item.ActorURI); err == nil && actor != nil {
+		e.ActorId = actor.Id
+	}
+	e.FromRelay = item.SignerURI != item.ActorURI || isActorFromAnyRelay(item.ActorURI, database)
+	return e
+}
+
+// processActorInboxItems processes one actor's items in order.
+// After a failure the actor's remaining items wait, so they are never applied before the failed one.
+func processActorInboxItems(items []domain.InboxQueueItem, conf *util.AppConfig, deps *InboxDeps) {
+	database := deps.Database
+	for _, item := range items {
+		err := processInboxItemWithDeps(&item, conf, deps)
+		if err == nil {
+			metrics.InboxActivity(item.ActivityType, metrics.InboxProcessed)
+			events.Publish(activityReceivedEvent(&item, database))
+			if err := database.DeleteInboxItem(item.Id); err != nil {
+				log.Printf("InboxWorker: Failed to remove processed item %s: %v", item.Id, err)
+			}
+			continue
+		}
+
+		item.Attempts++
+		if item.Attempts >= inboxMaxAttempts {
+			log.Printf("InboxWorker: Holding %s from %s after %d attempts: %v", item.ActivityType, item.ActorURI, item.Attempts, err)
+			metrics.InboxActivity(item.ActivityType, metrics.InboxHeld)
+			database.HoldInboxItem(item.Id, item.Attempts, err.Error())
+			// Held items no longer block the actor's newer activities
+			continue
+		}
+
+		backoff := inboxBackoff[min(item.Attempts-1, len(inboxBackoff)-1)]
+		log.Printf("InboxWorker: %s from %s failed (attempt %d), retry in %s: %v", item.ActivityType, item.ActorURI, item.Attempts, backoff, err)
+		metrics.InboxActivity(item.ActivityType, metrics.InboxFailed)
+		database.UpdateInboxItemAttempt(item.Id, item.Attempts, time.Now().Add(backoff), err.Error())
+		return
+	}
+}
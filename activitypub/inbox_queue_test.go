@@ -0,0 +1,173 @@
+package activitypub
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+// queueFollow adds a queued Follow of alice from actorURI
+func queueFollow(mockDB *MockDatabase, actorURI string, createdAt time.Time) *domain.InboxQueueItem {
+	id := uuid.New()
+	item := &domain.InboxQueueItem{
+		Id:       id,
+		Username: "alice",
+		ActivityJSON: fmt.Sprintf(`{
+			"@context": "https://www.w3.org/ns/activitystreams",
+			"id": "%s/follows/%s",
+			"type": "Follow",
+			"actor": "%s",
+			"object": "https://local.example.com/users/alice"
+		}`, actorURI, id, actorURI),
+		ActivityType: "Follow",
+		ActorURI:     actorURI,
+		SignerURI:    actorURI,
+		NextRetryAt:  createdAt,
+		CreatedAt:    createdAt,
+	}
+	mockDB.EnqueueInboxItem(item)
+	return item
+}
+
+func setupInboxQueueTest(t *testing.T) (*MockDatabase, *InboxDeps, *util.AppConfig) {
+	t.Helper()
+
+	mockDB := NewMockDatabase()
+	keypair, err := GenerateTestKeyPair()
+	if err != nil {
+		t.Fatalf("Failed to generate keypair: %v", err)
+	}
+	mockDB.AddAccount(&domain.Account{
+		Id:            uuid.New(),
+		Username:      "alice",
+		WebPrivateKey: keypair.PrivatePEM,
+		WebPublicKey:  keypair.PublicPEM,
+	})
+
+	// bob is cached, carol's actor can't be fetched
+	bob := &domain.RemoteAccount{
+		Id:            uuid.New(),
+		Username:      "bob",
+		Domain:        "remote.example.com",
+		ActorURI:      "https://remote.example.com/users/bob",
+		InboxURI:      "https://remote.example.com/users/bob/inbox",
+		LastFetchedAt: time.Now(),
+	}
+	mockDB.AddRemoteAccount(bob)
+
+	mockHTTP := NewMockHTTPClient()
+	mockHTTP.SetResponse(bob.InboxURI, http.StatusAccepted, nil)
+
+	conf := &util.AppConfig{}
+	conf.Conf.SslDomain = "local.example.com"
+
+	return mockDB, &InboxDeps{Database: mockDB, HTTPClient: mockHTTP}, conf
+}
+
+// TestProcessInboxQueue_RetryKeepsActorOrder tests that a failed item is retried later
+// and holds back newer items of the same actor, but not those of other actors
+func TestProcessInboxQueue_RetryKeepsActorOrder(t *testing.T) {
+	mockDB, deps, conf := setupInboxQueueTest(t)
+
+	base := time.Now().Add(-time.Minute)
+	carolFirst := queueFollow(mockDB, "https://other.example.com/users/carol", base)
+	carolSecond := queueFollow(mockDB, "https://other.example.com/users/carol", base.Add(time.Second))
+	bobFollow := queueFollow(mockDB, "https://remote.example.com/users/bob", base.Add(2*time.Second))
+
+	processInboxQueueWithDeps(conf, deps)
+
+	if _, ok := mockDB.InboxQueue[bobFollow.Id]; ok {
+		t.Error("Expected processed item to be removed from the queue")
+	}
+	if len(mockDB.Follows) != 1 {
+		t.Errorf("Expected bob's follow to be stored, got %d follows", len(mockDB.Follows))
+	}
+
+	first := mockDB.InboxQueue[carolFirst.Id]
+	if first == nil || first.Attempts != 1 || first.Status != domain.InboxQueuePending {
+		t.Fatalf("Expected failed item to stay pending with 1 attempt, got %+v", first)
+	}
+	if !first.NextRetryAt.After(time.Now()) {
+		t.Errorf("Expected retry to be scheduled in the future, got %v", first.NextRetryAt)
+	}
+	if !strings.Contains(first.LastError, "failed to fetch actor") {
+		t.Errorf("Expected last error to be recorded, got %q", first.LastError)
+	}
+	if second := mockDB.InboxQueue[carolSecond.Id]; second == nil || second.Attempts != 0 {
+		t.Errorf("Expected carol's newer item to wait untouched, got %+v", second)
+	}
+
+	// The newer item stays blocked until the older one is retried
+	err, items := mockDB.ReadPendingInboxItems(inboxBatchSize)
+	if err != nil || len(*items) != 0 {
+		t.Errorf("Expected no ready items while carol waits, got %v (err=%v)", items, err)
+	}
+}
+
+// TestProcessInboxQueue_HoldsAfterMaxAttempts tests that items move to the holding area
+func TestProcessInboxQueue_HoldsAfterMaxAttempts(t *testing.T) {
+	mockDB, deps, conf := setupInboxQueueTest(t)
+
+	item := queueFollow(mockDB, "https://other.example.com/users/carol", time.Now().Add(-time.Minute))
+	item.Attempts = inboxMaxAttempts - 1
+
+	processInboxQueueWithDeps(conf, deps)
+
+	held := mockDB.InboxQueue[item.Id]
+	if held == nil || held.Status != domain.InboxQueueHeld {
+		t.Fatalf("Expected item to be held, got %+v", held)
+	}
+	if held.Attempts != inboxMaxAttempts {
+		t.Errorf("Expected %d attempts, got %d", inboxMaxAttempts, held.Attempts)
+	}
+}
+
+// TestHandleInboxWithDeps_QueuesActivity tests that the handler only queues the activity
+func TestHandleInboxWithDeps_QueuesActivity(t *testing.T) {
+	mockDB, deps, conf := setupInboxQueueTest(t)
+
+	keypair, _ := GenerateTestKeyPair()
+	mockDB.RemoteByURI["https://remote.example.com/users/bob"].PublicKeyPem = keypair.PublicPEM
+
+	body := []byte(`{"@context":"https://www.w3.org/ns/activitystreams","id":"https://remote.example.com/follows/1","type":"Follow","actor":"https://remote.example.com/users/bob","object":"https://local.example.com/users/alice"}`)
+	req := createSignedRequest(t, "POST", "/users/alice/inbox", body, keypair, "https://remote.example.com/users/bob#main-key")
+
+	rr := httptest.NewRecorder()
+	HandleInboxWithDeps(rr, req, "alice", conf, deps)
+
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("Expected 202 Accepted, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(mockDB.InboxQueue) != 1 || len(mockDB.Follows) != 0 {
+		t.Fatalf("Expected 1 queued item and no follows yet, got %d/%d", len(mockDB.InboxQueue), len(mockDB.Follows))
+	}
+
+	processInboxQueueWithDeps(conf, deps)
+
+	if len(mockDB.InboxQueue) != 0 || len(mockDB.Follows) != 1 {
+		t.Errorf("Expected queue drained and follow stored, got %d/%d", len(mockDB.InboxQueue), len(mockDB.Follows))
+	}
+}
+
+func TestActivityReceivedEvent(t *testing.T) {
+	mockDB := NewMockDatabase()
+	known := &domain.RemoteAccount{Id: uuid.New(), Username: "bob", Domain: "remote.example", ActorURI: "https://remote.example/users/bob"}
+	mockDB.AddRemoteAccount(known)
+
+	e := activityReceivedEvent(&domain.InboxQueueItem{ActivityType: "Create", ActorURI: known.ActorURI, SignerURI: known.ActorURI}, mockDB)
+	if e.ActorId != known.Id || e.FromRelay || e.ActivityType != "Create" {
+		t.Errorf("Unexpected event for a known actor: %+v", e)
+	}
+
+	e = activityReceivedEvent(&domain.InboxQueueItem{ActivityType: "Create", ActorURI: "https://remote.example/users/carol", SignerURI: "https://relay.example/actor"}, mockDB)
+	if e.ActorId != uuid.Nil || !e.FromRelay {
+		t.Errorf("Expected a relay-forwarded activity of an unknown actor, got %+v", e)
+	}
+}
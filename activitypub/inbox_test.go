@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -3433,6 +3434,7 @@ func TestHandleInboxWithDeps_MissingSignature(t *testing.T) {
 
 	rr := httptest.NewRecorder()
 	HandleInboxWithDeps(rr, req, "alice", conf, deps)
+	processInboxQueueWithDeps(conf, deps)
 
 	if rr.Code != http.StatusUnauthorized {
 		t.Errorf("Expected status 401 Unauthorized, got %d", rr.Code)
@@ -3477,6 +3479,7 @@ func TestHandleInboxWithDeps_InvalidJSON(t *testing.T) {
 
 	rr := httptest.NewRecorder()
 	HandleInboxWithDeps(rr, req, "alice", conf, deps)
+	processInboxQueueWithDeps(conf, deps)
 
 	if rr.Code != http.StatusBadRequest {
 		t.Errorf("Expected status 400 Bad Request, got %d", rr.Code)
@@ -3513,6 +3516,7 @@ func TestHandleInboxWithDeps_UnknownActor(t *testing.T) {
 
 	rr := httptest.NewRecorder()
 	HandleInboxWithDeps(rr, req, "alice", conf, deps)
+	processInboxQueueWithDeps(conf, deps)
 
 	if rr.Code != http.StatusBadRequest {
 		t.Errorf("Expected status 400 Bad Request, got %d", rr.Code)
@@ -3565,6 +3569,7 @@ func TestHandleInboxWithDeps_InvalidSignature(t *testing.T) {
 
 	rr := httptest.NewRecorder()
 	HandleInboxWithDeps(rr, req, "alice", conf, deps)
+	processInboxQueueWithDeps(conf, deps)
 
 	if rr.Code != http.StatusUnauthorized {
 		t.Errorf("Expected status 401 Unauthorized, got %d", rr.Code)
@@ -3627,6 +3632,7 @@ func TestHandleInboxWithDeps_FollowSuccess(t *testing.T) {
 
 	rr := httptest.NewRecorder()
 	HandleInboxWithDeps(rr, req, "alice", conf, deps)
+	processInboxQueueWithDeps(conf, deps)
 
 	if rr.Code != http.StatusAccepted {
 		t.Errorf("Expected status 202 Accepted, got %d: %s", rr.Code, rr.Body.String())
@@ -3709,6 +3715,7 @@ func TestHandleInboxWithDeps_AcceptSuccess(t *testing.T) {
 
 	rr := httptest.NewRecorder()
 	HandleInboxWithDeps(rr, req, "alice", conf, deps)
+	processInboxQueueWithDeps(conf, deps)
 
 	if rr.Code != http.StatusAccepted {
 		t.Errorf("Expected status 202 Accepted, got %d: %s", rr.Code, rr.Body.String())
@@ -3786,6 +3793,7 @@ func TestHandleInboxWithDeps_UndoSuccess(t *testing.T) {
 
 	rr := httptest.NewRecorder()
 	HandleInboxWithDeps(rr, req, "alice", conf, deps)
+	processInboxQueueWithDeps(conf, deps)
 
 	if rr.Code != http.StatusAccepted {
 		t.Errorf("Expected status 202 Accepted, got %d: %s", rr.Code, rr.Body.String())
@@ -3861,6 +3869,7 @@ func TestHandleInboxWithDeps_DeleteSuccess(t *testing.T) {
 
 	rr := httptest.NewRecorder()
 	HandleInboxWithDeps(rr, req, "alice", conf, deps)
+	processInboxQueueWithDeps(conf, deps)
 
 	if rr.Code != http.StatusAccepted {
 		t.Errorf("Expected status 202 Accepted, got %d: %s", rr.Code, rr.Body.String())
@@ -3927,6 +3936,7 @@ func TestHandleInboxWithDeps_UnsupportedType(t *testing.T) {
 
 	rr := httptest.NewRecorder()
 	HandleInboxWithDeps(rr, req, "alice", conf, deps)
+	processInboxQueueWithDeps(conf, deps)
 
 	// Should still return 202 (unsupported types are logged but not rejected)
 	if rr.Code != http.StatusAccepted {
@@ -4013,6 +4023,7 @@ func TestHandleInboxWithDeps_ObjectURIExtraction(t *testing.T) {
 
 			rr := httptest.NewRecorder()
 			HandleInboxWithDeps(rr, req, "alice", conf, deps)
+			processInboxQueueWithDeps(conf, deps)
 
 			if rr.Code != http.StatusAccepted {
 				t.Errorf("Expected 202, got %d", rr.Code)
@@ -4886,3 +4897,182 @@ func TestHandleAnnounceActivity_FollowedRemoteUser_DuplicateBoostIgnored(t *test
 		t.Errorf("Expected 1 boost (no duplicate), got %d", len(mockDB.Boosts))
 	}
 }
+
+// setupReactionTest creates a local note owned by alice and a cached remote actor bob
+func setupReactionTest(t *testing.T) (*MockDatabase, *domain.Note, *domain.RemoteAccount, *InboxDeps) {
+	t.Helper()
+	mockDB := NewMockDatabase()
+
+	mockDB.AddAccount(&domain.Account{
+		Id:       uuid.New(),
+		Username: "alice",
+	})
+
+	noteId := uuid.New()
+	note := &domain.Note{
+		Id:        noteId,
+		CreatedBy: "alice",
+		Message:   "Hello world!",
+		ObjectURI: "https://local.example.com/notes/" + noteId.String(),
+	}
+	mockDB.AddNote(note)
+
+	remoteAccount := &domain.RemoteAccount{
+		Id:            uuid.New(),
+		Username:      "bob",
+		Domain:        "remote.example.com",
+		ActorURI:      "https://remote.example.com/users/bob",
+		InboxURI:      "https://remote.example.com/users/bob/inbox",
+		PublicKeyPem:  "-----BEGIN PUBLIC KEY-----\ntest\n-----END PUBLIC KEY-----",
+		LastFetchedAt: time.Now(),
+	}
+	mockDB.AddRemoteAccount(remoteAccount)
+
+	deps := &InboxDeps{
+		Database:   mockDB,
+		HTTPClient: NewMockHTTPClient(),
+	}
+	return mockDB, note, remoteAccount, deps
+}
+
+// TestHandleEmojiReact_StoresReactionAndNotifies tests that an EmojiReact is stored
+// and a reaction notification is created for the note author
+func TestHandleEmojiReact_StoresReactionAndNotifies(t *testing.T) {
+	mockDB, note, remoteAccount, deps := setupReactionTest(t)
+
+	body := []byte(`{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "https://remote.example.com/activities/react-1",
+		"type": "EmojiReact",
+		"actor": "https://remote.example.com/users/bob",
+		"object": "` + note.ObjectURI + `",
+		"content": "🎉"
+	}`)
+
+	if err := handleEmojiReactActivityWithDeps(body, "alice", deps); err != nil {
+		t.Fatalf("handleEmojiReactActivityWithDeps failed: %v", err)
+	}
+
+	if len(mockDB.Reactions) != 1 {
+		t.Fatalf("Expected 1 reaction stored, got %d", len(mockDB.Reactions))
+	}
+	for _, reaction := range mockDB.Reactions {
+		if reaction.Emoji != "🎉" {
+			t.Errorf("Expected emoji 🎉, got %s", reaction.Emoji)
+		}
+		if reaction.AccountId != remoteAccount.Id {
+			t.Errorf("Reaction has wrong account ID: got %s", reaction.AccountId)
+		}
+		if reaction.NoteId != note.Id {
+			t.Errorf("Reaction has wrong note ID: got %s", reaction.NoteId)
+		}
+	}
+
+	if len(mockDB.Notifications) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(mockDB.Notifications))
+	}
+	if mockDB.Notifications[0].NotificationType != domain.NotificationReaction {
+		t.Errorf("Expected reaction notification, got %s", mockDB.Notifications[0].NotificationType)
+	}
+	if mockDB.Notifications[0].Emoji != "🎉" {
+		t.Errorf("Expected notification emoji 🎉, got %s", mockDB.Notifications[0].Emoji)
+	}
+}
+
+// TestHandleLikeActivity_MisskeyReaction tests that a Like carrying _misskey_reaction
+// is stored as a reaction instead of a plain like
+func TestHandleLikeActivity_MisskeyReaction(t *testing.T) {
+	mockDB, note, _, deps := setupReactionTest(t)
+
+	body := []byte(`{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id": "https://remote.example.com/activities/like-react-1",
+		"type": "Like",
+		"actor": "https://remote.example.com/users/bob",
+		"object": "` + note.ObjectURI + `",
+		"_misskey_reaction": ":blobcat:"
+	}`)
+
+	if err := handleLikeActivityWithDeps(body, "alice", deps); err != nil {
+		t.Fatalf("handleLikeActivityWithDeps failed: %v", err)
+	}
+
+	if len(mockDB.Likes) != 0 {
+		t.Errorf("Expected no plain likes, got %d", len(mockDB.Likes))
+	}
+	if len(mockDB.Reactions) != 1 {
+		t.Fatalf("Expected 1 reaction stored, got %d", len(mockDB.Reactions))
+	}
+	for _, reaction := range mockDB.Reactions {
+		if reaction.Emoji != ":blobcat:" {
+			t.Errorf("Expected emoji :blobcat:, got %s", reaction.Emoji)
+		}
+	}
+}
+
+// TestHandleEmojiReact_ReplacesPreviousReaction tests that a second reaction from the
+// same account replaces the first one
+func TestHandleEmojiReact_ReplacesPreviousReaction(t *testing.T) {
+	mockDB, note, _, deps := setupReactionTest(t)
+
+	for i, emoji := range []string{"👍", "🔥"} {
+		body := []byte(fmt.Sprintf(`{
+			"id": "https://remote.example.com/activities/react-%d",
+			"type": "EmojiReact",
+			"actor": "https://remote.example.com/users/bob",
+			"object": "%s",
+			"content": "%s"
+		}`, i, note.ObjectURI, emoji))
+		if err := handleEmojiReactActivityWithDeps(body, "alice", deps); err != nil {
+			t.Fatalf("handleEmojiReactActivityWithDeps failed: %v", err)
+		}
+	}
+
+	if len(mockDB.Reactions) != 1 {
+		t.Fatalf("Expected 1 reaction after replacement, got %d", len(mockDB.Reactions))
+	}
+	for _, reaction := range mockDB.Reactions {
+		if reaction.Emoji != "🔥" {
+			t.Errorf("Expected latest emoji 🔥, got %s", reaction.Emoji)
+		}
+	}
+}
+
+// TestHandleUndoEmojiReact tests that Undo of a reaction removes it, including
+// Misskey-style Undo Like pointing at a reaction
+func TestHandleUndoEmojiReact(t *testing.T) {
+	for _, undoType := range []string{"EmojiReact", "Like"} {
+		t.Run(undoType, func(t *testing.T) {
+			mockDB, note, remoteAccount, deps := setupReactionTest(t)
+
+			reaction := &domain.Reaction{
+				Id:        uuid.New(),
+				AccountId: remoteAccount.Id,
+				NoteId:    note.Id,
+				ObjectURI: note.ObjectURI,
+				Emoji:     "👍",
+				URI:       "https://remote.example.com/activities/react-1",
+			}
+			mockDB.Reactions[reaction.Id] = reaction
+
+			undoBody := []byte(`{
+				"id": "https://remote.example.com/activities/undo-react-1",
+				"type": "Undo",
+				"actor": "https://remote.example.com/users/bob",
+				"object": {
+					"id": "https://remote.example.com/activities/react-1",
+					"type": "` + undoType + `",
+					"actor": "https://remote.example.com/users/bob",
+					"object": "` + note.ObjectURI + `"
+				}
+			}`)
+
+			if err := handleUndoActivityWithDeps(undoBody, "alice", remoteAccount, deps); err != nil {
+				t.Fatalf("handleUndoActivityWithDeps failed: %v", err)
+			}
+			if len(mockDB.Reactions) != 0 {
+				t.Errorf("Expected reaction to be removed, got %d", len(mockDB.Reactions))
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package activitypub
 
 import (
 	"database/sql"
+	"sort"
 	"sync"
 	"time"
 
@@ -20,19 +21,28 @@ type MockDatabase struct {
 	RemoteAccounts  map[uuid.UUID]*domain.RemoteAccount
 	RemoteByURI     map[string]*domain.RemoteAccount
 	RemoteByActor   map[string]*domain.RemoteAccount
+	RemoteFailures  map[uuid.UUID]int       // Consecutive not-found refetch failures
+	RemoteFailedAt  map[uuid.UUID]time.Time // Last failed refetch
+	RemoteGone      map[uuid.UUID]bool
 	Follows         map[uuid.UUID]*domain.Follow
 	FollowsByURI    map[string]*domain.Follow
 	Activities      map[uuid.UUID]*domain.Activity
 	ActivitiesByObj map[string]*domain.Activity
 	ActivitiesByURI map[string]*domain.Activity // Index by ActivityURI
 	DeliveryQueue   map[uuid.UUID]*domain.DeliveryQueueItem
+	InboxQueue      map[uuid.UUID]*domain.InboxQueueItem
 	Notes           map[uuid.UUID]*domain.Note
 	NotesByURI      map[string]*domain.Note
 	Likes           map[uuid.UUID]*domain.Like
 	LikesByURI      map[string]*domain.Like
+	Reactions       map[uuid.UUID]*domain.Reaction
 	Boosts          map[uuid.UUID]*domain.Boost
 	Relays          map[uuid.UUID]*domain.Relay
 	RelaysByURI     map[string]*domain.Relay
+	Notifications   []*domain.Notification
+	Hashtags        map[string]int64
+	NoteHashtags    map[uuid.UUID][]int64
+	CustomEmojis    []domain.CustomEmoji
 
 	// Error injection for testing error handling
 	ForceError error
@@ -41,6 +51,19 @@ type MockDatabase struct {
 	IncrementReplyCountCalls []string    // URIs passed to IncrementReplyCountByURI
 	IncrementLikeCountCalls  []uuid.UUID // Note IDs passed to IncrementLikeCountByNoteId
 	IncrementBoostCountCalls []uuid.UUID // Note IDs passed to IncrementBoostCountByNoteId
+	PruneCalls               []PruneCall // Arguments passed to PruneFederatedContent
+	VacuumCalls              int
+
+	// Result returned by PruneFederatedContent
+	PruneStats domain.RetentionStats
+}
+
+// PruneCall records the arguments of a PruneFederatedContent call
+type PruneCall struct {
+	Cutoff          time.Time
+	AccountsBefore  time.Time
+	LocalNotePrefix string
+	DryRun          bool
 }
 
 // NewMockDatabase creates a new mock database with initialized maps
@@ -51,19 +74,26 @@ func NewMockDatabase() *MockDatabase {
 		RemoteAccounts:  make(map[uuid.UUID]*domain.RemoteAccount),
 		RemoteByURI:     make(map[string]*domain.RemoteAccount),
 		RemoteByActor:   make(map[string]*domain.RemoteAccount),
+		RemoteFailures:  make(map[uuid.UUID]int),
+		RemoteFailedAt:  make(map[uuid.UUID]time.Time),
+		RemoteGone:      make(map[uuid.UUID]bool),
 		Follows:         make(map[uuid.UUID]*domain.Follow),
 		FollowsByURI:    make(map[string]*domain.Follow),
 		Activities:      make(map[uuid.UUID]*domain.Activity),
 		ActivitiesByObj: make(map[string]*domain.Activity),
 		ActivitiesByURI: make(map[string]*domain.Activity),
 		DeliveryQueue:   make(map[uuid.UUID]*domain.DeliveryQueueItem),
+		InboxQueue:      make(map[uuid.UUID]*domain.InboxQueueItem),
 		Notes:           make(map[uuid.UUID]*domain.Note),
 		NotesByURI:      make(map[string]*domain.Note),
 		Likes:           make(map[uuid.UUID]*domain.Like),
 		LikesByURI:      make(map[string]*domain.Like),
+		Reactions:       make(map[uuid.UUID]*domain.Reaction),
 		Boosts:          make(map[uuid.UUID]*domain.Boost),
 		Relays:          make(map[uuid.UUID]*domain.Relay),
 		RelaysByURI:     make(map[string]*domain.Relay),
+		Hashtags:        make(map[string]int64),
+		NoteHashtags:    make(map[uuid.UUID][]int64),
 	}
 }
 
@@ -211,6 +241,58 @@ func (m *MockDatabase) UpdateRemoteAccount(acc *domain.RemoteAccount) error {
 	m.RemoteAccounts[acc.Id] = acc
 	m.RemoteByURI[acc.ActorURI] = acc
 	m.RemoteByActor[acc.ActorURI] = acc
+	// A successful fetch clears the refresh failure state
+	delete(m.RemoteFailures, acc.Id)
+	delete(m.RemoteFailedAt, acc.Id)
+	delete(m.RemoteGone, acc.Id)
+	return nil
+}
+
+func (m *MockDatabase) ReadStaleRemoteAccounts(fetchedBefore, retryBefore time.Time, limit int) (error, *[]domain.RemoteAccount) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.ForceError != nil {
+		return m.ForceError, nil
+	}
+	var accounts []domain.RemoteAccount
+	for id, acc := range m.RemoteAccounts {
+		if m.RemoteGone[id] || !acc.LastFetchedAt.Before(fetchedBefore) {
+			continue
+		}
+		if failedAt, ok := m.RemoteFailedAt[id]; ok && !failedAt.Before(retryBefore) {
+			continue
+		}
+		accounts = append(accounts, *acc)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].LastFetchedAt.Before(accounts[j].LastFetchedAt)
+	})
+	if len(accounts) > limit {
+		accounts = accounts[:limit]
+	}
+	return nil, &accounts
+}
+
+func (m *MockDatabase) RecordRemoteAccountFetchFailure(id uuid.UUID, notFound bool) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ForceError != nil {
+		return 0, m.ForceError
+	}
+	if notFound {
+		m.RemoteFailures[id]++
+	}
+	m.RemoteFailedAt[id] = time.Now()
+	return m.RemoteFailures[id], nil
+}
+
+func (m *MockDatabase) MarkRemoteAccountGone(id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ForceError != nil {
+		return m.ForceError
+	}
+	m.RemoteGone[id] = true
 	return nil
 }
 
@@ -458,6 +540,93 @@ func (m *MockDatabase) DeleteDelivery(id uuid.UUID) error {
 	return nil
 }
 
+// Inbox queue operations
+
+func (m *MockDatabase) EnqueueInboxItem(item *domain.InboxQueueItem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ForceError != nil {
+		return m.ForceError
+	}
+	if item.Status == "" {
+		item.Status = domain.InboxQueuePending
+	}
+	m.InboxQueue[item.Id] = item
+	return nil
+}
+
+func (m *MockDatabase) ReadPendingInboxItems(limit int) (error, *[]domain.InboxQueueItem) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.ForceError != nil {
+		return m.ForceError, nil
+	}
+	var pending []domain.InboxQueueItem
+	for _, item := range m.InboxQueue {
+		if item.Status == domain.InboxQueuePending {
+			pending = append(pending, *item)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].CreatedAt.Before(pending[j].CreatedAt) })
+
+	// Same rule as the real query: an actor's items wait behind its older items that wait for a retry
+	now := time.Now()
+	blocked := map[string]bool{}
+	items := []domain.InboxQueueItem{}
+	for _, item := range pending {
+		if item.NextRetryAt.After(now) {
+			blocked[item.ActorURI] = true
+			continue
+		}
+		if blocked[item.ActorURI] {
+			continue
+		}
+		items = append(items, item)
+		if len(items) >= limit {
+			break
+		}
+	}
+	return nil, &items
+}
+
+func (m *MockDatabase) UpdateInboxItemAttempt(id uuid.UUID, attempts int, nextRetry time.Time, lastError string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ForceError != nil {
+		return m.ForceError
+	}
+	if item, ok := m.InboxQueue[id]; ok {
+		item.Attempts = attempts
+		item.NextRetryAt = nextRetry
+		item.LastError = lastError
+	}
+	return nil
+}
+
+func (m *MockDatabase) HoldInboxItem(id uuid.UUID, attempts int, lastError string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ForceError != nil {
+		return m.ForceError
+	}
+	if item, ok := m.InboxQueue[id]; ok {
+		item.Status = domain.InboxQueueHeld
+		item.Attempts = attempts
+		item.LastError = lastError
+	}
+	return nil
+}
+
+func (m *MockDatabase) DeleteInboxItem(id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ForceError != nil {
+		return m.ForceError
+	}
+	delete(m.InboxQueue, id)
+	return nil
+}
+
 // Note operations
 
 func (m *MockDatabase) ReadNoteByURI(objectURI string) (error, *domain.Note) {
@@ -473,6 +642,45 @@ func (m *MockDatabase) ReadNoteByURI(objectURI string) (error, *domain.Note) {
 	return nil, note
 }
 
+func (m *MockDatabase) ReadNoteId(id uuid.UUID) (error, *domain.Note) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.ForceError != nil {
+		return m.ForceError, nil
+	}
+	note, ok := m.Notes[id]
+	if !ok {
+		return sql.ErrNoRows, nil
+	}
+	return nil, note
+}
+
+// Hashtag operations
+
+func (m *MockDatabase) CreateOrUpdateHashtag(name string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ForceError != nil {
+		return 0, m.ForceError
+	}
+	if id, ok := m.Hashtags[name]; ok {
+		return id, nil
+	}
+	id := int64(len(m.Hashtags) + 1)
+	m.Hashtags[name] = id
+	return id, nil
+}
+
+func (m *MockDatabase) LinkNoteHashtags(noteId uuid.UUID, hashtagIds []int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ForceError != nil {
+		return m.ForceError
+	}
+	m.NoteHashtags[noteId] = append(m.NoteHashtags[noteId], hashtagIds...)
+	return nil
+}
+
 // Mention operations
 
 func (m *MockDatabase) CreateNoteMention(mention *domain.NoteMention) error {
@@ -589,6 +797,70 @@ func (m *MockDatabase) DecrementLikeCountByNoteId(noteId uuid.UUID) error {
 	return nil
 }
 
+// Reaction operations
+
+func (m *MockDatabase) CreateReaction(reaction *domain.Reaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ForceError != nil {
+		return m.ForceError
+	}
+	m.Reactions[reaction.Id] = reaction
+	return nil
+}
+
+func (m *MockDatabase) ReadReactionByURI(uri string) (error, *domain.Reaction) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.ForceError != nil {
+		return m.ForceError, nil
+	}
+	for _, reaction := range m.Reactions {
+		if reaction.URI == uri {
+			return nil, reaction
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockDatabase) ReadReactionByAccountAndNote(accountId, noteId uuid.UUID) (error, *domain.Reaction) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.ForceError != nil {
+		return m.ForceError, nil
+	}
+	for _, reaction := range m.Reactions {
+		if reaction.AccountId == accountId && reaction.NoteId == noteId {
+			return nil, reaction
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockDatabase) DeleteReactionByURI(uri string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ForceError != nil {
+		return m.ForceError
+	}
+	for id, reaction := range m.Reactions {
+		if reaction.URI == uri {
+			delete(m.Reactions, id)
+		}
+	}
+	return nil
+}
+
+func (m *MockDatabase) DeleteReactionById(id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ForceError != nil {
+		return m.ForceError
+	}
+	delete(m.Reactions, id)
+	return nil
+}
+
 // AddNote adds a note to the mock database
 func (m *MockDatabase) AddNote(note *domain.Note) {
 	m.mu.Lock()
@@ -816,14 +1088,50 @@ func (m *MockDatabase) DeleteRelay(id uuid.UUID) error {
 	return nil
 }
 
-// CreateNotification creates a notification (no-op for mock)
+// CreateNotification records a notification so tests can inspect it
 func (m *MockDatabase) CreateNotification(notification *domain.Notification) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if m.ForceError != nil {
 		return m.ForceError
 	}
-	// No-op for now, can be extended if needed for testing
+	m.Notifications = append(m.Notifications, notification)
+	return nil
+}
+
+// Custom emoji operations
+
+func (m *MockDatabase) ReadAllCustomEmojis() (error, *[]domain.CustomEmoji) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.ForceError != nil {
+		return m.ForceError, nil
+	}
+	emojis := make([]domain.CustomEmoji, len(m.CustomEmojis))
+	copy(emojis, m.CustomEmojis)
+	return nil, &emojis
+}
+
+// Retention operations
+
+func (m *MockDatabase) PruneFederatedContent(cutoff, accountsBefore time.Time, localNotePrefix string, dryRun bool) (*domain.RetentionStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ForceError != nil {
+		return nil, m.ForceError
+	}
+	m.PruneCalls = append(m.PruneCalls, PruneCall{cutoff, accountsBefore, localNotePrefix, dryRun})
+	stats := m.PruneStats
+	return &stats, nil
+}
+
+func (m *MockDatabase) IncrementalVacuum() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ForceError != nil {
+		return m.ForceError
+	}
+	m.VacuumCalls++
 	return nil
 }
 
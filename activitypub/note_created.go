@@ -0,0 +1,109 @@
+package activitypub
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+// NoteCreated does what follows the creation of a local note, however it was posted
+// (editor, scheduler, CLI or Mastodon API): it links the note's hashtags, notifies the
+// local author of the note it replies to and notifies the mentioned local users.
+// Federating the note is left to the caller.
+// This is the production wrapper that uses the default database.
+func NoteCreated(noteId uuid.UUID, author *domain.Account, message string, inReplyToURI string, conf *util.AppConfig) {
+	NoteCreatedWithDeps(noteId, author, message, inReplyToURI, conf, NewDBWrapper())
+}
+
+// NoteCreatedWithDeps does what follows the creation of a local note.
+// This version accepts dependencies for testing.
+func NoteCreatedWithDeps(noteId uuid.UUID, author *domain.Account, message string, inReplyToURI string, conf *util.AppConfig, database Database) {
+	LinkHashtagsWithDeps(noteId, message, database)
+
+	preview := NotePreview(message)
+
+	// Reply notification for the local author of the parent note
+	if inReplyToURI != "" {
+		var parent *domain.Note
+		if strings.HasPrefix(inReplyToURI, "local:") {
+			if parentId, err := uuid.Parse(strings.TrimPrefix(inReplyToURI, "local:")); err == nil {
+				_, parent = database.ReadNoteId(parentId)
+			}
+		} else {
+			_, parent = database.ReadNoteByURI(inReplyToURI)
+		}
+		if parent != nil {
+			notifyLocalUser(database, parent.CreatedBy, author, domain.NotificationReply, noteId, preview)
+		}
+	}
+
+	// Mention notifications for local users
+	for _, mention := range util.ParseMentions(message) {
+		if mention.Domain != "" && mention.Domain != conf.Conf.SslDomain {
+			continue
+		}
+		notifyLocalUser(database, mention.Username, author, domain.NotificationMention, noteId, preview)
+	}
+}
+
+// LinkHashtags creates the hashtags used in a message and links them to the note.
+// This is the production wrapper that uses the default database.
+func LinkHashtags(noteId uuid.UUID, message string) {
+	LinkHashtagsWithDeps(noteId, message, NewDBWrapper())
+}
+
+// LinkHashtagsWithDeps creates the hashtags used in a message and links them to the note.
+// This version accepts dependencies for testing.
+func LinkHashtagsWithDeps(noteId uuid.UUID, message string, database Database) {
+	hashtags := util.ParseHashtags(message)
+	if len(hashtags) == 0 {
+		return
+	}
+	hashtagIds := make([]int64, 0, len(hashtags))
+	for _, tag := range hashtags {
+		hashtagId, err := database.CreateOrUpdateHashtag(tag)
+		if err != nil {
+			log.Printf("Failed to create/update hashtag %s: %v", tag, err)
+			continue
+		}
+		hashtagIds = append(hashtagIds, hashtagId)
+	}
+	if len(hashtagIds) > 0 {
+		if err := database.LinkNoteHashtags(noteId, hashtagIds); err != nil {
+			log.Printf("Failed to link hashtags to note: %v", err)
+		}
+	}
+}
+
+// NotePreview returns the notification preview of a message
+func NotePreview(message string) string {
+	return util.TruncatePreview(util.StripHTMLTags(message), 100)
+}
+
+// notifyLocalUser notifies the local user username about a note by actor,
+// unless the user does not exist or is the actor
+func notifyLocalUser(database Database, username string, actor *domain.Account, notificationType domain.NotificationType, noteId uuid.UUID, preview string) {
+	err, recipient := database.ReadAccByUsername(username)
+	if err != nil || recipient == nil || recipient.Id == actor.Id {
+		return
+	}
+	notification := &domain.Notification{
+		Id:               uuid.New(),
+		AccountId:        recipient.Id,
+		NotificationType: notificationType,
+		ActorId:          actor.Id,
+		ActorUsername:    actor.Username,
+		ActorDomain:      "", // Empty for local users
+		NoteId:           noteId,
+		NotePreview:      preview,
+		Read:             false,
+		CreatedAt:        time.Now(),
+	}
+	if err := database.CreateNotification(notification); err != nil {
+		log.Printf("Failed to create %s notification: %v", notificationType, err)
+	}
+}
@@ -0,0 +1,60 @@
+package activitypub
+
+import (
+	"testing"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+func TestNoteCreatedWithDeps(t *testing.T) {
+	mockDB := NewMockDatabase()
+	alice := &domain.Account{Id: uuid.New(), Username: "alice"}
+	bob := &domain.Account{Id: uuid.New(), Username: "bob"}
+	carol := &domain.Account{Id: uuid.New(), Username: "carol"}
+	for _, acc := range []*domain.Account{alice, bob, carol} {
+		mockDB.AddAccount(acc)
+	}
+	parent := &domain.Note{Id: uuid.New(), CreatedBy: "bob", Message: "hello"}
+	mockDB.AddNote(parent)
+
+	conf := &util.AppConfig{}
+	conf.Conf.SslDomain = "local.example"
+
+	noteId := uuid.New()
+	message := "@carol@local.example @alice @dave@remote.example see #golang"
+	NoteCreatedWithDeps(noteId, alice, message, "local:"+parent.Id.String(), conf, mockDB)
+
+	notified := map[string]domain.NotificationType{}
+	for _, n := range mockDB.Notifications {
+		if n.NoteId != noteId || n.ActorId != alice.Id {
+			t.Errorf("Unexpected notification %+v", n)
+		}
+		err, acc := mockDB.ReadAccById(n.AccountId)
+		if err != nil {
+			t.Fatalf("Notification for unknown account %s", n.AccountId)
+		}
+		notified[acc.Username] = n.NotificationType
+	}
+	if len(notified) != 2 || notified["bob"] != domain.NotificationReply || notified["carol"] != domain.NotificationMention {
+		t.Errorf("Expected a reply notification for bob and a mention for carol, got %v", notified)
+	}
+
+	if len(mockDB.NoteHashtags[noteId]) != 1 || mockDB.Hashtags["golang"] == 0 {
+		t.Errorf("Expected #golang to be linked, got %v", mockDB.NoteHashtags)
+	}
+}
+
+func TestNoteCreatedWithDeps_OwnReply(t *testing.T) {
+	mockDB := NewMockDatabase()
+	alice := &domain.Account{Id: uuid.New(), Username: "alice"}
+	mockDB.AddAccount(alice)
+	parent := &domain.Note{Id: uuid.New(), CreatedBy: "alice", ObjectURI: "https://local.example/notes/1"}
+	mockDB.AddNote(parent)
+
+	NoteCreatedWithDeps(uuid.New(), alice, "continued", parent.ObjectURI, &util.AppConfig{}, mockDB)
+	if len(mockDB.Notifications) != 0 {
+		t.Errorf("Expected no notification for a reply to oneself, got %+v", mockDB.Notifications)
+	}
+}
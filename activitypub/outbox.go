@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -205,6 +206,10 @@ func SendCreateWithDeps(note *domain.Note, localAccount *domain.Account, conf *u
 		})
 	}
 
+	// Add Emoji tags for local custom emoji used in the note
+	emojiTags := EmojiTags(note.Message, baseURL, readLocalEmojiMap(database))
+	tags = append(tags, emojiTags...)
+
 	if len(tags) > 0 {
 		noteObj["tag"] = tags
 	}
@@ -222,18 +227,11 @@ func SendCreateWithDeps(note *domain.Note, localAccount *domain.Account, conf *u
 		noteObj["content"] = contentHTML
 	}
 
-	// Build context - include Hashtag definition if we have hashtags
-	var context any
-	if len(hashtags) > 0 {
-		context = []any{
-			"https://www.w3.org/ns/activitystreams",
-			map[string]any{
-				"Hashtag": "as:Hashtag",
-			},
-		}
-	} else {
-		context = "https://www.w3.org/ns/activitystreams"
-	}
+	// Long-form notes federate as Articles
+	ApplyArticle(noteObj, note)
+
+	// Build context - include Hashtag/Emoji definitions if the note uses them
+	context := NoteContext(len(hashtags) > 0, len(emojiTags) > 0)
 
 	create := map[string]any{
 		"@context":  context,
@@ -451,6 +449,10 @@ func SendUpdateWithDeps(note *domain.Note, localAccount *domain.Account, conf *u
 		})
 	}
 
+	// Add Emoji tags for local custom emoji used in the note
+	emojiTags := EmojiTags(note.Message, baseURL, readLocalEmojiMap(database))
+	tags = append(tags, emojiTags...)
+
 	if len(tags) > 0 {
 		noteObj["tag"] = tags
 	}
@@ -468,18 +470,11 @@ func SendUpdateWithDeps(note *domain.Note, localAccount *domain.Account, conf *u
 		noteObj["content"] = contentHTML
 	}
 
-	// Build context - include Hashtag definition if we have hashtags
-	var context any
-	if len(hashtags) > 0 {
-		context = []any{
-			"https://www.w3.org/ns/activitystreams",
-			map[string]any{
-				"Hashtag": "as:Hashtag",
-			},
-		}
-	} else {
-		context = "https://www.w3.org/ns/activitystreams"
-	}
+	// Long-form notes federate as Articles
+	ApplyArticle(noteObj, note)
+
+	// Build context - include Hashtag/Emoji definitions if the note uses them
+	context := NoteContext(len(hashtags) > 0, len(emojiTags) > 0)
 
 	update := map[string]any{
 		"@context": context,
@@ -676,6 +671,12 @@ func SendDeleteWithDeps(noteId uuid.UUID, localAccount *domain.Account, conf *ut
 	return nil
 }
 
+// ErrAlreadyFollowing is returned by SendFollow when the account already follows the actor
+var ErrAlreadyFollowing = errors.New("already following")
+
+// ErrFollowPending is returned by SendFollow when the actor has not accepted an earlier follow yet
+var ErrFollowPending = errors.New("follow pending")
+
 // SendFollow sends a Follow activity to a remote actor.
 // This is the production wrapper that uses the default HTTP client and database.
 func SendFollow(localAccount *domain.Account, remoteActorURI string, conf *util.AppConfig) error {
@@ -709,11 +710,11 @@ func SendFollowWithDeps(localAccount *domain.Account, remoteActorURI string, con
 		if existingFollow.Accepted {
 			// Already following and accepted
 			log.Printf("SendFollow: User %s is already following %s@%s (accepted)", localAccount.Username, remoteActor.Username, remoteActor.Domain)
-			return fmt.Errorf("already following %s@%s", remoteActor.Username, remoteActor.Domain)
+			return fmt.Errorf("%w %s@%s", ErrAlreadyFollowing, remoteActor.Username, remoteActor.Domain)
 		} else {
 			// Follow exists but pending acceptance
 			log.Printf("SendFollow: User %s has pending follow request to %s@%s", localAccount.Username, remoteActor.Username, remoteActor.Domain)
-			return fmt.Errorf("follow pending %s@%s", remoteActor.Username, remoteActor.Domain)
+			return fmt.Errorf("%w %s@%s", ErrFollowPending, remoteActor.Username, remoteActor.Domain)
 		}
 	}
 
@@ -866,6 +867,98 @@ func SendUndoLikeWithDeps(localAccount *domain.Account, noteURI string, likeURI
 	return SendActivityWithDeps(undo, remoteActor.InboxURI, localAccount, conf, client)
 }
 
+// SendEmojiReact sends an EmojiReact activity for a note.
+// This is the production wrapper that uses the default HTTP client and database.
+func SendEmojiReact(localAccount *domain.Account, noteURI string, emoji string, reactionURI string, conf *util.AppConfig) error {
+	return SendEmojiReactWithDeps(localAccount, noteURI, emoji, reactionURI, conf, defaultHTTPClient, NewDBWrapper())
+}
+
+// SendEmojiReactWithDeps sends an EmojiReact activity for a note.
+// The _misskey_reaction field is included for compatibility with Misskey-based servers.
+// This version accepts dependencies for testing.
+func SendEmojiReactWithDeps(localAccount *domain.Account, noteURI string, emoji string, reactionURI string, conf *util.AppConfig, client HTTPClient, database Database) error {
+	// Find the author of the note to deliver the reaction
+	authorURI := extractAuthorFromURI(noteURI, database, conf)
+	if authorURI == "" {
+		return fmt.Errorf("could not determine note author for %s", noteURI)
+	}
+
+	// Check if this is a local note (don't send ActivityPub for local reactions)
+	if strings.Contains(authorURI, conf.Conf.SslDomain) {
+		log.Printf("Outbox: Skipping EmojiReact delivery for local note %s", noteURI)
+		return nil
+	}
+
+	// Fetch remote actor to get inbox
+	remoteActor, err := GetOrFetchActorWithDeps(authorURI, client, database)
+	if err != nil {
+		return fmt.Errorf("failed to fetch note author: %w", err)
+	}
+
+	actorURI := fmt.Sprintf("https://%s/users/%s", conf.Conf.SslDomain, localAccount.Username)
+
+	react := map[string]any{
+		"@context":          "https://www.w3.org/ns/activitystreams",
+		"id":                reactionURI,
+		"type":              "EmojiReact",
+		"actor":             actorURI,
+		"object":            noteURI,
+		"content":           emoji,
+		"_misskey_reaction": emoji,
+	}
+
+	log.Printf("Outbox: Sending EmojiReact %s from %s for note %s to %s@%s", emoji, localAccount.Username, noteURI, remoteActor.Username, remoteActor.Domain)
+	return SendActivityWithDeps(react, remoteActor.InboxURI, localAccount, conf, client)
+}
+
+// SendUndoEmojiReact sends an Undo activity for an EmojiReact.
+// This is the production wrapper that uses the default HTTP client and database.
+func SendUndoEmojiReact(localAccount *domain.Account, noteURI string, emoji string, reactionURI string, conf *util.AppConfig) error {
+	return SendUndoEmojiReactWithDeps(localAccount, noteURI, emoji, reactionURI, conf, defaultHTTPClient, NewDBWrapper())
+}
+
+// SendUndoEmojiReactWithDeps sends an Undo activity for an EmojiReact.
+// This version accepts dependencies for testing.
+func SendUndoEmojiReactWithDeps(localAccount *domain.Account, noteURI string, emoji string, reactionURI string, conf *util.AppConfig, client HTTPClient, database Database) error {
+	// Find the author of the note to deliver the Undo
+	authorURI := extractAuthorFromURI(noteURI, database, conf)
+	if authorURI == "" {
+		return fmt.Errorf("could not determine note author for %s", noteURI)
+	}
+
+	// Check if this is a local note (don't send ActivityPub for local reactions)
+	if strings.Contains(authorURI, conf.Conf.SslDomain) {
+		log.Printf("Outbox: Skipping Undo EmojiReact delivery for local note %s", noteURI)
+		return nil
+	}
+
+	// Fetch remote actor to get inbox
+	remoteActor, err := GetOrFetchActorWithDeps(authorURI, client, database)
+	if err != nil {
+		return fmt.Errorf("failed to fetch note author: %w", err)
+	}
+
+	undoID := fmt.Sprintf("https://%s/activities/%s", conf.Conf.SslDomain, uuid.New().String())
+	actorURI := fmt.Sprintf("https://%s/users/%s", conf.Conf.SslDomain, localAccount.Username)
+
+	undo := map[string]any{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"id":       undoID,
+		"type":     "Undo",
+		"actor":    actorURI,
+		"object": map[string]any{
+			"id":      reactionURI,
+			"type":    "EmojiReact",
+			"actor":   actorURI,
+			"object":  noteURI,
+			"content": emoji,
+		},
+	}
+
+	log.Printf("Outbox: Sending Undo EmojiReact from %s for note %s to %s@%s", localAccount.Username, noteURI, remoteActor.Username, remoteActor.Domain)
+	return SendActivityWithDeps(undo, remoteActor.InboxURI, localAccount, conf, client)
+}
+
 // SendAnnounce sends an Announce activity (boost) for a note.
 // This is the production wrapper that uses the default HTTP client and database.
 func SendAnnounce(localAccount *domain.Account, noteURI string, announceURI string, conf *util.AppConfig) error {
@@ -1095,6 +1188,11 @@ func extractAuthorFromURI(objectURI string, database Database, conf *util.AppCon
 	return ""
 }
 
+// ResolveHandle resolves @username@domain to an ActivityPub actor URI using WebFinger
+func ResolveHandle(username, domain string) (string, error) {
+	return resolveMentionURI(username, domain)
+}
+
 // resolveMentionURI resolves a @username@domain mention to an ActivityPub actor URI
 // using WebFinger lookup
 func resolveMentionURI(username, domain string) (string, error) {
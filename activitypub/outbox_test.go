@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"testing"
@@ -1496,8 +1497,8 @@ func TestSendFollowWithDeps_AlreadyFollowing(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for already following")
 	}
-	if !strings.Contains(err.Error(), "already following") {
-		t.Errorf("Error should mention already following: %v", err)
+	if !errors.Is(err, ErrAlreadyFollowing) || !strings.Contains(err.Error(), "already following") {
+		t.Errorf("Error should be ErrAlreadyFollowing: %v", err)
 	}
 }
 
@@ -1559,8 +1560,8 @@ func TestSendFollowWithDeps_PendingFollow(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for pending follow")
 	}
-	if !strings.Contains(err.Error(), "follow pending") {
-		t.Errorf("Error should mention pending follow: %v", err)
+	if !errors.Is(err, ErrFollowPending) || !strings.Contains(err.Error(), "follow pending") {
+		t.Errorf("Error should be ErrFollowPending: %v", err)
 	}
 }
 
@@ -1760,6 +1761,72 @@ func TestSendCreateWithDeps_WithFollowers(t *testing.T) {
 	}
 }
 
+// TestSendCreateWithDeps_Article tests that notes with a title federate as Articles
+func TestSendCreateWithDeps_Article(t *testing.T) {
+	mockDB := NewMockDatabase()
+
+	keypair, _ := GenerateTestKeyPair()
+	account := CreateTestAccount("alice", keypair)
+	mockDB.AddAccount(account)
+
+	remoteActor := &domain.RemoteAccount{
+		Id:       uuid.New(),
+		Username: "bob",
+		Domain:   "remote1.example.com",
+		ActorURI: "https://remote1.example.com/users/bob",
+		InboxURI: "https://remote1.example.com/users/bob/inbox",
+	}
+	mockDB.AddRemoteAccount(remoteActor)
+	mockDB.AddFollow(&domain.Follow{
+		Id:              uuid.New(),
+		AccountId:       remoteActor.Id,
+		TargetAccountId: account.Id,
+		URI:             "https://remote1.example.com/follows/1",
+		Accepted:        true,
+		CreatedAt:       time.Now(),
+	})
+
+	conf := &util.AppConfig{}
+	conf.Conf.SslDomain = "local.example.com"
+
+	note := &domain.Note{
+		Id:        uuid.New(),
+		CreatedBy: account.Username,
+		Title:     "Why Plates?",
+		Message:   "## Theories\n\nThermoregulation, **display**, or both.",
+		CreatedAt: time.Now(),
+	}
+
+	if err := SendCreateWithDeps(note, account, conf, mockDB); err != nil {
+		t.Fatalf("SendCreateWithDeps failed: %v", err)
+	}
+	if len(mockDB.DeliveryQueue) != 1 {
+		t.Fatalf("Expected 1 delivery queue item, got %d", len(mockDB.DeliveryQueue))
+	}
+
+	for _, item := range mockDB.DeliveryQueue {
+		var activity map[string]any
+		if err := json.Unmarshal([]byte(item.ActivityJSON), &activity); err != nil {
+			t.Fatalf("Failed to parse activity JSON: %v", err)
+		}
+		obj := activity["object"].(map[string]any)
+
+		if obj["type"] != "Article" {
+			t.Errorf("Expected object type 'Article', got %v", obj["type"])
+		}
+		if obj["name"] != "Why Plates?" {
+			t.Errorf("Expected name 'Why Plates?', got %v", obj["name"])
+		}
+		content, _ := obj["content"].(string)
+		if !strings.Contains(content, "<h2") || !strings.Contains(content, "<strong>display</strong>") {
+			t.Errorf("Expected rendered markdown content, got %q", content)
+		}
+		if obj["summary"] != "Theories Thermoregulation, display, or both." {
+			t.Errorf("Expected plain-text summary, got %v", obj["summary"])
+		}
+	}
+}
+
 // TestSendUpdateWithDeps_NoFollowers tests updating a note with no followers
 func TestSendUpdateWithDeps_NoFollowers(t *testing.T) {
 	mockDB := NewMockDatabase()
@@ -0,0 +1,191 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+// ResolvedObject is a remote post or account looked up by its URL or ActivityPub id
+type ResolvedObject struct {
+	Activity *domain.Activity      // The post, stored as a Create activity (nil for accounts)
+	Actor    *domain.RemoteAccount // The resolved account, or the author of Activity
+}
+
+// actorTypes are the ActivityStreams types of accounts that can be opened as profiles
+var actorTypes = map[string]bool{
+	"Person":       true,
+	"Service":      true,
+	"Application":  true,
+	"Group":        true,
+	"Organization": true,
+}
+
+// postTypes are the object types that are stored and shown as posts
+var postTypes = map[string]bool{
+	"Note":     true,
+	"Article":  true,
+	"Page":     true,
+	"Question": true,
+}
+
+// ResolveObject fetches a post or account by URL (content negotiated) and stores it locally
+func ResolveObject(uri string) (*ResolvedObject, error) {
+	return ResolveObjectWithDeps(uri, defaultHTTPClient, NewDBWrapper())
+}
+
+// ResolveObjectWithDeps fetches a post or account by URL and stores it locally.
+// Posts that are already stored are returned without fetching them again.
+// This version accepts dependencies for testing.
+func ResolveObjectWithDeps(uri string, client HTTPClient, database Database) (*ResolvedObject, error) {
+	if err, activity := database.ReadActivityByObjectURI(uri); err == nil && activity != nil {
+		return resolvedPost(activity, client, database), nil
+	}
+
+	object, err := fetchActivityPubObject(uri, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", uri, err)
+	}
+
+	// Unwrap activities that carry the post (e.g. an id pointing to a Create)
+	objectType, _ := object["type"].(string)
+	if objectType == "Create" || objectType == "Update" {
+		switch inner := object["object"].(type) {
+		case map[string]any:
+			object = inner
+		case string:
+			if object, err = fetchActivityPubObject(inner, client); err != nil {
+				return nil, fmt.Errorf("failed to fetch %s: %w", inner, err)
+			}
+		}
+		objectType, _ = object["type"].(string)
+	}
+
+	id, _ := object["id"].(string)
+	if id == "" {
+		id = uri
+	}
+
+	switch {
+	case actorTypes[objectType]:
+		actor, err := GetOrFetchActorWithDeps(id, client, database)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch account %s: %w", id, err)
+		}
+		return &ResolvedObject{Actor: actor}, nil
+
+	case postTypes[objectType]:
+		// The canonical id may differ from the pasted (web UI) URL
+		if err, activity := database.ReadActivityByObjectURI(id); err == nil && activity != nil {
+			return resolvedPost(activity, client, database), nil
+		}
+		activity, err := storeRemotePost(id, object, false, database)
+		if err != nil {
+			return nil, err
+		}
+		return resolvedPost(activity, client, database), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported object type %q", objectType)
+	}
+}
+
+// resolvedPost attaches the author to a stored post, fetching the account if needed
+func resolvedPost(activity *domain.Activity, client HTTPClient, database Database) *ResolvedObject {
+	actor, err := GetOrFetchActorWithDeps(activity.ActorURI, client, database)
+	if err != nil {
+		log.Printf("Resolve: Failed to fetch author %s: %v", activity.ActorURI, err)
+	}
+	return &ResolvedObject{Activity: activity, Actor: actor}
+}
+
+// storeRemotePost stores a fetched post as a Create activity so it can be
+// shown in threads and liked, boosted or replied to like any federated post
+func storeRemotePost(objectURI string, object map[string]any, backfilled bool, database Database) (*domain.Activity, error) {
+	actorURI := attributedToURI(object)
+	if actorURI == "" {
+		return nil, fmt.Errorf("post %s has no attributedTo", objectURI)
+	}
+
+	inReplyTo, _ := object["inReplyTo"].(string)
+	objectURL, _ := object["url"].(string)
+
+	createdAt := time.Now()
+	if published, ok := object["published"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, published); err == nil {
+			createdAt = t
+		}
+	}
+
+	rawJSON, err := json.Marshal(map[string]any{
+		"@context": "https://www.w3.org/ns/activitystreams",
+		"type":     "Create",
+		"actor":    actorURI,
+		"object":   object,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal post: %w", err)
+	}
+
+	activity := &domain.Activity{
+		Id:           uuid.New(),
+		ActivityURI:  objectURI + "#create", // Synthetic activity URI
+		ActivityType: "Create",
+		ActorURI:     actorURI,
+		ObjectURI:    objectURI,
+		ObjectURL:    objectURL,
+		InReplyTo:    inReplyTo,
+		RawJSON:      string(rawJSON),
+		Processed:    true,
+		CreatedAt:    createdAt,
+		Backfilled:   backfilled,
+	}
+	if err := database.CreateActivity(activity); err != nil {
+		if !db.IsUniqueViolation(err) {
+			return nil, fmt.Errorf("failed to store post: %w", err)
+		}
+		// Stored concurrently (e.g. delivered to the inbox meanwhile)
+		if err, existing := database.ReadActivityByObjectURI(objectURI); err == nil && existing != nil {
+			return existing, nil
+		}
+	}
+
+	log.Printf("Stored post %s from %s", objectURI, actorURI)
+	return activity, nil
+}
+
+// attributedToURI returns the author of an object. attributedTo may be a URI,
+// an embedded actor or a list of either (the first Person wins).
+func attributedToURI(object map[string]any) string {
+	var uriOf func(v any) string
+	uriOf = func(v any) string {
+		switch a := v.(type) {
+		case string:
+			return a
+		case map[string]any:
+			id, _ := a["id"].(string)
+			return id
+		case []any:
+			for _, item := range a {
+				if m, ok := item.(map[string]any); ok && m["type"] != nil && m["type"] != "Person" {
+					continue
+				}
+				if uri := uriOf(item); uri != "" {
+					return uri
+				}
+			}
+		}
+		return ""
+	}
+
+	if uri := uriOf(object["attributedTo"]); uri != "" {
+		return uri
+	}
+	actor, _ := object["actor"].(string)
+	return actor
+}
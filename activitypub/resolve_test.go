@@ -0,0 +1,114 @@
+package activitypub
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveObjectWithDeps_Post(t *testing.T) {
+	mockDB := NewMockDatabase()
+	actor := CreateTestRemoteAccount("https://remote.example.com", "bob", "")
+	mockDB.AddRemoteAccount(actor)
+	mockHTTP := NewMockHTTPClient()
+
+	// The web UI URL content-negotiates to the canonical object
+	webURL := "https://remote.example.com/@bob/123"
+	objectURI := "https://remote.example.com/users/bob/statuses/123"
+	mockHTTP.SetJSONResponse(webURL, 200, map[string]any{
+		"id":           objectURI,
+		"type":         "Note",
+		"attributedTo": actor.ActorURI,
+		"content":      "<p>hello</p>",
+		"url":          webURL,
+		"published":    "2024-05-01T10:00:00Z",
+	})
+
+	resolved, err := ResolveObjectWithDeps(webURL, mockHTTP, mockDB)
+	if err != nil {
+		t.Fatalf("ResolveObjectWithDeps failed: %v", err)
+	}
+	if resolved.Activity == nil || resolved.Activity.ObjectURI != objectURI {
+		t.Fatalf("Expected stored post with canonical URI, got %+v", resolved.Activity)
+	}
+	if resolved.Activity.ObjectURL != webURL || resolved.Activity.ActivityType != "Create" {
+		t.Errorf("Unexpected stored activity: %+v", resolved.Activity)
+	}
+	if !resolved.Activity.CreatedAt.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected published time, got %v", resolved.Activity.CreatedAt)
+	}
+	if resolved.Actor == nil || resolved.Actor.Id != actor.Id {
+		t.Errorf("Expected author to be attached, got %+v", resolved.Actor)
+	}
+
+	// Resolving the canonical id again uses the stored post
+	requests := len(mockHTTP.Requests)
+	again, err := ResolveObjectWithDeps(objectURI, mockHTTP, mockDB)
+	if err != nil || again.Activity.Id != resolved.Activity.Id {
+		t.Errorf("Expected stored post to be reused, got %+v (err=%v)", again, err)
+	}
+	if len(mockHTTP.Requests) != requests {
+		t.Errorf("Expected no fetch for a stored post, got %d requests", len(mockHTTP.Requests)-requests)
+	}
+}
+
+func TestResolveObjectWithDeps_Account(t *testing.T) {
+	mockDB := NewMockDatabase()
+	actor := CreateTestRemoteAccount("https://remote.example.com", "bob", "")
+	mockDB.AddRemoteAccount(actor)
+	mockHTTP := NewMockHTTPClient()
+
+	profileURL := "https://remote.example.com/@bob"
+	mockHTTP.SetJSONResponse(profileURL, 200, map[string]any{
+		"id":   actor.ActorURI,
+		"type": "Person",
+	})
+
+	resolved, err := ResolveObjectWithDeps(profileURL, mockHTTP, mockDB)
+	if err != nil {
+		t.Fatalf("ResolveObjectWithDeps failed: %v", err)
+	}
+	if resolved.Activity != nil || resolved.Actor == nil || resolved.Actor.Id != actor.Id {
+		t.Errorf("Expected the cached account, got %+v", resolved)
+	}
+}
+
+func TestResolveObjectWithDeps_Errors(t *testing.T) {
+	mockDB := NewMockDatabase()
+	mockHTTP := NewMockHTTPClient()
+
+	if _, err := ResolveObjectWithDeps("https://remote.example.com/missing", mockHTTP, mockDB); err == nil {
+		t.Error("Expected error for a 404")
+	}
+
+	mockHTTP.SetJSONResponse("https://remote.example.com/like/1", 200, map[string]any{
+		"id":   "https://remote.example.com/like/1",
+		"type": "Like",
+	})
+	if _, err := ResolveObjectWithDeps("https://remote.example.com/like/1", mockHTTP, mockDB); err == nil {
+		t.Error("Expected error for an unsupported type")
+	}
+}
+
+func TestAttributedToURI(t *testing.T) {
+	tests := []struct {
+		name   string
+		object map[string]any
+		want   string
+	}{
+		{"string", map[string]any{"attributedTo": "https://a.example/u/1"}, "https://a.example/u/1"},
+		{"embedded", map[string]any{"attributedTo": map[string]any{"id": "https://a.example/u/2"}}, "https://a.example/u/2"},
+		{"list prefers person", map[string]any{"attributedTo": []any{
+			map[string]any{"type": "Group", "id": "https://a.example/c/1"},
+			map[string]any{"type": "Person", "id": "https://a.example/u/3"},
+		}}, "https://a.example/u/3"},
+		{"actor fallback", map[string]any{"actor": "https://a.example/u/4"}, "https://a.example/u/4"},
+		{"missing", map[string]any{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := attributedToURI(tt.object); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package activitypub
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+)
+
+const (
+	// retentionInterval is how often the retention job runs
+	retentionInterval = 24 * time.Hour
+	// retentionAccountGrace keeps recently fetched remote accounts even if nothing refers to them yet,
+	// e.g. while an incoming follow or post is still being processed
+	retentionAccountGrace = 24 * time.Hour
+)
+
+// StartRetentionWorker starts a background worker that prunes old federated content once a day.
+// The worker does nothing while retentionDays is 0.
+// Returns a stop function that can be called to gracefully stop the worker.
+func StartRetentionWorker(conf *util.AppConfig) func() {
+	log.Println("Starting retention worker...")
+
+	ticker := time.NewTicker(retentionInterval)
+	stop := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if conf.Conf.RetentionDays > 0 {
+					PruneFederatedContent(conf, false)
+				}
+			case <-stop:
+				ticker.Stop()
+				log.Println("Retention worker stopped")
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+	}
+}
+
+// PruneFederatedContent prunes federated content older than retentionDays, or with dryRun
+// only counts what would be pruned.
+// This is the production wrapper that uses the default database.
+func PruneFederatedContent(conf *util.AppConfig, dryRun bool) (*domain.RetentionStats, error) {
+	return pruneFederatedContentWithDeps(conf, dryRun, time.Now(), NewDBWrapper())
+}
+
+// pruneFederatedContentWithDeps removes remote posts nobody interacted with, remote boosts of
+// removed posts, old notifications and orphaned remote accounts, then returns free pages to
+// the file system. This version accepts dependencies for testing.
+func pruneFederatedContentWithDeps(conf *util.AppConfig, dryRun bool, now time.Time, database Database) (*domain.RetentionStats, error) {
+	if conf.Conf.RetentionDays <= 0 {
+		return nil, fmt.Errorf("retention is disabled (retentionDays is 0)")
+	}
+
+	cutoff := now.AddDate(0, 0, -conf.Conf.RetentionDays)
+	localNotePrefix := fmt.Sprintf("https://%s/notes/", conf.Conf.SslDomain)
+
+	stats, err := database.PruneFederatedContent(cutoff, now.Add(-retentionAccountGrace), localNotePrefix, dryRun)
+	if err != nil {
+		log.Printf("Retention: Failed to prune: %v", err)
+		return nil, err
+	}
+	if dryRun {
+		return stats, nil
+	}
+
+	log.Printf("Retention: Pruned %d posts, %d boosts, %d notifications, %d remote accounts older than %d days",
+		stats.Activities, stats.Boosts, stats.Notifications, stats.RemoteAccounts, conf.Conf.RetentionDays)
+
+	if stats.Activities+stats.Boosts+stats.Notifications+stats.RemoteAccounts > 0 {
+		if err := database.IncrementalVacuum(); err != nil {
+			log.Printf("Retention: Incremental vacuum failed: %v", err)
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,69 @@
+package activitypub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+)
+
+func retentionTestConfig(days int) *util.AppConfig {
+	conf := &util.AppConfig{}
+	conf.Conf.SslDomain = "local.example"
+	conf.Conf.RetentionDays = days
+	return conf
+}
+
+func TestPruneFederatedContentWithDeps(t *testing.T) {
+	mockDB := NewMockDatabase()
+	mockDB.PruneStats = domain.RetentionStats{Activities: 3, RemoteAccounts: 1}
+	now := time.Date(2026, 5, 31, 12, 0, 0, 0, time.UTC)
+
+	stats, err := pruneFederatedContentWithDeps(retentionTestConfig(30), false, now, mockDB)
+	if err != nil {
+		t.Fatalf("pruneFederatedContentWithDeps failed: %v", err)
+	}
+	if stats.Activities != 3 || stats.RemoteAccounts != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if len(mockDB.PruneCalls) != 1 {
+		t.Fatalf("Expected 1 prune call, got %d", len(mockDB.PruneCalls))
+	}
+	call := mockDB.PruneCalls[0]
+	if !call.Cutoff.Equal(time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected cutoff 30 days back, got %v", call.Cutoff)
+	}
+	if call.LocalNotePrefix != "https://local.example/notes/" || call.DryRun {
+		t.Errorf("Unexpected prune call: %+v", call)
+	}
+	if mockDB.VacuumCalls != 1 {
+		t.Errorf("Expected vacuum after pruning, got %d calls", mockDB.VacuumCalls)
+	}
+}
+
+func TestPruneFederatedContentWithDeps_DryRun(t *testing.T) {
+	mockDB := NewMockDatabase()
+	mockDB.PruneStats = domain.RetentionStats{Notifications: 5}
+
+	if _, err := pruneFederatedContentWithDeps(retentionTestConfig(30), true, time.Now(), mockDB); err != nil {
+		t.Fatalf("pruneFederatedContentWithDeps failed: %v", err)
+	}
+	if len(mockDB.PruneCalls) != 1 || !mockDB.PruneCalls[0].DryRun {
+		t.Errorf("Expected a dry run, got %+v", mockDB.PruneCalls)
+	}
+	if mockDB.VacuumCalls != 0 {
+		t.Error("Expected no vacuum after a dry run")
+	}
+}
+
+func TestPruneFederatedContentWithDeps_Disabled(t *testing.T) {
+	mockDB := NewMockDatabase()
+
+	if _, err := pruneFederatedContentWithDeps(retentionTestConfig(0), true, time.Now(), mockDB); err == nil {
+		t.Error("Expected error when retention is disabled")
+	}
+	if len(mockDB.PruneCalls) != 0 {
+		t.Error("Expected nothing to be pruned when retention is disabled")
+	}
+}
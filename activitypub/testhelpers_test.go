@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"time"
 
 	"github.com/deemkeen/stegodon/domain"
@@ -385,6 +386,8 @@ type MockHTTPClient struct {
 	DefaultResponse *http.Response
 	// DefaultError is returned when no specific error is configured
 	DefaultError error
+
+	mu sync.Mutex // Inbox workers call Do concurrently
 }
 
 // NewMockHTTPClient creates a new mock HTTP client
@@ -398,6 +401,8 @@ func NewMockHTTPClient() *MockHTTPClient {
 
 // Do implements the HTTPClient interface
 func (c *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.Requests = append(c.Requests, req)
 
 	url := req.URL.String()
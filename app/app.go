@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -14,7 +15,10 @@ import (
 	"github.com/charmbracelet/wish"
 	"github.com/charmbracelet/wish/logging"
 	"github.com/deemkeen/stegodon/activitypub"
+	"github.com/deemkeen/stegodon/backup"
 	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/health"
+	"github.com/deemkeen/stegodon/metrics"
 	"github.com/deemkeen/stegodon/middleware"
 	"github.com/deemkeen/stegodon/util"
 	"github.com/deemkeen/stegodon/web"
@@ -38,8 +42,14 @@ type App struct {
 	config             *util.AppConfig
 	sshServer          *ssh.Server
 	httpServer         *http.Server
+	metricsServer      *http.Server // Prometheus endpoint, nil unless metricsAddr is set
 	done               chan os.Signal
 	stopDeliveryWorker func() // Stop function for ActivityPub delivery worker
+	stopInboxWorker    func() // Stop function for ActivityPub inbox worker
+	stopActorRefresher func() // Stop function for the remote actor refresh worker
+	stopRetention      func() // Stop function for the retention worker
+	stopScheduler      func() // Stop function for the scheduled notes worker
+	stopBackups        func() // Stop function for the backup worker
 }
 
 // New creates a new App instance with the given configuration
@@ -50,55 +60,24 @@ func New(conf *util.AppConfig) (*App, error) {
 	}, nil
 }
 
-// Initialize sets up the database, runs migrations, and initializes servers
-func (a *App) Initialize() error {
-	// Run database migrations
+// Migrate opens the database and applies the pending migrations
+func (a *App) Migrate() error {
 	log.Println("Running database migrations...")
-	database := db.GetDB()
-	if err := database.RunActivityPubMigrations(); err != nil {
-		log.Printf("Warning: Migration errors (may be normal if tables exist): %v", err)
+	db.Configure(a.config.Conf.DatabaseURL)
+	if err := db.GetDB().RunMigrations(); err != nil {
+		return fmt.Errorf("database migration failed: %w", err)
 	}
 	log.Println("Database migrations complete")
+	return nil
+}
 
-	// Run key format migration (PKCS#1 to PKCS#8)
-	log.Println("Checking for key format migration...")
-	if err := database.MigrateKeysToPKCS8(); err != nil {
-		log.Printf("Warning: Key migration encountered errors: %v", err)
-		log.Println("You may need to manually review the migration. See logs above for details.")
-	} else {
-		log.Println("Key format migration complete")
-	}
-
-	// Run duplicate follows cleanup migration
-	log.Println("Checking for duplicate follows...")
-	if err := database.MigrateDuplicateFollows(); err != nil {
-		log.Printf("Warning: Duplicate follows migration encountered errors: %v", err)
-		log.Println("You may need to manually review the migration. See logs above for details.")
-	} else {
-		log.Println("Duplicate follows migration complete")
-	}
-
-	// Run local reply counts migration
-	log.Println("Checking for uncounted local replies...")
-	if err := database.MigrateLocalReplyCounts(); err != nil {
-		log.Printf("Warning: Local reply counts migration encountered errors: %v", err)
-	} else {
-		log.Println("Local reply counts migration complete")
-	}
-
-	// Run performance indexes migration
-	if err := database.MigratePerformanceIndexes(); err != nil {
-		log.Printf("Warning: Performance indexes migration encountered errors: %v", err)
-	}
-
-	// Run orphan activities cleanup migration (fixes bug where Create activities
-	// were stored before checking follow relationships)
-	log.Println("Checking for orphan activities...")
-	if err := database.MigrateOrphanActivities(); err != nil {
-		log.Printf("Warning: Orphan activities migration encountered errors: %v", err)
-	} else {
-		log.Println("Orphan activities migration complete")
+// Initialize sets up the database, runs migrations, and initializes servers
+func (a *App) Initialize() error {
+	// Refuse to start on a half-migrated schema
+	if err := a.Migrate(); err != nil {
+		return err
 	}
+	database := db.GetDB()
 
 	// Cleanup expired IP bans (IP addresses older than 60 days are cleared)
 	database.CleanupExpiredIPBans()
@@ -113,6 +92,7 @@ func (a *App) Initialize() error {
 		wish.WithPublicKeyAuth(func(ssh.Context, ssh.PublicKey) bool { return true }),
 		wish.WithMiddleware(
 			middleware.MainTui(),
+			middleware.MetricsMiddleware(),
 			middleware.AuthMiddleware(a.config),
 			logging.MiddlewareWithLogger(log.Default()),
 		),
@@ -133,23 +113,55 @@ func (a *App) Initialize() error {
 		Handler: headToGetWrapper(router),
 	}
 
+	// Metrics get their own listener so they are never exposed with the public web UI
+	if a.config.Conf.MetricsAddr != "" {
+		metrics.RegisterStats(database)
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		a.metricsServer = &http.Server{
+			Addr:    a.config.Conf.MetricsAddr,
+			Handler: mux,
+		}
+	}
+
 	return nil
 }
 
 // Start starts all servers and blocks until a shutdown signal is received
 func (a *App) Start() error {
-	// Start ActivityPub delivery worker if enabled
+	// Start ActivityPub delivery, inbox and actor refresh workers if enabled
 	if a.config.Conf.WithAp {
 		a.stopDeliveryWorker = activitypub.StartDeliveryWorker(a.config)
+		a.stopInboxWorker = activitypub.StartInboxWorker(a.config)
+		a.stopActorRefresher = activitypub.StartActorRefreshWorker(a.config)
+		a.stopRetention = activitypub.StartRetentionWorker(a.config)
+	}
+
+	// Publish scheduled notes (federated only when ActivityPub is enabled)
+	a.stopScheduler = startScheduler(a.config)
+
+	// Online backups are SQLite snapshots; PostgreSQL has its own tools
+	if a.config.Conf.BackupHours > 0 {
+		if a.config.Conf.DatabaseURL != "" {
+			log.Println("Scheduled backups are disabled on PostgreSQL, use pg_dump")
+		} else {
+			a.stopBackups = backup.StartWorker(a.config)
+		}
 	}
 
 	// Setup signal handling
 	signal.Notify(a.done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
-	// Start SSH server
+	// Start SSH server. Listening here, before serving, lets /readyz report
+	// the SSH server as up only once it accepts connections.
 	log.Printf("Starting SSH server on %s:%d", a.config.Conf.Host, a.config.Conf.SshPort)
+	sshListener, err := net.Listen("tcp", a.sshServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for SSH: %w", err)
+	}
+	health.SetSSHListening(true)
 	go func() {
-		if err := a.sshServer.ListenAndServe(); err != nil && err != ssh.ErrServerClosed {
+		if err := a.sshServer.Serve(sshListener); err != nil && err != ssh.ErrServerClosed {
 			log.Fatalf("SSH server error: %v", err)
 		}
 	}()
@@ -162,6 +174,15 @@ func (a *App) Start() error {
 		}
 	}()
 
+	if a.metricsServer != nil {
+		log.Printf("Starting metrics server on %s", a.config.Conf.MetricsAddr)
+		go func() {
+			if err := a.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Metrics server error: %v", err)
+			}
+		}()
+	}
+
 	// Wait for shutdown signal
 	<-a.done
 	log.Println("Shutdown signal received")
@@ -184,6 +205,35 @@ func (a *App) Shutdown() error {
 		a.stopDeliveryWorker()
 	}
 
+	// Stop the inbox worker; queued activities stay in the database for the next start
+	if a.stopInboxWorker != nil {
+		log.Println("Stopping ActivityPub inbox worker...")
+		a.stopInboxWorker()
+	}
+
+	if a.stopActorRefresher != nil {
+		log.Println("Stopping actor refresh worker...")
+		a.stopActorRefresher()
+	}
+
+	if a.stopRetention != nil {
+		log.Println("Stopping retention worker...")
+		a.stopRetention()
+	}
+
+	if a.stopScheduler != nil {
+		log.Println("Stopping scheduled notes worker...")
+		a.stopScheduler()
+	}
+
+	if a.stopBackups != nil {
+		log.Println("Stopping backup worker...")
+		a.stopBackups()
+	}
+
+	// Readiness fails from here on, the SSH server is stopped below
+	health.SetSSHListening(false)
+
 	// Shutdown HTTP server (stop accepting new requests)
 	log.Println("Stopping HTTP server...")
 	if err := a.httpServer.Shutdown(ctx); err != nil {
@@ -193,6 +243,13 @@ func (a *App) Shutdown() error {
 		log.Println("HTTP server stopped gracefully")
 	}
 
+	if a.metricsServer != nil {
+		log.Println("Stopping metrics server...")
+		if err := a.metricsServer.Shutdown(ctx); err != nil {
+			log.Printf("Metrics server shutdown error: %v", err)
+		}
+	}
+
 	// Shutdown SSH server
 	log.Println("Stopping SSH server...")
 	if err := a.sshServer.Shutdown(ctx); err != nil {
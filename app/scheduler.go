@@ -0,0 +1,93 @@
+package app
+
+import (
+	"log"
+	"time"
+
+	"github.com/deemkeen/stegodon/activitypub"
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+const (
+	// schedulerInterval is how often due scheduled notes are published
+	schedulerInterval = 30 * time.Second
+	// schedulerBatchSize is the maximum number of notes published per tick
+	schedulerBatchSize = 50
+)
+
+// startScheduler starts a background worker that publishes scheduled notes when they are due.
+// Returns a stop function that can be called to gracefully stop the worker.
+func startScheduler(conf *util.AppConfig) func() {
+	log.Println("Starting scheduled notes worker...")
+
+	ticker := time.NewTicker(schedulerInterval)
+	stop := make(chan struct{})
+
+	go func() {
+		// Publish notes that became due while the server was down
+		publishDueNotes(conf)
+		for {
+			select {
+			case <-ticker.C:
+				publishDueNotes(conf)
+			case <-stop:
+				ticker.Stop()
+				log.Println("Scheduled notes worker stopped")
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+	}
+}
+
+// publishDueNotes publishes all scheduled notes that are due
+func publishDueNotes(conf *util.AppConfig) {
+	database := db.GetDB()
+	err, due := database.ReadDueScheduledNotes(time.Now(), schedulerBatchSize)
+	if err != nil {
+		log.Printf("Scheduler: Failed to read due notes: %v", err)
+		return
+	}
+
+	for i := range *due {
+		scheduled := &(*due)[i]
+		noteId, err := database.PublishScheduledNote(scheduled)
+		if err != nil {
+			log.Printf("Scheduler: Failed to publish scheduled note %s: %v", scheduled.Id, err)
+			continue
+		}
+		publishSideEffects(database, conf, scheduled, noteId)
+		log.Printf("Scheduler: Published scheduled note %s as %s", scheduled.Id, noteId)
+	}
+}
+
+// publishSideEffects does what the editor does after a note is created:
+// links hashtags, notifies mentioned local users and federates the note
+func publishSideEffects(database db.Store, conf *util.AppConfig, scheduled *domain.ScheduledNote, noteId uuid.UUID) {
+	err, author := database.ReadAccById(scheduled.AccountId)
+	if err != nil || author == nil {
+		log.Printf("Scheduler: Failed to read author of %s: %v", noteId, err)
+		return
+	}
+
+	activitypub.NoteCreated(noteId, author, scheduled.Message, "", conf)
+
+	// Federate the note via ActivityPub
+	if !conf.Conf.WithAp {
+		return
+	}
+	err, createdNote := database.ReadNoteIdWithReplyInfo(noteId)
+	if err != nil {
+		log.Printf("Scheduler: Failed to read published note for federation: %v", err)
+		return
+	}
+	if err := activitypub.SendCreate(createdNote, author, conf); err != nil {
+		log.Printf("Scheduler: Failed to federate note: %v", err)
+	}
+}
@@ -0,0 +1,501 @@
+// Package backup writes online snapshots of the SQLite database, together with the
+// uploaded avatars and the SSH host key, to tar.gz archives, and restores them.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/util"
+)
+
+const (
+	// archivePrefix and archiveSuffix frame the archive names; the timestamp between
+	// them sorts chronologically, which rotation relies on
+	archivePrefix = "stegodon-backup-"
+	archiveSuffix = ".tar.gz"
+	archiveTime   = "20060102-150405"
+
+	manifestName = "manifest.json"
+	databaseName = "database.db"
+	avatarsName  = "avatars"
+	hostKeyName  = ".ssh/stegodonhostkey"
+)
+
+// Source is the database being backed up
+type Source interface {
+	SnapshotTo(path string) error
+	ReadSchemaVersion() (int, error)
+}
+
+// Paths are the files a backup covers
+type Paths struct {
+	Database string // SQLite database file
+	Avatars  string // Directory of uploaded avatars
+	HostKey  string // SSH host key; its .pub file is included when present
+}
+
+// DefaultPaths returns the files the server uses
+func DefaultPaths() Paths {
+	paths := Paths{
+		Database: util.ResolveFilePath(databaseName),
+		HostKey:  util.ResolveFilePathWithSubdir(".ssh", "stegodonhostkey"),
+	}
+	if configDir, err := util.GetConfigDir(); err == nil {
+		paths.Avatars = filepath.Join(configDir, avatarsName)
+	}
+	return paths
+}
+
+// Dir returns the configured backup directory, ~/.config/stegodon/backups by default
+func Dir(conf *util.AppConfig) (string, error) {
+	if conf.Conf.BackupDir != "" {
+		return conf.Conf.BackupDir, nil
+	}
+	configDir, err := util.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "backups"), nil
+}
+
+// Manifest describes a backup archive
+type Manifest struct {
+	Version       string    `json:"version"`        // stegodon version that wrote the backup
+	SchemaVersion int       `json:"schema_version"` // Database schema version, see db.SchemaVersion
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Create snapshots the database and writes it with the avatars and host key to a new
+// archive in dir, then removes all but the newest keep archives. It returns the archive path.
+func Create(src Source, paths Paths, dir string, keep int, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	schemaVersion, err := src.ReadSchemaVersion()
+	if err != nil {
+		return "", fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	// VACUUM INTO needs a path that does not exist yet
+	name := archivePrefix + now.Format(archiveTime)
+	snapshot := filepath.Join(dir, "."+name+".db")
+	os.Remove(snapshot)
+	if err := src.SnapshotTo(snapshot); err != nil {
+		return "", fmt.Errorf("failed to snapshot database: %w", err)
+	}
+	defer os.Remove(snapshot)
+
+	manifest := Manifest{Version: util.GetVersion(), SchemaVersion: schemaVersion, CreatedAt: now}
+	archive := filepath.Join(dir, name+archiveSuffix)
+	if err := writeArchive(archive, manifest, snapshot, paths); err != nil {
+		return "", err
+	}
+
+	if _, err := Rotate(dir, keep); err != nil {
+		log.Printf("Backup: Failed to remove old backups: %v", err)
+	}
+	return archive, nil
+}
+
+// writeArchive writes the archive to a temporary file first, so an interrupted
+// backup never leaves a truncated archive behind
+func writeArchive(archive string, manifest Manifest, snapshot string, paths Paths) error {
+	tmp := archive + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer os.Remove(tmp)
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	err = addArchiveEntries(tw, manifest, snapshot, paths)
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return os.Rename(tmp, archive)
+}
+
+func addArchiveEntries(tw *tar.Writer, manifest Manifest, snapshot string, paths Paths) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: manifestName, Mode: 0600, Size: int64(len(data)), ModTime: manifest.CreatedAt}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+
+	if err := addFile(tw, databaseName, snapshot); err != nil {
+		return err
+	}
+
+	if paths.Avatars != "" {
+		entries, err := os.ReadDir(paths.Avatars)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() {
+				if err := addFile(tw, avatarsName+"/"+entry.Name(), filepath.Join(paths.Avatars, entry.Name())); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if paths.HostKey != "" {
+		for _, suffix := range []string{"", ".pub"} {
+			if _, err := os.Stat(paths.HostKey + suffix); err == nil {
+				if err := addFile(tw, hostKeyName+suffix, paths.HostKey+suffix); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func addFile(tw *tar.Writer, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header := &tar.Header{Name: name, Mode: int64(info.Mode().Perm()), Size: info.Size(), ModTime: info.ModTime()}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// List returns the backup archives in dir, oldest first
+func List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var archives []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, archivePrefix) && strings.HasSuffix(name, archiveSuffix) {
+			archives = append(archives, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(archives)
+	return archives, nil
+}
+
+// Rotate removes all but the newest keep archives in dir and returns the removed paths
+func Rotate(dir string, keep int) ([]string, error) {
+	archives, err := List(dir)
+	if err != nil || keep < 1 || len(archives) <= keep {
+		return nil, err
+	}
+	removed := archives[:len(archives)-keep]
+	for _, archive := range removed {
+		if err := os.Remove(archive); err != nil {
+			return nil, err
+		}
+	}
+	return removed, nil
+}
+
+// Restored describes what Restore replaced
+type Restored struct {
+	Manifest Manifest
+	Avatars  int      // Number of avatar files written
+	HostKey  bool     // Whether the host key was restored
+	Previous []string // Paths the replaced files were moved to
+}
+
+// Restore replaces the database, and writes the avatars and host key, from archive.
+// The database in the archive is checked before anything is replaced: it must pass
+// SQLite's integrity check and must not have a newer schema than this stegodon.
+// Restore refuses to run while the current database is in use, so the server must be
+// stopped. All files are copied next to their destinations before the first one is
+// replaced, which leaves only renames that can fail halfway. Replaced files are kept
+// next to the originals with a .pre-restore suffix.
+func Restore(archive string, paths Paths, now time.Time) (*Restored, error) {
+	// The lock is held until the restore is done, so no server can start meanwhile
+	if _, err := os.Stat(paths.Database); err == nil {
+		current, err := db.Open(paths.Database)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open current database: %w", err)
+		}
+		defer current.Close()
+		if err := current.LockExclusive(); err != nil {
+			return nil, fmt.Errorf("current database is in use, stop the server first: %w", err)
+		}
+	}
+
+	staging, err := os.MkdirTemp(filepath.Dir(paths.Database), ".restore-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	manifest, err := extract(archive, staging)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDatabase(filepath.Join(staging, databaseName)); err != nil {
+		return nil, err
+	}
+
+	// Copies of the avatars and host key in their destination directories, removed
+	// again unless renamed into place
+	var avatars, hostKeys []stagedFile
+	defer func() {
+		for _, f := range append(avatars, hostKeys...) {
+			os.Remove(f.path)
+		}
+	}()
+
+	entries, _ := os.ReadDir(filepath.Join(staging, avatarsName))
+	if len(entries) > 0 && paths.Avatars != "" {
+		for _, entry := range entries {
+			target := filepath.Join(paths.Avatars, entry.Name())
+			path, err := stageFile(filepath.Join(staging, avatarsName, entry.Name()), target, 0755, 0644)
+			if err != nil {
+				return nil, fmt.Errorf("failed to restore avatar: %w", err)
+			}
+			avatars = append(avatars, stagedFile{path: path, target: target})
+		}
+	}
+	if paths.HostKey != "" {
+		for _, ext := range []string{"", ".pub"} {
+			src := filepath.Join(staging, filepath.FromSlash(hostKeyName)+ext)
+			if _, err := os.Stat(src); err != nil {
+				continue
+			}
+			path, err := stageFile(src, paths.HostKey+ext, 0700, 0600)
+			if err != nil {
+				return nil, fmt.Errorf("failed to restore host key: %w", err)
+			}
+			hostKeys = append(hostKeys, stagedFile{path: path, target: paths.HostKey + ext})
+		}
+	}
+
+	restored := &Restored{Manifest: *manifest}
+	suffix := ".pre-restore-" + now.Format(archiveTime)
+
+	// The -wal and -shm files belong to the replaced database and would corrupt the restored one
+	for _, path := range []string{paths.Database, paths.Database + "-wal", paths.Database + "-shm"} {
+		moved, err := moveAside(path, suffix)
+		if err != nil {
+			return nil, err
+		}
+		if moved != "" {
+			restored.Previous = append(restored.Previous, moved)
+		}
+	}
+	if err := os.Rename(filepath.Join(staging, databaseName), paths.Database); err != nil {
+		return nil, fmt.Errorf("failed to restore database: %w", err)
+	}
+
+	for _, f := range hostKeys {
+		moved, err := moveAside(f.target, suffix)
+		if err != nil {
+			return nil, err
+		}
+		if moved != "" {
+			restored.Previous = append(restored.Previous, moved)
+		}
+		if err := os.Rename(f.path, f.target); err != nil {
+			return nil, fmt.Errorf("failed to restore host key: %w", err)
+		}
+		restored.HostKey = true
+	}
+	for _, f := range avatars {
+		if err := os.Rename(f.path, f.target); err != nil {
+			return nil, fmt.Errorf("failed to restore avatar: %w", err)
+		}
+		restored.Avatars++
+	}
+
+	return restored, nil
+}
+
+// stagedFile is a copy of a restored file waiting to be renamed to target
+type stagedFile struct {
+	path   string
+	target string
+}
+
+// stageFile copies src to a temporary file in the directory of target, so that putting
+// it in place is a rename, and returns the temporary file's path
+func stageFile(src, target string, dirPerm, perm os.FileMode) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(target), dirPerm); err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".restore-")
+	if err != nil {
+		return "", err
+	}
+	tmp.Close()
+	if err := copyFile(src, tmp.Name(), perm); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// extract unpacks the known archive entries into dir and returns the manifest.
+// Unknown entries are rejected, which also keeps names like "../x" out of dir.
+func extract(archive, dir string) (*Manifest, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	var manifest *Manifest
+	hasDatabase := false
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		name := header.Name
+		switch {
+		case name == manifestName:
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("invalid manifest: %w", err)
+			}
+			continue
+		case name == databaseName:
+			hasDatabase = true
+		case name == hostKeyName || name == hostKeyName+".pub":
+		case strings.HasPrefix(name, avatarsName+"/") && isPlainName(strings.TrimPrefix(name, avatarsName+"/")):
+		default:
+			return nil, fmt.Errorf("unexpected file in backup archive: %s", name)
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("unexpected file type in backup archive: %s", name)
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil, err
+		}
+		out, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, err
+		}
+		_, err = io.Copy(out, tr)
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract %s: %w", name, err)
+		}
+	}
+
+	if manifest == nil || !hasDatabase {
+		return nil, fmt.Errorf("not a stegodon backup: manifest or database missing")
+	}
+	return manifest, nil
+}
+
+// isPlainName reports whether name is a single path element
+func isPlainName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// validateDatabase checks the database in a backup before it replaces the current one
+func validateDatabase(path string) error {
+	database, err := db.Open(path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	if err := database.CheckIntegrity(); err != nil {
+		return fmt.Errorf("backup database is damaged: %w", err)
+	}
+	version, err := database.ReadSchemaVersion()
+	if err != nil {
+		return fmt.Errorf("failed to read backup schema version: %w", err)
+	}
+	if version > db.SchemaVersion {
+		return fmt.Errorf("backup has schema version %d, this stegodon supports up to %d; upgrade stegodon first", version, db.SchemaVersion)
+	}
+	return nil
+}
+
+// moveAside renames path by appending suffix and returns the new path, or "" if path does not exist
+func moveAside(path, suffix string) (string, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", nil
+	}
+	if err := os.Rename(path, path+suffix); err != nil {
+		return "", fmt.Errorf("failed to keep %s: %w", path, err)
+	}
+	return path + suffix, nil
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
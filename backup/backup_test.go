@@ -0,0 +1,289 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/db"
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite"
+)
+
+// setupServerFiles creates a migrated database with one account, an avatar and a host key
+func setupServerFiles(t *testing.T) (Paths, *db.DB) {
+	t.Helper()
+	dir := t.TempDir()
+	paths := Paths{
+		Database: filepath.Join(dir, "database.db"),
+		Avatars:  filepath.Join(dir, "avatars"),
+		HostKey:  filepath.Join(dir, ".ssh", "stegodonhostkey"),
+	}
+	writeFile(t, paths.Database, "")
+
+	database, err := db.Open(paths.Database)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if err := database.CreateDB(); err != nil {
+		t.Fatalf("CreateDB failed: %v", err)
+	}
+	if err := database.RunMigrations(); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+	if err := database.CreateAccountWithPublicKey("alice", "ssh-ed25519 AAAAalice"); err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+
+	writeFile(t, filepath.Join(paths.Avatars, uuid.NewString()+".png"), "avatar")
+	writeFile(t, paths.HostKey, "host key")
+	writeFile(t, paths.HostKey+".pub", "host key pub")
+	return paths, database
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func archiveNames(t *testing.T, archive string) []string {
+	t.Helper()
+	f, err := os.Open(archive)
+	if err != nil {
+		t.Fatalf("Failed to open archive: %v", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+	var names []string
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, header.Name)
+	}
+	return names
+}
+
+func TestCreate(t *testing.T) {
+	paths, database := setupServerFiles(t)
+	dir := filepath.Join(t.TempDir(), "backups")
+
+	archive, err := Create(database, paths, dir, 7, time.Date(2025, 6, 1, 3, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if filepath.Base(archive) != "stegodon-backup-20250601-030000.tar.gz" {
+		t.Errorf("Unexpected archive name %s", archive)
+	}
+
+	names := strings.Join(archiveNames(t, archive), ",")
+	for _, want := range []string{"manifest.json", "database.db", "avatars/", ".ssh/stegodonhostkey", ".ssh/stegodonhostkey.pub"} {
+		if !strings.Contains(names, want) {
+			t.Errorf("Expected %s in the archive, got %s", want, names)
+		}
+	}
+
+	// Only the archive is left behind
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only the archive in the backup directory, got %v", entries)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"stegodon-backup-20250101-000000.tar.gz",
+		"stegodon-backup-20250102-000000.tar.gz",
+		"stegodon-backup-20250103-000000.tar.gz",
+		"notes.txt",
+	} {
+		writeFile(t, filepath.Join(dir, name), "x")
+	}
+
+	removed, err := Rotate(dir, 2)
+	if err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	if len(removed) != 1 || filepath.Base(removed[0]) != "stegodon-backup-20250101-000000.tar.gz" {
+		t.Errorf("Expected the oldest archive to be removed, got %v", removed)
+	}
+	archives, _ := List(dir)
+	if len(archives) != 2 {
+		t.Errorf("Expected 2 archives left, got %v", archives)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Error("Expected other files to be left alone")
+	}
+}
+
+func TestRestore(t *testing.T) {
+	paths, database := setupServerFiles(t)
+	archive, err := Create(database, paths, t.TempDir(), 7, time.Now())
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// Changes after the backup are rolled back by the restore
+	if err := database.CreateAccountWithPublicKey("bob", "ssh-ed25519 AAAAbob"); err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	database.Close()
+	writeFile(t, paths.HostKey, "new host key")
+
+	restored, err := Restore(archive, paths, time.Now())
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if restored.Manifest.SchemaVersion != db.SchemaVersion || restored.Avatars != 1 || !restored.HostKey {
+		t.Errorf("Unexpected restore result %+v", restored)
+	}
+
+	reopened, err := db.Open(paths.Database)
+	if err != nil {
+		t.Fatalf("Failed to open restored database: %v", err)
+	}
+	defer reopened.Close()
+	if err, _ := reopened.ReadAccByUsername("alice"); err != nil {
+		t.Errorf("Expected alice in the restored database: %v", err)
+	}
+	if err, _ := reopened.ReadAccByUsername("bob"); err == nil {
+		t.Error("Expected bob to be gone after the restore")
+	}
+
+	key, _ := os.ReadFile(paths.HostKey)
+	if string(key) != "host key" {
+		t.Errorf("Expected the host key from the backup, got %q", key)
+	}
+	for _, previous := range restored.Previous {
+		if _, err := os.Stat(previous); err != nil {
+			t.Errorf("Expected the replaced file at %s", previous)
+		}
+	}
+	if len(restored.Previous) < 2 {
+		t.Errorf("Expected the database and host key to be kept, got %v", restored.Previous)
+	}
+}
+
+func TestRestore_RefusesRunningServer(t *testing.T) {
+	paths, database := setupServerFiles(t)
+	archive, err := Create(database, paths, t.TempDir(), 7, time.Now())
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	database.Close()
+
+	// A running server keeps the database open in WAL mode
+	server, err := sql.Open("sqlite", paths.Database+"?_pragma=journal_mode(WAL)")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer server.Close()
+	var accounts int
+	if err := server.QueryRow(`SELECT COUNT(*) FROM accounts`).Scan(&accounts); err != nil {
+		t.Fatalf("Failed to read database: %v", err)
+	}
+
+	if _, err := Restore(archive, paths, time.Now()); err == nil || !strings.Contains(err.Error(), "stop the server first") {
+		t.Fatalf("Expected the restore to be refused, got %v", err)
+	}
+	if _, err := os.Stat(paths.Database); err != nil {
+		t.Errorf("Expected the database to be left in place: %v", err)
+	}
+}
+
+func TestRestore_FailureLeavesFilesAlone(t *testing.T) {
+	paths, database := setupServerFiles(t)
+	archive, err := Create(database, paths, t.TempDir(), 7, time.Now())
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	database.Close()
+
+	// The host key cannot be written, which is only noticed after the database was staged
+	paths.HostKey = filepath.Join(paths.HostKey, "stegodonhostkey")
+
+	before, _ := os.ReadFile(paths.Database)
+	if _, err := Restore(archive, paths, time.Now()); err == nil || !strings.Contains(err.Error(), "failed to restore host key") {
+		t.Fatalf("Expected the host key to fail, got %v", err)
+	}
+	after, _ := os.ReadFile(paths.Database)
+	if string(before) != string(after) {
+		t.Error("Expected the database to be left alone")
+	}
+	entries, _ := os.ReadDir(paths.Avatars)
+	if len(entries) != 1 {
+		t.Errorf("Expected no staged avatars to be left behind, got %v", entries)
+	}
+}
+
+func TestRestore_RejectsNewerSchema(t *testing.T) {
+	paths, database := setupServerFiles(t)
+	if err := database.CreateDB(); err != nil {
+		t.Fatalf("CreateDB failed: %v", err)
+	}
+	database.Close()
+
+	sqlDB, err := sql.Open("sqlite", paths.Database)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	sqlDB.Exec("PRAGMA user_version = 9999")
+	sqlDB.Close()
+
+	newer, err := db.Open(paths.Database)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	archive, err := Create(newer, paths, t.TempDir(), 7, time.Now())
+	newer.Close()
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	before, _ := os.ReadFile(paths.Database)
+	if _, err := Restore(archive, paths, time.Now()); err == nil || !strings.Contains(err.Error(), "schema version 9999") {
+		t.Fatalf("Expected the newer schema to be rejected, got %v", err)
+	}
+	after, _ := os.ReadFile(paths.Database)
+	if string(before) != string(after) {
+		t.Error("Expected the database to be left alone")
+	}
+}
+
+func TestRestore_RejectsUnknownEntries(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "evil.tar.gz")
+	f, _ := os.Create(archive)
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "../escape", Mode: 0600, Size: 1})
+	tw.Write([]byte("x"))
+	tw.Close()
+	gz.Close()
+	f.Close()
+
+	paths := Paths{Database: filepath.Join(dir, "database.db")}
+	if _, err := Restore(archive, paths, time.Now()); err == nil || !strings.Contains(err.Error(), "unexpected file") {
+		t.Errorf("Expected the archive to be rejected, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escape")); err == nil {
+		t.Error("Expected nothing to be written outside the staging directory")
+	}
+}
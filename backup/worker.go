@@ -0,0 +1,51 @@
+package backup
+
+import (
+	"log"
+	"time"
+
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/util"
+)
+
+// StartWorker starts a background worker that writes a backup every backupHours.
+// Returns a stop function that can be called to gracefully stop the worker.
+func StartWorker(conf *util.AppConfig) func() {
+	log.Printf("Starting backup worker (every %d hours)...", conf.Conf.BackupHours)
+
+	ticker := time.NewTicker(time.Duration(conf.Conf.BackupHours) * time.Hour)
+	stop := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				Run(conf)
+			case <-stop:
+				ticker.Stop()
+				log.Println("Backup worker stopped")
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+	}
+}
+
+// Run writes a backup of the running server to the configured directory
+func Run(conf *util.AppConfig) (string, error) {
+	dir, err := Dir(conf)
+	if err != nil {
+		log.Printf("Backup: Failed to resolve backup directory: %v", err)
+		return "", err
+	}
+	archive, err := Create(db.GetDB(), DefaultPaths(), dir, conf.Conf.BackupKeep, time.Now())
+	if err != nil {
+		log.Printf("Backup: Failed: %v", err)
+		return "", err
+	}
+	log.Printf("Backup: Wrote %s", archive)
+	return archive, nil
+}
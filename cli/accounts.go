@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/deemkeen/stegodon/actions"
+)
+
+const (
+	// maxDisplayNameLength and maxBioLength are the limits of the TUI's account settings
+	maxDisplayNameLength = 50
+	maxBioLength         = 200
+)
+
+// handleFollow follows or unfollows a local or remote account.
+// Following an account again, or unfollowing one that is not followed, is not an error.
+func (h *Handler) handleFollow(action string, args []string) error {
+	if len(args) != 1 || strings.TrimSpace(args[0]) == "" {
+		err := fmt.Errorf("usage: %s <@user|@user@domain|profile-url>", action)
+		h.output.Error(err)
+		return err
+	}
+
+	handle := args[0]
+	pending := false
+	var err error
+	if action == "follow" {
+		pending, err = h.db.Follow(h.account, handle)
+	} else {
+		err = h.db.Unfollow(h.account, handle)
+	}
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	if h.output.IsJSON() {
+		h.output.JSON(FollowResponse{
+			Status:    "ok",
+			Account:   handle,
+			Following: action == "follow",
+			Pending:   pending,
+		})
+	} else if action == "unfollow" {
+		h.output.Success("Unfollowed %s\n", handle)
+	} else if pending {
+		h.output.Success("Sent follow request to %s\n", handle)
+	} else {
+		h.output.Success("Following %s\n", handle)
+	}
+	return nil
+}
+
+// handleFollowList lists the user's followers or the accounts the user follows
+func (h *Handler) handleFollowList(list string, args []string) error {
+	if len(args) > 0 {
+		err := fmt.Errorf("usage: %s", list)
+		h.output.Error(err)
+		return err
+	}
+
+	err, profiles := h.db.ReadFollowProfiles(h.account.Id, list == "followers")
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	accounts := make([]AccountItem, 0, len(*profiles))
+	for _, p := range *profiles {
+		accounts = append(accounts, AccountItem{
+			ID:          p.Id.String(),
+			Handle:      p.Handle(),
+			DisplayName: p.DisplayName,
+			URL:         p.URL,
+		})
+	}
+
+	if h.output.IsJSON() {
+		h.output.JSON(AccountsResponse{Accounts: accounts, Count: len(accounts)})
+		return nil
+	}
+	if len(accounts) == 0 {
+		if list == "followers" {
+			h.output.Println("No followers yet.")
+		} else {
+			h.output.Println("Not following anyone yet.")
+		}
+		return nil
+	}
+	for _, a := range accounts {
+		if a.DisplayName != "" {
+			h.output.Print("%s (%s)\n", a.Handle, a.DisplayName)
+		} else {
+			h.output.Print("%s\n", a.Handle)
+		}
+	}
+	return nil
+}
+
+// handleProfile shows the profile of an account, or the user's own without arguments
+func (h *Handler) handleProfile(args []string) error {
+	if len(args) > 1 {
+		err := fmt.Errorf("usage: profile [@user|@user@domain|profile-url]")
+		h.output.Error(err)
+		return err
+	}
+
+	handle := ""
+	if len(args) == 1 {
+		handle = args[0]
+	}
+	err, profile := h.db.ReadProfile(h.account, handle)
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	resp := toProfileResponse(profile)
+	if h.output.IsJSON() {
+		h.output.JSON(resp)
+		return nil
+	}
+
+	if resp.DisplayName != "" {
+		h.output.Print("%s (%s)\n", resp.DisplayName, resp.Handle)
+	} else {
+		h.output.Print("%s\n", resp.Handle)
+	}
+	if resp.Bio != "" {
+		h.output.Print("%s\n", resp.Bio)
+	}
+	if resp.URL != "" {
+		h.output.Print("%s\n", resp.URL)
+	}
+	if profile.Local {
+		h.output.Print("%d posts, %d followers, %d following\n", profile.Posts, profile.Followers, profile.Following)
+	}
+	var relation []string
+	if resp.IsFollowing {
+		relation = append(relation, "you follow them")
+	} else if resp.Requested {
+		relation = append(relation, "follow request pending")
+	}
+	if resp.FollowsYou {
+		relation = append(relation, "follows you")
+	}
+	if len(relation) > 0 {
+		h.output.Print("%s\n", strings.Join(relation, ", "))
+	}
+	return nil
+}
+
+// toProfileResponse converts a profile for output
+func toProfileResponse(profile *actions.Profile) ProfileResponse {
+	resp := ProfileResponse{
+		ID:          profile.Id.String(),
+		Handle:      profile.Handle(),
+		DisplayName: profile.DisplayName,
+		Bio:         profile.Summary,
+		URL:         profile.URL,
+		IsFollowing: profile.IsFollowing,
+		Requested:   profile.Requested,
+		FollowsYou:  profile.FollowsYou,
+	}
+	if profile.Local {
+		resp.CreatedAt = &profile.CreatedAt
+		resp.Followers = &profile.Followers
+		resp.Following = &profile.Following
+		resp.Posts = &profile.Posts
+	}
+	return resp
+}
+
+// handleWhoami shows the account the SSH key logs in as: the fediverse handle in text
+// mode, the full profile in JSON mode
+func (h *Handler) handleWhoami(args []string) error {
+	if len(args) > 0 {
+		err := fmt.Errorf("usage: whoami")
+		h.output.Error(err)
+		return err
+	}
+
+	if !h.output.IsJSON() {
+		handle := "@" + h.account.Username
+		if domain := h.conf.Conf.SslDomain; domain != "" {
+			handle += "@" + domain
+		}
+		h.output.Println(handle)
+		return nil
+	}
+
+	err, profile := h.db.ReadProfile(h.account, "")
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+	h.output.JSON(toProfileResponse(profile))
+	return nil
+}
+
+// handleSetProfile changes the user's bio (set-bio) or display name (set-name).
+// An empty value given as "" clears it.
+func (h *Handler) handleSetProfile(cmd string, args []string) error {
+	field, label, limit := "bio", "Bio", maxBioLength
+	if cmd == "set-name" {
+		field, label, limit = "display_name", "Display name", maxDisplayNameLength
+	}
+	if len(args) == 0 {
+		err := fmt.Errorf("usage: %s <text|->", cmd)
+		h.output.Error(err)
+		return err
+	}
+
+	value, err := h.readText(args)
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+	value = strings.TrimSpace(value)
+	if n := utf8.RuneCountInString(value); n > limit {
+		err := fmt.Errorf("%s too long (%d chars, max %d)", strings.ToLower(label), n, limit)
+		h.output.Error(err)
+		return err
+	}
+
+	if field == "bio" {
+		err = h.db.UpdateAccountSummary(h.account.Id, value)
+	} else {
+		err = h.db.UpdateAccountDisplayName(h.account.Id, value)
+	}
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	if h.output.IsJSON() {
+		h.output.JSON(ProfileUpdateResponse{Status: "ok", Field: field, Value: value})
+	} else {
+		h.output.Success("%s updated.\n", label)
+	}
+	return nil
+}
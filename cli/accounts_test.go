@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/actions"
+	"github.com/google/uuid"
+)
+
+func TestFollow_RemotePending(t *testing.T) {
+	db := &mockDatabase{followPending: true}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"follow", "@bob@remote.example", "-j"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(db.actions) != 1 || db.actions[0] != "follow @bob@remote.example" {
+		t.Errorf("Expected a follow, got: %v", db.actions)
+	}
+	var resp FollowResponse
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if !resp.Following || !resp.Pending || resp.Account != "@bob@remote.example" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestUnfollow(t *testing.T) {
+	db := &mockDatabase{}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"unfollow", "@alice"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(db.actions) != 1 || db.actions[0] != "unfollow @alice" {
+		t.Errorf("Expected an unfollow, got: %v", db.actions)
+	}
+	if !strings.Contains(output.String(), "Unfollowed @alice") {
+		t.Errorf("Expected confirmation, got: %s", output.String())
+	}
+}
+
+func TestFollowers(t *testing.T) {
+	db := &mockDatabase{followProfiles: map[bool][]actions.Profile{
+		true: {
+			{Id: uuid.New(), Username: "alice", DisplayName: "Alice", Local: true},
+			{Id: uuid.New(), Username: "bob", Domain: "remote.example"},
+		},
+	}}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"followers"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	result := output.String()
+	if !strings.Contains(result, "@alice (Alice)") || !strings.Contains(result, "@bob@remote.example") {
+		t.Errorf("Expected both followers, got: %s", result)
+	}
+}
+
+func TestFollowing_EmptyJSON(t *testing.T) {
+	handler, output := newTestHandlerWithDB("", &mockDatabase{})
+
+	if err := handler.Execute([]string{"following", "-j"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var resp AccountsResponse
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if resp.Accounts == nil || resp.Count != 0 {
+		t.Errorf("Expected an empty list, got: %s", output.String())
+	}
+}
+
+func TestProfile_Remote(t *testing.T) {
+	db := &mockDatabase{profiles: map[string]*actions.Profile{
+		"@bob@remote.example": {
+			Id:          uuid.New(),
+			Username:    "bob",
+			Domain:      "remote.example",
+			DisplayName: "Bob",
+			Summary:     "Hello from Bob",
+			IsFollowing: true,
+			FollowsYou:  true,
+		},
+	}}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"profile", "@bob@remote.example"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	result := output.String()
+	if !strings.Contains(result, "Bob (@bob@remote.example)") || !strings.Contains(result, "Hello from Bob") {
+		t.Errorf("Expected name and bio, got: %s", result)
+	}
+	if !strings.Contains(result, "you follow them, follows you") {
+		t.Errorf("Expected the relationship, got: %s", result)
+	}
+	if strings.Contains(result, "posts") {
+		t.Errorf("Expected no counts for a remote account, got: %s", result)
+	}
+}
+
+func TestWhoami(t *testing.T) {
+	handler, output := newTestHandlerWithDB("", &mockDatabase{})
+	handler.conf.Conf.SslDomain = "stegodon.example"
+
+	if err := handler.Execute([]string{"whoami"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if strings.TrimSpace(output.String()) != "@testuser@stegodon.example" {
+		t.Errorf("Expected the handle, got: %q", output.String())
+	}
+}
+
+func TestWhoami_JSONIncludesCounts(t *testing.T) {
+	db := &mockDatabase{profiles: map[string]*actions.Profile{
+		"": {Id: uuid.New(), Username: "testuser", Local: true, Followers: 0, Posts: 3, CreatedAt: time.Now()},
+	}}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"whoami", "--json"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var resp ProfileResponse
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if resp.Handle != "@testuser" || resp.Posts == nil || *resp.Posts != 3 || resp.Followers == nil || *resp.Followers != 0 {
+		t.Errorf("Unexpected response: %s", output.String())
+	}
+}
+
+func TestSetName(t *testing.T) {
+	db := &mockDatabase{}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"set-name", "Test", "User"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if db.displayName != "Test User" {
+		t.Errorf("Expected the display name to be set, got: %q", db.displayName)
+	}
+	if !strings.Contains(output.String(), "Display name updated.") {
+		t.Errorf("Expected confirmation, got: %s", output.String())
+	}
+}
+
+func TestSetName_TooLong(t *testing.T) {
+	db := &mockDatabase{}
+	handler, _ := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"set-name", strings.Repeat("n", 51)}); err == nil {
+		t.Fatal("Expected an error for a name over 50 characters")
+	}
+	if db.displayName != "" {
+		t.Errorf("Expected the name to be unchanged, got: %q", db.displayName)
+	}
+}
+
+func TestSetBio_FromStdinJSON(t *testing.T) {
+	db := &mockDatabase{}
+	handler, output := newTestHandlerWithDB("  Writing about Go.\n", db)
+
+	if err := handler.Execute([]string{"set-bio", "-", "-j"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var resp ProfileUpdateResponse
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if db.summary != "Writing about Go." || resp.Field != "bio" || resp.Value != db.summary {
+		t.Errorf("Unexpected result: bio %q, response %+v", db.summary, resp)
+	}
+}
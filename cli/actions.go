@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+// handleReply posts a reply to a local or remote post, given by id or ActivityPub URI
+func (h *Handler) handleReply(args []string) error {
+	if len(args) < 2 {
+		err := fmt.Errorf("usage: reply <id|uri> <message|->")
+		h.output.Error(err)
+		return err
+	}
+
+	ref := args[0]
+	message, err := h.readMessage(args[1:])
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+	if err := h.validateNote(message); err != nil {
+		return err
+	}
+
+	noteId, err := h.db.ReplyToPost(h.account, ref, message)
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	if h.output.IsJSON() {
+		h.output.JSON(PostResponse{
+			ID:        fmt.Sprintf("%v", noteId),
+			InReplyTo: ref,
+			Message:   message,
+			CreatedAt: time.Now(),
+		})
+	} else {
+		h.output.Success("Replied: %v\n", noteId)
+	}
+	return nil
+}
+
+// postActionDone is the text output of the actions on a post
+var postActionDone = map[string]string{
+	"like":    "Liked",
+	"unlike":  "Unliked",
+	"boost":   "Boosted",
+	"unboost": "Unboosted",
+	"edit":    "Edited",
+	"delete":  "Deleted",
+}
+
+// handlePostAction likes, unlikes, boosts or unboosts a post. Repeating an action is not an error.
+func (h *Handler) handlePostAction(action string, args []string) error {
+	if len(args) != 1 || strings.TrimSpace(args[0]) == "" {
+		err := fmt.Errorf("usage: %s <id|uri>", action)
+		h.output.Error(err)
+		return err
+	}
+
+	ref := args[0]
+	var err error
+	switch action {
+	case "like", "unlike":
+		err = h.db.SetLike(h.account, ref, action == "like")
+	default:
+		err = h.db.SetBoost(h.account, ref, action == "boost")
+	}
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	h.writePostAction(action, ref)
+	return nil
+}
+
+// writePostAction outputs the result of an action on a post
+func (h *Handler) writePostAction(action, ref string) {
+	if h.output.IsJSON() {
+		h.output.JSON(PostActionResponse{
+			Status: "ok",
+			Action: action,
+			Post:   ref,
+			DoneAt: time.Now(),
+		})
+	} else {
+		h.output.Success("%s: %s\n", postActionDone[action], ref)
+	}
+}
+
+// parseNoteId parses the id of one of the user's notes for edit and delete
+func parseNoteId(arg string) (uuid.UUID, error) {
+	noteId, err := uuid.Parse(strings.TrimSpace(arg))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid post id: %s", arg)
+	}
+	return noteId, nil
+}
+
+// handleEdit replaces the text of one of the user's notes, or the body of an article
+func (h *Handler) handleEdit(args []string) error {
+	if len(args) < 2 {
+		err := fmt.Errorf("usage: edit <id> <message|->")
+		h.output.Error(err)
+		return err
+	}
+
+	noteId, err := parseNoteId(args[0])
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+	message, err := h.readMessage(args[1:])
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	err, note := h.db.ReadNoteIdWithReplyInfo(noteId)
+	if err != nil || note == nil {
+		err = fmt.Errorf("post not found: %s", noteId)
+		h.output.Error(err)
+		return err
+	}
+	if note.CreatedBy != h.account.Username {
+		err := fmt.Errorf("you can only change your own posts")
+		h.output.Error(err)
+		return err
+	}
+
+	// Articles keep their title; only the markdown body is replaced
+	if note.Title != "" {
+		if err := util.ValidateArticle(note.Title, message); err != nil {
+			h.output.Error(err)
+			return err
+		}
+	} else if err := h.validateNote(message); err != nil {
+		return err
+	}
+
+	if err := h.db.EditNote(h.account, noteId, message); err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	h.writePostAction("edit", noteId.String())
+	return nil
+}
+
+// handleDelete deletes one of the user's notes
+func (h *Handler) handleDelete(args []string) error {
+	if len(args) != 1 {
+		err := fmt.Errorf("usage: delete <id>")
+		h.output.Error(err)
+		return err
+	}
+
+	noteId, err := parseNoteId(args[0])
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+	if err := h.db.DeleteNote(h.account, noteId); err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	h.writePostAction("delete", noteId.String())
+	return nil
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+func TestReply_FromStdin(t *testing.T) {
+	db := &mockDatabase{}
+	handler, output := newTestHandlerWithDB("Thanks for sharing\n", db)
+	uri := "https://remote.example/users/bob/statuses/1"
+
+	if err := handler.Execute([]string{"reply", uri, "-", "--json"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(db.replies) != 1 || db.replies[0].InReplyToURI != uri || db.replies[0].Message != "Thanks for sharing" {
+		t.Fatalf("Expected a reply to %s, got: %+v", uri, db.replies)
+	}
+	var resp PostResponse
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if resp.InReplyTo != uri || resp.ID != db.replies[0].Id.String() {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestReply_TooLong(t *testing.T) {
+	db := &mockDatabase{}
+	handler, _ := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"reply", uuid.New().String(), strings.Repeat("a", 151)}); err == nil {
+		t.Fatal("Expected an error for a reply over the limit")
+	}
+	if len(db.actions) != 0 {
+		t.Errorf("Expected no reply, got: %v", db.actions)
+	}
+}
+
+func TestReply_MissingMessage(t *testing.T) {
+	handler, output := newTestHandlerWithDB("", &mockDatabase{})
+
+	if err := handler.Execute([]string{"reply", uuid.New().String()}); err == nil {
+		t.Fatal("Expected an error without a message")
+	}
+	if !strings.Contains(output.String(), "usage: reply <id|uri> <message|->") {
+		t.Errorf("Expected usage message, got: %s", output.String())
+	}
+}
+
+func TestPostActions(t *testing.T) {
+	ref := uuid.New().String()
+	tests := []struct {
+		action string
+		want   string
+	}{
+		{"like", "Liked: " + ref},
+		{"unlike", "Unliked: " + ref},
+		{"boost", "Boosted: " + ref},
+		{"unboost", "Unboosted: " + ref},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			db := &mockDatabase{}
+			handler, output := newTestHandlerWithDB("", db)
+
+			if err := handler.Execute([]string{tt.action, ref}); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if len(db.actions) != 1 || db.actions[0] != tt.action+" "+ref {
+				t.Errorf("Expected %s to be performed, got: %v", tt.action, db.actions)
+			}
+			if !strings.Contains(output.String(), tt.want) {
+				t.Errorf("Expected %q, got: %s", tt.want, output.String())
+			}
+		})
+	}
+}
+
+func TestPostAction_ErrorJSON(t *testing.T) {
+	db := &mockDatabase{actionError: errors.New("post not found: x")}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"like", "x", "-j"}); err == nil {
+		t.Fatal("Expected an error")
+	}
+	var resp map[string]string
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if resp["error"] != "post not found: x" {
+		t.Errorf("Expected the error in JSON, got: %v", resp)
+	}
+}
+
+func TestEdit(t *testing.T) {
+	db := &mockDatabase{}
+	handler, output := newTestHandlerWithDB("", db)
+	noteId := uuid.New()
+
+	if err := handler.Execute([]string{"edit", noteId.String(), "Fixed", "typo", "-j"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(db.actions) != 1 || db.actions[0] != "edit "+noteId.String()+" Fixed typo" {
+		t.Errorf("Expected the note to be edited, got: %v", db.actions)
+	}
+	var resp PostActionResponse
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if resp.Action != "edit" || resp.Post != noteId.String() {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestEdit_OtherUsersNote(t *testing.T) {
+	db := &mockDatabase{note: &domain.Note{Id: uuid.New(), CreatedBy: "alice", Message: "hello"}}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"edit", db.note.Id.String(), "hijacked"}); err == nil {
+		t.Fatal("Expected an error for another user's note")
+	}
+	if !strings.Contains(output.String(), "only change your own posts") || len(db.actions) != 0 {
+		t.Errorf("Expected the edit to be refused, got: %s", output.String())
+	}
+}
+
+func TestEdit_ArticleUsesArticleLimits(t *testing.T) {
+	db := &mockDatabase{note: &domain.Note{Id: uuid.New(), CreatedBy: "testuser", Title: "My Article"}}
+	handler, _ := newTestHandlerWithDB("", db)
+	body := strings.Repeat("Long form text. ", 50)
+
+	if err := handler.Execute([]string{"edit", db.note.Id.String(), body}); err != nil {
+		t.Fatalf("Expected an article body over the note limit to be accepted, got: %v", err)
+	}
+}
+
+func TestDelete_InvalidId(t *testing.T) {
+	db := &mockDatabase{}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"delete", "not-an-id"}); err == nil {
+		t.Fatal("Expected an error for an invalid id")
+	}
+	if !strings.Contains(output.String(), "invalid post id") || len(db.actions) != 0 {
+		t.Errorf("Expected nothing to be deleted, got: %s", output.String())
+	}
+}
+
+func TestDelete(t *testing.T) {
+	db := &mockDatabase{}
+	handler, output := newTestHandlerWithDB("", db)
+	noteId := uuid.New()
+
+	if err := handler.Execute([]string{"delete", noteId.String()}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(db.actions) != 1 || db.actions[0] != "delete "+noteId.String() {
+		t.Errorf("Expected the note to be deleted, got: %v", db.actions)
+	}
+	if !strings.Contains(output.String(), "Deleted: "+noteId.String()) {
+		t.Errorf("Expected confirmation, got: %s", output.String())
+	}
+}
@@ -0,0 +1,589 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// AdminDatabase is the database interface for the offline admin commands
+type AdminDatabase interface {
+	ReadSchemaStatus() (error, *domain.SchemaStatus)
+	CreateDB() error
+	RunMigrations() error
+	MigrateDown(version int) error
+	ReadAllAccountsAdmin() (error, *[]domain.Account)
+	ReadAccByUsername(username string) (error, *domain.Account)
+	CreateAccountWithPublicKey(username string, publicKey string) error
+	UpdateAccountAdmin(accountId uuid.UUID, isAdmin bool) error
+	UpdateAccountPublicKey(accountId uuid.UUID, publicKey string) error
+	CreateBan(id, username, ipAddress, publicKeyHash, reason string) error
+	BanAccount(accountId uuid.UUID) error
+	UnbanAccount(accountId uuid.UUID) error
+	DeleteBan(id string) error
+	DeleteAccount(accountId uuid.UUID) error
+	ReadAllRelays() (error, *[]domain.Relay)
+	DeleteRelay(id uuid.UUID) error
+	ReadAllDeliveries(limit int) (error, *[]domain.DeliveryQueueItem)
+	CountInboxQueue() (pending int, held int, err error)
+	PurgeDeliveryQueue() (int64, error)
+}
+
+// AdminHandler runs the offline admin subcommands of the stegodon binary
+// ("stegodon admin ...") directly against the database, for when the server
+// is down or an admin has locked themselves out
+type AdminHandler struct {
+	input  io.Reader
+	db     AdminDatabase
+	output *Output
+}
+
+// adminUsage lists the admin subcommands
+const adminUsage = `usage: stegodon admin <command> [--json]
+
+  user list                          List all accounts
+  user create <username> <key|->     Create an account for an SSH public key
+  user set-admin <username> [--off]  Grant (or revoke) admin rights
+  user reset-key <username> <key|->  Replace the SSH public key of an account
+  user ban <username> [--reason <text>]
+  user unban <username>
+  user delete <username> --yes       Delete an account and its posts
+  relay list                         List relay subscriptions
+  relay remove <id|actor-uri>        Remove a relay subscription
+  queue list [-n <count>]            Show queued deliveries
+  queue purge --yes                  Drop all queued deliveries
+  migrate [--status|--dry-run]       Apply (or only show) schema migrations
+  migrate --down <version> --yes     Revert the migrations above a version
+
+Keys are in authorized_keys format ("ssh-ed25519 AAAA... comment"); "-" reads the key from stdin.`
+
+// NewAdminHandler creates a handler for the admin subcommands
+func NewAdminHandler(input io.Reader, w io.Writer, db AdminDatabase, jsonMode bool) *AdminHandler {
+	return &AdminHandler{
+		input:  input,
+		db:     db,
+		output: NewOutput(w, jsonMode),
+	}
+}
+
+// ParseAdminArgs extracts the global --json flag from the admin arguments
+func ParseAdminArgs(args []string) ([]string, bool) {
+	return parseGlobalFlags(args)
+}
+
+// Execute parses and executes an admin command
+func (h *AdminHandler) Execute(args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "--help" || args[0] == "-h" {
+		h.output.Println(adminUsage)
+		return nil
+	}
+
+	if args[0] == "migrate" {
+		return h.handleMigrate(args[1:])
+	}
+
+	// Everything else relies on the current schema
+	if err := h.checkSchema(); err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	var err error
+	switch args[0] {
+	case "user":
+		err = h.handleUser(args[1:])
+	case "relay":
+		err = h.handleRelay(args[1:])
+	case "queue":
+		err = h.handleQueue(args[1:])
+	default:
+		err = fmt.Errorf("unknown admin command: %s", args[0])
+	}
+	if err != nil {
+		h.output.Error(err)
+	}
+	return err
+}
+
+// checkSchema refuses to work on a database the migrations have not been applied to
+func (h *AdminHandler) checkSchema() error {
+	err, status := h.db.ReadSchemaStatus()
+	if err != nil {
+		return err
+	}
+	if !status.UpToDate() {
+		return fmt.Errorf("database schema is out of date (%d missing tables, %d missing columns, %d pending migrations), run 'stegodon admin migrate' first",
+			len(status.MissingTables), len(status.MissingColumns), len(status.Pending()))
+	}
+	return nil
+}
+
+func (h *AdminHandler) handleMigrate(args []string) error {
+	apply := true
+	if len(args) > 0 {
+		switch args[0] {
+		case "--status", "--dry-run":
+			apply = false
+		case "--down":
+			return h.handleMigrateDown(args[1:])
+		default:
+			err := fmt.Errorf("unknown migrate option: %s", args[0])
+			h.output.Error(err)
+			return err
+		}
+	}
+
+	var applied []domain.Migration
+	if apply {
+		err, before := h.db.ReadSchemaStatus()
+		if err != nil {
+			h.output.Error(err)
+			return err
+		}
+		if err := h.db.CreateDB(); err != nil {
+			h.output.Error(err)
+			return err
+		}
+		if err := h.db.RunMigrations(); err != nil {
+			h.output.Error(err)
+			return err
+		}
+		applied = before.Pending()
+	}
+
+	err, status := h.db.ReadSchemaStatus()
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	if h.output.IsJSON() {
+		response := MigrationStatusResponse{
+			UpToDate:       status.UpToDate(),
+			JournalMode:    status.JournalMode,
+			Tables:         status.Tables,
+			MissingTables:  status.MissingTables,
+			MissingColumns: status.MissingColumns,
+			Migrations:     make([]MigrationItem, 0, len(status.Migrations)),
+		}
+		for _, m := range status.Migrations {
+			item := MigrationItem{Version: m.Version, Name: m.Name, Applied: m.Applied, Reversible: m.Reversible, Modified: m.Modified}
+			if m.Applied {
+				appliedAt := m.AppliedAt
+				item.AppliedAt = &appliedAt
+			}
+			response.Migrations = append(response.Migrations, item)
+		}
+		for _, m := range applied {
+			response.Applied = append(response.Applied, m.Version)
+		}
+		h.output.JSON(response)
+		return nil
+	}
+
+	for _, m := range applied {
+		h.output.Print("Applied migration %d: %s\n", m.Version, m.Name)
+	}
+	h.output.Print("Journal mode: %s\n", status.JournalMode)
+	h.output.Print("Tables: %d\n", status.Tables)
+	pending := status.Pending()
+	h.output.Print("Migrations: %d applied, %d pending\n", len(status.Migrations)-len(pending), len(pending))
+	for _, m := range status.Migrations {
+		if m.Modified {
+			h.output.Print("  changed migration: %d (%s), the server refuses to start\n", m.Version, m.Name)
+		}
+	}
+	if status.UpToDate() {
+		h.output.Println("Schema is up to date")
+		return nil
+	}
+	for _, m := range pending {
+		h.output.Print("  pending migration: %d (%s)\n", m.Version, m.Name)
+	}
+	for _, table := range status.MissingTables {
+		h.output.Print("  missing table:  %s\n", table)
+	}
+	for _, column := range status.MissingColumns {
+		h.output.Print("  missing column: %s\n", column)
+	}
+	h.output.Println("Run 'stegodon admin migrate' (or start the server) to apply the migrations")
+	return nil
+}
+
+// handleMigrateDown reverts the migrations above a version
+func (h *AdminHandler) handleMigrateDown(args []string) error {
+	err := func() error {
+		if len(args) == 0 {
+			return fmt.Errorf("usage: stegodon admin migrate --down <version> --yes")
+		}
+		version, err := strconv.Atoi(args[0])
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version: %s", args[0])
+		}
+		if !hasFlag(args[1:], "--yes") {
+			return fmt.Errorf("this reverts the schema to migration %d and may drop data, add --yes to confirm", version)
+		}
+		if err := h.db.MigrateDown(version); err != nil {
+			return err
+		}
+		return h.actionDone("reverted", strconv.Itoa(version), "Reverted the schema to migration %d\n", version)
+	}()
+	if err != nil {
+		h.output.Error(err)
+	}
+	return err
+}
+
+func (h *AdminHandler) handleUser(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: user list|create|set-admin|reset-key|ban|unban|delete")
+	}
+	if args[0] == "list" {
+		return h.listUsers()
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("usage: user %s <username>", args[0])
+	}
+	username := args[1]
+	flags := args[2:]
+
+	if args[0] == "create" {
+		return h.createUser(username, flags)
+	}
+
+	err, acc := h.db.ReadAccByUsername(username)
+	if err != nil || acc == nil {
+		return fmt.Errorf("no account named %s", username)
+	}
+
+	switch args[0] {
+	case "set-admin":
+		isAdmin := !hasFlag(flags, "--off")
+		if err := h.db.UpdateAccountAdmin(acc.Id, isAdmin); err != nil {
+			return err
+		}
+		if isAdmin {
+			return h.actionDone("admin", acc.Username, "%s is now an admin\n", acc.Username)
+		}
+		return h.actionDone("not admin", acc.Username, "%s is no longer an admin\n", acc.Username)
+
+	case "reset-key":
+		key, err := h.readPublicKey(flags)
+		if err != nil {
+			return err
+		}
+		if err := h.db.UpdateAccountPublicKey(acc.Id, key); err != nil {
+			return err
+		}
+		return h.actionDone("key reset", acc.Username, "%s can now log in with the new key\n", acc.Username)
+
+	case "ban":
+		reason := "Banned by administrator"
+		if i := indexOf(flags, "--reason"); i >= 0 && i+1 < len(flags) {
+			reason = flags[i+1]
+		}
+		// Same as banning from the admin panel: block the key and last IP, keep the account
+		if err := h.db.CreateBan(acc.Id.String(), acc.Username, acc.LastIP, acc.Publickey, reason); err != nil {
+			return err
+		}
+		if err := h.db.BanAccount(acc.Id); err != nil {
+			return err
+		}
+		return h.actionDone("banned", acc.Username, "Banned %s\n", acc.Username)
+
+	case "unban":
+		if err := h.db.UnbanAccount(acc.Id); err != nil {
+			return err
+		}
+		if err := h.db.DeleteBan(acc.Id.String()); err != nil {
+			return err
+		}
+		return h.actionDone("unbanned", acc.Username, "Unbanned %s\n", acc.Username)
+
+	case "delete":
+		if !hasFlag(flags, "--yes") {
+			return fmt.Errorf("this deletes %s and all their posts, add --yes to confirm", acc.Username)
+		}
+		if err := h.db.DeleteAccount(acc.Id); err != nil {
+			return err
+		}
+		return h.actionDone("deleted", acc.Username, "Deleted %s (remote servers are not notified while offline)\n", acc.Username)
+
+	default:
+		return fmt.Errorf("unknown user command: %s", args[0])
+	}
+}
+
+func (h *AdminHandler) listUsers() error {
+	err, accounts := h.db.ReadAllAccountsAdmin()
+	if err != nil {
+		return err
+	}
+
+	items := make([]AdminUserItem, 0, len(*accounts))
+	for _, acc := range *accounts {
+		items = append(items, AdminUserItem{
+			ID:          acc.Id.String(),
+			Username:    acc.Username,
+			DisplayName: acc.DisplayName,
+			IsAdmin:     acc.IsAdmin,
+			Muted:       acc.Muted,
+			Banned:      acc.Banned,
+			FirstLogin:  acc.FirstTimeLogin == domain.TRUE,
+			LastIP:      acc.LastIP,
+			CreatedAt:   acc.CreatedAt,
+		})
+	}
+
+	if h.output.IsJSON() {
+		h.output.JSON(AdminUsersResponse{Users: items, Count: len(items)})
+		return nil
+	}
+
+	if len(items) == 0 {
+		h.output.Println("No accounts.")
+		return nil
+	}
+	for _, item := range items {
+		var flags []string
+		if item.IsAdmin {
+			flags = append(flags, "admin")
+		}
+		if item.Muted {
+			flags = append(flags, "muted")
+		}
+		if item.Banned {
+			flags = append(flags, "banned")
+		}
+		if item.FirstLogin {
+			flags = append(flags, "not logged in yet")
+		}
+		line := fmt.Sprintf("%s  %-20s  %s", item.ID, item.Username, item.CreatedAt.Format(util.ScheduleTimeFormat))
+		if len(flags) > 0 {
+			line += "  [" + strings.Join(flags, ", ") + "]"
+		}
+		h.output.Println(line)
+	}
+	return nil
+}
+
+func (h *AdminHandler) createUser(username string, args []string) error {
+	if valid, msg := util.IsValidWebFingerUsername(username); !valid {
+		return fmt.Errorf("%s", msg)
+	}
+	if err, existing := h.db.ReadAccByUsername(username); err == nil && existing != nil {
+		return fmt.Errorf("an account named %s already exists", username)
+	}
+
+	key, err := h.readPublicKey(args)
+	if err != nil {
+		return err
+	}
+	if err := h.db.CreateAccountWithPublicKey(username, key); err != nil {
+		return err
+	}
+	return h.actionDone("created", username, "Created %s, who can now log in with the key\n", username)
+}
+
+// readPublicKey reads an SSH public key from the arguments (or stdin for "-")
+// and normalizes it the way keys are stored at login
+func (h *AdminHandler) readPublicKey(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("missing public key (authorized_keys format, or - to read it from stdin)")
+	}
+
+	raw := strings.Join(args, " ")
+	if raw == "-" {
+		data, err := io.ReadAll(h.input)
+		if err != nil {
+			return "", err
+		}
+		raw = string(data)
+	}
+
+	key, _, _, _, err := gossh.ParseAuthorizedKey([]byte(strings.TrimSpace(raw)))
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %w", err)
+	}
+	return util.PublicKeyToString(key), nil
+}
+
+func (h *AdminHandler) handleRelay(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: relay list|remove <id|actor-uri>")
+	}
+
+	err, relays := h.db.ReadAllRelays()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "list":
+		items := make([]AdminRelayItem, 0, len(*relays))
+		for _, relay := range *relays {
+			items = append(items, AdminRelayItem{
+				ID:        relay.Id.String(),
+				ActorURI:  relay.ActorURI,
+				Name:      relay.Name,
+				Status:    relay.Status,
+				Paused:    relay.Paused,
+				CreatedAt: relay.CreatedAt,
+			})
+		}
+		if h.output.IsJSON() {
+			h.output.JSON(AdminRelaysResponse{Relays: items, Count: len(items)})
+			return nil
+		}
+		if len(items) == 0 {
+			h.output.Println("No relays.")
+		}
+		for _, item := range items {
+			status := item.Status
+			if item.Paused {
+				status += ", paused"
+			}
+			h.output.Print("%s  %s  [%s]\n", item.ID, item.ActorURI, status)
+		}
+		return nil
+
+	case "remove":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: relay remove <id|actor-uri>")
+		}
+		var matches []domain.Relay
+		for _, relay := range *relays {
+			if relay.ActorURI == args[1] || strings.HasPrefix(relay.Id.String(), strings.ToLower(args[1])) {
+				matches = append(matches, relay)
+			}
+		}
+		if len(matches) != 1 {
+			return fmt.Errorf("%d relays match %s", len(matches), args[1])
+		}
+		if err := h.db.DeleteRelay(matches[0].Id); err != nil {
+			return err
+		}
+		// No Undo is sent offline; the relay drops us once deliveries to our inbox fail
+		return h.actionDone("removed", matches[0].ActorURI, "Removed relay %s\n", matches[0].ActorURI)
+
+	default:
+		return fmt.Errorf("unknown relay command: %s", args[0])
+	}
+}
+
+func (h *AdminHandler) handleQueue(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: queue list [-n <count>]|purge --yes")
+	}
+
+	switch args[0] {
+	case "list":
+		limit := 50
+		if i := indexOf(args, "-n"); i >= 0 && i+1 < len(args) {
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid count: %s", args[i+1])
+			}
+			limit = n
+		}
+		return h.listQueue(limit)
+
+	case "purge":
+		if !hasFlag(args[1:], "--yes") {
+			return fmt.Errorf("this drops all queued deliveries, add --yes to confirm")
+		}
+		purged, err := h.db.PurgeDeliveryQueue()
+		if err != nil {
+			return err
+		}
+		if h.output.IsJSON() {
+			h.output.JSON(AdminQueuePurgeResponse{Purged: purged})
+		} else {
+			h.output.Print("Purged %d queued deliveries\n", purged)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown queue command: %s", args[0])
+	}
+}
+
+func (h *AdminHandler) listQueue(limit int) error {
+	err, deliveries := h.db.ReadAllDeliveries(limit)
+	if err != nil {
+		return err
+	}
+	pending, held, err := h.db.CountInboxQueue()
+	if err != nil {
+		return err
+	}
+
+	items := make([]AdminQueueItem, 0, len(*deliveries))
+	for _, item := range *deliveries {
+		var activity struct {
+			Type string `json:"type"`
+		}
+		json.Unmarshal([]byte(item.ActivityJSON), &activity)
+		items = append(items, AdminQueueItem{
+			ID:           item.Id.String(),
+			InboxURI:     item.InboxURI,
+			ActivityType: activity.Type,
+			Attempts:     item.Attempts,
+			NextRetryAt:  item.NextRetryAt,
+			CreatedAt:    item.CreatedAt,
+		})
+	}
+
+	if h.output.IsJSON() {
+		h.output.JSON(AdminQueueResponse{
+			Deliveries:   items,
+			Count:        len(items),
+			InboxPending: pending,
+			InboxHeld:    held,
+		})
+		return nil
+	}
+
+	if len(items) == 0 {
+		h.output.Println("No queued deliveries.")
+	}
+	for _, item := range items {
+		h.output.Print("%s  %-8s  %s  attempts: %d, next: %s\n",
+			item.ID, item.ActivityType, item.InboxURI, item.Attempts, item.NextRetryAt.Local().Format(util.ScheduleTimeFormat))
+	}
+	h.output.Print("Inbox queue: %d pending, %d held\n", pending, held)
+	return nil
+}
+
+// actionDone reports a successful change
+func (h *AdminHandler) actionDone(status, target, format string, args ...interface{}) error {
+	if h.output.IsJSON() {
+		h.output.JSON(AdminActionResponse{Status: status, Target: target, DoneAt: time.Now()})
+	} else {
+		h.output.Success(format, args...)
+	}
+	return nil
+}
+
+// hasFlag reports whether args contains flag
+func hasFlag(args []string, flag string) bool {
+	return indexOf(args, flag) >= 0
+}
+
+// indexOf returns the position of s in args, or -1
+func indexOf(args []string, s string) int {
+	for i, arg := range args {
+		if arg == s {
+			return i
+		}
+	}
+	return -1
+}
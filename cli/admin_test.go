@@ -0,0 +1,361 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+const testAuthorizedKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl alice@laptop"
+
+// mockAdminDatabase implements cli.AdminDatabase for testing
+type mockAdminDatabase struct {
+	status     domain.SchemaStatus
+	migrated   bool
+	revertedTo int
+	accounts   []domain.Account
+	created    map[string]string
+	keys       map[uuid.UUID]string
+	admins     map[uuid.UUID]bool
+	bans       []string
+	deleted    []uuid.UUID
+	relays     []domain.Relay
+	deliveries []domain.DeliveryQueueItem
+	purged     bool
+}
+
+func newMockAdminDatabase() *mockAdminDatabase {
+	return &mockAdminDatabase{
+		status:  domain.SchemaStatus{JournalMode: "wal", Tables: 27},
+		created: map[string]string{},
+		keys:    map[uuid.UUID]string{},
+		admins:  map[uuid.UUID]bool{},
+	}
+}
+
+func (m *mockAdminDatabase) ReadSchemaStatus() (error, *domain.SchemaStatus) {
+	status := m.status
+	return nil, &status
+}
+
+func (m *mockAdminDatabase) CreateDB() error { return nil }
+
+func (m *mockAdminDatabase) RunMigrations() error {
+	m.migrated = true
+	m.status.MissingTables = nil
+	m.status.MissingColumns = nil
+	migrations := make([]domain.Migration, len(m.status.Migrations))
+	for i, migration := range m.status.Migrations {
+		migration.Applied = true
+		migrations[i] = migration
+	}
+	m.status.Migrations = migrations
+	return nil
+}
+
+func (m *mockAdminDatabase) MigrateDown(version int) error {
+	for _, migration := range m.status.Migrations {
+		if migration.Applied && migration.Version > version && !migration.Reversible {
+			return fmt.Errorf("migration %d (%s) cannot be reverted", migration.Version, migration.Name)
+		}
+	}
+	m.revertedTo = version
+	return nil
+}
+
+func (m *mockAdminDatabase) ReadAllAccountsAdmin() (error, *[]domain.Account) {
+	accounts := m.accounts
+	return nil, &accounts
+}
+
+func (m *mockAdminDatabase) ReadAccByUsername(username string) (error, *domain.Account) {
+	for i := range m.accounts {
+		if m.accounts[i].Username == username {
+			return nil, &m.accounts[i]
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockAdminDatabase) CreateAccountWithPublicKey(username string, publicKey string) error {
+	m.created[username] = publicKey
+	return nil
+}
+
+func (m *mockAdminDatabase) UpdateAccountAdmin(accountId uuid.UUID, isAdmin bool) error {
+	m.admins[accountId] = isAdmin
+	return nil
+}
+
+func (m *mockAdminDatabase) UpdateAccountPublicKey(accountId uuid.UUID, publicKey string) error {
+	m.keys[accountId] = publicKey
+	return nil
+}
+
+func (m *mockAdminDatabase) CreateBan(id, username, ipAddress, publicKeyHash, reason string) error {
+	m.bans = append(m.bans, username+":"+reason)
+	return nil
+}
+
+func (m *mockAdminDatabase) BanAccount(accountId uuid.UUID) error   { return nil }
+func (m *mockAdminDatabase) UnbanAccount(accountId uuid.UUID) error { return nil }
+func (m *mockAdminDatabase) DeleteBan(id string) error              { return nil }
+
+func (m *mockAdminDatabase) DeleteAccount(accountId uuid.UUID) error {
+	m.deleted = append(m.deleted, accountId)
+	return nil
+}
+
+func (m *mockAdminDatabase) ReadAllRelays() (error, *[]domain.Relay) {
+	relays := m.relays
+	return nil, &relays
+}
+
+func (m *mockAdminDatabase) DeleteRelay(id uuid.UUID) error {
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func (m *mockAdminDatabase) ReadAllDeliveries(limit int) (error, *[]domain.DeliveryQueueItem) {
+	deliveries := m.deliveries
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return nil, &deliveries
+}
+
+func (m *mockAdminDatabase) CountInboxQueue() (int, int, error) {
+	return 2, 1, nil
+}
+
+func (m *mockAdminDatabase) PurgeDeliveryQueue() (int64, error) {
+	m.purged = true
+	return int64(len(m.deliveries)), nil
+}
+
+func runAdmin(t *testing.T, db *mockAdminDatabase, input string, args ...string) (string, error) {
+	t.Helper()
+	args, jsonMode := ParseAdminArgs(args)
+	out := &bytes.Buffer{}
+	err := NewAdminHandler(strings.NewReader(input), out, db, jsonMode).Execute(args)
+	return out.String(), err
+}
+
+func TestAdminUserList(t *testing.T) {
+	db := newMockAdminDatabase()
+	db.accounts = []domain.Account{
+		{Id: uuid.New(), Username: "alice", IsAdmin: true, CreatedAt: time.Now()},
+		{Id: uuid.New(), Username: "bob", Banned: true, CreatedAt: time.Now()},
+	}
+
+	out, err := runAdmin(t, db, "", "user", "list", "--json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var resp AdminUsersResponse
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, out)
+	}
+	if resp.Count != 2 || !resp.Users[0].IsAdmin || !resp.Users[1].Banned {
+		t.Errorf("Unexpected users: %+v", resp)
+	}
+}
+
+func TestAdminUserCreate(t *testing.T) {
+	db := newMockAdminDatabase()
+
+	if _, err := runAdmin(t, db, testAuthorizedKey+"\n", "user", "create", "alice", "-"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	key := db.created["alice"]
+	if !strings.HasPrefix(key, "ssh-ed25519 AAAA") || strings.Contains(key, "alice@laptop") {
+		t.Errorf("Expected normalized key without comment, got %q", key)
+	}
+}
+
+func TestAdminUserCreateRejectsInvalidInput(t *testing.T) {
+	db := newMockAdminDatabase()
+	db.accounts = []domain.Account{{Id: uuid.New(), Username: "alice"}}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"existing username", []string{"user", "create", "alice", testAuthorizedKey}},
+		{"invalid username", []string{"user", "create", "no spaces", testAuthorizedKey}},
+		{"invalid key", []string{"user", "create", "bob", "ssh-ed25519", "garbage"}},
+		{"missing key", []string{"user", "create", "bob"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := runAdmin(t, db, "", tt.args...); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+	if len(db.created) != 0 {
+		t.Errorf("Expected no accounts to be created, got %v", db.created)
+	}
+}
+
+func TestAdminUserChanges(t *testing.T) {
+	db := newMockAdminDatabase()
+	id := uuid.New()
+	db.accounts = []domain.Account{{Id: id, Username: "alice", Publickey: "hash"}}
+
+	if _, err := runAdmin(t, db, "", "user", "set-admin", "alice"); err != nil || !db.admins[id] {
+		t.Errorf("Expected alice to become admin (err %v)", err)
+	}
+	if _, err := runAdmin(t, db, "", "user", "set-admin", "alice", "--off"); err != nil || db.admins[id] {
+		t.Errorf("Expected alice to lose admin (err %v)", err)
+	}
+	if _, err := runAdmin(t, db, "", "user", "reset-key", "alice", testAuthorizedKey); err != nil || db.keys[id] == "" {
+		t.Errorf("Expected key to be reset (err %v)", err)
+	}
+	if _, err := runAdmin(t, db, "", "user", "ban", "alice", "--reason", "spam"); err != nil || len(db.bans) != 1 || db.bans[0] != "alice:spam" {
+		t.Errorf("Expected ban with reason, got %v (err %v)", db.bans, err)
+	}
+	if _, err := runAdmin(t, db, "", "user", "unban", "ghost"); err == nil {
+		t.Error("Expected an error for an unknown user")
+	}
+}
+
+func TestAdminUserDeleteNeedsConfirmation(t *testing.T) {
+	db := newMockAdminDatabase()
+	id := uuid.New()
+	db.accounts = []domain.Account{{Id: id, Username: "alice"}}
+
+	if _, err := runAdmin(t, db, "", "user", "delete", "alice"); err == nil || len(db.deleted) != 0 {
+		t.Fatal("Expected delete without --yes to be refused")
+	}
+	if _, err := runAdmin(t, db, "", "user", "delete", "alice", "--yes"); err != nil || len(db.deleted) != 1 || db.deleted[0] != id {
+		t.Errorf("Expected alice to be deleted, got %v (err %v)", db.deleted, err)
+	}
+}
+
+func TestAdminRelayRemove(t *testing.T) {
+	db := newMockAdminDatabase()
+	relay := domain.Relay{Id: uuid.New(), ActorURI: "https://relay.example/actor", Status: "active"}
+	db.relays = []domain.Relay{relay, {Id: uuid.New(), ActorURI: "https://other.example/actor"}}
+
+	if _, err := runAdmin(t, db, "", "relay", "remove", relay.Id.String()[:8]); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(db.deleted) != 1 || db.deleted[0] != relay.Id {
+		t.Errorf("Expected relay to be removed, got %v", db.deleted)
+	}
+	if _, err := runAdmin(t, db, "", "relay", "remove", "https://unknown.example/actor"); err == nil {
+		t.Error("Expected an error for an unknown relay")
+	}
+}
+
+func TestAdminQueue(t *testing.T) {
+	db := newMockAdminDatabase()
+	db.deliveries = []domain.DeliveryQueueItem{
+		{Id: uuid.New(), InboxURI: "https://a.example/inbox", ActivityJSON: `{"type":"Create"}`, Attempts: 3},
+		{Id: uuid.New(), InboxURI: "https://b.example/inbox", ActivityJSON: `{"type":"Like"}`},
+	}
+
+	out, err := runAdmin(t, db, "", "--json", "queue", "list", "-n", "1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var resp AdminQueueResponse
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, out)
+	}
+	if resp.Count != 1 || resp.Deliveries[0].ActivityType != "Create" || resp.InboxPending != 2 || resp.InboxHeld != 1 {
+		t.Errorf("Unexpected queue output: %+v", resp)
+	}
+
+	if _, err := runAdmin(t, db, "", "queue", "purge"); err == nil || db.purged {
+		t.Fatal("Expected purge without --yes to be refused")
+	}
+	out, err = runAdmin(t, db, "", "queue", "purge", "--yes")
+	if err != nil || !db.purged || !strings.Contains(out, "Purged 2") {
+		t.Errorf("Expected queue to be purged, got %q (err %v)", out, err)
+	}
+}
+
+func TestAdminRequiresCurrentSchema(t *testing.T) {
+	db := newMockAdminDatabase()
+	db.status.MissingColumns = []string{"accounts.is_admin"}
+
+	out, err := runAdmin(t, db, "", "user", "list")
+	if err == nil || !strings.Contains(out, "admin migrate") {
+		t.Fatalf("Expected schema error, got %q", out)
+	}
+
+	out, err = runAdmin(t, db, "", "migrate", "--status")
+	if err != nil || db.migrated || !strings.Contains(out, "missing column: accounts.is_admin") {
+		t.Errorf("Expected status without migrating, got %q (err %v)", out, err)
+	}
+
+	out, err = runAdmin(t, db, "", "migrate")
+	if err != nil || !db.migrated || !strings.Contains(out, "Schema is up to date") {
+		t.Errorf("Expected migrations to run, got %q (err %v)", out, err)
+	}
+}
+
+func TestAdminMigrateDryRun(t *testing.T) {
+	db := newMockAdminDatabase()
+	db.status.Migrations = []domain.Migration{
+		{Version: 1, Name: "baseline schema", Applied: true, AppliedAt: time.Now()},
+		{Version: 2, Name: "unique follows"},
+	}
+
+	out, err := runAdmin(t, db, "", "user", "list")
+	if err == nil || !strings.Contains(out, "1 pending migrations") {
+		t.Fatalf("Expected pending migrations to block admin commands, got %q", out)
+	}
+
+	out, err = runAdmin(t, db, "", "migrate", "--dry-run")
+	if err != nil || db.migrated || !strings.Contains(out, "1 applied, 1 pending") || !strings.Contains(out, "pending migration: 2 (unique follows)") {
+		t.Errorf("Expected pending migrations without applying them, got %q (err %v)", out, err)
+	}
+
+	out, err = runAdmin(t, db, "", "migrate", "--json")
+	if err != nil || !db.migrated {
+		t.Fatalf("Expected migrations to run, got %q (err %v)", out, err)
+	}
+	var response MigrationStatusResponse
+	if err := json.Unmarshal([]byte(out), &response); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", out, err)
+	}
+	if !response.UpToDate || len(response.Applied) != 1 || response.Applied[0] != 2 || len(response.Migrations) != 2 {
+		t.Errorf("Unexpected migrate response %+v", response)
+	}
+}
+
+func TestAdminMigrateDown(t *testing.T) {
+	db := newMockAdminDatabase()
+	db.revertedTo = -1
+	db.status.Migrations = []domain.Migration{
+		{Version: 1, Name: "baseline schema", Applied: true},
+		{Version: 2, Name: "drafts index", Applied: true, Reversible: true},
+	}
+
+	out, err := runAdmin(t, db, "", "migrate", "--down", "1")
+	if err == nil || db.revertedTo != -1 || !strings.Contains(out, "--yes") {
+		t.Errorf("Expected confirmation to be required, got %q", out)
+	}
+
+	out, err = runAdmin(t, db, "", "migrate", "--down", "1", "--yes")
+	if err != nil || db.revertedTo != 1 || !strings.Contains(out, "Reverted the schema to migration 1") {
+		t.Errorf("Expected migration 2 to be reverted, got %q (err %v)", out, err)
+	}
+
+	out, err = runAdmin(t, db, "", "migrate", "--down", "0", "--yes")
+	if err == nil || !strings.Contains(out, "cannot be reverted") {
+		t.Errorf("Expected the baseline to be irreversible, got %q", out)
+	}
+}
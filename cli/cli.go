@@ -1,12 +1,17 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
+	"github.com/deemkeen/stegodon/actions"
 	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
 	"github.com/deemkeen/stegodon/util"
+	"github.com/deemkeen/stegodon/web"
 )
 
 // Session interface represents the minimal session requirements for CLI operations
@@ -18,11 +23,39 @@ type Session interface {
 // Database interface for CLI operations
 type Database interface {
 	CreateNote(userId interface{}, message string) (interface{}, error)
+	CreateNoteWithReply(userId interface{}, message string, inReplyToURI string) (interface{}, error)
+	CreateArticle(userId interface{}, title string, message string) (interface{}, error)
+	CreateScheduledNote(note *domain.ScheduledNote) error
+	ReadDraftsByAccountId(accountId interface{}) (error, *[]domain.Draft)
+	PublishDraft(draft *domain.Draft) (interface{}, error)
 	ReadNoteIdWithReplyInfo(id interface{}) (error, *domain.Note)
-	ReadHomeTimelinePosts(accountId interface{}, limit int) (error, *[]domain.HomePost)
-	ReadNotificationsByAccountId(accountId interface{}, limit int) (error, *[]domain.Notification)
+	ReadHomeTimelinePosts(accountId interface{}, before domain.Cursor, limit int) (error, *[]domain.HomePost)
+	ReadListByName(accountId interface{}, name string) (error, *domain.List)
+	ReadListTimelinePosts(accountId interface{}, listId interface{}, before domain.Cursor, limit int) (error, *[]domain.HomePost)
+	ReadFiltersByAccountId(accountId interface{}) (error, *[]domain.Filter)
+	ReadNotificationsByAccountId(accountId interface{}, before domain.Cursor, limit int) (error, *[]domain.Notification)
 	CountUnreadNotifications(accountId interface{}) (int, error)
 	DeleteAllNotifications(accountId interface{}) error
+	ReadFollowedHashtags(accountId interface{}) (error, []string)
+	ReadFollows(accountId interface{}) (error, *events.Follows) // Decide what events change the home timeline
+	FollowHashtag(accountId interface{}, tag string) error
+	UnfollowHashtag(accountId interface{}, tag string) error
+
+	// Post and account actions. They perform the same notifications and federation as the TUI.
+	// Posts are referred to by id or ActivityPub URI, accounts by @user, @user@domain or profile URL.
+	NoteCreated(account *domain.Account, noteId interface{}, message string, inReplyToURI string) // Links hashtags and notifies local users
+	ReplyToPost(account *domain.Account, ref string, message string) (interface{}, error)
+	SetLike(account *domain.Account, ref string, like bool) error
+	SetBoost(account *domain.Account, ref string, boost bool) error
+	EditNote(account *domain.Account, noteId interface{}, message string) error
+	DeleteNote(account *domain.Account, noteId interface{}) error
+	Follow(account *domain.Account, handle string) (bool, error) // Reports whether the follow is pending
+	Unfollow(account *domain.Account, handle string) error
+	ReadThread(ref string) (error, *actions.Thread)
+	ReadProfile(viewer *domain.Account, handle string) (error, *actions.Profile) // Empty handle for the viewer
+	ReadFollowProfiles(accountId interface{}, followers bool) (error, *[]actions.Profile)
+	UpdateAccountDisplayName(accountId interface{}, displayName string) error
+	UpdateAccountSummary(accountId interface{}, summary string) error
 }
 
 // Handler processes CLI commands
@@ -33,16 +66,25 @@ type Handler struct {
 	output   *Output
 	jsonMode bool
 	conf     *util.AppConfig
+	resolve  func(input string) (*web.ResolveResult, error) // Looks up posts and accounts by URL
+
+	ctx       context.Context             // Ends when the client disconnects; stops --follow streams
+	subscribe func() *events.Subscription // Event source for --follow, the process-wide bus if nil
+	heartbeat time.Duration               // Heartbeat interval of --follow streams, followHeartbeatInterval if zero
 }
 
-// NewHandler creates a new CLI handler
-func NewHandler(s Session, db Database, acc *domain.Account, conf *util.AppConfig) *Handler {
+// NewHandler creates a new CLI handler. ctx is the lifetime of the SSH session.
+func NewHandler(ctx context.Context, s Session, db Database, acc *domain.Account, conf *util.AppConfig) *Handler {
 	return &Handler{
+		ctx:      ctx,
 		session:  s,
 		db:       db,
 		account:  acc,
 		jsonMode: false,
 		conf:     conf,
+		resolve: func(input string) (*web.ResolveResult, error) {
+			return web.ResolveURL(input, conf)
+		},
 	}
 }
 
@@ -72,6 +114,32 @@ func (h *Handler) Execute(args []string) error {
 		return h.handleNotifications(cmdArgs)
 	case "clear-notifications":
 		return h.handleClearNotifications(cmdArgs)
+	case "tags":
+		return h.handleTags(cmdArgs)
+	case "resolve":
+		return h.handleResolve(cmdArgs)
+	case "drafts":
+		return h.handleDrafts(cmdArgs)
+	case "reply":
+		return h.handleReply(cmdArgs)
+	case "like", "unlike", "boost", "unboost":
+		return h.handlePostAction(cmd, cmdArgs)
+	case "edit":
+		return h.handleEdit(cmdArgs)
+	case "delete":
+		return h.handleDelete(cmdArgs)
+	case "thread":
+		return h.handleThread(cmdArgs)
+	case "follow", "unfollow":
+		return h.handleFollow(cmd, cmdArgs)
+	case "followers", "following":
+		return h.handleFollowList(cmd, cmdArgs)
+	case "profile":
+		return h.handleProfile(cmdArgs)
+	case "whoami":
+		return h.handleWhoami(cmdArgs)
+	case "set-bio", "set-name":
+		return h.handleSetProfile(cmd, cmdArgs)
 	case "--help", "-h", "help":
 		return h.showHelp()
 	default:
@@ -107,25 +175,110 @@ func (h *Handler) showHelp() error {
 				{
 					Name:        "post",
 					Description: "Create a new note",
-					Usage:       "post <message> or post -",
-					Flags:       []string{"-: read message from stdin"},
+					Usage:       "post [--at <time>] <message|->, post --thread <text|->, or post --article [--title <title>] <markdown|->",
+					Flags: []string{
+						"-: read message from stdin",
+						"--at <time>: publish later (+2h, 07:30 or 2006-01-02 15:04, server time)",
+						"--article: publish a long-form markdown article (title from the first line)",
+						"--title <title>: article title (implies --article)",
+						"--thread: split long text into numbered notes, on \"---\" lines or automatically",
+					},
 				},
 				{
 					Name:        "timeline",
 					Description: "Show recent home timeline",
-					Usage:       "timeline [-n <count>]",
-					Flags:       []string{"-n <count>: limit number of posts (default 20)"},
+					Usage:       "timeline [-n <count>] [--list <name>] [--before <cursor>] [--follow]",
+					Flags: []string{
+						"-n <count>: limit number of posts (default 20)",
+						"--list <name>: show a list timeline instead of home",
+						"--before <cursor>: show the posts older than a cursor (next_cursor of the previous page)",
+						"--follow, -f: keep streaming new posts, one per line, until disconnected (-n 0 skips older posts)",
+					},
 				},
 				{
 					Name:        "notifications",
 					Description: "Show unread notifications",
-					Usage:       "notifications",
+					Usage:       "notifications [-n <count>] [--follow]",
+					Flags: []string{
+						"-n <count>: limit number of notifications (default 20)",
+						"--follow, -f: keep streaming new notifications, one per line, until disconnected",
+					},
 				},
 				{
 					Name:        "clear-notifications",
 					Description: "Clear all notifications",
 					Usage:       "clear-notifications",
 				},
+				{
+					Name:        "tags",
+					Description: "List, follow or unfollow hashtags shown in the home timeline",
+					Usage:       "tags [list] | tags follow <tag> | tags unfollow <tag>",
+				},
+				{
+					Name:        "resolve",
+					Description: "Fetch a post or account by URL, ActivityPub id or @user@domain handle",
+					Usage:       "resolve <url>",
+				},
+				{
+					Name:        "drafts",
+					Description: "List drafts autosaved in the TUI or publish one",
+					Usage:       "drafts [list] | drafts publish <id>",
+				},
+				{
+					Name:        "reply",
+					Description: "Reply to a post",
+					Usage:       "reply <id|uri> <message|->",
+				},
+				{
+					Name:        "like",
+					Description: "Like a post (unlike to undo)",
+					Usage:       "like <id|uri> | unlike <id|uri>",
+				},
+				{
+					Name:        "boost",
+					Description: "Boost a post (unboost to undo)",
+					Usage:       "boost <id|uri> | unboost <id|uri>",
+				},
+				{
+					Name:        "edit",
+					Description: "Replace the text of one of your posts (the body of an article)",
+					Usage:       "edit <id> <message|->",
+				},
+				{
+					Name:        "delete",
+					Description: "Delete one of your posts",
+					Usage:       "delete <id>",
+				},
+				{
+					Name:        "thread",
+					Description: "Show a post with the posts it replies to and its replies",
+					Usage:       "thread <id|uri>",
+				},
+				{
+					Name:        "follow",
+					Description: "Follow an account (unfollow to undo)",
+					Usage:       "follow <@user|@user@domain|url> | unfollow <@user|@user@domain|url>",
+				},
+				{
+					Name:        "followers",
+					Description: "List your followers, or the accounts you follow",
+					Usage:       "followers | following",
+				},
+				{
+					Name:        "profile",
+					Description: "Show the profile of an account, or your own",
+					Usage:       "profile [@user|@user@domain|url]",
+				},
+				{
+					Name:        "whoami",
+					Description: "Show the account you are logged in as",
+					Usage:       "whoami",
+				},
+				{
+					Name:        "set-bio",
+					Description: "Change your bio (max 200 chars) or display name (max 50 chars)",
+					Usage:       "set-bio <text|-> | set-name <text|->",
+				},
 				{
 					Name:        "help",
 					Description: "Show this help message",
@@ -145,10 +298,35 @@ func (h *Handler) showHelp() error {
 		h.output.Println("Commands:")
 		h.output.Println("  post <message>        Create a new note")
 		h.output.Println("  post -                Read message from stdin")
+		h.output.Println("  post --at <time> ...  Schedule the post (+2h, 07:30, 2006-01-02 15:04)")
+		h.output.Println("  post --article -      Publish a markdown article from stdin (first line is the title)")
+		h.output.Println("  post --thread -       Split long text from stdin into a thread of notes")
 		h.output.Println("  timeline              Show recent home timeline")
 		h.output.Println("  timeline -n <N>       Limit to N posts")
+		h.output.Println("  timeline --list <L>   Show the timeline of list L")
+		h.output.Println("  timeline --before <C> Show the next page, older than cursor C")
+		h.output.Println("  timeline --follow     Stream new posts until disconnected")
 		h.output.Println("  notifications         Show unread notifications")
+		h.output.Println("  notifications -f      Stream new notifications until disconnected")
 		h.output.Println("  clear-notifications   Clear all notifications")
+		h.output.Println("  tags                  List followed hashtags")
+		h.output.Println("  tags follow <tag>     Follow a hashtag into your home timeline")
+		h.output.Println("  tags unfollow <tag>   Unfollow a hashtag")
+		h.output.Println("  resolve <url>         Fetch a post or account by URL or @user@domain")
+		h.output.Println("  drafts                List drafts autosaved in the TUI")
+		h.output.Println("  drafts publish <id>   Publish a draft (id or unique id prefix)")
+		h.output.Println("  reply <id> <message>  Reply to a post (id or ActivityPub URI)")
+		h.output.Println("  like <id>, unlike     Like a post or undo the like")
+		h.output.Println("  boost <id>, unboost   Boost a post or undo the boost")
+		h.output.Println("  edit <id> <message>   Replace the text of one of your posts")
+		h.output.Println("  delete <id>           Delete one of your posts")
+		h.output.Println("  thread <id>           Show a post with its parents and replies")
+		h.output.Println("  follow <@user@domain> Follow an account (unfollow to undo)")
+		h.output.Println("  followers, following  List your followers or who you follow")
+		h.output.Println("  profile [@user]       Show a profile, your own without argument")
+		h.output.Println("  whoami                Show the account you are logged in as")
+		h.output.Println("  set-bio <text>        Change your bio")
+		h.output.Println("  set-name <text>       Change your display name")
 		h.output.Println("  help                  Show this help message")
 		h.output.Println("")
 		h.output.Println("Global flags:")
@@ -158,6 +336,8 @@ func (h *Handler) showHelp() error {
 		h.output.Println("  ssh -p 23232 localhost post \"Hello world\"")
 		h.output.Println("  ssh -p 23232 localhost timeline -j")
 		h.output.Println("  echo \"Hello\" | ssh -p 23232 localhost post -")
+		h.output.Println("  ssh -p 23232 localhost post --article - < article.md")
+		h.output.Println("  ssh -p 23232 localhost reply <id> \"Thanks!\"")
 	}
 	return nil
 }
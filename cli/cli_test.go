@@ -3,12 +3,15 @@ package cli
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/deemkeen/stegodon/actions"
 	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
 	"github.com/deemkeen/stegodon/util"
 	"github.com/google/uuid"
 )
@@ -43,6 +46,134 @@ type mockDatabase struct {
 	createdNoteID      uuid.UUID
 	deleteAllCalled    bool
 	deleteAllError     error
+	followedTags       []string
+	follows            *events.Follows // Nil: follows not known
+	tagError           error
+	lists              map[string]domain.List
+	listNotes          map[uuid.UUID][]domain.HomePost
+	filters            []domain.Filter
+	scheduled          []domain.ScheduledNote
+	drafts             []domain.Draft
+	publishedDrafts    []domain.Draft
+	articles           []domain.Note
+	replies            []domain.Note
+	actions            []string // "<action> <ref>" for each post or account action
+	createdNotes       []string // Messages passed to NoteCreated
+	actionError        error
+	followPending      bool
+	thread             *actions.Thread
+	profiles           map[string]*actions.Profile // By handle; "" is the viewer
+	followProfiles     map[bool][]actions.Profile  // Followers (true) or following (false)
+	displayName        string
+	summary            string
+	note               *domain.Note // Returned for any note id if set
+}
+
+func (m *mockDatabase) NoteCreated(account *domain.Account, noteId interface{}, message string, inReplyToURI string) {
+	m.createdNotes = append(m.createdNotes, message)
+}
+
+func (m *mockDatabase) ReplyToPost(account *domain.Account, ref string, message string) (interface{}, error) {
+	if m.actionError != nil {
+		return nil, m.actionError
+	}
+	m.actions = append(m.actions, "reply "+ref)
+	id := uuid.New()
+	m.replies = append(m.replies, domain.Note{Id: id, Message: message, InReplyToURI: ref})
+	return id, nil
+}
+
+func (m *mockDatabase) SetLike(account *domain.Account, ref string, like bool) error {
+	if like {
+		return m.record("like " + ref)
+	}
+	return m.record("unlike " + ref)
+}
+
+func (m *mockDatabase) SetBoost(account *domain.Account, ref string, boost bool) error {
+	if boost {
+		return m.record("boost " + ref)
+	}
+	return m.record("unboost " + ref)
+}
+
+func (m *mockDatabase) EditNote(account *domain.Account, noteId interface{}, message string) error {
+	return m.record(fmt.Sprintf("edit %v %s", noteId, message))
+}
+
+func (m *mockDatabase) DeleteNote(account *domain.Account, noteId interface{}) error {
+	return m.record(fmt.Sprintf("delete %v", noteId))
+}
+
+func (m *mockDatabase) Follow(account *domain.Account, handle string) (bool, error) {
+	return m.followPending, m.record("follow " + handle)
+}
+
+func (m *mockDatabase) Unfollow(account *domain.Account, handle string) error {
+	return m.record("unfollow " + handle)
+}
+
+// record records an action, or fails it with actionError
+func (m *mockDatabase) record(action string) error {
+	if m.actionError != nil {
+		return m.actionError
+	}
+	m.actions = append(m.actions, action)
+	return nil
+}
+
+func (m *mockDatabase) ReadThread(ref string) (error, *actions.Thread) {
+	if m.thread == nil {
+		return fmt.Errorf("post not found: %s", ref), nil
+	}
+	return nil, m.thread
+}
+
+func (m *mockDatabase) ReadProfile(viewer *domain.Account, handle string) (error, *actions.Profile) {
+	profile, ok := m.profiles[handle]
+	if !ok {
+		return fmt.Errorf("user not found: %s", handle), nil
+	}
+	return nil, profile
+}
+
+func (m *mockDatabase) ReadFollowProfiles(accountId interface{}, followers bool) (error, *[]actions.Profile) {
+	profiles := m.followProfiles[followers]
+	return nil, &profiles
+}
+
+func (m *mockDatabase) UpdateAccountDisplayName(accountId interface{}, displayName string) error {
+	m.displayName = displayName
+	return m.actionError
+}
+
+func (m *mockDatabase) UpdateAccountSummary(accountId interface{}, summary string) error {
+	m.summary = summary
+	return m.actionError
+}
+
+func (m *mockDatabase) ReadDraftsByAccountId(accountId interface{}) (error, *[]domain.Draft) {
+	drafts := m.drafts
+	return nil, &drafts
+}
+
+func (m *mockDatabase) PublishDraft(draft *domain.Draft) (interface{}, error) {
+	if m.createError != nil {
+		return nil, m.createError
+	}
+	m.publishedDrafts = append(m.publishedDrafts, *draft)
+	if m.createdNoteID == uuid.Nil {
+		m.createdNoteID = uuid.New()
+	}
+	return m.createdNoteID, nil
+}
+
+func (m *mockDatabase) CreateScheduledNote(note *domain.ScheduledNote) error {
+	if m.createError != nil {
+		return m.createError
+	}
+	m.scheduled = append(m.scheduled, *note)
+	return nil
 }
 
 func (m *mockDatabase) CreateNote(userId interface{}, message string) (interface{}, error) {
@@ -55,8 +186,31 @@ func (m *mockDatabase) CreateNote(userId interface{}, message string) (interface
 	return m.createdNoteID, nil
 }
 
+func (m *mockDatabase) CreateNoteWithReply(userId interface{}, message string, inReplyToURI string) (interface{}, error) {
+	if m.createError != nil {
+		return nil, m.createError
+	}
+	id := uuid.New()
+	m.replies = append(m.replies, domain.Note{Id: id, Message: message, InReplyToURI: inReplyToURI})
+	return id, nil
+}
+
+func (m *mockDatabase) CreateArticle(userId interface{}, title string, message string) (interface{}, error) {
+	if m.createError != nil {
+		return nil, m.createError
+	}
+	if m.createdNoteID == uuid.Nil {
+		m.createdNoteID = uuid.New()
+	}
+	m.articles = append(m.articles, domain.Note{Id: m.createdNoteID, Title: title, Message: message})
+	return m.createdNoteID, nil
+}
+
 func (m *mockDatabase) ReadNoteIdWithReplyInfo(id interface{}) (error, *domain.Note) {
 	noteId := id.(uuid.UUID)
+	if m.note != nil {
+		return nil, m.note
+	}
 	return nil, &domain.Note{
 		Id:        noteId,
 		CreatedBy: "testuser",
@@ -64,16 +218,51 @@ func (m *mockDatabase) ReadNoteIdWithReplyInfo(id interface{}) (error, *domain.N
 	}
 }
 
-func (m *mockDatabase) ReadHomeTimelinePosts(accountId interface{}, limit int) (error, *[]domain.HomePost) {
-	posts := m.notes
+func (m *mockDatabase) ReadHomeTimelinePosts(accountId interface{}, before domain.Cursor, limit int) (error, *[]domain.HomePost) {
+	posts := []domain.HomePost{}
+	for _, p := range m.notes {
+		if before.IsZero() || before.After(p.Cursor()) {
+			posts = append(posts, p)
+		}
+	}
 	if len(posts) > limit {
 		posts = posts[:limit]
 	}
 	return nil, &posts
 }
 
-func (m *mockDatabase) ReadNotificationsByAccountId(accountId interface{}, limit int) (error, *[]domain.Notification) {
-	notifs := m.notifications
+func (m *mockDatabase) ReadListByName(accountId interface{}, name string) (error, *domain.List) {
+	list, ok := m.lists[name]
+	if !ok {
+		return nil, nil
+	}
+	return nil, &list
+}
+
+func (m *mockDatabase) ReadListTimelinePosts(accountId interface{}, listId interface{}, before domain.Cursor, limit int) (error, *[]domain.HomePost) {
+	posts := []domain.HomePost{}
+	for _, p := range m.listNotes[listId.(uuid.UUID)] {
+		if before.IsZero() || before.After(p.Cursor()) {
+			posts = append(posts, p)
+		}
+	}
+	if len(posts) > limit {
+		posts = posts[:limit]
+	}
+	return nil, &posts
+}
+
+func (m *mockDatabase) ReadFiltersByAccountId(accountId interface{}) (error, *[]domain.Filter) {
+	return nil, &m.filters
+}
+
+func (m *mockDatabase) ReadNotificationsByAccountId(accountId interface{}, before domain.Cursor, limit int) (error, *[]domain.Notification) {
+	notifs := []domain.Notification{}
+	for _, n := range m.notifications {
+		if before.IsZero() || before.After(n.Cursor()) {
+			notifs = append(notifs, n)
+		}
+	}
 	if len(notifs) > limit {
 		notifs = notifs[:limit]
 	}
@@ -89,6 +278,40 @@ func (m *mockDatabase) DeleteAllNotifications(accountId interface{}) error {
 	return m.deleteAllError
 }
 
+func (m *mockDatabase) ReadFollows(accountId interface{}) (error, *events.Follows) {
+	return nil, m.follows
+}
+
+func (m *mockDatabase) ReadFollowedHashtags(accountId interface{}) (error, []string) {
+	return m.tagError, m.followedTags
+}
+
+func (m *mockDatabase) FollowHashtag(accountId interface{}, tag string) error {
+	if m.tagError != nil {
+		return m.tagError
+	}
+	for _, t := range m.followedTags {
+		if t == tag {
+			return nil
+		}
+	}
+	m.followedTags = append(m.followedTags, tag)
+	return nil
+}
+
+func (m *mockDatabase) UnfollowHashtag(accountId interface{}, tag string) error {
+	if m.tagError != nil {
+		return m.tagError
+	}
+	for i, t := range m.followedTags {
+		if t == tag {
+			m.followedTags = append(m.followedTags[:i], m.followedTags[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
 func newTestHandler(input string) (*Handler, *bytes.Buffer) {
 	session := newMockSession(input)
 	db := &mockDatabase{}
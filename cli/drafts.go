@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+)
+
+// handleDrafts lists drafts or publishes one
+func (h *Handler) handleDrafts(args []string) error {
+	if len(args) == 0 || strings.ToLower(args[0]) == "list" {
+		return h.listDrafts()
+	}
+
+	if strings.ToLower(args[0]) != "publish" {
+		err := fmt.Errorf("unknown drafts action: %s (use list or publish)", args[0])
+		h.output.Error(err)
+		return err
+	}
+
+	if len(args) < 2 {
+		err := fmt.Errorf("usage: drafts publish <id>")
+		h.output.Error(err)
+		return err
+	}
+
+	return h.publishDraft(args[1])
+}
+
+// listDrafts shows the user's drafts, most recently edited first
+func (h *Handler) listDrafts() error {
+	err, drafts := h.db.ReadDraftsByAccountId(h.account.Id)
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	if h.output.IsJSON() {
+		items := []DraftItem{}
+		if drafts != nil {
+			for _, draft := range *drafts {
+				items = append(items, DraftItem{
+					ID:           draft.Id.String(),
+					Title:        draft.Title,
+					Message:      draft.Message,
+					InReplyToURI: draft.InReplyToURI,
+					ReplyTo:      draft.ReplyToAuthor,
+					UpdatedAt:    draft.UpdatedAt,
+				})
+			}
+		}
+		h.output.JSON(DraftsResponse{
+			Drafts: items,
+			Count:  len(items),
+		})
+		return nil
+	}
+
+	if drafts == nil || len(*drafts) == 0 {
+		h.output.Println("No drafts.")
+		return nil
+	}
+
+	for _, draft := range *drafts {
+		header := fmt.Sprintf("%s  %s", draft.Id, draft.UpdatedAt.Local().Format(util.ScheduleTimeFormat))
+		if draft.InReplyToURI != "" {
+			header += "  reply to " + draft.ReplyToAuthor
+		}
+		if draft.Title != "" {
+			header += "  article: " + draft.Title
+		}
+		h.output.Print("%s\n%s\n\n", header, draft.Message)
+	}
+
+	return nil
+}
+
+// publishDraft posts the draft with the given id (or unique id prefix) and removes it
+func (h *Handler) publishDraft(id string) error {
+	draft, err := h.findDraft(id)
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	// Drafts are saved while typing, so they may not be valid notes yet
+	if draft.Title != "" {
+		if err := util.ValidateArticle(draft.Title, draft.Message); err != nil {
+			h.output.Error(err)
+			return err
+		}
+		return h.publishValidDraft(draft)
+	}
+	if strings.TrimSpace(draft.Message) == "" {
+		err := fmt.Errorf("draft is empty")
+		h.output.Error(err)
+		return err
+	}
+	if visibleChars := util.CountVisibleChars(draft.Message); visibleChars > h.conf.Conf.MaxChars {
+		err := fmt.Errorf("draft too long (%d visible characters, max %d)", visibleChars, h.conf.Conf.MaxChars)
+		h.output.Error(err)
+		return err
+	}
+	if err := util.ValidateNoteLength(draft.Message); err != nil {
+		h.output.Error(err)
+		return err
+	}
+	draft.Message = util.NormalizeInput(draft.Message)
+
+	return h.publishValidDraft(draft)
+}
+
+// publishValidDraft stores a validated draft as a note or article and federates it
+func (h *Handler) publishValidDraft(draft *domain.Draft) error {
+	noteId, err := h.db.PublishDraft(draft)
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+	h.db.NoteCreated(h.account, noteId, draft.Message, "")
+
+	// Federate the note via ActivityPub (background task)
+	go h.federateNote(noteId)
+
+	if h.output.IsJSON() {
+		h.output.JSON(PostResponse{
+			ID:        fmt.Sprintf("%v", noteId),
+			Title:     draft.Title,
+			Message:   draft.Message,
+			CreatedAt: time.Now(),
+		})
+	} else {
+		h.output.Success("Posted: %v\n", noteId)
+	}
+
+	return nil
+}
+
+// findDraft returns the user's draft whose id starts with prefix, if exactly one does
+func (h *Handler) findDraft(prefix string) (*domain.Draft, error) {
+	err, drafts := h.db.ReadDraftsByAccountId(h.account.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	var found *domain.Draft
+	if drafts != nil && prefix != "" {
+		for i := range *drafts {
+			if strings.HasPrefix((*drafts)[i].Id.String(), prefix) {
+				if found != nil {
+					return nil, fmt.Errorf("draft id %s is ambiguous", prefix)
+				}
+				found = &(*drafts)[i]
+			}
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("draft not found: %s", prefix)
+	}
+	return found, nil
+}
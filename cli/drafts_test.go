@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+func testDrafts() []domain.Draft {
+	now := time.Now()
+	return []domain.Draft{
+		{Id: uuid.MustParse("1a2b3c4d-0000-4000-8000-000000000001"), Message: "half a thought", UpdatedAt: now},
+		{
+			Id: uuid.MustParse("1a2b9999-0000-4000-8000-000000000002"), Message: "@bob agreed",
+			InReplyToURI: "https://remote.example/notes/1", ReplyToAuthor: "@bob@remote.example", UpdatedAt: now.Add(-time.Hour),
+		},
+	}
+}
+
+func TestDrafts_List(t *testing.T) {
+	db := &mockDatabase{drafts: testDrafts()}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"drafts"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	result := output.String()
+	if !strings.Contains(result, "half a thought") || !strings.Contains(result, "reply to @bob@remote.example") {
+		t.Errorf("Expected drafts with reply context, got: %s", result)
+	}
+}
+
+func TestDrafts_ListJSON(t *testing.T) {
+	db := &mockDatabase{drafts: testDrafts()}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"drafts", "list", "-j"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var resp DraftsResponse
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Expected valid JSON, got error: %v, output: %s", err, output.String())
+	}
+	if resp.Count != 2 || resp.Drafts[1].InReplyToURI != "https://remote.example/notes/1" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestDrafts_ListEmpty(t *testing.T) {
+	handler, output := newTestHandlerWithDB("", &mockDatabase{})
+
+	if err := handler.Execute([]string{"drafts"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(output.String(), "No drafts.") {
+		t.Errorf("Expected 'No drafts.', got: %s", output.String())
+	}
+}
+
+func TestDrafts_Publish(t *testing.T) {
+	db := &mockDatabase{drafts: testDrafts()}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"drafts", "publish", "1a2b9"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(db.publishedDrafts) != 1 || db.publishedDrafts[0].InReplyToURI != "https://remote.example/notes/1" {
+		t.Fatalf("Expected the reply draft to be published, got %+v", db.publishedDrafts)
+	}
+	if !strings.Contains(output.String(), "Posted:") {
+		t.Errorf("Expected 'Posted:' in output, got: %s", output.String())
+	}
+}
+
+func TestDrafts_PublishErrors(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"drafts", "publish"}, "usage"},
+		{[]string{"drafts", "publish", "1a2b"}, "ambiguous"},
+		{[]string{"drafts", "publish", "ffff"}, "not found"},
+		{[]string{"drafts", "send", "1a2b"}, "unknown drafts action"},
+	}
+	for _, tt := range tests {
+		db := &mockDatabase{drafts: testDrafts()}
+		handler, _ := newTestHandlerWithDB("", db)
+
+		err := handler.Execute(tt.args)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: expected error containing %q, got %v", tt.args, tt.want, err)
+		}
+		if len(db.publishedDrafts) != 0 {
+			t.Errorf("%v: expected nothing to be published", tt.args)
+		}
+	}
+}
+
+func TestDrafts_PublishTooLong(t *testing.T) {
+	drafts := testDrafts()
+	drafts[0].Message = strings.Repeat("a", 200)
+	db := &mockDatabase{drafts: drafts}
+	handler, _ := newTestHandlerWithDB("", db)
+
+	err := handler.Execute([]string{"drafts", "publish", drafts[0].Id.String()})
+	if err == nil || !strings.Contains(err.Error(), "too long") {
+		t.Errorf("Expected 'too long' error, got: %v", err)
+	}
+	if len(db.publishedDrafts) != 0 {
+		t.Error("Expected nothing to be published")
+	}
+}
+
+func TestDrafts_PublishArticle(t *testing.T) {
+	drafts := testDrafts()
+	drafts[0].Title = "Field Notes"
+	drafts[0].Message = strings.Repeat("A long paragraph. ", 50)
+	db := &mockDatabase{drafts: drafts}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"drafts", "publish", "1a2b3c"}); err != nil {
+		t.Fatalf("Expected article draft beyond the note limit to publish, got: %v", err)
+	}
+	if len(db.publishedDrafts) != 1 || db.publishedDrafts[0].Title != "Field Notes" {
+		t.Fatalf("Expected the article draft to be published, got %+v", db.publishedDrafts)
+	}
+	if !strings.Contains(output.String(), "Posted:") {
+		t.Errorf("Expected 'Posted:' in output, got: %s", output.String())
+	}
+}
@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
+	"github.com/google/uuid"
+)
+
+const (
+	// followHeartbeatInterval is how often a --follow stream writes a heartbeat,
+	// so clients can tell a quiet stream from a stalled one
+	followHeartbeatInterval = 30 * time.Second
+	// followPageSize is how many items a --follow stream reads per query while it pages
+	// back to the last item it wrote
+	followPageSize = 50
+)
+
+// context returns the lifetime of the session, which ends when the client disconnects
+func (h *Handler) context() context.Context {
+	if h.ctx == nil {
+		return context.Background()
+	}
+	return h.ctx
+}
+
+// follow keeps the session open until the client disconnects. It calls poll shortly
+// after each event for which relevant is true, and writes a heartbeat in between.
+func (h *Handler) follow(sub *events.Subscription, relevant func(events.Event) bool, poll func() error) error {
+	interval := h.heartbeat
+	if interval <= 0 {
+		interval = followHeartbeatInterval
+	}
+	heartbeat := time.NewTicker(interval)
+	defer heartbeat.Stop()
+
+	// A burst of events causes a single poll, like the TUI's reloads
+	var reload <-chan time.Time
+	for {
+		select {
+		case <-h.context().Done():
+			return nil
+		case e, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+			if reload == nil && relevant(e) {
+				reload = time.After(events.ReloadDelay)
+			}
+		case <-reload:
+			reload = nil
+			// A failed read is reported but does not end the stream
+			if err := poll(); err != nil {
+				h.output.Error(err)
+			}
+		case t := <-heartbeat.C:
+			h.writeStreamItem(StreamItem{Type: "heartbeat", Time: t.UTC()}, "heartbeat "+t.UTC().Format(time.RFC3339))
+		}
+	}
+}
+
+// subscribeEvents subscribes to the event bus, or to the handler's own bus in tests
+func (h *Handler) subscribeEvents() *events.Subscription {
+	if h.subscribe != nil {
+		return h.subscribe()
+	}
+	return events.Subscribe()
+}
+
+// writeStreamItem writes one stream item: a JSON line in JSON mode, otherwise text
+func (h *Handler) writeStreamItem(item StreamItem, text string) {
+	if h.output.IsJSON() {
+		h.output.JSONLine(item)
+	} else {
+		h.output.Println(text)
+	}
+}
+
+// oneLine collapses text onto a single line for stream output
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// readSince reads the items newer than last, newest first. It pages back with the cursor
+// until it reaches last, so a burst of more than one page is streamed in full.
+// With a zero last, which means nothing was there before, every item is new.
+func readSince[T any](read func(before domain.Cursor, limit int) (error, []T), last domain.Cursor, cursor func(T) domain.Cursor) (error, []T) {
+	var fresh []T
+	before := domain.Cursor{}
+	for {
+		err, page := read(before, followPageSize)
+		if err != nil {
+			return err, nil
+		}
+		for _, item := range page {
+			if !last.IsZero() && !cursor(item).After(last) {
+				return nil, fresh
+			}
+			fresh = append(fresh, item)
+		}
+		if len(page) < followPageSize {
+			return nil, fresh
+		}
+		before = cursor(page[len(page)-1])
+	}
+}
+
+// newest returns the cursor of the first of newest-first items, or last if there are none
+func newest[T any](items []T, last domain.Cursor, cursor func(T) domain.Cursor) domain.Cursor {
+	if len(items) == 0 {
+		return last
+	}
+	return cursor(items[0])
+}
+
+// followTimeline streams the home timeline, or a list timeline when listId is set.
+// It starts with the newest backlog posts, oldest first.
+func (h *Handler) followTimeline(listId uuid.UUID, backlog int) error {
+	sub := h.subscribeEvents()
+	defer sub.Close()
+
+	read := func(before domain.Cursor, limit int) (error, []domain.HomePost) {
+		var err error
+		var posts *[]domain.HomePost
+		if listId != uuid.Nil {
+			err, posts = h.db.ReadListTimelinePosts(h.account.Id, listId, before, limit)
+		} else {
+			err, posts = h.db.ReadHomeTimelinePosts(h.account.Id, before, limit)
+		}
+		if err != nil || posts == nil {
+			return err, nil
+		}
+		return nil, *posts
+	}
+	cursor := func(p domain.HomePost) domain.Cursor { return p.Cursor() }
+	// Like the TUI, only events of followed accounts reload the timeline;
+	// the follows are read again with every reload
+	readFollows := func() *events.Follows {
+		err, follows := h.db.ReadFollows(h.account.Id)
+		if err != nil {
+			h.output.Error(err)
+			return nil
+		}
+		return follows
+	}
+
+	err, page := read(domain.Cursor{}, max(backlog, 1))
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+	last := newest(page, domain.Cursor{}, cursor)
+	if err := h.writePosts(page[:min(backlog, len(page))]); err != nil {
+		h.output.Error(err)
+		return err
+	}
+	follows := readFollows()
+
+	return h.follow(sub, func(e events.Event) bool {
+		return events.ChangesHomeTimeline(e, h.account.Id, follows)
+	}, func() error {
+		follows = readFollows()
+		err, fresh := readSince(read, last, cursor)
+		if err != nil {
+			return err
+		}
+		last = newest(fresh, last, cursor)
+		return h.writePosts(fresh)
+	})
+}
+
+// writePosts writes newest-first posts to the stream oldest first, applying keyword filters
+func (h *Handler) writePosts(posts []domain.HomePost) error {
+	if len(posts) == 0 {
+		return nil
+	}
+	err, filters := h.db.ReadFiltersByAccountId(h.account.Id)
+	if err != nil {
+		return err
+	}
+	posts = domain.FilterHomePosts(posts, *filters, domain.FilterContextHome, time.Now())
+	for i := len(posts) - 1; i >= 0; i-- {
+		post := posts[i]
+		item := toTimelinePost(post)
+		content := item.Message
+		if post.FilterWarning != "" {
+			content = "⚠ filtered: " + post.FilterWarning
+		}
+		h.writeStreamItem(
+			StreamItem{Type: "post", ID: item.ID, Time: post.Time, Post: &item},
+			"post "+item.ID+" "+post.Author+": "+oneLine(content),
+		)
+	}
+	return nil
+}
+
+// followNotifications streams the notifications of the account.
+// It starts with the newest backlog notifications, oldest first.
+func (h *Handler) followNotifications(backlog int) error {
+	sub := h.subscribeEvents()
+	defer sub.Close()
+
+	read := func(before domain.Cursor, limit int) (error, []domain.Notification) {
+		err, notifications := h.db.ReadNotificationsByAccountId(h.account.Id, before, limit)
+		if err != nil || notifications == nil {
+			return err, nil
+		}
+		return nil, *notifications
+	}
+	cursor := func(n domain.Notification) domain.Cursor { return n.Cursor() }
+
+	err, page := read(domain.Cursor{}, max(backlog, 1))
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+	last := newest(page, domain.Cursor{}, cursor)
+	h.writeNotifications(page[:min(backlog, len(page))])
+
+	return h.follow(sub, func(e events.Event) bool {
+		return e.Kind == events.NotificationCreated && e.AccountId == h.account.Id
+	}, func() error {
+		err, fresh := readSince(read, last, cursor)
+		if err != nil {
+			return err
+		}
+		last = newest(fresh, last, cursor)
+		h.writeNotifications(fresh)
+		return nil
+	})
+}
+
+// writeNotifications writes newest-first notifications to the stream oldest first
+func (h *Handler) writeNotifications(notifications []domain.Notification) {
+	for i := len(notifications) - 1; i >= 0; i-- {
+		n := notifications[i]
+		item := toNotificationItem(n)
+		text := "notification " + item.ID + " " + n.Summary()
+		if item.NotePreview != "" {
+			text += ": " + oneLine(item.NotePreview)
+		}
+		h.writeStreamItem(StreamItem{Type: "notification", ID: item.ID, Time: n.CreatedAt, Notification: &item}, text)
+	}
+}
@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+// streamSession is a session whose output can be read while a stream writes to it
+type streamSession struct {
+	mu  sync.Mutex
+	out bytes.Buffer
+}
+
+func (s *streamSession) Read(p []byte) (int, error) {
+	return 0, nil
+}
+
+func (s *streamSession) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.out.Write(p)
+}
+
+func (s *streamSession) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.out.String()
+}
+
+// waitFor waits until the output contains text
+func (s *streamSession) waitFor(t *testing.T, text string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(s.String(), text) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %q in output:\n%s", text, s.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// startFollow runs a --follow command in the background and returns a function that
+// disconnects the client and waits for the command to end
+func startFollow(t *testing.T, db *mockDatabase, accountId uuid.UUID, bus *events.Bus, args []string) (*streamSession, func() error) {
+	t.Helper()
+	session := &streamSession{}
+	ctx, cancel := context.WithCancel(context.Background())
+	conf := &util.AppConfig{}
+	handler := &Handler{
+		session:   session,
+		db:        db,
+		account:   &domain.Account{Id: accountId, Username: "testuser"},
+		conf:      conf,
+		ctx:       ctx,
+		subscribe: bus.Subscribe,
+		heartbeat: 20 * time.Millisecond,
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- handler.Execute(args)
+	}()
+	return session, func() error {
+		cancel()
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("Stream did not end after the client disconnected")
+			return nil
+		}
+	}
+}
+
+func TestTimelineFollow_StreamsNewPostsAsJSONLines(t *testing.T) {
+	now := time.Now()
+	oldId := uuid.New()
+	db := &mockDatabase{notes: []domain.HomePost{
+		{ID: oldId, Author: "@alice", Content: "Older post", Time: now.Add(-time.Minute)},
+	}}
+	bus := events.NewBus()
+	accountId := uuid.New()
+
+	session, stop := startFollow(t, db, accountId, bus, []string{"timeline", "--follow", "-j"})
+	session.waitFor(t, oldId.String())
+
+	// A new post shows up after an event that changes the timeline
+	newId := uuid.New()
+	db.notes = append([]domain.HomePost{{ID: newId, Author: "@bob@remote.example", Content: "<p>New\npost</p>", Time: now}}, db.notes...)
+	bus.Publish(events.Event{Kind: events.ActivityReceived, ActivityType: "Create"})
+	session.waitFor(t, newId.String())
+	session.waitFor(t, `"heartbeat"`)
+
+	if err := stop(); err != nil {
+		t.Fatalf("Expected no error after disconnect, got: %v", err)
+	}
+
+	var posts []StreamItem
+	for _, line := range strings.Split(strings.TrimSpace(session.String()), "\n") {
+		var item StreamItem
+		if err := json.Unmarshal([]byte(line), &item); err != nil {
+			t.Fatalf("Expected one JSON object per line, got %q: %v", line, err)
+		}
+		if item.Type == "post" {
+			posts = append(posts, item)
+		}
+	}
+	if len(posts) != 2 {
+		t.Fatalf("Expected the backlog post and the new post once each, got %d", len(posts))
+	}
+	if posts[0].ID != oldId.String() || posts[1].ID != newId.String() {
+		t.Errorf("Expected posts oldest first, got %s then %s", posts[0].ID, posts[1].ID)
+	}
+	if posts[1].Post == nil || posts[1].Post.Author != "bob" || posts[1].Post.Domain != "remote.example" {
+		t.Errorf("Expected the post payload, got %+v", posts[1].Post)
+	}
+}
+
+func TestTimelineFollow_NoBacklogWithZeroCount(t *testing.T) {
+	oldId := uuid.New()
+	db := &mockDatabase{notes: []domain.HomePost{{ID: oldId, Author: "@alice", Content: "Older post", Time: time.Now()}}}
+	bus := events.NewBus()
+
+	session, stop := startFollow(t, db, uuid.New(), bus, []string{"timeline", "-n", "0", "--follow"})
+	session.waitFor(t, "heartbeat ")
+	stop()
+
+	if strings.Contains(session.String(), oldId.String()) {
+		t.Errorf("Expected no backlog with -n 0, got:\n%s", session.String())
+	}
+}
+
+func TestTimelineFollow_RejectsBefore(t *testing.T) {
+	handler, output := newTestHandler("")
+	c := domain.Cursor{CreatedAt: time.Now(), Id: uuid.New()}
+
+	if err := handler.Execute([]string{"timeline", "--follow", "--before", c.String()}); err == nil {
+		t.Fatal("Expected an error for --before with --follow")
+	}
+	if !strings.Contains(output.String(), "--before cannot be used with --follow") {
+		t.Errorf("Expected error message, got: %s", output.String())
+	}
+}
+
+func TestTimeline_ZeroCountNeedsFollow(t *testing.T) {
+	handler, _ := newTestHandler("")
+	if err := handler.Execute([]string{"timeline", "-n", "0"}); err == nil {
+		t.Error("Expected an error for -n 0 without --follow")
+	}
+}
+
+func TestNotificationsFollow_StreamsOwnNotificationsAsText(t *testing.T) {
+	accountId := uuid.New()
+	db := &mockDatabase{}
+	bus := events.NewBus()
+
+	session, stop := startFollow(t, db, accountId, bus, []string{"notifications", "--follow"})
+	session.waitFor(t, "heartbeat ")
+
+	notif := domain.Notification{
+		Id:               uuid.New(),
+		AccountId:        accountId,
+		NotificationType: domain.NotificationLike,
+		ActorUsername:    "bob",
+		ActorDomain:      "remote.example",
+		NotePreview:      "Hello\nworld",
+		CreatedAt:        time.Now(),
+	}
+	db.notifications = []domain.Notification{notif}
+	bus.Publish(events.Event{Kind: events.NotificationCreated, AccountId: accountId})
+	session.waitFor(t, "notification "+notif.Id.String())
+	stop()
+
+	out := session.String()
+	if !strings.Contains(out, "@bob@remote.example") || !strings.Contains(out, ": Hello world") {
+		t.Errorf("Expected the notification on one line, got:\n%s", out)
+	}
+	if strings.Count(out, notif.Id.String()) != 1 {
+		t.Errorf("Expected the notification once, got:\n%s", out)
+	}
+}
+
+func TestTimelineFollow_StreamsBurstLargerThanOnePage(t *testing.T) {
+	now := time.Now()
+	oldId := uuid.New()
+	db := &mockDatabase{notes: []domain.HomePost{
+		{ID: oldId, Author: "@alice", Content: "Older post", Time: now.Add(-time.Hour)},
+	}}
+	bus := events.NewBus()
+
+	session, stop := startFollow(t, db, uuid.New(), bus, []string{"timeline", "-n", "1", "--follow"})
+	session.waitFor(t, oldId.String())
+
+	burst := followPageSize*2 + 5
+	fresh := make([]domain.HomePost, 0, burst)
+	for i := burst; i > 0; i-- {
+		fresh = append(fresh, domain.HomePost{ID: uuid.New(), Author: "@alice", Content: "New post", Time: now.Add(-time.Duration(i) * time.Second)})
+	}
+	// Newest first, like the database
+	for i, j := 0, len(fresh)-1; i < j; i, j = i+1, j-1 {
+		fresh[i], fresh[j] = fresh[j], fresh[i]
+	}
+	db.notes = append(fresh, db.notes...)
+	bus.Publish(events.Event{Kind: events.NoteCreated})
+	session.waitFor(t, fresh[0].ID.String())
+	stop()
+
+	out := session.String()
+	for _, post := range fresh {
+		if strings.Count(out, post.ID.String()) != 1 {
+			t.Fatalf("Expected every post of the burst once, missing %s", post.ID)
+		}
+	}
+	if strings.Count(out, oldId.String()) != 1 {
+		t.Errorf("Expected the backlog post once, got:\n%s", out)
+	}
+}
+
+func TestTimelineFollow_IgnoresPostsOfUnfollowedAccounts(t *testing.T) {
+	accountId := uuid.New()
+	followed := uuid.New()
+	db := &mockDatabase{follows: &events.Follows{Accounts: map[uuid.UUID]bool{followed: true}}}
+	bus := events.NewBus()
+
+	session, stop := startFollow(t, db, accountId, bus, []string{"timeline", "-n", "0", "--follow"})
+	session.waitFor(t, "heartbeat ")
+
+	// Only an event of a followed account reads the timeline
+	strangerPost := domain.HomePost{ID: uuid.New(), Author: "@carol@remote.example", Content: "Not followed", Time: time.Now()}
+	db.notes = []domain.HomePost{strangerPost}
+	bus.Publish(events.Event{Kind: events.ActivityReceived, ActivityType: "Create", ActorId: uuid.New()})
+	time.Sleep(events.ReloadDelay * 3)
+	if strings.Contains(session.String(), strangerPost.ID.String()) {
+		t.Fatalf("Expected no reload for an account that is not followed, got:\n%s", session.String())
+	}
+
+	bus.Publish(events.Event{Kind: events.ActivityReceived, ActivityType: "Create", ActorId: followed})
+	session.waitFor(t, strangerPost.ID.String())
+	stop()
+}
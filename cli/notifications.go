@@ -1,6 +1,10 @@
 package cli
 
 import (
+	"fmt"
+	"strconv"
+
+	"github.com/deemkeen/stegodon/domain"
 	"github.com/deemkeen/stegodon/util"
 )
 
@@ -26,8 +30,43 @@ func (h *Handler) handleClearNotifications(args []string) error {
 	return nil
 }
 
-// handleNotifications shows unread notifications
+// handleNotifications shows unread notifications.
+// --follow keeps streaming new notifications until the client disconnects.
 func (h *Handler) handleNotifications(args []string) error {
+	limit := defaultNotificationsLimit
+	follow := false
+
+	// Parse -n and --follow flags
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--follow", "-f":
+			follow = true
+		case "-n":
+			if i+1 >= len(args) {
+				err := fmt.Errorf("usage: notifications [-n <count>] [--follow]")
+				h.output.Error(err)
+				return err
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 0 {
+				err = fmt.Errorf("invalid value for -n: %s", args[i+1])
+				h.output.Error(err)
+				return err
+			}
+			limit = n
+			i++ // Skip the number
+		}
+	}
+	// -n 0 only makes sense for a stream, which then starts with new notifications
+	if limit == 0 && !follow {
+		err := fmt.Errorf("-n must be at least 1")
+		h.output.Error(err)
+		return err
+	}
+	if follow {
+		return h.followNotifications(limit)
+	}
+
 	// Get unread count
 	unreadCount, err := h.db.CountUnreadNotifications(h.account.Id)
 	if err != nil {
@@ -36,7 +75,7 @@ func (h *Handler) handleNotifications(args []string) error {
 	}
 
 	// Read notifications
-	err, notifications := h.db.ReadNotificationsByAccountId(h.account.Id, defaultNotificationsLimit)
+	err, notifications := h.db.ReadNotificationsByAccountId(h.account.Id, domain.Cursor{}, limit)
 	if err != nil {
 		h.output.Error(err)
 		return err
@@ -58,19 +97,7 @@ func (h *Handler) handleNotifications(args []string) error {
 	if h.output.IsJSON() {
 		items := make([]NotificationItem, 0, len(*notifications))
 		for _, n := range *notifications {
-			// Strip HTML tags from preview
-			preview := ""
-			if n.NotePreview != "" {
-				preview = util.StripHTMLTags(n.NotePreview)
-			}
-
-			items = append(items, NotificationItem{
-				ID:          n.Id.String(),
-				Type:        string(n.NotificationType),
-				Actor:       n.ActorHandle(),
-				NotePreview: preview,
-				CreatedAt:   n.CreatedAt,
-			})
+			items = append(items, toNotificationItem(n))
 		}
 
 		h.output.JSON(NotificationsResponse{
@@ -95,3 +122,19 @@ func (h *Handler) handleNotifications(args []string) error {
 
 	return nil
 }
+
+// toNotificationItem converts a notification for JSON output
+func toNotificationItem(n domain.Notification) NotificationItem {
+	// Strip HTML tags from preview
+	preview := ""
+	if n.NotePreview != "" {
+		preview = util.StripHTMLTags(n.NotePreview)
+	}
+	return NotificationItem{
+		ID:          n.Id.String(),
+		Type:        string(n.NotificationType),
+		Actor:       n.ActorHandle(),
+		NotePreview: preview,
+		CreatedAt:   n.CreatedAt,
+	}
+}
@@ -77,6 +77,19 @@ func (o *Output) JSON(v interface{}) {
 	}
 }
 
+// JSONLine outputs a value as a single line of JSON, for newline-delimited streams
+func (o *Output) JSONLine(v interface{}) {
+	if !o.jsonMode {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		fmt.Fprintf(o.writer, `{"error":"failed to marshal JSON: %s"}`+"\n", err.Error())
+		return
+	}
+	fmt.Fprintln(o.writer, string(data))
+}
+
 // writeJSON marshals and writes JSON to the output
 func (o *Output) writeJSON(v interface{}) {
 	data, err := json.MarshalIndent(v, "", "  ")
@@ -91,10 +104,55 @@ func (o *Output) writeJSON(v interface{}) {
 // PostResponse represents a post creation response
 type PostResponse struct {
 	ID        string    `json:"id"`
+	Title     string    `json:"title,omitempty"`       // Set for articles
+	InReplyTo string    `json:"in_reply_to,omitempty"` // Set for replies: the id or URI given to reply
 	Message   string    `json:"message"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// PostActionResponse represents the result of like, unlike, boost, unboost, edit or delete
+type PostActionResponse struct {
+	Status string    `json:"status"`
+	Action string    `json:"action"`
+	Post   string    `json:"post"` // The id or URI the action was given
+	DoneAt time.Time `json:"done_at"`
+}
+
+// PostThreadResponse represents the thread output
+type PostThreadResponse struct {
+	Ancestors []TimelinePost `json:"ancestors"` // Oldest first
+	Post      TimelinePost   `json:"post"`
+	Replies   []TimelinePost `json:"replies"` // Direct replies first
+}
+
+// ThreadResponse represents the notes created for a thread, in order
+type ThreadResponse struct {
+	Notes []PostResponse `json:"notes"`
+}
+
+// ScheduledPostResponse represents a scheduled post creation response
+type ScheduledPostResponse struct {
+	ID          string    `json:"id"`
+	Message     string    `json:"message"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+// DraftItem represents a draft in drafts output
+type DraftItem struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title,omitempty"` // Set for article drafts
+	Message      string    `json:"message"`
+	InReplyToURI string    `json:"in_reply_to_uri,omitempty"`
+	ReplyTo      string    `json:"reply_to,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DraftsResponse represents the drafts output
+type DraftsResponse struct {
+	Drafts []DraftItem `json:"drafts"`
+	Count  int         `json:"count"`
+}
+
 // TimelinePost represents a post in timeline output
 type TimelinePost struct {
 	ID         string    `json:"id"`
@@ -105,12 +163,27 @@ type TimelinePost struct {
 	ReplyCount int       `json:"reply_count"`
 	LikeCount  int       `json:"like_count"`
 	BoostCount int       `json:"boost_count"`
+	ViaHashtag string    `json:"via_hashtag,omitempty"`
+	// Phrase of the keyword filter the post matched; clients should collapse the post
+	FilterWarning string `json:"filter_warning,omitempty"`
 }
 
 // TimelineResponse represents the timeline output
 type TimelineResponse struct {
 	Posts []TimelinePost `json:"posts"`
 	Count int            `json:"count"`
+	List  string         `json:"list,omitempty"` // set for list timelines
+
+	NextCursor string `json:"next_cursor,omitempty"` // pass to --before for the next page; empty on the last page
+}
+
+// StreamItem is one line of a --follow stream in JSON mode
+type StreamItem struct {
+	Type         string            `json:"type"`         // "post", "notification" or "heartbeat"
+	ID           string            `json:"id,omitempty"` // id of the post or notification
+	Time         time.Time         `json:"time"`
+	Post         *TimelinePost     `json:"post,omitempty"`
+	Notification *NotificationItem `json:"notification,omitempty"`
 }
 
 // NotificationItem represents a notification in output
@@ -134,6 +207,164 @@ type ClearNotificationsResponse struct {
 	Cleared bool   `json:"cleared"`
 }
 
+// TagsResponse represents the followed hashtags output
+type TagsResponse struct {
+	Tags  []string `json:"tags"`
+	Count int      `json:"count"`
+}
+
+// TagActionResponse represents the tags follow/unfollow output
+type TagActionResponse struct {
+	Status    string `json:"status"`
+	Tag       string `json:"tag"`
+	Following bool   `json:"following"`
+}
+
+// ResolveResponse represents a resolved post or account
+type ResolveResponse struct {
+	Type        string     `json:"type"` // "post" or "account"
+	ID          string     `json:"id"`
+	URI         string     `json:"uri,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	Author      string     `json:"author"`
+	DisplayName string     `json:"display_name,omitempty"`
+	Content     string     `json:"content,omitempty"` // post text or account bio
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+}
+
+// AccountItem represents an account in followers and following output
+type AccountItem struct {
+	ID          string `json:"id"`
+	Handle      string `json:"handle"` // @user or @user@domain
+	DisplayName string `json:"display_name,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// AccountsResponse represents the followers and following output
+type AccountsResponse struct {
+	Accounts []AccountItem `json:"accounts"`
+	Count    int           `json:"count"`
+}
+
+// ProfileResponse represents the profile and whoami output
+type ProfileResponse struct {
+	ID          string     `json:"id"`
+	Handle      string     `json:"handle"` // @user or @user@domain
+	DisplayName string     `json:"display_name,omitempty"`
+	Bio         string     `json:"bio,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"` // Local accounts only
+	// Counts are only set for local accounts
+	Followers *int `json:"followers,omitempty"`
+	Following *int `json:"following,omitempty"`
+	Posts     *int `json:"posts,omitempty"`
+	// Relationship to you, false on your own profile
+	IsFollowing bool `json:"is_following"`
+	Requested   bool `json:"requested"` // Your follow request is pending
+	FollowsYou  bool `json:"follows_you"`
+}
+
+// FollowResponse represents the follow and unfollow output
+type FollowResponse struct {
+	Status    string `json:"status"`
+	Account   string `json:"account"` // The handle given
+	Following bool   `json:"following"`
+	Pending   bool   `json:"pending"` // The remote server has not accepted the follow yet
+}
+
+// ProfileUpdateResponse represents the set-bio and set-name output
+type ProfileUpdateResponse struct {
+	Status string `json:"status"`
+	Field  string `json:"field"` // "bio" or "display_name"
+	Value  string `json:"value"`
+}
+
+// AdminUserItem represents an account in admin user list output
+type AdminUserItem struct {
+	ID          string    `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name,omitempty"`
+	IsAdmin     bool      `json:"is_admin"`
+	Muted       bool      `json:"muted"`
+	Banned      bool      `json:"banned"`
+	FirstLogin  bool      `json:"first_login"` // has not logged in yet
+	LastIP      string    `json:"last_ip,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AdminUsersResponse represents the admin user list output
+type AdminUsersResponse struct {
+	Users []AdminUserItem `json:"users"`
+	Count int             `json:"count"`
+}
+
+// AdminRelayItem represents a relay in admin relay list output
+type AdminRelayItem struct {
+	ID        string    `json:"id"`
+	ActorURI  string    `json:"actor_uri"`
+	Name      string    `json:"name,omitempty"`
+	Status    string    `json:"status"`
+	Paused    bool      `json:"paused"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AdminRelaysResponse represents the admin relay list output
+type AdminRelaysResponse struct {
+	Relays []AdminRelayItem `json:"relays"`
+	Count  int              `json:"count"`
+}
+
+// AdminQueueItem represents a queued delivery in admin queue list output
+type AdminQueueItem struct {
+	ID           string    `json:"id"`
+	InboxURI     string    `json:"inbox_uri"`
+	ActivityType string    `json:"activity_type"`
+	Attempts     int       `json:"attempts"`
+	NextRetryAt  time.Time `json:"next_retry_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AdminQueueResponse represents the admin queue list output
+type AdminQueueResponse struct {
+	Deliveries   []AdminQueueItem `json:"deliveries"`
+	Count        int              `json:"count"`
+	InboxPending int              `json:"inbox_pending"`
+	InboxHeld    int              `json:"inbox_held"`
+}
+
+// AdminQueuePurgeResponse represents the admin queue purge output
+type AdminQueuePurgeResponse struct {
+	Purged int64 `json:"purged"`
+}
+
+// AdminActionResponse represents the result of an admin change to a user or relay
+type AdminActionResponse struct {
+	Status string    `json:"status"`
+	Target string    `json:"target"`
+	DoneAt time.Time `json:"done_at"`
+}
+
+// MigrationStatusResponse represents the admin migrate output
+type MigrationStatusResponse struct {
+	UpToDate       bool            `json:"up_to_date"`
+	JournalMode    string          `json:"journal_mode"`
+	Tables         int             `json:"tables"`
+	MissingTables  []string        `json:"missing_tables"`
+	MissingColumns []string        `json:"missing_columns"`
+	Migrations     []MigrationItem `json:"migrations"`
+	Applied        []int           `json:"applied,omitempty"` // Versions applied by this run
+}
+
+// MigrationItem represents a numbered schema migration in the admin migrate output
+type MigrationItem struct {
+	Version    int        `json:"version"`
+	Name       string     `json:"name"`
+	Applied    bool       `json:"applied"`
+	AppliedAt  *time.Time `json:"applied_at,omitempty"`
+	Reversible bool       `json:"reversible"`
+	Modified   bool       `json:"modified,omitempty"`
+}
+
 // HelpCommand represents a command in help output
 type HelpCommand struct {
 	Name        string   `json:"name"`
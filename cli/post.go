@@ -8,52 +8,61 @@ import (
 	"time"
 
 	"github.com/deemkeen/stegodon/activitypub"
+	"github.com/deemkeen/stegodon/domain"
 	"github.com/deemkeen/stegodon/util"
 	"github.com/google/uuid"
 )
 
-// handlePost creates a new note
+// handlePost creates a new note, schedules it with --at <time>,
+// publishes a long-form article with --article or splits long text into a thread with --thread
 func (h *Handler) handlePost(args []string) error {
-	var message string
+	args, flags, err := parsePostFlags(args)
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
 
 	if len(args) == 0 {
-		err := fmt.Errorf("usage: post <message> or post -")
+		err := fmt.Errorf("usage: post [--at <time>] <message|->, post --thread <text|->, or post --article [--title <title>] <markdown|->")
 		h.output.Error(err)
 		return err
 	}
 
-	if args[0] == "-" {
-		// Read from stdin
-		data, err := io.ReadAll(h.session)
-		if err != nil {
+	message, err := h.readMessage(args)
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	if flags.article {
+		if flags.thread {
+			err := fmt.Errorf("--thread cannot be used with --article")
 			h.output.Error(err)
 			return err
 		}
-		message = strings.TrimSpace(string(data))
-	} else {
-		message = strings.Join(args, " ")
+		if flags.at != "" {
+			err := fmt.Errorf("--at cannot be used with --article")
+			h.output.Error(err)
+			return err
+		}
+		return h.postArticle(message, flags.title)
 	}
 
-	// Validate message not empty
-	if message == "" {
-		err := fmt.Errorf("message cannot be empty")
-		h.output.Error(err)
-		return err
+	if flags.thread {
+		if flags.at != "" {
+			err := fmt.Errorf("--at cannot be used with --thread")
+			h.output.Error(err)
+			return err
+		}
+		return h.postThread(message)
 	}
 
-	// Validate visible character count
-	visibleChars := util.CountVisibleChars(message)
-	maxChars := h.conf.Conf.MaxChars
-	if visibleChars > maxChars {
-		err := fmt.Errorf("message too long (%d chars, max %d)", visibleChars, maxChars)
-		h.output.ErrorWithDetails("message too long", fmt.Sprintf("%d chars, max %d", visibleChars, maxChars))
+	if err := h.validateNote(message); err != nil {
 		return err
 	}
 
-	// Validate total note length (including markdown syntax)
-	if err := util.ValidateNoteLength(message); err != nil {
-		h.output.Error(err)
-		return err
+	if flags.at != "" {
+		return h.schedulePost(message, flags.at)
 	}
 
 	// Create the note
@@ -62,28 +71,10 @@ func (h *Handler) handlePost(args []string) error {
 		h.output.Error(err)
 		return err
 	}
+	h.db.NoteCreated(h.account, noteId, message, "")
 
 	// Federate the note via ActivityPub (background task)
-	go func() {
-		// Only federate if ActivityPub is enabled
-		if !h.conf.Conf.WithAp {
-			return
-		}
-
-		// Get the created note from database
-		err, createdNote := h.db.ReadNoteIdWithReplyInfo(noteId)
-		if err != nil {
-			log.Printf("CLI: Failed to read created note for federation: %v", err)
-			return
-		}
-
-		// Send Create activity to all followers
-		if err := activitypub.SendCreate(createdNote, h.account, h.conf); err != nil {
-			log.Printf("CLI: Failed to federate note: %v", err)
-		} else {
-			log.Printf("CLI: Note federated successfully for %s", h.account.Username)
-		}
-	}()
+	go h.federateNote(noteId)
 
 	// Output response
 	if h.output.IsJSON() {
@@ -106,3 +97,231 @@ func (h *Handler) handlePost(args []string) error {
 
 	return nil
 }
+
+// readMessage returns the message given as arguments, or read from stdin for "-"
+func (h *Handler) readMessage(args []string) (string, error) {
+	message, err := h.readText(args)
+	if err == nil && message == "" {
+		err = fmt.Errorf("message cannot be empty")
+	}
+	return message, err
+}
+
+// readText returns the text given as arguments, or read from stdin for "-"
+func (h *Handler) readText(args []string) (string, error) {
+	if len(args) > 0 && args[0] == "-" {
+		data, err := io.ReadAll(h.session)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return strings.Join(args, " "), nil
+}
+
+// validateNote checks the length limits of a note, writing any error to the output
+func (h *Handler) validateNote(message string) error {
+	// Validate visible character count
+	visibleChars := util.CountVisibleChars(message)
+	maxChars := h.conf.Conf.MaxChars
+	if visibleChars > maxChars {
+		err := fmt.Errorf("message too long (%d chars, max %d)", visibleChars, maxChars)
+		h.output.ErrorWithDetails("message too long", fmt.Sprintf("%d chars, max %d", visibleChars, maxChars))
+		return err
+	}
+
+	// Validate total note length (including markdown syntax)
+	if err := util.ValidateNoteLength(message); err != nil {
+		h.output.Error(err)
+		return err
+	}
+	return nil
+}
+
+// federateNote sends the Create activity for a new note to all followers
+func (h *Handler) federateNote(noteId interface{}) {
+	// Only federate if ActivityPub is enabled
+	if !h.conf.Conf.WithAp {
+		return
+	}
+
+	// Get the created note from database
+	err, createdNote := h.db.ReadNoteIdWithReplyInfo(noteId)
+	if err != nil {
+		log.Printf("CLI: Failed to read created note for federation: %v", err)
+		return
+	}
+
+	// Send Create activity to all followers
+	if err := activitypub.SendCreate(createdNote, h.account, h.conf); err != nil {
+		log.Printf("CLI: Failed to federate note: %v", err)
+	} else {
+		log.Printf("CLI: Note federated successfully for %s", h.account.Username)
+	}
+}
+
+// postFlags are the options of the post command
+type postFlags struct {
+	at      string // publish later at this time (--at)
+	article bool   // publish a long-form article (--article)
+	title   string // article title (--title, implies --article); defaults to the first line
+	thread  bool   // split long text into a thread of notes (--thread)
+}
+
+// parsePostFlags extracts "--at <time>", "--article", "--title <title>" and "--thread" from the post arguments
+func parsePostFlags(args []string) ([]string, postFlags, error) {
+	var rest []string
+	var flags postFlags
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--at":
+			if i+1 >= len(args) {
+				return nil, flags, fmt.Errorf("--at requires a time (e.g. +2h, 07:30 or %s)", util.ScheduleTimeFormat)
+			}
+			flags.at = args[i+1]
+			i++
+		case "--article":
+			flags.article = true
+		case "--thread":
+			flags.thread = true
+		case "--title":
+			if i+1 >= len(args) {
+				return nil, flags, fmt.Errorf("--title requires a title")
+			}
+			flags.title = args[i+1]
+			flags.article = true
+			i++
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return rest, flags, nil
+}
+
+// postArticle publishes a long-form markdown article.
+// Without a title, the first line of the text (e.g. "# My Title") is used.
+func (h *Handler) postArticle(source, title string) error {
+	body := source
+	if title == "" {
+		title, body = util.SplitArticleSource(source)
+	}
+	title = strings.TrimSpace(title)
+
+	if err := util.ValidateArticle(title, body); err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	noteId, err := h.db.CreateArticle(h.account.Id, title, body)
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+	h.db.NoteCreated(h.account, noteId, body, "")
+
+	// Federate the article via ActivityPub (background task)
+	go h.federateNote(noteId)
+
+	if h.output.IsJSON() {
+		h.output.JSON(PostResponse{
+			ID:        fmt.Sprintf("%v", noteId),
+			Title:     title,
+			Message:   body,
+			CreatedAt: time.Now(),
+		})
+	} else {
+		h.output.Success("Published article: %v\n", noteId)
+	}
+	return nil
+}
+
+// postThread splits long text into numbered notes (on "---" lines, paragraphs
+// and sentences) and posts them as a thread, each note replying to the previous one
+func (h *Handler) postThread(text string) error {
+	parts, err := util.SplitThread(text, h.conf.Conf.MaxChars)
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	noteIds := make([]interface{}, 0, len(parts))
+	replyURI := ""
+	for _, part := range parts {
+		noteId, err := h.db.CreateNoteWithReply(h.account.Id, part, replyURI)
+		if err != nil {
+			err = fmt.Errorf("posted %d of %d notes: %w", len(noteIds), len(parts), err)
+			h.output.Error(err)
+			go h.federateThread(noteIds)
+			return err
+		}
+		h.db.NoteCreated(h.account, noteId, part, replyURI)
+		noteIds = append(noteIds, noteId)
+		replyURI = h.localNoteURI(noteId)
+	}
+
+	// Federate the notes one after another so they arrive in order (background task)
+	go h.federateThread(noteIds)
+
+	if h.output.IsJSON() {
+		resp := ThreadResponse{Notes: make([]PostResponse, len(parts))}
+		for i, part := range parts {
+			resp.Notes[i] = PostResponse{
+				ID:        fmt.Sprintf("%v", noteIds[i]),
+				Message:   part,
+				CreatedAt: time.Now(),
+			}
+		}
+		h.output.JSON(resp)
+	} else {
+		h.output.Success("Posted thread of %d notes: %v\n", len(parts), noteIds[0])
+	}
+	return nil
+}
+
+// federateThread sends the Create activities for the notes of a thread in order
+func (h *Handler) federateThread(noteIds []interface{}) {
+	for _, noteId := range noteIds {
+		h.federateNote(noteId)
+	}
+}
+
+// localNoteURI returns the URI a reply to a local note refers to
+// (the note's ActivityPub id, or "local:<id>" without a configured domain)
+func (h *Handler) localNoteURI(noteId interface{}) string {
+	if domain := h.conf.Conf.SslDomain; domain != "" && domain != "example.com" {
+		return fmt.Sprintf("https://%s/notes/%v", domain, noteId)
+	}
+	return fmt.Sprintf("local:%v", noteId)
+}
+
+// schedulePost stores the message as a scheduled note, published by the server at the given time
+func (h *Handler) schedulePost(message, at string) error {
+	scheduledAt, err := util.ParseScheduleTime(at, time.Now())
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	scheduled := &domain.ScheduledNote{
+		Id:          uuid.New(),
+		AccountId:   h.account.Id,
+		Message:     message,
+		ScheduledAt: scheduledAt,
+		CreatedAt:   time.Now(),
+	}
+	if err := h.db.CreateScheduledNote(scheduled); err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	if h.output.IsJSON() {
+		h.output.JSON(ScheduledPostResponse{
+			ID:          scheduled.Id.String(),
+			Message:     message,
+			ScheduledAt: scheduledAt,
+		})
+	} else {
+		h.output.Success("Scheduled: %s for %s\n", scheduled.Id, scheduledAt.Format(util.ScheduleTimeFormat))
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/deemkeen/stegodon/domain"
 	"github.com/deemkeen/stegodon/util"
@@ -49,6 +50,9 @@ func TestPost_TextMode(t *testing.T) {
 	if !strings.Contains(result, db.createdNoteID.String()) {
 		t.Errorf("Expected note ID in output, got: %s", result)
 	}
+	if len(db.createdNotes) != 1 || db.createdNotes[0] != "Hello from CLI" {
+		t.Errorf("Expected hashtags and notifications for the new note, got %v", db.createdNotes)
+	}
 }
 
 func TestPost_JSONMode(t *testing.T) {
@@ -166,3 +170,196 @@ func TestPost_DatabaseError(t *testing.T) {
 		t.Errorf("Expected 'database error', got: %v", err)
 	}
 }
+
+func TestPost_Scheduled(t *testing.T) {
+	db := &mockDatabase{}
+	handler, output := newTestHandlerWithDB("", db)
+
+	err := handler.Execute([]string{"post", "--at", "+2h", "Good", "morning"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if db.createdNoteID != uuid.Nil {
+		t.Error("Expected no note to be created right away")
+	}
+	if len(db.scheduled) != 1 {
+		t.Fatalf("Expected 1 scheduled note, got %d", len(db.scheduled))
+	}
+	scheduled := db.scheduled[0]
+	if scheduled.Message != "Good morning" {
+		t.Errorf("Expected message 'Good morning', got %s", scheduled.Message)
+	}
+	if d := time.Until(scheduled.ScheduledAt); d < 119*time.Minute || d > 2*time.Hour {
+		t.Errorf("Expected note scheduled in 2h, got %v", d)
+	}
+	if !strings.Contains(output.String(), "Scheduled:") {
+		t.Errorf("Expected 'Scheduled:' in output, got: %s", output.String())
+	}
+}
+
+func TestPost_ScheduledJSON(t *testing.T) {
+	db := &mockDatabase{}
+	handler, output := newTestHandlerWithDB("", db)
+
+	err := handler.Execute([]string{"post", "Hello", "--at", "+1d", "-j"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var resp ScheduledPostResponse
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Expected valid JSON, got error: %v, output: %s", err, output.String())
+	}
+	if resp.ID != db.scheduled[0].Id.String() || resp.Message != "Hello" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestPost_ScheduledInvalidTime(t *testing.T) {
+	tests := [][]string{
+		{"post", "Hello", "--at"},
+		{"post", "--at", "someday", "Hello"},
+		{"post", "--at", "2000-01-01 00:00", "Hello"},
+	}
+	for _, args := range tests {
+		db := &mockDatabase{}
+		handler, _ := newTestHandlerWithDB("", db)
+
+		if err := handler.Execute(args); err == nil {
+			t.Errorf("Expected error for %v", args)
+		}
+		if len(db.scheduled) != 0 || db.createdNoteID != uuid.Nil {
+			t.Errorf("Expected nothing to be stored for %v", args)
+		}
+	}
+}
+
+func TestPost_ArticleStdin(t *testing.T) {
+	db := &mockDatabase{}
+	body := "Plates, spikes and " + strings.Repeat("a lot of words ", 40) + "\n\n## More\n\nText."
+	handler, output := newTestHandlerWithDB("# Field Notes\n\n"+body+"\n", db)
+
+	err := handler.Execute([]string{"post", "--article", "-"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(db.articles) != 1 {
+		t.Fatalf("Expected 1 article, got %d", len(db.articles))
+	}
+	article := db.articles[0]
+	if article.Title != "Field Notes" {
+		t.Errorf("Expected title from first line, got %q", article.Title)
+	}
+	if article.Message != body {
+		t.Errorf("Expected full body beyond the note limit, got %q", article.Message)
+	}
+	if !strings.Contains(output.String(), "Published article:") {
+		t.Errorf("Expected 'Published article:' in output, got: %s", output.String())
+	}
+}
+
+func TestPost_ArticleWithTitleJSON(t *testing.T) {
+	db := &mockDatabase{}
+	handler, output := newTestHandlerWithDB("", db)
+
+	err := handler.Execute([]string{"post", "--title", "Hello", "Some", "*markdown*", "-j"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var resp PostResponse
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Expected valid JSON, got error: %v, output: %s", err, output.String())
+	}
+	if resp.Title != "Hello" || resp.Message != "Some *markdown*" || resp.ID != db.createdNoteID.String() {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestPost_ArticleInvalid(t *testing.T) {
+	tests := []struct {
+		args  []string
+		input string
+	}{
+		{[]string{"post", "--article", "-"}, "Only a title\n"},
+		{[]string{"post", "--article", "--at", "+1h", "-"}, "# Title\n\nBody"},
+		{[]string{"post", "--title"}, ""},
+	}
+	for _, tt := range tests {
+		db := &mockDatabase{}
+		handler, _ := newTestHandlerWithDB(tt.input, db)
+
+		if err := handler.Execute(tt.args); err == nil {
+			t.Errorf("Expected error for %v", tt.args)
+		}
+		if len(db.articles) != 0 || len(db.scheduled) != 0 {
+			t.Errorf("Expected nothing to be stored for %v", tt.args)
+		}
+	}
+}
+
+func TestPost_ThreadStdin(t *testing.T) {
+	db := &mockDatabase{}
+	text := "First part of the thread.\n---\n" + strings.Repeat("Another sentence in the thread. ", 8)
+	handler, output := newTestHandlerWithDB(text, db)
+	handler.conf.Conf.SslDomain = "stegodon.example"
+
+	err := handler.Execute([]string{"post", "--thread", "-"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(db.replies) != 3 {
+		t.Fatalf("Expected 3 notes, got %d: %+v", len(db.replies), db.replies)
+	}
+	if db.replies[0].Message != "First part of the thread. 1/3" || db.replies[0].InReplyToURI != "" {
+		t.Errorf("Unexpected first note: %+v", db.replies[0])
+	}
+	for i := 1; i < len(db.replies); i++ {
+		want := "https://stegodon.example/notes/" + db.replies[i-1].Id.String()
+		if db.replies[i].InReplyToURI != want {
+			t.Errorf("Expected note %d to reply to %s, got %q", i+1, want, db.replies[i].InReplyToURI)
+		}
+		if util.CountVisibleChars(db.replies[i].Message) > 150 {
+			t.Errorf("Note %d too long: %q", i+1, db.replies[i].Message)
+		}
+	}
+	if !strings.Contains(output.String(), "Posted thread of 3 notes") {
+		t.Errorf("Expected 'Posted thread of 3 notes' in output, got: %s", output.String())
+	}
+}
+
+func TestPost_ThreadJSON(t *testing.T) {
+	db := &mockDatabase{}
+	handler, output := newTestHandlerWithDB("one\n---\ntwo", db)
+
+	err := handler.Execute([]string{"post", "--thread", "-", "-j"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var resp ThreadResponse
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Expected valid JSON, got error: %v, output: %s", err, output.String())
+	}
+	if len(resp.Notes) != 2 || resp.Notes[1].Message != "two 2/2" || resp.Notes[1].ID != db.replies[1].Id.String() {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	if db.replies[1].InReplyToURI != "local:"+db.replies[0].Id.String() {
+		t.Errorf("Expected local reply URI without a domain, got %q", db.replies[1].InReplyToURI)
+	}
+}
+
+func TestPost_ThreadWithAt(t *testing.T) {
+	db := &mockDatabase{}
+	handler, _ := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"post", "--thread", "--at", "+2h", "hello"}); err == nil {
+		t.Error("Expected an error for a scheduled thread")
+	}
+	if len(db.replies) != 0 {
+		t.Error("Expected nothing to be posted")
+	}
+}
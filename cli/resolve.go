@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/deemkeen/stegodon/util"
+	"github.com/deemkeen/stegodon/web"
+)
+
+// handleResolve fetches a post or account by URL, ActivityPub id or handle
+func (h *Handler) handleResolve(args []string) error {
+	if len(args) == 0 || strings.TrimSpace(args[0]) == "" {
+		err := fmt.Errorf("usage: resolve <url>")
+		h.output.Error(err)
+		return err
+	}
+
+	result, err := h.resolve(args[0])
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	resp := h.resolveResponse(result)
+	if h.output.IsJSON() {
+		h.output.JSON(resp)
+		return nil
+	}
+
+	if resp.Type == "post" {
+		h.output.Print("%s (%s)\n", resp.Author, FormatTimeAgo(*resp.CreatedAt))
+		h.output.Print("%s\n\n", resp.Content)
+	} else {
+		if resp.DisplayName != "" {
+			h.output.Print("%s (%s)\n", resp.DisplayName, resp.Author)
+		} else {
+			h.output.Print("%s\n", resp.Author)
+		}
+		if resp.Content != "" {
+			h.output.Print("%s\n", resp.Content)
+		}
+		h.output.Println("")
+	}
+	if resp.URI != "" {
+		h.output.Print("%s\n", resp.URI)
+	}
+	if resp.Type == "post" {
+		h.output.Println("Reply, like or boost it with its id or URI, or open it in the TUI with ctrl+r.")
+	} else {
+		h.output.Println("Follow it with its handle, or open it in the TUI with ctrl+r.")
+	}
+
+	return nil
+}
+
+// resolveResponse flattens a resolve result for output
+func (h *Handler) resolveResponse(result *web.ResolveResult) ResolveResponse {
+	localDomain := ""
+	if h.conf != nil {
+		localDomain = h.conf.Conf.SslDomain
+	}
+
+	switch {
+	case result.Note != nil:
+		note := result.Note
+		uri := note.ObjectURI
+		if uri == "" && localDomain != "" {
+			uri = fmt.Sprintf("https://%s/u/%s/%s", localDomain, note.CreatedBy, note.Id)
+		}
+		return ResolveResponse{
+			Type:      "post",
+			ID:        note.Id.String(),
+			URI:       uri,
+			Author:    "@" + note.CreatedBy,
+			Content:   util.StripHTMLTags(note.Message),
+			CreatedAt: &note.CreatedAt,
+		}
+	case result.Activity != nil:
+		activity := result.Activity
+		resp := ResolveResponse{
+			Type:      "post",
+			ID:        activity.Id.String(),
+			URI:       activity.ObjectURI,
+			URL:       activity.ObjectURL,
+			Author:    activity.ActorURI,
+			Content:   resolvedContent(activity.RawJSON),
+			CreatedAt: &activity.CreatedAt,
+		}
+		if result.RemoteAccount != nil {
+			resp.Author = "@" + result.RemoteAccount.Username + "@" + result.RemoteAccount.Domain
+		}
+		return resp
+	case result.Account != nil:
+		acc := result.Account
+		resp := ResolveResponse{
+			Type:        "account",
+			ID:          acc.Id.String(),
+			Author:      "@" + acc.Username,
+			DisplayName: acc.DisplayName,
+			Content:     acc.Summary,
+		}
+		if localDomain != "" {
+			resp.URI = fmt.Sprintf("https://%s/users/%s", localDomain, acc.Username)
+		}
+		return resp
+	default:
+		remote := result.RemoteAccount
+		return ResolveResponse{
+			Type:        "account",
+			ID:          remote.Id.String(),
+			URI:         remote.ActorURI,
+			Author:      "@" + remote.Username + "@" + remote.Domain,
+			DisplayName: remote.DisplayName,
+			Content:     util.StripHTMLTags(remote.Summary),
+		}
+	}
+}
+
+// resolvedContent returns the plain text of a stored Create activity's object
+func resolvedContent(rawJSON string) string {
+	var create struct {
+		Object struct {
+			Content string `json:"content"`
+		} `json:"object"`
+	}
+	if err := json.Unmarshal([]byte(rawJSON), &create); err != nil {
+		return ""
+	}
+	return util.StripHTMLTags(create.Object.Content)
+}
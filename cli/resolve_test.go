@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/web"
+	"github.com/google/uuid"
+)
+
+func TestResolve_MissingURL(t *testing.T) {
+	handler, output := newTestHandlerWithDB("", &mockDatabase{})
+
+	if err := handler.Execute([]string{"resolve"}); err == nil {
+		t.Fatal("Expected error for missing URL")
+	}
+	if !strings.Contains(output.String(), "usage: resolve <url>") {
+		t.Errorf("Expected usage message, got: %s", output.String())
+	}
+}
+
+func TestResolve_RemotePost(t *testing.T) {
+	handler, output := newTestHandlerWithDB("", &mockDatabase{})
+	var resolved string
+	handler.resolve = func(input string) (*web.ResolveResult, error) {
+		resolved = input
+		return &web.ResolveResult{
+			Activity: &domain.Activity{
+				Id:        uuid.New(),
+				ObjectURI: "https://remote.example/users/bob/statuses/1",
+				ObjectURL: "https://remote.example/@bob/1",
+				RawJSON:   `{"type":"Create","object":{"type":"Note","content":"<p>hello fediverse</p>"}}`,
+				CreatedAt: time.Now().Add(-time.Hour),
+			},
+			RemoteAccount: &domain.RemoteAccount{Username: "bob", Domain: "remote.example"},
+		}, nil
+	}
+
+	if err := handler.Execute([]string{"resolve", "https://remote.example/@bob/1"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if resolved != "https://remote.example/@bob/1" {
+		t.Errorf("Expected URL to be resolved, got: %q", resolved)
+	}
+	result := output.String()
+	if !strings.Contains(result, "@bob@remote.example (1 hour ago)") || !strings.Contains(result, "hello fediverse") {
+		t.Errorf("Expected author and content, got: %s", result)
+	}
+	if strings.Contains(result, "<p>") {
+		t.Errorf("Expected HTML to be stripped, got: %s", result)
+	}
+}
+
+func TestResolve_RemoteAccountJSON(t *testing.T) {
+	handler, output := newTestHandlerWithDB("", &mockDatabase{})
+	handler.resolve = func(input string) (*web.ResolveResult, error) {
+		return &web.ResolveResult{
+			RemoteAccount: &domain.RemoteAccount{
+				Id:          uuid.New(),
+				Username:    "bob",
+				Domain:      "remote.example",
+				DisplayName: "Bob",
+				ActorURI:    "https://remote.example/users/bob",
+				Summary:     "<p>I post things</p>",
+			},
+		}, nil
+	}
+
+	if err := handler.Execute([]string{"resolve", "@bob@remote.example", "--json"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var resp ResolveResponse
+	if err := json.Unmarshal([]byte(output.String()), &resp); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if resp.Type != "account" || resp.Author != "@bob@remote.example" || resp.URI != "https://remote.example/users/bob" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	if resp.Content != "I post things" || resp.CreatedAt != nil {
+		t.Errorf("Unexpected content or created_at: %+v", resp)
+	}
+}
+
+func TestResolve_Error(t *testing.T) {
+	handler, output := newTestHandlerWithDB("", &mockDatabase{})
+	handler.resolve = func(input string) (*web.ResolveResult, error) {
+		return nil, errors.New("HTTP 404")
+	}
+
+	if err := handler.Execute([]string{"resolve", "https://remote.example/missing", "-j"}); err == nil {
+		t.Fatal("Expected error to be returned")
+	}
+	if !strings.Contains(output.String(), `"error": "HTTP 404"`) {
+		t.Errorf("Expected JSON error, got: %s", output.String())
+	}
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/deemkeen/stegodon/util"
+)
+
+// handleTags lists, follows, or unfollows hashtags
+func (h *Handler) handleTags(args []string) error {
+	if len(args) == 0 || strings.ToLower(args[0]) == "list" {
+		return h.listFollowedTags()
+	}
+
+	action := strings.ToLower(args[0])
+	if action != "follow" && action != "unfollow" {
+		err := fmt.Errorf("unknown tags action: %s (use list, follow or unfollow)", args[0])
+		h.output.Error(err)
+		return err
+	}
+
+	if len(args) < 2 {
+		err := fmt.Errorf("usage: tags %s <tag>", action)
+		h.output.Error(err)
+		return err
+	}
+
+	tag, ok := util.NormalizeHashtag(args[1])
+	if !ok {
+		err := fmt.Errorf("invalid hashtag: %s", args[1])
+		h.output.Error(err)
+		return err
+	}
+
+	var err error
+	if action == "follow" {
+		err = h.db.FollowHashtag(h.account.Id, tag)
+	} else {
+		err = h.db.UnfollowHashtag(h.account.Id, tag)
+	}
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	following := action == "follow"
+	if h.output.IsJSON() {
+		h.output.JSON(TagActionResponse{
+			Status:    "ok",
+			Tag:       tag,
+			Following: following,
+		})
+	} else if following {
+		h.output.Success("Following #%s\n", tag)
+	} else {
+		h.output.Success("Unfollowed #%s\n", tag)
+	}
+
+	return nil
+}
+
+// listFollowedTags shows the hashtags the user follows
+func (h *Handler) listFollowedTags() error {
+	err, tags := h.db.ReadFollowedHashtags(h.account.Id)
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	if tags == nil {
+		tags = []string{}
+	}
+
+	if h.output.IsJSON() {
+		h.output.JSON(TagsResponse{
+			Tags:  tags,
+			Count: len(tags),
+		})
+		return nil
+	}
+
+	if len(tags) == 0 {
+		h.output.Println("Not following any hashtags.")
+		return nil
+	}
+
+	for _, tag := range tags {
+		h.output.Print("#%s\n", tag)
+	}
+
+	return nil
+}
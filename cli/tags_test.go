@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTags_ListEmpty(t *testing.T) {
+	handler, output := newTestHandlerWithDB("", &mockDatabase{})
+
+	if err := handler.Execute([]string{"tags"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !strings.Contains(output.String(), "Not following any hashtags") {
+		t.Errorf("Expected empty message, got: %s", output.String())
+	}
+}
+
+func TestTags_List(t *testing.T) {
+	db := &mockDatabase{followedTags: []string{"golang", "fediverse"}}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"tags", "list"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	result := output.String()
+	if !strings.Contains(result, "#golang") || !strings.Contains(result, "#fediverse") {
+		t.Errorf("Expected followed tags in output, got: %s", result)
+	}
+}
+
+func TestTags_ListJSON(t *testing.T) {
+	db := &mockDatabase{followedTags: []string{"golang"}}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"tags", "--json"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var resp TagsResponse
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if resp.Count != 1 || resp.Tags[0] != "golang" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestTags_FollowNormalizesTag(t *testing.T) {
+	db := &mockDatabase{}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"tags", "follow", "#GoLang"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(db.followedTags) != 1 || db.followedTags[0] != "golang" {
+		t.Errorf("Expected normalized tag 'golang' to be followed, got: %v", db.followedTags)
+	}
+	if !strings.Contains(output.String(), "Following #golang") {
+		t.Errorf("Expected confirmation, got: %s", output.String())
+	}
+}
+
+func TestTags_UnfollowJSON(t *testing.T) {
+	db := &mockDatabase{followedTags: []string{"golang"}}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"tags", "unfollow", "golang", "-j"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(db.followedTags) != 0 {
+		t.Errorf("Expected tag to be unfollowed, got: %v", db.followedTags)
+	}
+
+	var resp TagActionResponse
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if resp.Status != "ok" || resp.Tag != "golang" || resp.Following {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+func TestTags_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		db   *mockDatabase
+	}{
+		{"missing tag", []string{"tags", "follow"}, &mockDatabase{}},
+		{"invalid tag", []string{"tags", "follow", "123"}, &mockDatabase{}},
+		{"unknown action", []string{"tags", "mute", "golang"}, &mockDatabase{}},
+		{"database error", []string{"tags", "follow", "golang"}, &mockDatabase{tagError: errors.New("db down")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, output := newTestHandlerWithDB("", tt.db)
+			if err := handler.Execute(tt.args); err == nil {
+				t.Error("Expected an error")
+			}
+			if !strings.Contains(output.String(), "Error:") {
+				t.Errorf("Expected error output, got: %s", output.String())
+			}
+		})
+	}
+}
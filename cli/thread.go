@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+)
+
+// handleThread shows a post with the posts it replies to and the replies below it.
+// Keyword filters (thread context) hide or collapse replies and ancestors, like in the TUI;
+// the post itself is only collapsed.
+func (h *Handler) handleThread(args []string) error {
+	if len(args) != 1 || strings.TrimSpace(args[0]) == "" {
+		err := fmt.Errorf("usage: thread <id|uri>")
+		h.output.Error(err)
+		return err
+	}
+
+	err, thread := h.db.ReadThread(args[0])
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+	err, filters := h.db.ReadFiltersByAccountId(h.account.Id)
+	if err != nil {
+		h.output.Error(err)
+		return err
+	}
+
+	now := time.Now()
+	post := thread.Post
+	if f, ok := domain.MatchFilters(*filters, domain.FilterContextThread, post.Content, now); ok {
+		post.FilterWarning = f.Phrase
+	}
+	ancestors := domain.FilterHomePosts(thread.Ancestors, *filters, domain.FilterContextThread, now)
+	replies := domain.FilterHomePosts(thread.Replies, *filters, domain.FilterContextThread, now)
+
+	if h.output.IsJSON() {
+		resp := PostThreadResponse{
+			Ancestors: make([]TimelinePost, 0, len(ancestors)),
+			Post:      toTimelinePost(post),
+			Replies:   make([]TimelinePost, 0, len(replies)),
+		}
+		for _, p := range ancestors {
+			resp.Ancestors = append(resp.Ancestors, toTimelinePost(p))
+		}
+		for _, p := range replies {
+			resp.Replies = append(resp.Replies, toTimelinePost(p))
+		}
+		h.output.JSON(resp)
+		return nil
+	}
+
+	for _, p := range ancestors {
+		h.writeThreadPost(p, "")
+	}
+	h.writeThreadPost(post, "> ")
+	if len(replies) > 0 {
+		h.output.Print("%d replies:\n\n", len(replies))
+	}
+	for _, p := range replies {
+		h.writeThreadPost(p, "  ")
+	}
+	return nil
+}
+
+// writeThreadPost writes a post of a thread in text mode, with its id to act on it
+func (h *Handler) writeThreadPost(post domain.HomePost, indent string) {
+	item := toTimelinePost(post)
+	content := item.Message
+	if post.FilterWarning != "" {
+		content = "⚠ filtered: " + post.FilterWarning
+	}
+	h.output.Print("%s%s (%s) %s\n", indent, post.Author, FormatTimeAgo(post.Time), item.ID)
+	h.output.Print("%s%s\n\n", indent, strings.ReplaceAll(content, "\n", "\n"+indent))
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/actions"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+func testThread() *actions.Thread {
+	now := time.Now()
+	return &actions.Thread{
+		Ancestors: []domain.HomePost{{ID: uuid.New(), Author: "@alice", Content: "Original post", Time: now.Add(-2 * time.Hour)}},
+		Post:      domain.HomePost{ID: uuid.New(), Author: "@bob@remote.example", Content: "<p>A reply</p>", Time: now.Add(-time.Hour)},
+		Replies: []domain.HomePost{
+			{ID: uuid.New(), Author: "@carol", Content: "Agreed", Time: now},
+			{ID: uuid.New(), Author: "@dave", Content: "Buy crypto now", Time: now},
+		},
+	}
+}
+
+func TestThread_Text(t *testing.T) {
+	db := &mockDatabase{thread: testThread()}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"thread", db.thread.Post.ID.String()}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	result := output.String()
+	if !strings.Contains(result, "> @bob@remote.example (1 hour ago) "+db.thread.Post.ID.String()) {
+		t.Errorf("Expected the post marked with its id, got:\n%s", result)
+	}
+	if strings.Index(result, "Original post") > strings.Index(result, "A reply") {
+		t.Errorf("Expected ancestors before the post, got:\n%s", result)
+	}
+	if !strings.Contains(result, "2 replies:") || !strings.Contains(result, "  Agreed") {
+		t.Errorf("Expected indented replies, got:\n%s", result)
+	}
+	if strings.Contains(result, "<p>") {
+		t.Errorf("Expected HTML to be stripped, got:\n%s", result)
+	}
+}
+
+func TestThread_JSONAppliesFilters(t *testing.T) {
+	db := &mockDatabase{
+		thread: testThread(),
+		filters: []domain.Filter{
+			{Phrase: "crypto", Action: domain.FilterActionHide, Contexts: []string{domain.FilterContextThread}},
+			{Phrase: "reply", Action: domain.FilterActionHide, Contexts: []string{domain.FilterContextThread}},
+		},
+	}
+	handler, output := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"thread", "https://remote.example/users/bob/statuses/1", "-j"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var resp PostThreadResponse
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if len(resp.Ancestors) != 1 || len(resp.Replies) != 1 || resp.Replies[0].Message != "Agreed" {
+		t.Errorf("Expected the matching reply to be hidden, got: %s", output.String())
+	}
+	// The post itself is collapsed, never hidden
+	if resp.Post.ID != db.thread.Post.ID.String() || resp.Post.FilterWarning != "reply" {
+		t.Errorf("Expected the post to be collapsed, got: %+v", resp.Post)
+	}
+}
+
+func TestThread_NotFound(t *testing.T) {
+	handler, output := newTestHandlerWithDB("", &mockDatabase{})
+
+	if err := handler.Execute([]string{"thread", "missing"}); err == nil {
+		t.Fatal("Expected an error for an unknown post")
+	}
+	if !strings.Contains(output.String(), "post not found") {
+		t.Errorf("Expected error message, got: %s", output.String())
+	}
+}
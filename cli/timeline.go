@@ -4,18 +4,55 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/deemkeen/stegodon/domain"
 	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
 )
 
 const defaultTimelineLimit = 20
 
-// handleTimeline shows the home timeline
+// handleTimeline shows the home timeline, or a list timeline with --list <name>.
+// --before <cursor> continues after the last post of a previous page.
+// --follow keeps streaming new posts until the client disconnects.
 func (h *Handler) handleTimeline(args []string) error {
 	limit := defaultTimelineLimit
+	listName := ""
+	var before domain.Cursor
+	follow := false
 
-	// Parse -n flag
+	// Parse -n, --list, --before and --follow flags
 	for i := 0; i < len(args); i++ {
+		if args[i] == "--follow" || args[i] == "-f" {
+			follow = true
+			continue
+		}
+		if args[i] == "--before" {
+			if i+1 >= len(args) {
+				err := fmt.Errorf("usage: timeline --before <cursor>")
+				h.output.Error(err)
+				return err
+			}
+			var err error
+			before, err = domain.ParseCursor(args[i+1])
+			if err != nil {
+				h.output.Error(err)
+				return err
+			}
+			i++ // Skip the cursor
+			continue
+		}
+		if args[i] == "--list" {
+			if i+1 >= len(args) || strings.TrimSpace(args[i+1]) == "" {
+				err := fmt.Errorf("usage: timeline --list <name>")
+				h.output.Error(err)
+				return err
+			}
+			listName = strings.TrimSpace(args[i+1])
+			i++ // Skip the list name
+			continue
+		}
 		if args[i] == "-n" && i+1 < len(args) {
 			n, err := strconv.Atoi(args[i+1])
 			if err != nil {
@@ -23,8 +60,8 @@ func (h *Handler) handleTimeline(args []string) error {
 				h.output.Error(err)
 				return err
 			}
-			if n < 1 {
-				err = fmt.Errorf("-n must be at least 1")
+			if n < 0 {
+				err = fmt.Errorf("-n must not be negative")
 				h.output.Error(err)
 				return err
 			}
@@ -32,19 +69,69 @@ func (h *Handler) handleTimeline(args []string) error {
 			i++ // Skip the next argument (the number)
 		}
 	}
+	// -n 0 only makes sense for a stream, which then starts with new posts
+	if limit == 0 && !follow {
+		err := fmt.Errorf("-n must be at least 1")
+		h.output.Error(err)
+		return err
+	}
+	if follow && !before.IsZero() {
+		err := fmt.Errorf("--before cannot be used with --follow")
+		h.output.Error(err)
+		return err
+	}
 
 	// Read timeline posts
-	err, posts := h.db.ReadHomeTimelinePosts(h.account.Id, limit)
+	var err error
+	var posts *[]domain.HomePost
+	if listName != "" {
+		var list *domain.List
+		err, list = h.db.ReadListByName(h.account.Id, listName)
+		if err == nil && list == nil {
+			err = fmt.Errorf("list not found: %s", listName)
+		}
+		if err != nil {
+			h.output.Error(err)
+			return err
+		}
+		listName = list.Name
+		if follow {
+			return h.followTimeline(list.Id, limit)
+		}
+		err, posts = h.db.ReadListTimelinePosts(h.account.Id, list.Id, before, limit)
+	} else if follow {
+		return h.followTimeline(uuid.Nil, limit)
+	} else {
+		err, posts = h.db.ReadHomeTimelinePosts(h.account.Id, before, limit)
+	}
 	if err != nil {
 		h.output.Error(err)
 		return err
 	}
 
+	// A full page may have older posts; filtering below does not change where the page ends
+	nextCursor := ""
+	if posts != nil && len(*posts) == limit {
+		nextCursor = (*posts)[limit-1].Cursor().String()
+	}
+
+	// Apply keyword filters (home context, like the TUI's home and list timelines)
+	if posts != nil {
+		err, filters := h.db.ReadFiltersByAccountId(h.account.Id)
+		if err != nil {
+			h.output.Error(err)
+			return err
+		}
+		filtered := domain.FilterHomePosts(*posts, *filters, domain.FilterContextHome, time.Now())
+		posts = &filtered
+	}
+
 	if posts == nil || len(*posts) == 0 {
 		if h.output.IsJSON() {
 			h.output.JSON(TimelineResponse{
 				Posts: []TimelinePost{},
 				Count: 0,
+				List:  listName,
 			})
 		} else {
 			h.output.Println("No posts in timeline.")
@@ -56,39 +143,14 @@ func (h *Handler) handleTimeline(args []string) error {
 	if h.output.IsJSON() {
 		timelinePosts := make([]TimelinePost, 0, len(*posts))
 		for _, post := range *posts {
-			// Parse author and domain
-			author := post.Author
-			domain := ""
-			if strings.Contains(author, "@") && strings.Count(author, "@") >= 2 {
-				// Remote user: @user@domain -> user, domain
-				parts := strings.SplitN(strings.TrimPrefix(author, "@"), "@", 2)
-				if len(parts) == 2 {
-					author = parts[0]
-					domain = parts[1]
-				}
-			} else {
-				// Local user: @user -> user
-				author = strings.TrimPrefix(author, "@")
-			}
-
-			// Strip HTML tags from content for CLI output
-			content := util.StripHTMLTags(post.Content)
-
-			timelinePosts = append(timelinePosts, TimelinePost{
-				ID:         post.ID.String(),
-				Author:     author,
-				Domain:     domain,
-				Message:    content,
-				CreatedAt:  post.Time,
-				ReplyCount: post.ReplyCount,
-				LikeCount:  post.LikeCount,
-				BoostCount: post.BoostCount,
-			})
+			timelinePosts = append(timelinePosts, toTimelinePost(post))
 		}
 
 		h.output.JSON(TimelineResponse{
-			Posts: timelinePosts,
-			Count: len(timelinePosts),
+			Posts:      timelinePosts,
+			Count:      len(timelinePosts),
+			List:       listName,
+			NextCursor: nextCursor,
 		})
 	} else {
 		// Text output
@@ -96,10 +158,52 @@ func (h *Handler) handleTimeline(args []string) error {
 			// Strip HTML tags from content for CLI output
 			content := util.StripHTMLTags(post.Content)
 
-			h.output.Print("%s (%s)\n", post.Author, FormatTimeAgo(post.Time))
+			if post.ViaHashtag != "" {
+				h.output.Print("%s (%s) via #%s\n", post.Author, FormatTimeAgo(post.Time), post.ViaHashtag)
+			} else {
+				h.output.Print("%s (%s)\n", post.Author, FormatTimeAgo(post.Time))
+			}
+			if post.FilterWarning != "" {
+				content = fmt.Sprintf("⚠ filtered: %s", post.FilterWarning)
+			}
 			h.output.Print("%s\n\n", content)
 		}
+		if nextCursor != "" {
+			h.output.Print("More: timeline --before %s\n", nextCursor)
+		}
 	}
 
 	return nil
 }
+
+// toTimelinePost converts a home timeline post for JSON output
+func toTimelinePost(post domain.HomePost) TimelinePost {
+	// Parse author and domain
+	author := post.Author
+	domain := ""
+	if strings.Contains(author, "@") && strings.Count(author, "@") >= 2 {
+		// Remote user: @user@domain -> user, domain
+		parts := strings.SplitN(strings.TrimPrefix(author, "@"), "@", 2)
+		if len(parts) == 2 {
+			author = parts[0]
+			domain = parts[1]
+		}
+	} else {
+		// Local user: @user -> user
+		author = strings.TrimPrefix(author, "@")
+	}
+
+	return TimelinePost{
+		ID:         post.ID.String(),
+		Author:     author,
+		Domain:     domain,
+		Message:    util.StripHTMLTags(post.Content), // Strip HTML tags from content for CLI output
+		CreatedAt:  post.Time,
+		ReplyCount: post.ReplyCount,
+		LikeCount:  post.LikeCount,
+		BoostCount: post.BoostCount,
+		ViaHashtag: post.ViaHashtag,
+
+		FilterWarning: post.FilterWarning,
+	}
+}
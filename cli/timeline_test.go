@@ -2,6 +2,7 @@ package cli
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -145,6 +146,49 @@ func TestTimeline_WithLimit(t *testing.T) {
 	}
 }
 
+func TestTimeline_Before(t *testing.T) {
+	now := time.Now()
+	posts := make([]domain.HomePost, 5)
+	for i := range posts {
+		posts[i] = domain.HomePost{
+			ID:      uuid.New(),
+			Author:  "@user",
+			Content: fmt.Sprintf("Post %d", i),
+			Time:    now.Add(-time.Duration(i) * time.Minute),
+		}
+	}
+
+	db := &mockDatabase{notes: posts}
+	handler, output := newTestHandlerWithDB("", db)
+	if err := handler.Execute([]string{"timeline", "-n", "3", "--json"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var first TimelineResponse
+	if err := json.Unmarshal(output.Bytes(), &first); err != nil {
+		t.Fatalf("Expected valid JSON, got error: %v", err)
+	}
+	if first.Count != 3 || first.NextCursor == "" {
+		t.Fatalf("Expected a full first page with a next cursor, got %+v", first)
+	}
+
+	handler, output = newTestHandlerWithDB("", db)
+	if err := handler.Execute([]string{"timeline", "-n", "3", "--before", first.NextCursor, "--json"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var second TimelineResponse
+	if err := json.Unmarshal(output.Bytes(), &second); err != nil {
+		t.Fatalf("Expected valid JSON, got error: %v", err)
+	}
+	if second.Count != 2 || second.Posts[0].Message != "Post 3" || second.NextCursor != "" {
+		t.Errorf("Expected the last two posts without a next cursor, got %+v", second)
+	}
+
+	handler, _ = newTestHandlerWithDB("", db)
+	if err := handler.Execute([]string{"timeline", "--before", "garbage"}); err == nil {
+		t.Error("Expected an error for an invalid cursor")
+	}
+}
+
 func TestTimeline_InvalidLimit(t *testing.T) {
 	db := &mockDatabase{}
 	handler, _ := newTestHandlerWithDB("", db)
@@ -218,3 +262,94 @@ func TestTimeline_HTMLStripping(t *testing.T) {
 		t.Errorf("Expected content to contain 'Hello', got: %s", resp.Posts[0].Message)
 	}
 }
+
+func TestTimeline_List(t *testing.T) {
+	listId := uuid.New()
+	db := &mockDatabase{
+		notes: []domain.HomePost{{ID: uuid.New(), Author: "@alice", Content: "Home post", Time: time.Now()}},
+		lists: map[string]domain.List{"friends": {Id: listId, Name: "friends"}},
+		listNotes: map[uuid.UUID][]domain.HomePost{
+			listId: {{ID: uuid.New(), Author: "@bob", Content: "List post", Time: time.Now()}},
+		},
+	}
+	handler, output := newTestHandlerWithDB("", db)
+
+	err := handler.Execute([]string{"timeline", "--list", "friends", "-j"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var resp TimelineResponse
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Expected valid JSON, got error: %v", err)
+	}
+	if resp.List != "friends" {
+		t.Errorf("Expected list 'friends', got %q", resp.List)
+	}
+	if resp.Count != 1 || resp.Posts[0].Message != "List post" {
+		t.Errorf("Expected only the list post, got %+v", resp.Posts)
+	}
+}
+
+func TestTimeline_UnknownList(t *testing.T) {
+	db := &mockDatabase{}
+	handler, output := newTestHandlerWithDB("", db)
+
+	err := handler.Execute([]string{"timeline", "--list", "nope"})
+	if err == nil {
+		t.Fatal("Expected error for unknown list")
+	}
+	if !strings.Contains(output.String(), "list not found: nope") {
+		t.Errorf("Expected 'list not found' error, got: %s", output.String())
+	}
+}
+
+func TestTimeline_ListMissingName(t *testing.T) {
+	db := &mockDatabase{}
+	handler, _ := newTestHandlerWithDB("", db)
+
+	if err := handler.Execute([]string{"timeline", "--list"}); err == nil {
+		t.Error("Expected error for --list without a name")
+	}
+}
+
+func TestTimeline_KeywordFilters(t *testing.T) {
+	db := &mockDatabase{
+		notes: []domain.HomePost{
+			{ID: uuid.New(), Author: "@alice", Content: "Big crypto news", Time: time.Now()},
+			{ID: uuid.New(), Author: "@bob", Content: "Finale spoilers ahead", Time: time.Now()},
+			{ID: uuid.New(), Author: "@carol", Content: "Hello", Time: time.Now()},
+		},
+		filters: []domain.Filter{
+			{Phrase: "crypto", Contexts: []string{domain.FilterContextHome}, Action: domain.FilterActionHide},
+			{Phrase: "spoilers", Contexts: []string{domain.FilterContextHome}, Action: domain.FilterActionWarn},
+		},
+	}
+
+	handler, output := newTestHandlerWithDB("", db)
+	if err := handler.Execute([]string{"timeline", "-j"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var resp TimelineResponse
+	if err := json.Unmarshal(output.Bytes(), &resp); err != nil {
+		t.Fatalf("Expected valid JSON, got error: %v", err)
+	}
+	if resp.Count != 2 {
+		t.Fatalf("Expected hidden post to be dropped, got %d posts", resp.Count)
+	}
+	if resp.Posts[0].FilterWarning != "spoilers" || resp.Posts[1].FilterWarning != "" {
+		t.Errorf("Unexpected filter warnings: %+v", resp.Posts)
+	}
+
+	handler, output = newTestHandlerWithDB("", db)
+	if err := handler.Execute([]string{"timeline"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	result := output.String()
+	if strings.Contains(result, "crypto") || strings.Contains(result, "Finale") {
+		t.Errorf("Expected filtered content to be hidden, got: %s", result)
+	}
+	if !strings.Contains(result, "⚠ filtered: spoilers") {
+		t.Errorf("Expected filter warning in output, got: %s", result)
+	}
+}
@@ -0,0 +1,173 @@
+package db
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+const testAuthorizedKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIJ4nqXoB8Yv0kz0nJqkF6PmtZbJm8ny0sS1u7c5fQk0x admin@example"
+
+func TestOpenRequiresExistingDatabase(t *testing.T) {
+	if _, err := Open(filepath.Join(t.TempDir(), "missing.db")); err == nil {
+		t.Error("Expected an error for a missing database file")
+	}
+}
+
+func TestLockExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "database.db")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// An idle server connection keeps the database open in WAL mode
+	server, err := sql.Open("sqlite", path+"?_pragma=journal_mode(WAL)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.Exec(`CREATE TABLE a (id INTEGER)`); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	if err := db.LockExclusive(); err == nil {
+		t.Error("Expected the lock to fail while the server has the database open")
+	}
+	db.Close()
+	server.Close()
+
+	db, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer db.Close()
+	if err := db.LockExclusive(); err != nil {
+		t.Errorf("Expected the lock once the server is stopped, got %v", err)
+	}
+}
+
+func TestReadSchemaStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "database.db")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.CreateDB(); err != nil {
+		t.Fatalf("CreateDB() error: %v", err)
+	}
+	err, status := db.ReadSchemaStatus()
+	if err != nil {
+		t.Fatalf("ReadSchemaStatus() error: %v", err)
+	}
+	if status.UpToDate() || len(status.MissingTables) != len(schemaTables) {
+		t.Errorf("Expected all migration tables to be missing, got %v", status.MissingTables)
+	}
+	if len(status.MissingColumns) == 0 {
+		t.Error("Expected missing columns on the base accounts table")
+	}
+
+	if err := db.RunMigrations(); err != nil {
+		t.Fatalf("RunMigrations() error: %v", err)
+	}
+	err, status = db.ReadSchemaStatus()
+	if err != nil {
+		t.Fatalf("ReadSchemaStatus() error: %v", err)
+	}
+	if !status.UpToDate() {
+		t.Errorf("Expected schema to be up to date, missing tables %v, columns %v", status.MissingTables, status.MissingColumns)
+	}
+	if status.Tables != len(schemaTables)+2 {
+		t.Errorf("Expected %d tables, got %d", len(schemaTables)+2, status.Tables)
+	}
+}
+
+func TestCreateAccountWithPublicKey(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	if err := db.CreateAccountWithPublicKey("alice", testAuthorizedKey); err != nil {
+		t.Fatalf("CreateAccountWithPublicKey() error: %v", err)
+	}
+
+	err, acc := db.ReadAccByPkHash(util.PkToHash(testAuthorizedKey))
+	if err != nil || acc == nil {
+		t.Fatalf("Expected account to be found by key hash: %v", err)
+	}
+	if acc.Username != "alice" || !acc.IsAdmin || acc.WebPublicKey == "" {
+		t.Errorf("Unexpected account: %+v", acc)
+	}
+
+	if err := db.UpdateAccountAdmin(acc.Id, false); err != nil {
+		t.Fatalf("UpdateAccountAdmin() error: %v", err)
+	}
+	newKey := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIB7q0Wm2S0tq5l2pS6rW4vF2c8Jb7nKx2Lr9Wq3Yx1zT alice@laptop"
+	if err := db.UpdateAccountPublicKey(acc.Id, newKey); err != nil {
+		t.Fatalf("UpdateAccountPublicKey() error: %v", err)
+	}
+
+	err, acc = db.ReadAccById(acc.Id)
+	if err != nil {
+		t.Fatalf("ReadAccById() error: %v", err)
+	}
+	if acc.IsAdmin {
+		t.Error("Expected admin rights to be revoked")
+	}
+	if acc.Publickey != util.PkToHash(newKey) {
+		t.Error("Expected the new key hash to be stored")
+	}
+}
+
+func TestReadAllAndPurgeDeliveries(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+	if _, err := db.db.Exec(sqlCreateDeliveryQueueTable); err != nil {
+		t.Fatalf("Failed to create delivery_queue table: %v", err)
+	}
+
+	now := time.Now()
+	for i, retry := range []time.Time{now.Add(-time.Minute), now.Add(time.Hour)} {
+		item := &domain.DeliveryQueueItem{
+			Id:           uuid.New(),
+			InboxURI:     "https://remote.example/inbox",
+			ActivityJSON: `{"type":"Create"}`,
+			Attempts:     i,
+			NextRetryAt:  retry,
+			CreatedAt:    now.Add(time.Duration(i) * time.Second),
+		}
+		if err := db.EnqueueDelivery(item); err != nil {
+			t.Fatalf("EnqueueDelivery() error: %v", err)
+		}
+	}
+
+	err, pending := db.ReadPendingDeliveries(10)
+	if err != nil || len(*pending) != 1 {
+		t.Fatalf("Expected 1 pending delivery, got %v (%v)", pending, err)
+	}
+	err, all := db.ReadAllDeliveries(10)
+	if err != nil || len(*all) != 2 {
+		t.Fatalf("Expected 2 queued deliveries, got %v (%v)", all, err)
+	}
+
+	purged, err := db.PurgeDeliveryQueue()
+	if err != nil || purged != 2 {
+		t.Fatalf("Expected 2 purged deliveries, got %d (%v)", purged, err)
+	}
+	_, all = db.ReadAllDeliveries(10)
+	if len(*all) != 0 {
+		t.Errorf("Expected an empty queue, got %d items", len(*all))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -11,24 +12,36 @@ import (
 
 	"github.com/charmbracelet/ssh"
 	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
 	"github.com/deemkeen/stegodon/util"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"log"
 	"modernc.org/sqlite"
 	sqlitelib "modernc.org/sqlite/lib"
+	"os"
 	"time"
 )
 
-// DB is the database struct.
+// DB is the database struct. It runs the same queries on SQLite, the default,
+// and on PostgreSQL, see OpenPostgres.
 type DB struct {
-	db *sql.DB
+	db       *sql.DB
+	postgres bool
 }
 
 var (
-	dbInstance *DB
-	dbOnce     sync.Once
+	dbInstance  *DB
+	dbOnce      sync.Once
+	databaseURL string
 )
 
+// Configure selects the database GetDB opens: PostgreSQL at url, or the SQLite
+// database file when url is empty. It must be called before the first GetDB.
+func Configure(url string) {
+	databaseURL = url
+}
+
 const (
 	//TODO add indices
 
@@ -61,29 +74,36 @@ const (
                         )`
 	sqlInsertNote     = `INSERT INTO notes(id, user_id, message, created_at) VALUES (?, ?, ?, ?)`
 	sqlUpdateNote     = `UPDATE notes SET message = ?, edited_at = ? WHERE id = ?`
+	sqlUpdateArticle  = `UPDATE notes SET title = ?, message = ?, edited_at = ? WHERE id = ?`
 	sqlDeleteNote     = `DELETE FROM notes WHERE id = ?`
-	sqlSelectNoteById = `SELECT notes.id, accounts.username, notes.message, notes.created_at, notes.edited_at, COALESCE(notes.like_count, 0), COALESCE(notes.boost_count, 0) FROM notes
+	sqlInsertArticle  = `INSERT INTO notes(id, user_id, message, title, created_at) VALUES (?, ?, ?, ?, ?)`
+	sqlSelectNoteById = `SELECT notes.id, accounts.username, notes.message, COALESCE(notes.title, ''), notes.created_at, notes.edited_at, COALESCE(notes.like_count, 0), COALESCE(notes.boost_count, 0) FROM notes
     														INNER JOIN accounts ON accounts.id = notes.user_id
                                                             WHERE notes.id = ?`
-	sqlSelectNotesByUserId = `SELECT notes.id, accounts.username, notes.message, notes.created_at, notes.edited_at, notes.in_reply_to_uri, notes.like_count, notes.boost_count FROM notes
+	sqlSelectNotesByUserId = `SELECT notes.id, accounts.username, notes.message, COALESCE(notes.title, ''), notes.created_at, notes.edited_at, notes.in_reply_to_uri, notes.like_count, notes.boost_count FROM notes
     														INNER JOIN accounts ON accounts.id = notes.user_id
                                                             WHERE notes.user_id = ?
                                                             ORDER BY notes.created_at DESC`
-	sqlSelectNotesByUsername = `SELECT notes.id, accounts.username, notes.message, notes.created_at, notes.edited_at, notes.in_reply_to_uri FROM notes
+	sqlSelectNotesByUsername = `SELECT notes.id, accounts.username, notes.message, COALESCE(notes.title, ''), notes.created_at, notes.edited_at, notes.in_reply_to_uri FROM notes
     														INNER JOIN accounts ON accounts.id = notes.user_id
                                                             WHERE accounts.username = ?
                                                             ORDER BY notes.created_at DESC`
-	sqlSelectAllNotes = `SELECT notes.id, accounts.username, notes.message, notes.created_at, notes.edited_at, notes.in_reply_to_uri, COALESCE(notes.like_count, 0), COALESCE(notes.boost_count, 0) FROM notes
+	sqlSelectAllNotes = `SELECT notes.id, accounts.username, notes.message, COALESCE(notes.title, ''), notes.created_at, notes.edited_at, notes.in_reply_to_uri, COALESCE(notes.like_count, 0), COALESCE(notes.boost_count, 0) FROM notes
     														INNER JOIN accounts ON accounts.id = notes.user_id
                                                             ORDER BY notes.created_at DESC`
+	// Notes for paged reads; conditions, order and limit are appended
+	sqlSelectNotesPage = `SELECT notes.id, accounts.username, notes.message, COALESCE(notes.title, ''), notes.created_at, notes.edited_at, notes.in_reply_to_uri, COALESCE(notes.like_count, 0), COALESCE(notes.boost_count, 0) FROM notes
+		INNER JOIN accounts ON accounts.id = notes.user_id
+		WHERE 1 = 1`
 
 	// Local users and local timeline queries
 	sqlSelectAllAccounts        = `SELECT id, username, publickey, created_at, first_time_login, web_public_key, web_private_key, display_name, summary, avatar_url, is_admin, muted, banned, last_ip FROM accounts WHERE first_time_login = 0 ORDER BY username ASC`
 	sqlSelectAllAccountsAdmin   = `SELECT id, username, publickey, created_at, first_time_login, web_public_key, web_private_key, display_name, summary, avatar_url, is_admin, muted, banned, last_ip FROM accounts ORDER BY created_at ASC`
 	sqlCountAccounts            = `SELECT COUNT(*) FROM accounts`
 	sqlCountLocalPosts          = `SELECT COUNT(*) FROM notes`
-	sqlCountActiveUsersMonth    = `SELECT COUNT(DISTINCT user_id) FROM notes WHERE created_at >= datetime('now', '-30 days')`
-	sqlCountActiveUsersHalfYear = `SELECT COUNT(DISTINCT user_id) FROM notes WHERE created_at >= datetime('now', '-180 days')`
+	sqlCountTopLevelNotesByUser = `SELECT COUNT(*) FROM notes WHERE user_id = ? AND (in_reply_to_uri IS NULL OR in_reply_to_uri = '')`
+	sqlCountActiveUsersMonth    = `SELECT COUNT(DISTINCT user_id) FROM notes WHERE created_at >= ?`
+	sqlCountActiveUsersHalfYear = `SELECT COUNT(DISTINCT user_id) FROM notes WHERE created_at >= ?`
 	sqlSelectLocalTimelineNotes = `SELECT notes.id, accounts.username, notes.message, notes.created_at, notes.edited_at FROM notes
 														INNER JOIN accounts ON accounts.id = notes.user_id
 														ORDER BY notes.created_at DESC LIMIT ?`
@@ -97,12 +117,13 @@ const (
 														ORDER BY notes.created_at DESC LIMIT ?`
 
 	// Outbox collection query - returns public notes for ActivityPub outbox
-	sqlSelectPublicNotesByUsername = `SELECT notes.id, notes.user_id, notes.message, notes.created_at, notes.edited_at, notes.visibility, notes.object_uri
+	sqlSelectPublicNotesByUsername = `SELECT notes.id, notes.user_id, notes.message, COALESCE(notes.title, ''), notes.created_at, notes.edited_at, notes.visibility, notes.object_uri
 														FROM notes
 														INNER JOIN accounts ON accounts.id = notes.user_id
-														WHERE accounts.username = ? AND notes.visibility = 'public'
-														ORDER BY notes.created_at DESC
-														LIMIT ? OFFSET ?`
+														WHERE accounts.username = ? AND notes.visibility = 'public'`
+	sqlCountPublicNotesByUsername = `SELECT COUNT(*) FROM notes
+														INNER JOIN accounts ON accounts.id = notes.user_id
+														WHERE accounts.username = ? AND notes.visibility = 'public'`
 )
 
 func (db *DB) CreateAccount(s ssh.Session, username string) (error, bool) {
@@ -134,6 +155,31 @@ func (db *DB) CreateAccByUsername(s ssh.Session, username string, webKeyPair *ut
 	})
 }
 
+// CreateAccountWithPublicKey creates an account for an SSH public key in
+// authorized_keys format, as if its owner had logged in for the first time
+func (db *DB) CreateAccountWithPublicKey(username string, publicKey string) error {
+	keypair := util.GeneratePemKeypair()
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		return db.insertUser(tx, username, publicKey, keypair)
+	})
+}
+
+// UpdateAccountPublicKey replaces the SSH public key (authorized_keys format) an account logs in with
+func (db *DB) UpdateAccountPublicKey(accountId uuid.UUID, publicKey string) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`UPDATE accounts SET publickey = ? WHERE id = ?`, util.PkToHash(publicKey), accountId.String())
+		return err
+	})
+}
+
+// UpdateAccountAdmin grants or revokes admin rights
+func (db *DB) UpdateAccountAdmin(accountId uuid.UUID, isAdmin bool) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`UPDATE accounts SET is_admin = ? WHERE id = ?`, isAdmin, accountId.String())
+		return err
+	})
+}
+
 func (db *DB) CreateNote(userId uuid.UUID, message string) (uuid.UUID, error) {
 	return db.CreateNoteWithReply(userId, message, "")
 }
@@ -149,6 +195,29 @@ func (db *DB) CreateNoteWithReply(userId uuid.UUID, message string, inReplyToURI
 		noteId = id
 		return nil
 	})
+	publishNoteCreated(err, userId, noteId)
+	return noteId, err
+}
+
+// publishNoteCreated announces a committed note to the TUI sessions
+func publishNoteCreated(err error, userId uuid.UUID, noteId uuid.UUID) {
+	if err == nil {
+		events.Publish(events.Event{Kind: events.NoteCreated, AccountId: userId, NoteId: noteId})
+	}
+}
+
+// CreateArticle creates a long-form article: a top-level note with a title and a markdown body
+func (db *DB) CreateArticle(userId uuid.UUID, title string, message string) (uuid.UUID, error) {
+	var noteId uuid.UUID
+	err := db.wrapTransaction(func(tx *sql.Tx) error {
+		id, err := db.insertArticle(tx, userId, title, message)
+		if err != nil {
+			return err
+		}
+		noteId = id
+		return nil
+	})
+	publishNoteCreated(err, userId, noteId)
 	return noteId, err
 }
 
@@ -162,6 +231,14 @@ func (db *DB) UpdateNote(noteId uuid.UUID, message string) error {
 	})
 }
 
+// UpdateArticle changes the title and body of an article
+func (db *DB) UpdateArticle(noteId uuid.UUID, title string, message string) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlUpdateArticle, title, message, time.Now().Format("2006-01-02 15:04:05"), noteId)
+		return err
+	})
+}
+
 func (db *DB) DeleteNoteById(noteId uuid.UUID) error {
 	return db.wrapTransaction(func(tx *sql.Tx) error {
 		err := db.deleteNote(tx, noteId)
@@ -375,6 +452,16 @@ func (db *DB) ReadAccByUsername(username string) (error, *domain.Account) {
 	return err, &tempAcc
 }
 
+// daysAgo returns the time the given number of days ago for comparisons against
+// stored timestamps: in UTC in SQLite's datetime() format, or with its offset on PostgreSQL
+func (db *DB) daysAgo(days int) any {
+	t := time.Now().UTC().AddDate(0, 0, -days)
+	if db.postgres {
+		return t
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
 // parseTimestamp parses a timestamp string from SQLite, handling both ISO 8601 and space-separated formats
 // SQLite driver returns timestamps with Z suffix even though they're stored in local time
 func parseTimestamp(timestampStr string) (time.Time, error) {
@@ -413,7 +500,7 @@ func (db *DB) ReadNotesByUserId(userId uuid.UUID) (error, *[]domain.Note) {
 		var createdAtStr string
 		var editedAtStr sql.NullString
 		var inReplyToURI sql.NullString
-		if err := rows.Scan(&note.Id, &note.CreatedBy, &note.Message, &createdAtStr, &editedAtStr, &inReplyToURI, &note.LikeCount, &note.BoostCount); err != nil {
+		if err := rows.Scan(&note.Id, &note.CreatedBy, &note.Message, &note.Title, &createdAtStr, &editedAtStr, &inReplyToURI, &note.LikeCount, &note.BoostCount); err != nil {
 			return err, &notes
 		}
 
@@ -454,7 +541,7 @@ func (db *DB) ReadNotesByUsername(username string) (error, *[]domain.Note) {
 		var createdAtStr string
 		var editedAtStr sql.NullString
 		var inReplyToURI sql.NullString
-		if err := rows.Scan(&note.Id, &note.CreatedBy, &note.Message, &createdAtStr, &editedAtStr, &inReplyToURI); err != nil {
+		if err := rows.Scan(&note.Id, &note.CreatedBy, &note.Message, &note.Title, &createdAtStr, &editedAtStr, &inReplyToURI); err != nil {
 			return err, &notes
 		}
 
@@ -486,7 +573,7 @@ func (db *DB) ReadNoteId(id uuid.UUID) (error, *domain.Note) {
 	var note domain.Note
 	var createdAtStr string
 	var editedAtStr sql.NullString
-	err := row.Scan(&note.Id, &note.CreatedBy, &note.Message, &createdAtStr, &editedAtStr, &note.LikeCount, &note.BoostCount)
+	err := row.Scan(&note.Id, &note.CreatedBy, &note.Message, &note.Title, &createdAtStr, &editedAtStr, &note.LikeCount, &note.BoostCount)
 	if err == sql.ErrNoRows {
 		return err, nil
 	}
@@ -523,7 +610,7 @@ func (db *DB) ReadAllNotes() (error, *[]domain.Note) {
 		var createdAtStr string
 		var editedAtStr sql.NullString
 		var inReplyToURI sql.NullString
-		if err := rows.Scan(&note.Id, &note.CreatedBy, &note.Message, &createdAtStr, &editedAtStr, &inReplyToURI, &note.LikeCount, &note.BoostCount); err != nil {
+		if err := rows.Scan(&note.Id, &note.CreatedBy, &note.Message, &note.Title, &createdAtStr, &editedAtStr, &inReplyToURI, &note.LikeCount, &note.BoostCount); err != nil {
 			return err, &notes
 		}
 
@@ -550,14 +637,80 @@ func (db *DB) ReadAllNotes() (error, *[]domain.Note) {
 	return nil, &notes
 }
 
-func GetDB() *DB {
+// ReadNotesPageByUserId returns a page of a user's notes, replies included, starting after before
+func (db *DB) ReadNotesPageByUserId(userId uuid.UUID, before domain.Cursor, limit int) (error, *[]domain.Note) {
+	return db.readNotesPage(" AND notes.user_id = ?", []any{userId.String()}, before, limit)
+}
+
+// ReadTopLevelNotes returns a page of local notes that are not replies, starting after before.
+// With a userId other than uuid.Nil only that user's notes are read.
+func (db *DB) ReadTopLevelNotes(userId uuid.UUID, before domain.Cursor, limit int) (error, *[]domain.Note) {
+	filter := " AND (notes.in_reply_to_uri IS NULL OR notes.in_reply_to_uri = '')"
+	var args []any
+	if userId != uuid.Nil {
+		filter += " AND notes.user_id = ?"
+		args = append(args, userId.String())
+	}
+	return db.readNotesPage(filter, args, before, limit)
+}
+
+// readNotesPage reads up to limit notes matching filter that are older than before, newest first
+func (db *DB) readNotesPage(filter string, args []any, before domain.Cursor, limit int) (error, *[]domain.Note) {
+	pageFilter, pageArgs := db.cursorFilter("notes.created_at", "notes.id", before)
+	args = append(append(args, pageArgs...), limit)
+	rows, err := db.db.Query(sqlSelectNotesPage+filter+pageFilter+db.cursorOrder("notes.created_at", "notes.id")+" LIMIT ?", args...)
+	if err != nil {
+		return err, nil
+	}
+	defer rows.Close()
+
+	notes := []domain.Note{}
+	for rows.Next() {
+		var note domain.Note
+		var createdAtStr string
+		var editedAtStr sql.NullString
+		var inReplyToURI sql.NullString
+		if err := rows.Scan(&note.Id, &note.CreatedBy, &note.Message, &note.Title, &createdAtStr, &editedAtStr, &inReplyToURI, &note.LikeCount, &note.BoostCount); err != nil {
+			return err, &notes
+		}
+
+		if parsedTime, err := parseTimestamp(createdAtStr); err == nil {
+			note.CreatedAt = parsedTime
+		}
+		if editedAtStr.Valid {
+			if parsedTime, err := parseTimestamp(editedAtStr.String); err == nil {
+				note.EditedAt = &parsedTime
+			}
+		}
+		note.InReplyToURI = inReplyToURI.String
+
+		notes = append(notes, note)
+	}
+	if err = rows.Err(); err != nil {
+		return err, &notes
+	}
+	return nil, &notes
+}
+
+// GetDB returns the shared database, opening it on first use
+func GetDB() Store {
 	dbOnce.Do(func() {
+		if databaseURL != "" {
+			db, err := OpenPostgres(databaseURL)
+			if err != nil {
+				panic(err)
+			}
+			log.Printf("Using PostgreSQL database")
+			dbInstance = db
+			return
+		}
+
 		// Resolve database path (local first, then user config dir)
 		dbPath := util.ResolveFilePath("database.db")
 		log.Printf("Using database at: %s", dbPath)
 
 		// Open database connection
-		db, err := sql.Open("sqlite", dbPath)
+		db, err := sql.Open(instrumentedDriverName, dbPath)
 		if err != nil {
 			panic(err)
 		}
@@ -605,8 +758,97 @@ func GetDB() *DB {
 	return dbInstance
 }
 
+// Open opens the database at path for short-lived tools such as the offline
+// admin commands. Unlike GetDB it neither creates nor migrates the schema and
+// uses a single connection with a busy timeout, so it can run next to a live
+// server: the server keeps the database in WAL mode, where readers never block
+// and a write waits for the server's current transaction instead of failing.
+func Open(path string) (*DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("no database at %s: %w", path, err)
+	}
+
+	sqlDB, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return &DB{db: sqlDB}, nil
+}
+
+// Close closes the database connection
+func (db *DB) Close() error {
+	return db.db.Close()
+}
+
+// Ping checks that the database can still be reached
+func (db *DB) Ping() error {
+	return db.db.Ping()
+}
+
+// ReadDatabaseSize returns the size of the main database file in bytes.
+// The WAL file is not included. On PostgreSQL it is the size of the database.
+func (db *DB) ReadDatabaseSize() (int64, error) {
+	query := "SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()"
+	if db.postgres {
+		query = "SELECT pg_database_size(current_database())"
+	}
+	var size int64
+	err := db.db.QueryRow(query).Scan(&size)
+	return size, err
+}
+
+// SnapshotTo writes a consistent copy of the database to path with VACUUM INTO, while
+// other connections keep reading and writing. path must not exist yet.
+func (db *DB) SnapshotTo(path string) error {
+	if db.postgres {
+		return fmt.Errorf("snapshots are only supported for SQLite, use pg_dump for PostgreSQL")
+	}
+	_, err := db.db.Exec("VACUUM INTO ?", path)
+	return err
+}
+
+// CheckIntegrity runs SQLite's integrity check and returns its first problem, if any
+func (db *DB) CheckIntegrity() error {
+	if db.postgres {
+		return nil
+	}
+	var result string
+	if err := db.db.QueryRow("PRAGMA integrity_check").Scan(&result); err != nil {
+		return err
+	}
+	if result != "ok" {
+		return fmt.Errorf("integrity check failed: %s", result)
+	}
+	return nil
+}
+
+// LockExclusive locks the database against every other connection until it is closed,
+// and fails at once while another process, like a running server, has it open. In WAL
+// mode BEGIN EXCLUSIVE still admits readers, so the connection also switches to
+// exclusive locking mode. Only for databases from Open, which use one connection.
+func (db *DB) LockExclusive() error {
+	if db.postgres {
+		return fmt.Errorf("exclusive locks are only supported for SQLite")
+	}
+	for _, statement := range []string{"PRAGMA busy_timeout = 0", "PRAGMA locking_mode = EXCLUSIVE", "BEGIN EXCLUSIVE"} {
+		if _, err := db.db.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CreateDB creates the database.
 func (db *DB) CreateDB() error {
+	// The PostgreSQL migrations create all tables
+	if db.postgres {
+		return nil
+	}
 	return db.wrapTransaction(func(tx *sql.Tx) error {
 		err := db.createUserTable(tx)
 		if err != nil {
@@ -622,12 +864,6 @@ func (db *DB) CreateDB() error {
 	})
 }
 
-// RunActivityPubMigrations runs ActivityPub-specific migrations
-func (db *DB) RunActivityPubMigrations() error {
-	log.Println("Running ActivityPub migrations...")
-	return db.RunMigrations()
-}
-
 func (db *DB) createUserTable(tx *sql.Tx) error {
 	_, err := tx.Exec(sqlCreateUserTable)
 	return err
@@ -686,6 +922,12 @@ func (db *DB) insertNoteWithReply(tx *sql.Tx, userId uuid.UUID, message string,
 	return noteId, nil
 }
 
+func (db *DB) insertArticle(tx *sql.Tx, userId uuid.UUID, title string, message string) (uuid.UUID, error) {
+	noteId := uuid.New()
+	_, err := tx.Exec(sqlInsertArticle, noteId, userId, message, title, time.Now().Format("2006-01-02 15:04:05"))
+	return noteId, err
+}
+
 func (db *DB) updateNote(tx *sql.Tx, noteId uuid.UUID, message string) error {
 	_, err := tx.Exec(sqlUpdateNote, message, time.Now().Format("2006-01-02 15:04:05"), noteId)
 	return err
@@ -752,12 +994,28 @@ func (db *DB) wrapTransaction(f func(tx *sql.Tx) error) error {
 	return nil
 }
 
+// IsUniqueViolation reports whether err is a UNIQUE or primary key constraint
+// violation, on SQLite or on PostgreSQL
+func IsUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505" // unique_violation
+	}
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
 // Remote Accounts queries
 const (
-	sqlInsertRemoteAccount      = `INSERT INTO remote_accounts(id, username, domain, actor_uri, display_name, summary, inbox_uri, outbox_uri, public_key_pem, avatar_url, last_fetched_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	sqlSelectRemoteAccountByURI = `SELECT id, username, domain, actor_uri, display_name, summary, inbox_uri, outbox_uri, public_key_pem, avatar_url, last_fetched_at FROM remote_accounts WHERE actor_uri = ?`
-	sqlSelectRemoteAccountById  = `SELECT id, username, domain, actor_uri, display_name, summary, inbox_uri, outbox_uri, public_key_pem, avatar_url, last_fetched_at FROM remote_accounts WHERE id = ?`
-	sqlUpdateRemoteAccount      = `UPDATE remote_accounts SET display_name = ?, summary = ?, inbox_uri = ?, outbox_uri = ?, public_key_pem = ?, avatar_url = ?, last_fetched_at = ? WHERE actor_uri = ?`
+	sqlInsertRemoteAccount         = `INSERT INTO remote_accounts(id, username, domain, actor_uri, display_name, summary, inbox_uri, outbox_uri, public_key_pem, avatar_url, last_fetched_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	sqlSelectRemoteAccountByURI    = `SELECT id, username, domain, actor_uri, display_name, summary, inbox_uri, outbox_uri, public_key_pem, avatar_url, last_fetched_at FROM remote_accounts WHERE actor_uri = ?`
+	sqlSelectRemoteAccountById     = `SELECT id, username, domain, actor_uri, display_name, summary, inbox_uri, outbox_uri, public_key_pem, avatar_url, last_fetched_at FROM remote_accounts WHERE id = ?`
+	sqlSelectRemoteAccountByHandle = `SELECT id, username, domain, actor_uri, display_name, summary, inbox_uri, outbox_uri, public_key_pem, avatar_url, last_fetched_at FROM remote_accounts WHERE LOWER(username) = LOWER(?) AND LOWER(domain) = LOWER(?)`
+	// A successful fetch also clears the refresh failure state
+	sqlUpdateRemoteAccount = `UPDATE remote_accounts SET display_name = ?, summary = ?, inbox_uri = ?, outbox_uri = ?, public_key_pem = ?, avatar_url = ?, last_fetched_at = ?,
+		fetch_failures = 0, last_failure_at = NULL, gone_at = NULL WHERE actor_uri = ?`
 )
 
 func (db *DB) CreateRemoteAccount(acc *domain.RemoteAccount) error {
@@ -833,6 +1091,31 @@ func (db *DB) ReadRemoteAccountById(id uuid.UUID) (error, *domain.RemoteAccount)
 	return nil, &acc
 }
 
+// ReadRemoteAccountByHandle reads a cached remote account by username and domain
+func (db *DB) ReadRemoteAccountByHandle(username, userDomain string) (error, *domain.RemoteAccount) {
+	row := db.db.QueryRow(sqlSelectRemoteAccountByHandle, username, userDomain)
+	var acc domain.RemoteAccount
+	var idStr string
+	err := row.Scan(
+		&idStr,
+		&acc.Username,
+		&acc.Domain,
+		&acc.ActorURI,
+		&acc.DisplayName,
+		&acc.Summary,
+		&acc.InboxURI,
+		&acc.OutboxURI,
+		&acc.PublicKeyPem,
+		&acc.AvatarURL,
+		&acc.LastFetchedAt,
+	)
+	if err != nil {
+		return err, nil
+	}
+	acc.Id, _ = uuid.Parse(idStr)
+	return nil, &acc
+}
+
 func (db *DB) UpdateRemoteAccount(acc *domain.RemoteAccount) error {
 	return db.wrapTransaction(func(tx *sql.Tx) error {
 		_, err := tx.Exec(sqlUpdateRemoteAccount,
@@ -883,6 +1166,95 @@ func (db *DB) ReadAllRemoteAccounts() (error, []domain.RemoteAccount) {
 	return nil, accounts
 }
 
+// Remote account refresh queries
+const (
+	// Accounts due for a refetch, oldest first; accounts whose last refetch failed wait until retryBefore
+	sqlSelectStaleRemoteAccounts = `SELECT id, username, domain, actor_uri, COALESCE(display_name, ''), COALESCE(summary, ''), inbox_uri, COALESCE(outbox_uri, ''), public_key_pem, COALESCE(avatar_url, ''), last_fetched_at
+		FROM remote_accounts
+		WHERE gone_at IS NULL AND last_fetched_at < ? AND (last_failure_at IS NULL OR last_failure_at < ?)
+		ORDER BY last_fetched_at ASC LIMIT ?`
+	sqlRecordRemoteAccountFailure  = `UPDATE remote_accounts SET fetch_failures = fetch_failures + ?, last_failure_at = ? WHERE id = ?`
+	sqlSelectRemoteAccountFailures = `SELECT COALESCE(fetch_failures, 0) FROM remote_accounts WHERE id = ?`
+	sqlMarkRemoteAccountGone       = `UPDATE remote_accounts SET gone_at = ? WHERE id = ?`
+	sqlSelectRemoteAccountStats    = `SELECT COUNT(*),
+		COALESCE(SUM(CASE WHEN gone_at IS NULL AND last_fetched_at < ? THEN 1 ELSE 0 END), 0),
+		COALESCE(SUM(CASE WHEN gone_at IS NULL AND last_failure_at IS NOT NULL THEN 1 ELSE 0 END), 0),
+		COALESCE(SUM(CASE WHEN gone_at IS NOT NULL THEN 1 ELSE 0 END), 0)
+		FROM remote_accounts`
+)
+
+// ReadStaleRemoteAccounts returns up to limit accounts last fetched before fetchedBefore,
+// skipping gone accounts and accounts whose last refetch failed after retryBefore
+func (db *DB) ReadStaleRemoteAccounts(fetchedBefore, retryBefore time.Time, limit int) (error, *[]domain.RemoteAccount) {
+	rows, err := db.db.Query(sqlSelectStaleRemoteAccounts, fetchedBefore, retryBefore, limit)
+	if err != nil {
+		return err, nil
+	}
+	defer rows.Close()
+
+	var accounts []domain.RemoteAccount
+	for rows.Next() {
+		var acc domain.RemoteAccount
+		var idStr string
+		if err := rows.Scan(
+			&idStr,
+			&acc.Username,
+			&acc.Domain,
+			&acc.ActorURI,
+			&acc.DisplayName,
+			&acc.Summary,
+			&acc.InboxURI,
+			&acc.OutboxURI,
+			&acc.PublicKeyPem,
+			&acc.AvatarURL,
+			&acc.LastFetchedAt,
+		); err != nil {
+			return err, nil
+		}
+		acc.Id, _ = uuid.Parse(idStr)
+		accounts = append(accounts, acc)
+	}
+	if err := rows.Err(); err != nil {
+		return err, nil
+	}
+	return nil, &accounts
+}
+
+// RecordRemoteAccountFetchFailure records a failed refetch and returns the number of
+// consecutive not-found failures. Only notFound (404/410) failures count towards gone.
+func (db *DB) RecordRemoteAccountFetchFailure(id uuid.UUID, notFound bool) (int, error) {
+	increment := 0
+	if notFound {
+		increment = 1
+	}
+	failures := 0
+	err := db.wrapTransaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(sqlRecordRemoteAccountFailure, increment, time.Now(), id.String()); err != nil {
+			return err
+		}
+		return tx.QueryRow(sqlSelectRemoteAccountFailures, id.String()).Scan(&failures)
+	})
+	return failures, err
+}
+
+// MarkRemoteAccountGone marks a remote account as gone; it is no longer refreshed
+func (db *DB) MarkRemoteAccountGone(id uuid.UUID) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlMarkRemoteAccountGone, time.Now(), id.String())
+		return err
+	})
+}
+
+// ReadRemoteAccountStats counts cached remote accounts by refresh state
+func (db *DB) ReadRemoteAccountStats(fetchedBefore time.Time) (error, *domain.RemoteAccountStats) {
+	var stats domain.RemoteAccountStats
+	err := db.db.QueryRow(sqlSelectRemoteAccountStats, fetchedBefore).Scan(&stats.Total, &stats.Stale, &stats.Failing, &stats.Gone)
+	if err != nil {
+		return err, nil
+	}
+	return nil, &stats
+}
+
 // Follow queries
 const (
 	sqlInsertFollow                  = `INSERT INTO follows(id, account_id, target_account_id, uri, accepted, created_at, is_local) VALUES (?, ?, ?, ?, ?, ?, ?)`
@@ -1013,9 +1385,11 @@ func (db *DB) CleanupOrphanedFollows() error {
 
 // Activity queries
 const (
-	sqlInsertActivity      = `INSERT INTO activities(id, activity_uri, activity_type, actor_uri, object_uri, object_url, in_reply_to, raw_json, processed, local, created_at, from_relay) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	sqlInsertActivity      = `INSERT INTO activities(id, activity_uri, activity_type, actor_uri, object_uri, object_url, in_reply_to, raw_json, processed, local, created_at, from_relay, backfilled) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	sqlUpdateActivity      = `UPDATE activities SET raw_json = ?, processed = ?, object_uri = ? WHERE id = ?`
 	sqlSelectActivityByURI = `SELECT id, activity_uri, activity_type, actor_uri, object_uri, raw_json, processed, local, created_at FROM activities WHERE activity_uri = ?`
+	sqlSelectActivityById  = `SELECT id, activity_uri, activity_type, actor_uri, COALESCE(object_uri, ''), COALESCE(object_url, ''), raw_json, processed, local, created_at,
+		COALESCE(reply_count, 0), COALESCE(like_count, 0), COALESCE(boost_count, 0) FROM activities WHERE id = ?`
 )
 
 func (db *DB) CreateActivity(activity *domain.Activity) error {
@@ -1033,6 +1407,7 @@ func (db *DB) CreateActivity(activity *domain.Activity) error {
 			activity.Local,
 			activity.CreatedAt.Format("2006-01-02 15:04:05"),
 			activity.FromRelay,
+			activity.Backfilled,
 		)
 		return err
 	})
@@ -1075,6 +1450,33 @@ func (db *DB) ReadActivityByURI(uri string) (error, *domain.Activity) {
 	return nil, &activity
 }
 
+// ReadActivityById reads an activity including its engagement counters
+func (db *DB) ReadActivityById(id uuid.UUID) (error, *domain.Activity) {
+	row := db.db.QueryRow(sqlSelectActivityById, id.String())
+	var activity domain.Activity
+	var idStr string
+	err := row.Scan(
+		&idStr,
+		&activity.ActivityURI,
+		&activity.ActivityType,
+		&activity.ActorURI,
+		&activity.ObjectURI,
+		&activity.ObjectURL,
+		&activity.RawJSON,
+		&activity.Processed,
+		&activity.Local,
+		&activity.CreatedAt,
+		&activity.ReplyCount,
+		&activity.LikeCount,
+		&activity.BoostCount,
+	)
+	if err != nil {
+		return err, nil
+	}
+	activity.Id, _ = uuid.Parse(idStr)
+	return nil, &activity
+}
+
 // ReadActivityByObjectURI reads an activity by the object URI
 // First tries exact match on object_uri column, falls back to searching raw_json for older activities
 func (db *DB) ReadActivityByObjectURI(objectURI string) (error, *domain.Activity) {
@@ -1174,6 +1576,42 @@ func (db *DB) ReadFederatedActivities(accountId uuid.UUID, limit int) (error, *[
 	return nil, &activities
 }
 
+// Remote profile queries
+const (
+	// Stored top-level posts of a remote actor, newest first
+	sqlSelectPostsByActorURI = `SELECT id, activity_uri, activity_type, actor_uri, COALESCE(object_uri, ''), COALESCE(object_url, ''), raw_json, processed, local, created_at,
+		COALESCE(reply_count, 0), COALESCE(like_count, 0), COALESCE(boost_count, 0)
+		FROM activities
+		WHERE actor_uri = ? AND activity_type = 'Create' AND (in_reply_to IS NULL OR in_reply_to = '')
+		ORDER BY created_at DESC LIMIT ?`
+)
+
+// ReadPostsByActorURI reads the stored top-level posts of a remote actor for its profile
+func (db *DB) ReadPostsByActorURI(actorURI string, limit int) (error, *[]domain.Activity) {
+	rows, err := db.db.Query(sqlSelectPostsByActorURI, actorURI, limit)
+	if err != nil {
+		return err, nil
+	}
+	defer rows.Close()
+
+	var activities []domain.Activity
+	for rows.Next() {
+		var activity domain.Activity
+		var idStr string
+		if err := rows.Scan(&idStr, &activity.ActivityURI, &activity.ActivityType, &activity.ActorURI, &activity.ObjectURI, &activity.ObjectURL,
+			&activity.RawJSON, &activity.Processed, &activity.Local, &activity.CreatedAt,
+			&activity.ReplyCount, &activity.LikeCount, &activity.BoostCount); err != nil {
+			return err, &activities
+		}
+		activity.Id, _ = uuid.Parse(idStr)
+		activities = append(activities, activity)
+	}
+	if err = rows.Err(); err != nil {
+		return err, &activities
+	}
+	return nil, &activities
+}
+
 // Home Timeline queries - combines local notes and remote activities
 const (
 	// Local notes for home timeline: own posts + posts from followed local users (excluding replies)
@@ -1184,8 +1622,7 @@ const (
 		AND (notes.user_id = ? OR notes.user_id IN (
 			SELECT target_account_id FROM follows
 			WHERE account_id = ? AND accepted = 1 AND is_local = 1
-		))
-		ORDER BY notes.created_at DESC LIMIT ?`
+		))`
 
 	// Remote activities for home timeline: posts from followed remote users
 	// Excludes replies (activities where inReplyTo has a URL value, not null)
@@ -1196,16 +1633,95 @@ const (
 		INNER JOIN remote_accounts ra ON ra.actor_uri = a.actor_uri
 		INNER JOIN follows f ON f.target_account_id = ra.id
 		WHERE a.activity_type = 'Create' AND a.local = 0 AND f.account_id = ? AND f.accepted = 1 AND f.is_local = 0
-		AND a.raw_json NOT LIKE '%"inReplyTo":"http%'
-		ORDER BY a.created_at DESC LIMIT ?`
+		AND a.raw_json NOT LIKE '%"inReplyTo":"http%'`
+
+	// Members of the account's lists that are hidden from the home timeline
+	sqlSelectHomeExcludedMembers = `SELECT lm.member_id FROM list_members lm
+		INNER JOIN lists l ON l.id = lm.list_id
+		WHERE l.account_id = ? AND l.exclude_from_home = 1`
+
+	// Members of a single list
+	sqlSelectListMemberIds = `SELECT member_id FROM list_members WHERE list_id = ?`
 )
 
-// ReadHomeTimelinePosts returns a unified home timeline combining local and remote posts
-func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]domain.HomePost) {
-	var posts []domain.HomePost
+// cursorKey is a created_at column truncated to the second, with the "T" of RFC3339
+// timestamps replaced so that all stored formats sort alike. PostgreSQL stores real
+// timestamps and formats them in the session's local time zone.
+func (db *DB) cursorKey(column string) string {
+	if db.postgres {
+		return "to_char(" + column + ", 'YYYY-MM-DD HH24:MI:SS')"
+	}
+	return "replace(substr(" + column + ", 1, 19), 'T', ' ')"
+}
 
-	// Fetch local notes (already excludes replies via sqlSelectHomeLocalNotes WHERE clause)
-	localRows, err := db.db.Query(sqlSelectHomeLocalNotes, accountId.String(), accountId.String(), limit)
+// cursorFilter returns an SQL condition keeping the rows older than before, and its arguments.
+// It is empty for the zero cursor.
+func (db *DB) cursorFilter(timeColumn, idColumn string, before domain.Cursor) (string, []any) {
+	if before.IsZero() {
+		return "", nil
+	}
+	key := db.cursorKey(timeColumn)
+	second := before.CreatedAt.In(time.Local).Format("2006-01-02 15:04:05")
+	return " AND (" + key + " < ? OR (" + key + " = ? AND " + idColumn + " < ?))",
+		[]any{second, second, before.Id.String()}
+}
+
+// cursorOrder returns the ORDER BY clause matching cursorFilter
+func (db *DB) cursorOrder(timeColumn, idColumn string) string {
+	return " ORDER BY " + db.cursorKey(timeColumn) + " DESC, " + idColumn + " DESC"
+}
+
+// timelineScope narrows the home timeline queries to a set of authors.
+// The home timeline (listId == uuid.Nil) leaves out members of lists marked
+// exclude_from_home; a list timeline only keeps the list's members.
+type timelineScope struct {
+	accountId uuid.UUID
+	listId    uuid.UUID
+}
+
+// filter returns an SQL condition restricting column to the scope's authors, and its argument
+func (s timelineScope) filter(column string) (string, string) {
+	if s.listId != uuid.Nil {
+		return " AND " + column + " IN (" + sqlSelectListMemberIds + ")", s.listId.String()
+	}
+	return " AND " + column + " NOT IN (" + sqlSelectHomeExcludedMembers + ")", s.accountId.String()
+}
+
+// ReadHomeTimelinePosts returns a page of the unified home timeline combining local and remote
+// posts, starting after before (the zero cursor starts at the newest post)
+func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, before domain.Cursor, limit int) (error, *[]domain.HomePost) {
+	return db.readScopedTimelinePosts(timelineScope{accountId: accountId}, before, limit)
+}
+
+// ReadListTimelinePosts returns a page of the home timeline restricted to the members of a list
+func (db *DB) ReadListTimelinePosts(accountId uuid.UUID, listId uuid.UUID, before domain.Cursor, limit int) (error, *[]domain.HomePost) {
+	return db.readScopedTimelinePosts(timelineScope{accountId: accountId, listId: listId}, before, limit)
+}
+
+// readScopedTimelinePosts builds a page of the home timeline for the given scope.
+// Relay posts and followed hashtags are only merged into the home timeline itself.
+// Every source query applies the same cursor and limit, so the merged page has no gaps.
+func (db *DB) readScopedTimelinePosts(scope timelineScope, before domain.Cursor, limit int) (error, *[]domain.HomePost) {
+	var posts []domain.HomePost
+	accountId := scope.accountId
+	isHome := scope.listId == uuid.Nil
+	localEmojis := db.readLocalEmojiMap()
+
+	var followedTags []string
+	if isHome {
+		var err error
+		err, followedTags = db.ReadFollowedHashtags(accountId)
+		if err != nil {
+			return err, nil
+		}
+	}
+
+	// Fetch local notes (already excludes replies via sqlSelectHomeLocalNotes WHERE clause)
+	authorFilter, scopeArg := scope.filter("notes.user_id")
+	pageFilter, pageArgs := db.cursorFilter("notes.created_at", "notes.id", before)
+	args := append([]any{accountId.String(), accountId.String(), scopeArg}, pageArgs...)
+	localRows, err := db.db.Query(sqlSelectHomeLocalNotes+authorFilter+pageFilter+db.cursorOrder("notes.created_at", "notes.id")+" LIMIT ?",
+		append(args, limit)...)
 	if err != nil {
 		return err, nil
 	}
@@ -1244,6 +1760,7 @@ func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]d
 			ReplyCount: replyCount,
 			LikeCount:  likeCount,
 			BoostCount: boostCount,
+			Emojis:     emojisForLocalMessage(message, localEmojis),
 		})
 	}
 	if err = localRows.Err(); err != nil {
@@ -1251,7 +1768,11 @@ func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]d
 	}
 
 	// Fetch remote activities (query excludes all replies - only top-level posts)
-	remoteRows, err := db.db.Query(sqlSelectHomeRemoteActivities, accountId.String(), limit)
+	authorFilter, scopeArg = scope.filter("ra.id")
+	pageFilter, pageArgs = db.cursorFilter("a.created_at", "a.id", before)
+	args = append([]any{accountId.String(), scopeArg}, pageArgs...)
+	remoteRows, err := db.db.Query(sqlSelectHomeRemoteActivities+authorFilter+pageFilter+db.cursorOrder("a.created_at", "a.id")+" LIMIT ?",
+		append(args, limit)...)
 	if err != nil {
 		return err, &posts
 	}
@@ -1296,74 +1817,84 @@ func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]d
 			ReplyCount: replyCount,
 			LikeCount:  likeCount,
 			BoostCount: boostCount,
+			Emojis:     util.ExtractEmojiTagsFromJSON(rawJSON),
 		})
 	}
 	if err = remoteRows.Err(); err != nil {
 		return err, &posts
 	}
 
-	// Fetch relay-forwarded activities (marked with from_relay = 1)
-	// These come from both FediBuzz (Announce-wrapped) and YUKIMOCHI (raw Create) relays
-	relayRows, err := db.db.Query(`
-		SELECT a.id, a.actor_uri, a.object_uri, COALESCE(a.object_url, ''), a.raw_json, a.created_at, COALESCE(a.reply_count, 0), COALESCE(a.like_count, 0), COALESCE(a.boost_count, 0)
-		FROM activities a
-		WHERE a.activity_type = 'Create' AND a.local = 0 AND a.from_relay = 1
-		AND a.raw_json NOT LIKE '%"inReplyTo":"http%'
-		ORDER BY a.created_at DESC LIMIT ?`, limit)
-	if err != nil {
-		return err, &posts
-	}
-	defer relayRows.Close()
-
-	for relayRows.Next() {
-		var idStr string
-		var actorURI string
-		var objectURI string
-		var objectURL string
-		var rawJSON string
-		var createdAtStr string
-		var replyCount int
-		var likeCount int
-		var boostCount int
-
-		if err := relayRows.Scan(&idStr, &actorURI, &objectURI, &objectURL, &rawJSON, &createdAtStr, &replyCount, &likeCount, &boostCount); err != nil {
+	// List timelines only show their members, so relay posts are home-only
+	if isHome {
+		// Fetch relay-forwarded activities (marked with from_relay = 1)
+		// These come from both FediBuzz (Announce-wrapped) and YUKIMOCHI (raw Create) relays
+		pageFilter, pageArgs = db.cursorFilter("a.created_at", "a.id", before)
+		relayRows, err := db.db.Query(`
+			SELECT a.id, a.actor_uri, a.object_uri, COALESCE(a.object_url, ''), a.raw_json, a.created_at, COALESCE(a.reply_count, 0), COALESCE(a.like_count, 0), COALESCE(a.boost_count, 0)
+			FROM activities a
+			WHERE a.activity_type = 'Create' AND a.local = 0 AND a.from_relay = 1
+			AND a.raw_json NOT LIKE '%"inReplyTo":"http%'`+pageFilter+db.cursorOrder("a.created_at", "a.id")+` LIMIT ?`,
+			append(pageArgs, limit)...)
+		if err != nil {
 			return err, &posts
 		}
+		defer relayRows.Close()
+
+		for relayRows.Next() {
+			var idStr string
+			var actorURI string
+			var objectURI string
+			var objectURL string
+			var rawJSON string
+			var createdAtStr string
+			var replyCount int
+			var likeCount int
+			var boostCount int
+
+			if err := relayRows.Scan(&idStr, &actorURI, &objectURI, &objectURL, &rawJSON, &createdAtStr, &replyCount, &likeCount, &boostCount); err != nil {
+				return err, &posts
+			}
 
-		activityId, _ := uuid.Parse(idStr)
-		parsedTime, _ := parseTimestamp(createdAtStr)
-
-		// Extract content from raw JSON
-		content := extractContentFromJSON(rawJSON)
-		// If content is empty but we have a URL, show the URL as content
-		if content == "" && objectURL != "" {
-			content = objectURL
-		}
+			activityId, _ := uuid.Parse(idStr)
+			parsedTime, _ := parseTimestamp(createdAtStr)
 
-		// Extract author info from actorURI (format: https://domain/users/username)
-		author := extractAuthorFromActorURI(actorURI)
+			// Extract content from raw JSON
+			content := extractContentFromJSON(rawJSON)
+			// If content is empty but we have a URL, show the URL as content
+			if content == "" && objectURL != "" {
+				content = objectURL
+			}
 
-		posts = append(posts, domain.HomePost{
-			ID:         activityId,
-			Author:     author,
-			Content:    content,
-			Time:       parsedTime,
-			ObjectURI:  objectURI,
-			ObjectURL:  objectURL,
-			IsLocal:    false,
-			NoteID:     uuid.Nil,
-			ReplyCount: replyCount,
-			LikeCount:  likeCount,
-			BoostCount: boostCount,
-		})
-	}
-	if err = relayRows.Err(); err != nil {
-		return err, &posts
+			// Extract author info from actorURI (format: https://domain/users/username)
+			author := extractAuthorFromActorURI(actorURI)
+
+			posts = append(posts, domain.HomePost{
+				ID:         activityId,
+				Author:     author,
+				Content:    content,
+				Time:       parsedTime,
+				ObjectURI:  objectURI,
+				ObjectURL:  objectURL,
+				IsLocal:    false,
+				NoteID:     uuid.Nil,
+				ReplyCount: replyCount,
+				LikeCount:  likeCount,
+				BoostCount: boostCount,
+				ViaHashtag: matchFollowedHashtag(util.ExtractHashtagsFromJSON(rawJSON), followedTags),
+				Emojis:     util.ExtractEmojiTagsFromJSON(rawJSON),
+			})
+		}
+		if err = relayRows.Err(); err != nil {
+			return err, &posts
+		}
 	}
 
 	// Fetch posts boosted by the current user or by local users that the current user follows
 	// Uses UNION to allow index usage (OR prevents index optimization)
 	// Excludes self-boosts of your own posts (they already appear as original posts)
+	authorFilter, scopeArg = scope.filter("b.account_id")
+	pageFilter, pageArgs = db.cursorFilter("boost_time", "id", before)
+	args = append([]any{accountId.String(), accountId.String(), scopeArg, accountId.String(), scopeArg}, pageArgs...)
 	boostedLocalRows, err := db.db.Query(`
 		SELECT id, username, message, boost_time, object_uri, reply_count, like_count, boost_count, booster_username
 		FROM (
@@ -1375,7 +1906,7 @@ func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]d
 			INNER JOIN accounts booster ON booster.id = b.account_id
 			INNER JOIN notes n ON n.id = b.note_id
 			INNER JOIN accounts a ON a.id = n.user_id
-			WHERE b.account_id = ? AND n.user_id != ?
+			WHERE b.account_id = ? AND n.user_id != ?`+authorFilter+`
 
 			UNION
 
@@ -1388,9 +1919,10 @@ func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]d
 			INNER JOIN notes n ON n.id = b.note_id
 			INNER JOIN accounts a ON a.id = n.user_id
 			INNER JOIN follows f ON f.target_account_id = b.account_id AND f.account_id = ? AND f.accepted = 1
-		)
-		ORDER BY boost_time DESC LIMIT ?`,
-		accountId.String(), accountId.String(), accountId.String(), limit)
+			WHERE 1 = 1`+authorFilter+`
+		) boosted
+		WHERE 1 = 1`+pageFilter+db.cursorOrder("boost_time", "id")+` LIMIT ?`,
+		append(args, limit)...)
 	if err != nil {
 		return err, &posts
 	}
@@ -1431,6 +1963,7 @@ func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]d
 			LikeCount:  likeCount,
 			BoostCount: boostCount,
 			BoostedBy:  "@" + boosterUsername,
+			Emojis:     emojisForLocalMessage(message, localEmojis),
 		})
 	}
 	if err = boostedLocalRows.Err(); err != nil {
@@ -1439,6 +1972,7 @@ func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]d
 
 	// Fetch remote posts boosted by the current user or by local users that the current user follows
 	// Uses UNION to allow index usage (OR prevents index optimization)
+	args = append([]any{accountId.String(), scopeArg, accountId.String(), scopeArg}, pageArgs...)
 	boostedRemoteRows, err := db.db.Query(`
 		SELECT id, actor_uri, object_uri, object_url, raw_json, boost_time, username, domain,
 		       reply_count, like_count, boost_count, booster_username
@@ -1452,7 +1986,7 @@ func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]d
 			INNER JOIN accounts booster ON booster.id = b.account_id
 			INNER JOIN activities act ON act.object_uri = b.object_uri
 			INNER JOIN remote_accounts ra ON ra.actor_uri = act.actor_uri
-			WHERE b.account_id = ? AND b.object_uri IS NOT NULL AND b.object_uri != ''
+			WHERE b.account_id = ? AND b.object_uri IS NOT NULL AND b.object_uri != ''`+authorFilter+`
 
 			UNION
 
@@ -1466,10 +2000,10 @@ func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]d
 			INNER JOIN activities act ON act.object_uri = b.object_uri
 			INNER JOIN remote_accounts ra ON ra.actor_uri = act.actor_uri
 			INNER JOIN follows f ON f.target_account_id = b.account_id AND f.account_id = ? AND f.accepted = 1
-			WHERE b.object_uri IS NOT NULL AND b.object_uri != ''
-		)
-		ORDER BY boost_time DESC LIMIT ?`,
-		accountId.String(), accountId.String(), limit)
+			WHERE b.object_uri IS NOT NULL AND b.object_uri != ''`+authorFilter+`
+		) boosted
+		WHERE 1 = 1`+pageFilter+db.cursorOrder("boost_time", "id")+` LIMIT ?`,
+		append(args, limit)...)
 	if err != nil {
 		return err, &posts
 	}
@@ -1516,6 +2050,7 @@ func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]d
 			LikeCount:  likeCount,
 			BoostCount: boostCount,
 			BoostedBy:  "@" + boosterUsername,
+			Emojis:     util.ExtractEmojiTagsFromJSON(rawJSON),
 		})
 	}
 	if err = boostedRemoteRows.Err(); err != nil {
@@ -1524,6 +2059,9 @@ func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]d
 
 	// Fetch boosts from followed REMOTE users (remote_account_id is set)
 	// These are boosts where the booster is a remote user that the current user follows
+	authorFilter, scopeArg = scope.filter("b.remote_account_id")
+	pageFilter, pageArgs = db.cursorFilter("b.created_at", "act.id", before)
+	args = append([]any{accountId.String(), scopeArg}, pageArgs...)
 	remoteBoosterRows, err := db.db.Query(`
 		SELECT act.id, act.actor_uri, act.object_uri, COALESCE(act.object_url, '') as object_url,
 		       act.raw_json, b.created_at as boost_time, ra_author.username, ra_author.domain,
@@ -1536,9 +2074,9 @@ func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]d
 		INNER JOIN remote_accounts ra_author ON ra_author.actor_uri = act.actor_uri
 		INNER JOIN follows f ON f.target_account_id = b.remote_account_id AND f.account_id = ? AND f.accepted = 1
 		WHERE b.remote_account_id IS NOT NULL AND b.remote_account_id != ''
-		AND b.object_uri IS NOT NULL AND b.object_uri != ''
-		ORDER BY b.created_at DESC LIMIT ?`,
-		accountId.String(), limit)
+		AND b.object_uri IS NOT NULL AND b.object_uri != ''`+authorFilter+pageFilter+
+		db.cursorOrder("b.created_at", "act.id")+` LIMIT ?`,
+		append(args, limit)...)
 	if err != nil {
 		return err, &posts
 	}
@@ -1582,13 +2120,24 @@ func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]d
 			LikeCount:  likeCount,
 			BoostCount: boostCount,
 			BoostedBy:  "@" + boosterUsername + "@" + boosterDomain,
+			Emojis:     util.ExtractEmojiTagsFromJSON(rawJSON),
 		})
 	}
 	if err = remoteBoosterRows.Err(); err != nil {
 		return err, &posts
 	}
 
+	// Merge local and remote posts carrying followed hashtags (labelled "via #tag")
+	if len(followedTags) > 0 {
+		err, tagPosts := db.readHashtagPosts(followedTags, before, limit, localEmojis)
+		if err != nil {
+			return err, &posts
+		}
+		posts = append(posts, tagPosts...)
+	}
+
 	// Deduplicate posts - prefer non-boosted version (original) over boosted
+	// Followed-hashtag posts come last, so posts from followed accounts keep their plain label
 	// Use a map to track seen posts by ID
 	seen := make(map[uuid.UUID]int) // maps ID to index in posts slice
 	var dedupedPosts []domain.HomePost
@@ -1606,9 +2155,9 @@ func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]d
 	}
 	posts = dedupedPosts
 
-	// Sort combined posts by time (newest first) using efficient sort
+	// Sort combined posts newest first, in the same (second, id) order the queries use
 	sort.Slice(posts, func(i, j int) bool {
-		return posts[i].Time.After(posts[j].Time)
+		return posts[i].Cursor().After(posts[j].Cursor())
 	})
 
 	// Limit to requested amount
@@ -1616,6 +2165,11 @@ func (db *DB) ReadHomeTimelinePosts(accountId uuid.UUID, limit int) (error, *[]d
 		posts = posts[:limit]
 	}
 
+	// Attach emoji reaction counts to the posts that will be displayed
+	for i := range posts {
+		posts[i].Reactions = db.readReactionCountsForPost(posts[i].IsLocal, posts[i].NoteID, posts[i].ObjectURI)
+	}
+
 	return nil, &posts
 }
 
@@ -1782,6 +2336,9 @@ func extractDomainFromActorURI(actorURI string) string {
 const (
 	sqlInsertDeliveryQueue     = `INSERT INTO delivery_queue(id, inbox_uri, activity_json, attempts, next_retry_at, created_at) VALUES (?, ?, ?, ?, ?, ?)`
 	sqlSelectPendingDeliveries = `SELECT id, inbox_uri, activity_json, attempts, next_retry_at, created_at FROM delivery_queue WHERE next_retry_at <= ? ORDER BY created_at ASC LIMIT ?`
+	sqlSelectAllDeliveries     = `SELECT id, inbox_uri, activity_json, attempts, next_retry_at, created_at FROM delivery_queue ORDER BY created_at ASC LIMIT ?`
+	sqlDeleteAllDeliveries     = `DELETE FROM delivery_queue`
+	sqlCountDeliveries         = `SELECT COUNT(*) FROM delivery_queue`
 	sqlUpdateDeliveryAttempt   = `UPDATE delivery_queue SET attempts = ?, next_retry_at = ? WHERE id = ?`
 	sqlDeleteDelivery          = `DELETE FROM delivery_queue WHERE id = ?`
 )
@@ -1801,7 +2358,28 @@ func (db *DB) EnqueueDelivery(item *domain.DeliveryQueueItem) error {
 }
 
 func (db *DB) ReadPendingDeliveries(limit int) (error, *[]domain.DeliveryQueueItem) {
-	rows, err := db.db.Query(sqlSelectPendingDeliveries, time.Now(), limit)
+	return db.readDeliveries(sqlSelectPendingDeliveries, time.Now(), limit)
+}
+
+// ReadAllDeliveries returns queued deliveries, oldest first, including those waiting for a retry
+func (db *DB) ReadAllDeliveries(limit int) (error, *[]domain.DeliveryQueueItem) {
+	return db.readDeliveries(sqlSelectAllDeliveries, limit)
+}
+
+// ReadDeliveryQueueStats returns the number of queued deliveries and when the oldest was queued
+func (db *DB) ReadDeliveryQueueStats() (depth int, oldest time.Time, err error) {
+	if err = db.db.QueryRow(sqlCountDeliveries).Scan(&depth); err != nil || depth == 0 {
+		return depth, oldest, err
+	}
+	err, items := db.ReadAllDeliveries(1)
+	if err == nil && len(*items) > 0 {
+		oldest = (*items)[0].CreatedAt
+	}
+	return depth, oldest, err
+}
+
+func (db *DB) readDeliveries(query string, args ...any) (error, *[]domain.DeliveryQueueItem) {
+	rows, err := db.db.Query(query, args...)
 	if err != nil {
 		return err, nil
 	}
@@ -1837,6 +2415,135 @@ func (db *DB) DeleteDelivery(id uuid.UUID) error {
 	})
 }
 
+// PurgeDeliveryQueue drops all queued deliveries and returns how many were removed
+func (db *DB) PurgeDeliveryQueue() (int64, error) {
+	var purged int64
+	err := db.wrapTransaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec(sqlDeleteAllDeliveries)
+		if err != nil {
+			return err
+		}
+		purged, _ = result.RowsAffected()
+		return nil
+	})
+	return purged, err
+}
+
+// Inbox queue queries
+const (
+	sqlInsertInboxQueue        = `INSERT INTO inbox_queue(id, username, activity_json, activity_type, actor_uri, signer_uri, attempts, next_retry_at, status, last_error, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	sqlSelectInboxQueueColumns = `SELECT id, username, activity_json, activity_type, actor_uri, signer_uri, attempts, next_retry_at, status, last_error, created_at FROM inbox_queue`
+	// Ready items, skipping any item whose actor has an older pending item still waiting for a retry,
+	// so activities of one actor are never applied out of order
+	sqlSelectPendingInboxItems = sqlSelectInboxQueueColumns + ` q WHERE q.status = 'pending' AND q.next_retry_at <= ?
+		AND NOT EXISTS (SELECT 1 FROM inbox_queue p WHERE p.actor_uri = q.actor_uri AND p.status = 'pending'
+			AND p.next_retry_at > ? AND p.created_at < q.created_at)
+		ORDER BY q.created_at ASC LIMIT ?`
+	sqlSelectHeldInboxItems  = sqlSelectInboxQueueColumns + ` WHERE status = 'held' ORDER BY created_at DESC LIMIT ?`
+	sqlUpdateInboxAttempt    = `UPDATE inbox_queue SET attempts = ?, next_retry_at = ?, last_error = ? WHERE id = ?`
+	sqlHoldInboxItem         = `UPDATE inbox_queue SET status = 'held', attempts = ?, last_error = ? WHERE id = ?`
+	sqlRetryInboxItem        = `UPDATE inbox_queue SET status = 'pending', attempts = 0, next_retry_at = ? WHERE id = ? AND status = 'held'`
+	sqlDeleteInboxItem       = `DELETE FROM inbox_queue WHERE id = ?`
+	sqlCountInboxQueueStatus = `SELECT COALESCE(SUM(CASE WHEN status = 'pending' THEN 1 ELSE 0 END), 0), COALESCE(SUM(CASE WHEN status = 'held' THEN 1 ELSE 0 END), 0) FROM inbox_queue`
+)
+
+// EnqueueInboxItem stores a verified incoming activity for the inbox workers
+func (db *DB) EnqueueInboxItem(item *domain.InboxQueueItem) error {
+	status := item.Status
+	if status == "" {
+		status = domain.InboxQueuePending
+	}
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlInsertInboxQueue,
+			item.Id.String(),
+			item.Username,
+			item.ActivityJSON,
+			item.ActivityType,
+			item.ActorURI,
+			item.SignerURI,
+			item.Attempts,
+			item.NextRetryAt,
+			status,
+			item.LastError,
+			item.CreatedAt,
+		)
+		return err
+	})
+}
+
+// ReadPendingInboxItems returns up to limit items that are ready to be processed, oldest first
+func (db *DB) ReadPendingInboxItems(limit int) (error, *[]domain.InboxQueueItem) {
+	now := time.Now()
+	return db.readInboxItems(sqlSelectPendingInboxItems, now, now, limit)
+}
+
+// ReadHeldInboxItems returns up to limit items that were held after failing too often, newest first
+func (db *DB) ReadHeldInboxItems(limit int) (error, *[]domain.InboxQueueItem) {
+	return db.readInboxItems(sqlSelectHeldInboxItems, limit)
+}
+
+func (db *DB) readInboxItems(query string, args ...any) (error, *[]domain.InboxQueueItem) {
+	rows, err := db.db.Query(query, args...)
+	if err != nil {
+		return err, nil
+	}
+	defer rows.Close()
+
+	var items []domain.InboxQueueItem
+	for rows.Next() {
+		var item domain.InboxQueueItem
+		var idStr string
+		if err := rows.Scan(&idStr, &item.Username, &item.ActivityJSON, &item.ActivityType, &item.ActorURI, &item.SignerURI,
+			&item.Attempts, &item.NextRetryAt, &item.Status, &item.LastError, &item.CreatedAt); err != nil {
+			return err, &items
+		}
+		item.Id, _ = uuid.Parse(idStr)
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return err, &items
+	}
+	return nil, &items
+}
+
+// UpdateInboxItemAttempt records a failed attempt and schedules the next retry
+func (db *DB) UpdateInboxItemAttempt(id uuid.UUID, attempts int, nextRetry time.Time, lastError string) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlUpdateInboxAttempt, attempts, nextRetry, lastError, id.String())
+		return err
+	})
+}
+
+// HoldInboxItem moves an item that keeps failing to the holding area
+func (db *DB) HoldInboxItem(id uuid.UUID, attempts int, lastError string) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlHoldInboxItem, attempts, lastError, id.String())
+		return err
+	})
+}
+
+// RetryInboxItem puts a held item back into the queue for immediate processing
+func (db *DB) RetryInboxItem(id uuid.UUID) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlRetryInboxItem, time.Now(), id.String())
+		return err
+	})
+}
+
+// DeleteInboxItem removes an item from the inbox queue
+func (db *DB) DeleteInboxItem(id uuid.UUID) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlDeleteInboxItem, id.String())
+		return err
+	})
+}
+
+// CountInboxQueue returns the number of pending and held inbox queue items
+func (db *DB) CountInboxQueue() (pending int, held int, err error) {
+	err = db.db.QueryRow(sqlCountInboxQueueStatus).Scan(&pending, &held)
+	return pending, held, err
+}
+
 // Follower queries
 const (
 	sqlSelectFollowersByAccountId = `SELECT id, account_id, target_account_id, uri, accepted, created_at, is_local FROM follows WHERE target_account_id = ? AND accepted = 1`
@@ -1987,10 +2694,20 @@ func (db *DB) CountLocalPosts() (int, error) {
 	return count, nil
 }
 
+// CountTopLevelNotesByUserId returns how many of a user's notes are not replies
+func (db *DB) CountTopLevelNotesByUserId(userId uuid.UUID) (int, error) {
+	var count int
+	err := db.db.QueryRow(sqlCountTopLevelNotesByUser, userId.String()).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // CountActiveUsersMonth returns the number of users who posted in the last 30 days
 func (db *DB) CountActiveUsersMonth() (int, error) {
 	var count int
-	err := db.db.QueryRow(sqlCountActiveUsersMonth).Scan(&count)
+	err := db.db.QueryRow(sqlCountActiveUsersMonth, db.daysAgo(30)).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -2000,7 +2717,7 @@ func (db *DB) CountActiveUsersMonth() (int, error) {
 // CountActiveUsersHalfYear returns the number of users who posted in the last 180 days
 func (db *DB) CountActiveUsersHalfYear() (int, error) {
 	var count int
-	err := db.db.QueryRow(sqlCountActiveUsersHalfYear).Scan(&count)
+	err := db.db.QueryRow(sqlCountActiveUsersHalfYear, db.daysAgo(180)).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -2029,6 +2746,35 @@ func (db *DB) DeleteAccount(accountId uuid.UUID) error {
 			return fmt.Errorf("failed to delete likes: %w", err)
 		}
 
+		// Delete all reactions by this user
+		_, err = tx.Exec("DELETE FROM reactions WHERE account_id = ?", accountId.String())
+		if err != nil {
+			return fmt.Errorf("failed to delete reactions: %w", err)
+		}
+
+		// Delete all followed hashtags of this user
+		_, err = tx.Exec("DELETE FROM followed_hashtags WHERE account_id = ?", accountId.String())
+		if err != nil {
+			return fmt.Errorf("failed to delete followed hashtags: %w", err)
+		}
+
+		// Delete this user's lists and their memberships, and remove the user from other lists
+		_, err = tx.Exec("DELETE FROM list_members WHERE list_id IN (SELECT id FROM lists WHERE account_id = ?) OR member_id = ?",
+			accountId.String(), accountId.String())
+		if err != nil {
+			return fmt.Errorf("failed to delete list members: %w", err)
+		}
+		_, err = tx.Exec("DELETE FROM lists WHERE account_id = ?", accountId.String())
+		if err != nil {
+			return fmt.Errorf("failed to delete lists: %w", err)
+		}
+
+		// Delete all keyword filters of this user
+		_, err = tx.Exec("DELETE FROM filters WHERE account_id = ?", accountId.String())
+		if err != nil {
+			return fmt.Errorf("failed to delete filters: %w", err)
+		}
+
 		// Delete all delivery queue items for this user (if table exists)
 		_, err = tx.Exec("DELETE FROM delivery_queue WHERE account_id = ?", accountId.String())
 		if err != nil {
@@ -2036,6 +2782,22 @@ func (db *DB) DeleteAccount(accountId uuid.UUID) error {
 			log.Printf("Warning: failed to delete delivery queue items (table may not exist): %v", err)
 		}
 
+		// Revoke the user's client app tokens and pending authorizations
+		_, err = tx.Exec("DELETE FROM oauth_tokens WHERE account_id = ?", accountId.String())
+		if err != nil {
+			return fmt.Errorf("failed to delete oauth tokens: %w", err)
+		}
+		_, err = tx.Exec("DELETE FROM oauth_authorizations WHERE account_id = ?", accountId.String())
+		if err != nil {
+			return fmt.Errorf("failed to delete oauth authorizations: %w", err)
+		}
+
+		// Drop queued incoming activities addressed to this user
+		_, err = tx.Exec("DELETE FROM inbox_queue WHERE username = (SELECT username FROM accounts WHERE id = ?)", accountId.String())
+		if err != nil {
+			log.Printf("Warning: failed to delete inbox queue items (table may not exist): %v", err)
+		}
+
 		// Note: We don't delete activities because they're linked by actor_uri (string) not account_id
 		// Activities will remain as a historical record even after account deletion
 		// This matches ActivityPub behavior where activities persist after account deletion
@@ -2139,10 +2901,12 @@ func (db *DB) DeleteLocalFollow(followerAccountId, targetAccountId uuid.UUID) er
 	})
 }
 
-// ReadPublicNotesByUsername returns public notes for a user's ActivityPub outbox with pagination
+// ReadPublicNotesByUsername returns a page of public notes for a user's ActivityPub outbox, starting after before
 // Returns notes with full metadata including object_uri for ActivityPub compatibility
-func (db *DB) ReadPublicNotesByUsername(username string, limit, offset int) (error, *[]domain.Note) {
-	rows, err := db.db.Query(sqlSelectPublicNotesByUsername, username, limit, offset)
+func (db *DB) ReadPublicNotesByUsername(username string, before domain.Cursor, limit int) (error, *[]domain.Note) {
+	pageFilter, pageArgs := db.cursorFilter("notes.created_at", "notes.id", before)
+	args := append(append([]any{username}, pageArgs...), limit)
+	rows, err := db.db.Query(sqlSelectPublicNotesByUsername+pageFilter+db.cursorOrder("notes.created_at", "notes.id")+" LIMIT ?", args...)
 	if err != nil {
 		return err, nil
 	}
@@ -2154,7 +2918,7 @@ func (db *DB) ReadPublicNotesByUsername(username string, limit, offset int) (err
 		var userId, visibility, objectURI sql.NullString
 		var editedAt sql.NullTime
 
-		err := rows.Scan(&note.Id, &userId, &note.Message, &note.CreatedAt, &editedAt, &visibility, &objectURI)
+		err := rows.Scan(&note.Id, &userId, &note.Message, &note.Title, &note.CreatedAt, &editedAt, &visibility, &objectURI)
 		if err != nil {
 			return err, &notes
 		}
@@ -2177,6 +2941,16 @@ func (db *DB) ReadPublicNotesByUsername(username string, limit, offset int) (err
 	return nil, &notes
 }
 
+// CountPublicNotesByUsername returns how many public notes a user's outbox holds
+func (db *DB) CountPublicNotesByUsername(username string) (int, error) {
+	var count int
+	err := db.db.QueryRow(sqlCountPublicNotesByUsername, username).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // IsFollowingLocal checks if a user is following another local user
 func (db *DB) IsFollowingLocal(followerAccountId, targetAccountId uuid.UUID) (bool, error) {
 	var count int
@@ -2271,13 +3045,19 @@ func (db *DB) DeleteFollowsByRemoteAccountId(remoteAccountId uuid.UUID) error {
 	return nil
 }
 
-// MigrateKeysToPKCS8 converts all existing PKCS#1 keys to PKCS#8 format
+// Queries of the PKCS#8 key migration
+const (
+	sqlSelectAccountKeys = `SELECT id, username, web_private_key, web_public_key FROM accounts WHERE web_private_key IS NOT NULL`
+	sqlUpdateAccountKeys = `UPDATE accounts SET web_private_key = ?, web_public_key = ? WHERE id = ?`
+)
+
+// migrateKeysToPKCS8 converts all existing PKCS#1 keys to PKCS#8 format
 // This is a one-time migration that preserves the cryptographic key material
-func (db *DB) MigrateKeysToPKCS8() error {
+func (db *DB) migrateKeysToPKCS8(tx *sql.Tx) error {
 	log.Println("Starting PKCS#1 to PKCS#8 key migration...")
 
 	// Get all accounts
-	rows, err := db.db.Query("SELECT id, username, web_private_key, web_public_key FROM accounts WHERE web_private_key IS NOT NULL")
+	rows, err := tx.Query(sqlSelectAccountKeys)
 	if err != nil {
 		return fmt.Errorf("failed to query accounts: %w", err)
 	}
@@ -2319,8 +3099,7 @@ func (db *DB) MigrateKeysToPKCS8() error {
 		}
 
 		// Update database
-		_, err = db.db.Exec("UPDATE accounts SET web_private_key = ?, web_public_key = ? WHERE id = ?",
-			newPrivateKey, newPublicKey, idStr)
+		_, err = tx.Exec(sqlUpdateAccountKeys, newPrivateKey, newPublicKey, idStr)
 		if err != nil {
 			log.Printf("Failed to update keys for user %s: %v", username, err)
 			errorCount++
@@ -2344,40 +3123,67 @@ func (db *DB) MigrateKeysToPKCS8() error {
 	return nil
 }
 
-// MigrateDuplicateFollows removes duplicate follow relationships and adds UNIQUE constraint
+// Statements of the unique follows migration
+const (
+	sqlSelectDuplicateFollows = `
+		SELECT account_id, target_account_id, COUNT(*) as count
+		FROM follows
+		GROUP BY account_id, target_account_id
+		HAVING count > 1
+	`
+	// Keep the oldest follow (MIN(created_at)), delete the rest
+	sqlDeleteDuplicateFollows = `
+		DELETE FROM follows
+		WHERE account_id = ? AND target_account_id = ?
+		AND id NOT IN (
+			SELECT id FROM follows
+			WHERE account_id = ? AND target_account_id = ?
+			ORDER BY created_at ASC
+			LIMIT 1
+		)
+	`
+	sqlCreateUniqueFollowsTable = `
+		CREATE TABLE follows_new (
+			id TEXT NOT NULL PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			target_account_id TEXT NOT NULL,
+			uri TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			accepted INTEGER DEFAULT 0,
+			is_local INTEGER DEFAULT 0,
+			UNIQUE(account_id, target_account_id)
+		)
+	`
+	sqlCopyFollowsTable = `
+		INSERT INTO follows_new (id, account_id, target_account_id, uri, created_at, accepted, is_local)
+		SELECT id, account_id, target_account_id, uri, created_at, accepted,
+			   COALESCE(is_local, 0) as is_local
+		FROM follows
+	`
+	sqlReplaceFollowsTable = `DROP TABLE follows; ALTER TABLE follows_new RENAME TO follows`
+)
+
+// migrateDuplicateFollows removes duplicate follow relationships and adds UNIQUE constraint
 // This is a one-time migration to fix the issue where multiple Follow activities
 // from the same actor could create duplicate entries
-func (db *DB) MigrateDuplicateFollows() error {
+func (db *DB) migrateDuplicateFollows(tx *sql.Tx) error {
 	log.Println("Starting duplicate follows cleanup migration...")
 
 	// First, check if we already have the UNIQUE constraint
 	// If the constraint exists, we can skip this migration
-	rows, err := db.db.Query(`SELECT sql FROM sqlite_master WHERE type='table' AND name='follows'`)
+	var schema string
+	err := tx.QueryRow(`SELECT sql FROM sqlite_master WHERE type='table' AND name='follows'`).Scan(&schema)
 	if err != nil {
 		return fmt.Errorf("failed to check table schema: %w", err)
 	}
-	defer rows.Close()
-
-	if rows.Next() {
-		var sql string
-		if err := rows.Scan(&sql); err != nil {
-			return fmt.Errorf("failed to read table schema: %w", err)
-		}
-		// Check if UNIQUE constraint already exists
-		if strings.Contains(sql, "UNIQUE") && strings.Contains(sql, "account_id") && strings.Contains(sql, "target_account_id") {
-			log.Println("UNIQUE constraint already exists on follows table, skipping migration")
-			return nil
-		}
+	// Check if UNIQUE constraint already exists
+	if strings.Contains(schema, "UNIQUE") && strings.Contains(schema, "account_id") && strings.Contains(schema, "target_account_id") {
+		log.Println("UNIQUE constraint already exists on follows table, skipping migration")
+		return nil
 	}
 
 	// Find and count duplicate follows
-	duplicateQuery := `
-		SELECT account_id, target_account_id, COUNT(*) as count
-		FROM follows
-		GROUP BY account_id, target_account_id
-		HAVING count > 1
-	`
-	dupRows, err := db.db.Query(duplicateQuery)
+	dupRows, err := tx.Query(sqlSelectDuplicateFollows)
 	if err != nil {
 		return fmt.Errorf("failed to query duplicates: %w", err)
 	}
@@ -2407,18 +3213,7 @@ func (db *DB) MigrateDuplicateFollows() error {
 		// Remove duplicates, keeping only the oldest one (first created)
 		removedCount := 0
 		for _, dp := range duplicates {
-			// Keep the oldest follow (MIN(created_at)), delete the rest
-			deleteQuery := `
-				DELETE FROM follows
-				WHERE account_id = ? AND target_account_id = ?
-				AND id NOT IN (
-					SELECT id FROM follows
-					WHERE account_id = ? AND target_account_id = ?
-					ORDER BY created_at ASC
-					LIMIT 1
-				)
-			`
-			result, err := db.db.Exec(deleteQuery, dp.accountId, dp.targetAccountId, dp.accountId, dp.targetAccountId)
+			result, err := tx.Exec(sqlDeleteDuplicateFollows, dp.accountId, dp.targetAccountId, dp.accountId, dp.targetAccountId)
 			if err != nil {
 				log.Printf("Failed to delete duplicates for %s -> %s: %v", dp.accountId, dp.targetAccountId, err)
 				continue
@@ -2437,77 +3232,34 @@ func (db *DB) MigrateDuplicateFollows() error {
 
 	// SQLite doesn't support ALTER TABLE to add constraints, so we need to recreate the table
 	// https://www.sqlite.org/lang_altertable.html
-	err = db.wrapTransaction(func(tx *sql.Tx) error {
-		// Create new table with UNIQUE constraint
-		_, err := tx.Exec(`
-			CREATE TABLE follows_new (
-				id TEXT NOT NULL PRIMARY KEY,
-				account_id TEXT NOT NULL,
-				target_account_id TEXT NOT NULL,
-				uri TEXT NOT NULL,
-				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-				accepted INTEGER DEFAULT 0,
-				is_local INTEGER DEFAULT 0,
-				UNIQUE(account_id, target_account_id)
-			)
-		`)
-		if err != nil {
-			return fmt.Errorf("failed to create new follows table: %w", err)
-		}
-
-		// Copy data from old table to new table
-		_, err = tx.Exec(`
-			INSERT INTO follows_new (id, account_id, target_account_id, uri, created_at, accepted, is_local)
-			SELECT id, account_id, target_account_id, uri, created_at, accepted,
-				   COALESCE(is_local, 0) as is_local
-			FROM follows
-		`)
-		if err != nil {
-			return fmt.Errorf("failed to copy data to new table: %w", err)
-		}
-
-		// Drop old table
-		_, err = tx.Exec(`DROP TABLE follows`)
-		if err != nil {
-			return fmt.Errorf("failed to drop old table: %w", err)
-		}
-
-		// Rename new table to old name
-		_, err = tx.Exec(`ALTER TABLE follows_new RENAME TO follows`)
-		if err != nil {
-			return fmt.Errorf("failed to rename new table: %w", err)
-		}
+	// Create new table with UNIQUE constraint
+	if _, err := tx.Exec(sqlCreateUniqueFollowsTable); err != nil {
+		return fmt.Errorf("failed to create new follows table: %w", err)
+	}
 
-		// Recreate indices
-		_, err = tx.Exec(`
-			CREATE INDEX IF NOT EXISTS idx_follows_account_id ON follows(account_id);
-			CREATE INDEX IF NOT EXISTS idx_follows_target_account_id ON follows(target_account_id);
-			CREATE INDEX IF NOT EXISTS idx_follows_uri ON follows(uri);
-		`)
-		if err != nil {
-			return fmt.Errorf("failed to recreate indices: %w", err)
-		}
+	// Copy data from old table to new table
+	if _, err := tx.Exec(sqlCopyFollowsTable); err != nil {
+		return fmt.Errorf("failed to copy data to new table: %w", err)
+	}
 
-		return nil
-	})
+	// Drop old table and rename new table to old name
+	if _, err := tx.Exec(sqlReplaceFollowsTable); err != nil {
+		return fmt.Errorf("failed to replace follows table: %w", err)
+	}
 
-	if err != nil {
-		return fmt.Errorf("failed to add UNIQUE constraint: %w", err)
+	// Recreate indices
+	if _, err := tx.Exec(sqlCreateFollowsIndices); err != nil {
+		return fmt.Errorf("failed to recreate indices: %w", err)
 	}
 
 	log.Println("Successfully added UNIQUE constraint to follows table")
 	return nil
 }
 
-// MigrateLocalReplyCounts recalculates reply_count for notes with local replies
-// This fixes the issue where local-only replies (with "local:" prefix URIs) weren't
-// being counted, causing threads not to open in the TUI
-func (db *DB) MigrateLocalReplyCounts() error {
-	log.Println("Starting local reply counts migration...")
-
-	// Find all notes that have replies with "local:" prefix in_reply_to_uri
-	// but have reply_count = 0
-	query := `
+// Queries of the local reply counts migration
+const (
+	// Notes that have replies with "local:" prefix in_reply_to_uri but have reply_count = 0
+	sqlSelectNotesWithUncountedLocalReplies = `
 		SELECT DISTINCT n.id
 		FROM notes n
 		WHERE n.reply_count = 0
@@ -2516,7 +3268,28 @@ func (db *DB) MigrateLocalReplyCounts() error {
 			WHERE r.in_reply_to_uri LIKE 'local:' || n.id || '%'
 		)
 	`
-	rows, err := db.db.Query(query)
+	// Count direct and indirect replies
+	sqlCountLocalReplyChain = `
+		WITH RECURSIVE reply_chain(id) AS (
+			-- Direct replies to this note
+			SELECT id FROM notes WHERE in_reply_to_uri LIKE 'local:' || ? || '%'
+			UNION ALL
+			-- Replies to replies (recursive)
+			SELECT n.id FROM notes n
+			INNER JOIN reply_chain rc ON n.in_reply_to_uri LIKE 'local:' || rc.id || '%'
+		)
+		SELECT COUNT(*) FROM reply_chain
+	`
+	sqlUpdateNoteReplyCount = `UPDATE notes SET reply_count = ? WHERE id = ?`
+)
+
+// migrateLocalReplyCounts recalculates reply_count for notes with local replies
+// This fixes the issue where local-only replies (with "local:" prefix URIs) weren't
+// being counted, causing threads not to open in the TUI
+func (db *DB) migrateLocalReplyCounts(tx *sql.Tx) error {
+	log.Println("Starting local reply counts migration...")
+
+	rows, err := tx.Query(sqlSelectNotesWithUncountedLocalReplies)
 	if err != nil {
 		return fmt.Errorf("failed to query notes with local replies: %w", err)
 	}
@@ -2542,27 +3315,15 @@ func (db *DB) MigrateLocalReplyCounts() error {
 	// For each note, count all replies (including nested) and update reply_count
 	updatedCount := 0
 	for _, noteId := range noteIds {
-		// Count direct and indirect replies
-		countQuery := `
-			WITH RECURSIVE reply_chain(id) AS (
-				-- Direct replies to this note
-				SELECT id FROM notes WHERE in_reply_to_uri LIKE 'local:' || ? || '%'
-				UNION ALL
-				-- Replies to replies (recursive)
-				SELECT n.id FROM notes n
-				INNER JOIN reply_chain rc ON n.in_reply_to_uri LIKE 'local:' || rc.id || '%'
-			)
-			SELECT COUNT(*) FROM reply_chain
-		`
 		var replyCount int
-		err := db.db.QueryRow(countQuery, noteId).Scan(&replyCount)
+		err := tx.QueryRow(sqlCountLocalReplyChain, noteId).Scan(&replyCount)
 		if err != nil {
 			log.Printf("Failed to count replies for note %s: %v", noteId, err)
 			continue
 		}
 
 		if replyCount > 0 {
-			_, err = db.db.Exec(`UPDATE notes SET reply_count = ? WHERE id = ?`, replyCount, noteId)
+			_, err = tx.Exec(sqlUpdateNoteReplyCount, replyCount, noteId)
 			if err != nil {
 				log.Printf("Failed to update reply_count for note %s: %v", noteId, err)
 				continue
@@ -2576,53 +3337,53 @@ func (db *DB) MigrateLocalReplyCounts() error {
 	return nil
 }
 
-// MigrateOrphanActivities removes Create activities that were incorrectly stored
-// because the inbox handler stored them before checking if the user follows the actor.
-// This is a one-time cleanup migration for the bug fix in handleCreateActivityWithDeps.
-func (db *DB) MigrateOrphanActivities() error {
-	log.Println("Starting orphan activities cleanup migration...")
-
-	// Delete Create activities where:
-	// 1. activity_type = 'Create'
-	// 2. local = 0 (not local)
-	// 3. from_relay = 0 (not from a relay)
-	// 4. The actor is NOT followed by any local user
-	// 5. The activity is NOT a reply to a local note
-	//
-	// Note: Activities from relays (from_relay = 1) are valid even without follow relationship
-	// Note: Replies to local posts are valid even without follow relationship
-	deleteQuery := `
-		DELETE FROM activities
-		WHERE id IN (
-			SELECT a.id FROM activities a
-			WHERE a.activity_type = 'Create'
-			AND a.local = 0
-			AND a.from_relay = 0
-			-- Not followed by any local user
-			AND NOT EXISTS (
-				SELECT 1 FROM follows f
-				INNER JOIN remote_accounts ra ON ra.id = f.target_account_id
-				WHERE ra.actor_uri = a.actor_uri
-				AND f.accepted = 1
-			)
-			-- Not a reply to a local note
-			AND NOT EXISTS (
-				SELECT 1 FROM notes n
-				WHERE n.object_uri = a.in_reply_to
-				AND a.in_reply_to IS NOT NULL
-				AND a.in_reply_to != ''
-			)
-			-- Not a reply to another activity (local or remote)
-			AND NOT EXISTS (
-				SELECT 1 FROM activities parent
-				WHERE parent.object_uri = a.in_reply_to
-				AND a.in_reply_to IS NOT NULL
-				AND a.in_reply_to != ''
-			)
+// sqlDeleteOrphanActivities deletes Create activities where:
+// 1. activity_type = 'Create'
+// 2. local = 0 (not local)
+// 3. from_relay = 0 (not from a relay)
+// 4. The actor is NOT followed by any local user
+// 5. The activity is NOT a reply to a local note
+//
+// Note: Activities from relays (from_relay = 1) are valid even without follow relationship
+// Note: Replies to local posts are valid even without follow relationship
+const sqlDeleteOrphanActivities = `
+	DELETE FROM activities
+	WHERE id IN (
+		SELECT a.id FROM activities a
+		WHERE a.activity_type = 'Create'
+		AND a.local = 0
+		AND a.from_relay = 0
+		-- Not followed by any local user
+		AND NOT EXISTS (
+			SELECT 1 FROM follows f
+			INNER JOIN remote_accounts ra ON ra.id = f.target_account_id
+			WHERE ra.actor_uri = a.actor_uri
+			AND f.accepted = 1
 		)
-	`
+		-- Not a reply to a local note
+		AND NOT EXISTS (
+			SELECT 1 FROM notes n
+			WHERE n.object_uri = a.in_reply_to
+			AND a.in_reply_to IS NOT NULL
+			AND a.in_reply_to != ''
+		)
+		-- Not a reply to another activity (local or remote)
+		AND NOT EXISTS (
+			SELECT 1 FROM activities parent
+			WHERE parent.object_uri = a.in_reply_to
+			AND a.in_reply_to IS NOT NULL
+			AND a.in_reply_to != ''
+		)
+	)
+`
 
-	result, err := db.db.Exec(deleteQuery)
+// migrateOrphanActivities removes Create activities that were incorrectly stored
+// because the inbox handler stored them before checking if the user follows the actor.
+// This is a one-time cleanup migration for the bug fix in handleCreateActivityWithDeps.
+func (db *DB) migrateOrphanActivities(tx *sql.Tx) error {
+	log.Println("Starting orphan activities cleanup migration...")
+
+	result, err := tx.Exec(sqlDeleteOrphanActivities)
 	if err != nil {
 		return fmt.Errorf("failed to delete orphan activities: %w", err)
 	}
@@ -2639,11 +3400,11 @@ func (db *DB) MigrateOrphanActivities() error {
 
 // Hashtag queries
 const (
-	sqlInsertHashtag          = `INSERT INTO hashtags(name, usage_count, last_used_at) VALUES (?, 1, CURRENT_TIMESTAMP) ON CONFLICT(name) DO UPDATE SET usage_count = usage_count + 1, last_used_at = CURRENT_TIMESTAMP RETURNING id`
+	sqlInsertHashtag          = `INSERT INTO hashtags(name, usage_count, last_used_at) VALUES (?, 1, CURRENT_TIMESTAMP) ON CONFLICT(name) DO UPDATE SET usage_count = hashtags.usage_count + 1, last_used_at = CURRENT_TIMESTAMP RETURNING id`
 	sqlSelectHashtagByName    = `SELECT id, name, usage_count, last_used_at FROM hashtags WHERE name = ?`
-	sqlInsertNoteHashtag      = `INSERT OR IGNORE INTO note_hashtags(note_id, hashtag_id) VALUES (?, ?)`
+	sqlInsertNoteHashtag      = `INSERT INTO note_hashtags(note_id, hashtag_id) VALUES (?, ?) ON CONFLICT DO NOTHING`
 	sqlSelectHashtagsByNoteId = `SELECT h.name FROM hashtags h INNER JOIN note_hashtags nh ON h.id = nh.hashtag_id WHERE nh.note_id = ?`
-	sqlSelectNotesByHashtag   = `SELECT n.id, a.username, n.message, n.created_at, n.edited_at, COALESCE(n.like_count, 0), COALESCE(n.boost_count, 0)
+	sqlSelectNotesByHashtag   = `SELECT n.id, a.username, n.message, COALESCE(n.title, ''), n.created_at, n.edited_at, COALESCE(n.like_count, 0), COALESCE(n.boost_count, 0)
 								FROM notes n
 								INNER JOIN accounts a ON a.id = n.user_id
 								INNER JOIN note_hashtags nh ON nh.note_id = n.id
@@ -2654,98 +3415,1007 @@ const (
 	sqlCountNotesByHashtag = `SELECT COUNT(*) FROM note_hashtags nh INNER JOIN hashtags h ON h.id = nh.hashtag_id WHERE h.name = ?`
 )
 
-// CreateOrUpdateHashtag creates a new hashtag or increments usage count if it exists
-// Returns the hashtag ID
-func (db *DB) CreateOrUpdateHashtag(name string) (int64, error) {
-	var hashtagId int64
-	err := db.wrapTransaction(func(tx *sql.Tx) error {
-		err := tx.QueryRow(sqlInsertHashtag, strings.ToLower(name)).Scan(&hashtagId)
+// CreateOrUpdateHashtag creates a new hashtag or increments usage count if it exists
+// Returns the hashtag ID
+func (db *DB) CreateOrUpdateHashtag(name string) (int64, error) {
+	var hashtagId int64
+	err := db.wrapTransaction(func(tx *sql.Tx) error {
+		err := tx.QueryRow(sqlInsertHashtag, strings.ToLower(name)).Scan(&hashtagId)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	return hashtagId, err
+}
+
+// LinkNoteHashtags creates links between a note and multiple hashtags
+func (db *DB) LinkNoteHashtags(noteId uuid.UUID, hashtagIds []int64) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		for _, hashtagId := range hashtagIds {
+			_, err := tx.Exec(sqlInsertNoteHashtag, noteId.String(), hashtagId)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ReadHashtagsByNoteId returns all hashtag names for a given note
+func (db *DB) ReadHashtagsByNoteId(noteId uuid.UUID) (error, []string) {
+	rows, err := db.db.Query(sqlSelectHashtagsByNoteId, noteId.String())
+	if err != nil {
+		return err, nil
+	}
+	defer rows.Close()
+
+	var hashtags []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err, hashtags
+		}
+		hashtags = append(hashtags, name)
+	}
+	if err = rows.Err(); err != nil {
+		return err, hashtags
+	}
+	return nil, hashtags
+}
+
+// ReadNotesByHashtag returns notes that contain a specific hashtag with pagination
+func (db *DB) ReadNotesByHashtag(tag string, limit, offset int) (error, *[]domain.Note) {
+	rows, err := db.db.Query(sqlSelectNotesByHashtag, strings.ToLower(tag), limit, offset)
+	if err != nil {
+		return err, nil
+	}
+	defer rows.Close()
+
+	var notes []domain.Note
+	for rows.Next() {
+		var note domain.Note
+		var createdAtStr string
+		var editedAtStr sql.NullString
+		if err := rows.Scan(&note.Id, &note.CreatedBy, &note.Message, &note.Title, &createdAtStr, &editedAtStr, &note.LikeCount, &note.BoostCount); err != nil {
+			return err, &notes
+		}
+
+		if parsedTime, err := parseTimestamp(createdAtStr); err == nil {
+			note.CreatedAt = parsedTime
+		}
+
+		if editedAtStr.Valid {
+			if parsedTime, err := parseTimestamp(editedAtStr.String); err == nil {
+				note.EditedAt = &parsedTime
+			}
+		}
+
+		notes = append(notes, note)
+	}
+	if err = rows.Err(); err != nil {
+		return err, &notes
+	}
+	return nil, &notes
+}
+
+// CountNotesByHashtag returns the total count of notes with a specific hashtag
+func (db *DB) CountNotesByHashtag(tag string) (int, error) {
+	var count int
+	err := db.db.QueryRow(sqlCountNotesByHashtag, strings.ToLower(tag)).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Followed hashtag queries
+const (
+	sqlInsertFollowedHashtag      = `INSERT INTO followed_hashtags(id, account_id, hashtag, created_at) VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING`
+	sqlDeleteFollowedHashtag      = `DELETE FROM followed_hashtags WHERE account_id = ? AND hashtag = ?`
+	sqlSelectFollowedHashtags     = `SELECT hashtag FROM followed_hashtags WHERE account_id = ? ORDER BY hashtag ASC`
+	sqlCheckFollowedHashtag       = `SELECT COUNT(*) FROM followed_hashtags WHERE account_id = ? AND hashtag = ?`
+	sqlCountHashtagFollowers      = `SELECT COUNT(*) FROM followed_hashtags WHERE hashtag = ?`
+	sqlSelectHashtagLocalNotesFmt = `SELECT notes.id, accounts.username, notes.message, notes.created_at, notes.object_uri, COALESCE(notes.reply_count, 0), COALESCE(notes.like_count, 0), COALESCE(notes.boost_count, 0), h.name FROM notes
+		INNER JOIN accounts ON accounts.id = notes.user_id
+		INNER JOIN note_hashtags nh ON nh.note_id = notes.id
+		INNER JOIN hashtags h ON h.id = nh.hashtag_id
+		WHERE h.name IN (%s)
+		AND (notes.in_reply_to_uri IS NULL OR notes.in_reply_to_uri = '')%s LIMIT ?`
+
+	// Remote hashtags are not indexed, so candidates are pre-filtered with LIKE on the raw JSON
+	// and confirmed against the parsed Hashtag tags in Go
+	sqlSelectHashtagRemoteActivitiesFmt = `SELECT a.id, a.actor_uri, a.object_uri, COALESCE(a.object_url, ''), a.raw_json, a.created_at, COALESCE(ra.username, ''), COALESCE(ra.domain, ''), COALESCE(a.reply_count, 0), COALESCE(a.like_count, 0), COALESCE(a.boost_count, 0)
+		FROM activities a
+		LEFT JOIN remote_accounts ra ON ra.actor_uri = a.actor_uri
+		WHERE a.activity_type = 'Create' AND a.local = 0
+		AND a.raw_json NOT LIKE '%%"inReplyTo":"http%%'
+		AND (%s)%s LIMIT ?`
+)
+
+// FollowHashtag adds a hashtag to an account's followed hashtags (no-op if already followed)
+func (db *DB) FollowHashtag(accountId uuid.UUID, tag string) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlInsertFollowedHashtag, uuid.New().String(), accountId.String(), strings.ToLower(tag), time.Now().Format(time.RFC3339))
+		return err
+	})
+}
+
+// UnfollowHashtag removes a hashtag from an account's followed hashtags
+func (db *DB) UnfollowHashtag(accountId uuid.UUID, tag string) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlDeleteFollowedHashtag, accountId.String(), strings.ToLower(tag))
+		return err
+	})
+}
+
+// ReadFollowedHashtags returns the hashtag names followed by an account, sorted alphabetically
+func (db *DB) ReadFollowedHashtags(accountId uuid.UUID) (error, []string) {
+	rows, err := db.db.Query(sqlSelectFollowedHashtags, accountId.String())
+	if err != nil {
+		return err, nil
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return err, tags
+		}
+		tags = append(tags, tag)
+	}
+	if err = rows.Err(); err != nil {
+		return err, tags
+	}
+	return nil, tags
+}
+
+// IsFollowingHashtag checks if an account follows a hashtag
+func (db *DB) IsFollowingHashtag(accountId uuid.UUID, tag string) (bool, error) {
+	var count int
+	err := db.db.QueryRow(sqlCheckFollowedHashtag, accountId.String(), strings.ToLower(tag)).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CountHashtagFollowers returns how many local accounts follow a hashtag
+func (db *DB) CountHashtagFollowers(tag string) (int, error) {
+	var count int
+	err := db.db.QueryRow(sqlCountHashtagFollowers, strings.ToLower(tag)).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ReadHashtagTimelinePosts returns a page of local and remote top-level posts carrying a hashtag,
+// newest first, starting after before
+func (db *DB) ReadHashtagTimelinePosts(tag string, before domain.Cursor, limit int) (error, *[]domain.HomePost) {
+	err, posts := db.readHashtagPosts([]string{strings.ToLower(tag)}, before, limit, db.readLocalEmojiMap())
+	if err != nil {
+		return err, &posts
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		return posts[i].Cursor().After(posts[j].Cursor())
+	})
+	if len(posts) > limit {
+		posts = posts[:limit]
+	}
+
+	for i := range posts {
+		posts[i].Reactions = db.readReactionCountsForPost(posts[i].IsLocal, posts[i].NoteID, posts[i].ObjectURI)
+	}
+
+	return nil, &posts
+}
+
+// readHashtagPosts returns local and remote (including relay-forwarded) top-level posts carrying
+// any of the given hashtags, older than before. Each post is labelled with the matching tag in ViaHashtag.
+// Results are unsorted and may contain duplicates when a post carries several of the tags.
+func (db *DB) readHashtagPosts(tags []string, before domain.Cursor, limit int, localEmojis map[string]string) (error, []domain.HomePost) {
+	var posts []domain.HomePost
+	if len(tags) == 0 {
+		return nil, posts
+	}
+
+	placeholders := make([]string, len(tags))
+	localArgs := make([]any, 0, len(tags)+1)
+	for i, tag := range tags {
+		placeholders[i] = "?"
+		localArgs = append(localArgs, tag)
+	}
+	localFilter, pageArgs := db.cursorFilter("notes.created_at", "notes.id", before)
+	localArgs = append(append(localArgs, pageArgs...), limit)
+
+	localRows, err := db.db.Query(fmt.Sprintf(sqlSelectHashtagLocalNotesFmt, strings.Join(placeholders, ", "),
+		localFilter+db.cursorOrder("notes.created_at", "notes.id")), localArgs...)
+	if err != nil {
+		return err, posts
+	}
+	defer localRows.Close()
+
+	for localRows.Next() {
+		var idStr string
+		var username string
+		var message string
+		var createdAtStr string
+		var objectURI sql.NullString
+		var replyCount int
+		var likeCount int
+		var boostCount int
+		var tag string
+
+		if err := localRows.Scan(&idStr, &username, &message, &createdAtStr, &objectURI, &replyCount, &likeCount, &boostCount, &tag); err != nil {
+			return err, posts
+		}
+
+		noteId, _ := uuid.Parse(idStr)
+		parsedTime, _ := parseTimestamp(createdAtStr)
+
+		posts = append(posts, domain.HomePost{
+			ID:         noteId,
+			Author:     username,
+			Content:    message,
+			Time:       parsedTime,
+			ObjectURI:  objectURI.String,
+			IsLocal:    true,
+			NoteID:     noteId,
+			ReplyCount: replyCount,
+			LikeCount:  likeCount,
+			BoostCount: boostCount,
+			ViaHashtag: tag,
+			Emojis:     emojisForLocalMessage(message, localEmojis),
+		})
+	}
+	if err = localRows.Err(); err != nil {
+		return err, posts
+	}
+
+	err, remotePosts := db.readHashtagRemotePosts(tags, before, limit)
+	return err, append(posts, remotePosts...)
+}
+
+// readHashtagRemotePosts returns up to limit remote top-level posts carrying any of the given
+// hashtags, older than before. The LIKE filter on raw_json also matches substrings (#go in #golang),
+// so batches are read until limit posts are confirmed against their Hashtag tags or none are left.
+func (db *DB) readHashtagRemotePosts(tags []string, before domain.Cursor, limit int) (error, []domain.HomePost) {
+	var posts []domain.HomePost
+	// Hashtags match regardless of case; LIKE does that in SQLite only
+	like := "LIKE"
+	if db.postgres {
+		like = "ILIKE"
+	}
+	likeClauses := make([]string, len(tags))
+	likeArgs := make([]any, len(tags))
+	for i, tag := range tags {
+		likeClauses[i] = "a.raw_json " + like + " ?"
+		likeArgs[i] = "%#" + tag + "%"
+	}
+
+	for len(posts) < limit {
+		filter, pageArgs := db.cursorFilter("a.created_at", "a.id", before)
+		args := append(append(append([]any{}, likeArgs...), pageArgs...), limit)
+		query := fmt.Sprintf(sqlSelectHashtagRemoteActivitiesFmt, strings.Join(likeClauses, " OR "),
+			filter+db.cursorOrder("a.created_at", "a.id"))
+
+		err, batch, read, last := db.readHashtagRemoteBatch(query, args, tags)
+		if err != nil {
+			return err, posts
+		}
+		for _, post := range batch {
+			if len(posts) == limit {
+				break
+			}
+			posts = append(posts, post)
+		}
+		if read < limit {
+			break
+		}
+		before = last
+	}
+	return nil, posts
+}
+
+// readHashtagRemoteBatch runs one page of sqlSelectHashtagRemoteActivitiesFmt and returns the posts
+// that really carry one of the tags, the number of rows read and the cursor of the last row
+func (db *DB) readHashtagRemoteBatch(query string, args []any, tags []string) (error, []domain.HomePost, int, domain.Cursor) {
+	var posts []domain.HomePost
+	var read int
+	var last domain.Cursor
+
+	remoteRows, err := db.db.Query(query, args...)
+	if err != nil {
+		return err, posts, read, last
+	}
+	defer remoteRows.Close()
+
+	for remoteRows.Next() {
+		var idStr string
+		var actorURI string
+		var objectURI string
+		var objectURL string
+		var rawJSON string
+		var createdAtStr string
+		var username string
+		var remDomain string
+		var replyCount int
+		var likeCount int
+		var boostCount int
+
+		if err := remoteRows.Scan(&idStr, &actorURI, &objectURI, &objectURL, &rawJSON, &createdAtStr, &username, &remDomain, &replyCount, &likeCount, &boostCount); err != nil {
+			return err, posts, read, last
+		}
+
+		activityId, _ := uuid.Parse(idStr)
+		parsedTime, _ := parseTimestamp(createdAtStr)
+		read++
+		last = domain.Cursor{CreatedAt: parsedTime, Id: activityId}
+
+		// Confirm the match against the actual Hashtag tags (LIKE may match substrings)
+		tag := matchFollowedHashtag(util.ExtractHashtagsFromJSON(rawJSON), tags)
+		if tag == "" {
+			continue
+		}
+
+		content := extractContentFromJSON(rawJSON)
+		if content == "" && objectURL != "" {
+			content = objectURL
+		}
+
+		// Relay-forwarded posts may come from actors we have never stored
+		author := "@" + username + "@" + remDomain
+		if username == "" {
+			author = extractAuthorFromActorURI(actorURI)
+		}
+
+		posts = append(posts, domain.HomePost{
+			ID:         activityId,
+			Author:     author,
+			Content:    content,
+			Time:       parsedTime,
+			ObjectURI:  objectURI,
+			ObjectURL:  objectURL,
+			IsLocal:    false,
+			NoteID:     uuid.Nil,
+			ReplyCount: replyCount,
+			LikeCount:  likeCount,
+			BoostCount: boostCount,
+			ViaHashtag: tag,
+			Emojis:     util.ExtractEmojiTagsFromJSON(rawJSON),
+		})
+	}
+	if err = remoteRows.Err(); err != nil {
+		return err, posts, read, last
+	}
+	return nil, posts, read, last
+}
+
+// matchFollowedHashtag returns the first of a post's hashtags that is in the followed list, or ""
+func matchFollowedHashtag(postTags []string, followed []string) string {
+	for _, tag := range postTags {
+		for _, f := range followed {
+			if tag == f {
+				return tag
+			}
+		}
+	}
+	return ""
+}
+
+// List queries
+const (
+	sqlInsertList     = `INSERT INTO lists(id, account_id, name, exclude_from_home, created_at) VALUES (?, ?, ?, ?, ?)`
+	sqlSelectListsFmt = `SELECT l.id, l.account_id, l.name, l.exclude_from_home, l.created_at,
+		(SELECT COUNT(*) FROM list_members lm WHERE lm.list_id = l.id)
+		FROM lists l WHERE %s`
+	sqlUpdateListExcludeFromHome = `UPDATE lists SET exclude_from_home = ? WHERE id = ?`
+	sqlDeleteListMembers         = `DELETE FROM list_members WHERE list_id = ?`
+	sqlDeleteList                = `DELETE FROM lists WHERE id = ?`
+	sqlInsertListMember          = `INSERT INTO list_members(list_id, member_id, is_local, created_at) VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING`
+	sqlDeleteListMember          = `DELETE FROM list_members WHERE list_id = ? AND member_id = ?`
+	sqlSelectListIdsByMember     = `SELECT lm.list_id FROM list_members lm
+		INNER JOIN lists l ON l.id = lm.list_id
+		WHERE l.account_id = ? AND lm.member_id = ?`
+)
+
+// CreateList creates a new list; names are unique per account
+func (db *DB) CreateList(list *domain.List) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlInsertList,
+			list.Id.String(),
+			list.AccountId.String(),
+			list.Name,
+			list.ExcludeFromHome,
+			list.CreatedAt.Format(time.RFC3339))
+		return err
+	})
+}
+
+// ReadListsByAccountId returns an account's lists with their member counts, ordered by name
+func (db *DB) ReadListsByAccountId(accountId uuid.UUID) (error, *[]domain.List) {
+	rows, err := db.db.Query(fmt.Sprintf(sqlSelectListsFmt, "l.account_id = ? ORDER BY LOWER(l.name) ASC"), accountId.String())
+	if err != nil {
+		return err, nil
+	}
+	defer rows.Close()
+
+	var lists []domain.List
+	for rows.Next() {
+		list, err := scanList(rows)
+		if err != nil {
+			return err, &lists
+		}
+		lists = append(lists, *list)
+	}
+	if err = rows.Err(); err != nil {
+		return err, &lists
+	}
+	return nil, &lists
+}
+
+// ReadListByName returns one of an account's lists by name, or nil if it doesn't exist
+func (db *DB) ReadListByName(accountId uuid.UUID, name string) (error, *domain.List) {
+	row := db.db.QueryRow(fmt.Sprintf(sqlSelectListsFmt, "l.account_id = ? AND l.name = ?"), accountId.String(), name)
+	list, err := scanList(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return err, nil
+	}
+	return nil, list
+}
+
+// scanList reads a list row selected with sqlSelectListsFmt
+func scanList(row interface{ Scan(...any) error }) (*domain.List, error) {
+	var list domain.List
+	var idStr, accountIdStr, createdAtStr string
+	if err := row.Scan(&idStr, &accountIdStr, &list.Name, &list.ExcludeFromHome, &createdAtStr, &list.MemberCount); err != nil {
+		return nil, err
+	}
+	list.Id, _ = uuid.Parse(idStr)
+	list.AccountId, _ = uuid.Parse(accountIdStr)
+	list.CreatedAt, _ = parseTimestamp(createdAtStr)
+	return &list, nil
+}
+
+// UpdateListExcludeFromHome sets whether the list's members are hidden from the home timeline
+func (db *DB) UpdateListExcludeFromHome(listId uuid.UUID, exclude bool) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlUpdateListExcludeFromHome, exclude, listId.String())
+		return err
+	})
+}
+
+// DeleteList removes a list and its memberships
+func (db *DB) DeleteList(listId uuid.UUID) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(sqlDeleteListMembers, listId.String()); err != nil {
+			return err
+		}
+		_, err := tx.Exec(sqlDeleteList, listId.String())
+		return err
+	})
+}
+
+// AddListMember adds a local account or remote account to a list (no-op if already a member)
+func (db *DB) AddListMember(listId uuid.UUID, memberId uuid.UUID, isLocal bool) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlInsertListMember, listId.String(), memberId.String(), isLocal, time.Now().Format(time.RFC3339))
+		return err
+	})
+}
+
+// RemoveListMember removes an account from a list
+func (db *DB) RemoveListMember(listId uuid.UUID, memberId uuid.UUID) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlDeleteListMember, listId.String(), memberId.String())
+		return err
+	})
+}
+
+// ReadListIdsByMember returns the ids of the account's lists that contain memberId
+func (db *DB) ReadListIdsByMember(accountId uuid.UUID, memberId uuid.UUID) (error, []uuid.UUID) {
+	rows, err := db.db.Query(sqlSelectListIdsByMember, accountId.String(), memberId.String())
+	if err != nil {
+		return err, nil
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var idStr string
+		if err := rows.Scan(&idStr); err != nil {
+			return err, ids
+		}
+		if id, err := uuid.Parse(idStr); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err, ids
+	}
+	return nil, ids
+}
+
+// Keyword filter queries
+const (
+	sqlInsertFilter             = `INSERT INTO filters(id, account_id, phrase, whole_word, contexts, action, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	sqlSelectFiltersByAccountId = `SELECT id, account_id, phrase, whole_word, contexts, action, expires_at, created_at
+		FROM filters WHERE account_id = ? ORDER BY created_at ASC`
+	sqlDeleteFilter = `DELETE FROM filters WHERE id = ?`
+)
+
+// CreateFilter stores a new keyword filter
+func (db *DB) CreateFilter(filter *domain.Filter) error {
+	var expiresAt any
+	if filter.ExpiresAt != nil {
+		expiresAt = filter.ExpiresAt.Format(time.RFC3339)
+	}
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlInsertFilter,
+			filter.Id.String(),
+			filter.AccountId.String(),
+			filter.Phrase,
+			filter.WholeWord,
+			strings.Join(filter.Contexts, ","),
+			filter.Action,
+			expiresAt,
+			filter.CreatedAt.Format(time.RFC3339))
+		return err
+	})
+}
+
+// ReadFiltersByAccountId returns all keyword filters of an account, including expired ones.
+// Expiry is checked when the filters are applied (see domain.MatchFilters).
+func (db *DB) ReadFiltersByAccountId(accountId uuid.UUID) (error, *[]domain.Filter) {
+	rows, err := db.db.Query(sqlSelectFiltersByAccountId, accountId.String())
+	if err != nil {
+		return err, nil
+	}
+	defer rows.Close()
+
+	var filters []domain.Filter
+	for rows.Next() {
+		var filter domain.Filter
+		var idStr, accountIdStr, contexts, createdAtStr string
+		var expiresAtStr sql.NullString
+		if err := rows.Scan(&idStr, &accountIdStr, &filter.Phrase, &filter.WholeWord, &contexts, &filter.Action, &expiresAtStr, &createdAtStr); err != nil {
+			return err, &filters
+		}
+		filter.Id, _ = uuid.Parse(idStr)
+		filter.AccountId, _ = uuid.Parse(accountIdStr)
+		if contexts != "" {
+			filter.Contexts = strings.Split(contexts, ",")
+		}
+		if expiresAtStr.Valid && expiresAtStr.String != "" {
+			if parsed, err := parseTimestamp(expiresAtStr.String); err == nil {
+				filter.ExpiresAt = &parsed
+			}
+		}
+		filter.CreatedAt, _ = parseTimestamp(createdAtStr)
+		filters = append(filters, filter)
+	}
+	if err = rows.Err(); err != nil {
+		return err, &filters
+	}
+	return nil, &filters
+}
+
+// DeleteFilter removes a keyword filter
+func (db *DB) DeleteFilter(id uuid.UUID) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlDeleteFilter, id.String())
+		return err
+	})
+}
+
+// Scheduled note queries
+const (
+	sqlInsertScheduledNote  = `INSERT INTO scheduled_notes(id, account_id, message, scheduled_at, created_at) VALUES (?, ?, ?, ?, ?)`
+	sqlSelectScheduledNotes = `SELECT id, account_id, message, scheduled_at, created_at FROM scheduled_notes`
+	sqlUpdateScheduledNote  = `UPDATE scheduled_notes SET message = ?, scheduled_at = ? WHERE id = ? AND account_id = ?`
+	sqlDeleteScheduledNote  = `DELETE FROM scheduled_notes WHERE id = ? AND account_id = ?`
+)
+
+// CreateScheduledNote stores a note to be published at note.ScheduledAt
+func (db *DB) CreateScheduledNote(note *domain.ScheduledNote) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlInsertScheduledNote,
+			note.Id.String(),
+			note.AccountId.String(),
+			note.Message,
+			note.ScheduledAt.UTC().Format(time.RFC3339),
+			note.CreatedAt.UTC().Format(time.RFC3339))
+		return err
+	})
+}
+
+// ReadScheduledNotesByAccountId returns the pending scheduled notes of an account, due first
+func (db *DB) ReadScheduledNotesByAccountId(accountId uuid.UUID) (error, *[]domain.ScheduledNote) {
+	return db.readScheduledNotes(sqlSelectScheduledNotes+` WHERE account_id = ? ORDER BY scheduled_at ASC`, accountId.String())
+}
+
+// ReadDueScheduledNotes returns up to limit scheduled notes due at or before now, oldest first
+func (db *DB) ReadDueScheduledNotes(now time.Time, limit int) (error, *[]domain.ScheduledNote) {
+	return db.readScheduledNotes(sqlSelectScheduledNotes+` WHERE scheduled_at <= ? ORDER BY scheduled_at ASC LIMIT ?`,
+		now.UTC().Format(time.RFC3339), limit)
+}
+
+func (db *DB) readScheduledNotes(query string, args ...any) (error, *[]domain.ScheduledNote) {
+	rows, err := db.db.Query(query, args...)
+	if err != nil {
+		return err, nil
+	}
+	defer rows.Close()
+
+	var notes []domain.ScheduledNote
+	for rows.Next() {
+		var note domain.ScheduledNote
+		var idStr, accountIdStr, scheduledAtStr, createdAtStr string
+		if err := rows.Scan(&idStr, &accountIdStr, &note.Message, &scheduledAtStr, &createdAtStr); err != nil {
+			return err, &notes
+		}
+		note.Id, _ = uuid.Parse(idStr)
+		note.AccountId, _ = uuid.Parse(accountIdStr)
+		// Stored as real UTC RFC3339, unlike the SQLite timestamps parseTimestamp handles
+		note.ScheduledAt, _ = time.Parse(time.RFC3339, scheduledAtStr)
+		note.CreatedAt, _ = time.Parse(time.RFC3339, createdAtStr)
+		notes = append(notes, note)
+	}
+	if err = rows.Err(); err != nil {
+		return err, &notes
+	}
+	return nil, &notes
+}
+
+// UpdateScheduledNote changes the text and time of a scheduled note owned by accountId
+func (db *DB) UpdateScheduledNote(id, accountId uuid.UUID, message string, scheduledAt time.Time) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec(sqlUpdateScheduledNote, message, scheduledAt.UTC().Format(time.RFC3339), id.String(), accountId.String())
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("scheduled note not found")
+		}
+		return nil
+	})
+}
+
+// DeleteScheduledNote cancels a scheduled note owned by accountId
+func (db *DB) DeleteScheduledNote(id, accountId uuid.UUID) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlDeleteScheduledNote, id.String(), accountId.String())
+		return err
+	})
+}
+
+// PublishScheduledNote turns a scheduled note into a regular note and removes it from the
+// schedule in one transaction, so a note cancelled at the same moment is never published
+// and a published note is never published twice. Returns the new note's id.
+func (db *DB) PublishScheduledNote(scheduled *domain.ScheduledNote) (uuid.UUID, error) {
+	var noteId uuid.UUID
+	err := db.wrapTransaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec(sqlDeleteScheduledNote, scheduled.Id.String(), scheduled.AccountId.String())
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("scheduled note %s was cancelled", scheduled.Id)
+		}
+		noteId, err = db.insertNoteWithReply(tx, scheduled.AccountId, scheduled.Message, "")
+		return err
+	})
+	publishNoteCreated(err, scheduled.AccountId, noteId)
+	return noteId, err
+}
+
+// Draft queries
+const (
+	sqlUpsertDraft = `INSERT INTO drafts(id, account_id, message, title, in_reply_to_uri, reply_to_author, reply_to_preview, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET message = excluded.message, title = excluded.title, in_reply_to_uri = excluded.in_reply_to_uri, reply_to_author = excluded.reply_to_author,
+		reply_to_preview = excluded.reply_to_preview, updated_at = excluded.updated_at WHERE drafts.account_id = excluded.account_id`
+	sqlSelectDraftsByAccountId = `SELECT id, account_id, message, COALESCE(title, ''), in_reply_to_uri, reply_to_author, reply_to_preview, created_at, updated_at
+		FROM drafts WHERE account_id = ? ORDER BY updated_at DESC`
+	sqlDeleteDraft = `DELETE FROM drafts WHERE id = ? AND account_id = ?`
+)
+
+// SaveDraft creates or updates a draft (keyed by draft.Id)
+func (db *DB) SaveDraft(draft *domain.Draft) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlUpsertDraft,
+			draft.Id.String(),
+			draft.AccountId.String(),
+			draft.Message,
+			draft.Title,
+			draft.InReplyToURI,
+			draft.ReplyToAuthor,
+			draft.ReplyToPreview,
+			draft.CreatedAt.UTC().Format(time.RFC3339),
+			draft.UpdatedAt.UTC().Format(time.RFC3339))
+		return err
+	})
+}
+
+// ReadDraftsByAccountId returns the drafts of an account, most recently edited first
+func (db *DB) ReadDraftsByAccountId(accountId uuid.UUID) (error, *[]domain.Draft) {
+	rows, err := db.db.Query(sqlSelectDraftsByAccountId, accountId.String())
+	if err != nil {
+		return err, nil
+	}
+	defer rows.Close()
+
+	var drafts []domain.Draft
+	for rows.Next() {
+		var draft domain.Draft
+		var idStr, accountIdStr, createdAtStr, updatedAtStr string
+		if err := rows.Scan(&idStr, &accountIdStr, &draft.Message, &draft.Title, &draft.InReplyToURI, &draft.ReplyToAuthor, &draft.ReplyToPreview, &createdAtStr, &updatedAtStr); err != nil {
+			return err, &drafts
+		}
+		draft.Id, _ = uuid.Parse(idStr)
+		draft.AccountId, _ = uuid.Parse(accountIdStr)
+		draft.CreatedAt, _ = time.Parse(time.RFC3339, createdAtStr)
+		draft.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAtStr)
+		drafts = append(drafts, draft)
+	}
+	if err = rows.Err(); err != nil {
+		return err, &drafts
+	}
+	return nil, &drafts
+}
+
+// DeleteDraft discards a draft owned by accountId
+func (db *DB) DeleteDraft(id, accountId uuid.UUID) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlDeleteDraft, id.String(), accountId.String())
+		return err
+	})
+}
+
+// PublishDraft turns a draft into a regular note (a reply if InReplyToURI is set,
+// an article if Title is set) and removes the draft in one transaction. Returns the new note's id.
+func (db *DB) PublishDraft(draft *domain.Draft) (uuid.UUID, error) {
+	var noteId uuid.UUID
+	err := db.wrapTransaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec(sqlDeleteDraft, draft.Id.String(), draft.AccountId.String())
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("draft %s not found", draft.Id)
+		}
+		if draft.Title != "" {
+			noteId, err = db.insertArticle(tx, draft.AccountId, draft.Title, draft.Message)
+			return err
+		}
+		noteId, err = db.insertNoteWithReply(tx, draft.AccountId, draft.Message, draft.InReplyToURI)
+		return err
+	})
+	publishNoteCreated(err, draft.AccountId, noteId)
+	return noteId, err
+}
+
+// OAuth queries (Mastodon client API)
+const (
+	sqlInsertOAuthApp            = `INSERT INTO oauth_apps(id, client_id, client_secret, name, website, redirect_uris, scopes, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	sqlSelectOAuthAppByClientId  = `SELECT id, client_id, client_secret, name, website, redirect_uris, scopes, created_at FROM oauth_apps WHERE client_id = ?`
+	sqlInsertOAuthAuthorization  = `INSERT INTO oauth_authorizations(id, app_id, user_code, redirect_uri, scopes, state, status, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	sqlSelectOAuthAuthorizations = `SELECT oa.id, oa.app_id, COALESCE(app.name, ''), oa.user_code, oa.redirect_uri, oa.scopes, oa.state, oa.status,
+		COALESCE(oa.account_id, ''), COALESCE(oa.code, ''), oa.expires_at, oa.created_at
+		FROM oauth_authorizations oa LEFT JOIN oauth_apps app ON app.id = oa.app_id`
+	sqlApproveOAuthAuthorization = `UPDATE oauth_authorizations SET status = 'approved', account_id = ?, code = ? WHERE id = ? AND status = 'pending'`
+	sqlDenyOAuthAuthorization    = `UPDATE oauth_authorizations SET status = 'denied' WHERE id = ? AND status = 'pending'`
+	sqlDeleteOAuthAuthorization  = `DELETE FROM oauth_authorizations WHERE id = ?`
+	sqlInsertOAuthToken          = `INSERT INTO oauth_tokens(id, token_hash, app_id, account_id, scopes, created_at, last_used_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	sqlSelectOAuthTokens         = `SELECT t.id, t.token_hash, t.app_id, COALESCE(app.name, ''), COALESCE(t.account_id, ''), t.scopes, t.created_at, t.last_used_at
+		FROM oauth_tokens t LEFT JOIN oauth_apps app ON app.id = t.app_id`
+	sqlTouchOAuthToken  = `UPDATE oauth_tokens SET last_used_at = ? WHERE id = ?`
+	sqlDeleteOAuthToken = `DELETE FROM oauth_tokens WHERE id = ?`
+)
+
+// CreateOAuthApp registers a client application
+func (db *DB) CreateOAuthApp(app *domain.OAuthApp) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlInsertOAuthApp,
+			app.Id.String(),
+			app.ClientId,
+			app.ClientSecret,
+			app.Name,
+			app.Website,
+			strings.Join(app.RedirectURIs, " "),
+			strings.Join(app.Scopes, " "),
+			app.CreatedAt.Format(time.RFC3339))
+		return err
+	})
+}
+
+// ReadOAuthAppByClientId returns the app registered with the given client id
+func (db *DB) ReadOAuthAppByClientId(clientId string) (error, *domain.OAuthApp) {
+	var app domain.OAuthApp
+	var idStr, redirectURIs, scopes, createdAtStr string
+	err := db.db.QueryRow(sqlSelectOAuthAppByClientId, clientId).Scan(
+		&idStr, &app.ClientId, &app.ClientSecret, &app.Name, &app.Website, &redirectURIs, &scopes, &createdAtStr)
+	if err != nil {
+		return err, nil
+	}
+	app.Id, _ = uuid.Parse(idStr)
+	app.RedirectURIs = strings.Fields(redirectURIs)
+	app.Scopes = strings.Fields(scopes)
+	app.CreatedAt, _ = parseTimestamp(createdAtStr)
+	return nil, &app
+}
+
+// CreateOAuthAuthorization stores a new authorization request
+func (db *DB) CreateOAuthAuthorization(auth *domain.OAuthAuthorization) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		// Expired requests are of no use to anyone
+		if _, err := tx.Exec(`DELETE FROM oauth_authorizations WHERE expires_at < ?`, time.Now().Format(time.RFC3339)); err != nil {
+			return err
+		}
+		_, err := tx.Exec(sqlInsertOAuthAuthorization,
+			auth.Id.String(),
+			auth.AppId.String(),
+			auth.UserCode,
+			auth.RedirectURI,
+			strings.Join(auth.Scopes, " "),
+			auth.State,
+			domain.OAuthAuthorizationPending,
+			auth.ExpiresAt.Format(time.RFC3339),
+			auth.CreatedAt.Format(time.RFC3339))
+		return err
+	})
+}
+
+// ReadOAuthAuthorizationById returns an authorization request by id
+func (db *DB) ReadOAuthAuthorizationById(id uuid.UUID) (error, *domain.OAuthAuthorization) {
+	return db.readOAuthAuthorization(sqlSelectOAuthAuthorizations+` WHERE oa.id = ?`, id.String())
+}
+
+// ReadPendingOAuthAuthorizationByUserCode returns the pending request for a code typed into the TUI
+func (db *DB) ReadPendingOAuthAuthorizationByUserCode(userCode string) (error, *domain.OAuthAuthorization) {
+	return db.readOAuthAuthorization(sqlSelectOAuthAuthorizations+` WHERE oa.user_code = ? AND oa.status = 'pending' AND oa.expires_at > ?
+		ORDER BY oa.created_at DESC LIMIT 1`, userCode, time.Now().Format(time.RFC3339))
+}
+
+// ReadOAuthAuthorizationByCode returns the approved request an authorization code was issued for
+func (db *DB) ReadOAuthAuthorizationByCode(code string) (error, *domain.OAuthAuthorization) {
+	return db.readOAuthAuthorization(sqlSelectOAuthAuthorizations+` WHERE oa.code = ? AND oa.status = 'approved'`, code)
+}
+
+func (db *DB) readOAuthAuthorization(query string, args ...any) (error, *domain.OAuthAuthorization) {
+	var auth domain.OAuthAuthorization
+	var idStr, appIdStr, scopes, accountIdStr, expiresAtStr, createdAtStr string
+	err := db.db.QueryRow(query, args...).Scan(&idStr, &appIdStr, &auth.AppName, &auth.UserCode, &auth.RedirectURI, &scopes,
+		&auth.State, &auth.Status, &accountIdStr, &auth.Code, &expiresAtStr, &createdAtStr)
+	if err != nil {
+		return err, nil
+	}
+	auth.Id, _ = uuid.Parse(idStr)
+	auth.AppId, _ = uuid.Parse(appIdStr)
+	auth.Scopes = strings.Fields(scopes)
+	if accountIdStr != "" {
+		auth.AccountId, _ = uuid.Parse(accountIdStr)
+	}
+	auth.ExpiresAt, _ = parseTimestamp(expiresAtStr)
+	auth.CreatedAt, _ = parseTimestamp(createdAtStr)
+	return nil, &auth
+}
+
+// ApproveOAuthAuthorization attaches the confirming account and the authorization code to a pending request
+func (db *DB) ApproveOAuthAuthorization(id uuid.UUID, accountId uuid.UUID, code string) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec(sqlApproveOAuthAuthorization, accountId.String(), code, id.String())
 		if err != nil {
 			return err
 		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("authorization request is no longer pending")
+		}
 		return nil
 	})
-	return hashtagId, err
 }
 
-// LinkNoteHashtags creates links between a note and multiple hashtags
-func (db *DB) LinkNoteHashtags(noteId uuid.UUID, hashtagIds []int64) error {
+// DenyOAuthAuthorization rejects a pending request
+func (db *DB) DenyOAuthAuthorization(id uuid.UUID) error {
 	return db.wrapTransaction(func(tx *sql.Tx) error {
-		for _, hashtagId := range hashtagIds {
-			_, err := tx.Exec(sqlInsertNoteHashtag, noteId.String(), hashtagId)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
+		_, err := tx.Exec(sqlDenyOAuthAuthorization, id.String())
+		return err
 	})
 }
 
-// ReadHashtagsByNoteId returns all hashtag names for a given note
-func (db *DB) ReadHashtagsByNoteId(noteId uuid.UUID) (error, []string) {
-	rows, err := db.db.Query(sqlSelectHashtagsByNoteId, noteId.String())
-	if err != nil {
-		return err, nil
+// DeleteOAuthAuthorization removes a request once its code has been exchanged
+func (db *DB) DeleteOAuthAuthorization(id uuid.UUID) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlDeleteOAuthAuthorization, id.String())
+		return err
+	})
+}
+
+// CreateOAuthToken stores an issued access token
+func (db *DB) CreateOAuthToken(token *domain.OAuthToken) error {
+	var accountId any
+	if token.AccountId != uuid.Nil {
+		accountId = token.AccountId.String()
 	}
-	defer rows.Close()
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlInsertOAuthToken,
+			token.Id.String(),
+			token.TokenHash,
+			token.AppId.String(),
+			accountId,
+			strings.Join(token.Scopes, " "),
+			token.CreatedAt.Format(time.RFC3339),
+			token.CreatedAt.Format(time.RFC3339))
+		return err
+	})
+}
 
-	var hashtags []string
-	for rows.Next() {
-		var name string
-		if err := rows.Scan(&name); err != nil {
-			return err, hashtags
-		}
-		hashtags = append(hashtags, name)
+// ReadOAuthTokenByHash looks up an access token by its hash
+func (db *DB) ReadOAuthTokenByHash(tokenHash string) (error, *domain.OAuthToken) {
+	err, tokens := db.readOAuthTokens(sqlSelectOAuthTokens+` WHERE t.token_hash = ?`, tokenHash)
+	if err != nil {
+		return err, nil
 	}
-	if err = rows.Err(); err != nil {
-		return err, hashtags
+	if len(*tokens) == 0 {
+		return sql.ErrNoRows, nil
 	}
-	return nil, hashtags
+	return nil, &(*tokens)[0]
 }
 
-// ReadNotesByHashtag returns notes that contain a specific hashtag with pagination
-func (db *DB) ReadNotesByHashtag(tag string, limit, offset int) (error, *[]domain.Note) {
-	rows, err := db.db.Query(sqlSelectNotesByHashtag, strings.ToLower(tag), limit, offset)
+// ReadOAuthTokensByAccountId returns the apps an account has authorized, newest first
+func (db *DB) ReadOAuthTokensByAccountId(accountId uuid.UUID) (error, *[]domain.OAuthToken) {
+	return db.readOAuthTokens(sqlSelectOAuthTokens+` WHERE t.account_id = ? ORDER BY t.created_at DESC`, accountId.String())
+}
+
+func (db *DB) readOAuthTokens(query string, args ...any) (error, *[]domain.OAuthToken) {
+	rows, err := db.db.Query(query, args...)
 	if err != nil {
 		return err, nil
 	}
 	defer rows.Close()
 
-	var notes []domain.Note
+	tokens := []domain.OAuthToken{}
 	for rows.Next() {
-		var note domain.Note
-		var createdAtStr string
-		var editedAtStr sql.NullString
-		if err := rows.Scan(&note.Id, &note.CreatedBy, &note.Message, &createdAtStr, &editedAtStr, &note.LikeCount, &note.BoostCount); err != nil {
-			return err, &notes
-		}
-
-		if parsedTime, err := parseTimestamp(createdAtStr); err == nil {
-			note.CreatedAt = parsedTime
+		var token domain.OAuthToken
+		var idStr, appIdStr, accountIdStr, scopes, createdAtStr, lastUsedAtStr string
+		if err := rows.Scan(&idStr, &token.TokenHash, &appIdStr, &token.AppName, &accountIdStr, &scopes, &createdAtStr, &lastUsedAtStr); err != nil {
+			return err, &tokens
 		}
-
-		if editedAtStr.Valid {
-			if parsedTime, err := parseTimestamp(editedAtStr.String); err == nil {
-				note.EditedAt = &parsedTime
-			}
+		token.Id, _ = uuid.Parse(idStr)
+		token.AppId, _ = uuid.Parse(appIdStr)
+		if accountIdStr != "" {
+			token.AccountId, _ = uuid.Parse(accountIdStr)
 		}
-
-		notes = append(notes, note)
+		token.Scopes = strings.Fields(scopes)
+		token.CreatedAt, _ = parseTimestamp(createdAtStr)
+		token.LastUsedAt, _ = parseTimestamp(lastUsedAtStr)
+		tokens = append(tokens, token)
 	}
 	if err = rows.Err(); err != nil {
-		return err, &notes
+		return err, &tokens
 	}
-	return nil, &notes
+	return nil, &tokens
 }
 
-// CountNotesByHashtag returns the total count of notes with a specific hashtag
-func (db *DB) CountNotesByHashtag(tag string) (int, error) {
-	var count int
-	err := db.db.QueryRow(sqlCountNotesByHashtag, strings.ToLower(tag)).Scan(&count)
-	if err != nil {
-		return 0, err
-	}
-	return count, nil
+// TouchOAuthToken records that a token was just used
+func (db *DB) TouchOAuthToken(id uuid.UUID) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlTouchOAuthToken, time.Now().Format(time.RFC3339), id.String())
+		return err
+	})
+}
+
+// DeleteOAuthToken revokes an access token
+func (db *DB) DeleteOAuthToken(id uuid.UUID) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlDeleteOAuthToken, id.String())
+		return err
+	})
 }
 
 // Mention queries
@@ -3051,6 +4721,144 @@ func (db *DB) DeleteLikeByAccountAndObjectURI(accountId uuid.UUID, objectURI str
 	})
 }
 
+// Reaction queries
+const (
+	sqlInsertReaction                  = `INSERT INTO reactions(id, account_id, note_id, object_uri, emoji, uri, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	sqlSelectReactionByURI             = `SELECT id, account_id, note_id, object_uri, emoji, uri, created_at FROM reactions WHERE uri = ?`
+	sqlSelectReactionByAccountNote     = `SELECT id, account_id, note_id, object_uri, emoji, uri, created_at FROM reactions WHERE account_id = ? AND note_id = ?`
+	sqlSelectReactionByAccountObject   = `SELECT id, account_id, note_id, object_uri, emoji, uri, created_at FROM reactions WHERE account_id = ? AND object_uri = ? AND note_id IS NULL`
+	sqlDeleteReactionByURI             = `DELETE FROM reactions WHERE uri = ?`
+	sqlDeleteReactionById              = `DELETE FROM reactions WHERE id = ?`
+	sqlSelectReactionCountsByNoteId    = `SELECT emoji, COUNT(*) FROM reactions WHERE note_id = ? GROUP BY emoji ORDER BY COUNT(*) DESC, MIN(created_at) ASC`
+	sqlSelectReactionCountsByObjectURI = `SELECT emoji, COUNT(*) FROM reactions WHERE object_uri = ? AND note_id IS NULL GROUP BY emoji ORDER BY COUNT(*) DESC, MIN(created_at) ASC`
+)
+
+// CreateReaction stores an emoji reaction on a local note or remote post
+func (db *DB) CreateReaction(reaction *domain.Reaction) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		var noteId interface{}
+		if reaction.NoteId != uuid.Nil {
+			noteId = reaction.NoteId.String()
+		}
+		var objectURI interface{}
+		if reaction.ObjectURI != "" {
+			objectURI = reaction.ObjectURI
+		}
+		_, err := tx.Exec(sqlInsertReaction,
+			reaction.Id.String(),
+			reaction.AccountId.String(),
+			noteId,
+			objectURI,
+			reaction.Emoji,
+			reaction.URI,
+			reaction.CreatedAt.Format(time.RFC3339))
+		return err
+	})
+}
+
+// scanReaction scans a single reaction row
+func scanReaction(row *sql.Row) (error, *domain.Reaction) {
+	var reaction domain.Reaction
+	var idStr, accountIdStr, createdAtStr string
+	var noteIdStr sql.NullString
+	var objectURI sql.NullString
+	err := row.Scan(&idStr, &accountIdStr, &noteIdStr, &objectURI, &reaction.Emoji, &reaction.URI, &createdAtStr)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return err, nil
+	}
+	reaction.Id, _ = uuid.Parse(idStr)
+	reaction.AccountId, _ = uuid.Parse(accountIdStr)
+	if noteIdStr.Valid {
+		reaction.NoteId, _ = uuid.Parse(noteIdStr.String)
+	}
+	reaction.ObjectURI = objectURI.String
+	if parsedTime, err := parseTimestamp(createdAtStr); err == nil {
+		reaction.CreatedAt = parsedTime
+	}
+	return nil, &reaction
+}
+
+// ReadReactionByURI finds a reaction by its activity URI (returns nil if not found)
+func (db *DB) ReadReactionByURI(uri string) (error, *domain.Reaction) {
+	return scanReaction(db.db.QueryRow(sqlSelectReactionByURI, uri))
+}
+
+// ReadReactionByAccountAndNote finds the reaction an account left on a local note (returns nil if not found)
+func (db *DB) ReadReactionByAccountAndNote(accountId, noteId uuid.UUID) (error, *domain.Reaction) {
+	return scanReaction(db.db.QueryRow(sqlSelectReactionByAccountNote, accountId.String(), noteId.String()))
+}
+
+// ReadReactionByAccountAndObjectURI finds the reaction an account left on a post by object URI (returns nil if not found)
+func (db *DB) ReadReactionByAccountAndObjectURI(accountId uuid.UUID, objectURI string) (error, *domain.Reaction) {
+	return scanReaction(db.db.QueryRow(sqlSelectReactionByAccountObject, accountId.String(), objectURI))
+}
+
+// DeleteReactionByURI removes a reaction by its activity URI
+func (db *DB) DeleteReactionByURI(uri string) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlDeleteReactionByURI, uri)
+		return err
+	})
+}
+
+// DeleteReactionById removes a reaction by its ID
+func (db *DB) DeleteReactionById(id uuid.UUID) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlDeleteReactionById, id.String())
+		return err
+	})
+}
+
+// ReadReactionCountsByNoteId returns per-emoji reaction counts for a local note
+func (db *DB) ReadReactionCountsByNoteId(noteId uuid.UUID) (error, []domain.ReactionCount) {
+	return db.readReactionCounts(sqlSelectReactionCountsByNoteId, noteId.String())
+}
+
+// ReadReactionCountsByObjectURI returns per-emoji reaction counts for a post by object URI
+func (db *DB) ReadReactionCountsByObjectURI(objectURI string) (error, []domain.ReactionCount) {
+	return db.readReactionCounts(sqlSelectReactionCountsByObjectURI, objectURI)
+}
+
+func (db *DB) readReactionCounts(query string, arg string) (error, []domain.ReactionCount) {
+	rows, err := db.db.Query(query, arg)
+	if err != nil {
+		return err, nil
+	}
+	defer rows.Close()
+
+	var counts []domain.ReactionCount
+	for rows.Next() {
+		var rc domain.ReactionCount
+		if err := rows.Scan(&rc.Emoji, &rc.Count); err != nil {
+			return err, counts
+		}
+		counts = append(counts, rc)
+	}
+	if err = rows.Err(); err != nil {
+		return err, counts
+	}
+	return nil, counts
+}
+
+// readReactionCountsForPost returns reaction counts for a post, preferring the local note id
+func (db *DB) readReactionCountsForPost(isLocal bool, noteId uuid.UUID, objectURI string) []domain.ReactionCount {
+	var err error
+	var counts []domain.ReactionCount
+	if isLocal && noteId != uuid.Nil {
+		err, counts = db.ReadReactionCountsByNoteId(noteId)
+	} else if objectURI != "" {
+		err, counts = db.ReadReactionCountsByObjectURI(objectURI)
+	}
+	if err != nil {
+		log.Printf("Failed to read reaction counts: %v", err)
+		return nil
+	}
+	return counts
+}
+
 // Boost queries
 const (
 	sqlInsertBoost              = `INSERT INTO boosts(id, account_id, note_id, uri, created_at) VALUES (?, ?, ?, ?, ?)`
@@ -3455,7 +5263,7 @@ func (db *DB) decrementReplyCountRecursive(tx *sql.Tx, uri string, visited map[s
 	// Handle local: prefix for local-only mode (e.g., "local:414b193d-0b53-4657-b1bf-eb3a6091d672")
 	if strings.HasPrefix(uri, "local:") {
 		noteIdStr := strings.TrimPrefix(uri, "local:")
-		result, _ := tx.Exec(`UPDATE notes SET reply_count = MAX(0, reply_count - 1) WHERE id = ?`, noteIdStr)
+		result, _ := tx.Exec(`UPDATE notes SET reply_count = CASE WHEN reply_count > 0 THEN reply_count - 1 ELSE 0 END WHERE id = ?`, noteIdStr)
 		if rowsAffected, _ := result.RowsAffected(); rowsAffected > 0 {
 			tx.QueryRow(`SELECT COALESCE(in_reply_to_uri, '') FROM notes WHERE id = ?`, noteIdStr).Scan(&nextParentURI)
 			db.decrementReplyCountRecursive(tx, nextParentURI, visited)
@@ -3464,7 +5272,7 @@ func (db *DB) decrementReplyCountRecursive(tx *sql.Tx, uri string, visited map[s
 	}
 
 	// Try to decrement on notes table (by object_uri)
-	result, _ := tx.Exec(`UPDATE notes SET reply_count = MAX(0, reply_count - 1) WHERE object_uri = ?`, uri)
+	result, _ := tx.Exec(`UPDATE notes SET reply_count = CASE WHEN reply_count > 0 THEN reply_count - 1 ELSE 0 END WHERE object_uri = ?`, uri)
 	if rowsAffected, _ := result.RowsAffected(); rowsAffected > 0 {
 		tx.QueryRow(`SELECT COALESCE(in_reply_to_uri, '') FROM notes WHERE object_uri = ?`, uri).Scan(&nextParentURI)
 		db.decrementReplyCountRecursive(tx, nextParentURI, visited)
@@ -3479,7 +5287,7 @@ func (db *DB) decrementReplyCountRecursive(tx *sql.Tx, uri string, visited map[s
 			if idx := strings.Index(noteIdStr, "/"); idx > 0 {
 				noteIdStr = noteIdStr[:idx]
 			}
-			result, _ = tx.Exec(`UPDATE notes SET reply_count = MAX(0, reply_count - 1) WHERE id = ?`, noteIdStr)
+			result, _ = tx.Exec(`UPDATE notes SET reply_count = CASE WHEN reply_count > 0 THEN reply_count - 1 ELSE 0 END WHERE id = ?`, noteIdStr)
 			if rowsAffected, _ := result.RowsAffected(); rowsAffected > 0 {
 				tx.QueryRow(`SELECT COALESCE(in_reply_to_uri, '') FROM notes WHERE id = ?`, noteIdStr).Scan(&nextParentURI)
 				db.decrementReplyCountRecursive(tx, nextParentURI, visited)
@@ -3489,7 +5297,7 @@ func (db *DB) decrementReplyCountRecursive(tx *sql.Tx, uri string, visited map[s
 	}
 
 	// Try to decrement on activities table
-	result, _ = tx.Exec(`UPDATE activities SET reply_count = MAX(0, reply_count - 1) WHERE object_uri = ?`, uri)
+	result, _ = tx.Exec(`UPDATE activities SET reply_count = CASE WHEN reply_count > 0 THEN reply_count - 1 ELSE 0 END WHERE object_uri = ?`, uri)
 	if rowsAffected, _ := result.RowsAffected(); rowsAffected > 0 {
 		var rawJSON string
 		tx.QueryRow(`SELECT raw_json FROM activities WHERE object_uri = ?`, uri).Scan(&rawJSON)
@@ -3718,7 +5526,7 @@ func (db *DB) ReadNoteByURI(objectURI string) (error, *domain.Note) {
 // ReadNoteIdWithReplyInfo returns a note with full reply information
 func (db *DB) ReadNoteIdWithReplyInfo(id uuid.UUID) (error, *domain.Note) {
 	row := db.db.QueryRow(`
-		SELECT n.id, a.username, n.message, n.created_at, n.edited_at, n.in_reply_to_uri, n.object_uri, COALESCE(n.like_count, 0), COALESCE(n.boost_count, 0)
+		SELECT n.id, a.username, n.message, COALESCE(n.title, ''), n.created_at, n.edited_at, n.in_reply_to_uri, n.object_uri, COALESCE(n.like_count, 0), COALESCE(n.boost_count, 0)
 		FROM notes n
 		INNER JOIN accounts a ON a.id = n.user_id
 		WHERE n.id = ?`,
@@ -3727,7 +5535,7 @@ func (db *DB) ReadNoteIdWithReplyInfo(id uuid.UUID) (error, *domain.Note) {
 	var note domain.Note
 	var createdAtStr string
 	var editedAtStr, inReplyToURI, objectURI sql.NullString
-	err := row.Scan(&note.Id, &note.CreatedBy, &note.Message, &createdAtStr, &editedAtStr, &inReplyToURI, &objectURI, &note.LikeCount, &note.BoostCount)
+	err := row.Scan(&note.Id, &note.CreatedBy, &note.Message, &note.Title, &createdAtStr, &editedAtStr, &inReplyToURI, &objectURI, &note.LikeCount, &note.BoostCount)
 	if err == sql.ErrNoRows {
 		return err, nil
 	}
@@ -4034,18 +5842,144 @@ func (db *DB) DeleteRelayActivities() (int64, error) {
 	return count, err
 }
 
+// ============================================================================
+// Retention
+// ============================================================================
+
+const (
+	// Remote posts that are part of a thread with a local post: parents of local replies,
+	// replies to local notes, and everything reachable from those through inReplyTo.
+	// The walk up (to in_reply_to) and down (to object_uri) share one recursive step,
+	// as PostgreSQL allows only one.
+	sqlRetentionLocalThreads = `local_thread(uri) AS (
+		SELECT in_reply_to_uri FROM notes WHERE in_reply_to_uri IS NOT NULL AND in_reply_to_uri != ''
+		UNION
+		SELECT object_uri FROM activities WHERE object_uri IS NOT NULL
+			AND (in_reply_to LIKE ? OR in_reply_to IN (SELECT object_uri FROM notes WHERE object_uri IS NOT NULL AND object_uri != ''))
+		UNION
+		SELECT CASE WHEN a.object_uri = t.uri THEN a.in_reply_to ELSE a.object_uri END
+			FROM activities a INNER JOIN local_thread t ON a.object_uri = t.uri OR a.in_reply_to = t.uri
+			WHERE CASE WHEN a.object_uri = t.uri THEN a.in_reply_to ELSE a.object_uri END != ''
+	)`
+
+	// The rows each step prunes. Later steps look at kept_activities, kept_boosts and
+	// kept_notifications, the rows the earlier steps leave.
+
+	// Old remote posts nobody liked, boosted, reacted to or replied to
+	sqlPrunableActivities = `SELECT id FROM activities WHERE activity_type = 'Create' AND local = 0 AND created_at < ?
+		AND object_uri NOT IN (SELECT uri FROM local_thread WHERE uri IS NOT NULL)
+		AND object_uri NOT IN (SELECT object_uri FROM likes WHERE object_uri IS NOT NULL AND account_id IN (SELECT id FROM accounts))
+		AND object_uri NOT IN (SELECT object_uri FROM boosts WHERE object_uri IS NOT NULL AND account_id IN (SELECT id FROM accounts))
+		AND object_uri NOT IN (SELECT object_uri FROM reactions WHERE object_uri IS NOT NULL AND account_id IN (SELECT id FROM accounts))
+		AND object_uri NOT IN (SELECT in_reply_to FROM activities WHERE in_reply_to IS NOT NULL)`
+	// Boosts by remote accounts whose post is no longer stored (boosts of local notes are kept)
+	sqlPrunableRemoteBoosts = `SELECT id FROM boosts WHERE remote_account_id IS NOT NULL AND remote_account_id != '' AND created_at < ?
+		AND note_id NOT IN (SELECT id FROM notes)
+		AND (object_uri IS NULL OR object_uri NOT IN (SELECT object_uri FROM kept_activities WHERE object_uri IS NOT NULL))`
+	// Read notifications; unread ones are kept until their owner has seen them
+	sqlPrunableNotifications = `SELECT id FROM notifications WHERE read = 1 AND created_at < ?`
+	// Remote accounts nobody follows, lists, or refers to through stored content
+	sqlPrunableRemoteAccounts = `SELECT id FROM remote_accounts WHERE last_fetched_at < ?
+		AND id NOT IN (SELECT target_account_id FROM follows)
+		AND id NOT IN (SELECT account_id FROM follows)
+		AND id NOT IN (SELECT member_id FROM list_members)
+		AND id NOT IN (SELECT remote_account_id FROM kept_boosts WHERE remote_account_id IS NOT NULL)
+		AND id NOT IN (SELECT account_id FROM likes)
+		AND id NOT IN (SELECT account_id FROM reactions)
+		AND id NOT IN (SELECT actor_id FROM kept_notifications WHERE actor_id IS NOT NULL)
+		AND actor_uri NOT IN (SELECT actor_uri FROM kept_activities)`
+
+	// After the earlier deletes, the kept rows are the stored ones
+	sqlRetentionKeptAsStored = `kept_activities AS (SELECT * FROM activities),
+		kept_boosts AS (SELECT * FROM boosts),
+		kept_notifications AS (SELECT * FROM notifications)`
+
+	sqlPruneActivities     = `WITH RECURSIVE ` + sqlRetentionLocalThreads + ` DELETE FROM activities WHERE id IN (` + sqlPrunableActivities + `)`
+	sqlPruneRemoteBoosts   = `WITH ` + sqlRetentionKeptAsStored + ` DELETE FROM boosts WHERE id IN (` + sqlPrunableRemoteBoosts + `)`
+	sqlPruneNotifications  = `DELETE FROM notifications WHERE id IN (` + sqlPrunableNotifications + `)`
+	sqlPruneRemoteAccounts = `WITH ` + sqlRetentionKeptAsStored + ` DELETE FROM remote_accounts WHERE id IN (` + sqlPrunableRemoteAccounts + `)`
+
+	// The dry run counts every step's rows in one read, without the write lock
+	sqlCountPrunable = `WITH RECURSIVE ` + sqlRetentionLocalThreads + `,
+		pruned_activities AS (` + sqlPrunableActivities + `),
+		kept_activities AS (SELECT * FROM activities WHERE id NOT IN (SELECT id FROM pruned_activities)),
+		pruned_boosts AS (` + sqlPrunableRemoteBoosts + `),
+		kept_boosts AS (SELECT * FROM boosts WHERE id NOT IN (SELECT id FROM pruned_boosts)),
+		pruned_notifications AS (` + sqlPrunableNotifications + `),
+		kept_notifications AS (SELECT * FROM notifications WHERE id NOT IN (SELECT id FROM pruned_notifications)),
+		pruned_remote_accounts AS (` + sqlPrunableRemoteAccounts + `)
+		SELECT (SELECT COUNT(*) FROM pruned_activities), (SELECT COUNT(*) FROM pruned_boosts),
+			(SELECT COUNT(*) FROM pruned_notifications), (SELECT COUNT(*) FROM pruned_remote_accounts)`
+)
+
+// PruneFederatedContent removes federated content older than cutoff that no local user
+// interacted with, read notifications older than cutoff, and remote accounts last fetched
+// before accountsBefore that nothing refers to anymore. Replies to URIs starting with
+// localNotePrefix count as replies to local notes. With dryRun nothing is deleted: the
+// same predicates are counted in a single read, each over the rows the earlier steps
+// keep, so the returned counts are what a real run would remove.
+func (db *DB) PruneFederatedContent(cutoff, accountsBefore time.Time, localNotePrefix string, dryRun bool) (*domain.RetentionStats, error) {
+	var stats domain.RetentionStats
+	if dryRun {
+		err := db.db.QueryRow(sqlCountPrunable, localNotePrefix+"%", cutoff, cutoff, cutoff, accountsBefore).
+			Scan(&stats.Activities, &stats.Boosts, &stats.Notifications, &stats.RemoteAccounts)
+		if err != nil {
+			return nil, err
+		}
+		return &stats, nil
+	}
+
+	tx, err := db.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	steps := []struct {
+		query string
+		args  []any
+		count *int
+	}{
+		{sqlPruneActivities, []any{localNotePrefix + "%", cutoff}, &stats.Activities},
+		{sqlPruneRemoteBoosts, []any{cutoff}, &stats.Boosts},
+		{sqlPruneNotifications, []any{cutoff}, &stats.Notifications},
+		{sqlPruneRemoteAccounts, []any{accountsBefore}, &stats.RemoteAccounts},
+	}
+	for _, step := range steps {
+		result, err := tx.Exec(step.query, step.args...)
+		if err != nil {
+			return nil, err
+		}
+		affected, _ := result.RowsAffected()
+		*step.count = int(affected)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// IncrementalVacuum returns free pages to the file system. It only has an effect on
+// databases created with auto_vacuum = INCREMENTAL. PostgreSQL leaves this to autovacuum.
+func (db *DB) IncrementalVacuum() error {
+	if db.postgres {
+		return nil
+	}
+	_, err := db.db.Exec("PRAGMA incremental_vacuum")
+	return err
+}
+
 // ============================================================================
 // Notifications
 // ============================================================================
 
 const (
-	sqlInsertNotification = `INSERT INTO notifications(id, account_id, notification_type, actor_id, actor_username, actor_domain, note_id, note_uri, note_preview, read, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	sqlInsertNotification = `INSERT INTO notifications(id, account_id, notification_type, actor_id, actor_username, actor_domain, note_id, note_uri, note_preview, emoji, read, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	sqlSelectNotificationsByAccountId = `SELECT id, account_id, notification_type, actor_id, actor_username, actor_domain, note_id, note_uri, note_preview, read, created_at
+	sqlSelectNotificationsByAccountId = `SELECT id, account_id, notification_type, actor_id, actor_username, actor_domain, note_id, note_uri, note_preview, emoji, read, created_at
 		FROM notifications
-		WHERE account_id = ?
-		ORDER BY created_at DESC
-		LIMIT ?`
+		WHERE account_id = ?`
 
 	sqlSelectUnreadCountByAccountId = `SELECT COUNT(*) FROM notifications WHERE account_id = ? AND read = 0`
 
@@ -4053,13 +5987,13 @@ const (
 
 	sqlMarkAllNotificationsRead = `UPDATE notifications SET read = 1 WHERE account_id = ?`
 
-	sqlDeleteNotification     = `DELETE FROM notifications WHERE id = ?`
+	sqlDeleteNotification     = `DELETE FROM notifications WHERE id = ? AND account_id = ?`
 	sqlDeleteAllNotifications = `DELETE FROM notifications WHERE account_id = ?`
 )
 
 // CreateNotification creates a new notification
 func (db *DB) CreateNotification(notification *domain.Notification) error {
-	return db.wrapTransaction(func(tx *sql.Tx) error {
+	err := db.wrapTransaction(func(tx *sql.Tx) error {
 		readInt := 0
 		if notification.Read {
 			readInt = 1
@@ -4087,6 +6021,11 @@ func (db *DB) CreateNotification(notification *domain.Notification) error {
 			notePreview = nil
 		}
 
+		var emoji interface{}
+		if notification.Emoji != "" {
+			emoji = notification.Emoji
+		}
+
 		_, err := tx.Exec(sqlInsertNotification,
 			notification.Id.String(),
 			notification.AccountId.String(),
@@ -4097,15 +6036,39 @@ func (db *DB) CreateNotification(notification *domain.Notification) error {
 			noteIdStr,
 			noteURI,
 			notePreview,
+			emoji,
 			readInt,
 			notification.CreatedAt.Format(time.RFC3339))
 		return err
 	})
+	if err == nil {
+		events.Publish(events.Event{Kind: events.NotificationCreated, AccountId: notification.AccountId})
+	}
+	return err
+}
+
+// ReadNotificationsByAccountId retrieves a page of notifications for an account, starting after before
+func (db *DB) ReadNotificationsByAccountId(accountId uuid.UUID, before domain.Cursor, limit int) (error, *[]domain.Notification) {
+	pageFilter, pageArgs := db.cursorFilter("created_at", "id", before)
+	args := append(append([]any{accountId.String()}, pageArgs...), limit)
+	return db.readNotifications(sqlSelectNotificationsByAccountId+pageFilter+db.cursorOrder("created_at", "id")+" LIMIT ?", args...)
+}
+
+// ReadNotificationById retrieves one notification of an account
+func (db *DB) ReadNotificationById(notificationId, accountId uuid.UUID) (error, *domain.Notification) {
+	err, notifications := db.readNotifications(sqlSelectNotificationsByAccountId+" AND id = ?", accountId.String(), notificationId.String())
+	if err != nil {
+		return err, nil
+	}
+	if len(*notifications) == 0 {
+		return sql.ErrNoRows, nil
+	}
+	return nil, &(*notifications)[0]
 }
 
-// ReadNotificationsByAccountId retrieves notifications for an account
-func (db *DB) ReadNotificationsByAccountId(accountId uuid.UUID, limit int) (error, *[]domain.Notification) {
-	rows, err := db.db.Query(sqlSelectNotificationsByAccountId, accountId.String(), limit)
+// readNotifications runs a query selecting the columns of sqlSelectNotificationsByAccountId
+func (db *DB) readNotifications(query string, args ...any) (error, *[]domain.Notification) {
+	rows, err := db.db.Query(query, args...)
 	if err != nil {
 		return err, nil
 	}
@@ -4116,11 +6079,11 @@ func (db *DB) ReadNotificationsByAccountId(accountId uuid.UUID, limit int) (erro
 		var n domain.Notification
 		var idStr, accountIdStr, notificationTypeStr, actorIdStr string
 		var actorUsername, actorDomain, createdAtStr string
-		var noteIdStr, noteURI, notePreview sql.NullString
+		var noteIdStr, noteURI, notePreview, emoji sql.NullString
 		var readInt int
 
 		if err := rows.Scan(&idStr, &accountIdStr, &notificationTypeStr, &actorIdStr,
-			&actorUsername, &actorDomain, &noteIdStr, &noteURI, &notePreview,
+			&actorUsername, &actorDomain, &noteIdStr, &noteURI, &notePreview, &emoji,
 			&readInt, &createdAtStr); err != nil {
 			return err, &notifications
 		}
@@ -4138,6 +6101,7 @@ func (db *DB) ReadNotificationsByAccountId(accountId uuid.UUID, limit int) (erro
 		n.ActorDomain = actorDomain
 		n.NoteURI = noteURI.String
 		n.NotePreview = notePreview.String
+		n.Emoji = emoji.String
 		n.Read = readInt == 1
 
 		// Parse timestamp
@@ -4184,11 +6148,18 @@ func (db *DB) MarkAllNotificationsRead(accountId uuid.UUID) error {
 	})
 }
 
-// DeleteNotification deletes a notification
-func (db *DB) DeleteNotification(notificationId uuid.UUID) error {
+// DeleteNotification deletes a notification of accountId. Returns sql.ErrNoRows if
+// the account has no notification with that id.
+func (db *DB) DeleteNotification(notificationId, accountId uuid.UUID) error {
 	return db.wrapTransaction(func(tx *sql.Tx) error {
-		_, err := tx.Exec(sqlDeleteNotification, notificationId.String())
-		return err
+		result, err := tx.Exec(sqlDeleteNotification, notificationId.String(), accountId.String())
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
 	})
 }
 
@@ -4478,7 +6449,8 @@ func (db *DB) ReadBoostersInfoByObjectURI(objectURI string) ([]string, error) {
 
 const (
 	sqlSelectServerMessage = `SELECT id, message, enabled, web_enabled, updated_at FROM server_message WHERE id = 1`
-	sqlInsertServerMessage = `INSERT OR REPLACE INTO server_message(id, message, enabled, web_enabled, updated_at) VALUES (1, ?, ?, ?, ?)`
+	sqlInsertServerMessage = `INSERT INTO server_message(id, message, enabled, web_enabled, updated_at) VALUES (1, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET message = excluded.message, enabled = excluded.enabled, web_enabled = excluded.web_enabled, updated_at = excluded.updated_at`
 	sqlUpdateServerMessage = `UPDATE server_message SET message = ?, enabled = ?, web_enabled = ?, updated_at = ? WHERE id = 1`
 )
 
@@ -4541,10 +6513,11 @@ func (db *DB) UpdateServerMessage(message string, enabled bool, webEnabled bool)
 // Global Timeline (Local + Federated Posts)
 // ============================================================================
 
-// ReadGlobalTimelinePosts returns posts for the global timeline (local notes + remote activities)
-// excluding replies. Uses UNION ALL for efficient SQL-level sorting and pagination.
-func (db *DB) ReadGlobalTimelinePosts(limit, offset int) (error, *[]domain.GlobalTimelinePost) {
+// ReadGlobalTimelinePosts returns a page of the global timeline (local notes + remote activities)
+// excluding replies, starting after before. Uses UNION ALL for efficient SQL-level sorting and pagination.
+func (db *DB) ReadGlobalTimelinePosts(before domain.Cursor, limit int) (error, *[]domain.GlobalTimelinePost) {
 	// Use UNION ALL to combine local, remote, and boosted posts with SQL-level sorting and pagination
+	pageFilter, pageArgs := db.cursorFilter("created_at", "id", before)
 	rows, err := db.db.Query(`
 		SELECT
 			id, username, user_domain, profile_url, object_uri, object_url,
@@ -4663,13 +6636,15 @@ func (db *DB) ReadGlobalTimelinePosts(limit, offset int) (error, *[]domain.Globa
 			WHERE b.remote_account_id IS NOT NULL AND b.remote_account_id != ''
 			AND b.object_uri IS NOT NULL AND b.object_uri != ''
 		) combined
-		ORDER BY created_at DESC
-		LIMIT ? OFFSET ?`, limit, offset)
+		WHERE 1 = 1`+pageFilter+db.cursorOrder("created_at", "id")+`
+		LIMIT ?`, append(pageArgs, limit)...)
 	if err != nil {
 		return err, nil
 	}
 	defer rows.Close()
 
+	localEmojis := db.readLocalEmojiMap()
+
 	var posts []domain.GlobalTimelinePost
 	for rows.Next() {
 		var post domain.GlobalTimelinePost
@@ -4687,8 +6662,9 @@ func (db *DB) ReadGlobalTimelinePosts(limit, offset int) (error, *[]domain.Globa
 		post.CreatedAt, _ = parseTimestamp(createdAtStr)
 
 		if post.IsRemote {
-			// For remote posts, extract content from raw JSON
+			// For remote posts, extract content and custom emoji from raw JSON
 			post.Message = extractContentFromJSON(message)
+			post.Emojis = util.ExtractEmojiTagsFromJSON(message)
 			// Format username as @user@domain for display
 			post.Username = fmt.Sprintf("@%s@%s", post.Username, post.UserDomain)
 			// Convert ActivityPub object URI to HTML URL for display
@@ -4702,6 +6678,7 @@ func (db *DB) ReadGlobalTimelinePosts(limit, offset int) (error, *[]domain.Globa
 			}
 		} else {
 			post.Message = message
+			post.Emojis = emojisForLocalMessage(message, localEmojis)
 		}
 		posts = append(posts, post)
 	}
@@ -4727,6 +6704,12 @@ func (db *DB) ReadGlobalTimelinePosts(limit, offset int) (error, *[]domain.Globa
 		}
 	}
 
+	// Attach emoji reaction counts
+	for i := range dedupedPosts {
+		noteId, _ := uuid.Parse(dedupedPosts[i].NoteId)
+		dedupedPosts[i].Reactions = db.readReactionCountsForPost(!dedupedPosts[i].IsRemote, noteId, dedupedPosts[i].ObjectURI)
+	}
+
 	return nil, &dedupedPosts
 }
 
@@ -4753,14 +6736,15 @@ func (db *DB) CountGlobalTimelinePosts() (int, error) {
 // ============================================================================
 
 const (
-	sqlCreateBan      = `INSERT INTO bans(id, username, ip_address, public_key_hash, reason, banned_at) VALUES (?, ?, ?, ?, ?, ?)`
-	sqlReadAllBans    = `SELECT id, username, ip_address, public_key_hash, reason, banned_at FROM bans ORDER BY banned_at DESC`
-	sqlDeleteBan      = `DELETE FROM bans WHERE id = ?`
-	// IP bans expire after 60 days - only check recent bans
-	sqlCheckIPBanned  = `SELECT COUNT(*) FROM bans WHERE ip_address = ? AND ip_address != '' AND banned_at >= datetime('now', '-60 days')`
+	sqlCreateBan   = `INSERT INTO bans(id, username, ip_address, public_key_hash, reason, banned_at) VALUES (?, ?, ?, ?, ?, ?)`
+	sqlReadAllBans = `SELECT id, username, ip_address, public_key_hash, reason, banned_at FROM bans ORDER BY banned_at DESC`
+	sqlDeleteBan   = `DELETE FROM bans WHERE id = ?`
+	// IP bans expire after ipBanDays days - only check recent bans
+	ipBanDays         = 60
+	sqlCheckIPBanned  = `SELECT COUNT(*) FROM bans WHERE ip_address = ? AND ip_address != '' AND banned_at >= ?`
 	sqlCheckKeyBanned = `SELECT COUNT(*) FROM bans WHERE public_key_hash = ?`
 	// Cleanup query to clear expired IP addresses (older than 60 days)
-	sqlClearExpiredIPBans = `UPDATE bans SET ip_address = '' WHERE ip_address != '' AND banned_at < datetime('now', '-60 days')`
+	sqlClearExpiredIPBans = `UPDATE bans SET ip_address = '' WHERE ip_address != '' AND banned_at < ?`
 )
 
 // CreateBan adds a new ban record with IP address and public key hash
@@ -4802,7 +6786,7 @@ func (db *DB) DeleteBan(id string) error {
 // IsIPBanned checks if an IP address is banned
 func (db *DB) IsIPBanned(ipAddress string) bool {
 	var count int
-	err := db.db.QueryRow(sqlCheckIPBanned, ipAddress).Scan(&count)
+	err := db.db.QueryRow(sqlCheckIPBanned, ipAddress, db.daysAgo(ipBanDays)).Scan(&count)
 	if err != nil {
 		log.Printf("Error checking IP ban: %v", err)
 		return false
@@ -4848,7 +6832,7 @@ func (db *DB) UpdateAccountLastIPByPkHash(pkHash string, ipAddress string) error
 // CleanupExpiredIPBans clears IP addresses from bans older than 60 days
 // The ban record is kept (for public key blocking) but the IP is cleared
 func (db *DB) CleanupExpiredIPBans() (int64, error) {
-	result, err := db.db.Exec(sqlClearExpiredIPBans)
+	result, err := db.db.Exec(sqlClearExpiredIPBans, db.daysAgo(ipBanDays))
 	if err != nil {
 		log.Printf("Failed to cleanup expired IP bans: %v", err)
 		return 0, err
@@ -4859,3 +6843,110 @@ func (db *DB) CleanupExpiredIPBans() (int64, error) {
 	}
 	return affected, nil
 }
+
+// ============================================================================
+// Custom Emoji
+// ============================================================================
+
+const (
+	sqlUpsertCustomEmoji = `INSERT INTO custom_emojis(id, shortcode, image_url, media_type, created_by, created_at) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(shortcode) DO UPDATE SET image_url = excluded.image_url, media_type = excluded.media_type, created_by = excluded.created_by, created_at = excluded.created_at`
+	sqlSelectAllCustomEmojis        = `SELECT id, shortcode, image_url, media_type, COALESCE(created_by, ''), created_at FROM custom_emojis ORDER BY shortcode ASC`
+	sqlSelectCustomEmojiByShortcode = `SELECT id, shortcode, image_url, media_type, COALESCE(created_by, ''), created_at FROM custom_emojis WHERE shortcode = ?`
+	sqlDeleteCustomEmoji            = `DELETE FROM custom_emojis WHERE id = ?`
+)
+
+// SaveCustomEmoji creates a custom emoji or replaces the image of an existing shortcode
+func (db *DB) SaveCustomEmoji(emoji *domain.CustomEmoji) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlUpsertCustomEmoji,
+			emoji.Id.String(),
+			emoji.Shortcode,
+			emoji.ImageURL,
+			emoji.MediaType,
+			emoji.CreatedBy.String(),
+			emoji.CreatedAt.Format(time.RFC3339))
+		return err
+	})
+}
+
+// ReadAllCustomEmojis returns all custom emoji ordered by shortcode
+func (db *DB) ReadAllCustomEmojis() (error, *[]domain.CustomEmoji) {
+	rows, err := db.db.Query(sqlSelectAllCustomEmojis)
+	if err != nil {
+		return err, nil
+	}
+	defer rows.Close()
+
+	var emojis []domain.CustomEmoji
+	for rows.Next() {
+		var emoji domain.CustomEmoji
+		var idStr, createdByStr, createdAtStr string
+		if err := rows.Scan(&idStr, &emoji.Shortcode, &emoji.ImageURL, &emoji.MediaType, &createdByStr, &createdAtStr); err != nil {
+			return err, &emojis
+		}
+		emoji.Id, _ = uuid.Parse(idStr)
+		emoji.CreatedBy, _ = uuid.Parse(createdByStr)
+		emoji.CreatedAt, _ = parseTimestamp(createdAtStr)
+		emojis = append(emojis, emoji)
+	}
+	if err = rows.Err(); err != nil {
+		return err, &emojis
+	}
+	return nil, &emojis
+}
+
+// ReadCustomEmojiByShortcode returns a custom emoji by shortcode, or nil if it doesn't exist
+func (db *DB) ReadCustomEmojiByShortcode(shortcode string) (error, *domain.CustomEmoji) {
+	var emoji domain.CustomEmoji
+	var idStr, createdByStr, createdAtStr string
+	err := db.db.QueryRow(sqlSelectCustomEmojiByShortcode, shortcode).Scan(&idStr, &emoji.Shortcode, &emoji.ImageURL, &emoji.MediaType, &createdByStr, &createdAtStr)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return err, nil
+	}
+	emoji.Id, _ = uuid.Parse(idStr)
+	emoji.CreatedBy, _ = uuid.Parse(createdByStr)
+	emoji.CreatedAt, _ = parseTimestamp(createdAtStr)
+	return nil, &emoji
+}
+
+// DeleteCustomEmoji removes a custom emoji
+func (db *DB) DeleteCustomEmoji(id uuid.UUID) error {
+	return db.wrapTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqlDeleteCustomEmoji, id.String())
+		return err
+	})
+}
+
+// readLocalEmojiMap returns the shortcode -> image URL map of all local custom emoji
+func (db *DB) readLocalEmojiMap() map[string]string {
+	err, emojis := db.ReadAllCustomEmojis()
+	if err != nil {
+		log.Printf("Failed to read custom emoji: %v", err)
+		return nil
+	}
+	if emojis == nil || len(*emojis) == 0 {
+		return nil
+	}
+	return domain.EmojiMap(*emojis)
+}
+
+// emojisForLocalMessage returns the subset of local custom emoji used in a message
+func emojisForLocalMessage(message string, localEmojis map[string]string) map[string]string {
+	if len(localEmojis) == 0 {
+		return nil
+	}
+	var used map[string]string
+	for _, shortcode := range util.ParseEmojiShortcodes(message) {
+		if imageURL, ok := localEmojis[shortcode]; ok {
+			if used == nil {
+				used = make(map[string]string)
+			}
+			used[shortcode] = imageURL
+		}
+	}
+	return used
+}
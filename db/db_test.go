@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
 	"github.com/google/uuid"
 	_ "modernc.org/sqlite"
 )
@@ -42,6 +43,7 @@ func setupTestDB(t *testing.T) *DB {
 	db.db.Exec(`ALTER TABLE notes ADD COLUMN reply_count INTEGER DEFAULT 0`)
 	db.db.Exec(`ALTER TABLE notes ADD COLUMN like_count INTEGER DEFAULT 0`)
 	db.db.Exec(`ALTER TABLE notes ADD COLUMN boost_count INTEGER DEFAULT 0`)
+	db.db.Exec(`ALTER TABLE notes ADD COLUMN title TEXT`)
 
 	// Add ActivityPub profile fields to accounts table
 	db.db.Exec(`ALTER TABLE accounts ADD COLUMN display_name varchar(255)`)
@@ -67,6 +69,9 @@ func setupTestDB(t *testing.T) *DB {
 		public_key_pem text,
 		avatar_url varchar(500),
 		last_fetched_at timestamp default current_timestamp,
+		fetch_failures int default 0,
+		last_failure_at timestamp,
+		gone_at timestamp,
 		UNIQUE(username, domain)
 	)`)
 
@@ -94,6 +99,7 @@ func setupTestDB(t *testing.T) *DB {
 		created_at timestamp default current_timestamp,
 		local int default 0,
 		from_relay int default 0,
+		backfilled int default 0,
 		reply_count INTEGER DEFAULT 0,
 		like_count INTEGER DEFAULT 0,
 		boost_count INTEGER DEFAULT 0
@@ -157,6 +163,26 @@ func setupTestDB(t *testing.T) *DB {
 		accepted_at TIMESTAMP
 	)`)
 
+	// Create reactions table
+	db.db.Exec(sqlCreateReactionsTable)
+	db.db.Exec(sqlCreateReactionsIndices)
+
+	// Create custom emoji table
+	db.db.Exec(sqlCreateCustomEmojisTable)
+
+	// Create followed hashtags table
+	db.db.Exec(sqlCreateFollowedHashtagsTable)
+	db.db.Exec(sqlCreateListsTable)
+	db.db.Exec(sqlCreateListMembersTable)
+	db.db.Exec(sqlCreateFiltersTable)
+	db.db.Exec(sqlCreateInboxQueueTable)
+	db.db.Exec(sqlCreateOAuthAppsTable)
+	db.db.Exec(sqlCreateOAuthAuthorizationsTable)
+	db.db.Exec(sqlCreateOAuthTokensTable)
+	db.db.Exec(sqlCreateNotificationsTable)
+	db.db.Exec(sqlCreateScheduledNotesTable)
+	db.db.Exec(sqlCreateDraftsTable)
+
 	return db
 }
 
@@ -310,6 +336,130 @@ func TestCreateNote(t *testing.T) {
 	}
 }
 
+func TestCreateNotePublishesEvent(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	userId := uuid.New()
+	createTestAccount(t, db, userId, "testuser", "pubkey", "webpub", "webpriv")
+
+	sub := events.Subscribe()
+	defer sub.Close()
+
+	noteId, err := db.CreateNote(userId, "Live")
+	if err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+	notification := &domain.Notification{Id: uuid.New(), AccountId: userId, NotificationType: domain.NotificationLike, CreatedAt: time.Now()}
+	if err := db.CreateNotification(notification); err != nil {
+		t.Fatalf("CreateNotification failed: %v", err)
+	}
+
+	for _, want := range []events.Event{
+		{Kind: events.NoteCreated, AccountId: userId, NoteId: noteId},
+		{Kind: events.NotificationCreated, AccountId: userId},
+	} {
+		select {
+		case got := <-sub.Events():
+			if got != want {
+				t.Errorf("Expected %+v, got %+v", want, got)
+			}
+		default:
+			t.Fatalf("Expected a %v event", want.Kind)
+		}
+	}
+}
+
+func TestDeleteNotification_OnlyOwner(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+	db.db.SetMaxOpenConns(1)
+
+	aliceId := uuid.New()
+	createTestAccount(t, db, aliceId, "alice", "pubkey1", "webpub", "webpriv")
+	bobId := uuid.New()
+	createTestAccount(t, db, bobId, "bob", "pubkey2", "webpub", "webpriv")
+
+	notification := &domain.Notification{Id: uuid.New(), AccountId: aliceId, NotificationType: domain.NotificationLike, CreatedAt: time.Now()}
+	if err := db.CreateNotification(notification); err != nil {
+		t.Fatalf("CreateNotification failed: %v", err)
+	}
+
+	if err, n := db.ReadNotificationById(notification.Id, aliceId); err != nil || n.Id != notification.Id {
+		t.Errorf("Expected alice to read her notification, got %v", err)
+	}
+	if err, _ := db.ReadNotificationById(notification.Id, bobId); err != sql.ErrNoRows {
+		t.Errorf("Expected another account's notification to be hidden, got %v", err)
+	}
+	if err := db.DeleteNotification(notification.Id, bobId); err != sql.ErrNoRows {
+		t.Errorf("Expected another account's notification to be refused, got %v", err)
+	}
+	if err := db.DeleteNotification(notification.Id, aliceId); err != nil {
+		t.Fatalf("DeleteNotification failed: %v", err)
+	}
+	if err := db.DeleteNotification(notification.Id, aliceId); err != sql.ErrNoRows {
+		t.Errorf("Expected a deleted notification to be gone, got %v", err)
+	}
+}
+
+func TestCreateArticle(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	userId := uuid.New()
+	createTestAccount(t, db, userId, "writer", "pubkey", "webpub", "webpriv")
+
+	body := "## Intro\n\n" + strings.Repeat("A long paragraph. ", 200)
+	articleId, err := db.CreateArticle(userId, "On Stegosaurs", body)
+	if err != nil {
+		t.Fatalf("CreateArticle failed: %v", err)
+	}
+	noteId, err := db.CreateNote(userId, "just a note")
+	if err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+
+	err, article := db.ReadNoteId(articleId)
+	if err != nil {
+		t.Fatalf("ReadNoteId failed: %v", err)
+	}
+	if article.Title != "On Stegosaurs" || article.Message != body || !article.IsArticle() {
+		t.Errorf("Expected article with title and full body, got title %q (%d chars)", article.Title, len(article.Message))
+	}
+
+	err, note := db.ReadNoteId(noteId)
+	if err != nil {
+		t.Fatalf("ReadNoteId failed: %v", err)
+	}
+	if note.IsArticle() {
+		t.Errorf("Expected short note not to be an article, got title %q", note.Title)
+	}
+
+	err, notes := db.ReadAllNotes()
+	if err != nil {
+		t.Fatalf("ReadAllNotes failed: %v", err)
+	}
+	titles := 0
+	for _, n := range *notes {
+		if n.Title == "On Stegosaurs" {
+			titles++
+		}
+	}
+	if titles != 1 {
+		t.Errorf("Expected ReadAllNotes to include the article title once, got %d", titles)
+	}
+	if err := db.UpdateArticle(articleId, "On Stegosaurs, Revised", "Shorter now."); err != nil {
+		t.Fatalf("UpdateArticle failed: %v", err)
+	}
+	err, article = db.ReadNoteId(articleId)
+	if err != nil {
+		t.Fatalf("ReadNoteId failed: %v", err)
+	}
+	if article.Title != "On Stegosaurs, Revised" || article.Message != "Shorter now." || article.EditedAt == nil {
+		t.Errorf("Expected updated article, got title %q, message %q, edited %v", article.Title, article.Message, article.EditedAt)
+	}
+}
+
 func TestReadNoteIdNotFound(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.db.Close()
@@ -640,6 +790,85 @@ func TestCreateRemoteAccount(t *testing.T) {
 	}
 }
 
+func TestRemoteAccountRefreshState(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	newAcc := func(name string, fetchedAt time.Time) *domain.RemoteAccount {
+		acc := &domain.RemoteAccount{
+			Id:            uuid.New(),
+			Username:      name,
+			Domain:        "example.com",
+			ActorURI:      "https://example.com/users/" + name,
+			InboxURI:      "https://example.com/users/" + name + "/inbox",
+			PublicKeyPem:  "-----BEGIN PUBLIC KEY-----",
+			LastFetchedAt: fetchedAt,
+		}
+		if err := db.CreateRemoteAccount(acc); err != nil {
+			t.Fatalf("CreateRemoteAccount failed: %v", err)
+		}
+		return acc
+	}
+	stale := newAcc("stale", time.Now().Add(-100*time.Hour))
+	newAcc("fresh", time.Now())
+
+	cutoff := time.Now().Add(-72 * time.Hour)
+	err, accounts := db.ReadStaleRemoteAccounts(cutoff, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("ReadStaleRemoteAccounts failed: %v", err)
+	}
+	if len(*accounts) != 1 || (*accounts)[0].Id != stale.Id {
+		t.Fatalf("Expected only the stale account, got %+v", *accounts)
+	}
+
+	// Not-found failures count, other failures don't
+	if n, err := db.RecordRemoteAccountFetchFailure(stale.Id, true); err != nil || n != 1 {
+		t.Errorf("Expected 1 failure, got %d (err=%v)", n, err)
+	}
+	if n, err := db.RecordRemoteAccountFetchFailure(stale.Id, false); err != nil || n != 1 {
+		t.Errorf("Expected failure count to stay at 1, got %d (err=%v)", n, err)
+	}
+
+	// A recently failed account waits until the retry cutoff
+	_, accounts = db.ReadStaleRemoteAccounts(cutoff, time.Now().Add(-24*time.Hour), 10)
+	if len(*accounts) != 0 {
+		t.Errorf("Expected failed account to wait for retry, got %d", len(*accounts))
+	}
+
+	err, stats := db.ReadRemoteAccountStats(cutoff)
+	if err != nil {
+		t.Fatalf("ReadRemoteAccountStats failed: %v", err)
+	}
+	if stats.Total != 2 || stats.Stale != 1 || stats.Failing != 1 || stats.Gone != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	if err := db.MarkRemoteAccountGone(stale.Id); err != nil {
+		t.Fatalf("MarkRemoteAccountGone failed: %v", err)
+	}
+	_, stats = db.ReadRemoteAccountStats(cutoff)
+	if stats.Stale != 0 || stats.Failing != 0 || stats.Gone != 1 {
+		t.Errorf("Unexpected stats after marking gone: %+v", stats)
+	}
+	_, accounts = db.ReadStaleRemoteAccounts(cutoff, time.Now(), 10)
+	if len(*accounts) != 0 {
+		t.Errorf("Expected gone account to be skipped, got %d", len(*accounts))
+	}
+
+	// A successful refetch revives the account
+	stale.LastFetchedAt = time.Now()
+	if err := db.UpdateRemoteAccount(stale); err != nil {
+		t.Fatalf("UpdateRemoteAccount failed: %v", err)
+	}
+	_, stats = db.ReadRemoteAccountStats(cutoff)
+	if stats.Gone != 0 || stats.Failing != 0 {
+		t.Errorf("Expected refresh state to be cleared, got %+v", stats)
+	}
+	if n, _ := db.RecordRemoteAccountFetchFailure(stale.Id, true); n != 1 {
+		t.Errorf("Expected failure count to restart at 1, got %d", n)
+	}
+}
+
 func TestCreateLocalFollow(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.db.Close()
@@ -1107,7 +1336,7 @@ func TestReadPublicNotesByUsername(t *testing.T) {
 	}
 
 	// Test: Should return only public notes
-	err, notes := db.ReadPublicNotesByUsername("testuser", 10, 0)
+	err, notes := db.ReadPublicNotesByUsername("testuser", domain.Cursor{}, 10)
 	if err != nil {
 		t.Fatalf("ReadPublicNotesByUsername failed: %v", err)
 	}
@@ -1124,7 +1353,7 @@ func TestReadPublicNotesByUsername(t *testing.T) {
 	}
 
 	// Test: Pagination with limit
-	err, notesPage1 := db.ReadPublicNotesByUsername("testuser", 1, 0)
+	err, notesPage1 := db.ReadPublicNotesByUsername("testuser", domain.Cursor{}, 1)
 	if err != nil {
 		t.Fatalf("ReadPublicNotesByUsername with limit failed: %v", err)
 	}
@@ -1132,13 +1361,13 @@ func TestReadPublicNotesByUsername(t *testing.T) {
 		t.Errorf("Expected 1 note with limit=1, got %d", len(*notesPage1))
 	}
 
-	// Test: Pagination with offset
-	err, notesPage2 := db.ReadPublicNotesByUsername("testuser", 1, 1)
+	// Test: Pagination with a cursor
+	err, notesPage2 := db.ReadPublicNotesByUsername("testuser", (*notesPage1)[0].Cursor(), 1)
 	if err != nil {
-		t.Fatalf("ReadPublicNotesByUsername with offset failed: %v", err)
+		t.Fatalf("ReadPublicNotesByUsername with cursor failed: %v", err)
 	}
 	if len(*notesPage2) != 1 {
-		t.Errorf("Expected 1 note with offset=1, got %d", len(*notesPage2))
+		t.Errorf("Expected 1 note after the cursor, got %d", len(*notesPage2))
 	}
 
 	// Verify pages return different notes
@@ -1149,7 +1378,7 @@ func TestReadPublicNotesByUsername(t *testing.T) {
 	}
 
 	// Test: Non-existent user
-	err, notesNone := db.ReadPublicNotesByUsername("nonexistent", 10, 0)
+	err, notesNone := db.ReadPublicNotesByUsername("nonexistent", domain.Cursor{}, 10)
 	if err != nil {
 		t.Fatalf("ReadPublicNotesByUsername for non-existent user should not error: %v", err)
 	}
@@ -3250,7 +3479,7 @@ func TestReadHomeTimelinePosts_RemotePostsIncludeLikeAndBoostCounts(t *testing.T
 	db.db.Exec("UPDATE activities SET like_count = 15, boost_count = 8 WHERE object_uri = ?", objectURI)
 
 	// Read home timeline
-	err, posts := db.ReadHomeTimelinePosts(localAccountId, 10)
+	err, posts := db.ReadHomeTimelinePosts(localAccountId, domain.Cursor{}, 10)
 	if err != nil {
 		t.Fatalf("ReadHomeTimelinePosts failed: %v", err)
 	}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+func TestDraftOperations(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.db.Close()
+	testDB.db.SetMaxOpenConns(1)
+
+	accountId := uuid.New()
+	otherId := uuid.New()
+	createTestAccount(t, testDB, accountId, "alice", "pubkey1", "webpub", "webpriv")
+
+	now := time.Now().Truncate(time.Second)
+	note := &domain.Draft{Id: uuid.New(), AccountId: accountId, Message: "half a thought", CreatedAt: now, UpdatedAt: now}
+	reply := &domain.Draft{
+		Id: uuid.New(), AccountId: accountId, Message: "@bob agreed",
+		InReplyToURI: "https://remote.example/notes/1", ReplyToAuthor: "@bob@remote.example", ReplyToPreview: "thoughts?",
+		CreatedAt: now, UpdatedAt: now.Add(time.Second),
+	}
+	for _, d := range []*domain.Draft{note, reply} {
+		if err := testDB.SaveDraft(d); err != nil {
+			t.Fatalf("SaveDraft failed: %v", err)
+		}
+	}
+
+	// Autosave updates the draft in place
+	note.Message = "a whole thought"
+	note.UpdatedAt = now.Add(2 * time.Second)
+	if err := testDB.SaveDraft(note); err != nil {
+		t.Fatalf("SaveDraft (update) failed: %v", err)
+	}
+
+	// Another account cannot overwrite the draft
+	if err := testDB.SaveDraft(&domain.Draft{Id: note.Id, AccountId: otherId, Message: "hijacked", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("SaveDraft by another account failed: %v", err)
+	}
+
+	err, drafts := testDB.ReadDraftsByAccountId(accountId)
+	if err != nil {
+		t.Fatalf("ReadDraftsByAccountId failed: %v", err)
+	}
+	if len(*drafts) != 2 || (*drafts)[0].Id != note.Id || (*drafts)[0].Message != "a whole thought" {
+		t.Fatalf("Expected updated draft first, got %+v", *drafts)
+	}
+	if got := (*drafts)[1]; got.InReplyToURI != reply.InReplyToURI || got.ReplyToAuthor != reply.ReplyToAuthor || got.ReplyToPreview != "thoughts?" {
+		t.Errorf("Expected reply context to be kept, got %+v", got)
+	}
+	if !(*drafts)[0].UpdatedAt.Equal(note.UpdatedAt) {
+		t.Errorf("Expected UpdatedAt %v, got %v", note.UpdatedAt, (*drafts)[0].UpdatedAt)
+	}
+
+	noteId, err := testDB.PublishDraft(reply)
+	if err != nil {
+		t.Fatalf("PublishDraft failed: %v", err)
+	}
+	if err, published := testDB.ReadNoteIdWithReplyInfo(noteId); err != nil || published.Message != "@bob agreed" || published.InReplyToURI != reply.InReplyToURI {
+		t.Errorf("Expected published reply, got %+v (err=%v)", published, err)
+	}
+	if _, err := testDB.PublishDraft(reply); err == nil {
+		t.Error("Expected a published draft not to be published twice")
+	}
+
+	if err := testDB.DeleteDraft(note.Id, otherId); err != nil {
+		t.Fatalf("DeleteDraft failed: %v", err)
+	}
+	_, drafts = testDB.ReadDraftsByAccountId(accountId)
+	if len(*drafts) != 1 {
+		t.Fatalf("Expected discard by another account to be ignored, got %d drafts", len(*drafts))
+	}
+	if err := testDB.DeleteDraft(note.Id, accountId); err != nil {
+		t.Fatalf("DeleteDraft failed: %v", err)
+	}
+	_, drafts = testDB.ReadDraftsByAccountId(accountId)
+	if len(*drafts) != 0 {
+		t.Errorf("Expected no drafts left, got %d", len(*drafts))
+	}
+}
+
+func TestPublishArticleDraft(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.db.Close()
+	testDB.db.SetMaxOpenConns(1)
+
+	accountId := uuid.New()
+	createTestAccount(t, testDB, accountId, "alice", "pubkey1", "webpub", "webpriv")
+
+	now := time.Now().Truncate(time.Second)
+	draft := &domain.Draft{Id: uuid.New(), AccountId: accountId, Title: "Field Notes", Message: "## Day one\n\nSun.", CreatedAt: now, UpdatedAt: now}
+	if err := testDB.SaveDraft(draft); err != nil {
+		t.Fatalf("SaveDraft failed: %v", err)
+	}
+
+	_, drafts := testDB.ReadDraftsByAccountId(accountId)
+	if len(*drafts) != 1 || (*drafts)[0].Title != "Field Notes" {
+		t.Fatalf("Expected article draft with title, got %+v", *drafts)
+	}
+
+	noteId, err := testDB.PublishDraft(&(*drafts)[0])
+	if err != nil {
+		t.Fatalf("PublishDraft failed: %v", err)
+	}
+	err, published := testDB.ReadNoteIdWithReplyInfo(noteId)
+	if err != nil || !published.IsArticle() || published.Title != "Field Notes" || published.Message != draft.Message {
+		t.Errorf("Expected published article, got %+v (err=%v)", published, err)
+	}
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+func TestCustomEmojiOperations(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.db.Close()
+	testDB.db.SetMaxOpenConns(1)
+
+	adminId := uuid.New()
+
+	t.Run("SaveCustomEmoji and ReadCustomEmojiByShortcode", func(t *testing.T) {
+		emoji := &domain.CustomEmoji{
+			Id:        uuid.New(),
+			Shortcode: "blobcat",
+			ImageURL:  "/emojis/blobcat.png",
+			MediaType: "image/png",
+			CreatedBy: adminId,
+			CreatedAt: time.Now(),
+		}
+		if err := testDB.SaveCustomEmoji(emoji); err != nil {
+			t.Fatalf("Failed to save custom emoji: %v", err)
+		}
+
+		err, found := testDB.ReadCustomEmojiByShortcode("blobcat")
+		if err != nil {
+			t.Fatalf("Failed to read custom emoji: %v", err)
+		}
+		if found == nil {
+			t.Fatal("Expected custom emoji to be found")
+		}
+		if found.ImageURL != "/emojis/blobcat.png" {
+			t.Errorf("Expected image URL /emojis/blobcat.png, got %s", found.ImageURL)
+		}
+		if found.CreatedBy != adminId {
+			t.Errorf("Expected creator %s, got %s", adminId, found.CreatedBy)
+		}
+	})
+
+	t.Run("SaveCustomEmoji replaces existing shortcode", func(t *testing.T) {
+		emoji := &domain.CustomEmoji{
+			Id:        uuid.New(),
+			Shortcode: "blobcat",
+			ImageURL:  "/emojis/blobcat.png?v=2",
+			MediaType: "image/png",
+			CreatedBy: adminId,
+			CreatedAt: time.Now(),
+		}
+		if err := testDB.SaveCustomEmoji(emoji); err != nil {
+			t.Fatalf("Failed to replace custom emoji: %v", err)
+		}
+
+		err, emojis := testDB.ReadAllCustomEmojis()
+		if err != nil {
+			t.Fatalf("Failed to read custom emojis: %v", err)
+		}
+		if len(*emojis) != 1 {
+			t.Fatalf("Expected 1 custom emoji, got %d", len(*emojis))
+		}
+		if (*emojis)[0].ImageURL != "/emojis/blobcat.png?v=2" {
+			t.Errorf("Expected replaced image URL, got %s", (*emojis)[0].ImageURL)
+		}
+	})
+
+	t.Run("ReadCustomEmojiByShortcode returns nil when missing", func(t *testing.T) {
+		err, found := testDB.ReadCustomEmojiByShortcode("missing")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if found != nil {
+			t.Error("Expected nil custom emoji")
+		}
+	})
+
+	t.Run("DeleteCustomEmoji", func(t *testing.T) {
+		err, found := testDB.ReadCustomEmojiByShortcode("blobcat")
+		if err != nil || found == nil {
+			t.Fatalf("Expected custom emoji before delete, err=%v", err)
+		}
+		if err := testDB.DeleteCustomEmoji(found.Id); err != nil {
+			t.Fatalf("Failed to delete custom emoji: %v", err)
+		}
+
+		err, emojis := testDB.ReadAllCustomEmojis()
+		if err != nil {
+			t.Fatalf("Failed to read custom emojis: %v", err)
+		}
+		if len(*emojis) != 0 {
+			t.Errorf("Expected no custom emoji after delete, got %d", len(*emojis))
+		}
+	})
+}
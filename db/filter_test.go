@@ -0,0 +1,70 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+func TestFilterOperations(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.db.Close()
+	testDB.db.SetMaxOpenConns(1)
+
+	accountId := uuid.New()
+	createTestAccount(t, testDB, accountId, "alice", "pubkey1", "webpub", "webpriv")
+
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+	filters := []*domain.Filter{
+		{Id: uuid.New(), AccountId: accountId, Phrase: "spoilers", WholeWord: true,
+			Contexts: []string{domain.FilterContextHome, domain.FilterContextThread}, Action: domain.FilterActionWarn,
+			ExpiresAt: &expires, CreatedAt: time.Now().Add(-time.Minute)},
+		{Id: uuid.New(), AccountId: accountId, Phrase: "crypto",
+			Contexts: []string{domain.FilterContextGlobal}, Action: domain.FilterActionHide, CreatedAt: time.Now()},
+	}
+	for _, f := range filters {
+		if err := testDB.CreateFilter(f); err != nil {
+			t.Fatalf("CreateFilter failed: %v", err)
+		}
+	}
+
+	err, stored := testDB.ReadFiltersByAccountId(accountId)
+	if err != nil {
+		t.Fatalf("ReadFiltersByAccountId failed: %v", err)
+	}
+	if len(*stored) != 2 {
+		t.Fatalf("Expected 2 filters, got %d", len(*stored))
+	}
+	first := (*stored)[0]
+	if first.Phrase != "spoilers" || !first.WholeWord || first.Action != domain.FilterActionWarn {
+		t.Errorf("Unexpected first filter: %+v", first)
+	}
+	if !first.HasContext(domain.FilterContextThread) || first.HasContext(domain.FilterContextGlobal) {
+		t.Errorf("Contexts not round-tripped: %v", first.Contexts)
+	}
+	if first.ExpiresAt == nil || !first.ExpiresAt.Equal(expires) {
+		t.Errorf("Expected expiry %v, got %v", expires, first.ExpiresAt)
+	}
+	if (*stored)[1].ExpiresAt != nil {
+		t.Errorf("Expected no expiry for second filter, got %v", (*stored)[1].ExpiresAt)
+	}
+
+	if err := testDB.DeleteFilter(filters[0].Id); err != nil {
+		t.Fatalf("DeleteFilter failed: %v", err)
+	}
+	err, stored = testDB.ReadFiltersByAccountId(accountId)
+	if err != nil || len(*stored) != 1 || (*stored)[0].Phrase != "crypto" {
+		t.Errorf("Expected only the crypto filter after delete, got %+v (err=%v)", stored, err)
+	}
+
+	// Filters are removed with the account
+	if err := testDB.DeleteAccount(accountId); err != nil {
+		t.Fatalf("DeleteAccount failed: %v", err)
+	}
+	err, stored = testDB.ReadFiltersByAccountId(accountId)
+	if err != nil || len(*stored) != 0 {
+		t.Errorf("Expected filters to be deleted with the account, got %d", len(*stored))
+	}
+}
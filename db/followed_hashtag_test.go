@@ -0,0 +1,192 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+func TestFollowedHashtagOperations(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.db.Close()
+	testDB.db.SetMaxOpenConns(1)
+
+	accountId := uuid.New()
+	createTestAccount(t, testDB, accountId, "alice", "pubkey", "webpub", "webpriv")
+
+	if err := testDB.FollowHashtag(accountId, "Golang"); err != nil {
+		t.Fatalf("FollowHashtag failed: %v", err)
+	}
+	// Following twice is a no-op
+	if err := testDB.FollowHashtag(accountId, "golang"); err != nil {
+		t.Fatalf("FollowHashtag (duplicate) failed: %v", err)
+	}
+	if err := testDB.FollowHashtag(accountId, "fediverse"); err != nil {
+		t.Fatalf("FollowHashtag failed: %v", err)
+	}
+
+	err, tags := testDB.ReadFollowedHashtags(accountId)
+	if err != nil {
+		t.Fatalf("ReadFollowedHashtags failed: %v", err)
+	}
+	if len(tags) != 2 || tags[0] != "fediverse" || tags[1] != "golang" {
+		t.Errorf("Expected [fediverse golang], got %v", tags)
+	}
+
+	following, err := testDB.IsFollowingHashtag(accountId, "GOLANG")
+	if err != nil || !following {
+		t.Errorf("Expected to follow golang, got %v (err=%v)", following, err)
+	}
+
+	count, err := testDB.CountHashtagFollowers("golang")
+	if err != nil || count != 1 {
+		t.Errorf("Expected 1 follower, got %d (err=%v)", count, err)
+	}
+
+	if err := testDB.UnfollowHashtag(accountId, "golang"); err != nil {
+		t.Fatalf("UnfollowHashtag failed: %v", err)
+	}
+	following, _ = testDB.IsFollowingHashtag(accountId, "golang")
+	if following {
+		t.Error("Expected golang to be unfollowed")
+	}
+}
+
+func TestReadHomeTimelinePosts_FollowedHashtags(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	viewerId := uuid.New()
+	createTestAccount(t, db, viewerId, "viewer", "pubkey1", "webpub", "webpriv")
+	authorId := uuid.New()
+	createTestAccount(t, db, authorId, "author", "pubkey2", "webpub", "webpriv")
+
+	// Local note from an account the viewer does not follow
+	noteId, err := db.CreateNote(authorId, "Hello #golang")
+	if err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+	hashtagId, err := db.CreateOrUpdateHashtag("golang")
+	if err != nil {
+		t.Fatalf("CreateOrUpdateHashtag failed: %v", err)
+	}
+	if err := db.LinkNoteHashtags(noteId, []int64{hashtagId}); err != nil {
+		t.Fatalf("LinkNoteHashtags failed: %v", err)
+	}
+
+	// Remote post from an unknown actor carrying the tag
+	remoteURI := "https://remote.example/notes/1"
+	if err := db.CreateActivity(&domain.Activity{
+		Id:           uuid.New(),
+		ActivityURI:  "https://remote.example/activities/1",
+		ActivityType: "Create",
+		ActorURI:     "https://remote.example/users/bob",
+		ObjectURI:    remoteURI,
+		RawJSON:      `{"type":"Create","object":{"id":"` + remoteURI + `","content":"remote #golang","inReplyTo":null,"tag":[{"type":"Hashtag","name":"#GoLang"}]}}`,
+		CreatedAt:    time.Now(),
+	}); err != nil {
+		t.Fatalf("CreateActivity failed: %v", err)
+	}
+
+	// Remote post that only matches the LIKE pre-filter, not the actual tags
+	otherURI := "https://remote.example/notes/2"
+	if err := db.CreateActivity(&domain.Activity{
+		Id:           uuid.New(),
+		ActivityURI:  "https://remote.example/activities/2",
+		ActivityType: "Create",
+		ActorURI:     "https://remote.example/users/bob",
+		ObjectURI:    otherURI,
+		RawJSON:      `{"type":"Create","object":{"id":"` + otherURI + `","content":"#golangnuts","inReplyTo":null,"tag":[{"type":"Hashtag","name":"#golangnuts"}]}}`,
+		CreatedAt:    time.Now(),
+	}); err != nil {
+		t.Fatalf("CreateActivity failed: %v", err)
+	}
+
+	// Without followed tags nothing from the author or remote actor shows up
+	err, posts := db.ReadHomeTimelinePosts(viewerId, domain.Cursor{}, 20)
+	if err != nil {
+		t.Fatalf("ReadHomeTimelinePosts failed: %v", err)
+	}
+	if len(*posts) != 0 {
+		t.Fatalf("Expected empty home timeline, got %d posts", len(*posts))
+	}
+
+	if err := db.FollowHashtag(viewerId, "golang"); err != nil {
+		t.Fatalf("FollowHashtag failed: %v", err)
+	}
+
+	err, posts = db.ReadHomeTimelinePosts(viewerId, domain.Cursor{}, 20)
+	if err != nil {
+		t.Fatalf("ReadHomeTimelinePosts failed: %v", err)
+	}
+	if len(*posts) != 2 {
+		t.Fatalf("Expected 2 posts via #golang, got %d", len(*posts))
+	}
+	for _, post := range *posts {
+		if post.ViaHashtag != "golang" {
+			t.Errorf("Expected post %s to be labelled via #golang, got %q", post.ID, post.ViaHashtag)
+		}
+		if post.ObjectURI == remoteURI && post.Author != "@bob@remote.example" {
+			t.Errorf("Expected author derived from actor URI, got %s", post.Author)
+		}
+		if post.ObjectURI == otherURI {
+			t.Error("Did not expect post tagged #golangnuts")
+		}
+	}
+
+	// Own posts with a followed tag keep their plain label
+	ownId, err := db.CreateNote(viewerId, "Mine #golang")
+	if err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+	if err := db.LinkNoteHashtags(ownId, []int64{hashtagId}); err != nil {
+		t.Fatalf("LinkNoteHashtags failed: %v", err)
+	}
+	err, posts = db.ReadHomeTimelinePosts(viewerId, domain.Cursor{}, 20)
+	if err != nil {
+		t.Fatalf("ReadHomeTimelinePosts failed: %v", err)
+	}
+	for _, post := range *posts {
+		if post.NoteID == ownId && post.ViaHashtag != "" {
+			t.Errorf("Expected own post without via label, got %q", post.ViaHashtag)
+		}
+	}
+}
+
+func TestReadHashtagTimelinePosts_SkipsSubstringMatches(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	// The newest posts only match the LIKE pre-filter, the real matches are older
+	now := time.Now()
+	for i, name := range []string{"#golangnuts", "#golangnuts", "#golangnuts", "#golang", "#golang"} {
+		uri := fmt.Sprintf("https://remote.example/notes/%d", i)
+		if err := db.CreateActivity(&domain.Activity{
+			Id:           uuid.New(),
+			ActivityURI:  fmt.Sprintf("https://remote.example/activities/%d", i),
+			ActivityType: "Create",
+			ActorURI:     "https://remote.example/users/bob",
+			ObjectURI:    uri,
+			RawJSON:      `{"type":"Create","object":{"id":"` + uri + `","content":"` + name + `","inReplyTo":null,"tag":[{"type":"Hashtag","name":"` + name + `"}]}}`,
+			CreatedAt:    now.Add(-time.Duration(i) * time.Minute),
+		}); err != nil {
+			t.Fatalf("CreateActivity failed: %v", err)
+		}
+	}
+
+	err, posts := db.ReadHashtagTimelinePosts("golang", domain.Cursor{}, 2)
+	if err != nil {
+		t.Fatalf("ReadHashtagTimelinePosts failed: %v", err)
+	}
+	if len(*posts) != 2 {
+		t.Fatalf("Expected a full page of 2 posts, got %d", len(*posts))
+	}
+	for _, post := range *posts {
+		if post.Content != "#golang" {
+			t.Errorf("Did not expect %q in #golang", post.Content)
+		}
+	}
+}
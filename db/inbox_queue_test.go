@@ -0,0 +1,101 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+func newTestInboxItem(actor string, createdAt time.Time) *domain.InboxQueueItem {
+	return &domain.InboxQueueItem{
+		Id:           uuid.New(),
+		Username:     "alice",
+		ActivityJSON: `{"type":"Create"}`,
+		ActivityType: "Create",
+		ActorURI:     actor,
+		SignerURI:    actor,
+		NextRetryAt:  createdAt,
+		CreatedAt:    createdAt,
+	}
+}
+
+func TestInboxQueueOperations(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.db.Close()
+	testDB.db.SetMaxOpenConns(1)
+
+	base := time.Now().Add(-time.Minute)
+	bobFirst := newTestInboxItem("https://remote.example/users/bob", base)
+	bobSecond := newTestInboxItem("https://remote.example/users/bob", base.Add(time.Second))
+	carol := newTestInboxItem("https://remote.example/users/carol", base.Add(2*time.Second))
+	for _, item := range []*domain.InboxQueueItem{bobSecond, carol, bobFirst} {
+		if err := testDB.EnqueueInboxItem(item); err != nil {
+			t.Fatalf("EnqueueInboxItem failed: %v", err)
+		}
+	}
+
+	err, items := testDB.ReadPendingInboxItems(10)
+	if err != nil {
+		t.Fatalf("ReadPendingInboxItems failed: %v", err)
+	}
+	if len(*items) != 3 || (*items)[0].Id != bobFirst.Id || (*items)[1].Id != bobSecond.Id {
+		t.Fatalf("Expected items in arrival order, got %+v", *items)
+	}
+	if (*items)[0].Status != domain.InboxQueuePending {
+		t.Errorf("Expected status pending, got %q", (*items)[0].Status)
+	}
+
+	// A retry scheduled for bob's first item holds back his newer item
+	if err := testDB.UpdateInboxItemAttempt(bobFirst.Id, 1, time.Now().Add(time.Hour), "boom"); err != nil {
+		t.Fatalf("UpdateInboxItemAttempt failed: %v", err)
+	}
+	err, items = testDB.ReadPendingInboxItems(10)
+	if err != nil {
+		t.Fatalf("ReadPendingInboxItems failed: %v", err)
+	}
+	if len(*items) != 1 || (*items)[0].Id != carol.Id {
+		t.Fatalf("Expected only carol's item while bob waits, got %+v", *items)
+	}
+
+	// Held items no longer block the actor
+	if err := testDB.HoldInboxItem(bobFirst.Id, 8, "still failing"); err != nil {
+		t.Fatalf("HoldInboxItem failed: %v", err)
+	}
+	err, items = testDB.ReadPendingInboxItems(10)
+	if err != nil || len(*items) != 2 {
+		t.Fatalf("Expected 2 pending items after holding, got %v (err=%v)", items, err)
+	}
+	err, held := testDB.ReadHeldInboxItems(10)
+	if err != nil || len(*held) != 1 {
+		t.Fatalf("Expected 1 held item, got %v (err=%v)", held, err)
+	}
+	if (*held)[0].Attempts != 8 || (*held)[0].LastError != "still failing" {
+		t.Errorf("Unexpected held item: %+v", (*held)[0])
+	}
+
+	pending, heldCount, err := testDB.CountInboxQueue()
+	if err != nil || pending != 2 || heldCount != 1 {
+		t.Errorf("Expected 2 pending and 1 held, got %d/%d (err=%v)", pending, heldCount, err)
+	}
+
+	// Retrying puts the item back in the queue with a fresh attempt count
+	if err := testDB.RetryInboxItem(bobFirst.Id); err != nil {
+		t.Fatalf("RetryInboxItem failed: %v", err)
+	}
+	err, items = testDB.ReadPendingInboxItems(10)
+	if err != nil || len(*items) != 3 || (*items)[0].Id != bobFirst.Id || (*items)[0].Attempts != 0 {
+		t.Fatalf("Expected retried item first with 0 attempts, got %v (err=%v)", items, err)
+	}
+
+	for _, item := range []*domain.InboxQueueItem{bobFirst, bobSecond, carol} {
+		if err := testDB.DeleteInboxItem(item.Id); err != nil {
+			t.Fatalf("DeleteInboxItem failed: %v", err)
+		}
+	}
+	pending, heldCount, err = testDB.CountInboxQueue()
+	if err != nil || pending != 0 || heldCount != 0 {
+		t.Errorf("Expected empty queue, got %d/%d (err=%v)", pending, heldCount, err)
+	}
+}
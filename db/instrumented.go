@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/deemkeen/stegodon/metrics"
+)
+
+// instrumentedDriverName is the SQLite driver that records statement latency for the metrics
+const instrumentedDriverName = "sqlite-instrumented"
+
+func init() {
+	// Borrow the driver instance registered by modernc.org/sqlite
+	base, err := sql.Open("sqlite", "")
+	if err != nil {
+		panic(err)
+	}
+	sql.Register(instrumentedDriverName, &instrumentedDriver{base: base.Driver()})
+	base.Close()
+}
+
+// sqliteConn is the set of driver interfaces implemented by modernc.org/sqlite connections
+type sqliteConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+// instrumentedDriver wraps the SQLite driver so every query and exec is timed.
+// Errors are passed through unchanged, so *sqlite.Error checks keep working.
+type instrumentedDriver struct {
+	base driver.Driver
+}
+
+func (d *instrumentedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.base.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := conn.(sqliteConn)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected sqlite connection type %T", conn)
+	}
+	return &instrumentedConn{c}, nil
+}
+
+// instrumentedConn times ExecContext and QueryContext, which database/sql uses for
+// all statements run directly on a DB or Tx. Rows are timed until the first result,
+// not while they are scanned.
+type instrumentedConn struct {
+	sqliteConn
+}
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	result, err := c.sqliteConn.ExecContext(ctx, query, args)
+	metrics.ObserveDBQuery("exec", time.Since(start))
+	return result, err
+}
+
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := c.sqliteConn.QueryContext(ctx, query, args)
+	metrics.ObserveDBQuery("query", time.Since(start))
+	return rows, err
+}
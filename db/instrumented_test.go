@@ -0,0 +1,98 @@
+package db
+
+import (
+	"database/sql"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/metrics"
+	"github.com/google/uuid"
+	"modernc.org/sqlite"
+)
+
+func TestInstrumentedDriver(t *testing.T) {
+	sqlDB, err := sql.Open(instrumentedDriverName, ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open instrumented database: %v", err)
+	}
+	defer sqlDB.Close()
+	sqlDB.SetMaxOpenConns(1)
+
+	if _, err := sqlDB.Exec("CREATE TABLE t (id INTEGER PRIMARY KEY)"); err != nil {
+		t.Fatalf("Exec() error: %v", err)
+	}
+	var count int
+	if err := sqlDB.QueryRow("SELECT COUNT(*) FROM t").Scan(&count); err != nil {
+		t.Fatalf("QueryRow() error: %v", err)
+	}
+
+	// Driver errors must reach callers unchanged
+	_, err = sqlDB.Exec("INSERT INTO missing VALUES (1)")
+	if _, ok := err.(*sqlite.Error); !ok {
+		t.Errorf("Expected *sqlite.Error, got %T", err)
+	}
+
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	for _, op := range []string{"exec", "query"} {
+		if !strings.Contains(rec.Body.String(), `stegodon_db_query_duration_seconds_count{op="`+op+`"}`) {
+			t.Errorf("Expected %s latency in metrics", op)
+		}
+	}
+}
+
+func TestReadDeliveryQueueStats(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+	if _, err := db.db.Exec(sqlCreateDeliveryQueueTable); err != nil {
+		t.Fatalf("Failed to create delivery_queue table: %v", err)
+	}
+
+	depth, _, err := db.ReadDeliveryQueueStats()
+	if err != nil || depth != 0 {
+		t.Fatalf("Expected an empty queue, got %d (%v)", depth, err)
+	}
+
+	oldest := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i := 0; i < 3; i++ {
+		db.EnqueueDelivery(&domain.DeliveryQueueItem{
+			Id:           uuid.New(),
+			InboxURI:     "https://remote.example/inbox",
+			ActivityJSON: `{"type":"Create"}`,
+			NextRetryAt:  time.Now(),
+			CreatedAt:    oldest.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	depth, got, err := db.ReadDeliveryQueueStats()
+	if err != nil || depth != 3 {
+		t.Fatalf("Expected 3 queued deliveries, got %d (%v)", depth, err)
+	}
+	if !got.Equal(oldest) {
+		t.Errorf("Expected oldest delivery from %v, got %v", oldest, got)
+	}
+}
+
+func TestPingAndReadDatabaseSize(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("Ping() error: %v", err)
+	}
+	size, err := db.ReadDatabaseSize()
+	if err != nil {
+		t.Fatalf("ReadDatabaseSize() error: %v", err)
+	}
+	if size <= 0 {
+		t.Errorf("Expected a positive size, got %d", size)
+	}
+
+	db.db.Close()
+	if err := db.Ping(); err == nil {
+		t.Error("Expected Ping() to fail on a closed database")
+	}
+}
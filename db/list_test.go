@@ -0,0 +1,183 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+func TestListOperations(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.db.Close()
+	testDB.db.SetMaxOpenConns(1)
+
+	ownerId := uuid.New()
+	createTestAccount(t, testDB, ownerId, "alice", "pubkey1", "webpub", "webpriv")
+	memberId := uuid.New()
+	createTestAccount(t, testDB, memberId, "bob", "pubkey2", "webpub", "webpriv")
+
+	list := &domain.List{Id: uuid.New(), AccountId: ownerId, Name: "friends", CreatedAt: time.Now()}
+	if err := testDB.CreateList(list); err != nil {
+		t.Fatalf("CreateList failed: %v", err)
+	}
+	// Names are unique per account
+	if err := testDB.CreateList(&domain.List{Id: uuid.New(), AccountId: ownerId, Name: "friends", CreatedAt: time.Now()}); err == nil {
+		t.Error("Expected duplicate list name to fail")
+	}
+
+	if err := testDB.AddListMember(list.Id, memberId, true); err != nil {
+		t.Fatalf("AddListMember failed: %v", err)
+	}
+	// Adding twice is a no-op
+	if err := testDB.AddListMember(list.Id, memberId, true); err != nil {
+		t.Fatalf("AddListMember (duplicate) failed: %v", err)
+	}
+
+	err, lists := testDB.ReadListsByAccountId(ownerId)
+	if err != nil {
+		t.Fatalf("ReadListsByAccountId failed: %v", err)
+	}
+	if len(*lists) != 1 || (*lists)[0].Name != "friends" || (*lists)[0].MemberCount != 1 {
+		t.Fatalf("Expected one list with one member, got %+v", *lists)
+	}
+
+	err, ids := testDB.ReadListIdsByMember(ownerId, memberId)
+	if err != nil || len(ids) != 1 || ids[0] != list.Id {
+		t.Errorf("Expected member in list %s, got %v (err=%v)", list.Id, ids, err)
+	}
+
+	if err := testDB.UpdateListExcludeFromHome(list.Id, true); err != nil {
+		t.Fatalf("UpdateListExcludeFromHome failed: %v", err)
+	}
+	err, found := testDB.ReadListByName(ownerId, "friends")
+	if err != nil || found == nil {
+		t.Fatalf("ReadListByName failed: %v", err)
+	}
+	if !found.ExcludeFromHome {
+		t.Error("Expected list to be excluded from home")
+	}
+
+	err, missing := testDB.ReadListByName(ownerId, "nope")
+	if err != nil || missing != nil {
+		t.Errorf("Expected nil for unknown list, got %+v (err=%v)", missing, err)
+	}
+
+	if err := testDB.RemoveListMember(list.Id, memberId); err != nil {
+		t.Fatalf("RemoveListMember failed: %v", err)
+	}
+	err, ids = testDB.ReadListIdsByMember(ownerId, memberId)
+	if err != nil || len(ids) != 0 {
+		t.Errorf("Expected no memberships, got %v (err=%v)", ids, err)
+	}
+
+	if err := testDB.DeleteList(list.Id); err != nil {
+		t.Fatalf("DeleteList failed: %v", err)
+	}
+	err, lists = testDB.ReadListsByAccountId(ownerId)
+	if err != nil || len(*lists) != 0 {
+		t.Errorf("Expected no lists after delete, got %d (err=%v)", len(*lists), err)
+	}
+}
+
+func TestReadListTimelinePosts(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+
+	viewerId := uuid.New()
+	createTestAccount(t, db, viewerId, "viewer", "pubkey1", "webpub", "webpriv")
+	localId := uuid.New()
+	createTestAccount(t, db, localId, "carol", "pubkey2", "webpub", "webpriv")
+	otherId := uuid.New()
+	createTestAccount(t, db, otherId, "dave", "pubkey3", "webpub", "webpriv")
+	remoteId := uuid.New()
+	_, err := db.db.Exec(`INSERT INTO remote_accounts(id, username, domain, actor_uri, inbox_uri) VALUES (?, ?, ?, ?, ?)`,
+		remoteId.String(), "erin", "remote.example",
+		"https://remote.example/users/erin", "https://remote.example/users/erin/inbox")
+	if err != nil {
+		t.Fatalf("Failed to create remote account: %v", err)
+	}
+
+	for _, target := range []uuid.UUID{localId, otherId} {
+		if err := db.CreateLocalFollow(viewerId, target); err != nil {
+			t.Fatalf("CreateLocalFollow failed: %v", err)
+		}
+	}
+	_, err = db.db.Exec(`INSERT INTO follows(id, account_id, target_account_id, accepted, is_local) VALUES (?, ?, ?, 1, 0)`,
+		uuid.New().String(), viewerId.String(), remoteId.String())
+	if err != nil {
+		t.Fatalf("Failed to create follow: %v", err)
+	}
+
+	carolNote, _ := db.CreateNote(localId, "from carol")
+	daveNote, _ := db.CreateNote(otherId, "from dave")
+	ownNote, _ := db.CreateNote(viewerId, "from viewer")
+	remoteURI := "https://remote.example/notes/1"
+	if err := db.CreateActivity(&domain.Activity{
+		Id:           uuid.New(),
+		ActivityURI:  "https://remote.example/activities/1",
+		ActivityType: "Create",
+		ActorURI:     "https://remote.example/users/erin",
+		ObjectURI:    remoteURI,
+		RawJSON:      `{"type":"Create","object":{"id":"` + remoteURI + `","content":"from erin","inReplyTo":null}}`,
+		CreatedAt:    time.Now(),
+	}); err != nil {
+		t.Fatalf("CreateActivity failed: %v", err)
+	}
+
+	list := &domain.List{Id: uuid.New(), AccountId: viewerId, Name: "close", CreatedAt: time.Now()}
+	if err := db.CreateList(list); err != nil {
+		t.Fatalf("CreateList failed: %v", err)
+	}
+	db.AddListMember(list.Id, localId, true)
+	db.AddListMember(list.Id, remoteId, false)
+
+	collect := func(posts *[]domain.HomePost) map[string]bool {
+		seen := map[string]bool{}
+		for _, p := range *posts {
+			switch {
+			case p.NoteID == carolNote:
+				seen["carol"] = true
+			case p.NoteID == daveNote:
+				seen["dave"] = true
+			case p.NoteID == ownNote:
+				seen["own"] = true
+			case p.ObjectURI == remoteURI:
+				seen["erin"] = true
+			}
+		}
+		return seen
+	}
+
+	err, posts := db.ReadListTimelinePosts(viewerId, list.Id, domain.Cursor{}, 20)
+	if err != nil {
+		t.Fatalf("ReadListTimelinePosts failed: %v", err)
+	}
+	seen := collect(posts)
+	if !seen["carol"] || !seen["erin"] || seen["dave"] || seen["own"] {
+		t.Errorf("Expected only list members in list timeline, got %v", seen)
+	}
+
+	// Home still shows everyone until the list is excluded from home
+	err, posts = db.ReadHomeTimelinePosts(viewerId, domain.Cursor{}, 20)
+	if err != nil {
+		t.Fatalf("ReadHomeTimelinePosts failed: %v", err)
+	}
+	seen = collect(posts)
+	if !seen["carol"] || !seen["erin"] || !seen["dave"] || !seen["own"] {
+		t.Errorf("Expected all posts in home timeline, got %v", seen)
+	}
+
+	if err := db.UpdateListExcludeFromHome(list.Id, true); err != nil {
+		t.Fatalf("UpdateListExcludeFromHome failed: %v", err)
+	}
+	err, posts = db.ReadHomeTimelinePosts(viewerId, domain.Cursor{}, 20)
+	if err != nil {
+		t.Fatalf("ReadHomeTimelinePosts failed: %v", err)
+	}
+	seen = collect(posts)
+	if seen["carol"] || seen["erin"] || !seen["dave"] || !seen["own"] {
+		t.Errorf("Expected list members hidden from home, got %v", seen)
+	}
+}
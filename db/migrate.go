@@ -0,0 +1,276 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+)
+
+// migration is one numbered schema change. Applied migrations are recorded in
+// schema_migrations, so a new change is appended as a new version and existing
+// entries are never edited: RunMigrations refuses to start when the checksum of an
+// applied migration no longer matches.
+type migration struct {
+	version    int
+	name       string
+	statements []string                 // Run in order
+	apply      func(*DB, *sql.Tx) error // Run after statements, for changes SQL alone cannot express
+	body       []string                 // The schema and queries apply works from
+	down       []string                 // Reverts the migration, empty if it cannot be reverted
+}
+
+// checksum identifies the migration's name, statements and body, so that editing the
+// SQL of an apply function is detected like editing its statements
+func (m migration) checksum() string {
+	parts := append([]string{m.name}, m.statements...)
+	parts = append(parts, m.body...)
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n;\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// sqlCreateSchemaMigrationsTable records the applied migrations
+const sqlCreateSchemaMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INTEGER NOT NULL PRIMARY KEY,
+	name TEXT NOT NULL DEFAULT '',
+	checksum TEXT NOT NULL DEFAULT '',
+	applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+)`
+
+// appliedMigration is a row of schema_migrations
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// migrations returns the migrations of the database's backend, oldest first
+func (db *DB) migrations() []migration {
+	if db.postgres {
+		return postgresMigrations
+	}
+	return sqliteMigrations
+}
+
+// RunMigrations applies the migrations that have not run yet, oldest first, each in
+// its own transaction. It stops at the first one that fails, so the database is left
+// at the last version that applied cleanly.
+func (db *DB) RunMigrations() error {
+	if err := db.createSchemaMigrationsTable(); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	applied, err := db.readAppliedMigrations()
+	if err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	if err := db.checkAppliedMigrations(applied); err != nil {
+		return err
+	}
+
+	for _, m := range db.migrations() {
+		if _, ok := applied[m.version]; ok {
+			continue
+		}
+		log.Printf("Applying migration %d: %s", m.version, m.name)
+		if err := db.applyMigration(m); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+	}
+	return nil
+}
+
+// MigrateDown reverts the applied migrations above version, newest first. Nothing is
+// reverted unless all of them have a down migration.
+func (db *DB) MigrateDown(version int) error {
+	applied, err := db.readAppliedMigrations()
+	if err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	var revert []migration
+	known := db.migrations()
+	for i := len(known) - 1; i >= 0; i-- {
+		m := known[i]
+		if _, ok := applied[m.version]; !ok || m.version <= version {
+			continue
+		}
+		if len(m.down) == 0 {
+			return fmt.Errorf("migration %d (%s) cannot be reverted", m.version, m.name)
+		}
+		revert = append(revert, m)
+	}
+
+	for _, m := range revert {
+		log.Printf("Reverting migration %d: %s", m.version, m.name)
+		err := db.migrationTransaction(func(tx *sql.Tx) error {
+			for _, statement := range m.down {
+				if _, err := tx.Exec(statement); err != nil {
+					return err
+				}
+			}
+			if _, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, m.version); err != nil {
+				return err
+			}
+			return db.setUserVersion(tx, m.version-1)
+		})
+		if err != nil {
+			return fmt.Errorf("reverting migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+	}
+	return nil
+}
+
+// readMigrations returns the migrations of the database's backend and whether they
+// have been applied, without changing anything
+func (db *DB) readMigrations() ([]domain.Migration, error) {
+	applied, err := db.readAppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	var migrations []domain.Migration
+	for _, m := range db.migrations() {
+		row, ok := applied[m.version]
+		migrations = append(migrations, domain.Migration{
+			Version:    m.version,
+			Name:       m.name,
+			Reversible: len(m.down) > 0,
+			Applied:    ok,
+			AppliedAt:  row.appliedAt,
+			Modified:   ok && row.checksum != "" && row.checksum != m.checksum(),
+		})
+	}
+	return migrations, nil
+}
+
+// checkAppliedMigrations refuses databases migrated by a newer stegodon and migrations
+// that were edited after they had been applied. Rows recorded before checksums were
+// stored get the current checksum.
+func (db *DB) checkAppliedMigrations(applied map[int]appliedMigration) error {
+	known := db.migrations()
+	latest := known[len(known)-1].version
+	for version := range applied {
+		if version > latest {
+			return fmt.Errorf("database is at migration %d, this stegodon only knows up to %d; upgrade stegodon first", version, latest)
+		}
+	}
+
+	for _, m := range known {
+		row, ok := applied[m.version]
+		if !ok {
+			continue
+		}
+		if row.checksum == "" {
+			if _, err := db.db.Exec(`UPDATE schema_migrations SET name = ?, checksum = ? WHERE version = ?`, m.name, m.checksum(), m.version); err != nil {
+				return fmt.Errorf("failed to record checksum of migration %d: %w", m.version, err)
+			}
+			continue
+		}
+		if row.checksum != m.checksum() {
+			return fmt.Errorf("migration %d (%s) was changed after it had been applied; add a new migration instead", m.version, m.name)
+		}
+	}
+	return nil
+}
+
+// applyMigration runs a migration and records it in the same transaction
+func (db *DB) applyMigration(m migration) error {
+	return db.migrationTransaction(func(tx *sql.Tx) error {
+		for _, statement := range m.statements {
+			if _, err := tx.Exec(statement); err != nil {
+				return err
+			}
+		}
+		if m.apply != nil {
+			if err := m.apply(db, tx); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations(version, name, checksum) VALUES (?, ?, ?)`, m.version, m.name, m.checksum()); err != nil {
+			return err
+		}
+		return db.setUserVersion(tx, m.version)
+	})
+}
+
+// migrationTransaction runs f in a transaction without the time limit of
+// wrapTransaction, as migrations may rewrite whole tables
+func (db *DB) migrationTransaction(f func(tx *sql.Tx) error) error {
+	tx, err := db.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	if err := f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// setUserVersion keeps SQLite's PRAGMA user_version at the latest applied migration,
+// so the version can be read from backups without schema_migrations, see ReadSchemaVersion
+func (db *DB) setUserVersion(tx *sql.Tx, version int) error {
+	if db.postgres {
+		return nil
+	}
+	_, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", version))
+	return err
+}
+
+// createSchemaMigrationsTable creates schema_migrations, and adds the name and checksum
+// columns to PostgreSQL tables created before they existed
+func (db *DB) createSchemaMigrationsTable() error {
+	if !db.postgres {
+		_, err := db.db.Exec(sqlCreateSchemaMigrationsTable)
+		return err
+	}
+	for _, statement := range []string{
+		sqlCreatePostgresSchemaMigrationsTable,
+		`ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS name TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS checksum TEXT NOT NULL DEFAULT ''`,
+	} {
+		if _, err := db.db.Exec(statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readAppliedMigrations returns the rows of schema_migrations by version, none if the
+// table does not exist yet
+func (db *DB) readAppliedMigrations() (map[int]appliedMigration, error) {
+	applied := map[int]appliedMigration{}
+
+	existsQuery := `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`
+	if db.postgres {
+		existsQuery = `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = 'schema_migrations'`
+	}
+	var exists int
+	if err := db.db.QueryRow(existsQuery).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return applied, nil
+	}
+
+	rows, err := db.db.Query(`SELECT version, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int
+		var row appliedMigration
+		var appliedAt sql.NullTime
+		if err := rows.Scan(&version, &row.checksum, &appliedAt); err != nil {
+			return nil, err
+		}
+		row.appliedAt = appliedAt.Time
+		applied[version] = row
+	}
+	return applied, rows.Err()
+}
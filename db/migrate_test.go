@@ -0,0 +1,266 @@
+package db
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupEmptyTestDB opens a new, empty database file
+func setupEmptyTestDB(t *testing.T) *DB {
+	t.Helper()
+	sqlDB, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "database.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return &DB{db: sqlDB}
+}
+
+// withSQLiteMigrations replaces the SQLite migrations for the duration of a test
+func withSQLiteMigrations(t *testing.T, migrations []migration) {
+	t.Helper()
+	saved := sqliteMigrations
+	sqliteMigrations = migrations
+	t.Cleanup(func() { sqliteMigrations = saved })
+}
+
+func tableExists(t *testing.T, db *DB, name string) bool {
+	t.Helper()
+	var count int
+	if err := db.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&count); err != nil {
+		t.Fatalf("Failed to look up table %s: %v", name, err)
+	}
+	return count > 0
+}
+
+var testMigrations = []migration{
+	{version: 1, name: "create a", statements: []string{`CREATE TABLE a (id INTEGER)`}},
+	{version: 2, name: "create b", statements: []string{`CREATE TABLE b (id INTEGER)`}, down: []string{`DROP TABLE b`}},
+}
+
+func TestMigrationsAreNumbered(t *testing.T) {
+	for name, migrations := range map[string][]migration{"sqlite": sqliteMigrations, "postgres": postgresMigrations} {
+		for i, m := range migrations {
+			if m.version != i+1 {
+				t.Errorf("%s migration %d has version %d", name, i+1, m.version)
+			}
+			if m.name == "" || (len(m.statements) == 0 && m.apply == nil) {
+				t.Errorf("%s migration %d needs a name and statements or an apply function", name, m.version)
+			}
+		}
+	}
+	if SchemaVersion != sqliteMigrations[len(sqliteMigrations)-1].version {
+		t.Errorf("SchemaVersion is %d, the last SQLite migration %d", SchemaVersion, len(sqliteMigrations))
+	}
+}
+
+func TestRunMigrations_FreshDatabase(t *testing.T) {
+	db := setupEmptyTestDB(t)
+	if err := db.CreateDB(); err != nil {
+		t.Fatalf("CreateDB failed: %v", err)
+	}
+	if err := db.RunMigrations(); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+
+	version, err := db.ReadSchemaVersion()
+	if err != nil || version != SchemaVersion {
+		t.Errorf("Expected schema version %d, got %d (%v)", SchemaVersion, version, err)
+	}
+	err, status := db.ReadSchemaStatus()
+	if err != nil {
+		t.Fatalf("ReadSchemaStatus failed: %v", err)
+	}
+	if !status.UpToDate() || len(status.Migrations) != SchemaVersion {
+		t.Errorf("Expected all %d migrations to be applied, got %+v", SchemaVersion, status.Migrations)
+	}
+	for _, m := range status.Migrations {
+		if m.AppliedAt.IsZero() || m.Modified {
+			t.Errorf("Unexpected migration status %+v", m)
+		}
+	}
+
+	// Running them again is a no-op
+	if err := db.RunMigrations(); err != nil {
+		t.Fatalf("Second RunMigrations failed: %v", err)
+	}
+	var rows int
+	db.db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&rows)
+	if rows != SchemaVersion {
+		t.Errorf("Expected %d recorded migrations, got %d", SchemaVersion, rows)
+	}
+}
+
+func TestRunMigrations_UnversionedDatabase(t *testing.T) {
+	db := setupEmptyTestDB(t)
+	if err := db.CreateDB(); err != nil {
+		t.Fatalf("CreateDB failed: %v", err)
+	}
+	if err := db.RunMigrations(); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+	if err := db.CreateAccountWithPublicKey("alice", testAuthorizedKey); err != nil {
+		t.Fatalf("CreateAccountWithPublicKey failed: %v", err)
+	}
+
+	// A database migrated before schema_migrations existed has the tables but no record
+	db.db.Exec(`DROP TABLE schema_migrations`)
+	db.db.Exec(`PRAGMA user_version = 0`)
+
+	if err := db.RunMigrations(); err != nil {
+		t.Fatalf("RunMigrations on an unversioned database failed: %v", err)
+	}
+	if version, _ := db.ReadSchemaVersion(); version != SchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", SchemaVersion, version)
+	}
+	if err, acc := db.ReadAccByUsername("alice"); err != nil || acc == nil {
+		t.Errorf("Expected existing accounts to be kept: %v", err)
+	}
+}
+
+func TestRunMigrations_AddsMissingColumns(t *testing.T) {
+	db := setupEmptyTestDB(t)
+	if err := db.CreateDB(); err != nil {
+		t.Fatalf("CreateDB failed: %v", err)
+	}
+	if err := db.RunMigrations(); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+
+	// An unversioned database whose notifications table predates the emoji column
+	if _, err := db.db.Exec(`ALTER TABLE notifications DROP COLUMN emoji`); err != nil {
+		t.Fatalf("Failed to drop column: %v", err)
+	}
+	db.db.Exec(`DROP TABLE schema_migrations`)
+	db.db.Exec(`PRAGMA user_version = 0`)
+
+	if err := db.RunMigrations(); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+	columns, err := db.readColumnNames(db.db, "notifications")
+	if err != nil || !columns["emoji"] {
+		t.Errorf("Expected notifications.emoji to be added, got %v (%v)", columns, err)
+	}
+}
+
+func TestRunMigrations_FailureRollsBack(t *testing.T) {
+	withSQLiteMigrations(t, []migration{
+		testMigrations[0],
+		{version: 2, name: "broken", statements: []string{`CREATE TABLE b (id INTEGER)`, `INSERT INTO missing VALUES (1)`}},
+	})
+	db := setupEmptyTestDB(t)
+
+	err := db.RunMigrations()
+	if err == nil || !strings.Contains(err.Error(), "migration 2 (broken) failed") {
+		t.Fatalf("Expected migration 2 to fail, got %v", err)
+	}
+	if !tableExists(t, db, "a") || tableExists(t, db, "b") {
+		t.Error("Expected migration 1 to be kept and migration 2 to be rolled back")
+	}
+	if version, _ := db.ReadSchemaVersion(); version != 1 {
+		t.Errorf("Expected schema version 1, got %d", version)
+	}
+}
+
+func TestRunMigrations_RefusesChangedMigration(t *testing.T) {
+	withSQLiteMigrations(t, testMigrations)
+	db := setupEmptyTestDB(t)
+	if err := db.RunMigrations(); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+
+	changed := []migration{testMigrations[0], testMigrations[1]}
+	changed[1].statements = []string{`CREATE TABLE b (id INTEGER, name TEXT)`}
+	sqliteMigrations = changed
+
+	err := db.RunMigrations()
+	if err == nil || !strings.Contains(err.Error(), "was changed after it had been applied") {
+		t.Fatalf("Expected the changed migration to be refused, got %v", err)
+	}
+	err, status := db.ReadSchemaStatus()
+	if err != nil || !status.Migrations[1].Modified {
+		t.Errorf("Expected migration 2 to be reported as modified, got %+v (%v)", status, err)
+	}
+}
+
+func TestRunMigrations_RefusesChangedApplyBody(t *testing.T) {
+	apply := func(db *DB, tx *sql.Tx) error { return nil }
+	withSQLiteMigrations(t, []migration{
+		{version: 1, name: "backfill", apply: apply, body: []string{`UPDATE a SET id = 1`}},
+	})
+	db := setupEmptyTestDB(t)
+	if err := db.RunMigrations(); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+
+	sqliteMigrations = []migration{
+		{version: 1, name: "backfill", apply: apply, body: []string{`UPDATE a SET id = 2`}},
+	}
+	applied, err := db.readAppliedMigrations()
+	if err != nil {
+		t.Fatalf("readAppliedMigrations failed: %v", err)
+	}
+	if err := db.checkAppliedMigrations(applied); err == nil || !strings.Contains(err.Error(), "was changed after it had been applied") {
+		t.Errorf("Expected the changed body to be refused, got %v", err)
+	}
+}
+
+func TestBaselineChecksumCoversSchema(t *testing.T) {
+	before := sqliteMigrations[0].checksum()
+
+	saved := schemaColumns
+	schemaColumns = append(schemaColumns[:len(schemaColumns):len(schemaColumns)], schemaColumns[0])
+	schemaColumns[len(schemaColumns)-1].column = "added"
+	defer func() { schemaColumns = saved }()
+
+	changed := sqliteMigrations[0]
+	changed.body = baselineSchema()
+	if changed.checksum() == before {
+		t.Error("Expected a new column to change the baseline checksum")
+	}
+}
+
+func TestRunMigrations_RefusesNewerDatabase(t *testing.T) {
+	withSQLiteMigrations(t, testMigrations)
+	db := setupEmptyTestDB(t)
+	if err := db.RunMigrations(); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+
+	sqliteMigrations = testMigrations[:1]
+	if err := db.RunMigrations(); err == nil || !strings.Contains(err.Error(), "upgrade stegodon") {
+		t.Errorf("Expected a newer database to be refused, got %v", err)
+	}
+}
+
+func TestMigrateDown(t *testing.T) {
+	withSQLiteMigrations(t, testMigrations)
+	db := setupEmptyTestDB(t)
+	if err := db.RunMigrations(); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+
+	if err := db.MigrateDown(1); err != nil {
+		t.Fatalf("MigrateDown failed: %v", err)
+	}
+	if tableExists(t, db, "b") {
+		t.Error("Expected migration 2 to be reverted")
+	}
+	if version, _ := db.ReadSchemaVersion(); version != 1 {
+		t.Errorf("Expected schema version 1, got %d", version)
+	}
+
+	if err := db.MigrateDown(0); err == nil || !strings.Contains(err.Error(), "cannot be reverted") {
+		t.Errorf("Expected migration 1 to be irreversible, got %v", err)
+	}
+	if !tableExists(t, db, "a") {
+		t.Error("Expected migration 1 to be kept")
+	}
+
+	// Reverted migrations are applied again
+	if err := db.RunMigrations(); err != nil || !tableExists(t, db, "b") {
+		t.Errorf("Expected migration 2 to be applied again: %v", err)
+	}
+}
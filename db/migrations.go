@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"strings"
 
+	"github.com/deemkeen/stegodon/domain"
 	"github.com/google/uuid"
 )
 
@@ -88,6 +88,28 @@ const (
 		CREATE INDEX IF NOT EXISTS idx_likes_note_id ON likes(note_id);
 		CREATE INDEX IF NOT EXISTS idx_likes_account_id ON likes(account_id);
 		CREATE INDEX IF NOT EXISTS idx_likes_object_uri ON likes(object_uri);
+		-- One like per account per remote post (identified by object_uri)
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_likes_account_object_uri ON likes(account_id, object_uri) WHERE object_uri IS NOT NULL AND object_uri != '';
+	`
+
+	// Emoji reactions table (EmojiReact and Misskey-style Like with content)
+	sqlCreateReactionsTable = `CREATE TABLE IF NOT EXISTS reactions (
+		id TEXT NOT NULL PRIMARY KEY,
+		account_id TEXT NOT NULL,
+		note_id TEXT,
+		object_uri TEXT,
+		emoji TEXT NOT NULL,
+		uri TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+
+	// One reaction per account per post: local notes are keyed by note_id, remote posts by object_uri
+	sqlCreateReactionsIndices = `
+		CREATE INDEX IF NOT EXISTS idx_reactions_note_id ON reactions(note_id);
+		CREATE INDEX IF NOT EXISTS idx_reactions_object_uri ON reactions(object_uri);
+		CREATE INDEX IF NOT EXISTS idx_reactions_uri ON reactions(uri);
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_reactions_account_note ON reactions(account_id, note_id) WHERE note_id IS NOT NULL;
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_reactions_account_object_uri ON reactions(account_id, object_uri) WHERE note_id IS NULL;
 	`
 
 	// Boosts/announces table
@@ -108,6 +130,8 @@ const (
 		CREATE INDEX IF NOT EXISTS idx_boosts_object_uri ON boosts(object_uri);
 		CREATE INDEX IF NOT EXISTS idx_boosts_remote_account_id ON boosts(remote_account_id);
 		CREATE INDEX IF NOT EXISTS idx_boosts_remote_created ON boosts(remote_account_id, created_at DESC);
+		-- One boost per account per remote post (identified by object_uri)
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_boosts_account_object_uri ON boosts(account_id, object_uri) WHERE object_uri IS NOT NULL AND object_uri != '';
 	`
 
 	// Delivery queue table
@@ -194,6 +218,7 @@ const (
 		note_id TEXT,
 		note_uri TEXT,
 		note_preview TEXT,
+		emoji TEXT,
 		read INTEGER DEFAULT 0,
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
@@ -260,6 +285,167 @@ const (
 		CREATE INDEX IF NOT EXISTS idx_bans_public_key_hash ON bans(public_key_hash);
 	`
 
+	// Custom emoji table for admin-uploaded :shortcode: emoji
+	sqlCreateCustomEmojisTable = `CREATE TABLE IF NOT EXISTS custom_emojis (
+		id TEXT NOT NULL PRIMARY KEY,
+		shortcode TEXT NOT NULL UNIQUE,
+		image_url TEXT NOT NULL,
+		media_type TEXT NOT NULL DEFAULT 'image/png',
+		created_by TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+
+	// Hashtags followed by local accounts (merged into the home timeline)
+	sqlCreateFollowedHashtagsTable = `CREATE TABLE IF NOT EXISTS followed_hashtags (
+		id TEXT NOT NULL PRIMARY KEY,
+		account_id TEXT NOT NULL,
+		hashtag TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(account_id, hashtag)
+	)`
+
+	sqlCreateFollowedHashtagsIndices = `
+		CREATE INDEX IF NOT EXISTS idx_followed_hashtags_account_id ON followed_hashtags(account_id);
+		CREATE INDEX IF NOT EXISTS idx_followed_hashtags_hashtag ON followed_hashtags(hashtag);
+	`
+
+	// User-defined lists of followed accounts (local or remote) with their own timeline
+	sqlCreateListsTable = `CREATE TABLE IF NOT EXISTS lists (
+		id TEXT NOT NULL PRIMARY KEY,
+		account_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		exclude_from_home INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(account_id, name)
+	)`
+
+	// member_id is a local account id or a remote_accounts id (same as follows.target_account_id)
+	sqlCreateListMembersTable = `CREATE TABLE IF NOT EXISTS list_members (
+		list_id TEXT NOT NULL,
+		member_id TEXT NOT NULL,
+		is_local INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY(list_id, member_id)
+	)`
+
+	sqlCreateListsIndices = `
+		CREATE INDEX IF NOT EXISTS idx_lists_account_id ON lists(account_id);
+		CREATE INDEX IF NOT EXISTS idx_list_members_member_id ON list_members(member_id);
+	`
+
+	// Incoming activities waiting to be processed by the inbox workers
+	sqlCreateInboxQueueTable = `CREATE TABLE IF NOT EXISTS inbox_queue (
+		id TEXT NOT NULL PRIMARY KEY,
+		username TEXT NOT NULL,
+		activity_json TEXT NOT NULL,
+		activity_type TEXT NOT NULL,
+		actor_uri TEXT NOT NULL,
+		signer_uri TEXT NOT NULL,
+		attempts INTEGER DEFAULT 0,
+		next_retry_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		status TEXT NOT NULL DEFAULT 'pending',
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+
+	sqlCreateInboxQueueIndices = `
+		CREATE INDEX IF NOT EXISTS idx_inbox_queue_status_next_retry ON inbox_queue(status, next_retry_at);
+		CREATE INDEX IF NOT EXISTS idx_inbox_queue_actor ON inbox_queue(actor_uri, created_at);
+	`
+
+	// Client apps registered through the Mastodon client API
+	// redirect_uris and scopes are space separated
+	sqlCreateOAuthAppsTable = `CREATE TABLE IF NOT EXISTS oauth_apps (
+		id TEXT NOT NULL PRIMARY KEY,
+		client_id TEXT NOT NULL UNIQUE,
+		client_secret TEXT NOT NULL,
+		name TEXT NOT NULL,
+		website TEXT NOT NULL DEFAULT '',
+		redirect_uris TEXT NOT NULL,
+		scopes TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+
+	// Authorization requests waiting for the user to confirm the code in the TUI
+	sqlCreateOAuthAuthorizationsTable = `CREATE TABLE IF NOT EXISTS oauth_authorizations (
+		id TEXT NOT NULL PRIMARY KEY,
+		app_id TEXT NOT NULL,
+		user_code TEXT NOT NULL,
+		redirect_uri TEXT NOT NULL,
+		scopes TEXT NOT NULL,
+		state TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL DEFAULT 'pending',
+		account_id TEXT,
+		code TEXT,
+		expires_at TIMESTAMP NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+
+	// Access tokens (stored as SHA-256 hashes)
+	sqlCreateOAuthTokensTable = `CREATE TABLE IF NOT EXISTS oauth_tokens (
+		id TEXT NOT NULL PRIMARY KEY,
+		token_hash TEXT NOT NULL UNIQUE,
+		app_id TEXT NOT NULL,
+		account_id TEXT,
+		scopes TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		last_used_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+
+	sqlCreateOAuthIndices = `
+		CREATE INDEX IF NOT EXISTS idx_oauth_authorizations_user_code ON oauth_authorizations(user_code);
+		CREATE INDEX IF NOT EXISTS idx_oauth_authorizations_code ON oauth_authorizations(code);
+		CREATE INDEX IF NOT EXISTS idx_oauth_tokens_account_id ON oauth_tokens(account_id);
+	`
+
+	// Per-user keyword filters (muted phrases)
+	// contexts is a comma-separated list of home, global, notifications, thread
+	sqlCreateFiltersTable = `CREATE TABLE IF NOT EXISTS filters (
+		id TEXT NOT NULL PRIMARY KEY,
+		account_id TEXT NOT NULL,
+		phrase TEXT NOT NULL,
+		whole_word INTEGER NOT NULL DEFAULT 0,
+		contexts TEXT NOT NULL,
+		action TEXT NOT NULL DEFAULT 'hide',
+		expires_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+
+	sqlCreateFiltersIndices = `
+		CREATE INDEX IF NOT EXISTS idx_filters_account_id ON filters(account_id);
+	`
+
+	// Notes waiting to be published at scheduled_at (RFC3339, UTC)
+	sqlCreateScheduledNotesTable = `CREATE TABLE IF NOT EXISTS scheduled_notes (
+		id TEXT NOT NULL PRIMARY KEY,
+		account_id TEXT NOT NULL,
+		message TEXT NOT NULL,
+		scheduled_at TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`
+
+	// Unsent notes autosaved from the write note panel (timestamps RFC3339, UTC)
+	sqlCreateDraftsTable = `CREATE TABLE IF NOT EXISTS drafts (
+		id TEXT NOT NULL PRIMARY KEY,
+		account_id TEXT NOT NULL,
+		message TEXT NOT NULL,
+		title TEXT NOT NULL DEFAULT '',
+		in_reply_to_uri TEXT NOT NULL DEFAULT '',
+		reply_to_author TEXT NOT NULL DEFAULT '',
+		reply_to_preview TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL,
+		updated_at TEXT NOT NULL
+	)`
+
+	sqlCreateDraftsIndices = `
+		CREATE INDEX IF NOT EXISTS idx_drafts_account_id ON drafts(account_id, updated_at);
+	`
+
+	sqlCreateScheduledNotesIndices = `
+		CREATE INDEX IF NOT EXISTS idx_scheduled_notes_account_id ON scheduled_notes(account_id);
+		CREATE INDEX IF NOT EXISTS idx_scheduled_notes_scheduled_at ON scheduled_notes(scheduled_at);
+	`
+
 	// Extend existing tables with new columns
 	sqlExtendAccountsTable = `
 		ALTER TABLE accounts ADD COLUMN display_name TEXT;
@@ -288,133 +474,268 @@ const (
 	`
 )
 
-// RunMigrations executes all database migrations
-func (db *DB) RunMigrations() error {
-	return db.wrapTransaction(func(tx *sql.Tx) error {
-		// Create new tables
-		if err := db.createTableIfNotExists(tx, sqlCreateFollowsTable, "follows"); err != nil {
-			return err
-		}
-		if err := db.createTableIfNotExists(tx, sqlCreateRemoteAccountsTable, "remote_accounts"); err != nil {
-			return err
-		}
-		if err := db.createTableIfNotExists(tx, sqlCreateActivitiesTable, "activities"); err != nil {
-			return err
-		}
-		if err := db.createTableIfNotExists(tx, sqlCreateLikesTable, "likes"); err != nil {
-			return err
-		}
-		if err := db.createTableIfNotExists(tx, sqlCreateBoostsTable, "boosts"); err != nil {
-			return err
-		}
-		if err := db.createTableIfNotExists(tx, sqlCreateDeliveryQueueTable, "delivery_queue"); err != nil {
-			return err
-		}
-		if err := db.createTableIfNotExists(tx, sqlCreateHashtagsTable, "hashtags"); err != nil {
-			return err
-		}
-		if err := db.createTableIfNotExists(tx, sqlCreateNoteHashtagsTable, "note_hashtags"); err != nil {
-			return err
-		}
-		if err := db.createTableIfNotExists(tx, sqlCreateNoteMentionsTable, "note_mentions"); err != nil {
-			return err
-		}
-		if err := db.createTableIfNotExists(tx, sqlCreateRelaysTable, "relays"); err != nil {
-			return err
-		}
-		if err := db.createTableIfNotExists(tx, sqlCreateNotificationsTable, "notifications"); err != nil {
-			return err
-		}
-		if err := db.createTableIfNotExists(tx, sqlCreateInfoBoxesTable, "info_boxes"); err != nil {
-			return err
-		}
-		if err := db.createTableIfNotExists(tx, sqlCreateUploadTokensTable, "upload_tokens"); err != nil {
-			return err
-		}
-		if err := db.createTableIfNotExists(tx, sqlCreateServerMessageTable, "server_message"); err != nil {
-			return err
-		}
-		if err := db.createTableIfNotExists(tx, sqlCreateBansTable, "bans"); err != nil {
+// schemaTables are the tables created by RunMigrations, in creation order
+var schemaTables = []struct {
+	name      string
+	createSQL string
+}{
+	{"follows", sqlCreateFollowsTable},
+	{"remote_accounts", sqlCreateRemoteAccountsTable},
+	{"activities", sqlCreateActivitiesTable},
+	{"likes", sqlCreateLikesTable},
+	{"reactions", sqlCreateReactionsTable},
+	{"boosts", sqlCreateBoostsTable},
+	{"delivery_queue", sqlCreateDeliveryQueueTable},
+	{"hashtags", sqlCreateHashtagsTable},
+	{"note_hashtags", sqlCreateNoteHashtagsTable},
+	{"note_mentions", sqlCreateNoteMentionsTable},
+	{"relays", sqlCreateRelaysTable},
+	{"notifications", sqlCreateNotificationsTable},
+	{"info_boxes", sqlCreateInfoBoxesTable},
+	{"upload_tokens", sqlCreateUploadTokensTable},
+	{"server_message", sqlCreateServerMessageTable},
+	{"bans", sqlCreateBansTable},
+	{"custom_emojis", sqlCreateCustomEmojisTable},
+	{"followed_hashtags", sqlCreateFollowedHashtagsTable},
+	{"lists", sqlCreateListsTable},
+	{"list_members", sqlCreateListMembersTable},
+	{"filters", sqlCreateFiltersTable},
+	{"inbox_queue", sqlCreateInboxQueueTable},
+	{"oauth_apps", sqlCreateOAuthAppsTable},
+	{"oauth_authorizations", sqlCreateOAuthAuthorizationsTable},
+	{"oauth_tokens", sqlCreateOAuthTokensTable},
+	{"scheduled_notes", sqlCreateScheduledNotesTable},
+	{"drafts", sqlCreateDraftsTable},
+}
+
+// schemaIndices are the indexes created by the baseline migration, once the columns
+// they cover exist
+var schemaIndices = []struct {
+	name      string
+	createSQL string
+}{
+	{"follows", sqlCreateFollowsIndices},
+	{"remote_accounts", sqlCreateRemoteAccountsIndices},
+	{"activities", sqlCreateActivitiesIndices},
+	{"likes", sqlCreateLikesIndices},
+	{"reactions", sqlCreateReactionsIndices},
+	{"boosts", sqlCreateBoostsIndices},
+	{"delivery_queue", sqlCreateDeliveryQueueIndices},
+	{"hashtags", sqlCreateHashtagsIndices},
+	{"note_hashtags", sqlCreateNoteHashtagsIndices},
+	{"note_mentions", sqlCreateNoteMentionsIndices},
+	{"relays", sqlCreateRelaysIndices},
+	{"notifications", sqlCreateNotificationsIndices},
+	{"info_boxes", sqlCreateInfoBoxesIndices},
+	{"upload_tokens", sqlCreateUploadTokensIndices},
+	{"bans", sqlCreateBansIndices},
+	{"followed_hashtags", sqlCreateFollowedHashtagsIndices},
+	{"lists", sqlCreateListsIndices},
+	{"filters", sqlCreateFiltersIndices},
+	{"inbox_queue", sqlCreateInboxQueueIndices},
+	{"oauth", sqlCreateOAuthIndices},
+	{"scheduled_notes", sqlCreateScheduledNotesIndices},
+	{"drafts", sqlCreateDraftsIndices},
+	{"notes", sqlCreateNotesIndices},
+}
+
+// baselineSchema is the schema the baseline migration creates, the body of its checksum
+func baselineSchema() []string {
+	var body []string
+	for _, table := range schemaTables {
+		body = append(body, table.createSQL)
+	}
+	for _, c := range schemaColumns {
+		body = append(body, c.table+"."+c.column+" "+c.definition)
+	}
+	for _, index := range schemaIndices {
+		body = append(body, index.createSQL)
+	}
+	return append(body, defaultInfoBoxesBody()...)
+}
+
+// sqliteMigrations is the SQLite schema history. The first migration brings databases
+// created before versioned migrations, which re-ran every step on each start, to the
+// same state as new ones; later changes are appended as new versions.
+var sqliteMigrations = []migration{
+	{version: 1, name: "baseline schema", apply: (*DB).migrateBaseline, body: baselineSchema()},
+	{version: 2, name: "convert keys to PKCS#8", apply: (*DB).migrateKeysToPKCS8,
+		body: []string{sqlSelectAccountKeys, sqlUpdateAccountKeys}},
+	{version: 3, name: "unique follows", apply: (*DB).migrateDuplicateFollows,
+		body: []string{sqlSelectDuplicateFollows, sqlDeleteDuplicateFollows, sqlCreateUniqueFollowsTable, sqlCopyFollowsTable, sqlReplaceFollowsTable, sqlCreateFollowsIndices}},
+	{version: 4, name: "count local replies", apply: (*DB).migrateLocalReplyCounts,
+		body: []string{sqlSelectNotesWithUncountedLocalReplies, sqlCountLocalReplyChain, sqlUpdateNoteReplyCount}},
+	{version: 5, name: "backfill activity replies and urls", apply: (*DB).backfillActivityRepliesAndURLs,
+		body: []string{sqlSelectActivitiesWithoutInReplyTo, sqlUpdateActivityInReplyTo, sqlSelectActivitiesWithoutObjectURL, sqlUpdateActivityObjectURL}},
+	{version: 6, name: "remove orphan activities", apply: (*DB).migrateOrphanActivities,
+		body: []string{sqlDeleteOrphanActivities}},
+}
+
+// SchemaVersion is the version of the last SQLite migration, stored in PRAGMA
+// user_version once applied, so that a backup of a newer schema is not restored
+// by an older stegodon
+const SchemaVersion = 6
+
+// migrateBaseline creates the tables, columns and indexes, and runs the data fixes that
+// used to run on every start. Every step is idempotent, so it also completes databases
+// that were migrated by those earlier versions.
+func (db *DB) migrateBaseline(tx *sql.Tx) error {
+	// Create new tables
+	for _, table := range schemaTables {
+		if err := db.createTableIfNotExists(tx, table.createSQL, table.name); err != nil {
 			return err
 		}
+	}
 
-		// Create indices
-		if _, err := tx.Exec(sqlCreateFollowsIndices); err != nil {
-			log.Printf("Warning: Failed to create follows indices: %v", err)
-		}
-		if _, err := tx.Exec(sqlCreateRemoteAccountsIndices); err != nil {
-			log.Printf("Warning: Failed to create remote_accounts indices: %v", err)
-		}
-		if _, err := tx.Exec(sqlCreateActivitiesIndices); err != nil {
-			log.Printf("Warning: Failed to create activities indices: %v", err)
-		}
-		if _, err := tx.Exec(sqlCreateLikesIndices); err != nil {
-			log.Printf("Warning: Failed to create likes indices: %v", err)
-		}
-		if _, err := tx.Exec(sqlCreateBoostsIndices); err != nil {
-			log.Printf("Warning: Failed to create boosts indices: %v", err)
-		}
-		if _, err := tx.Exec(sqlCreateDeliveryQueueIndices); err != nil {
-			log.Printf("Warning: Failed to create delivery_queue indices: %v", err)
-		}
-		if _, err := tx.Exec(sqlCreateHashtagsIndices); err != nil {
-			log.Printf("Warning: Failed to create hashtags indices: %v", err)
-		}
-		if _, err := tx.Exec(sqlCreateNoteHashtagsIndices); err != nil {
-			log.Printf("Warning: Failed to create note_hashtags indices: %v", err)
-		}
-		if _, err := tx.Exec(sqlCreateNoteMentionsIndices); err != nil {
-			log.Printf("Warning: Failed to create note_mentions indices: %v", err)
+	// Extend existing tables before indexing columns that older databases lack
+	if err := db.extendExistingTables(tx); err != nil {
+		return err
+	}
+
+	// Create indices
+	for _, index := range schemaIndices {
+		if _, err := tx.Exec(index.createSQL); err != nil {
+			return fmt.Errorf("failed to create %s indices: %w", index.name, err)
 		}
-		if _, err := tx.Exec(sqlCreateRelaysIndices); err != nil {
-			log.Printf("Warning: Failed to create relays indices: %v", err)
+	}
+
+	// Backfill object_uri for existing activities
+	if err := db.backfillActivityObjectURIs(tx); err != nil {
+		return fmt.Errorf("failed to backfill activity object_uri: %w", err)
+	}
+
+	// Add username uniqueness constraint (handles duplicates gracefully)
+	if err := db.addUsernameUniqueConstraint(tx); err != nil {
+		return fmt.Errorf("failed to add username unique constraint: %w", err)
+	}
+
+	// Backfill reply counts for existing notes and activities
+	if err := db.backfillReplyCounts(tx); err != nil {
+		return fmt.Errorf("failed to backfill reply counts: %w", err)
+	}
+
+	// Fix orphaned Update activities (convert to Create so they show in timeline)
+	if err := db.fixOrphanedUpdateActivities(tx); err != nil {
+		return fmt.Errorf("failed to fix orphaned Update activities: %w", err)
+	}
+
+	// Seed default info boxes if none exist
+	if err := db.seedDefaultInfoBoxes(tx); err != nil {
+		return fmt.Errorf("failed to seed default info boxes: %w", err)
+	}
+
+	return nil
+}
+
+// ReadSchemaVersion returns the schema version of the database: PRAGMA user_version on
+// SQLite (0 before RunMigrations recorded one), the last applied migration on PostgreSQL
+func (db *DB) ReadSchemaVersion() (int, error) {
+	query := "PRAGMA user_version"
+	if db.postgres {
+		query = "SELECT COALESCE(MAX(version), 0) FROM schema_migrations"
+	}
+	var version int
+	err := db.db.QueryRow(query).Scan(&version)
+	return version, err
+}
+
+// ReadSchemaStatus compares the database with the tables and columns the
+// migrations create and lists the applied migrations, without changing anything
+func (db *DB) ReadSchemaStatus() (error, *domain.SchemaStatus) {
+	status := &domain.SchemaStatus{}
+	tablesQuery := `SELECT name FROM sqlite_master WHERE type = 'table'`
+	if db.postgres {
+		status.JournalMode = "postgres"
+		tablesQuery = `SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema()`
+	} else if err := db.db.QueryRow("PRAGMA journal_mode").Scan(&status.JournalMode); err != nil {
+		return err, nil
+	}
+
+	existing := map[string]bool{}
+	rows, err := db.db.Query(tablesQuery)
+	if err != nil {
+		return err, nil
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err, nil
 		}
-		if _, err := tx.Exec(sqlCreateNotificationsIndices); err != nil {
-			log.Printf("Warning: Failed to create notifications indices: %v", err)
+		existing[name] = true
+	}
+	rows.Close()
+
+	for _, name := range []string{"accounts", "notes"} {
+		if existing[name] {
+			status.Tables++
+		} else {
+			status.MissingTables = append(status.MissingTables, name)
 		}
-		if _, err := tx.Exec(sqlCreateInfoBoxesIndices); err != nil {
-			log.Printf("Warning: Failed to create info_boxes indices: %v", err)
+	}
+	for _, table := range schemaTables {
+		if existing[table.name] {
+			status.Tables++
+		} else {
+			status.MissingTables = append(status.MissingTables, table.name)
 		}
-		if _, err := tx.Exec(sqlCreateUploadTokensIndices); err != nil {
-			log.Printf("Warning: Failed to create upload_tokens indices: %v", err)
+	}
+
+	// Columns of missing tables are created with the table
+	columns := map[string]map[string]bool{}
+	for _, c := range schemaColumns {
+		if !existing[c.table] {
+			continue
 		}
-		if _, err := tx.Exec(sqlCreateBansIndices); err != nil {
-			log.Printf("Warning: Failed to create bans indices: %v", err)
+		if columns[c.table] == nil {
+			names, err := db.readColumnNames(db.db, c.table)
+			if err != nil {
+				return err, nil
+			}
+			columns[c.table] = names
 		}
-		if _, err := tx.Exec(sqlCreateNotesIndices); err != nil {
-			log.Printf("Warning: Failed to create notes indices: %v", err)
+		if !columns[c.table][c.column] {
+			status.MissingColumns = append(status.MissingColumns, c.table+"."+c.column)
 		}
+	}
 
-		// Extend existing tables (ignore errors if columns already exist)
-		db.extendExistingTables(tx)
-
-		// Backfill object_uri for existing activities
-		if err := db.backfillActivityObjectURIs(tx); err != nil {
-			log.Printf("Warning: Failed to backfill activity object_uri: %v", err)
-		}
+	migrations, err := db.readMigrations()
+	if err != nil {
+		return err, nil
+	}
+	status.Migrations = migrations
 
-		// Add username uniqueness constraint (handles duplicates gracefully)
-		if err := db.addUsernameUniqueConstraint(tx); err != nil {
-			log.Printf("Warning: Failed to add username unique constraint: %v", err)
-		}
+	return nil, status
+}
 
-		// Backfill reply counts for existing notes and activities
-		if err := db.backfillReplyCounts(tx); err != nil {
-			log.Printf("Warning: Failed to backfill reply counts: %v", err)
-		}
+// queryer is a *sql.DB or a *sql.Tx
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
 
-		// Fix orphaned Update activities (convert to Create so they show in timeline)
-		if err := db.fixOrphanedUpdateActivities(tx); err != nil {
-			log.Printf("Warning: Failed to fix orphaned Update activities: %v", err)
-		}
+// readColumnNames returns the names of a table's columns
+func (db *DB) readColumnNames(q queryer, table string) (map[string]bool, error) {
+	var rows *sql.Rows
+	var err error
+	if db.postgres {
+		rows, err = q.Query(`SELECT column_name FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ?`, table)
+	} else {
+		rows, err = q.Query(fmt.Sprintf("SELECT name FROM pragma_table_info('%s')", table))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		// Seed default info boxes if none exist
-		if err := db.seedDefaultInfoBoxes(tx); err != nil {
-			log.Printf("Warning: Failed to seed default info boxes: %v", err)
+	names := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
 		}
-
-		return nil
-	})
+		names[name] = true
+	}
+	return names, rows.Err()
 }
 
 func (db *DB) createTableIfNotExists(tx *sql.Tx, createSQL string, tableName string) error {
@@ -427,72 +748,96 @@ func (db *DB) createTableIfNotExists(tx *sql.Tx, createSQL string, tableName str
 	return nil
 }
 
-func (db *DB) extendExistingTables(tx *sql.Tx) {
-	// Try to add columns to accounts table (ignore errors if they exist)
-	tx.Exec("ALTER TABLE accounts ADD COLUMN display_name TEXT")
-	tx.Exec("ALTER TABLE accounts ADD COLUMN summary TEXT")
-	tx.Exec("ALTER TABLE accounts ADD COLUMN avatar_url TEXT")
-	tx.Exec("ALTER TABLE accounts ADD COLUMN is_admin INTEGER DEFAULT 0")
-	tx.Exec("ALTER TABLE accounts ADD COLUMN muted INTEGER DEFAULT 0")
-	tx.Exec("ALTER TABLE accounts ADD COLUMN banned INTEGER DEFAULT 0")
-	tx.Exec("ALTER TABLE accounts ADD COLUMN last_ip TEXT")
-
-	// Try to add columns to notes table (ignore errors if they exist)
-	tx.Exec("ALTER TABLE notes ADD COLUMN visibility TEXT DEFAULT 'public'")
-	tx.Exec("ALTER TABLE notes ADD COLUMN in_reply_to_uri TEXT")
-	tx.Exec("ALTER TABLE notes ADD COLUMN object_uri TEXT")
-	tx.Exec("ALTER TABLE notes ADD COLUMN federated INTEGER DEFAULT 1")
-	tx.Exec("ALTER TABLE notes ADD COLUMN sensitive INTEGER DEFAULT 0")
-	tx.Exec("ALTER TABLE notes ADD COLUMN content_warning TEXT")
-	tx.Exec("ALTER TABLE notes ADD COLUMN edited_at TIMESTAMP")
-
+// schemaColumns are the columns added to existing tables by extendExistingTables
+var schemaColumns = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	// Profile and admin columns for accounts
+	{"accounts", "display_name", "TEXT"},
+	{"accounts", "summary", "TEXT"},
+	{"accounts", "avatar_url", "TEXT"},
+	{"accounts", "is_admin", "INTEGER DEFAULT 0"},
+	{"accounts", "muted", "INTEGER DEFAULT 0"},
+	{"accounts", "banned", "INTEGER DEFAULT 0"},
+	{"accounts", "last_ip", "TEXT"},
+	// Federation, visibility and article columns for notes
+	{"notes", "visibility", "TEXT DEFAULT 'public'"},
+	{"notes", "in_reply_to_uri", "TEXT"},
+	{"notes", "object_uri", "TEXT"},
+	{"notes", "federated", "INTEGER DEFAULT 1"},
+	{"notes", "sensitive", "INTEGER DEFAULT 0"},
+	{"notes", "content_warning", "TEXT"},
+	{"notes", "edited_at", "TIMESTAMP"},
+	{"notes", "title", "TEXT"},
+	// Add title column to drafts table so article drafts keep their title
+	{"drafts", "title", "TEXT NOT NULL DEFAULT ''"},
 	// Engagement count columns for notes (denormalized for performance)
-	tx.Exec("ALTER TABLE notes ADD COLUMN reply_count INTEGER DEFAULT 0")
-	tx.Exec("ALTER TABLE notes ADD COLUMN like_count INTEGER DEFAULT 0")
-	tx.Exec("ALTER TABLE notes ADD COLUMN boost_count INTEGER DEFAULT 0")
-
+	{"notes", "reply_count", "INTEGER DEFAULT 0"},
+	{"notes", "like_count", "INTEGER DEFAULT 0"},
+	{"notes", "boost_count", "INTEGER DEFAULT 0"},
 	// Engagement count columns for activities (remote posts)
-	tx.Exec("ALTER TABLE activities ADD COLUMN reply_count INTEGER DEFAULT 0")
-	tx.Exec("ALTER TABLE activities ADD COLUMN like_count INTEGER DEFAULT 0")
-	tx.Exec("ALTER TABLE activities ADD COLUMN boost_count INTEGER DEFAULT 0")
-
+	{"activities", "reply_count", "INTEGER DEFAULT 0"},
+	{"activities", "like_count", "INTEGER DEFAULT 0"},
+	{"activities", "boost_count", "INTEGER DEFAULT 0"},
 	// Add is_local column to follows table to support local follows
-	tx.Exec("ALTER TABLE follows ADD COLUMN is_local INTEGER DEFAULT 0")
-
+	{"follows", "is_local", "INTEGER DEFAULT 0"},
 	// Add account_id column to delivery_queue table to support account-based cleanup
-	tx.Exec("ALTER TABLE delivery_queue ADD COLUMN account_id TEXT")
-
+	{"delivery_queue", "account_id", "TEXT"},
 	// Add object_uri column to likes table for remote post likes
-	tx.Exec("ALTER TABLE likes ADD COLUMN object_uri TEXT")
-
-	// Add unique index for remote post likes (account_id + object_uri)
-	// This allows one like per account per remote post (identified by object_uri)
-	tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_likes_account_object_uri ON likes(account_id, object_uri) WHERE object_uri IS NOT NULL AND object_uri != ''")
-
+	{"likes", "object_uri", "TEXT"},
 	// Add object_uri column to boosts table for remote post boosts
-	tx.Exec("ALTER TABLE boosts ADD COLUMN object_uri TEXT")
-
-	// Add unique index for remote post boosts (account_id + object_uri)
-	tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_boosts_account_object_uri ON boosts(account_id, object_uri) WHERE object_uri IS NOT NULL AND object_uri != ''")
-
+	{"boosts", "object_uri", "TEXT"},
 	// Add follow_uri column to relays table for proper Undo Follow
-	tx.Exec("ALTER TABLE relays ADD COLUMN follow_uri TEXT")
-
+	{"relays", "follow_uri", "TEXT"},
 	// Add paused column to relays table for pause/resume functionality
-	tx.Exec("ALTER TABLE relays ADD COLUMN paused INTEGER DEFAULT 0")
-
+	{"relays", "paused", "INTEGER DEFAULT 0"},
 	// Add from_relay column to activities table to track relay-forwarded content
-	tx.Exec("ALTER TABLE activities ADD COLUMN from_relay INTEGER DEFAULT 0")
-
+	{"activities", "from_relay", "INTEGER DEFAULT 0"},
 	// Add web_enabled column to server_message table for separate web UI toggle
-	tx.Exec("ALTER TABLE server_message ADD COLUMN web_enabled INTEGER NOT NULL DEFAULT 1")
-
+	{"server_message", "web_enabled", "INTEGER NOT NULL DEFAULT 1"},
 	// Add remote_account_id column to boosts table for tracking boosts from remote followed users
-	tx.Exec("ALTER TABLE boosts ADD COLUMN remote_account_id TEXT")
-	tx.Exec("CREATE INDEX IF NOT EXISTS idx_boosts_remote_account_id ON boosts(remote_account_id)")
-	tx.Exec("CREATE INDEX IF NOT EXISTS idx_boosts_remote_created ON boosts(remote_account_id, created_at DESC)")
+	{"boosts", "remote_account_id", "TEXT"},
+	// Add emoji column to notifications table for reaction notifications
+	{"notifications", "emoji", "TEXT"},
+	// Add backfilled column to activities table to mark posts fetched from outboxes after a follow
+	{"activities", "backfilled", "INTEGER DEFAULT 0"},
+	// Add in_reply_to and object_url columns to activities for reply lookups and web links
+	{"activities", "in_reply_to", "TEXT"},
+	{"activities", "object_url", "TEXT"},
+	// Add refresh tracking columns to remote_accounts for the periodic actor refresh
+	{"remote_accounts", "fetch_failures", "INTEGER DEFAULT 0"},
+	{"remote_accounts", "last_failure_at", "TIMESTAMP"},
+	{"remote_accounts", "gone_at", "TIMESTAMP"},
+}
+
+// extendExistingTables adds the schemaColumns that tables created by older versions lack
+func (db *DB) extendExistingTables(tx *sql.Tx) error {
+	columns := map[string]map[string]bool{}
+	added := 0
+	for _, c := range schemaColumns {
+		if columns[c.table] == nil {
+			names, err := db.readColumnNames(tx, c.table)
+			if err != nil {
+				return fmt.Errorf("failed to read columns of %s: %w", c.table, err)
+			}
+			columns[c.table] = names
+		}
+		if columns[c.table][c.column] {
+			continue
+		}
+		if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", c.table, c.column, err)
+		}
+		columns[c.table][c.column] = true
+		added++
+	}
 
-	log.Println("Extended existing tables with new columns")
+	if added > 0 {
+		log.Printf("Extended existing tables with %d new columns", added)
+	}
+	return nil
 }
 
 // backfillActivityObjectURIs extracts object_uri from raw_json for activities that are missing it
@@ -763,79 +1108,39 @@ func (db *DB) fixOrphanedUpdateActivities(tx *sql.Tx) error {
 	return nil
 }
 
-// MigratePerformanceIndexes adds performance-critical indexes that were missing
-// These indexes speed up threading queries and relay content filtering
-func (db *DB) MigratePerformanceIndexes() error {
-	log.Println("Checking for missing performance indexes...")
-
-	// Add index on notes.in_reply_to_uri for faster threading queries
-	_, err := db.db.Exec(`CREATE INDEX IF NOT EXISTS idx_notes_in_reply_to_uri ON notes(in_reply_to_uri)`)
-	if err != nil {
-		log.Printf("Warning: Failed to create idx_notes_in_reply_to_uri: %v", err)
-	}
-
-	// Add index on activities.object_uri for faster deduplication checks
-	_, err = db.db.Exec(`CREATE INDEX IF NOT EXISTS idx_activities_object_uri ON activities(object_uri)`)
-	if err != nil {
-		log.Printf("Warning: Failed to create idx_activities_object_uri: %v", err)
-	}
-
-	// Add index on activities.from_relay for faster relay content filtering
-	_, err = db.db.Exec(`CREATE INDEX IF NOT EXISTS idx_activities_from_relay ON activities(from_relay)`)
-	if err != nil {
-		log.Printf("Warning: Failed to create idx_activities_from_relay: %v", err)
-	}
-
-	// Add in_reply_to column to activities for faster reply queries (avoids LIKE on raw_json)
-	_, err = db.db.Exec(`ALTER TABLE activities ADD COLUMN in_reply_to TEXT`)
-	if err != nil {
-		// Column likely already exists, which is fine
-		if !strings.Contains(err.Error(), "duplicate column") {
-			log.Printf("Note: in_reply_to column may already exist: %v", err)
-		}
-	} else {
-		log.Println("Added in_reply_to column to activities table")
-	}
-
-	// Create index on in_reply_to for fast reply lookups
-	_, err = db.db.Exec(`CREATE INDEX IF NOT EXISTS idx_activities_in_reply_to ON activities(in_reply_to)`)
-	if err != nil {
-		log.Printf("Warning: Failed to create idx_activities_in_reply_to: %v", err)
-	}
-
-	// Backfill in_reply_to from raw_json for existing activities
-	db.backfillActivitiesInReplyTo()
+// Queries of the activity in_reply_to and object_url backfill
+const (
+	sqlSelectActivitiesWithoutInReplyTo = `
+		SELECT id, raw_json FROM activities
+		WHERE in_reply_to IS NULL
+		AND activity_type = 'Create'
+		AND (raw_json LIKE '%"inReplyTo":%' OR raw_json LIKE '%"inReplyTo" :%')
+	`
+	sqlUpdateActivityInReplyTo          = `UPDATE activities SET in_reply_to = ? WHERE id = ?`
+	sqlSelectActivitiesWithoutObjectURL = `
+		SELECT id, raw_json FROM activities
+		WHERE object_url IS NULL
+		AND activity_type = 'Create'
+		AND raw_json LIKE '%"url":%'
+	`
+	sqlUpdateActivityObjectURL = `UPDATE activities SET object_url = ? WHERE id = ?`
+)
 
-	// Add object_url column to activities for human-readable web links (vs object_uri which is the AP id)
-	_, err = db.db.Exec(`ALTER TABLE activities ADD COLUMN object_url TEXT`)
-	if err != nil {
-		// Column likely already exists, which is fine
-		if !strings.Contains(err.Error(), "duplicate column") {
-			log.Printf("Note: object_url column may already exist: %v", err)
-		}
-	} else {
-		log.Println("Added object_url column to activities table")
+// backfillActivityRepliesAndURLs fills the in_reply_to and object_url columns of
+// activities stored before they existed from their raw JSON
+func (db *DB) backfillActivityRepliesAndURLs(tx *sql.Tx) error {
+	if err := db.backfillActivitiesInReplyTo(tx); err != nil {
+		return err
 	}
-
-	// Backfill object_url from raw_json for existing activities
-	db.backfillActivitiesObjectURL()
-
-	log.Println("Performance indexes migration complete")
-	return nil
+	return db.backfillActivitiesObjectURL(tx)
 }
 
 // backfillActivitiesInReplyTo extracts inReplyTo from raw_json and populates the in_reply_to column
-func (db *DB) backfillActivitiesInReplyTo() {
+func (db *DB) backfillActivitiesInReplyTo(tx *sql.Tx) error {
 	// Only backfill rows where in_reply_to is NULL but raw_json contains inReplyTo
-	rows, err := db.db.Query(`
-		SELECT id, raw_json FROM activities
-		WHERE in_reply_to IS NULL
-		AND activity_type = 'Create'
-		AND (raw_json LIKE '%"inReplyTo":%' OR raw_json LIKE '%"inReplyTo" :%')
-	`)
+	rows, err := tx.Query(sqlSelectActivitiesWithoutInReplyTo)
 	if err != nil {
-		log.Printf("Warning: Failed to query activities for in_reply_to backfill: %v", err)
-		return
+		return fmt.Errorf("failed to query activities for in_reply_to backfill: %w", err)
 	}
 	defer rows.Close()
 
@@ -849,7 +1154,7 @@ func (db *DB) backfillActivitiesInReplyTo() {
 		// Extract inReplyTo from JSON
 		inReplyTo := extractInReplyToFromJSON(rawJSON)
 		if inReplyTo != "" {
-			_, err := db.db.Exec(`UPDATE activities SET in_reply_to = ? WHERE id = ?`, inReplyTo, id)
+			_, err := tx.Exec(sqlUpdateActivityInReplyTo, inReplyTo, id)
 			if err == nil {
 				updated++
 			}
@@ -859,20 +1164,15 @@ func (db *DB) backfillActivitiesInReplyTo() {
 	if updated > 0 {
 		log.Printf("Backfilled in_reply_to for %d activities", updated)
 	}
+	return rows.Err()
 }
 
 // backfillActivitiesObjectURL extracts url from raw_json and populates the object_url column
-func (db *DB) backfillActivitiesObjectURL() {
+func (db *DB) backfillActivitiesObjectURL(tx *sql.Tx) error {
 	// Only backfill rows where object_url is NULL but raw_json contains url
-	rows, err := db.db.Query(`
-		SELECT id, raw_json FROM activities
-		WHERE object_url IS NULL
-		AND activity_type = 'Create'
-		AND raw_json LIKE '%"url":%'
-	`)
+	rows, err := tx.Query(sqlSelectActivitiesWithoutObjectURL)
 	if err != nil {
-		log.Printf("Warning: Failed to query activities for object_url backfill: %v", err)
-		return
+		return fmt.Errorf("failed to query activities for object_url backfill: %w", err)
 	}
 	defer rows.Close()
 
@@ -886,7 +1186,7 @@ func (db *DB) backfillActivitiesObjectURL() {
 		// Extract url from JSON
 		objectURL := extractObjectURLFromJSON(rawJSON)
 		if objectURL != "" {
-			_, err := db.db.Exec(`UPDATE activities SET object_url = ? WHERE id = ?`, objectURL, id)
+			_, err := tx.Exec(sqlUpdateActivityObjectURL, objectURL, id)
 			if err == nil {
 				updated++
 			}
@@ -896,6 +1196,7 @@ func (db *DB) backfillActivitiesObjectURL() {
 	if updated > 0 {
 		log.Printf("Backfilled object_url for %d activities", updated)
 	}
+	return rows.Err()
 }
 
 // extractObjectURLFromJSON extracts the object.url field from ActivityPub JSON
@@ -914,31 +1215,15 @@ func extractObjectURLFromJSON(rawJSON string) string {
 	return ""
 }
 
-// seedDefaultInfoBoxes creates default info boxes on first run
-func (db *DB) seedDefaultInfoBoxes(tx *sql.Tx) error {
-	// Check if any info boxes already exist
-	var count int
-	err := tx.QueryRow(`SELECT COUNT(*) FROM info_boxes`).Scan(&count)
-	if err != nil {
-		return fmt.Errorf("failed to check existing info boxes: %w", err)
-	}
-
-	if count > 0 {
-		log.Println("Info boxes already exist, skipping seed")
-		return nil
-	}
-
-	log.Println("Seeding default info boxes...")
-
-	// Default info boxes based on current template
-	defaultBoxes := []struct {
-		title    string
-		content  string
-		orderNum int
-	}{
-		{
-			title: "ssh-first fediverse blog",
-			content: `Connect via SSH to start posting:
+// defaultInfoBoxes are seeded on first run, based on the original template
+var defaultInfoBoxes = []struct {
+	title    string
+	content  string
+	orderNum int
+}{
+	{
+		title: "ssh-first fediverse blog",
+		content: `Connect via SSH to start posting:
 
 ` + "```" + `
 ssh -p {{SSH_PORT}} YourIpOrDomain
@@ -951,34 +1236,62 @@ On federated services like Mastodon people now can follow you, when searching fo
 ` + "```" + `
 @YourUser@YourSslDomain.com
 ` + "```" + ``,
-			orderNum: 1,
-		},
-		{
-			title: "features",
-			content: `- Create and read posts
+		orderNum: 1,
+	},
+	{
+		title: "features",
+		content: `- Create and read posts
 - Follow local and remote users
 - ActivityPub federation
 - [RSS feeds](/feed)`,
-			orderNum: 2,
-		},
-		{
-			title:    `<svg style="width: 1.2em; height: 1.2em; vertical-align: middle; margin-right: 8px;" viewBox="0 0 16 16" fill="currentColor"><path d="M8 0C3.58 0 0 3.58 0 8c0 3.54 2.29 6.53 5.47 7.59.4.07.55-.17.55-.38 0-.19-.01-.82-.01-1.49-2.01.37-2.53-.49-2.69-.94-.09-.23-.48-.94-.82-1.13-.28-.15-.68-.52-.01-.53.63-.01 1.08.58 1.23.82.72 1.21 1.87.87 2.33.66.07-.52.28-.87.51-1.07-1.78-.2-3.64-.89-3.64-3.95 0-.87.31-1.59.82-2.15-.08-.2-.36-1.02.08-2.12 0 0 .67-.21 2.2.82.64-.18 1.32-.27 2-.27.68 0 1.36.09 2 .27 1.53-1.04 2.2-.82 2.2-.82.44 1.1.16 1.92.08 2.12.51.56.82 1.27.82 2.15 0 3.07-1.87 3.75-3.65 3.95.29.25.54.73.54 1.48 0 1.07-.01 1.93-.01 2.2 0 .21.15.46.55.38A8.013 8.013 0 0016 8c0-4.42-3.58-8-8-8z"/></svg>github`,
-			content:  `Source and Documentation available on [Github](https://github.com/deemkeen/stegodon)`,
-			orderNum: 3,
-		},
+		orderNum: 2,
+	},
+	{
+		title:    `<svg style="width: 1.2em; height: 1.2em; vertical-align: middle; margin-right: 8px;" viewBox="0 0 16 16" fill="currentColor"><path d="M8 0C3.58 0 0 3.58 0 8c0 3.54 2.29 6.53 5.47 7.59.4.07.55-.17.55-.38 0-.19-.01-.82-.01-1.49-2.01.37-2.53-.49-2.69-.94-.09-.23-.48-.94-.82-1.13-.28-.15-.68-.52-.01-.53.63-.01 1.08.58 1.23.82.72 1.21 1.87.87 2.33.66.07-.52.28-.87.51-1.07-1.78-.2-3.64-.89-3.64-3.95 0-.87.31-1.59.82-2.15-.08-.2-.36-1.02.08-2.12 0 0 .67-.21 2.2.82.64-.18 1.32-.27 2-.27.68 0 1.36.09 2 .27 1.53-1.04 2.2-.82 2.2-.82.44 1.1.16 1.92.08 2.12.51.56.82 1.27.82 2.15 0 3.07-1.87 3.75-3.65 3.95.29.25.54.73.54 1.48 0 1.07-.01 1.93-.01 2.2 0 .21.15.46.55.38A8.013 8.013 0 0016 8c0-4.42-3.58-8-8-8z"/></svg>github`,
+		content:  `Source and Documentation available on [Github](https://github.com/deemkeen/stegodon)`,
+		orderNum: 3,
+	},
+}
+
+// sqlInsertDefaultInfoBox seeds one of defaultInfoBoxes
+const sqlInsertDefaultInfoBox = `
+	INSERT INTO info_boxes (id, title, content, order_num, enabled, created_at, updated_at)
+	VALUES (?, ?, ?, ?, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+`
+
+// defaultInfoBoxesBody is what seedDefaultInfoBoxes inserts, the body of its migration's checksum
+func defaultInfoBoxesBody() []string {
+	body := []string{sqlInsertDefaultInfoBox}
+	for _, box := range defaultInfoBoxes {
+		body = append(body, fmt.Sprintf("%d %s\n%s", box.orderNum, box.title, box.content))
+	}
+	return body
+}
+
+// seedDefaultInfoBoxes creates default info boxes on first run
+func (db *DB) seedDefaultInfoBoxes(tx *sql.Tx) error {
+	// Check if any info boxes already exist
+	var count int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM info_boxes`).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to check existing info boxes: %w", err)
 	}
 
-	for _, box := range defaultBoxes {
+	if count > 0 {
+		log.Println("Info boxes already exist, skipping seed")
+		return nil
+	}
+
+	log.Println("Seeding default info boxes...")
+
+	for _, box := range defaultInfoBoxes {
 		id := uuid.New()
-		_, err := tx.Exec(`
-			INSERT INTO info_boxes (id, title, content, order_num, enabled, created_at, updated_at)
-			VALUES (?, ?, ?, ?, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		`, id.String(), box.title, box.content, box.orderNum)
+		_, err := tx.Exec(sqlInsertDefaultInfoBox, id.String(), box.title, box.content, box.orderNum)
 		if err != nil {
 			return fmt.Errorf("failed to seed info box '%s': %w", box.title, err)
 		}
 	}
 
-	log.Printf("Seeded %d default info boxes", len(defaultBoxes))
+	log.Printf("Seeded %d default info boxes", len(defaultInfoBoxes))
 	return nil
 }
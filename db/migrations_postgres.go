@@ -0,0 +1,394 @@
+package db
+
+// postgresMigrations is the PostgreSQL schema history, see migration. The version
+// numbers are independent of the SQLite ones.
+//
+// The tables match what the SQLite migrations produce, with PostgreSQL types: ids are
+// TEXT like in SQLite, flags stay INTEGER, and TIMESTAMP columns are TIMESTAMPTZ so
+// that values written with an offset keep it.
+var postgresMigrations = []migration{
+	{version: 1, name: "initial schema", statements: []string{
+		`CREATE TABLE accounts (
+			id TEXT NOT NULL PRIMARY KEY,
+			username TEXT NOT NULL UNIQUE,
+			publickey TEXT UNIQUE,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			first_time_login INTEGER DEFAULT 1,
+			web_public_key TEXT,
+			web_private_key TEXT,
+			display_name TEXT,
+			summary TEXT,
+			avatar_url TEXT,
+			is_admin INTEGER DEFAULT 0,
+			muted INTEGER DEFAULT 0,
+			banned INTEGER DEFAULT 0,
+			last_ip TEXT
+		)`,
+		`CREATE UNIQUE INDEX idx_accounts_username ON accounts(LOWER(username))`,
+
+		`CREATE TABLE notes (
+			id TEXT NOT NULL PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			message TEXT,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			visibility TEXT DEFAULT 'public',
+			in_reply_to_uri TEXT,
+			object_uri TEXT,
+			federated INTEGER DEFAULT 1,
+			sensitive INTEGER DEFAULT 0,
+			content_warning TEXT,
+			edited_at TIMESTAMPTZ,
+			title TEXT,
+			reply_count INTEGER DEFAULT 0,
+			like_count INTEGER DEFAULT 0,
+			boost_count INTEGER DEFAULT 0
+		)`,
+		`CREATE INDEX idx_notes_created_at ON notes(created_at DESC)`,
+		`CREATE INDEX idx_notes_in_reply_to_uri ON notes(in_reply_to_uri)`,
+		`CREATE INDEX idx_notes_user_id ON notes(user_id)`,
+
+		`CREATE TABLE follows (
+			id TEXT NOT NULL PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			target_account_id TEXT NOT NULL,
+			uri TEXT NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			accepted INTEGER DEFAULT 0,
+			is_local INTEGER DEFAULT 0,
+			UNIQUE(account_id, target_account_id)
+		)`,
+		`CREATE INDEX idx_follows_account_id ON follows(account_id)`,
+		`CREATE INDEX idx_follows_target_account_id ON follows(target_account_id)`,
+		`CREATE INDEX idx_follows_uri ON follows(uri)`,
+
+		`CREATE TABLE remote_accounts (
+			id TEXT NOT NULL PRIMARY KEY,
+			username TEXT NOT NULL,
+			domain TEXT NOT NULL,
+			actor_uri TEXT UNIQUE NOT NULL,
+			display_name TEXT,
+			summary TEXT,
+			inbox_uri TEXT NOT NULL,
+			outbox_uri TEXT,
+			public_key_pem TEXT NOT NULL,
+			avatar_url TEXT,
+			last_fetched_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			fetch_failures INTEGER DEFAULT 0,
+			last_failure_at TIMESTAMPTZ,
+			gone_at TIMESTAMPTZ,
+			UNIQUE(username, domain)
+		)`,
+		`CREATE INDEX idx_remote_accounts_actor_uri ON remote_accounts(actor_uri)`,
+		`CREATE INDEX idx_remote_accounts_domain ON remote_accounts(domain)`,
+
+		`CREATE TABLE activities (
+			id TEXT NOT NULL PRIMARY KEY,
+			activity_uri TEXT UNIQUE NOT NULL,
+			activity_type TEXT NOT NULL,
+			actor_uri TEXT NOT NULL,
+			object_uri TEXT,
+			object_url TEXT,
+			in_reply_to TEXT,
+			raw_json TEXT NOT NULL,
+			processed INTEGER DEFAULT 0,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			local INTEGER DEFAULT 0,
+			reply_count INTEGER DEFAULT 0,
+			like_count INTEGER DEFAULT 0,
+			boost_count INTEGER DEFAULT 0,
+			from_relay INTEGER DEFAULT 0,
+			backfilled INTEGER DEFAULT 0
+		)`,
+		`CREATE INDEX idx_activities_created_at ON activities(created_at DESC)`,
+		`CREATE INDEX idx_activities_from_relay ON activities(from_relay)`,
+		`CREATE INDEX idx_activities_in_reply_to ON activities(in_reply_to)`,
+		`CREATE INDEX idx_activities_object_uri ON activities(object_uri)`,
+		`CREATE INDEX idx_activities_processed ON activities(processed)`,
+		`CREATE INDEX idx_activities_type ON activities(activity_type)`,
+
+		`CREATE TABLE likes (
+			id TEXT NOT NULL PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			note_id TEXT NOT NULL,
+			uri TEXT NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			object_uri TEXT,
+			UNIQUE(account_id, note_id)
+		)`,
+		`CREATE INDEX idx_likes_account_id ON likes(account_id)`,
+		`CREATE INDEX idx_likes_note_id ON likes(note_id)`,
+		`CREATE UNIQUE INDEX idx_likes_account_object_uri ON likes(account_id, object_uri) WHERE object_uri IS NOT NULL AND object_uri != ''`,
+
+		`CREATE TABLE reactions (
+			id TEXT NOT NULL PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			note_id TEXT,
+			object_uri TEXT,
+			emoji TEXT NOT NULL,
+			uri TEXT NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE UNIQUE INDEX idx_reactions_account_note ON reactions(account_id, note_id) WHERE note_id IS NOT NULL`,
+		`CREATE UNIQUE INDEX idx_reactions_account_object_uri ON reactions(account_id, object_uri) WHERE note_id IS NULL`,
+		`CREATE INDEX idx_reactions_note_id ON reactions(note_id)`,
+		`CREATE INDEX idx_reactions_object_uri ON reactions(object_uri)`,
+		`CREATE INDEX idx_reactions_uri ON reactions(uri)`,
+
+		`CREATE TABLE boosts (
+			id TEXT NOT NULL PRIMARY KEY,
+			account_id TEXT,
+			remote_account_id TEXT,
+			note_id TEXT NOT NULL,
+			object_uri TEXT,
+			uri TEXT NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(account_id, note_id)
+		)`,
+		`CREATE INDEX idx_boosts_account_id ON boosts(account_id)`,
+		`CREATE INDEX idx_boosts_note_id ON boosts(note_id)`,
+		`CREATE INDEX idx_boosts_object_uri ON boosts(object_uri)`,
+		`CREATE INDEX idx_boosts_remote_account_id ON boosts(remote_account_id)`,
+		`CREATE INDEX idx_boosts_remote_created ON boosts(remote_account_id, created_at DESC)`,
+		`CREATE UNIQUE INDEX idx_boosts_account_object_uri ON boosts(account_id, object_uri) WHERE object_uri IS NOT NULL AND object_uri != ''`,
+
+		`CREATE TABLE delivery_queue (
+			id TEXT NOT NULL PRIMARY KEY,
+			inbox_uri TEXT NOT NULL,
+			activity_json TEXT NOT NULL,
+			attempts INTEGER DEFAULT 0,
+			next_retry_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			account_id TEXT
+		)`,
+		`CREATE INDEX idx_delivery_queue_next_retry ON delivery_queue(next_retry_at)`,
+
+		`CREATE TABLE inbox_queue (
+			id TEXT NOT NULL PRIMARY KEY,
+			username TEXT NOT NULL,
+			activity_json TEXT NOT NULL,
+			activity_type TEXT NOT NULL,
+			actor_uri TEXT NOT NULL,
+			signer_uri TEXT NOT NULL,
+			attempts INTEGER DEFAULT 0,
+			next_retry_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			status TEXT NOT NULL DEFAULT 'pending',
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX idx_inbox_queue_actor ON inbox_queue(actor_uri, created_at)`,
+		`CREATE INDEX idx_inbox_queue_status_next_retry ON inbox_queue(status, next_retry_at)`,
+
+		`CREATE TABLE hashtags (
+			id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+			name TEXT NOT NULL UNIQUE,
+			usage_count INTEGER DEFAULT 0,
+			last_used_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX idx_hashtags_usage ON hashtags(usage_count DESC)`,
+
+		`CREATE TABLE note_hashtags (
+			note_id TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+			hashtag_id BIGINT NOT NULL REFERENCES hashtags(id) ON DELETE CASCADE,
+			PRIMARY KEY (note_id, hashtag_id)
+		)`,
+		`CREATE INDEX idx_note_hashtags_hashtag_id ON note_hashtags(hashtag_id)`,
+
+		`CREATE TABLE followed_hashtags (
+			id TEXT NOT NULL PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			hashtag TEXT NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(account_id, hashtag)
+		)`,
+		`CREATE INDEX idx_followed_hashtags_hashtag ON followed_hashtags(hashtag)`,
+
+		`CREATE TABLE note_mentions (
+			id TEXT PRIMARY KEY,
+			note_id TEXT NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+			mentioned_actor_uri TEXT NOT NULL,
+			mentioned_username TEXT NOT NULL,
+			mentioned_domain TEXT NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX idx_note_mentions_actor_uri ON note_mentions(mentioned_actor_uri)`,
+		`CREATE INDEX idx_note_mentions_note_id ON note_mentions(note_id)`,
+
+		`CREATE TABLE lists (
+			id TEXT NOT NULL PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			exclude_from_home INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(account_id, name)
+		)`,
+		`CREATE TABLE list_members (
+			list_id TEXT NOT NULL,
+			member_id TEXT NOT NULL,
+			is_local INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY(list_id, member_id)
+		)`,
+		`CREATE INDEX idx_list_members_member_id ON list_members(member_id)`,
+
+		`CREATE TABLE filters (
+			id TEXT NOT NULL PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			phrase TEXT NOT NULL,
+			whole_word INTEGER NOT NULL DEFAULT 0,
+			contexts TEXT NOT NULL,
+			action TEXT NOT NULL DEFAULT 'hide',
+			expires_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX idx_filters_account_id ON filters(account_id)`,
+
+		`CREATE TABLE scheduled_notes (
+			id TEXT NOT NULL PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			message TEXT NOT NULL,
+			scheduled_at TEXT NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX idx_scheduled_notes_account_id ON scheduled_notes(account_id)`,
+		`CREATE INDEX idx_scheduled_notes_scheduled_at ON scheduled_notes(scheduled_at)`,
+
+		`CREATE TABLE drafts (
+			id TEXT NOT NULL PRIMARY KEY,
+			account_id TEXT NOT NULL,
+			message TEXT NOT NULL,
+			title TEXT NOT NULL DEFAULT '',
+			in_reply_to_uri TEXT NOT NULL DEFAULT '',
+			reply_to_author TEXT NOT NULL DEFAULT '',
+			reply_to_preview TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL
+		)`,
+		`CREATE INDEX idx_drafts_account_id ON drafts(account_id, updated_at)`,
+
+		`CREATE TABLE oauth_apps (
+			id TEXT NOT NULL PRIMARY KEY,
+			client_id TEXT NOT NULL UNIQUE,
+			client_secret TEXT NOT NULL,
+			name TEXT NOT NULL,
+			website TEXT NOT NULL DEFAULT '',
+			redirect_uris TEXT NOT NULL,
+			scopes TEXT NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE oauth_authorizations (
+			id TEXT NOT NULL PRIMARY KEY,
+			app_id TEXT NOT NULL,
+			user_code TEXT NOT NULL,
+			redirect_uri TEXT NOT NULL,
+			scopes TEXT NOT NULL,
+			state TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL DEFAULT 'pending',
+			account_id TEXT,
+			code TEXT,
+			expires_at TIMESTAMPTZ NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX idx_oauth_authorizations_code ON oauth_authorizations(code)`,
+		`CREATE INDEX idx_oauth_authorizations_user_code ON oauth_authorizations(user_code)`,
+		`CREATE TABLE oauth_tokens (
+			id TEXT NOT NULL PRIMARY KEY,
+			token_hash TEXT NOT NULL UNIQUE,
+			app_id TEXT NOT NULL,
+			account_id TEXT,
+			scopes TEXT NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			last_used_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX idx_oauth_tokens_account_id ON oauth_tokens(account_id)`,
+
+		`CREATE TABLE relays (
+			id TEXT NOT NULL PRIMARY KEY,
+			actor_uri TEXT UNIQUE NOT NULL,
+			inbox_uri TEXT NOT NULL,
+			follow_uri TEXT,
+			name TEXT,
+			status TEXT DEFAULT 'pending',
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			accepted_at TIMESTAMPTZ,
+			paused INTEGER DEFAULT 0
+		)`,
+		`CREATE INDEX idx_relays_status ON relays(status)`,
+
+		`CREATE TABLE notifications (
+			id TEXT NOT NULL PRIMARY KEY,
+			account_id TEXT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+			notification_type TEXT NOT NULL,
+			actor_id TEXT,
+			actor_username TEXT,
+			actor_domain TEXT,
+			note_id TEXT,
+			note_uri TEXT,
+			note_preview TEXT,
+			emoji TEXT,
+			read INTEGER DEFAULT 0,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX idx_notifications_account_id ON notifications(account_id)`,
+		`CREATE INDEX idx_notifications_account_read ON notifications(account_id, read)`,
+		`CREATE INDEX idx_notifications_created_at ON notifications(created_at DESC)`,
+
+		`CREATE TABLE info_boxes (
+			id TEXT NOT NULL PRIMARY KEY,
+			title TEXT NOT NULL,
+			content TEXT NOT NULL,
+			order_num INTEGER DEFAULT 0,
+			enabled INTEGER DEFAULT 1,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX idx_info_boxes_enabled ON info_boxes(enabled)`,
+		`CREATE INDEX idx_info_boxes_order ON info_boxes(order_num)`,
+
+		`CREATE TABLE upload_tokens (
+			token TEXT NOT NULL PRIMARY KEY,
+			account_id TEXT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+			token_type TEXT NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			expires_at TIMESTAMPTZ NOT NULL
+		)`,
+		`CREATE INDEX idx_upload_tokens_account_id ON upload_tokens(account_id)`,
+		`CREATE INDEX idx_upload_tokens_expires_at ON upload_tokens(expires_at)`,
+
+		`CREATE TABLE server_message (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			message TEXT NOT NULL DEFAULT '',
+			enabled INTEGER NOT NULL DEFAULT 0,
+			web_enabled INTEGER NOT NULL DEFAULT 1,
+			updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+
+		`CREATE TABLE bans (
+			id TEXT NOT NULL PRIMARY KEY,
+			username TEXT NOT NULL,
+			ip_address TEXT NOT NULL,
+			public_key_hash TEXT NOT NULL,
+			reason TEXT NOT NULL DEFAULT 'Banned by administrator',
+			banned_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX idx_bans_ip_address ON bans(ip_address)`,
+		`CREATE INDEX idx_bans_public_key_hash ON bans(public_key_hash)`,
+
+		`CREATE TABLE custom_emojis (
+			id TEXT NOT NULL PRIMARY KEY,
+			shortcode TEXT NOT NULL UNIQUE,
+			image_url TEXT NOT NULL,
+			media_type TEXT NOT NULL DEFAULT 'image/png',
+			created_by TEXT,
+			created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+		)`,
+	}},
+	{version: 2, name: "default info boxes", apply: (*DB).seedDefaultInfoBoxes, body: defaultInfoBoxesBody()},
+}
+
+// sqlCreatePostgresSchemaMigrationsTable is sqlCreateSchemaMigrationsTable with a time zone
+const sqlCreatePostgresSchemaMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INTEGER NOT NULL PRIMARY KEY,
+	name TEXT NOT NULL DEFAULT '',
+	checksum TEXT NOT NULL DEFAULT '',
+	applied_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+)`
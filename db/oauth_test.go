@@ -0,0 +1,165 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+func TestOAuthFlow(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.db.Close()
+	testDB.db.SetMaxOpenConns(1)
+
+	accountId := uuid.New()
+	createTestAccount(t, testDB, accountId, "alice", "pubkey1", "webpub", "webpriv")
+
+	app := &domain.OAuthApp{
+		Id:           uuid.New(),
+		ClientId:     "client-id",
+		ClientSecret: "client-secret",
+		Name:         "Tusky",
+		Website:      "https://tusky.app",
+		RedirectURIs: []string{"tusky://oauth", domain.OAuthOOBRedirect},
+		Scopes:       []string{"read", "write", "follow"},
+		CreatedAt:    time.Now(),
+	}
+	if err := testDB.CreateOAuthApp(app); err != nil {
+		t.Fatalf("CreateOAuthApp failed: %v", err)
+	}
+	err, readApp := testDB.ReadOAuthAppByClientId("client-id")
+	if err != nil {
+		t.Fatalf("ReadOAuthAppByClientId failed: %v", err)
+	}
+	if readApp.Name != "Tusky" || !readApp.AllowsRedirect("tusky://oauth") || len(readApp.Scopes) != 3 {
+		t.Errorf("Unexpected app: %+v", readApp)
+	}
+
+	auth := &domain.OAuthAuthorization{
+		Id:          uuid.New(),
+		AppId:       app.Id,
+		UserCode:    "ABCD-EFGH",
+		RedirectURI: "tusky://oauth",
+		Scopes:      []string{"read", "write"},
+		State:       "xyz",
+		ExpiresAt:   time.Now().Add(10 * time.Minute),
+		CreatedAt:   time.Now(),
+	}
+	if err := testDB.CreateOAuthAuthorization(auth); err != nil {
+		t.Fatalf("CreateOAuthAuthorization failed: %v", err)
+	}
+
+	err, pending := testDB.ReadPendingOAuthAuthorizationByUserCode("ABCD-EFGH")
+	if err != nil {
+		t.Fatalf("ReadPendingOAuthAuthorizationByUserCode failed: %v", err)
+	}
+	if pending.Id != auth.Id || pending.AppName != "Tusky" || pending.Status != domain.OAuthAuthorizationPending {
+		t.Errorf("Unexpected pending authorization: %+v", pending)
+	}
+
+	if err := testDB.ApproveOAuthAuthorization(auth.Id, accountId, "the-code"); err != nil {
+		t.Fatalf("ApproveOAuthAuthorization failed: %v", err)
+	}
+	// A request can only be approved once
+	if err := testDB.ApproveOAuthAuthorization(auth.Id, accountId, "other-code"); err == nil {
+		t.Error("Expected second approval to fail")
+	}
+	if err, _ := testDB.ReadPendingOAuthAuthorizationByUserCode("ABCD-EFGH"); err == nil {
+		t.Error("Expected approved request to no longer be pending")
+	}
+
+	err, approved := testDB.ReadOAuthAuthorizationByCode("the-code")
+	if err != nil {
+		t.Fatalf("ReadOAuthAuthorizationByCode failed: %v", err)
+	}
+	if approved.AccountId != accountId || approved.State != "xyz" || len(approved.Scopes) != 2 {
+		t.Errorf("Unexpected approved authorization: %+v", approved)
+	}
+	if err := testDB.DeleteOAuthAuthorization(auth.Id); err != nil {
+		t.Fatalf("DeleteOAuthAuthorization failed: %v", err)
+	}
+	if err, _ := testDB.ReadOAuthAuthorizationById(auth.Id); err == nil {
+		t.Error("Expected authorization to be deleted")
+	}
+
+	token := &domain.OAuthToken{
+		Id:        uuid.New(),
+		TokenHash: "hash",
+		AppId:     app.Id,
+		AccountId: accountId,
+		Scopes:    approved.Scopes,
+		CreatedAt: time.Now(),
+	}
+	if err := testDB.CreateOAuthToken(token); err != nil {
+		t.Fatalf("CreateOAuthToken failed: %v", err)
+	}
+	// App-only tokens have no account
+	appToken := &domain.OAuthToken{Id: uuid.New(), TokenHash: "apphash", AppId: app.Id, Scopes: []string{"read"}, CreatedAt: time.Now()}
+	if err := testDB.CreateOAuthToken(appToken); err != nil {
+		t.Fatalf("CreateOAuthToken (app-only) failed: %v", err)
+	}
+	err, readAppToken := testDB.ReadOAuthTokenByHash("apphash")
+	if err != nil || readAppToken.AccountId != uuid.Nil {
+		t.Errorf("Expected app-only token without account, got %+v (err=%v)", readAppToken, err)
+	}
+
+	err, readToken := testDB.ReadOAuthTokenByHash("hash")
+	if err != nil {
+		t.Fatalf("ReadOAuthTokenByHash failed: %v", err)
+	}
+	if readToken.AccountId != accountId || readToken.AppName != "Tusky" {
+		t.Errorf("Unexpected token: %+v", readToken)
+	}
+	if err := testDB.TouchOAuthToken(token.Id); err != nil {
+		t.Fatalf("TouchOAuthToken failed: %v", err)
+	}
+
+	err, tokens := testDB.ReadOAuthTokensByAccountId(accountId)
+	if err != nil || len(*tokens) != 1 {
+		t.Fatalf("Expected 1 authorized app, got %v (err=%v)", tokens, err)
+	}
+
+	if err := testDB.DeleteOAuthToken(token.Id); err != nil {
+		t.Fatalf("DeleteOAuthToken failed: %v", err)
+	}
+	if err, _ := testDB.ReadOAuthTokenByHash("hash"); err == nil {
+		t.Error("Expected revoked token to be gone")
+	}
+}
+
+func TestOAuthAuthorizationExpiry(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.db.Close()
+	testDB.db.SetMaxOpenConns(1)
+
+	expired := &domain.OAuthAuthorization{
+		Id:        uuid.New(),
+		AppId:     uuid.New(),
+		UserCode:  "OLDC-ODEX",
+		ExpiresAt: time.Now().Add(-time.Minute),
+		CreatedAt: time.Now().Add(-11 * time.Minute),
+	}
+	if err := testDB.CreateOAuthAuthorization(expired); err != nil {
+		t.Fatalf("CreateOAuthAuthorization failed: %v", err)
+	}
+	if err, _ := testDB.ReadPendingOAuthAuthorizationByUserCode("OLDC-ODEX"); err == nil {
+		t.Error("Expected expired code to be rejected")
+	}
+
+	// Creating a new request clears expired ones
+	fresh := &domain.OAuthAuthorization{
+		Id:        uuid.New(),
+		AppId:     uuid.New(),
+		UserCode:  "NEWC-ODEX",
+		ExpiresAt: time.Now().Add(10 * time.Minute),
+		CreatedAt: time.Now(),
+	}
+	if err := testDB.CreateOAuthAuthorization(fresh); err != nil {
+		t.Fatalf("CreateOAuthAuthorization failed: %v", err)
+	}
+	if err, _ := testDB.ReadOAuthAuthorizationById(expired.Id); err == nil {
+		t.Error("Expected expired request to be cleaned up")
+	}
+}
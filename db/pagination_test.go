@@ -0,0 +1,191 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+// seedPagedTimeline creates a viewer following a remote account, with 5 own notes and
+// 5 remote posts. Each note shares its second with a remote post to exercise tie-breaking.
+func seedPagedTimeline(t *testing.T, db *DB) uuid.UUID {
+	t.Helper()
+	viewerId := uuid.New()
+	createTestAccount(t, db, viewerId, "viewer", "pubkey1", "webpub", "webpriv")
+	remoteId := uuid.New()
+	_, err := db.db.Exec(`INSERT INTO remote_accounts(id, username, domain, actor_uri, inbox_uri) VALUES (?, ?, ?, ?, ?)`,
+		remoteId.String(), "erin", "remote.example",
+		"https://remote.example/users/erin", "https://remote.example/users/erin/inbox")
+	if err != nil {
+		t.Fatalf("Failed to create remote account: %v", err)
+	}
+	_, err = db.db.Exec(`INSERT INTO follows(id, account_id, target_account_id, accepted, is_local) VALUES (?, ?, ?, 1, 0)`,
+		uuid.New().String(), viewerId.String(), remoteId.String())
+	if err != nil {
+		t.Fatalf("Failed to create follow: %v", err)
+	}
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i := 0; i < 5; i++ {
+		at := base.Add(-time.Duration(i) * time.Second)
+		_, err := db.db.Exec(`INSERT INTO notes(id, user_id, message, created_at) VALUES (?, ?, ?, ?)`,
+			uuid.New().String(), viewerId.String(), "note", at)
+		if err != nil {
+			t.Fatalf("Failed to create note: %v", err)
+		}
+		uri := "https://remote.example/notes/" + uuid.NewString()
+		if err := db.CreateActivity(&domain.Activity{
+			Id:           uuid.New(),
+			ActivityURI:  uri + "/activity",
+			ActivityType: "Create",
+			ActorURI:     "https://remote.example/users/erin",
+			ObjectURI:    uri,
+			RawJSON:      `{"type":"Create","object":{"id":"` + uri + `","content":"remote","inReplyTo":null}}`,
+			CreatedAt:    at.Add(200 * time.Millisecond),
+		}); err != nil {
+			t.Fatalf("CreateActivity failed: %v", err)
+		}
+	}
+	return viewerId
+}
+
+// readAllPages follows the cursor until an empty page and checks the newest-first order
+func readAllPages(t *testing.T, read func(before domain.Cursor) []domain.Cursor) []domain.Cursor {
+	t.Helper()
+	var all []domain.Cursor
+	before := domain.Cursor{}
+	for page := 0; page < 20; page++ {
+		cursors := read(before)
+		if len(cursors) == 0 {
+			return all
+		}
+		for _, c := range cursors {
+			if len(all) > 0 && !all[len(all)-1].After(c) {
+				t.Fatalf("Items out of order or repeated at %v", c)
+			}
+			all = append(all, c)
+		}
+		before = cursors[len(cursors)-1]
+	}
+	t.Fatal("Pagination did not terminate")
+	return nil
+}
+
+func TestReadHomeTimelinePostsPages(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+	viewerId := seedPagedTimeline(t, db)
+
+	all := readAllPages(t, func(before domain.Cursor) []domain.Cursor {
+		err, posts := db.ReadHomeTimelinePosts(viewerId, before, 3)
+		if err != nil {
+			t.Fatalf("ReadHomeTimelinePosts failed: %v", err)
+		}
+		cursors := []domain.Cursor{}
+		for _, p := range *posts {
+			cursors = append(cursors, p.Cursor())
+		}
+		return cursors
+	})
+	if len(all) != 10 {
+		t.Errorf("Expected all 10 posts across pages, got %d", len(all))
+	}
+}
+
+func TestReadGlobalTimelinePostsPages(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+	seedPagedTimeline(t, db)
+
+	all := readAllPages(t, func(before domain.Cursor) []domain.Cursor {
+		err, posts := db.ReadGlobalTimelinePosts(before, 4)
+		if err != nil {
+			t.Fatalf("ReadGlobalTimelinePosts failed: %v", err)
+		}
+		cursors := []domain.Cursor{}
+		for _, p := range *posts {
+			cursors = append(cursors, p.Cursor())
+		}
+		return cursors
+	})
+	if len(all) != 10 {
+		t.Errorf("Expected all 10 posts across pages, got %d", len(all))
+	}
+}
+
+func TestReadNotificationsByAccountIdPages(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+	accountId := uuid.New()
+	createTestAccount(t, db, accountId, "alice", "pubkey", "webpub", "webpriv")
+
+	// All in the same second, so the pages are split by id alone
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := db.CreateNotification(&domain.Notification{
+			Id:               uuid.New(),
+			AccountId:        accountId,
+			NotificationType: domain.NotificationFollow,
+			ActorId:          uuid.New(),
+			ActorUsername:    "bob",
+			CreatedAt:        now,
+		}); err != nil {
+			t.Fatalf("CreateNotification failed: %v", err)
+		}
+	}
+
+	all := readAllPages(t, func(before domain.Cursor) []domain.Cursor {
+		err, notifications := db.ReadNotificationsByAccountId(accountId, before, 2)
+		if err != nil {
+			t.Fatalf("ReadNotificationsByAccountId failed: %v", err)
+		}
+		cursors := []domain.Cursor{}
+		for _, n := range *notifications {
+			cursors = append(cursors, n.Cursor())
+		}
+		return cursors
+	})
+	if len(all) != 5 {
+		t.Errorf("Expected all 5 notifications across pages, got %d", len(all))
+	}
+}
+
+func TestReadTopLevelNotes(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.db.Close()
+	aliceId := uuid.New()
+	createTestAccount(t, db, aliceId, "alice", "pubkey1", "webpub", "webpriv")
+	bobId := uuid.New()
+	createTestAccount(t, db, bobId, "bob", "pubkey2", "webpub", "webpriv")
+
+	db.CreateNote(aliceId, "first")
+	db.CreateNote(aliceId, "second")
+	db.CreateNote(bobId, "from bob")
+	if _, err := db.CreateNoteWithReply(aliceId, "a reply", "https://remote.example/notes/1"); err != nil {
+		t.Fatalf("CreateNoteWithReply failed: %v", err)
+	}
+
+	err, notes := db.ReadTopLevelNotes(uuid.Nil, domain.Cursor{}, 10)
+	if err != nil {
+		t.Fatalf("ReadTopLevelNotes failed: %v", err)
+	}
+	if len(*notes) != 3 {
+		t.Errorf("Expected 3 top-level notes from all users, got %d", len(*notes))
+	}
+
+	err, notes = db.ReadTopLevelNotes(aliceId, domain.Cursor{}, 1)
+	if err != nil || len(*notes) != 1 {
+		t.Fatalf("Expected one note on the first page, got %v (%v)", notes, err)
+	}
+	err, rest := db.ReadTopLevelNotes(aliceId, (*notes)[0].Cursor(), 10)
+	if err != nil || len(*rest) != 1 || (*rest)[0].Id == (*notes)[0].Id {
+		t.Errorf("Expected alice's other top-level note on the next page, got %v (%v)", rest, err)
+	}
+
+	err, all := db.ReadNotesPageByUserId(aliceId, domain.Cursor{}, 10)
+	if err != nil || len(*all) != 3 {
+		t.Errorf("Expected alice's 3 notes including the reply, got %v (%v)", all, err)
+	}
+}
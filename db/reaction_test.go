@@ -0,0 +1,149 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+func TestReactionOperations(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.db.Close()
+	// Keep a single connection so the in-memory database survives failed transactions
+	testDB.db.SetMaxOpenConns(1)
+
+	noteId := uuid.New()
+	remotePostURI := "https://remote.example/notes/123"
+
+	newReaction := func(accountId uuid.UUID, noteId uuid.UUID, objectURI, emoji string) *domain.Reaction {
+		return &domain.Reaction{
+			Id:        uuid.New(),
+			AccountId: accountId,
+			NoteId:    noteId,
+			ObjectURI: objectURI,
+			Emoji:     emoji,
+			URI:       "https://remote.example/activities/" + uuid.New().String(),
+			CreatedAt: time.Now(),
+		}
+	}
+
+	t.Run("CreateReaction and ReadReactionByURI", func(t *testing.T) {
+		reaction := newReaction(uuid.New(), noteId, "", "👍")
+		if err := testDB.CreateReaction(reaction); err != nil {
+			t.Fatalf("Failed to create reaction: %v", err)
+		}
+
+		err, found := testDB.ReadReactionByURI(reaction.URI)
+		if err != nil {
+			t.Fatalf("Failed to read reaction: %v", err)
+		}
+		if found == nil {
+			t.Fatal("Expected reaction to be found")
+		}
+		if found.Emoji != "👍" {
+			t.Errorf("Expected emoji 👍, got %s", found.Emoji)
+		}
+		if found.NoteId != noteId {
+			t.Errorf("Expected note id %s, got %s", noteId, found.NoteId)
+		}
+	})
+
+	t.Run("ReadReactionByURI returns nil when missing", func(t *testing.T) {
+		err, found := testDB.ReadReactionByURI("https://nowhere.example/activities/none")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if found != nil {
+			t.Error("Expected nil reaction")
+		}
+	})
+
+	t.Run("One reaction per account per note", func(t *testing.T) {
+		accountId := uuid.New()
+		if err := testDB.CreateReaction(newReaction(accountId, noteId, "", "🎉")); err != nil {
+			t.Fatalf("Failed to create reaction: %v", err)
+		}
+		if err := testDB.CreateReaction(newReaction(accountId, noteId, "", "🔥")); err == nil {
+			t.Error("Expected unique constraint error for second reaction on same note")
+		}
+
+		err, existing := testDB.ReadReactionByAccountAndNote(accountId, noteId)
+		if err != nil || existing == nil {
+			t.Fatalf("Expected existing reaction, got err=%v", err)
+		}
+		if existing.Emoji != "🎉" {
+			t.Errorf("Expected 🎉, got %s", existing.Emoji)
+		}
+	})
+
+	t.Run("ReadReactionCountsByNoteId groups by emoji", func(t *testing.T) {
+		if err := testDB.CreateReaction(newReaction(uuid.New(), noteId, "", "👍")); err != nil {
+			t.Fatalf("Failed to create reaction: %v", err)
+		}
+
+		err, counts := testDB.ReadReactionCountsByNoteId(noteId)
+		if err != nil {
+			t.Fatalf("Failed to read counts: %v", err)
+		}
+		if len(counts) != 2 {
+			t.Fatalf("Expected 2 emoji groups, got %d", len(counts))
+		}
+		if counts[0].Emoji != "👍" || counts[0].Count != 2 {
+			t.Errorf("Expected 👍 x2 first, got %s x%d", counts[0].Emoji, counts[0].Count)
+		}
+		if counts[1].Emoji != "🎉" || counts[1].Count != 1 {
+			t.Errorf("Expected 🎉 x1 second, got %s x%d", counts[1].Emoji, counts[1].Count)
+		}
+	})
+
+	t.Run("Reactions on remote posts are keyed by object URI", func(t *testing.T) {
+		accountId := uuid.New()
+		reaction := newReaction(accountId, uuid.Nil, remotePostURI, "❤️")
+		if err := testDB.CreateReaction(reaction); err != nil {
+			t.Fatalf("Failed to create reaction: %v", err)
+		}
+
+		err, existing := testDB.ReadReactionByAccountAndObjectURI(accountId, remotePostURI)
+		if err != nil || existing == nil {
+			t.Fatalf("Expected existing reaction, got err=%v", err)
+		}
+
+		err, counts := testDB.ReadReactionCountsByObjectURI(remotePostURI)
+		if err != nil {
+			t.Fatalf("Failed to read counts: %v", err)
+		}
+		if len(counts) != 1 || counts[0].Count != 1 {
+			t.Errorf("Expected a single ❤️ reaction, got %+v", counts)
+		}
+
+		if err := testDB.DeleteReactionById(existing.Id); err != nil {
+			t.Fatalf("Failed to delete reaction: %v", err)
+		}
+		err, existing = testDB.ReadReactionByAccountAndObjectURI(accountId, remotePostURI)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if existing != nil {
+			t.Error("Expected reaction to be deleted")
+		}
+	})
+
+	t.Run("DeleteReactionByURI removes the reaction", func(t *testing.T) {
+		reaction := newReaction(uuid.New(), noteId, "", "😂")
+		if err := testDB.CreateReaction(reaction); err != nil {
+			t.Fatalf("Failed to create reaction: %v", err)
+		}
+		if err := testDB.DeleteReactionByURI(reaction.URI); err != nil {
+			t.Fatalf("Failed to delete reaction: %v", err)
+		}
+		err, found := testDB.ReadReactionByURI(reaction.URI)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if found != nil {
+			t.Error("Expected reaction to be deleted")
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+func TestPruneFederatedContent(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.db.Close()
+	// Keep a single connection so the in-memory database is shared by the prune transaction
+	testDB.db.SetMaxOpenConns(1)
+
+	old := time.Now().Add(-100 * 24 * time.Hour)
+	now := time.Now()
+	localPrefix := "https://local.example/notes/"
+
+	localId := uuid.New()
+	createTestAccount(t, testDB, localId, "alice", "ssh-rsa AAAA", "webpub", "webpriv")
+	localNoteId := uuid.New()
+	localNoteURI := localPrefix + localNoteId.String()
+	if _, err := testDB.db.Exec(`INSERT INTO notes(id, user_id, message, created_at, in_reply_to_uri) VALUES (?, ?, ?, ?, ?)`,
+		localNoteId, localId, "local reply", old, "https://r1.example/posts/parent"); err != nil {
+		t.Fatalf("Failed to insert note: %v", err)
+	}
+
+	newRemote := func(name string, fetchedAt time.Time) *domain.RemoteAccount {
+		acc := &domain.RemoteAccount{
+			Id:            uuid.New(),
+			Username:      name,
+			Domain:        name + ".example",
+			ActorURI:      "https://" + name + ".example/users/" + name,
+			InboxURI:      "https://" + name + ".example/inbox",
+			PublicKeyPem:  "-----BEGIN PUBLIC KEY-----",
+			LastFetchedAt: fetchedAt,
+		}
+		if err := testDB.CreateRemoteAccount(acc); err != nil {
+			t.Fatalf("CreateRemoteAccount failed: %v", err)
+		}
+		return acc
+	}
+	followed := newRemote("r1", old)
+	orphan := newRemote("r2", old)
+	newRemote("r3", now) // Recently fetched, kept even without references
+	if _, err := testDB.db.Exec(`INSERT INTO follows(id, account_id, target_account_id, uri, accepted) VALUES (?, ?, ?, ?, 1)`,
+		uuid.New(), localId, followed.Id, "https://local.example/follows/1"); err != nil {
+		t.Fatalf("Failed to insert follow: %v", err)
+	}
+
+	addPost := func(actor *domain.RemoteAccount, objectURI, inReplyTo string, createdAt time.Time) {
+		if _, err := testDB.db.Exec(`INSERT INTO activities(id, activity_uri, activity_type, actor_uri, object_uri, in_reply_to, raw_json, local, created_at) VALUES (?, ?, 'Create', ?, ?, ?, '{}', 0, ?)`,
+			uuid.New(), objectURI+"/activity", actor.ActorURI, objectURI, inReplyTo, createdAt); err != nil {
+			t.Fatalf("Failed to insert activity: %v", err)
+		}
+	}
+	addPost(orphan, "https://r2.example/posts/old", "", old)
+	addPost(orphan, "https://r2.example/posts/boosted-remotely", "", old)
+	addPost(followed, "https://r1.example/posts/new", "", now)
+	addPost(followed, "https://r1.example/posts/liked", "", old)
+	addPost(followed, "https://r1.example/posts/root", "", old)
+	addPost(followed, "https://r1.example/posts/parent", "https://r1.example/posts/root", old)
+	addPost(followed, "https://r1.example/posts/reply-to-local", localNoteURI, old)
+	addPost(followed, "https://r1.example/posts/deep-reply", "https://r1.example/posts/reply-to-local", old)
+
+	if _, err := testDB.db.Exec(`INSERT INTO likes(id, account_id, note_id, uri, object_uri) VALUES (?, ?, ?, ?, ?)`,
+		uuid.New(), localId, uuid.New(), "https://local.example/likes/1", "https://r1.example/posts/liked"); err != nil {
+		t.Fatalf("Failed to insert like: %v", err)
+	}
+	if err := testDB.CreateBoostFromRemote(&domain.Boost{
+		Id:              uuid.New(),
+		RemoteAccountId: orphan.Id,
+		ObjectURI:       "https://r2.example/posts/boosted-remotely",
+		URI:             "https://r2.example/boosts/1",
+		CreatedAt:       old,
+	}); err != nil {
+		t.Fatalf("CreateBoostFromRemote failed: %v", err)
+	}
+
+	// Only the old notification that was read is pruned
+	for _, n := range []struct {
+		read      int
+		createdAt time.Time
+	}{{1, old}, {0, old}, {1, now}} {
+		if _, err := testDB.db.Exec(sqlInsertNotification, uuid.New(), localId, "like", "", "r1", "r1.example", "", "", "", "", n.read, n.createdAt); err != nil {
+			t.Fatalf("Failed to insert notification: %v", err)
+		}
+	}
+
+	countRows := func(table string) int {
+		var n int
+		if err := testDB.db.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&n); err != nil {
+			t.Fatalf("Failed to count %s: %v", table, err)
+		}
+		return n
+	}
+
+	cutoff := now.Add(-30 * 24 * time.Hour)
+	expected := domain.RetentionStats{Activities: 2, Boosts: 1, Notifications: 1, RemoteAccounts: 1}
+
+	stats, err := testDB.PruneFederatedContent(cutoff, now.Add(-24*time.Hour), localPrefix, true)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if *stats != expected {
+		t.Errorf("Dry run: expected %+v, got %+v", expected, *stats)
+	}
+	if countRows("activities") != 8 || countRows("remote_accounts") != 3 || countRows("notifications") != 3 {
+		t.Fatal("Dry run must not delete anything")
+	}
+
+	stats, err = testDB.PruneFederatedContent(cutoff, now.Add(-24*time.Hour), localPrefix, false)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if *stats != expected {
+		t.Errorf("Expected %+v, got %+v", expected, *stats)
+	}
+
+	for _, uri := range []string{
+		"https://r1.example/posts/new",
+		"https://r1.example/posts/liked",
+		"https://r1.example/posts/root",
+		"https://r1.example/posts/parent",
+		"https://r1.example/posts/reply-to-local",
+		"https://r1.example/posts/deep-reply",
+	} {
+		if err, activity := testDB.ReadActivityByObjectURI(uri); err != nil || activity == nil {
+			t.Errorf("Expected %s to be kept", uri)
+		}
+	}
+	if err, activity := testDB.ReadActivityByObjectURI("https://r2.example/posts/old"); err == nil && activity != nil {
+		t.Error("Expected old unused post to be pruned")
+	}
+	if err, acc := testDB.ReadRemoteAccountById(orphan.Id); err == nil && acc != nil {
+		t.Error("Expected orphaned remote account to be pruned")
+	}
+	if err, acc := testDB.ReadRemoteAccountById(followed.Id); err != nil || acc == nil {
+		t.Error("Expected followed remote account to be kept")
+	}
+	if countRows("boosts") != 0 || countRows("notifications") != 2 {
+		t.Error("Expected the remote boost and the old read notification to be pruned")
+	}
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+func TestScheduledNoteOperations(t *testing.T) {
+	testDB := setupTestDB(t)
+	defer testDB.db.Close()
+	testDB.db.SetMaxOpenConns(1)
+
+	accountId := uuid.New()
+	otherId := uuid.New()
+	createTestAccount(t, testDB, accountId, "alice", "pubkey1", "webpub", "webpriv")
+
+	now := time.Now().Truncate(time.Second)
+	later := &domain.ScheduledNote{Id: uuid.New(), AccountId: accountId, Message: "good morning", ScheduledAt: now.Add(8 * time.Hour), CreatedAt: now}
+	soon := &domain.ScheduledNote{Id: uuid.New(), AccountId: accountId, Message: "soon", ScheduledAt: now.Add(time.Hour), CreatedAt: now}
+	for _, n := range []*domain.ScheduledNote{later, soon} {
+		if err := testDB.CreateScheduledNote(n); err != nil {
+			t.Fatalf("CreateScheduledNote failed: %v", err)
+		}
+	}
+
+	err, notes := testDB.ReadScheduledNotesByAccountId(accountId)
+	if err != nil {
+		t.Fatalf("ReadScheduledNotesByAccountId failed: %v", err)
+	}
+	if len(*notes) != 2 || (*notes)[0].Id != soon.Id || !(*notes)[1].ScheduledAt.Equal(later.ScheduledAt) {
+		t.Fatalf("Expected notes ordered by due time, got %+v", *notes)
+	}
+
+	// Only the owner can edit or cancel
+	if err := testDB.UpdateScheduledNote(soon.Id, otherId, "hijacked", now); err == nil {
+		t.Error("Expected update by another account to fail")
+	}
+	if err := testDB.UpdateScheduledNote(soon.Id, accountId, "soon, edited", now.Add(-time.Minute)); err != nil {
+		t.Fatalf("UpdateScheduledNote failed: %v", err)
+	}
+
+	err, due := testDB.ReadDueScheduledNotes(now, 10)
+	if err != nil {
+		t.Fatalf("ReadDueScheduledNotes failed: %v", err)
+	}
+	if len(*due) != 1 || (*due)[0].Message != "soon, edited" {
+		t.Fatalf("Expected the edited note to be due, got %+v", *due)
+	}
+
+	noteId, err := testDB.PublishScheduledNote(&(*due)[0])
+	if err != nil {
+		t.Fatalf("PublishScheduledNote failed: %v", err)
+	}
+	if err, note := testDB.ReadNoteId(noteId); err != nil || note == nil || note.Message != "soon, edited" {
+		t.Errorf("Expected published note, got %+v (err=%v)", note, err)
+	}
+	if _, err := testDB.PublishScheduledNote(&(*due)[0]); err == nil {
+		t.Error("Expected a published note not to be published twice")
+	}
+
+	if err := testDB.DeleteScheduledNote(later.Id, otherId); err != nil {
+		t.Fatalf("DeleteScheduledNote failed: %v", err)
+	}
+	_, notes = testDB.ReadScheduledNotesByAccountId(accountId)
+	if len(*notes) != 1 {
+		t.Fatalf("Expected cancel by another account to be ignored, got %d notes", len(*notes))
+	}
+	if err := testDB.DeleteScheduledNote(later.Id, accountId); err != nil {
+		t.Fatalf("DeleteScheduledNote failed: %v", err)
+	}
+	_, notes = testDB.ReadScheduledNotesByAccountId(accountId)
+	if len(*notes) != 0 {
+		t.Errorf("Expected no scheduled notes left, got %d", len(*notes))
+	}
+}
+
+func TestScheduledNote_RoundTripsInNonUTCZone(t *testing.T) {
+	saved := time.Local
+	time.Local = time.FixedZone("UTC+5", 5*60*60)
+	defer func() { time.Local = saved }()
+
+	testDB := setupTestDB(t)
+	defer testDB.db.Close()
+	testDB.db.SetMaxOpenConns(1)
+
+	accountId := uuid.New()
+	createTestAccount(t, testDB, accountId, "alice", "pubkey1", "webpub", "webpriv")
+
+	scheduledAt := time.Date(2030, 1, 2, 7, 30, 0, 0, time.Local)
+	note := &domain.ScheduledNote{Id: uuid.New(), AccountId: accountId, Message: "good morning", ScheduledAt: scheduledAt, CreatedAt: time.Now().Truncate(time.Second)}
+	if err := testDB.CreateScheduledNote(note); err != nil {
+		t.Fatalf("CreateScheduledNote failed: %v", err)
+	}
+
+	err, notes := testDB.ReadScheduledNotesByAccountId(accountId)
+	if err != nil || len(*notes) != 1 {
+		t.Fatalf("ReadScheduledNotesByAccountId failed: %v", err)
+	}
+	if got := (*notes)[0].ScheduledAt; !got.Equal(scheduledAt) {
+		t.Errorf("Expected ScheduledAt %v, got %v", scheduledAt, got)
+	}
+
+	// Not due a minute early, due on time
+	if err, due := testDB.ReadDueScheduledNotes(scheduledAt.Add(-time.Minute), 10); err != nil || len(*due) != 0 {
+		t.Errorf("Expected no due notes before the scheduled time, got %v (%v)", due, err)
+	}
+	if err, due := testDB.ReadDueScheduledNotes(scheduledAt, 10); err != nil || len(*due) != 1 {
+		t.Errorf("Expected the note to be due at the scheduled time, got %v (%v)", due, err)
+	}
+}
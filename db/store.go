@@ -0,0 +1,317 @@
+package db
+
+import (
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/google/uuid"
+)
+
+// Store is the storage used by the ActivityPub handlers, the web server and the UI.
+// *DB implements it on SQLite, the default, and on PostgreSQL, see OpenPostgres.
+type Store interface {
+	// Accounts and notes
+	CreateAccount(s ssh.Session, username string) (error, bool)
+	CreateAccountWithPublicKey(username string, publicKey string) error
+	UpdateAccountPublicKey(accountId uuid.UUID, publicKey string) error
+	UpdateAccountAdmin(accountId uuid.UUID, isAdmin bool) error
+	CreateNote(userId uuid.UUID, message string) (uuid.UUID, error)
+	CreateNoteWithReply(userId uuid.UUID, message string, inReplyToURI string) (uuid.UUID, error)
+	CreateArticle(userId uuid.UUID, title string, message string) (uuid.UUID, error)
+	UpdateNote(noteId uuid.UUID, message string) error
+	UpdateArticle(noteId uuid.UUID, title string, message string) error
+	DeleteNoteById(noteId uuid.UUID) error
+	UpdateLoginById(username string, displayName string, summary string, id uuid.UUID) error
+	UpdateAccountDisplayName(accountId uuid.UUID, displayName string) error
+	UpdateAccountSummary(accountId uuid.UUID, summary string) error
+	UpdateAccountAvatar(accountId uuid.UUID, avatarURL string) error
+
+	// Upload tokens
+	CreateUploadToken(accountId uuid.UUID, token string, tokenType string, expiresIn time.Duration) error
+	ValidateUploadToken(token string) (uuid.UUID, string, error)
+	DeleteUploadToken(token string) error
+	GetExistingUploadToken(accountId uuid.UUID, tokenType string) (string, time.Time, error)
+
+	// Account and note reads
+	ReadAccBySession(s ssh.Session) (error, *domain.Account)
+	ReadAccById(id uuid.UUID) (error, *domain.Account)
+	ReadAccByUsername(username string) (error, *domain.Account)
+	ReadNotesByUserId(userId uuid.UUID) (error, *[]domain.Note)
+	ReadNotesByUsername(username string) (error, *[]domain.Note)
+	ReadNoteId(id uuid.UUID) (error, *domain.Note)
+	ReadAllNotes() (error, *[]domain.Note)
+	ReadNotesPageByUserId(userId uuid.UUID, before domain.Cursor, limit int) (error, *[]domain.Note)
+	ReadTopLevelNotes(userId uuid.UUID, before domain.Cursor, limit int) (error, *[]domain.Note)
+
+	// Database
+	Close() error
+	Ping() error
+	ReadDatabaseSize() (int64, error)
+	SnapshotTo(path string) error
+	ReadSchemaVersion() (int, error)
+	CreateDB() error
+
+	// Remote accounts
+	CreateRemoteAccount(acc *domain.RemoteAccount) error
+	ReadRemoteAccountByURI(uri string) (error, *domain.RemoteAccount)
+	ReadRemoteAccountById(id uuid.UUID) (error, *domain.RemoteAccount)
+	ReadRemoteAccountByHandle(username, userDomain string) (error, *domain.RemoteAccount)
+	UpdateRemoteAccount(acc *domain.RemoteAccount) error
+	ReadAllRemoteAccounts() (error, []domain.RemoteAccount)
+
+	// Remote account refresh
+	ReadStaleRemoteAccounts(fetchedBefore, retryBefore time.Time, limit int) (error, *[]domain.RemoteAccount)
+	RecordRemoteAccountFetchFailure(id uuid.UUID, notFound bool) (int, error)
+	MarkRemoteAccountGone(id uuid.UUID) error
+	ReadRemoteAccountStats(fetchedBefore time.Time) (error, *domain.RemoteAccountStats)
+
+	// Follows
+	CreateFollow(follow *domain.Follow) error
+	ReadFollowByURI(uri string) (error, *domain.Follow)
+	ReadFollowByAccountIds(accountId, targetAccountId uuid.UUID) (error, *domain.Follow)
+	DeleteFollowByURI(uri string) error
+	DeleteFollowByAccountIds(accountId, targetAccountId uuid.UUID) error
+	AcceptFollowByURI(uri string) error
+	CleanupOrphanedFollows() error
+
+	// Activities
+	CreateActivity(activity *domain.Activity) error
+	UpdateActivity(activity *domain.Activity) error
+	ReadActivityByURI(uri string) (error, *domain.Activity)
+	ReadActivityById(id uuid.UUID) (error, *domain.Activity)
+	ReadActivityByObjectURI(objectURI string) (error, *domain.Activity)
+
+	// Timelines
+	ReadPostsByActorURI(actorURI string, limit int) (error, *[]domain.Activity)
+	ReadHomeTimelinePosts(accountId uuid.UUID, before domain.Cursor, limit int) (error, *[]domain.HomePost)
+	ReadListTimelinePosts(accountId uuid.UUID, listId uuid.UUID, before domain.Cursor, limit int) (error, *[]domain.HomePost)
+
+	// Delivery queue
+	EnqueueDelivery(item *domain.DeliveryQueueItem) error
+	ReadPendingDeliveries(limit int) (error, *[]domain.DeliveryQueueItem)
+	ReadAllDeliveries(limit int) (error, *[]domain.DeliveryQueueItem)
+	ReadDeliveryQueueStats() (depth int, oldest time.Time, err error)
+	UpdateDeliveryAttempt(id uuid.UUID, attempts int, nextRetry time.Time) error
+	DeleteDelivery(id uuid.UUID) error
+	PurgeDeliveryQueue() (int64, error)
+
+	// Inbox queue
+	EnqueueInboxItem(item *domain.InboxQueueItem) error
+	ReadPendingInboxItems(limit int) (error, *[]domain.InboxQueueItem)
+	ReadHeldInboxItems(limit int) (error, *[]domain.InboxQueueItem)
+	UpdateInboxItemAttempt(id uuid.UUID, attempts int, nextRetry time.Time, lastError string) error
+	HoldInboxItem(id uuid.UUID, attempts int, lastError string) error
+	RetryInboxItem(id uuid.UUID) error
+	DeleteInboxItem(id uuid.UUID) error
+	CountInboxQueue() (pending int, held int, err error)
+
+	// Followers and local accounts
+	ReadFollowersByAccountId(accountId uuid.UUID) (error, *[]domain.Follow)
+	ReadFollowingByAccountId(accountId uuid.UUID) (error, *[]domain.Follow)
+	ReadAllAccounts() (error, *[]domain.Account)
+	ReadAllAccountsAdmin() (error, *[]domain.Account)
+	CountAccounts() (int, error)
+	CountLocalPosts() (int, error)
+	CountTopLevelNotesByUserId(userId uuid.UUID) (int, error)
+	CountActiveUsersMonth() (int, error)
+	CountActiveUsersHalfYear() (int, error)
+	DeleteAccount(accountId uuid.UUID) error
+	MuteUser(accountId uuid.UUID) error
+	CreateLocalFollow(followerAccountId, targetAccountId uuid.UUID) error
+	DeleteLocalFollow(followerAccountId, targetAccountId uuid.UUID) error
+	ReadPublicNotesByUsername(username string, before domain.Cursor, limit int) (error, *[]domain.Note)
+	CountPublicNotesByUsername(username string) (int, error)
+	IsFollowingLocal(followerAccountId, targetAccountId uuid.UUID) (bool, error)
+	ReadLocalFollowsByAccountId(accountId uuid.UUID) (error, *[]domain.Follow)
+	DeleteActivity(id uuid.UUID) error
+	ReadRemoteAccountByActorURI(actorURI string) (error, *domain.RemoteAccount)
+	DeleteRemoteAccount(id uuid.UUID) error
+	DeleteFollowsByRemoteAccountId(remoteAccountId uuid.UUID) error
+
+	// Hashtag
+	CreateOrUpdateHashtag(name string) (int64, error)
+	LinkNoteHashtags(noteId uuid.UUID, hashtagIds []int64) error
+	ReadNotesByHashtag(tag string, limit, offset int) (error, *[]domain.Note)
+	CountNotesByHashtag(tag string) (int, error)
+
+	// Followed hashtag
+	FollowHashtag(accountId uuid.UUID, tag string) error
+	UnfollowHashtag(accountId uuid.UUID, tag string) error
+	ReadFollowedHashtags(accountId uuid.UUID) (error, []string)
+	IsFollowingHashtag(accountId uuid.UUID, tag string) (bool, error)
+	CountHashtagFollowers(tag string) (int, error)
+	ReadHashtagTimelinePosts(tag string, before domain.Cursor, limit int) (error, *[]domain.HomePost)
+
+	// List
+	CreateList(list *domain.List) error
+	ReadListsByAccountId(accountId uuid.UUID) (error, *[]domain.List)
+	ReadListByName(accountId uuid.UUID, name string) (error, *domain.List)
+	UpdateListExcludeFromHome(listId uuid.UUID, exclude bool) error
+	DeleteList(listId uuid.UUID) error
+	AddListMember(listId uuid.UUID, memberId uuid.UUID, isLocal bool) error
+	RemoveListMember(listId uuid.UUID, memberId uuid.UUID) error
+	ReadListIdsByMember(accountId uuid.UUID, memberId uuid.UUID) (error, []uuid.UUID)
+
+	// Keyword filter
+	CreateFilter(filter *domain.Filter) error
+	ReadFiltersByAccountId(accountId uuid.UUID) (error, *[]domain.Filter)
+	DeleteFilter(id uuid.UUID) error
+
+	// Scheduled note
+	CreateScheduledNote(note *domain.ScheduledNote) error
+	ReadScheduledNotesByAccountId(accountId uuid.UUID) (error, *[]domain.ScheduledNote)
+	ReadDueScheduledNotes(now time.Time, limit int) (error, *[]domain.ScheduledNote)
+	UpdateScheduledNote(id, accountId uuid.UUID, message string, scheduledAt time.Time) error
+	DeleteScheduledNote(id, accountId uuid.UUID) error
+	PublishScheduledNote(scheduled *domain.ScheduledNote) (uuid.UUID, error)
+
+	// Draft
+	SaveDraft(draft *domain.Draft) error
+	ReadDraftsByAccountId(accountId uuid.UUID) (error, *[]domain.Draft)
+	DeleteDraft(id, accountId uuid.UUID) error
+	PublishDraft(draft *domain.Draft) (uuid.UUID, error)
+
+	// OAuth
+	CreateOAuthApp(app *domain.OAuthApp) error
+	ReadOAuthAppByClientId(clientId string) (error, *domain.OAuthApp)
+	CreateOAuthAuthorization(auth *domain.OAuthAuthorization) error
+	ReadOAuthAuthorizationById(id uuid.UUID) (error, *domain.OAuthAuthorization)
+	ReadPendingOAuthAuthorizationByUserCode(userCode string) (error, *domain.OAuthAuthorization)
+	ReadOAuthAuthorizationByCode(code string) (error, *domain.OAuthAuthorization)
+	ApproveOAuthAuthorization(id uuid.UUID, accountId uuid.UUID, code string) error
+	DenyOAuthAuthorization(id uuid.UUID) error
+	DeleteOAuthAuthorization(id uuid.UUID) error
+	CreateOAuthToken(token *domain.OAuthToken) error
+	ReadOAuthTokenByHash(tokenHash string) (error, *domain.OAuthToken)
+	ReadOAuthTokensByAccountId(accountId uuid.UUID) (error, *[]domain.OAuthToken)
+	TouchOAuthToken(id uuid.UUID) error
+	DeleteOAuthToken(id uuid.UUID) error
+
+	// Mention
+	CreateNoteMention(mention *domain.NoteMention) error
+	ReadMentionsByNoteId(noteId uuid.UUID) (error, []domain.NoteMention)
+
+	// Like
+	CreateLike(like *domain.Like) error
+	HasLike(accountId, noteId uuid.UUID) (bool, error)
+	HasLikeByURI(uri string) (bool, error)
+	IncrementLikeCountByNoteId(noteId uuid.UUID) error
+	DecrementLikeCountByNoteId(noteId uuid.UUID) error
+	ReadLikeByAccountAndNote(accountId, noteId uuid.UUID) (error, *domain.Like)
+	DeleteLikeByAccountAndNote(accountId, noteId uuid.UUID) error
+	IncrementLikeCountByObjectURI(objectURI string) error
+	DecrementLikeCountByObjectURI(objectURI string) error
+	HasLikeByObjectURI(accountId uuid.UUID, objectURI string) (bool, error)
+	CreateLikeByObjectURI(like *domain.Like, objectURI string) error
+	ReadLikeByAccountAndObjectURI(accountId uuid.UUID, objectURI string) (error, *domain.Like)
+	DeleteLikeByAccountAndObjectURI(accountId uuid.UUID, objectURI string) error
+
+	// Reaction
+	CreateReaction(reaction *domain.Reaction) error
+	ReadReactionByURI(uri string) (error, *domain.Reaction)
+	ReadReactionByAccountAndNote(accountId, noteId uuid.UUID) (error, *domain.Reaction)
+	ReadReactionByAccountAndObjectURI(accountId uuid.UUID, objectURI string) (error, *domain.Reaction)
+	DeleteReactionByURI(uri string) error
+	DeleteReactionById(id uuid.UUID) error
+	ReadReactionCountsByNoteId(noteId uuid.UUID) (error, []domain.ReactionCount)
+
+	// Boost
+	CreateBoost(boost *domain.Boost) error
+	HasBoost(accountId, noteId uuid.UUID) (bool, error)
+	DeleteBoostByAccountAndNote(accountId, noteId uuid.UUID) error
+	IncrementBoostCountByNoteId(noteId uuid.UUID) error
+	DecrementBoostCountByNoteId(noteId uuid.UUID) error
+	ReadBoostByAccountAndNote(accountId, noteId uuid.UUID) (error, *domain.Boost)
+	IncrementBoostCountByObjectURI(objectURI string) error
+	DecrementBoostCountByObjectURI(objectURI string) error
+	HasBoostByObjectURI(accountId uuid.UUID, objectURI string) (bool, error)
+	CreateBoostByObjectURI(boost *domain.Boost, objectURI string) error
+	ReadBoostByAccountAndObjectURI(accountId uuid.UUID, objectURI string) (error, *domain.Boost)
+	DeleteBoostByAccountAndObjectURI(accountId uuid.UUID, objectURI string) error
+
+	// Boosts from remote accounts
+	IsRemoteAccountFollowed(remoteAccountId uuid.UUID) (bool, error)
+	CreateBoostFromRemote(boost *domain.Boost) error
+	HasBoostFromRemote(remoteAccountId uuid.UUID, objectURI string) (bool, error)
+	DeleteBoostByRemoteAccountAndObjectURI(remoteAccountId uuid.UUID, objectURI string) error
+
+	// Replies and threads
+	ReadRepliesByNoteId(noteId uuid.UUID) (error, *[]domain.Note)
+	ReadRepliesByURI(objectURI string) (error, *[]domain.Note)
+	CountRepliesByNoteId(noteId uuid.UUID) (int, error)
+	CountRepliesByURI(objectURI string) (int, error)
+	IncrementReplyCountByURI(parentURI string) error
+	ReadNoteByURI(objectURI string) (error, *domain.Note)
+	ReadNoteIdWithReplyInfo(id uuid.UUID) (error, *domain.Note)
+	ReadActivitiesByInReplyTo(parentURI string) (error, *[]domain.Activity)
+	CountActivitiesByInReplyTo(parentURI string) (int, error)
+
+	// Relays
+	CreateRelay(relay *domain.Relay) error
+	ReadAllRelays() (error, *[]domain.Relay)
+	ReadActiveRelays() (error, *[]domain.Relay)
+	ReadActiveUnpausedRelays() (error, *[]domain.Relay)
+	ReadRelayByActorURI(actorURI string) (error, *domain.Relay)
+	UpdateRelayStatus(id uuid.UUID, status string, acceptedAt *time.Time) error
+	DeleteRelay(id uuid.UUID) error
+	UpdateRelayPaused(id uuid.UUID, paused bool) error
+	DeleteRelayActivities() (int64, error)
+
+	// Retention
+	PruneFederatedContent(cutoff, accountsBefore time.Time, localNotePrefix string, dryRun bool) (*domain.RetentionStats, error)
+	IncrementalVacuum() error
+
+	// Notifications
+	CreateNotification(notification *domain.Notification) error
+	ReadNotificationsByAccountId(accountId uuid.UUID, before domain.Cursor, limit int) (error, *[]domain.Notification)
+	ReadNotificationById(notificationId, accountId uuid.UUID) (error, *domain.Notification)
+	ReadUnreadNotificationCount(accountId uuid.UUID) (int, error)
+	DeleteNotification(notificationId, accountId uuid.UUID) error
+	DeleteAllNotifications(accountId uuid.UUID) error
+
+	// Info boxes
+	ReadAllInfoBoxes() (error, *[]domain.InfoBox)
+	ReadEnabledInfoBoxes() (error, *[]domain.InfoBox)
+	CreateInfoBox(box *domain.InfoBox) error
+	UpdateInfoBox(box *domain.InfoBox) error
+	DeleteInfoBox(id uuid.UUID) error
+	ToggleInfoBoxEnabled(id uuid.UUID) error
+
+	// Engagement info (likers and boosters)
+	ReadLikersInfoByNoteId(noteId uuid.UUID) ([]string, error)
+	ReadLikersInfoByObjectURI(objectURI string) ([]string, error)
+	ReadBoostersInfoByNoteId(noteId uuid.UUID) ([]string, error)
+	ReadBoostersInfoByObjectURI(objectURI string) ([]string, error)
+
+	// Server message
+	ReadServerMessage() (error, *domain.ServerMessage)
+	UpdateServerMessage(message string, enabled bool, webEnabled bool) error
+
+	// Global timeline (local and federated posts)
+	ReadGlobalTimelinePosts(before domain.Cursor, limit int) (error, *[]domain.GlobalTimelinePost)
+
+	// Bans
+	CreateBan(id, username, ipAddress, publicKeyHash, reason string) error
+	ReadAllBans() (error, *[]domain.Ban)
+	DeleteBan(id string) error
+	IsIPBanned(ipAddress string) bool
+	IsPublicKeyBanned(publicKeyHash string) bool
+	BanAccount(accountId uuid.UUID) error
+	UnbanAccount(accountId uuid.UUID) error
+	UpdateAccountLastIP(accountId uuid.UUID, ipAddress string) error
+	UpdateAccountLastIPByPkHash(pkHash string, ipAddress string) error
+	CleanupExpiredIPBans() (int64, error)
+
+	// Custom emoji
+	SaveCustomEmoji(emoji *domain.CustomEmoji) error
+	ReadAllCustomEmojis() (error, *[]domain.CustomEmoji)
+	DeleteCustomEmoji(id uuid.UUID) error
+
+	// Migrations
+	RunMigrations() error
+	MigrateDown(version int) error
+	ReadSchemaStatus() (error, *domain.SchemaStatus)
+}
+
+var _ Store = (*DB)(nil)
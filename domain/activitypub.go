@@ -40,6 +40,23 @@ type Like struct {
 	CreatedAt time.Time
 }
 
+// Reaction represents an emoji reaction on a note (EmojiReact or Misskey-style Like with content)
+type Reaction struct {
+	Id        uuid.UUID
+	AccountId uuid.UUID // Who reacted (local account id or remote account id)
+	NoteId    uuid.UUID // Which local note was reacted to (nil for remote posts)
+	ObjectURI string    // URI of the reacted object
+	Emoji     string    // Unicode emoji or :shortcode: for custom emoji
+	URI       string    // ActivityPub EmojiReact/Like activity URI
+	CreatedAt time.Time
+}
+
+// ReactionCount is the aggregated number of reactions with a given emoji on a post
+type ReactionCount struct {
+	Emoji string
+	Count int
+}
+
 // Boost represents a boost/reblog/announce on a note
 type Boost struct {
 	Id              uuid.UUID
@@ -65,6 +82,7 @@ type Activity struct {
 	CreatedAt    time.Time
 	Local        bool // true if originated from this server
 	FromRelay    bool // true if forwarded by a relay
+	Backfilled   bool // true if fetched from the actor's outbox after a follow
 	ReplyCount   int  // Denormalized reply count
 	LikeCount    int  // Denormalized like count
 	BoostCount   int  // Denormalized boost count
@@ -80,6 +98,43 @@ type DeliveryQueueItem struct {
 	CreatedAt    time.Time
 }
 
+// Inbox queue item states
+const (
+	InboxQueuePending = "pending" // Waiting to be processed (or retried)
+	InboxQueueHeld    = "held"    // Failed too often; kept for admins to inspect, retry or drop
+)
+
+// RemoteAccountStats summarizes the health of the remote account cache
+type RemoteAccountStats struct {
+	Total   int // All cached remote accounts
+	Stale   int // Not refetched within the refresh TTL
+	Failing int // Last refetch failed, not gone yet
+	Gone    int // Marked gone after repeated 404/410 responses
+}
+
+// RetentionStats counts the rows removed (or, for a dry run, that would be removed) by a retention run
+type RetentionStats struct {
+	Activities     int // Remote Create activities nobody interacted with
+	Boosts         int // Boosts by remote accounts of posts no longer stored
+	Notifications  int // Notifications older than the retention period
+	RemoteAccounts int // Remote accounts nothing refers to anymore
+}
+
+// InboxQueueItem is a signature-verified incoming activity waiting to be processed
+type InboxQueueItem struct {
+	Id           uuid.UUID
+	Username     string // Local user whose inbox received the activity
+	ActivityJSON string // The raw activity as received
+	ActivityType string
+	ActorURI     string // Activity actor; items of the same actor are processed in order
+	SignerURI    string // Actor whose key signed the request (differs from ActorURI for relays)
+	Attempts     int
+	NextRetryAt  time.Time
+	Status       string // InboxQueuePending or InboxQueueHeld
+	LastError    string
+	CreatedAt    time.Time
+}
+
 // NoteMention represents a @user@domain mention in a note
 type NoteMention struct {
 	Id                uuid.UUID
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cursor marks a position in a newest-first timeline ordered by (created_at, id).
+// Timestamps are compared at second precision, the precision all stored formats share.
+// The zero Cursor means "start at the newest item".
+type Cursor struct {
+	CreatedAt time.Time
+	Id        uuid.UUID
+}
+
+// IsZero reports whether the cursor points at the start of the timeline
+func (c Cursor) IsZero() bool {
+	return c.Id == uuid.Nil && c.CreatedAt.IsZero()
+}
+
+// After reports whether c comes before other in newest-first order
+func (c Cursor) After(other Cursor) bool {
+	if c.CreatedAt.Unix() != other.CreatedAt.Unix() {
+		return c.CreatedAt.Unix() > other.CreatedAt.Unix()
+	}
+	return c.Id.String() > other.Id.String()
+}
+
+// String encodes the cursor for URLs and the CLI as "<unix seconds>_<id>"
+func (c Cursor) String() string {
+	if c.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%d_%s", c.CreatedAt.Unix(), c.Id)
+}
+
+// ParseCursor decodes a cursor produced by Cursor.String. An empty string is the zero Cursor.
+func ParseCursor(s string) (Cursor, error) {
+	if s == "" {
+		return Cursor{}, nil
+	}
+	secs, id, ok := strings.Cut(s, "_")
+	if !ok {
+		return Cursor{}, fmt.Errorf("invalid cursor: %s", s)
+	}
+	unix, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor: %s", s)
+	}
+	parsedId, err := uuid.Parse(id)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor: %s", s)
+	}
+	return Cursor{CreatedAt: time.Unix(unix, 0), Id: parsedId}, nil
+}
+
+// Cursor returns the position of the post in the home timeline
+func (p HomePost) Cursor() Cursor {
+	return Cursor{CreatedAt: p.Time, Id: p.ID}
+}
+
+// Cursor returns the position of the post in the global timeline
+func (p GlobalTimelinePost) Cursor() Cursor {
+	id, _ := uuid.Parse(p.NoteId)
+	return Cursor{CreatedAt: p.CreatedAt, Id: id}
+}
+
+// Cursor returns the position of the note in a list of notes
+func (note Note) Cursor() Cursor {
+	return Cursor{CreatedAt: note.CreatedAt, Id: note.Id}
+}
+
+// Cursor returns the position of the notification in the notifications list
+func (n Notification) Cursor() Cursor {
+	return Cursor{CreatedAt: n.CreatedAt, Id: n.Id}
+}
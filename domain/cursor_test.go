@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	c := Cursor{CreatedAt: time.Unix(1760000000, 500), Id: uuid.New()}
+
+	parsed, err := ParseCursor(c.String())
+	if err != nil {
+		t.Fatalf("ParseCursor failed: %v", err)
+	}
+	if parsed.Id != c.Id || parsed.CreatedAt.Unix() != c.CreatedAt.Unix() {
+		t.Errorf("Expected %v, got %v", c, parsed)
+	}
+
+	zero, err := ParseCursor("")
+	if err != nil || !zero.IsZero() {
+		t.Errorf("Expected the zero cursor for an empty string, got %v (%v)", zero, err)
+	}
+	if (Cursor{}).String() != "" {
+		t.Error("Expected the zero cursor to encode as an empty string")
+	}
+
+	for _, invalid := range []string{"abc", "12_notauuid", "x_" + uuid.NewString()} {
+		if _, err := ParseCursor(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestCursorAfter(t *testing.T) {
+	now := time.Unix(1760000000, 0)
+	low := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	high := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+
+	newer := Cursor{CreatedAt: now.Add(time.Second), Id: low}
+	older := Cursor{CreatedAt: now, Id: high}
+	if !newer.After(older) || older.After(newer) {
+		t.Error("Expected the later timestamp to come first")
+	}
+
+	// Within the same second the higher id comes first, sub-second parts are ignored
+	a := Cursor{CreatedAt: now.Add(100 * time.Millisecond), Id: low}
+	b := Cursor{CreatedAt: now, Id: high}
+	if !b.After(a) || a.After(b) {
+		t.Error("Expected ties within a second to be ordered by id")
+	}
+}
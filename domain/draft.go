@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Draft is an unsent note autosaved from the write note panel.
+// InReplyToURI and the reply fields are set when the draft is a reply,
+// Title when it is an article.
+type Draft struct {
+	Id             uuid.UUID
+	AccountId      uuid.UUID
+	Message        string
+	Title          string
+	InReplyToURI   string
+	ReplyToAuthor  string // Shown as "reply to ..." when the draft is resumed
+	ReplyToPreview string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
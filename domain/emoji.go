@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CustomEmoji represents a custom :shortcode: emoji uploaded by an admin
+type CustomEmoji struct {
+	Id        uuid.UUID
+	Shortcode string // Shortcode without colons (e.g. "blobcat")
+	ImageURL  string // Image path relative to the server root (e.g. "/emojis/blobcat.png")
+	MediaType string // MIME type of the image
+	CreatedBy uuid.UUID
+	CreatedAt time.Time
+}
+
+// EmojiMap converts a list of custom emoji to a shortcode -> image URL map
+func EmojiMap(emojis []CustomEmoji) map[string]string {
+	m := make(map[string]string, len(emojis))
+	for _, e := range emojis {
+		m[e.Shortcode] = e.ImageURL
+	}
+	return m
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Contexts a keyword filter can apply to
+const (
+	FilterContextHome          = "home"
+	FilterContextGlobal        = "global"
+	FilterContextNotifications = "notifications"
+	FilterContextThread        = "thread"
+)
+
+// FilterContexts lists all filter contexts in display order
+var FilterContexts = []string{FilterContextHome, FilterContextGlobal, FilterContextNotifications, FilterContextThread}
+
+// What happens to a post matching a keyword filter
+const (
+	FilterActionHide = "hide" // Remove the post from the view
+	FilterActionWarn = "warn" // Collapse the post behind a warning
+)
+
+// Filter is a per-user keyword filter (muted phrase)
+type Filter struct {
+	Id        uuid.UUID
+	AccountId uuid.UUID
+	Phrase    string
+	WholeWord bool       // Only match the phrase as a whole word, not inside other words
+	Contexts  []string   // FilterContext* values the filter applies to
+	Action    string     // FilterActionHide or FilterActionWarn
+	ExpiresAt *time.Time // nil if the filter never expires
+	CreatedAt time.Time
+}
+
+// IsExpired reports whether the filter has expired at now
+func (f Filter) IsExpired(now time.Time) bool {
+	return f.ExpiresAt != nil && !now.Before(*f.ExpiresAt)
+}
+
+// HasContext reports whether the filter applies to context
+func (f Filter) HasContext(context string) bool {
+	for _, c := range f.Contexts {
+		if c == context {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether text contains the filter phrase (case-insensitive)
+func (f Filter) Matches(text string) bool {
+	phrase := strings.ToLower(strings.TrimSpace(f.Phrase))
+	if phrase == "" {
+		return false
+	}
+	text = strings.ToLower(text)
+
+	if !f.WholeWord {
+		return strings.Contains(text, phrase)
+	}
+
+	// Whole word: the characters around a match must not be letters, digits or underscores
+	for start := 0; start < len(text); {
+		idx := strings.Index(text[start:], phrase)
+		if idx < 0 {
+			return false
+		}
+		idx += start
+		end := idx + len(phrase)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:idx])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (idx == 0 || !isWordRune(before)) && (end == len(text) || !isWordRune(after)) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(text[idx:])
+		start = idx + size
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// MatchFilters returns the active filter that applies to text in context.
+// Hide filters take precedence over warn filters.
+func MatchFilters(filters []Filter, context, text string, now time.Time) (Filter, bool) {
+	var match Filter
+	found := false
+	for _, f := range filters {
+		if f.IsExpired(now) || !f.HasContext(context) || !f.Matches(text) {
+			continue
+		}
+		if f.Action == FilterActionHide {
+			return f, true
+		}
+		if !found {
+			match = f
+			found = true
+		}
+	}
+	return match, found
+}
+
+// FilterHomePosts drops posts matching hide filters and marks posts matching
+// warn filters with FilterWarning (the matched phrase)
+func FilterHomePosts(posts []HomePost, filters []Filter, context string, now time.Time) []HomePost {
+	if len(filters) == 0 {
+		return posts
+	}
+	kept := make([]HomePost, 0, len(posts))
+	for _, post := range posts {
+		if f, ok := MatchFilters(filters, context, post.Content, now); ok {
+			if f.Action == FilterActionHide {
+				continue
+			}
+			post.FilterWarning = f.Phrase
+		}
+		kept = append(kept, post)
+	}
+	return kept
+}
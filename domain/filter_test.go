@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestFilterMatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		phrase    string
+		wholeWord bool
+		text      string
+		want      bool
+	}{
+		{"substring", "spoil", false, "No SPOILERS please", true},
+		{"no match", "golang", false, "rust all the way", false},
+		{"whole word match", "cat", true, "my cat is cute", true},
+		{"whole word at edges", "cat", true, "cat", true},
+		{"whole word inside word", "cat", true, "concatenate", false},
+		{"whole word with punctuation", "cat", true, "look, a cat!", true},
+		{"whole word later occurrence", "cat", true, "concat and cat", true},
+		{"phrase", "season finale", true, "The Season Finale was wild", true},
+		{"unicode boundary", "café", true, "le café, s'il vous plaît", true},
+		{"empty phrase", "  ", false, "anything", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Filter{Phrase: tt.phrase, WholeWord: tt.wholeWord}
+			if got := f.Matches(tt.text); got != tt.want {
+				t.Errorf("Matches(%q) with phrase %q = %v, want %v", tt.text, tt.phrase, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchFilters(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	filters := []Filter{
+		{Id: uuid.New(), Phrase: "expired", Contexts: []string{FilterContextHome}, Action: FilterActionHide, ExpiresAt: &past},
+		{Id: uuid.New(), Phrase: "finale", Contexts: []string{FilterContextHome}, Action: FilterActionWarn, ExpiresAt: &future},
+		{Id: uuid.New(), Phrase: "crypto", Contexts: []string{FilterContextGlobal}, Action: FilterActionHide},
+		{Id: uuid.New(), Phrase: "finale", Contexts: []string{FilterContextHome}, Action: FilterActionHide},
+	}
+
+	if _, ok := MatchFilters(filters, FilterContextHome, "expired stuff", now); ok {
+		t.Error("Expected expired filter to be ignored")
+	}
+	if _, ok := MatchFilters(filters, FilterContextHome, "crypto news", now); ok {
+		t.Error("Expected global-only filter to be ignored in home")
+	}
+	if f, ok := MatchFilters(filters, FilterContextGlobal, "crypto news", now); !ok || f.Action != FilterActionHide {
+		t.Error("Expected crypto to be hidden in global")
+	}
+	// Hide wins over warn for the same text
+	if f, ok := MatchFilters(filters, FilterContextHome, "the finale", now); !ok || f.Action != FilterActionHide {
+		t.Errorf("Expected hide filter to take precedence, got %+v", f)
+	}
+}
+
+func TestFilterHomePosts(t *testing.T) {
+	now := time.Now()
+	filters := []Filter{
+		{Phrase: "spoiler", Contexts: []string{FilterContextHome}, Action: FilterActionWarn},
+		{Phrase: "politics", WholeWord: true, Contexts: []string{FilterContextHome}, Action: FilterActionHide},
+	}
+	posts := []HomePost{
+		{Content: "big spoiler ahead"},
+		{Content: "talking politics"},
+		{Content: "nice weather"},
+	}
+
+	got := FilterHomePosts(posts, filters, FilterContextHome, now)
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 posts after filtering, got %d", len(got))
+	}
+	if got[0].FilterWarning != "spoiler" {
+		t.Errorf("Expected first post collapsed behind 'spoiler', got %q", got[0].FilterWarning)
+	}
+	if got[1].FilterWarning != "" {
+		t.Errorf("Expected unfiltered post, got warning %q", got[1].FilterWarning)
+	}
+
+	// Other contexts are not affected
+	if got := FilterHomePosts(posts, filters, FilterContextGlobal, now); len(got) != 3 {
+		t.Errorf("Expected no filtering in global context, got %d posts", len(got))
+	}
+}
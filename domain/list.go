@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// List is a named group of followed accounts with its own timeline
+type List struct {
+	Id              uuid.UUID
+	AccountId       uuid.UUID // Owner of the list
+	Name            string
+	ExcludeFromHome bool // Members' posts are shown only in the list timeline, not in home
+	MemberCount     int  // Number of accounts in the list (filled by read queries)
+	CreatedAt       time.Time
+}
@@ -14,25 +14,30 @@ type SaveNote struct {
 
 // GlobalTimelinePost represents a post in the global timeline (local + federated)
 type GlobalTimelinePost struct {
-	NoteId      string
-	Username    string
-	UserDomain  string
-	ProfileURL  string
-	ObjectURI   string // ActivityPub object id (canonical URI, for replies/likes)
-	ObjectURL   string // ActivityPub object url (human-readable web UI link, preferred for display)
-	IsRemote    bool
-	Message     string
-	CreatedAt   time.Time
-	ReplyCount  int
-	LikeCount   int
-	BoostCount  int
-	BoostedBy   string // if non-empty, this post was boosted by this user (e.g., "@alice" or "@bob@domain")
+	NoteId     string
+	Username   string
+	UserDomain string
+	ProfileURL string
+	ObjectURI  string // ActivityPub object id (canonical URI, for replies/likes)
+	ObjectURL  string // ActivityPub object url (human-readable web UI link, preferred for display)
+	IsRemote   bool
+	Message    string
+	CreatedAt  time.Time
+	ReplyCount int
+	LikeCount  int
+	BoostCount int
+	BoostedBy  string            // if non-empty, this post was boosted by this user (e.g., "@alice" or "@bob@domain")
+	Reactions  []ReactionCount   // per-emoji reaction counts
+	Emojis     map[string]string // custom emoji used in the post (shortcode -> image URL)
+
+	FilterWarning string // if non-empty, the post matched this keyword filter phrase and is collapsed
 }
 
 type Note struct {
 	Id        uuid.UUID
 	CreatedBy string
 	Message   string
+	Title     string // Article title (empty for short notes)
 	CreatedAt time.Time
 	EditedAt  *time.Time // When the note was last edited (nil if never edited)
 	// ActivityPub fields
@@ -48,6 +53,11 @@ type Note struct {
 	BoostCount int // Number of boosts
 }
 
+// IsArticle reports whether the note is a long-form article rather than a short note
+func (note *Note) IsArticle() bool {
+	return note.Title != ""
+}
+
 func (note *Note) ToString() string {
 	return fmt.Sprintf("\n\tId: %s \n\tCreatedBy: %s \n\tMessage: %s \n\tCreatedAt: %s)", note.Id, note.CreatedBy, note.Message, note.CreatedAt)
 }
@@ -58,12 +68,17 @@ type HomePost struct {
 	Author     string // @user (local) or @user@domain (remote)
 	Content    string
 	Time       time.Time
-	ObjectURI  string    // ActivityPub object id (canonical URI, returns JSON)
-	ObjectURL  string    // ActivityPub object url (human-readable web UI link, preferred for display)
-	IsLocal    bool      // true = local note, false = remote activity
-	NoteID     uuid.UUID // only set for local posts (for editing/deleting)
-	ReplyCount int       // number of replies to this post
-	LikeCount  int       // number of likes on this post
-	BoostCount int       // number of boosts on this post
-	BoostedBy  string    // if non-empty, this post was boosted by this user (e.g., "@alice" or "@bob@domain")
+	ObjectURI  string            // ActivityPub object id (canonical URI, returns JSON)
+	ObjectURL  string            // ActivityPub object url (human-readable web UI link, preferred for display)
+	IsLocal    bool              // true = local note, false = remote activity
+	NoteID     uuid.UUID         // only set for local posts (for editing/deleting)
+	ReplyCount int               // number of replies to this post
+	LikeCount  int               // number of likes on this post
+	BoostCount int               // number of boosts on this post
+	BoostedBy  string            // if non-empty, this post was boosted by this user (e.g., "@alice" or "@bob@domain")
+	ViaHashtag string            // if non-empty, this post is shown because the user follows this hashtag (without #)
+	Reactions  []ReactionCount   // per-emoji reaction counts
+	Emojis     map[string]string // custom emoji used in the post (shortcode -> image URL)
+
+	FilterWarning string // if non-empty, the post matched this keyword filter phrase and is collapsed
 }
@@ -11,26 +11,30 @@ import (
 type NotificationType string
 
 const (
-	NotificationFollow  NotificationType = "follow"
-	NotificationLike    NotificationType = "like"
-	NotificationBoost   NotificationType = "boost"
-	NotificationReply   NotificationType = "reply"
-	NotificationMention NotificationType = "mention"
+	NotificationFollow   NotificationType = "follow"
+	NotificationLike     NotificationType = "like"
+	NotificationBoost    NotificationType = "boost"
+	NotificationReply    NotificationType = "reply"
+	NotificationMention  NotificationType = "mention"
+	NotificationReaction NotificationType = "reaction"
 )
 
 // Notification represents a user notification
 type Notification struct {
 	Id               uuid.UUID
 	AccountId        uuid.UUID        // The local user receiving the notification
-	NotificationType NotificationType // follow, like, reply, mention
+	NotificationType NotificationType // follow, like, reply, mention, reaction
 	ActorId          uuid.UUID        // The account that triggered the notification (local or remote)
 	ActorUsername    string           // Denormalized for display (e.g., "alice")
 	ActorDomain      string           // Denormalized for display (e.g., "mastodon.social", empty for local)
 	NoteId           uuid.UUID        // Reference to the note (for like/reply/mention)
 	NoteURI          string           // ActivityPub URI of the note
 	NotePreview      string           // First 100 chars of note content
+	Emoji            string           // Reaction emoji (only for reaction notifications)
 	Read             bool             // Whether the notification has been read
 	CreatedAt        time.Time
+
+	FilterWarning string // Keyword filter phrase the preview matched (display only, not stored)
 }
 
 // ActorHandle returns the formatted @user or @user@domain string
@@ -54,6 +58,8 @@ func (n *Notification) TypeLabel() string {
 		return "replied to your post"
 	case NotificationMention:
 		return "mentioned you"
+	case NotificationReaction:
+		return "reacted to your post"
 	default:
 		return ""
 	}
@@ -72,6 +78,11 @@ func (n *Notification) TypeIcon() string {
 		return "💬"
 	case NotificationMention:
 		return "@"
+	case NotificationReaction:
+		if n.Emoji != "" {
+			return n.Emoji
+		}
+		return "✨"
 	default:
 		return "•"
 	}
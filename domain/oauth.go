@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OAuthOOBRedirect is the redirect URI for apps that show the authorization code to the user
+const OAuthOOBRedirect = "urn:ietf:wg:oauth:2.0:oob"
+
+// OAuth authorization request states
+const (
+	OAuthAuthorizationPending  = "pending"
+	OAuthAuthorizationApproved = "approved"
+	OAuthAuthorizationDenied   = "denied"
+)
+
+// OAuthApp is a client application registered through the Mastodon client API
+type OAuthApp struct {
+	Id           uuid.UUID
+	ClientId     string
+	ClientSecret string
+	Name         string
+	Website      string
+	RedirectURIs []string
+	Scopes       []string
+	CreatedAt    time.Time
+}
+
+// AllowsRedirect reports whether uri is one of the app's registered redirect URIs
+func (app *OAuthApp) AllowsRedirect(uri string) bool {
+	for _, r := range app.RedirectURIs {
+		if r == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// OAuthAuthorization is a pending authorization request.
+// The user confirms UserCode in the TUI, which attaches the account and issues Code.
+type OAuthAuthorization struct {
+	Id          uuid.UUID
+	AppId       uuid.UUID
+	AppName     string // Joined from oauth_apps for display
+	UserCode    string // Short code shown in the browser and typed into the TUI
+	RedirectURI string
+	Scopes      []string
+	State       string
+	Status      string
+	AccountId   uuid.UUID // Set once approved
+	Code        string    // Authorization code exchanged for a token (set once approved)
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+}
+
+// IsExpired reports whether the authorization request can no longer be used
+func (a *OAuthAuthorization) IsExpired(now time.Time) bool {
+	return now.After(a.ExpiresAt)
+}
+
+// OAuthToken is an issued access token. Only a hash of the token is stored.
+type OAuthToken struct {
+	Id         uuid.UUID
+	TokenHash  string
+	AppId      uuid.UUID
+	AppName    string    // Joined from oauth_apps for display
+	AccountId  uuid.UUID // uuid.Nil for app-only tokens (client_credentials grant)
+	Scopes     []string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+}
+
+// ParseOAuthScopes splits a space separated scope string (as sent by clients)
+func ParseOAuthScopes(scopes string) []string {
+	return strings.Fields(strings.ReplaceAll(scopes, "+", " "))
+}
+
+// HasOAuthScope reports whether the granted scopes cover the required one.
+// A top-level scope covers its sub-scopes ("read" covers "read:statuses"),
+// and the legacy "follow" scope covers reading and changing follows.
+func HasOAuthScope(granted []string, required string) bool {
+	for _, g := range granted {
+		if g == required || strings.HasPrefix(required, g+":") {
+			return true
+		}
+		if g == "follow" && (required == "read:follows" || required == "write:follows") {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeOAuthUserCode brings a typed user code into the canonical XXXX-XXXX form
+func NormalizeOAuthUserCode(code string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	normalized := b.String()
+	if len(normalized) == 8 {
+		return normalized[:4] + "-" + normalized[4:]
+	}
+	return normalized
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "testing"
+
+func TestHasOAuthScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		granted  []string
+		required string
+		want     bool
+	}{
+		{"exact", []string{"read:statuses"}, "read:statuses", true},
+		{"top-level covers sub-scope", []string{"read"}, "read:notifications", true},
+		{"sub-scope does not cover top-level", []string{"read:statuses"}, "read", false},
+		{"other sub-scope", []string{"read:statuses"}, "read:accounts", false},
+		{"write does not cover read", []string{"write"}, "read:statuses", false},
+		{"prefix is not a scope", []string{"rea"}, "read:statuses", false},
+		{"follow covers follows", []string{"follow"}, "write:follows", true},
+		{"follow does not cover statuses", []string{"follow"}, "write:statuses", false},
+		{"nothing granted", nil, "read", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasOAuthScope(tt.granted, tt.required); got != tt.want {
+				t.Errorf("HasOAuthScope(%v, %q) = %v, want %v", tt.granted, tt.required, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOAuthScopes(t *testing.T) {
+	got := ParseOAuthScopes("read+write  follow")
+	if len(got) != 3 || got[0] != "read" || got[1] != "write" || got[2] != "follow" {
+		t.Errorf("Unexpected scopes: %v", got)
+	}
+	if got := ParseOAuthScopes(""); len(got) != 0 {
+		t.Errorf("Expected no scopes, got %v", got)
+	}
+}
+
+func TestNormalizeOAuthUserCode(t *testing.T) {
+	tests := map[string]string{
+		"ABCD-EFGH":   "ABCD-EFGH",
+		"abcdefgh":    "ABCD-EFGH",
+		" abcd efgh ": "ABCD-EFGH",
+		"ab-cd-ef-gh": "ABCD-EFGH",
+		"abc":         "ABC",
+	}
+	for in, want := range tests {
+		if got := NormalizeOAuthUserCode(in); got != want {
+			t.Errorf("NormalizeOAuthUserCode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduledNote is a note waiting to be published at ScheduledAt
+type ScheduledNote struct {
+	Id          uuid.UUID
+	AccountId   uuid.UUID
+	Message     string
+	ScheduledAt time.Time
+	CreatedAt   time.Time
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// SchemaStatus describes how far a database's schema is from the one this version migrates to
+type SchemaStatus struct {
+	JournalMode    string      // SQLite journal mode ("wal" once a server has opened the database), "postgres" on PostgreSQL
+	Tables         int         // Number of expected tables that exist
+	MissingTables  []string    // Tables the migrations would create
+	MissingColumns []string    // Columns the migrations would add, as "table.column"
+	Migrations     []Migration // The numbered migrations, oldest first
+}
+
+// UpToDate reports whether all migrations have been applied
+func (s *SchemaStatus) UpToDate() bool {
+	return len(s.MissingTables) == 0 && len(s.MissingColumns) == 0 && len(s.Pending()) == 0
+}
+
+// Pending returns the migrations that have not been applied yet
+func (s *SchemaStatus) Pending() []Migration {
+	var pending []Migration
+	for _, m := range s.Migrations {
+		if !m.Applied {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// Migration is a numbered schema migration and whether it has been applied
+type Migration struct {
+	Version    int
+	Name       string
+	Reversible bool      // Has a down migration
+	Applied    bool      // Recorded in schema_migrations
+	AppliedAt  time.Time // Zero unless applied
+	Modified   bool      // Applied with a different checksum than the current code
+}
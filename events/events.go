@@ -0,0 +1,162 @@
+// Package events is an in-process publish/subscribe bus. The database and the
+// federation code publish what changed, and every TUI session subscribes so its
+// views can update as soon as something happens instead of polling.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind identifies what happened
+type Kind int
+
+const (
+	NoteCreated         Kind = iota // A local note was published; AccountId is the author
+	ActivityReceived                // An incoming activity was processed; ActivityType is set
+	NotificationCreated             // AccountId is the account that was notified
+	FollowAccepted                  // A remote account accepted a follow; AccountId is the follower
+)
+
+var kindNames = map[Kind]string{
+	NoteCreated:         "note_created",
+	ActivityReceived:    "activity_received",
+	NotificationCreated: "notification_created",
+	FollowAccepted:      "follow_accepted",
+}
+
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Event describes one change
+type Event struct {
+	Kind         Kind
+	AccountId    uuid.UUID // Local account the event concerns (uuid.Nil if none)
+	NoteId       uuid.UUID // Set for NoteCreated
+	ActivityType string    // Set for ActivityReceived, e.g. "Create" or "Like"
+	ActorId      uuid.UUID // Set for ActivityReceived: the stored remote account that sent it (uuid.Nil if unknown)
+	FromRelay    bool      // Set for ActivityReceived: forwarded by a relay
+}
+
+// subscriptionBuffer is how many events a subscriber may fall behind before
+// further events are dropped for it
+const subscriptionBuffer = 64
+
+// Bus delivers published events to all current subscribers
+type Bus struct {
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+// NewBus creates an empty bus
+func NewBus() *Bus {
+	return &Bus{subs: map[*Subscription]struct{}{}}
+}
+
+// Publish sends e to every subscriber without blocking.
+// A subscriber whose buffer is full misses the event.
+func (b *Bus) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		select {
+		case sub.ch <- e:
+		default:
+		}
+	}
+}
+
+// Subscribe registers a new subscriber. Close it when it is no longer read.
+func (b *Bus) Subscribe() *Subscription {
+	sub := &Subscription{bus: b, ch: make(chan Event, subscriptionBuffer)}
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// Subscription receives the events published after it was created
+type Subscription struct {
+	bus  *Bus
+	ch   chan Event
+	once sync.Once
+}
+
+// Events returns the channel the events arrive on. It is closed by Close.
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Close unsubscribes and closes the events channel. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		close(s.ch)
+		s.bus.mu.Unlock()
+	})
+}
+
+var defaultBus = NewBus()
+
+// Publish sends e to the subscribers of the process-wide bus
+func Publish(e Event) {
+	defaultBus.Publish(e)
+}
+
+// Subscribe subscribes to the process-wide bus
+func Subscribe() *Subscription {
+	return defaultBus.Subscribe()
+}
+
+// ReloadDelay is how long a view or stream waits after an event before it reloads,
+// so a burst of events (e.g. from a busy relay) causes a single reload
+const ReloadDelay = 500 * time.Millisecond
+
+// timelineActivities are the incoming activity types that can change a timeline
+var timelineActivities = map[string]bool{
+	"Create": true, "Update": true, "Delete": true, "Announce": true,
+	"Like": true, "EmojiReact": true, "Undo": true,
+}
+
+// ChangesTimeline reports whether an event can change the timelines of accountId
+func ChangesTimeline(e Event, accountId uuid.UUID) bool {
+	switch e.Kind {
+	case NoteCreated:
+		return true
+	case ActivityReceived:
+		return timelineActivities[e.ActivityType]
+	case FollowAccepted:
+		return e.AccountId == accountId
+	}
+	return false
+}
+
+// Follows is what an account's home timeline shows besides its own posts
+type Follows struct {
+	Accounts map[uuid.UUID]bool // Followed local and remote accounts
+	Hashtags bool               // Follows hashtags, which bring in posts of any account
+}
+
+// ChangesHomeTimeline narrows ChangesTimeline to the events that can change the home
+// timeline of accountId: its own posts, those of the accounts it follows and relay posts.
+// Without follows (not loaded yet) it is the same as ChangesTimeline.
+func ChangesHomeTimeline(e Event, accountId uuid.UUID, follows *Follows) bool {
+	changes := ChangesTimeline(e, accountId)
+	if !changes || follows == nil {
+		return changes
+	}
+	switch e.Kind {
+	case NoteCreated:
+		return e.AccountId == accountId || follows.Accounts[e.AccountId] || follows.Hashtags
+	case ActivityReceived:
+		return e.FromRelay || follows.Accounts[e.ActorId] || (follows.Hashtags && e.ActivityType == "Create")
+	}
+	return true
+}
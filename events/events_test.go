@@ -0,0 +1,119 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestPublishSubscribe(t *testing.T) {
+	bus := NewBus()
+	first := bus.Subscribe()
+	second := bus.Subscribe()
+	defer first.Close()
+	defer second.Close()
+
+	accountId := uuid.New()
+	bus.Publish(Event{Kind: NotificationCreated, AccountId: accountId})
+
+	for _, sub := range []*Subscription{first, second} {
+		select {
+		case e := <-sub.Events():
+			if e.Kind != NotificationCreated || e.AccountId != accountId {
+				t.Errorf("Unexpected event %+v", e)
+			}
+		default:
+			t.Fatal("Expected the event to be delivered to every subscriber")
+		}
+	}
+}
+
+func TestPublishDoesNotBlock(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe()
+	defer sub.Close()
+
+	// Nobody reads, so everything beyond the buffer is dropped
+	for i := 0; i < subscriptionBuffer+10; i++ {
+		bus.Publish(Event{Kind: ActivityReceived, ActivityType: "Create"})
+	}
+	if len(sub.Events()) != subscriptionBuffer {
+		t.Errorf("Expected %d buffered events, got %d", subscriptionBuffer, len(sub.Events()))
+	}
+}
+
+func TestClose(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe()
+	sub.Close()
+	sub.Close()
+
+	if _, ok := <-sub.Events(); ok {
+		t.Error("Expected the events channel to be closed")
+	}
+	// Publishing after Close must not panic on the closed channel
+	bus.Publish(Event{Kind: NoteCreated})
+	if len(bus.subs) != 0 {
+		t.Errorf("Expected no subscribers, got %d", len(bus.subs))
+	}
+}
+
+func TestKindString(t *testing.T) {
+	if FollowAccepted.String() != "follow_accepted" {
+		t.Errorf("Unexpected name %q", FollowAccepted.String())
+	}
+	if Kind(99).String() != "unknown" {
+		t.Errorf("Expected unknown for an undefined kind")
+	}
+}
+
+func TestChangesTimeline(t *testing.T) {
+	accountId := uuid.New()
+	tests := []struct {
+		event Event
+		want  bool
+	}{
+		{Event{Kind: NoteCreated, AccountId: uuid.New()}, true},
+		{Event{Kind: ActivityReceived, ActivityType: "Create"}, true},
+		{Event{Kind: ActivityReceived, ActivityType: "Follow"}, false},
+		{Event{Kind: FollowAccepted, AccountId: accountId}, true},
+		{Event{Kind: FollowAccepted, AccountId: uuid.New()}, false},
+		{Event{Kind: NotificationCreated, AccountId: accountId}, false},
+	}
+	for _, tt := range tests {
+		if got := ChangesTimeline(tt.event, accountId); got != tt.want {
+			t.Errorf("ChangesTimeline(%+v) = %v, want %v", tt.event, got, tt.want)
+		}
+	}
+}
+
+func TestChangesHomeTimeline(t *testing.T) {
+	accountId := uuid.New()
+	followed := uuid.New()
+	follows := &Follows{Accounts: map[uuid.UUID]bool{followed: true}}
+	withHashtags := &Follows{Accounts: map[uuid.UUID]bool{}, Hashtags: true}
+	tests := []struct {
+		name    string
+		event   Event
+		follows *Follows
+		want    bool
+	}{
+		{"own note", Event{Kind: NoteCreated, AccountId: accountId}, follows, true},
+		{"followed note", Event{Kind: NoteCreated, AccountId: followed}, follows, true},
+		{"other note", Event{Kind: NoteCreated, AccountId: uuid.New()}, follows, false},
+		{"other note with hashtags", Event{Kind: NoteCreated, AccountId: uuid.New()}, withHashtags, true},
+		{"followed actor", Event{Kind: ActivityReceived, ActivityType: "Like", ActorId: followed}, follows, true},
+		{"other actor", Event{Kind: ActivityReceived, ActivityType: "Create", ActorId: uuid.New()}, follows, false},
+		{"other actor with hashtags", Event{Kind: ActivityReceived, ActivityType: "Create"}, withHashtags, true},
+		{"other actor like with hashtags", Event{Kind: ActivityReceived, ActivityType: "Like"}, withHashtags, false},
+		{"relay", Event{Kind: ActivityReceived, ActivityType: "Announce", FromRelay: true}, follows, true},
+		{"follow accepted", Event{Kind: FollowAccepted, AccountId: accountId}, follows, true},
+		{"follows not loaded", Event{Kind: ActivityReceived, ActivityType: "Create"}, nil, true},
+		{"unrelated activity", Event{Kind: ActivityReceived, ActivityType: "Follow", ActorId: followed}, follows, false},
+	}
+	for _, tt := range tests {
+		if got := ChangesHomeTimeline(tt.event, accountId, tt.follows); got != tt.want {
+			t.Errorf("%s: ChangesHomeTimeline = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
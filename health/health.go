@@ -0,0 +1,178 @@
+// Package health answers whether the server is alive and ready to serve, and
+// gathers the server status shown on /status and in the admin panel.
+package health
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/deemkeen/stegodon/activitypub"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+)
+
+// Readiness check names
+const (
+	CheckDatabase   = "database"
+	CheckMigrations = "migrations"
+	CheckSSH        = "ssh"
+)
+
+var (
+	startedAt    = time.Now()
+	sshListening atomic.Bool
+)
+
+// SetSSHListening records whether the SSH server is accepting connections
+func SetSSHListening(up bool) {
+	sshListening.Store(up)
+}
+
+// Source provides the database reads behind readiness and status
+type Source interface {
+	Ping() error
+	ReadSchemaStatus() (error, *domain.SchemaStatus)
+	ReadDatabaseSize() (int64, error)
+	ReadDeliveryQueueStats() (depth int, oldest time.Time, err error)
+	CountInboxQueue() (pending int, held int, err error)
+	ReadAllRelays() (error, *[]domain.Relay)
+}
+
+// Check is the result of one readiness check
+type Check struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Ready runs the readiness checks: the database answers, all migrations are
+// applied and the SSH server is listening. It reports whether all of them passed.
+func Ready(source Source) (bool, []Check) {
+	checks := []Check{{Name: CheckDatabase}, {Name: CheckMigrations}, {Name: CheckSSH}}
+
+	if err := source.Ping(); err != nil {
+		checks[0].Error = err.Error()
+	} else {
+		checks[0].OK = true
+	}
+
+	if err, schema := source.ReadSchemaStatus(); err != nil {
+		checks[1].Error = err.Error()
+	} else if !schema.UpToDate() {
+		checks[1].Error = "pending migrations"
+	} else {
+		checks[1].OK = true
+	}
+
+	if sshListening.Load() {
+		checks[2].OK = true
+	} else {
+		checks[2].Error = "ssh server is not listening"
+	}
+
+	ready := true
+	for _, check := range checks {
+		ready = ready && check.OK
+	}
+	return ready, checks
+}
+
+// Status is a snapshot of the running server
+type Status struct {
+	Version                string        `json:"version"`
+	StartedAt              time.Time     `json:"started_at"`
+	UptimeSeconds          int64         `json:"uptime_seconds"`
+	Ready                  bool          `json:"ready"`
+	Checks                 []Check       `json:"checks"`
+	DeliveryQueue          DeliveryQueue `json:"delivery_queue"`
+	InboxQueue             InboxQueue    `json:"inbox_queue"`
+	LastSuccessfulDelivery *time.Time    `json:"last_successful_delivery"` // nil if nothing was delivered since the start
+	Relays                 []RelayState  `json:"relays"`
+	DatabaseBytes          int64         `json:"database_bytes"` // Main database file, without the WAL
+}
+
+// DeliveryQueue summarizes the outgoing delivery queue
+type DeliveryQueue struct {
+	Depth    int        `json:"depth"`
+	OldestAt *time.Time `json:"oldest_at"` // nil when the queue is empty
+}
+
+// InboxQueue summarizes the incoming activity queue
+type InboxQueue struct {
+	Pending int `json:"pending"`
+	Held    int `json:"held"`
+}
+
+// RelayState is the subscription state of one relay
+type RelayState struct {
+	ActorURI string `json:"actor_uri"`
+	Name     string `json:"name"`
+	Status   string `json:"status"` // pending, active or failed
+	Paused   bool   `json:"paused"`
+}
+
+// Uptime returns how long the server has been running
+func (s *Status) Uptime() time.Duration {
+	return time.Duration(s.UptimeSeconds) * time.Second
+}
+
+// ReadStatus gathers the server status. Values whose query failed are left
+// empty and the first error is returned next to the partial status.
+func ReadStatus(source Source) (*Status, error) {
+	ready, checks := Ready(source)
+	status := &Status{
+		Version:       util.GetVersion(),
+		StartedAt:     startedAt,
+		UptimeSeconds: int64(time.Since(startedAt).Seconds()),
+		Ready:         ready,
+		Checks:        checks,
+		Relays:        []RelayState{},
+	}
+
+	var firstErr error
+	keep := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if depth, oldest, err := source.ReadDeliveryQueueStats(); err != nil {
+		keep(err)
+	} else {
+		status.DeliveryQueue.Depth = depth
+		if depth > 0 {
+			status.DeliveryQueue.OldestAt = &oldest
+		}
+	}
+
+	if pending, held, err := source.CountInboxQueue(); err != nil {
+		keep(err)
+	} else {
+		status.InboxQueue = InboxQueue{Pending: pending, Held: held}
+	}
+
+	if last := activitypub.LastSuccessfulDelivery(); !last.IsZero() {
+		status.LastSuccessfulDelivery = &last
+	}
+
+	if err, relays := source.ReadAllRelays(); err != nil {
+		keep(err)
+	} else {
+		for _, relay := range *relays {
+			status.Relays = append(status.Relays, RelayState{
+				ActorURI: relay.ActorURI,
+				Name:     relay.Name,
+				Status:   relay.Status,
+				Paused:   relay.Paused,
+			})
+		}
+	}
+
+	if size, err := source.ReadDatabaseSize(); err != nil {
+		keep(err)
+	} else {
+		status.DatabaseBytes = size
+	}
+
+	return status, firstErr
+}
@@ -0,0 +1,92 @@
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+)
+
+// mockSource implements Source for testing
+type mockSource struct {
+	pingErr  error
+	schema   *domain.SchemaStatus
+	relayErr error
+}
+
+func (m *mockSource) Ping() error { return m.pingErr }
+func (m *mockSource) ReadSchemaStatus() (error, *domain.SchemaStatus) {
+	if m.schema == nil {
+		return nil, &domain.SchemaStatus{}
+	}
+	return nil, m.schema
+}
+func (m *mockSource) ReadDatabaseSize() (int64, error) { return 4096, nil }
+func (m *mockSource) ReadDeliveryQueueStats() (int, time.Time, error) {
+	return 2, time.Now().Add(-time.Minute), nil
+}
+func (m *mockSource) CountInboxQueue() (int, int, error) { return 3, 1, nil }
+func (m *mockSource) ReadAllRelays() (error, *[]domain.Relay) {
+	if m.relayErr != nil {
+		return m.relayErr, nil
+	}
+	return nil, &[]domain.Relay{{ActorURI: "https://relay.example.com/actor", Status: "active", Paused: true}}
+}
+
+func TestReady(t *testing.T) {
+	SetSSHListening(true)
+	defer SetSSHListening(false)
+
+	ready, checks := Ready(&mockSource{})
+	if !ready {
+		t.Fatalf("Expected ready, got %+v", checks)
+	}
+
+	ready, checks = Ready(&mockSource{pingErr: errors.New("disk I/O error")})
+	if ready || checks[0].OK || checks[0].Error != "disk I/O error" {
+		t.Errorf("Expected the database check to fail, got %+v", checks)
+	}
+
+	ready, checks = Ready(&mockSource{schema: &domain.SchemaStatus{MissingColumns: []string{"notes.title"}}})
+	if ready || checks[1].OK {
+		t.Errorf("Expected the migrations check to fail, got %+v", checks)
+	}
+
+	SetSSHListening(false)
+	ready, checks = Ready(&mockSource{})
+	if ready || checks[2].Name != CheckSSH || checks[2].OK {
+		t.Errorf("Expected the ssh check to fail, got %+v", checks)
+	}
+}
+
+func TestReadStatus(t *testing.T) {
+	status, err := ReadStatus(&mockSource{})
+	if err != nil {
+		t.Fatalf("ReadStatus() error: %v", err)
+	}
+	if status.Version == "" || status.StartedAt.IsZero() {
+		t.Errorf("Expected version and start time, got %+v", status)
+	}
+	if status.DeliveryQueue.Depth != 2 || status.DeliveryQueue.OldestAt == nil {
+		t.Errorf("Unexpected delivery queue: %+v", status.DeliveryQueue)
+	}
+	if status.InboxQueue.Pending != 3 || status.InboxQueue.Held != 1 {
+		t.Errorf("Unexpected inbox queue: %+v", status.InboxQueue)
+	}
+	if len(status.Relays) != 1 || !status.Relays[0].Paused {
+		t.Errorf("Unexpected relays: %+v", status.Relays)
+	}
+	if status.DatabaseBytes != 4096 {
+		t.Errorf("Expected 4096 database bytes, got %d", status.DatabaseBytes)
+	}
+
+	// A failed read still returns the rest of the status
+	status, err = ReadStatus(&mockSource{relayErr: errors.New("no such table: relays")})
+	if err == nil {
+		t.Error("Expected the relay error to be returned")
+	}
+	if status == nil || status.InboxQueue.Pending != 3 || len(status.Relays) != 0 {
+		t.Errorf("Expected a partial status, got %+v", status)
+	}
+}
@@ -3,18 +3,25 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"time"
 
 	"github.com/deemkeen/stegodon/app"
+	"github.com/deemkeen/stegodon/backup"
+	"github.com/deemkeen/stegodon/cli"
+	"github.com/deemkeen/stegodon/db"
 	"github.com/deemkeen/stegodon/util"
 )
 
 func main() {
 	// Parse command line flags
 	versionFlag := flag.Bool("v", false, "Print version information")
+	migrateOnly := flag.Bool("migrate-only", false, "Apply the database migrations and exit")
+	dryRun := flag.Bool("dry-run", false, "List the pending database migrations without applying them")
 	flag.Parse()
 
 	// Handle version flag
@@ -23,6 +30,22 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Offline admin commands work on the database directly, server running or not
+	if *dryRun {
+		os.Exit(runAdmin([]string{"migrate", "--dry-run"}))
+	}
+	if flag.Arg(0) == "admin" {
+		os.Exit(runAdmin(flag.Args()[1:]))
+	}
+
+	// Backups can be written while the server runs; restoring needs it stopped
+	switch flag.Arg(0) {
+	case "backup":
+		os.Exit(runBackup(flag.Args()[1:]))
+	case "restore":
+		os.Exit(runRestore(flag.Args()[1:]))
+	}
+
 	// Load configuration
 	conf, err := util.ReadConf()
 	if err != nil {
@@ -53,6 +76,13 @@ func main() {
 		log.Fatalf("Failed to create application: %v", err)
 	}
 
+	if *migrateOnly {
+		if err := application.Migrate(); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		return
+	}
+
 	if err := application.Initialize(); err != nil {
 		log.Fatalf("Failed to initialize application: %v", err)
 	}
@@ -62,3 +92,108 @@ func main() {
 		log.Fatalf("Application error: %v", err)
 	}
 }
+
+// runAdmin executes "stegodon admin ..." and returns the process exit code
+func runAdmin(args []string) int {
+	args, jsonMode := cli.ParseAdminArgs(args)
+
+	// The database layer logs every query error; keep stdout for the command output
+	log.SetOutput(io.Discard)
+
+	database, err := openAdminDatabase()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer database.Close()
+
+	if err := cli.NewAdminHandler(os.Stdin, os.Stdout, database, jsonMode).Execute(args); err != nil {
+		return 1
+	}
+	return 0
+}
+
+// openAdminDatabase opens the configured PostgreSQL database, or the SQLite
+// database file when no databaseUrl is set
+func openAdminDatabase() (*db.DB, error) {
+	conf, err := util.ReadConf()
+	if err != nil {
+		return nil, err
+	}
+	if conf.Conf.DatabaseURL != "" {
+		return db.OpenPostgres(conf.Conf.DatabaseURL)
+	}
+	return db.Open(util.ResolveFilePath("database.db"))
+}
+
+// runBackup executes "stegodon backup now" and returns the process exit code
+func runBackup(args []string) int {
+	if len(args) != 1 || args[0] != "now" {
+		fmt.Fprintln(os.Stderr, "Usage: stegodon backup now")
+		return 2
+	}
+	log.SetOutput(io.Discard)
+
+	conf, err := util.ReadConf()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if conf.Conf.DatabaseURL != "" {
+		fmt.Fprintln(os.Stderr, "Error: backups cover the SQLite database, use pg_dump for PostgreSQL")
+		return 1
+	}
+	dir, err := backup.Dir(conf)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	paths := backup.DefaultPaths()
+	database, err := db.Open(paths.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer database.Close()
+
+	archive, err := backup.Create(database, paths, dir, conf.Conf.BackupKeep, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Println(archive)
+	return 0
+}
+
+// runRestore executes "stegodon restore <file>" and returns the process exit code
+func runRestore(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: stegodon restore <backup.tar.gz>  (stop the server first)")
+		return 2
+	}
+	log.SetOutput(io.Discard)
+
+	conf, err := util.ReadConf()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if conf.Conf.DatabaseURL != "" {
+		fmt.Fprintln(os.Stderr, "Error: backups cover the SQLite database, use pg_restore for PostgreSQL")
+		return 1
+	}
+
+	restored, err := backup.Restore(args[0], backup.DefaultPaths(), time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	m := restored.Manifest
+	fmt.Printf("Restored backup from %s (stegodon v%s, schema version %d)\n", m.CreatedAt.Format(time.RFC3339), m.Version, m.SchemaVersion)
+	fmt.Printf("Avatars restored: %d, host key restored: %t\n", restored.Avatars, restored.HostKey)
+	for _, path := range restored.Previous {
+		fmt.Printf("Previous file kept at %s\n", path)
+	}
+	return 0
+}
@@ -0,0 +1,209 @@
+// Package metrics exposes Prometheus metrics for the SSH, HTTP, federation
+// and database layers. The metrics are served on their own listen address
+// (metricsAddr in the config) so they never show up on the public web server.
+package metrics
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/deemkeen/stegodon/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Inbox activity results
+const (
+	InboxQueued    = "queued"    // Verified and queued for processing
+	InboxBlocked   = "blocked"   // Dropped because it came through a paused relay
+	InboxRejected  = "rejected"  // Unparseable or could not be queued
+	InboxProcessed = "processed" // Handled by the inbox worker
+	InboxFailed    = "failed"    // Handler failed, will be retried
+	InboxHeld      = "held"      // Failed too often, held for admins
+)
+
+// Delivery results
+const (
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"  // Will be retried
+	DeliveryDropped   = "dropped" // Given up after too many attempts
+)
+
+// Signature failure reasons
+const (
+	SignatureMissing    = "missing"
+	SignatureMalformed  = "malformed"
+	SignatureUnknownKey = "unknown_key" // Signer's actor could not be fetched
+	SignatureInvalid    = "invalid"
+)
+
+// Session kinds
+const (
+	SessionTUI = "tui"
+	SessionCLI = "cli"
+)
+
+// knownActivityTypes bounds the type label; anything else is counted as "Other"
+var knownActivityTypes = map[string]bool{
+	"Create": true, "Update": true, "Delete": true, "Follow": true, "Accept": true,
+	"Reject": true, "Undo": true, "Like": true, "EmojiReact": true, "Announce": true,
+	"Move": true, "Block": true, "Flag": true,
+}
+
+var registry = prometheus.NewRegistry()
+
+var (
+	sshSessions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "stegodon_ssh_sessions",
+		Help: "Active SSH sessions by kind (tui or cli).",
+	}, []string{"kind"})
+
+	tuiViews = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "stegodon_tui_views",
+		Help: "Active TUI sessions by the view they are showing.",
+	}, []string{"view"})
+
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "stegodon_http_requests_total",
+		Help: "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "stegodon_http_request_duration_seconds",
+		Help:    "HTTP request latency by route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route"})
+
+	inboxActivities = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "stegodon_inbox_activities_total",
+		Help: "Incoming ActivityPub activities by type and result.",
+	}, []string{"type", "result"})
+
+	signatureFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "stegodon_signature_failures_total",
+		Help: "Inbox requests rejected for their HTTP signature, by reason.",
+	}, []string{"reason"})
+
+	deliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "stegodon_deliveries_total",
+		Help: "Outgoing ActivityPub deliveries by target host and result.",
+	}, []string{"host", "result"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "stegodon_db_query_duration_seconds",
+		Help:    "SQLite statement latency by operation (query or exec).",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 5},
+	}, []string{"op"})
+
+	buildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "stegodon_build_info",
+		Help: "Always 1, labeled with the running version.",
+	}, []string{"version"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		sshSessions, tuiViews, httpRequests, httpDuration, inboxActivities,
+		signatureFailures, deliveries, dbQueryDuration, buildInfo,
+	)
+	buildInfo.WithLabelValues(util.GetVersion()).Set(1)
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// SSHSessionStarted counts an SSH session of the given kind as active.
+// The returned function marks it as ended.
+func SSHSessionStarted(kind string) func() {
+	gauge := sshSessions.WithLabelValues(kind)
+	gauge.Inc()
+	var once sync.Once
+	return func() { once.Do(gauge.Dec) }
+}
+
+// ObserveHTTPRequest records a finished HTTP request
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(route).Observe(duration.Seconds())
+}
+
+// InboxActivity counts an incoming activity with one of the Inbox* results
+func InboxActivity(activityType, result string) {
+	if !knownActivityTypes[activityType] {
+		activityType = "Other"
+	}
+	inboxActivities.WithLabelValues(activityType, result).Inc()
+}
+
+// SignatureFailure counts an inbox request rejected for one of the Signature* reasons
+func SignatureFailure(reason string) {
+	signatureFailures.WithLabelValues(reason).Inc()
+}
+
+// Delivery counts a delivery attempt to inboxURI with one of the Delivery* results
+func Delivery(inboxURI, result string) {
+	host := "unknown"
+	if u, err := url.Parse(inboxURI); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	deliveries.WithLabelValues(host, result).Inc()
+}
+
+// ObserveDBQuery records the latency of a database statement
+func ObserveDBQuery(op string, duration time.Duration) {
+	dbQueryDuration.WithLabelValues(op).Observe(duration.Seconds())
+}
+
+// ViewTracker follows the view of one TUI session for stegodon_tui_views.
+// A nil tracker ignores all calls.
+type ViewTracker struct {
+	mu     sync.Mutex
+	view   string
+	closed bool
+}
+
+// NewViewTracker creates a tracker for a new TUI session
+func NewViewTracker() *ViewTracker {
+	return &ViewTracker{}
+}
+
+// Set moves the session to view
+func (t *ViewTracker) Set(view string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed || t.view == view {
+		return
+	}
+	if t.view != "" {
+		tuiViews.WithLabelValues(t.view).Dec()
+	}
+	t.view = view
+	tuiViews.WithLabelValues(view).Inc()
+}
+
+// Close removes the session from the view counts when it ends
+func (t *ViewTracker) Close() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return
+	}
+	t.closed = true
+	if t.view != "" {
+		tuiViews.WithLabelValues(t.view).Dec()
+	}
+}
@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestViewTracker(t *testing.T) {
+	home := tuiViews.WithLabelValues("test_home")
+	thread := tuiViews.WithLabelValues("test_thread")
+
+	views := NewViewTracker()
+	views.Set("test_home")
+	views.Set("test_home")
+	if got := testutil.ToFloat64(home); got != 1 {
+		t.Fatalf("Expected 1 session on home, got %v", got)
+	}
+
+	views.Set("test_thread")
+	if testutil.ToFloat64(home) != 0 || testutil.ToFloat64(thread) != 1 {
+		t.Fatalf("Expected the session to move to thread")
+	}
+
+	views.Close()
+	views.Close()
+	views.Set("test_home")
+	if testutil.ToFloat64(home) != 0 || testutil.ToFloat64(thread) != 0 {
+		t.Errorf("Expected a closed session to leave all views")
+	}
+
+	var untracked *ViewTracker
+	untracked.Set("test_home")
+	untracked.Close()
+}
+
+func TestSSHSessionStarted(t *testing.T) {
+	gauge := sshSessions.WithLabelValues("test")
+	ended := SSHSessionStarted("test")
+	if testutil.ToFloat64(gauge) != 1 {
+		t.Fatal("Expected one active session")
+	}
+	ended()
+	ended()
+	if testutil.ToFloat64(gauge) != 0 {
+		t.Errorf("Expected the session to be counted once, got %v", testutil.ToFloat64(gauge))
+	}
+}
+
+func TestInboxActivityBoundsTypes(t *testing.T) {
+	InboxActivity("Follow", InboxQueued)
+	InboxActivity("<script>", InboxQueued)
+
+	if testutil.ToFloat64(inboxActivities.WithLabelValues("Follow", InboxQueued)) != 1 {
+		t.Error("Expected Follow to be counted")
+	}
+	if testutil.ToFloat64(inboxActivities.WithLabelValues("Other", InboxQueued)) != 1 {
+		t.Error("Expected unknown types to be counted as Other")
+	}
+}
+
+func TestDeliveryByHost(t *testing.T) {
+	Delivery("https://mastodon.example/users/alice/inbox", DeliverySucceeded)
+	Delivery("not a url", DeliveryFailed)
+
+	if testutil.ToFloat64(deliveries.WithLabelValues("mastodon.example", DeliverySucceeded)) != 1 {
+		t.Error("Expected delivery to be counted by host")
+	}
+	if testutil.ToFloat64(deliveries.WithLabelValues("unknown", DeliveryFailed)) != 1 {
+		t.Error("Expected unparseable inbox to be counted as unknown")
+	}
+}
+
+// mockStats implements StatsSource for testing
+type mockStats struct{}
+
+func (mockStats) CountAccounts() (int, error)   { return 3, nil }
+func (mockStats) CountLocalPosts() (int, error) { return 42, nil }
+func (mockStats) ReadAllRelays() (error, *[]domain.Relay) {
+	return nil, &[]domain.Relay{{Status: "active"}, {Status: "active", Paused: true}, {Status: "failed"}}
+}
+func (mockStats) ReadDeliveryQueueStats() (int, time.Time, error) {
+	return 5, time.Now().Add(-time.Hour), nil
+}
+func (mockStats) CountInboxQueue() (int, int, error) { return 7, 1, nil }
+
+func TestStatsCollector(t *testing.T) {
+	c := &statsCollector{source: mockStats{}}
+
+	expected := `
+# HELP stegodon_relays Relay subscriptions by status (pending, active, failed or paused).
+# TYPE stegodon_relays gauge
+stegodon_relays{status="active"} 1
+stegodon_relays{status="failed"} 1
+stegodon_relays{status="paused"} 1
+stegodon_relays{status="pending"} 0
+# HELP stegodon_inbox_queue_depth Incoming activities in the inbox queue by state (pending or held).
+# TYPE stegodon_inbox_queue_depth gauge
+stegodon_inbox_queue_depth{state="held"} 1
+stegodon_inbox_queue_depth{state="pending"} 7
+# HELP stegodon_accounts Local accounts.
+# TYPE stegodon_accounts gauge
+stegodon_accounts 3
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected), "stegodon_relays", "stegodon_inbox_queue_depth", "stegodon_accounts"); err != nil {
+		t.Error(err)
+	}
+
+	age, err := testutil.GatherAndCount(registryWith(c), "stegodon_delivery_queue_oldest_age_seconds")
+	if err != nil || age != 1 {
+		t.Errorf("Expected the queue age to be reported, got %d (err %v)", age, err)
+	}
+}
+
+func registryWith(c prometheus.Collector) *prometheus.Registry {
+	r := prometheus.NewPedanticRegistry()
+	r.MustRegister(c)
+	return r
+}
+
+func TestHandler(t *testing.T) {
+	ObserveHTTPRequest("GET", "/users/:actor", 200, 10*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		`stegodon_http_requests_total{method="GET",route="/users/:actor",status="200"} 1`,
+		"stegodon_build_info{version=",
+		"go_goroutines",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected %q in metrics output", want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"log"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// StatsSource provides the values that are read from the database on every scrape
+type StatsSource interface {
+	CountAccounts() (int, error)
+	CountLocalPosts() (int, error)
+	ReadAllRelays() (error, *[]domain.Relay)
+	ReadDeliveryQueueStats() (depth int, oldest time.Time, err error)
+	CountInboxQueue() (pending int, held int, err error)
+}
+
+var (
+	accountsDesc = prometheus.NewDesc("stegodon_accounts",
+		"Local accounts.", nil, nil)
+	localPostsDesc = prometheus.NewDesc("stegodon_local_posts",
+		"Notes written on this server.", nil, nil)
+	relaysDesc = prometheus.NewDesc("stegodon_relays",
+		"Relay subscriptions by status (pending, active, failed or paused).", []string{"status"}, nil)
+	deliveryQueueDepthDesc = prometheus.NewDesc("stegodon_delivery_queue_depth",
+		"Deliveries waiting in the queue, including those waiting for a retry.", nil, nil)
+	deliveryQueueAgeDesc = prometheus.NewDesc("stegodon_delivery_queue_oldest_age_seconds",
+		"Age of the oldest queued delivery (0 when the queue is empty).", nil, nil)
+	inboxQueueDepthDesc = prometheus.NewDesc("stegodon_inbox_queue_depth",
+		"Incoming activities in the inbox queue by state (pending or held).", []string{"state"}, nil)
+)
+
+// statsCollector reads the database counts at scrape time
+type statsCollector struct {
+	source StatsSource
+}
+
+// RegisterStats adds the database counts to the metrics.
+// Call it once, after the database is ready.
+func RegisterStats(source StatsSource) {
+	registry.MustRegister(&statsCollector{source: source})
+}
+
+func (c *statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- accountsDesc
+	ch <- localPostsDesc
+	ch <- relaysDesc
+	ch <- deliveryQueueDepthDesc
+	ch <- deliveryQueueAgeDesc
+	ch <- inboxQueueDepthDesc
+}
+
+// Collect skips the values whose query failed, so one broken count does not fail the scrape
+func (c *statsCollector) Collect(ch chan<- prometheus.Metric) {
+	if n, err := c.source.CountAccounts(); err != nil {
+		log.Printf("Metrics: Failed to count accounts: %v", err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(accountsDesc, prometheus.GaugeValue, float64(n))
+	}
+
+	if n, err := c.source.CountLocalPosts(); err != nil {
+		log.Printf("Metrics: Failed to count local posts: %v", err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(localPostsDesc, prometheus.GaugeValue, float64(n))
+	}
+
+	if err, relays := c.source.ReadAllRelays(); err != nil {
+		log.Printf("Metrics: Failed to read relays: %v", err)
+	} else {
+		counts := map[string]int{"pending": 0, "active": 0, "failed": 0, "paused": 0}
+		for _, relay := range *relays {
+			if relay.Paused {
+				counts["paused"]++
+			} else {
+				counts[relay.Status]++
+			}
+		}
+		for status, n := range counts {
+			ch <- prometheus.MustNewConstMetric(relaysDesc, prometheus.GaugeValue, float64(n), status)
+		}
+	}
+
+	if depth, oldest, err := c.source.ReadDeliveryQueueStats(); err != nil {
+		log.Printf("Metrics: Failed to read delivery queue: %v", err)
+	} else {
+		age := 0.0
+		if depth > 0 {
+			age = time.Since(oldest).Seconds()
+		}
+		ch <- prometheus.MustNewConstMetric(deliveryQueueDepthDesc, prometheus.GaugeValue, float64(depth))
+		ch <- prometheus.MustNewConstMetric(deliveryQueueAgeDesc, prometheus.GaugeValue, age)
+	}
+
+	if pending, held, err := c.source.CountInboxQueue(); err != nil {
+		log.Printf("Metrics: Failed to count inbox queue: %v", err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(inboxQueueDepthDesc, prometheus.GaugeValue, float64(pending), domain.InboxQueuePending)
+		ch <- prometheus.MustNewConstMetric(inboxQueueDepthDesc, prometheus.GaugeValue, float64(held), domain.InboxQueueHeld)
+	}
+}
@@ -8,9 +8,12 @@ import (
 	"github.com/charmbracelet/ssh"
 	"github.com/charmbracelet/wish"
 	bm "github.com/charmbracelet/wish/bubbletea"
+	"github.com/deemkeen/stegodon/actions"
+	"github.com/deemkeen/stegodon/activitypub"
 	"github.com/deemkeen/stegodon/cli"
 	"github.com/deemkeen/stegodon/db"
 	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
 	"github.com/deemkeen/stegodon/ui"
 	"github.com/deemkeen/stegodon/util"
 	"github.com/google/uuid"
@@ -41,6 +44,15 @@ func MainTui() wish.Middleware {
 		lipgloss.SetColorProfile(termenv.ANSI256)
 
 		m := ui.NewModel(*acc, pty.Window.Width, pty.Window.Height)
+		m.TrackViews(sessionViewTracker(s))
+
+		// One event subscription per session, dropped when the connection closes
+		sub := events.Subscribe()
+		go func() {
+			<-s.Context().Done()
+			sub.Close()
+		}()
+		m.SubscribeEvents(sub)
 		return tea.NewProgram(m, tea.WithFPS(60), tea.WithInput(s), tea.WithOutput(s), tea.WithAltScreen())
 	}
 	return bm.MiddlewareWithProgramHandler(teaHandler, termenv.ANSI256)
@@ -65,32 +77,65 @@ func handleCLI(s ssh.Session, cmd []string) {
 	}
 
 	// Create CLI handler and execute command
-	handler := cli.NewHandler(s, &dbWrapper{database}, acc, conf)
+	handler := cli.NewHandler(s.Context(), s, &dbWrapper{db: database, conf: conf}, acc, conf)
 	if err := handler.Execute(cmd); err != nil {
 		// Error already printed by handler
 		return
 	}
 }
 
-// dbWrapper wraps *db.DB to implement cli.Database interface
+// dbWrapper wraps db.Store to implement cli.Database interface
 type dbWrapper struct {
-	db *db.DB
+	db   db.Store
+	conf *util.AppConfig // Used by the actions that federate
 }
 
 func (w *dbWrapper) CreateNote(userId interface{}, message string) (interface{}, error) {
 	return w.db.CreateNote(userId.(uuid.UUID), message)
 }
 
+func (w *dbWrapper) CreateNoteWithReply(userId interface{}, message string, inReplyToURI string) (interface{}, error) {
+	return w.db.CreateNoteWithReply(userId.(uuid.UUID), message, inReplyToURI)
+}
+
+func (w *dbWrapper) CreateArticle(userId interface{}, title string, message string) (interface{}, error) {
+	return w.db.CreateArticle(userId.(uuid.UUID), title, message)
+}
+
+func (w *dbWrapper) CreateScheduledNote(note *domain.ScheduledNote) error {
+	return w.db.CreateScheduledNote(note)
+}
+
+func (w *dbWrapper) ReadDraftsByAccountId(accountId interface{}) (error, *[]domain.Draft) {
+	return w.db.ReadDraftsByAccountId(accountId.(uuid.UUID))
+}
+
+func (w *dbWrapper) PublishDraft(draft *domain.Draft) (interface{}, error) {
+	return w.db.PublishDraft(draft)
+}
+
 func (w *dbWrapper) ReadNoteIdWithReplyInfo(id interface{}) (error, *domain.Note) {
 	return w.db.ReadNoteIdWithReplyInfo(id.(uuid.UUID))
 }
 
-func (w *dbWrapper) ReadHomeTimelinePosts(accountId interface{}, limit int) (error, *[]domain.HomePost) {
-	return w.db.ReadHomeTimelinePosts(accountId.(uuid.UUID), limit)
+func (w *dbWrapper) ReadHomeTimelinePosts(accountId interface{}, before domain.Cursor, limit int) (error, *[]domain.HomePost) {
+	return w.db.ReadHomeTimelinePosts(accountId.(uuid.UUID), before, limit)
+}
+
+func (w *dbWrapper) ReadListByName(accountId interface{}, name string) (error, *domain.List) {
+	return w.db.ReadListByName(accountId.(uuid.UUID), name)
+}
+
+func (w *dbWrapper) ReadListTimelinePosts(accountId interface{}, listId interface{}, before domain.Cursor, limit int) (error, *[]domain.HomePost) {
+	return w.db.ReadListTimelinePosts(accountId.(uuid.UUID), listId.(uuid.UUID), before, limit)
 }
 
-func (w *dbWrapper) ReadNotificationsByAccountId(accountId interface{}, limit int) (error, *[]domain.Notification) {
-	return w.db.ReadNotificationsByAccountId(accountId.(uuid.UUID), limit)
+func (w *dbWrapper) ReadFiltersByAccountId(accountId interface{}) (error, *[]domain.Filter) {
+	return w.db.ReadFiltersByAccountId(accountId.(uuid.UUID))
+}
+
+func (w *dbWrapper) ReadNotificationsByAccountId(accountId interface{}, before domain.Cursor, limit int) (error, *[]domain.Notification) {
+	return w.db.ReadNotificationsByAccountId(accountId.(uuid.UUID), before, limit)
 }
 
 func (w *dbWrapper) CountUnreadNotifications(accountId interface{}) (int, error) {
@@ -100,3 +145,83 @@ func (w *dbWrapper) CountUnreadNotifications(accountId interface{}) (int, error)
 func (w *dbWrapper) DeleteAllNotifications(accountId interface{}) error {
 	return w.db.DeleteAllNotifications(accountId.(uuid.UUID))
 }
+
+func (w *dbWrapper) ReadFollowedHashtags(accountId interface{}) (error, []string) {
+	return w.db.ReadFollowedHashtags(accountId.(uuid.UUID))
+}
+
+func (w *dbWrapper) ReadFollows(accountId interface{}) (error, *events.Follows) {
+	follows, err := actions.ReadFollows(w.db, accountId.(uuid.UUID))
+	return err, follows
+}
+
+func (w *dbWrapper) FollowHashtag(accountId interface{}, tag string) error {
+	return w.db.FollowHashtag(accountId.(uuid.UUID), tag)
+}
+
+func (w *dbWrapper) UnfollowHashtag(accountId interface{}, tag string) error {
+	return w.db.UnfollowHashtag(accountId.(uuid.UUID), tag)
+}
+
+func (w *dbWrapper) NoteCreated(account *domain.Account, noteId interface{}, message string, inReplyToURI string) {
+	activitypub.NoteCreated(noteId.(uuid.UUID), account, message, inReplyToURI, w.conf)
+}
+
+func (w *dbWrapper) ReplyToPost(account *domain.Account, ref string, message string) (interface{}, error) {
+	return actions.Reply(w.db, w.conf, account, ref, message)
+}
+
+func (w *dbWrapper) SetLike(account *domain.Account, ref string, like bool) error {
+	target, err := actions.ReadTargetByRef(w.db, w.conf, ref)
+	if err != nil {
+		return err
+	}
+	return actions.SetLike(w.db, w.conf, account, target, like)
+}
+
+func (w *dbWrapper) SetBoost(account *domain.Account, ref string, boost bool) error {
+	target, err := actions.ReadTargetByRef(w.db, w.conf, ref)
+	if err != nil {
+		return err
+	}
+	return actions.SetBoost(w.db, w.conf, account, target, boost)
+}
+
+func (w *dbWrapper) EditNote(account *domain.Account, noteId interface{}, message string) error {
+	return actions.EditNote(w.db, w.conf, account, noteId.(uuid.UUID), message)
+}
+
+func (w *dbWrapper) DeleteNote(account *domain.Account, noteId interface{}) error {
+	return actions.DeleteNote(w.db, w.conf, account, noteId.(uuid.UUID))
+}
+
+func (w *dbWrapper) Follow(account *domain.Account, handle string) (bool, error) {
+	return actions.FollowAccount(w.db, w.conf, account, handle)
+}
+
+func (w *dbWrapper) Unfollow(account *domain.Account, handle string) error {
+	return actions.UnfollowAccount(w.db, w.conf, account, handle)
+}
+
+func (w *dbWrapper) ReadThread(ref string) (error, *actions.Thread) {
+	thread, err := actions.ReadThread(w.db, w.conf, ref)
+	return err, thread
+}
+
+func (w *dbWrapper) ReadProfile(viewer *domain.Account, handle string) (error, *actions.Profile) {
+	profile, err := actions.ReadProfile(w.db, w.conf, viewer, handle)
+	return err, profile
+}
+
+func (w *dbWrapper) ReadFollowProfiles(accountId interface{}, followers bool) (error, *[]actions.Profile) {
+	profiles, err := actions.ReadFollowProfiles(w.db, w.conf, accountId.(uuid.UUID), followers)
+	return err, &profiles
+}
+
+func (w *dbWrapper) UpdateAccountDisplayName(accountId interface{}, displayName string) error {
+	return w.db.UpdateAccountDisplayName(accountId.(uuid.UUID), displayName)
+}
+
+func (w *dbWrapper) UpdateAccountSummary(accountId interface{}, summary string) error {
+	return w.db.UpdateAccountSummary(accountId.(uuid.UUID), summary)
+}
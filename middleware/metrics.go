@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/deemkeen/stegodon/metrics"
+)
+
+// viewTrackerKey holds the session's *metrics.ViewTracker in the SSH context
+type viewTrackerKey struct{}
+
+// MetricsMiddleware counts active SSH sessions and gives TUI sessions a view
+// tracker that MainTui hands to the UI. Both are released when the session ends.
+func MetricsMiddleware() wish.Middleware {
+	return func(h ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			kind := metrics.SessionTUI
+			if len(s.Command()) > 0 {
+				kind = metrics.SessionCLI
+			}
+			ended := metrics.SSHSessionStarted(kind)
+			defer ended()
+
+			if kind == metrics.SessionTUI {
+				views := metrics.NewViewTracker()
+				defer views.Close()
+				s.Context().SetValue(viewTrackerKey{}, views)
+			}
+
+			h(s)
+		}
+	}
+}
+
+// sessionViewTracker returns the view tracker set up by MetricsMiddleware, or nil
+func sessionViewTracker(s ssh.Session) *metrics.ViewTracker {
+	views, _ := s.Context().Value(viewTrackerKey{}).(*metrics.ViewTracker)
+	return views
+}
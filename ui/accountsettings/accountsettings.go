@@ -53,6 +53,11 @@ const (
 	EditBioView
 	AvatarView
 	DeleteView
+	FiltersView
+	FilterFormView
+	AppsView
+	AppCodeView
+	AppConfirmView
 )
 
 // MenuItem represents a menu option
@@ -62,6 +67,8 @@ const (
 	MenuEditDisplayName MenuItem = iota
 	MenuEditBio
 	MenuChangeAvatar
+	MenuFilters
+	MenuApps
 	MenuDeleteAccount
 )
 
@@ -98,6 +105,18 @@ type Model struct {
 
 	// Pre-rendered avatar for display
 	avatarRendered string
+
+	// Keyword filters
+	filters        []domain.Filter
+	filterSelected int
+	filterInput    textinput.Model
+	filterForm     filterForm
+
+	// Apps authorized through the Mastodon client API
+	apps                 []domain.OAuthToken
+	appSelected          int
+	appCodeInput         textinput.Model
+	pendingAuthorization *domain.OAuthAuthorization
 }
 
 func InitialModel(account *domain.Account) Model {
@@ -134,6 +153,9 @@ func InitialModel(account *domain.Account) Model {
 		bioInput:         bioInput,
 		conf:             conf,
 		avatarRendered:   avatarStr,
+		filterInput:      newFilterInput(),
+		filterForm:       newFilterForm(),
+		appCodeInput:     newAppCodeInput(),
 	}
 }
 
@@ -264,6 +286,49 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case filtersLoadedMsg:
+		if msg.err != nil {
+			m.Error = fmt.Sprintf("Failed to update filters: %v", msg.err)
+		} else if msg.status != "" {
+			m.Status = msg.status
+		}
+		m.filters = msg.filters
+		if m.filterSelected >= len(m.filters) {
+			m.filterSelected = max(0, len(m.filters)-1)
+		}
+		if msg.err != nil || msg.status != "" {
+			return m, clearStatusAfter(3 * time.Second)
+		}
+		return m, nil
+
+	case appsLoadedMsg:
+		if msg.err != nil {
+			m.Error = fmt.Sprintf("Failed to update authorized apps: %v", msg.err)
+		} else if msg.status != "" {
+			m.Status = msg.status
+		}
+		m.apps = msg.apps
+		if m.appSelected >= len(m.apps) {
+			m.appSelected = max(0, len(m.apps)-1)
+		}
+		if msg.err != nil || msg.status != "" {
+			return m, clearStatusAfter(3 * time.Second)
+		}
+		return m, nil
+
+	case appAuthorizationMsg:
+		if m.ViewState != AppCodeView {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.Error = msg.err.Error()
+			return m, clearStatusAfter(3 * time.Second)
+		}
+		m.appCodeInput.Blur()
+		m.pendingAuthorization = msg.authorization
+		m.ViewState = AppConfirmView
+		return m, nil
+
 	case tea.KeyMsg:
 		switch m.ViewState {
 		case MenuView:
@@ -276,6 +341,16 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m.updateAvatar(msg)
 		case DeleteView:
 			return m.updateDelete(msg)
+		case FiltersView:
+			return m.updateFilters(msg)
+		case FilterFormView:
+			return m.updateFilterForm(msg)
+		case AppsView:
+			return m.updateApps(msg)
+		case AppCodeView:
+			return m.updateAppCode(msg)
+		case AppConfirmView:
+			return m.updateAppConfirm(msg)
 		}
 	}
 
@@ -307,6 +382,10 @@ func (m Model) updateMenu(msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.uploadToken = ""
 			m.uploadURL = ""
 			return m, nil
+		case MenuFilters:
+			return m.openFilters()
+		case MenuApps:
+			return m.openApps()
 		case MenuDeleteAccount:
 			m.ViewState = DeleteView
 			m.ConfirmStep = 0
@@ -325,6 +404,10 @@ func (m Model) updateMenu(msg tea.KeyMsg) (Model, tea.Cmd) {
 		m.uploadToken = ""
 		m.uploadURL = ""
 		return m, nil
+	case "f":
+		return m.openFilters()
+	case "o":
+		return m.openApps()
 	case "d":
 		m.ViewState = DeleteView
 		m.ConfirmStep = 0
@@ -439,6 +522,16 @@ func (m Model) View() string {
 		s.WriteString(m.renderAvatar())
 	case DeleteView:
 		s.WriteString(m.renderDelete())
+	case FiltersView:
+		s.WriteString(m.renderFilters())
+	case FilterFormView:
+		s.WriteString(m.renderFilterForm())
+	case AppsView:
+		s.WriteString(m.renderApps())
+	case AppCodeView:
+		s.WriteString(m.renderAppCode())
+	case AppConfirmView:
+		s.WriteString(m.renderAppConfirm())
 	}
 
 	// Status and error messages
@@ -497,6 +590,8 @@ func (m Model) renderMenu() string {
 		{"e", "Edit display name"},
 		{"b", "Edit bio"},
 		{"a", "Change avatar"},
+		{"f", "Keyword filters"},
+		{"o", "Authorized apps"},
 		{"d", "Delete account"},
 	}
 
@@ -1,6 +1,8 @@
 package accountsettings
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -53,6 +55,16 @@ func TestMenuNavigation(t *testing.T) {
 		t.Errorf("Expected MenuChangeAvatar after down, got %d", model.MenuItem)
 	}
 
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if model.MenuItem != MenuFilters {
+		t.Errorf("Expected MenuFilters after down, got %d", model.MenuItem)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if model.MenuItem != MenuApps {
+		t.Errorf("Expected MenuApps after down, got %d", model.MenuItem)
+	}
+
 	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
 	if model.MenuItem != MenuDeleteAccount {
 		t.Errorf("Expected MenuDeleteAccount after down, got %d", model.MenuItem)
@@ -66,6 +78,16 @@ func TestMenuNavigation(t *testing.T) {
 
 	// Test up navigation
 	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	if model.MenuItem != MenuApps {
+		t.Errorf("Expected MenuApps after up, got %d", model.MenuItem)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if model.MenuItem != MenuFilters {
+		t.Errorf("Expected MenuFilters after up, got %d", model.MenuItem)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyUp})
 	if model.MenuItem != MenuChangeAvatar {
 		t.Errorf("Expected MenuChangeAvatar after up, got %d", model.MenuItem)
 	}
@@ -86,6 +108,8 @@ func TestMenuHotkeys(t *testing.T) {
 		{'e', EditDisplayNameView},
 		{'b', EditBioView},
 		{'a', AvatarView},
+		{'f', FiltersView},
+		{'o', AppsView},
 		{'d', DeleteView},
 	}
 
@@ -472,3 +496,106 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestFilterForm(t *testing.T) {
+	acc := createTestAccount()
+	model := InitialModel(acc)
+	model.ViewState = FiltersView
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if model.ViewState != FilterFormView {
+		t.Fatalf("Expected FilterFormView after n, got %d", model.ViewState)
+	}
+
+	// An empty phrase is rejected
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.filterForm.step != 0 || model.Error == "" {
+		t.Error("Expected empty phrase to be rejected")
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("spoiler")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.filterForm.step != 1 {
+		t.Fatalf("Expected options step after entering a phrase, got %d", model.filterForm.step)
+	}
+
+	for _, key := range []rune{'w', '2', 'a', 'x', 'x'} {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+	}
+	f := model.filterForm
+	if !f.wholeWord {
+		t.Error("Expected whole word to be toggled on")
+	}
+	if f.contexts[domain.FilterContextGlobal] || !f.contexts[domain.FilterContextHome] {
+		t.Errorf("Expected only the global context to be toggled off, got %v", f.contexts)
+	}
+	if f.action != domain.FilterActionWarn {
+		t.Errorf("Expected warn action, got %s", f.action)
+	}
+	if filterExpiries[f.expiry].duration != time.Hour {
+		t.Errorf("Expected 1 hour expiry, got %s", filterExpiries[f.expiry].label)
+	}
+
+	// Saving returns to the list and issues the create command
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.ViewState != FiltersView || cmd == nil {
+		t.Errorf("Expected save to return to FiltersView with a command, got state %d", model.ViewState)
+	}
+}
+
+func TestFormatExpiry(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	soon := now.Add(90 * time.Minute)
+
+	if got := formatExpiry(domain.Filter{}, now); got != "never expires" {
+		t.Errorf("Expected 'never expires', got %q", got)
+	}
+	if got := formatExpiry(domain.Filter{ExpiresAt: &past}, now); got != "expired" {
+		t.Errorf("Expected 'expired', got %q", got)
+	}
+	if got := formatExpiry(domain.Filter{ExpiresAt: &soon}, now); got != "expires in 1h" {
+		t.Errorf("Expected 'expires in 1h', got %q", got)
+	}
+}
+
+func TestAppAuthorizationConfirm(t *testing.T) {
+	acc := createTestAccount()
+	model := InitialModel(acc)
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if model.ViewState != AppCodeView {
+		t.Fatalf("Expected AppCodeView after n, got %d", model.ViewState)
+	}
+
+	// An unknown code keeps the input open and shows an error
+	model, _ = model.Update(appAuthorizationMsg{err: errors.New("no pending authorization")})
+	if model.ViewState != AppCodeView || model.Error == "" {
+		t.Errorf("Expected error in AppCodeView, got state %d error %q", model.ViewState, model.Error)
+	}
+
+	auth := &domain.OAuthAuthorization{Id: uuid.New(), AppName: "Tusky", Scopes: []string{"read", "write"}}
+	model, _ = model.Update(appAuthorizationMsg{authorization: auth})
+	if model.ViewState != AppConfirmView {
+		t.Fatalf("Expected AppConfirmView, got %d", model.ViewState)
+	}
+	if view := model.View(); !strings.Contains(view, "Tusky") || !strings.Contains(view, "read, write") {
+		t.Errorf("Expected confirmation to name the app and scopes, got %q", view)
+	}
+
+	// Denying returns to the list and issues the update command
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if model.ViewState != AppsView || model.pendingAuthorization != nil || cmd == nil {
+		t.Errorf("Expected deny to return to AppsView with a command, got state %d", model.ViewState)
+	}
+}
+
+func TestFormatLastUsed(t *testing.T) {
+	if got := formatLastUsed(time.Time{}); got != "never" {
+		t.Errorf("Expected 'never', got %q", got)
+	}
+	if got := formatLastUsed(time.Now().Add(-3 * time.Hour)); got != "3h ago" {
+		t.Errorf("Expected '3h ago', got %q", got)
+	}
+}
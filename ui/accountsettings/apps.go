@@ -0,0 +1,242 @@
+package accountsettings
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/ui/common"
+	"github.com/google/uuid"
+)
+
+func newAppCodeInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "XXXX-XXXX"
+	ti.CharLimit = 12
+	ti.Width = 12
+	return ti
+}
+
+// appsLoadedMsg is sent when the account's authorized apps are (re)loaded
+type appsLoadedMsg struct {
+	apps   []domain.OAuthToken
+	status string
+	err    error
+}
+
+// appAuthorizationMsg is sent when a code typed into the apps view was looked up
+type appAuthorizationMsg struct {
+	authorization *domain.OAuthAuthorization
+	err           error
+}
+
+// loadAppsCmd loads the apps that hold an access token for the account
+func loadAppsCmd(accountId uuid.UUID) tea.Cmd {
+	return appActionCmd(accountId, nil, "")
+}
+
+// appActionCmd runs action (if any) and reloads the authorized apps afterwards
+func appActionCmd(accountId uuid.UUID, action func(database db.Store) error, status string) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+		msg := appsLoadedMsg{}
+
+		if action != nil {
+			if err := action(database); err != nil {
+				log.Printf("Authorized app update failed: %v", err)
+				msg.err = err
+			} else {
+				msg.status = status
+			}
+		}
+
+		err, tokens := database.ReadOAuthTokensByAccountId(accountId)
+		if err != nil {
+			log.Printf("Failed to load authorized apps: %v", err)
+			msg.err = err
+			return msg
+		}
+		if tokens != nil {
+			msg.apps = *tokens
+		}
+		return msg
+	}
+}
+
+// lookupAppAuthorizationCmd finds the pending authorization request for a typed code
+func lookupAppAuthorizationCmd(userCode string) tea.Cmd {
+	return func() tea.Msg {
+		err, auth := db.GetDB().ReadPendingOAuthAuthorizationByUserCode(userCode)
+		if err != nil {
+			return appAuthorizationMsg{err: fmt.Errorf("no pending authorization for code %s", userCode)}
+		}
+		return appAuthorizationMsg{authorization: auth}
+	}
+}
+
+// generateAuthorizationCode returns the secret code the app exchanges for a token
+func generateAuthorizationCode() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatalf("Failed to read random bytes: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (m Model) openApps() (Model, tea.Cmd) {
+	m.ViewState = AppsView
+	m.appSelected = 0
+	return m, loadAppsCmd(m.Account.Id)
+}
+
+func (m Model) updateApps(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.ViewState = MenuView
+		return m, nil
+	case "up", "k":
+		if m.appSelected > 0 {
+			m.appSelected--
+		}
+	case "down", "j":
+		if m.appSelected < len(m.apps)-1 {
+			m.appSelected++
+		}
+	case "n":
+		m.ViewState = AppCodeView
+		m.appCodeInput.SetValue("")
+		m.appCodeInput.Focus()
+		return m, textinput.Blink
+	case "d":
+		if m.appSelected < len(m.apps) {
+			app := m.apps[m.appSelected]
+			return m, appActionCmd(m.Account.Id, func(database db.Store) error {
+				return database.DeleteOAuthToken(app.Id)
+			}, fmt.Sprintf("Revoked access for %s", app.AppName))
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateAppCode(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.appCodeInput.Blur()
+		m.ViewState = AppsView
+		return m, nil
+	case "enter":
+		code := domain.NormalizeOAuthUserCode(m.appCodeInput.Value())
+		if code == "" {
+			m.Error = "Code cannot be empty"
+			return m, clearStatusAfter(2 * time.Second)
+		}
+		return m, lookupAppAuthorizationCmd(code)
+	}
+	var cmd tea.Cmd
+	m.appCodeInput, cmd = m.appCodeInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateAppConfirm(msg tea.KeyMsg) (Model, tea.Cmd) {
+	auth := m.pendingAuthorization
+	if auth == nil {
+		m.ViewState = AppsView
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "y":
+		m.ViewState = AppsView
+		m.pendingAuthorization = nil
+		accountId := m.Account.Id
+		return m, appActionCmd(accountId, func(database db.Store) error {
+			return database.ApproveOAuthAuthorization(auth.Id, accountId, generateAuthorizationCode())
+		}, fmt.Sprintf("Authorized %s, return to the app to finish signing in", auth.AppName))
+	case "n", "esc":
+		m.ViewState = AppsView
+		m.pendingAuthorization = nil
+		return m, appActionCmd(m.Account.Id, func(database db.Store) error {
+			return database.DenyOAuthAuthorization(auth.Id)
+		}, fmt.Sprintf("Denied access for %s", auth.AppName))
+	}
+	return m, nil
+}
+
+func (m Model) renderApps() string {
+	var s strings.Builder
+
+	s.WriteString("Authorized Apps\n\n")
+
+	if len(m.apps) == 0 {
+		s.WriteString(common.ListEmptyStyle.Render("No apps have access yet.\nPress n to enter a code shown by an app."))
+		s.WriteString("\n")
+	}
+
+	for i, app := range m.apps {
+		badge := fmt.Sprintf(" %s · last used %s", strings.Join(app.Scopes, " "), formatLastUsed(app.LastUsedAt))
+
+		if i == m.appSelected {
+			s.WriteString(common.ListSelectedPrefix + common.ListItemSelectedStyle.Render(app.AppName+badge))
+		} else {
+			s.WriteString(common.ListUnselectedPrefix + common.ListItemStyle.Render(app.AppName) + common.ListBadgeStyle.Render(badge))
+		}
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(instructionStyle.Render("n: enter code • d: revoke • Esc go back"))
+
+	return s.String()
+}
+
+func (m Model) renderAppCode() string {
+	var s strings.Builder
+
+	s.WriteString("Authorize App\n\n")
+	s.WriteString("Enter the code shown in your browser:\n\n")
+	s.WriteString(m.appCodeInput.View())
+	s.WriteString("\n\n")
+	s.WriteString(instructionStyle.Render("Enter to continue, Esc to cancel"))
+
+	return s.String()
+}
+
+func (m Model) renderAppConfirm() string {
+	var s strings.Builder
+
+	s.WriteString("Authorize App\n\n")
+	if auth := m.pendingAuthorization; auth != nil {
+		s.WriteString(confirmStyle.Render(fmt.Sprintf("Allow %s to access @%s?", auth.AppName, m.Account.Username)))
+		s.WriteString("\n\n")
+		s.WriteString(menuStyle.Render("Permissions: " + strings.Join(auth.Scopes, ", ")))
+		s.WriteString("\n\n")
+	}
+	s.WriteString(instructionStyle.Render("y: allow • n: deny"))
+
+	return s.String()
+}
+
+// formatLastUsed describes when a token was last used
+func formatLastUsed(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	elapsed := time.Since(t)
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%dm ago", int(elapsed.Minutes()))
+	case elapsed < common.HoursPerDay*time.Hour:
+		return fmt.Sprintf("%dh ago", int(elapsed.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(elapsed.Hours()/common.HoursPerDay))
+	}
+}
@@ -0,0 +1,302 @@
+package accountsettings
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/ui/common"
+	"github.com/google/uuid"
+)
+
+// maxFilterPhraseLength limits filter phrases so they fit in the collapsed post line
+const maxFilterPhraseLength = 100
+
+// filterExpiries are the expiry options of the filter form (0 = never expires)
+var filterExpiries = []struct {
+	label    string
+	duration time.Duration
+}{
+	{"never", 0},
+	{"30 minutes", 30 * time.Minute},
+	{"1 hour", time.Hour},
+	{"6 hours", 6 * time.Hour},
+	{"1 day", 24 * time.Hour},
+	{"1 week", 7 * 24 * time.Hour},
+	{"30 days", 30 * 24 * time.Hour},
+}
+
+// filterForm holds the options of a filter being created
+type filterForm struct {
+	step      int // 0 = entering the phrase, 1 = choosing options
+	wholeWord bool
+	contexts  map[string]bool
+	action    string
+	expiry    int // Index into filterExpiries
+}
+
+func newFilterForm() filterForm {
+	contexts := map[string]bool{}
+	for _, c := range domain.FilterContexts {
+		contexts[c] = true
+	}
+	return filterForm{contexts: contexts, action: domain.FilterActionHide}
+}
+
+func newFilterInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "word or phrase"
+	ti.CharLimit = maxFilterPhraseLength
+	ti.Width = 40
+	return ti
+}
+
+// filtersLoadedMsg is sent when the account's filters are (re)loaded
+type filtersLoadedMsg struct {
+	filters []domain.Filter
+	status  string
+	err     error
+}
+
+// loadFiltersCmd loads the account's keyword filters
+func loadFiltersCmd(accountId uuid.UUID) tea.Cmd {
+	return filterActionCmd(accountId, nil, "")
+}
+
+// filterActionCmd runs action (if any) and reloads the filters afterwards
+func filterActionCmd(accountId uuid.UUID, action func(database db.Store) error, status string) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+		msg := filtersLoadedMsg{}
+
+		if action != nil {
+			if err := action(database); err != nil {
+				log.Printf("Filter update failed: %v", err)
+				msg.err = err
+			} else {
+				msg.status = status
+			}
+		}
+
+		err, filters := database.ReadFiltersByAccountId(accountId)
+		if err != nil {
+			log.Printf("Failed to load filters: %v", err)
+			msg.err = err
+			return msg
+		}
+		if filters != nil {
+			msg.filters = *filters
+		}
+		return msg
+	}
+}
+
+func (m Model) openFilters() (Model, tea.Cmd) {
+	m.ViewState = FiltersView
+	m.filterSelected = 0
+	return m, loadFiltersCmd(m.Account.Id)
+}
+
+func (m Model) updateFilters(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.ViewState = MenuView
+		return m, nil
+	case "up", "k":
+		if m.filterSelected > 0 {
+			m.filterSelected--
+		}
+	case "down", "j":
+		if m.filterSelected < len(m.filters)-1 {
+			m.filterSelected++
+		}
+	case "n":
+		m.ViewState = FilterFormView
+		m.filterForm = newFilterForm()
+		m.filterInput.SetValue("")
+		m.filterInput.Focus()
+		return m, textinput.Blink
+	case "d":
+		if m.filterSelected < len(m.filters) {
+			filter := m.filters[m.filterSelected]
+			return m, filterActionCmd(m.Account.Id, func(database db.Store) error {
+				return database.DeleteFilter(filter.Id)
+			}, fmt.Sprintf("Deleted filter %q", filter.Phrase))
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateFilterForm(msg tea.KeyMsg) (Model, tea.Cmd) {
+	f := &m.filterForm
+
+	if f.step == 0 {
+		switch msg.String() {
+		case "esc":
+			m.filterInput.Blur()
+			m.ViewState = FiltersView
+			return m, nil
+		case "enter":
+			if strings.TrimSpace(m.filterInput.Value()) == "" {
+				m.Error = "Filter phrase cannot be empty"
+				return m, clearStatusAfter(2 * time.Second)
+			}
+			m.filterInput.Blur()
+			f.step = 1
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.filterInput, cmd = m.filterInput.Update(msg)
+		return m, cmd
+	}
+
+	switch key := msg.String(); key {
+	case "esc":
+		// Back to the phrase
+		f.step = 0
+		m.filterInput.Focus()
+		return m, textinput.Blink
+	case "w":
+		f.wholeWord = !f.wholeWord
+	case "1", "2", "3", "4":
+		context := domain.FilterContexts[key[0]-'1']
+		f.contexts[context] = !f.contexts[context]
+	case "a":
+		if f.action == domain.FilterActionHide {
+			f.action = domain.FilterActionWarn
+		} else {
+			f.action = domain.FilterActionHide
+		}
+	case "x":
+		f.expiry = (f.expiry + 1) % len(filterExpiries)
+	case "enter":
+		var contexts []string
+		for _, c := range domain.FilterContexts {
+			if f.contexts[c] {
+				contexts = append(contexts, c)
+			}
+		}
+		if len(contexts) == 0 {
+			m.Error = "Select at least one context"
+			return m, clearStatusAfter(2 * time.Second)
+		}
+
+		now := time.Now()
+		filter := &domain.Filter{
+			Id:        uuid.New(),
+			AccountId: m.Account.Id,
+			Phrase:    strings.TrimSpace(m.filterInput.Value()),
+			WholeWord: f.wholeWord,
+			Contexts:  contexts,
+			Action:    f.action,
+			CreatedAt: now,
+		}
+		if d := filterExpiries[f.expiry].duration; d > 0 {
+			expiresAt := now.Add(d)
+			filter.ExpiresAt = &expiresAt
+		}
+
+		m.ViewState = FiltersView
+		m.filterInput.SetValue("")
+		return m, filterActionCmd(m.Account.Id, func(database db.Store) error {
+			return database.CreateFilter(filter)
+		}, fmt.Sprintf("Added filter %q", filter.Phrase))
+	}
+	return m, nil
+}
+
+func (m Model) renderFilters() string {
+	var s strings.Builder
+
+	s.WriteString("Keyword Filters\n\n")
+
+	if len(m.filters) == 0 {
+		s.WriteString(common.ListEmptyStyle.Render("No filters yet.\nPress n to add one."))
+		s.WriteString("\n")
+	}
+
+	now := time.Now()
+	for i, filter := range m.filters {
+		text := filter.Phrase
+		if filter.WholeWord {
+			text += " (whole word)"
+		}
+		badge := fmt.Sprintf(" %s · %s · %s", strings.Join(filter.Contexts, ", "), filter.Action, formatExpiry(filter, now))
+
+		if i == m.filterSelected {
+			s.WriteString(common.ListSelectedPrefix + common.ListItemSelectedStyle.Render(text+badge))
+		} else {
+			s.WriteString(common.ListUnselectedPrefix + common.ListItemStyle.Render(text) + common.ListBadgeStyle.Render(badge))
+		}
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(instructionStyle.Render("n: new filter • d: delete • Esc go back"))
+
+	return s.String()
+}
+
+func (m Model) renderFilterForm() string {
+	var s strings.Builder
+
+	s.WriteString("New Keyword Filter\n\n")
+	s.WriteString(m.filterInput.View())
+	s.WriteString("\n\n")
+
+	if m.filterForm.step == 0 {
+		s.WriteString(instructionStyle.Render("Enter to continue, Esc to cancel"))
+		return s.String()
+	}
+
+	f := m.filterForm
+	check := func(on bool) string {
+		if on {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+
+	s.WriteString(menuStyle.Render(fmt.Sprintf("[w] %s whole word only", check(f.wholeWord))))
+	s.WriteString("\n\n")
+	for i, c := range domain.FilterContexts {
+		s.WriteString(menuStyle.Render(fmt.Sprintf("[%d] %s %s", i+1, check(f.contexts[c]), c)))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	action := "hide matching posts"
+	if f.action == domain.FilterActionWarn {
+		action = "collapse matching posts behind a warning"
+	}
+	s.WriteString(menuStyle.Render("[a] action: " + action))
+	s.WriteString("\n")
+	s.WriteString(menuStyle.Render("[x] expires: " + filterExpiries[f.expiry].label))
+	s.WriteString("\n\n")
+	s.WriteString(instructionStyle.Render("Enter to save, Esc to edit the phrase"))
+
+	return s.String()
+}
+
+// formatExpiry describes when a filter expires
+func formatExpiry(filter domain.Filter, now time.Time) string {
+	if filter.ExpiresAt == nil {
+		return "never expires"
+	}
+	if filter.IsExpired(now) {
+		return "expired"
+	}
+	remaining := filter.ExpiresAt.Sub(now)
+	switch {
+	case remaining < time.Hour:
+		return fmt.Sprintf("expires in %dm", int(remaining.Minutes())+1)
+	case remaining < common.HoursPerDay*time.Hour:
+		return fmt.Sprintf("expires in %dh", int(remaining.Hours()))
+	default:
+		return fmt.Sprintf("expires in %dd", int(remaining.Hours()/common.HoursPerDay))
+	}
+}
@@ -2,6 +2,8 @@ package admin
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -11,9 +13,12 @@ import (
 	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/deemkeen/stegodon/activitypub"
 	"github.com/deemkeen/stegodon/db"
 	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/health"
 	"github.com/deemkeen/stegodon/ui/common"
+	"github.com/deemkeen/stegodon/util"
 	"github.com/google/uuid"
 )
 
@@ -25,8 +30,16 @@ const (
 	InfoBoxesView
 	ServerMessageView
 	BansView
+	EmojisView
+	InboxQueueView
+	RemoteAccountsView
+	RetentionView
+	StatusView
 )
 
+// statusRefreshInterval is how often the server status view reloads while open
+const statusRefreshInterval = 5 * time.Second
+
 type Model struct {
 	AdminId      uuid.UUID
 	CurrentView  AdminView
@@ -60,6 +73,37 @@ type Model struct {
 	BanSelected  int
 	BanOffset    int
 
+	// Custom emoji management
+	Emojis             []domain.CustomEmoji
+	EmojiSelected      int
+	EmojiOffset        int
+	EmojiUploadURL     string    // One-time upload link for a new emoji
+	EmojiUploadExpires time.Time // When the upload link expires
+	ConfirmDeleteEmoji bool      // True when confirming emoji deletion
+
+	// Inbox queue holding area
+	HeldItems    []domain.InboxQueueItem
+	HeldSelected int
+	HeldOffset   int
+	QueuePending int // Items waiting to be processed or retried
+	QueueHeld    int // Items that failed too often and wait for an admin
+
+	// Remote account refresh
+	RemoteStats *domain.RemoteAccountStats
+	RemoteTTL   time.Duration                   // Refresh TTL from the config
+	LastRefresh *activitypub.ActorRefreshResult // Most recent refresh round (nil if none ran)
+	Refreshing  bool
+
+	// Retention
+	RetentionDays    int                    // retentionDays from the config (0 = disabled)
+	RetentionPreview *domain.RetentionStats // Dry-run counts of what a prune would remove
+	PruneConfirm     bool                   // Set after the first p, the second p prunes
+	Pruning          bool
+
+	// Server status
+	ServerStatus *health.Status
+	StatusGen    int // Bumped each time the view opens, so only the latest refresh loop keeps running
+
 	Width  int
 	Height int
 	Status string
@@ -86,7 +130,7 @@ func InitialModel(adminId uuid.UUID, width, height int) Model {
 }
 
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(loadUsers(), loadInfoBoxes(), loadServerMessage(), loadBans())
+	return tea.Batch(loadUsers(), loadInfoBoxes(), loadServerMessage(), loadBans(), loadEmojis())
 }
 
 // createTextarea creates a new textarea with standard settings
@@ -148,6 +192,54 @@ type bansLoadedMsg struct {
 
 type unbanUserMsg struct{}
 
+type emojisLoadedMsg struct {
+	emojis []domain.CustomEmoji
+}
+
+type emojiDeletedMsg struct{}
+
+type inboxQueueLoadedMsg struct {
+	items   []domain.InboxQueueItem
+	pending int
+	held    int
+}
+type inboxItemRetriedMsg struct{}
+type inboxItemDroppedMsg struct{}
+
+type remoteAccountsLoadedMsg struct {
+	stats       *domain.RemoteAccountStats
+	ttl         time.Duration
+	lastRefresh *activitypub.ActorRefreshResult
+}
+type retentionPreviewMsg struct {
+	days  int
+	stats *domain.RetentionStats
+	err   error
+}
+type prunedMsg struct {
+	stats *domain.RetentionStats
+	err   error
+}
+type actorsRefreshedMsg struct {
+	result activitypub.ActorRefreshResult
+	err    error
+}
+
+type serverStatusLoadedMsg struct {
+	status *health.Status
+	gen    int
+	err    error
+}
+type statusTickMsg struct {
+	gen int
+}
+
+type emojiUploadLinkMsg struct {
+	url       string
+	expiresAt time.Time
+	err       error
+}
+
 // User management commands
 func loadUsers() tea.Cmd {
 	return func() tea.Msg {
@@ -326,7 +418,183 @@ func unbanUserById(accountId uuid.UUID) tea.Cmd {
 	}
 }
 
+// Custom emoji management commands
+func loadEmojis() tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+		err, emojis := database.ReadAllCustomEmojis()
+		if err != nil {
+			log.Printf("Failed to load custom emoji: %v", err)
+			return emojisLoadedMsg{emojis: []domain.CustomEmoji{}}
+		}
+		if emojis == nil {
+			return emojisLoadedMsg{emojis: []domain.CustomEmoji{}}
+		}
+		return emojisLoadedMsg{emojis: *emojis}
+	}
+}
+
+func deleteEmoji(emoji domain.CustomEmoji) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+		if err := database.DeleteCustomEmoji(emoji.Id); err != nil {
+			log.Printf("Failed to delete custom emoji: %v", err)
+			return emojiDeletedMsg{}
+		}
+
+		// Remove the image file as well
+		if configDir, err := util.GetConfigDir(); err == nil {
+			imagePath := filepath.Join(configDir, "emojis", filepath.Base(emoji.ImageURL))
+			if err := os.Remove(imagePath); err != nil && !os.IsNotExist(err) {
+				log.Printf("Failed to remove custom emoji image: %v", err)
+			}
+		}
+		return emojiDeletedMsg{}
+	}
+}
+
+// createEmojiUploadLink creates a one-time upload link for a new custom emoji
+func createEmojiUploadLink(adminId uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+
+		conf, err := util.ReadConf()
+		if err != nil {
+			return emojiUploadLinkMsg{err: err}
+		}
+
+		// Reuse an existing valid token if there is one
+		token, expiresAt, err := database.GetExistingUploadToken(adminId, "emoji")
+		if err != nil {
+			return emojiUploadLinkMsg{err: err}
+		}
+		if token == "" {
+			token = util.RandomString(64)
+			expiresIn := 10 * time.Minute
+			if err := database.CreateUploadToken(adminId, token, "emoji", expiresIn); err != nil {
+				return emojiUploadLinkMsg{err: err}
+			}
+			expiresAt = time.Now().Add(expiresIn)
+		}
+
+		var url string
+		if conf.Conf.WithAp {
+			url = fmt.Sprintf("https://%s/upload/%s", conf.Conf.SslDomain, token)
+		} else {
+			url = fmt.Sprintf("http://localhost:%d/upload/%s", conf.Conf.HttpPort, token)
+		}
+		return emojiUploadLinkMsg{url: url, expiresAt: expiresAt}
+	}
+}
+
 // Server message management commands
+// Inbox queue commands
+func loadInboxQueue() tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+		pending, held, err := database.CountInboxQueue()
+		if err != nil {
+			log.Printf("Failed to count inbox queue: %v", err)
+		}
+		err, items := database.ReadHeldInboxItems(100)
+		if err != nil {
+			log.Printf("Failed to load held inbox items: %v", err)
+			return inboxQueueLoadedMsg{items: []domain.InboxQueueItem{}, pending: pending, held: held}
+		}
+		if items == nil {
+			return inboxQueueLoadedMsg{items: []domain.InboxQueueItem{}, pending: pending, held: held}
+		}
+		return inboxQueueLoadedMsg{items: *items, pending: pending, held: held}
+	}
+}
+
+func retryInboxItem(id uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+		if err := database.RetryInboxItem(id); err != nil {
+			log.Printf("Failed to retry inbox item: %v", err)
+		}
+		return inboxItemRetriedMsg{}
+	}
+}
+
+func dropInboxItem(id uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+		if err := database.DeleteInboxItem(id); err != nil {
+			log.Printf("Failed to drop inbox item: %v", err)
+		}
+		return inboxItemDroppedMsg{}
+	}
+}
+
+// Remote account commands
+func loadRemoteAccounts() tea.Cmd {
+	return func() tea.Msg {
+		conf, err := util.ReadConf()
+		if err != nil {
+			log.Printf("Failed to read config: %v", err)
+			return remoteAccountsLoadedMsg{lastRefresh: activitypub.LastActorRefresh()}
+		}
+		ttl := activitypub.ActorRefreshTTL(conf)
+		err, stats := db.GetDB().ReadRemoteAccountStats(time.Now().Add(-ttl))
+		if err != nil {
+			log.Printf("Failed to count remote accounts: %v", err)
+		}
+		return remoteAccountsLoadedMsg{stats: stats, ttl: ttl, lastRefresh: activitypub.LastActorRefresh()}
+	}
+}
+
+func refreshActors() tea.Cmd {
+	return func() tea.Msg {
+		conf, err := util.ReadConf()
+		if err != nil {
+			return actorsRefreshedMsg{err: err}
+		}
+		return actorsRefreshedMsg{result: activitypub.RefreshStaleActors(conf)}
+	}
+}
+
+// Retention commands
+func loadRetentionPreview() tea.Cmd {
+	return func() tea.Msg {
+		conf, err := util.ReadConf()
+		if err != nil {
+			return retentionPreviewMsg{err: err}
+		}
+		if conf.Conf.RetentionDays <= 0 {
+			return retentionPreviewMsg{}
+		}
+		stats, err := activitypub.PruneFederatedContent(conf, true)
+		return retentionPreviewMsg{days: conf.Conf.RetentionDays, stats: stats, err: err}
+	}
+}
+
+func pruneNow() tea.Cmd {
+	return func() tea.Msg {
+		conf, err := util.ReadConf()
+		if err != nil {
+			return prunedMsg{err: err}
+		}
+		stats, err := activitypub.PruneFederatedContent(conf, false)
+		return prunedMsg{stats: stats, err: err}
+	}
+}
+
+// Server status commands
+func loadServerStatus(gen int) tea.Cmd {
+	return func() tea.Msg {
+		status, err := health.ReadStatus(db.GetDB())
+		return serverStatusLoadedMsg{status: status, gen: gen, err: err}
+	}
+}
+
+func statusTick(gen int) tea.Cmd {
+	return tea.Tick(statusRefreshInterval, func(t time.Time) tea.Msg {
+		return statusTickMsg{gen: gen}
+	})
+}
+
 func loadServerMessage() tea.Cmd {
 	return func() tea.Msg {
 		database := db.GetDB()
@@ -420,6 +688,97 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		// Reload both users and bans lists to reflect the change
 		return m, tea.Batch(loadUsers(), loadBans())
 
+	case emojisLoadedMsg:
+		m.Emojis = msg.emojis
+		if m.EmojiSelected >= len(m.Emojis) && len(m.Emojis) > 0 {
+			m.EmojiSelected = len(m.Emojis) - 1
+		}
+		return m, nil
+
+	case emojiDeletedMsg:
+		m.Status = "Custom emoji deleted"
+		m.Error = ""
+		return m, loadEmojis()
+
+	case inboxQueueLoadedMsg:
+		m.HeldItems = msg.items
+		m.QueuePending = msg.pending
+		m.QueueHeld = msg.held
+		if m.HeldSelected >= len(m.HeldItems) && len(m.HeldItems) > 0 {
+			m.HeldSelected = len(m.HeldItems) - 1
+		}
+		return m, nil
+
+	case inboxItemRetriedMsg:
+		m.Status = "Activity queued for another attempt"
+		m.Error = ""
+		return m, loadInboxQueue()
+
+	case inboxItemDroppedMsg:
+		m.Status = "Activity dropped"
+		m.Error = ""
+		return m, loadInboxQueue()
+
+	case remoteAccountsLoadedMsg:
+		m.RemoteStats = msg.stats
+		m.RemoteTTL = msg.ttl
+		m.LastRefresh = msg.lastRefresh
+		return m, nil
+
+	case serverStatusLoadedMsg:
+		if msg.gen != m.StatusGen || m.CurrentView != StatusView {
+			return m, nil
+		}
+		m.ServerStatus = msg.status
+		if msg.err != nil {
+			m.Error = fmt.Sprintf("Failed to read part of the status: %v", msg.err)
+		}
+		return m, statusTick(msg.gen)
+
+	case statusTickMsg:
+		if msg.gen != m.StatusGen || m.CurrentView != StatusView {
+			return m, nil
+		}
+		return m, loadServerStatus(msg.gen)
+
+	case retentionPreviewMsg:
+		m.RetentionDays = msg.days
+		m.RetentionPreview = msg.stats
+		if msg.err != nil {
+			m.Error = fmt.Sprintf("Dry run failed: %v", msg.err)
+		}
+		return m, nil
+
+	case prunedMsg:
+		m.Pruning = false
+		if msg.err != nil {
+			m.Error = fmt.Sprintf("Prune failed: %v", msg.err)
+			return m, nil
+		}
+		m.Status = fmt.Sprintf("Pruned %d posts, %d boosts, %d notifications, %d remote accounts",
+			msg.stats.Activities, msg.stats.Boosts, msg.stats.Notifications, msg.stats.RemoteAccounts)
+		m.Error = ""
+		return m, loadRetentionPreview()
+
+	case actorsRefreshedMsg:
+		m.Refreshing = false
+		if msg.err != nil {
+			m.Error = fmt.Sprintf("Refresh failed: %v", msg.err)
+			return m, nil
+		}
+		m.Status = fmt.Sprintf("Refreshed %d, failed %d, gone %d", msg.result.Refreshed, msg.result.Failed, msg.result.Gone)
+		m.Error = ""
+		return m, loadRemoteAccounts()
+
+	case emojiUploadLinkMsg:
+		if msg.err != nil {
+			m.Error = fmt.Sprintf("Failed to create upload link: %v", msg.err)
+			return m, nil
+		}
+		m.EmojiUploadURL = msg.url
+		m.EmojiUploadExpires = msg.expiresAt
+		return m, nil
+
 	case tea.KeyMsg:
 		m.Status = ""
 		m.Error = ""
@@ -447,6 +806,16 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m.handleServerMessageKeys(msg)
 		case BansView:
 			return m.handleBansKeys(msg)
+		case EmojisView:
+			return m.handleEmojisKeys(msg)
+		case InboxQueueView:
+			return m.handleInboxQueueKeys(msg)
+		case RemoteAccountsView:
+			return m.handleRemoteAccountsKeys(msg)
+		case RetentionView:
+			return m.handleRetentionKeys(msg)
+		case StatusView:
+			return m.handleStatusKeys(msg)
 		}
 	}
 
@@ -492,7 +861,7 @@ func (m Model) handleMenuKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.MenuSelected--
 		}
 	case "down", "j":
-		if m.MenuSelected < 3 { // We have 4 menu items (0, 1, 2, and 3)
+		if m.MenuSelected < 8 { // We have 9 menu items (0 to 8)
 			m.MenuSelected++
 		}
 	case "enter":
@@ -506,6 +875,24 @@ func (m Model) handleMenuKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.CurrentView = ServerMessageView
 		case 3:
 			m.CurrentView = BansView
+		case 4:
+			m.CurrentView = EmojisView
+			return m, loadEmojis()
+		case 5:
+			m.CurrentView = InboxQueueView
+			return m, loadInboxQueue()
+		case 6:
+			m.CurrentView = RemoteAccountsView
+			return m, loadRemoteAccounts()
+		case 7:
+			m.CurrentView = RetentionView
+			m.RetentionPreview = nil
+			m.PruneConfirm = false
+			return m, loadRetentionPreview()
+		case 8:
+			m.CurrentView = StatusView
+			m.StatusGen++
+			return m, loadServerStatus(m.StatusGen)
 		}
 	}
 	return m, nil
@@ -739,6 +1126,150 @@ func (m Model) handleBansKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) handleEmojisKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	// Handle delete confirmation first
+	if m.ConfirmDeleteEmoji {
+		switch msg.String() {
+		case "y", "Y":
+			m.ConfirmDeleteEmoji = false
+			if len(m.Emojis) > 0 && m.EmojiSelected < len(m.Emojis) {
+				return m, deleteEmoji(m.Emojis[m.EmojiSelected])
+			}
+		case "n", "N", "esc":
+			m.ConfirmDeleteEmoji = false
+			m.Status = "Deletion cancelled"
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.CurrentView = MenuView
+		m.EmojiUploadURL = ""
+		return m, nil
+	case "up", "k":
+		if m.EmojiSelected > 0 {
+			m.EmojiSelected--
+			if m.EmojiSelected < m.EmojiOffset {
+				m.EmojiOffset--
+			}
+		}
+	case "down", "j":
+		if m.EmojiSelected < len(m.Emojis)-1 {
+			m.EmojiSelected++
+			if m.EmojiSelected >= m.EmojiOffset+common.DefaultItemsPerPage {
+				m.EmojiOffset++
+			}
+		}
+	case "g":
+		// Generate a one-time upload link for a new emoji
+		return m, createEmojiUploadLink(m.AdminId)
+	case "r":
+		// Refresh after uploading through the web
+		return m, loadEmojis()
+	case "d":
+		if len(m.Emojis) > 0 && m.EmojiSelected < len(m.Emojis) {
+			m.ConfirmDeleteEmoji = true
+		}
+	}
+	return m, nil
+}
+
+func (m Model) handleInboxQueueKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.CurrentView = MenuView
+		return m, nil
+	case "up", "k":
+		if m.HeldSelected > 0 {
+			m.HeldSelected--
+			if m.HeldSelected < m.HeldOffset {
+				m.HeldOffset--
+			}
+		}
+	case "down", "j":
+		if m.HeldSelected < len(m.HeldItems)-1 {
+			m.HeldSelected++
+			if m.HeldSelected >= m.HeldOffset+common.DefaultItemsPerPage {
+				m.HeldOffset++
+			}
+		}
+	case "r":
+		// Put the selected activity back into the queue
+		if len(m.HeldItems) > 0 && m.HeldSelected < len(m.HeldItems) {
+			return m, retryInboxItem(m.HeldItems[m.HeldSelected].Id)
+		}
+	case "d":
+		// Give up on the selected activity
+		if len(m.HeldItems) > 0 && m.HeldSelected < len(m.HeldItems) {
+			return m, dropInboxItem(m.HeldItems[m.HeldSelected].Id)
+		}
+	case "R":
+		return m, loadInboxQueue()
+	}
+	return m, nil
+}
+
+func (m Model) handleRemoteAccountsKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.CurrentView = MenuView
+		return m, nil
+	case "r":
+		// Run a refresh round now instead of waiting for the hourly job
+		if !m.Refreshing {
+			m.Refreshing = true
+			m.Status = "Refreshing stale remote accounts..."
+			return m, refreshActors()
+		}
+	case "R":
+		return m, loadRemoteAccounts()
+	}
+	return m, nil
+}
+
+func (m Model) handleStatusKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.CurrentView = MenuView
+		return m, nil
+	case "R":
+		// Restart the refresh loop so the manual reload does not run next to it
+		m.StatusGen++
+		return m, loadServerStatus(m.StatusGen)
+	}
+	return m, nil
+}
+
+func (m Model) handleRetentionKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.PruneConfirm = false
+		m.CurrentView = MenuView
+		return m, nil
+	case "p":
+		if m.RetentionDays <= 0 || m.Pruning {
+			return m, nil
+		}
+		// Pruning deletes data, so it needs a second p
+		if !m.PruneConfirm {
+			m.PruneConfirm = true
+			m.Status = "Press p again to prune now"
+			return m, nil
+		}
+		m.PruneConfirm = false
+		m.Pruning = true
+		m.Status = "Pruning..."
+		return m, pruneNow()
+	case "R":
+		m.PruneConfirm = false
+		return m, loadRetentionPreview()
+	default:
+		m.PruneConfirm = false
+	}
+	return m, nil
+}
+
 func (m Model) handleEditingKeys(msg tea.KeyMsg) (Model, tea.Cmd) {
 	// Check if any textarea is focused
 	isFocused := m.TitleInput.Focused() || m.ContentInput.Focused() || m.OrderInput.Focused()
@@ -880,6 +1411,16 @@ func (m Model) View() string {
 		}
 	case BansView:
 		s.WriteString(m.renderBansView())
+	case EmojisView:
+		s.WriteString(m.renderEmojisView())
+	case InboxQueueView:
+		s.WriteString(m.renderInboxQueueView())
+	case RemoteAccountsView:
+		s.WriteString(m.renderRemoteAccountsView())
+	case RetentionView:
+		s.WriteString(m.renderRetentionView())
+	case StatusView:
+		s.WriteString(m.renderStatusView())
 	}
 
 	// Status messages
@@ -899,7 +1440,7 @@ func (m Model) View() string {
 func (m Model) renderMenu() string {
 	var s strings.Builder
 
-	menuItems := []string{"Manage Users", "Manage Info Boxes", "Server Message", "Manage Bans"}
+	menuItems := []string{"Manage Users", "Manage Info Boxes", "Server Message", "Manage Bans", "Custom Emoji", "Inbox Queue", "Remote Accounts", "Retention", "Server Status"}
 
 	for i, item := range menuItems {
 		if i == m.MenuSelected {
@@ -1208,6 +1749,296 @@ func (m Model) renderBansView() string {
 	return s.String()
 }
 
+func (m Model) renderEmojisView() string {
+	var s strings.Builder
+
+	s.WriteString(common.CaptionStyle.Render(fmt.Sprintf("custom emoji (%d emoji)", len(m.Emojis))))
+	s.WriteString("\n\n")
+
+	if len(m.Emojis) == 0 {
+		s.WriteString(common.ListEmptyStyle.Render("No custom emoji yet. Press 'g' to get an upload link."))
+		s.WriteString("\n")
+	} else {
+		start := m.EmojiOffset
+		end := min(start+common.DefaultItemsPerPage, len(m.Emojis))
+
+		for i := start; i < end; i++ {
+			emoji := m.Emojis[i]
+			info := fmt.Sprintf(":%s:", emoji.Shortcode) + common.ListBadgeStyle.Render(" "+emoji.ImageURL)
+
+			if i == m.EmojiSelected {
+				text := common.ListItemSelectedStyle.Render(info)
+				s.WriteString(common.ListSelectedPrefix + text)
+			} else {
+				s.WriteString(common.ListUnselectedPrefix + common.ListItemStyle.Render(info))
+			}
+			s.WriteString("\n")
+		}
+
+		if len(m.Emojis) > common.DefaultItemsPerPage {
+			s.WriteString("\n")
+			paginationText := fmt.Sprintf("showing %d-%d of %d", start+1, end, len(m.Emojis))
+			s.WriteString(common.ListBadgeStyle.Render(paginationText))
+			s.WriteString("\n")
+		}
+	}
+
+	if m.EmojiUploadURL != "" {
+		s.WriteString("\n")
+		s.WriteString(common.ListItemStyle.Render("Open this link to upload an emoji:"))
+		s.WriteString("\n")
+		s.WriteString(util.FormatClickableURL(m.EmojiUploadURL, 80, ""))
+		s.WriteString("\n")
+		s.WriteString(common.ListBadgeStyle.Render(fmt.Sprintf("expires at %s • press r to refresh after upload", m.EmojiUploadExpires.Format("15:04"))))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	if m.ConfirmDeleteEmoji {
+		s.WriteString(common.ListErrorStyle.Render("Delete this emoji? (y/n)"))
+	} else {
+		s.WriteString(common.ListBadgeStyle.Render("Keys: ↑/↓: navigate • g: upload link • r: refresh • d: delete • esc: back"))
+	}
+
+	return s.String()
+}
+
+func (m Model) renderInboxQueueView() string {
+	var s strings.Builder
+
+	s.WriteString(common.CaptionStyle.Render(fmt.Sprintf("inbox queue (%d pending, %d held)", m.QueuePending, m.QueueHeld)))
+	s.WriteString("\n\n")
+
+	if len(m.HeldItems) == 0 {
+		s.WriteString(common.ListEmptyStyle.Render("No held activities. Activities land here after failing too often."))
+		s.WriteString("\n")
+	} else {
+		start := m.HeldOffset
+		end := min(start+common.DefaultItemsPerPage, len(m.HeldItems))
+
+		for i := start; i < end; i++ {
+			item := m.HeldItems[i]
+			info := fmt.Sprintf("%s from %s", item.ActivityType, item.ActorURI)
+			badge := fmt.Sprintf(" (%d attempts, received %s)", item.Attempts, item.CreatedAt.Format("2006-01-02 15:04"))
+
+			if i == m.HeldSelected {
+				s.WriteString(common.ListSelectedPrefix + common.ListItemSelectedStyle.Render(info+badge))
+				if item.LastError != "" {
+					s.WriteString("\n")
+					s.WriteString(common.ListUnselectedPrefix + common.ListErrorStyle.Render(item.LastError))
+				}
+			} else {
+				s.WriteString(common.ListUnselectedPrefix + common.ListItemStyle.Render(info) + common.ListBadgeStyle.Render(badge))
+			}
+			s.WriteString("\n")
+		}
+
+		if len(m.HeldItems) > common.DefaultItemsPerPage {
+			s.WriteString("\n")
+			paginationText := fmt.Sprintf("showing %d-%d of %d", start+1, end, len(m.HeldItems))
+			s.WriteString(common.ListBadgeStyle.Render(paginationText))
+			s.WriteString("\n")
+		}
+	}
+
+	s.WriteString("\n")
+	s.WriteString(common.ListBadgeStyle.Render("Keys: ↑/↓: navigate • r: retry • d: drop • R: refresh • esc: back"))
+
+	return s.String()
+}
+
+func (m Model) renderRemoteAccountsView() string {
+	var s strings.Builder
+
+	s.WriteString(common.CaptionStyle.Render("remote accounts"))
+	s.WriteString("\n\n")
+
+	if m.RemoteStats == nil {
+		s.WriteString(common.ListEmptyStyle.Render("Loading..."))
+		s.WriteString("\n")
+	} else {
+		stats := m.RemoteStats
+		rows := []struct {
+			label string
+			count int
+		}{
+			{"cached", stats.Total},
+			{fmt.Sprintf("stale (not fetched in %s)", formatTTL(m.RemoteTTL)), stats.Stale},
+			{"failing (last refetch failed)", stats.Failing},
+			{"gone (404/410, follows removed)", stats.Gone},
+		}
+		for _, row := range rows {
+			s.WriteString(common.ListUnselectedPrefix + common.ListItemStyle.Render(fmt.Sprintf("%6d ", row.count)) + common.ListBadgeStyle.Render(row.label))
+			s.WriteString("\n")
+		}
+	}
+
+	s.WriteString("\n")
+	if m.LastRefresh == nil {
+		s.WriteString(common.ListBadgeStyle.Render("No refresh has run since the server started. The job runs hourly."))
+	} else {
+		last := m.LastRefresh
+		s.WriteString(common.ListBadgeStyle.Render(fmt.Sprintf("Last refresh %s: %d refreshed, %d failed, %d gone",
+			last.RanAt.Format("2006-01-02 15:04"), last.Refreshed, last.Failed, last.Gone)))
+	}
+	s.WriteString("\n\n")
+	s.WriteString(common.ListBadgeStyle.Render("Keys: r: refresh now • R: reload • esc: back"))
+
+	return s.String()
+}
+
+func (m Model) renderRetentionView() string {
+	var s strings.Builder
+
+	s.WriteString(common.CaptionStyle.Render("retention"))
+	s.WriteString("\n\n")
+
+	if m.RetentionDays <= 0 {
+		s.WriteString(common.ListEmptyStyle.Render("Retention is disabled. Set retentionDays (or STEGODON_RETENTION_DAYS) to prune old federated content."))
+		s.WriteString("\n\n")
+		s.WriteString(common.ListBadgeStyle.Render("Keys: R: reload • esc: back"))
+		return s.String()
+	}
+
+	s.WriteString(common.ListBadgeStyle.Render(fmt.Sprintf("Content older than %d days is pruned daily. A prune now would remove:", m.RetentionDays)))
+	s.WriteString("\n\n")
+
+	if m.RetentionPreview == nil {
+		s.WriteString(common.ListEmptyStyle.Render("Loading..."))
+		s.WriteString("\n")
+	} else {
+		stats := m.RetentionPreview
+		rows := []struct {
+			label string
+			count int
+		}{
+			{"remote posts nobody interacted with", stats.Activities},
+			{"boosts of removed posts", stats.Boosts},
+			{"notifications", stats.Notifications},
+			{"remote accounts nothing refers to", stats.RemoteAccounts},
+		}
+		for _, row := range rows {
+			s.WriteString(common.ListUnselectedPrefix + common.ListItemStyle.Render(fmt.Sprintf("%6d ", row.count)) + common.ListBadgeStyle.Render(row.label))
+			s.WriteString("\n")
+		}
+	}
+
+	s.WriteString("\n")
+	s.WriteString(common.ListBadgeStyle.Render("Keys: p: prune now • R: dry run again • esc: back"))
+
+	return s.String()
+}
+
+func (m Model) renderStatusView() string {
+	var s strings.Builder
+
+	s.WriteString(common.CaptionStyle.Render("server status"))
+	s.WriteString("\n\n")
+
+	status := m.ServerStatus
+	if status == nil {
+		s.WriteString(common.ListEmptyStyle.Render("Loading..."))
+		s.WriteString("\n\n")
+		s.WriteString(common.ListBadgeStyle.Render("Keys: esc: back"))
+		return s.String()
+	}
+
+	ready := "ready"
+	if !status.Ready {
+		var failed []string
+		for _, check := range status.Checks {
+			if !check.OK {
+				failed = append(failed, check.Name+": "+check.Error)
+			}
+		}
+		ready = "not ready (" + strings.Join(failed, ", ") + ")"
+	}
+
+	deliveries := fmt.Sprintf("%d queued", status.DeliveryQueue.Depth)
+	if status.DeliveryQueue.OldestAt != nil {
+		deliveries += fmt.Sprintf(", oldest %s old", formatUptime(time.Since(*status.DeliveryQueue.OldestAt)))
+	}
+	lastDelivery := "none since start"
+	if status.LastSuccessfulDelivery != nil {
+		lastDelivery = status.LastSuccessfulDelivery.Format("2006-01-02 15:04:05")
+	}
+
+	rows := []struct {
+		label string
+		value string
+	}{
+		{"version", status.Version},
+		{"uptime", formatUptime(status.Uptime())},
+		{"readiness", ready},
+		{"delivery queue", deliveries},
+		{"inbox queue", fmt.Sprintf("%d pending, %d held", status.InboxQueue.Pending, status.InboxQueue.Held)},
+		{"last delivery", lastDelivery},
+		{"database size", formatBytes(status.DatabaseBytes)},
+	}
+	for _, row := range rows {
+		s.WriteString(common.ListUnselectedPrefix + common.ListBadgeStyle.Render(fmt.Sprintf("%-15s ", row.label)) + common.ListItemStyle.Render(row.value))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(common.CaptionStyle.Render(fmt.Sprintf("relays (%d)", len(status.Relays))))
+	s.WriteString("\n")
+	if len(status.Relays) == 0 {
+		s.WriteString(common.ListEmptyStyle.Render("No relays."))
+		s.WriteString("\n")
+	}
+	for _, relay := range status.Relays {
+		state := relay.Status
+		if relay.Paused {
+			state += ", paused"
+		}
+		s.WriteString(common.ListUnselectedPrefix + common.ListItemStyle.Render(relay.ActorURI) + common.ListBadgeStyle.Render(" ("+state+")"))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(common.ListBadgeStyle.Render(fmt.Sprintf("Refreshes every %ds. Keys: R: reload • esc: back", int(statusRefreshInterval.Seconds()))))
+
+	return s.String()
+}
+
+// formatUptime renders a duration as days, hours and minutes
+func formatUptime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+	if hours > 0 {
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+// formatBytes renders a size in bytes with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatTTL renders a refresh TTL in hours or days
+func formatTTL(ttl time.Duration) string {
+	hours := int(ttl.Hours())
+	if hours >= 24 && hours%24 == 0 {
+		return fmt.Sprintf("%dd", hours/24)
+	}
+	return fmt.Sprintf("%dh", hours)
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -25,6 +25,11 @@ const (
 	ThreadView          // View thread with parent and replies
 	NotificationsView   // View notifications
 	ProfileView         // View user profile with recent posts
+	TagView             // View posts for a hashtag and follow/unfollow it
+	ListTimelineView    // Timeline of a user-defined list (one tab per list)
+	ResolveView         // Open a remote post or account by pasting its URL
+	ScheduledNotesView  // Queue of notes waiting to be published
+	DraftsView          // Autosaved drafts to resume or discard
 )
 
 const (
@@ -32,13 +37,70 @@ const (
 	DeleteAccountView = AccountSettingsView
 )
 
+// sessionStateNames are the view names used in metrics
+var sessionStateNames = map[SessionState]string{
+	CreateNoteView:      "create_note",
+	HomeTimelineView:    "home_timeline",
+	MyPostsView:         "my_posts",
+	GlobalPostsView:     "global_posts",
+	CreateUserView:      "create_user",
+	UpdateNoteList:      "update_note_list",
+	FollowUserView:      "follow_user",
+	FollowersView:       "followers",
+	FollowingView:       "following",
+	LocalUsersView:      "local_users",
+	AdminPanelView:      "admin_panel",
+	RelayManagementView: "relay_management",
+	AccountSettingsView: "account_settings",
+	ThreadView:          "thread",
+	NotificationsView:   "notifications",
+	ProfileView:         "profile",
+	TagView:             "tag",
+	ListTimelineView:    "list_timeline",
+	ResolveView:         "resolve",
+	ScheduledNotesView:  "scheduled_notes",
+	DraftsView:          "drafts",
+}
+
+// String returns the view's name, e.g. "home_timeline"
+func (s SessionState) String() string {
+	if name, ok := sessionStateNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
 // EditNoteMsg is sent when user wants to edit an existing note
 type EditNoteMsg struct {
 	NoteId    uuid.UUID
 	Message   string
+	Title     string // Set when the note is an article
 	CreatedAt time.Time
 }
 
+// EditScheduledNoteMsg is sent when user wants to edit a scheduled note
+type EditScheduledNoteMsg struct {
+	Id          uuid.UUID
+	Message     string
+	ScheduledAt time.Time
+}
+
+// ResumeDraftMsg is sent when user picks a draft to continue writing
+type ResumeDraftMsg struct {
+	Id             uuid.UUID
+	Message        string
+	Title          string // Set when the draft is an article
+	InReplyToURI   string // Set when the draft is a reply
+	ReplyToAuthor  string
+	ReplyToPreview string
+	CreatedAt      time.Time
+}
+
+// DraftDiscardedMsg is sent when a draft was deleted from the drafts list
+type DraftDiscardedMsg struct {
+	Id uuid.UUID
+}
+
 // DeleteNoteMsg is sent when user confirms note deletion
 type DeleteNoteMsg struct {
 	NoteId uuid.UUID
@@ -74,6 +136,15 @@ type ViewThreadMsg struct {
 	CreatedAt time.Time // Timestamp
 }
 
+// ViewTagMsg is sent when user presses '#' to open the tag view for a hashtag
+type ViewTagMsg struct {
+	Tag string // Hashtag name without the leading #
+}
+
+// ListsChangedMsg is sent when the user creates, deletes or edits a list,
+// so the list timelines in the tab cycle can be reloaded
+type ListsChangedMsg struct{}
+
 // LikeNoteMsg is sent when user presses 'l' to like/unlike a post
 type LikeNoteMsg struct {
 	NoteURI string    // ActivityPub object URI of the note being liked
@@ -83,8 +154,9 @@ type LikeNoteMsg struct {
 
 // ViewProfileMsg is sent when user presses Enter on a local user to view their profile
 type ViewProfileMsg struct {
-	Username  string
-	AccountId uuid.UUID
+	Username       string
+	AccountId      uuid.UUID
+	RemoteActorURI string // Set to open the profile of a remote account
 }
 
 // BoostNoteMsg is sent when user presses 'b' to boost/unboost a post
@@ -93,3 +165,11 @@ type BoostNoteMsg struct {
 	NoteID  uuid.UUID // Local UUID (if local note)
 	IsLocal bool      // Whether this is a local note
 }
+
+// ReactNoteMsg is sent when user picks an emoji reaction for a post
+type ReactNoteMsg struct {
+	NoteURI string    // ActivityPub object URI of the note being reacted to
+	NoteID  uuid.UUID // Local UUID (if local note)
+	IsLocal bool      // Whether this is a local note
+	Emoji   string    // The selected emoji
+}
@@ -0,0 +1,26 @@
+package common
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/deemkeen/stegodon/events"
+)
+
+// EventMsg carries an event from the event bus into the TUI
+type EventMsg struct {
+	Event events.Event
+}
+
+// WaitForEvent returns a command that waits for the next event of the subscription.
+// It returns nil once the subscription is closed, which ends the chain.
+func WaitForEvent(sub *events.Subscription) tea.Cmd {
+	if sub == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		e, ok := <-sub.Events()
+		if !ok {
+			return nil
+		}
+		return EventMsg{Event: e}
+	}
+}
@@ -0,0 +1,16 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var filterWarningStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color(COLOR_WARNING)).
+	Italic(true)
+
+// FilterWarning renders the line shown instead of a post collapsed by a keyword filter
+func FilterWarning(phrase string) string {
+	return filterWarningStyle.Render(fmt.Sprintf("⚠ filtered: %s (v: show)", phrase))
+}
@@ -67,10 +67,7 @@ const (
 	// ReplyIndentWidth is the number of spaces used to indent replies in thread view
 	ReplyIndentWidth = 4
 
-	// TimelineRefreshSeconds is the interval for auto-refreshing timeline views
-	TimelineRefreshSeconds = 10
-
-	// HomeTimelinePostLimit is the maximum number of posts to load in home timeline
+	// HomeTimelinePostLimit is the number of posts loaded per page in the timelines
 	HomeTimelinePostLimit = 50
 
 	// MaxNoteDBLength is the maximum character length for notes in the database
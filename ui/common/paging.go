@@ -0,0 +1,29 @@
+package common
+
+// AppendPage appends the items of an older page that are not loaded yet.
+// Timelines can return a post on two pages (e.g. a boost and its original).
+func AppendPage[T any, K comparable](items, page []T, key func(T) K) []T {
+	seen := make(map[K]bool, len(items))
+	for _, item := range items {
+		seen[key(item)] = true
+	}
+	for _, item := range page {
+		if !seen[key(item)] {
+			seen[key(item)] = true
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ReloadLimit is how many items a reload reads so that pages loaded by
+// scrolling down stay loaded
+func ReloadLimit(loaded, pageSize int) int {
+	return max(loaded, pageSize)
+}
+
+// ShouldLoadMore reports whether the selection reached the last loaded item
+// while older items may exist and no page is loading yet
+func ShouldLoadMore(selected, loaded int, hasMore, loading bool) bool {
+	return hasMore && !loading && loaded > 0 && selected >= loaded-1
+}
@@ -0,0 +1,94 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/deemkeen/stegodon/domain"
+)
+
+// ReactionEmojis is the set of emoji offered by the reaction picker
+var ReactionEmojis = []string{"👍", "❤️", "😂", "😮", "😢", "🎉", "🔥", "👀"}
+
+var (
+	reactionPickerStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(COLOR_MUTED))
+
+	reactionPickerSelectedStyle = lipgloss.NewStyle().
+					Background(lipgloss.Color(COLOR_BUTTON)).
+					Foreground(lipgloss.Color(COLOR_BLACK))
+
+	reactionCountStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(COLOR_LIGHT))
+)
+
+// ReactionPicker is a small inline emoji selector used by timeline views
+type ReactionPicker struct {
+	Active   bool
+	Selected int
+}
+
+// Open shows the picker with the first emoji selected
+func (p *ReactionPicker) Open() {
+	p.Active = true
+	p.Selected = 0
+}
+
+// Close hides the picker
+func (p *ReactionPicker) Close() {
+	p.Active = false
+}
+
+// HandleKey processes a key press while the picker is open.
+// It returns the chosen emoji when the user confirms, or an empty string otherwise.
+func (p *ReactionPicker) HandleKey(key string) string {
+	switch key {
+	case "left", "h":
+		if p.Selected > 0 {
+			p.Selected--
+		}
+	case "right", "l":
+		if p.Selected < len(ReactionEmojis)-1 {
+			p.Selected++
+		}
+	case "enter":
+		p.Active = false
+		return ReactionEmojis[p.Selected]
+	case "esc", "e":
+		p.Active = false
+	default:
+		// Number keys pick an emoji directly
+		if len(key) == 1 && key[0] >= '1' && int(key[0]-'0') <= len(ReactionEmojis) {
+			p.Active = false
+			return ReactionEmojis[key[0]-'1']
+		}
+	}
+	return ""
+}
+
+// View renders the picker as a single line
+func (p ReactionPicker) View() string {
+	var s strings.Builder
+	for i, emoji := range ReactionEmojis {
+		if i == p.Selected {
+			s.WriteString(reactionPickerSelectedStyle.Render(" " + emoji + " "))
+		} else {
+			s.WriteString(reactionPickerStyle.Render(" " + emoji + " "))
+		}
+	}
+	s.WriteString(reactionPickerStyle.Render("  ←/→ • enter: react • esc: cancel"))
+	return s.String()
+}
+
+// FormatReactionCounts renders per-emoji reaction counts, e.g. "👍 2  🎉 1"
+func FormatReactionCounts(reactions []domain.ReactionCount) string {
+	if len(reactions) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(reactions))
+	for _, r := range reactions {
+		parts = append(parts, fmt.Sprintf("%s %d", r.Emoji, r.Count))
+	}
+	return reactionCountStyle.Render(strings.Join(parts, "  "))
+}
@@ -0,0 +1,203 @@
+package drafts
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/ui/common"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+// previewLength is the number of characters of a draft shown in the list
+const previewLength = 60
+
+type Model struct {
+	AccountId  uuid.UUID
+	Drafts     []domain.Draft
+	Selected   int
+	Status     string
+	Error      string
+	ReturnView common.SessionState // View to return to on Esc
+}
+
+func InitialModel(accountId uuid.UUID) Model {
+	return Model{
+		AccountId:  accountId,
+		ReturnView: common.CreateNoteView,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return loadDraftsCmd(m.AccountId)
+}
+
+// draftsLoadedMsg is sent when the drafts are (re)loaded
+type draftsLoadedMsg struct {
+	drafts []domain.Draft
+	status string
+	err    error
+}
+
+// clearStatusMsg is sent after a delay to clear status/error messages
+type clearStatusMsg struct{}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case clearStatusMsg:
+		m.Status = ""
+		m.Error = ""
+		return m, nil
+
+	case draftsLoadedMsg:
+		m.Drafts = msg.drafts
+		if m.Selected >= len(m.Drafts) {
+			m.Selected = max(len(m.Drafts)-1, 0)
+		}
+		if msg.err != nil {
+			m.Error = fmt.Sprintf("Failed: %v", msg.err)
+			return m, clearStatusAfter(3 * time.Second)
+		}
+		if msg.status != "" {
+			m.Status = msg.status
+			return m, clearStatusAfter(2 * time.Second)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if m.Selected > 0 {
+				m.Selected--
+			}
+		case "down", "j":
+			if m.Selected < len(m.Drafts)-1 {
+				m.Selected++
+			}
+		case "enter":
+			if m.Selected < len(m.Drafts) {
+				draft := m.Drafts[m.Selected]
+				return m, func() tea.Msg {
+					return common.ResumeDraftMsg{
+						Id:             draft.Id,
+						Message:        draft.Message,
+						Title:          draft.Title,
+						InReplyToURI:   draft.InReplyToURI,
+						ReplyToAuthor:  draft.ReplyToAuthor,
+						ReplyToPreview: draft.ReplyToPreview,
+						CreatedAt:      draft.CreatedAt,
+					}
+				}
+			}
+		case "d":
+			if m.Selected < len(m.Drafts) {
+				draft := m.Drafts[m.Selected]
+				return m, tea.Batch(
+					draftActionCmd(m.AccountId, func(database db.Store) error {
+						return database.DeleteDraft(draft.Id, m.AccountId)
+					}, "Discarded draft"),
+					func() tea.Msg { return common.DraftDiscardedMsg{Id: draft.Id} },
+				)
+			}
+		case "esc":
+			m.Status = ""
+			m.Error = ""
+			returnView := m.ReturnView
+			return m, func() tea.Msg { return returnView }
+		}
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	var s strings.Builder
+
+	s.WriteString(common.CaptionStyle.Render("drafts"))
+	s.WriteString("\n\n")
+
+	if len(m.Drafts) == 0 {
+		s.WriteString(common.ListEmptyStyle.Render("No drafts.\nNotes are saved here while you type."))
+		s.WriteString("\n")
+	}
+
+	for i, draft := range m.Drafts {
+		preview := strings.ReplaceAll(util.StripHTMLTags(draft.Message), "\n", " ")
+		if draft.Title != "" {
+			preview = draft.Title + ": " + preview
+		}
+		if len([]rune(preview)) > previewLength {
+			preview = string([]rune(preview)[:previewLength-3]) + "..."
+		}
+		badge := " " + draft.UpdatedAt.Local().Format(util.ScheduleTimeFormat)
+		if draft.InReplyToURI != "" {
+			badge = " reply to " + draft.ReplyToAuthor + " ·" + badge
+		}
+
+		if i == m.Selected {
+			s.WriteString(common.ListSelectedPrefix + common.ListItemSelectedStyle.Render(preview+badge))
+		} else {
+			s.WriteString(common.ListUnselectedPrefix + common.ListItemStyle.Render(preview) + common.ListBadgeStyle.Render(badge))
+		}
+		s.WriteString("\n")
+	}
+
+	if m.Status != "" {
+		s.WriteString("\n")
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(common.COLOR_SUCCESS)).Render(m.Status))
+		s.WriteString("\n")
+	}
+
+	if m.Error != "" {
+		s.WriteString("\n")
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(common.COLOR_ERROR)).Render(m.Error))
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+// clearStatusAfter returns a command that sends clearStatusMsg after a duration
+func clearStatusAfter(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return clearStatusMsg{}
+	})
+}
+
+// loadDraftsCmd loads the account's drafts
+func loadDraftsCmd(accountId uuid.UUID) tea.Cmd {
+	return draftActionCmd(accountId, nil, "")
+}
+
+// draftActionCmd runs action (if any) and reloads the drafts afterwards
+func draftActionCmd(accountId uuid.UUID, action func(database db.Store) error, status string) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+		msg := draftsLoadedMsg{}
+
+		if action != nil {
+			if err := action(database); err != nil {
+				log.Printf("Draft update failed: %v", err)
+				msg.err = err
+			} else {
+				msg.status = status
+			}
+		}
+
+		err, drafts := database.ReadDraftsByAccountId(accountId)
+		if err != nil {
+			log.Printf("Failed to load drafts: %v", err)
+			msg.err = err
+			return msg
+		}
+		if drafts != nil {
+			msg.drafts = *drafts
+		}
+		return msg
+	}
+}
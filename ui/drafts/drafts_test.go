@@ -0,0 +1,82 @@
+package drafts
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/ui/common"
+	"github.com/google/uuid"
+)
+
+func testDrafts() []domain.Draft {
+	now := time.Now()
+	return []domain.Draft{
+		{Id: uuid.New(), Message: "half a thought", UpdatedAt: now},
+		{
+			Id: uuid.New(), Message: "@bob agreed", UpdatedAt: now.Add(-time.Hour),
+			InReplyToURI: "https://remote.example/notes/1", ReplyToAuthor: "@bob@remote.example", ReplyToPreview: "thoughts?",
+		},
+	}
+}
+
+func TestResumeSelectedDraft(t *testing.T) {
+	m := InitialModel(uuid.New())
+	m, _ = m.Update(draftsLoadedMsg{drafts: testDrafts()})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if m.Selected != 1 {
+		t.Fatalf("Expected selection to stop at the last draft, got %d", m.Selected)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected resume command")
+	}
+	resume, ok := cmd().(common.ResumeDraftMsg)
+	if !ok || resume.Id != m.Drafts[1].Id || resume.InReplyToURI != "https://remote.example/notes/1" || resume.ReplyToPreview != "thoughts?" {
+		t.Errorf("Expected ResumeDraftMsg with reply context, got %#v", resume)
+	}
+}
+
+func TestLoadedMsgClampsSelection(t *testing.T) {
+	m := InitialModel(uuid.New())
+	m.Selected = 3
+
+	m, _ = m.Update(draftsLoadedMsg{drafts: testDrafts()[:1], status: "Discarded draft"})
+	if m.Selected != 0 || m.Status == "" {
+		t.Errorf("Expected selection 0 and status, got %d %q", m.Selected, m.Status)
+	}
+
+	m, _ = m.Update(draftsLoadedMsg{err: errors.New("db locked")})
+	if m.Error == "" {
+		t.Error("Expected error to be shown")
+	}
+}
+
+func TestEscReturnsToReturnView(t *testing.T) {
+	m := InitialModel(uuid.New())
+	m.ReturnView = common.HomeTimelineView
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if cmd == nil || cmd() != common.HomeTimelineView {
+		t.Error("Expected to return to HomeTimelineView")
+	}
+}
+
+func TestView(t *testing.T) {
+	m := InitialModel(uuid.New())
+	if !strings.Contains(m.View(), "No drafts") {
+		t.Error("Expected empty state")
+	}
+
+	m, _ = m.Update(draftsLoadedMsg{drafts: testDrafts()})
+	view := m.View()
+	if !strings.Contains(view, "half a thought") || !strings.Contains(view, "reply to @bob@remote.example") {
+		t.Errorf("Expected drafts with reply context, got:\n%s", view)
+	}
+}
@@ -24,6 +24,9 @@ type Model struct {
 	Height    int
 	Status    string
 	Error     string
+
+	EditingLists bool // List picker for the selected account is open
+	picker       listPicker
 }
 
 func InitialModel(accountId uuid.UUID, width, height int) Model {
@@ -49,6 +52,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.Following = msg.following
 		m.Selected = 0
 		m.Offset = 0
+		m.EditingLists = false
 		return m, nil
 
 	case clearStatusMsg:
@@ -56,7 +60,29 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.Error = ""
 		return m, nil
 
+	case listsLoadedMsg:
+		m.picker.lists = msg.lists
+		m.picker.memberships = msg.memberships
+		if m.picker.selected >= len(m.picker.lists) {
+			m.picker.selected = max(0, len(m.picker.lists)-1)
+		}
+		if msg.err != nil {
+			m.Error = fmt.Sprintf("List update failed: %v", msg.err)
+			return m, clearStatusAfter(2 * time.Second)
+		}
+		if msg.changed {
+			m.Status = msg.status
+			m.Error = ""
+			return m, tea.Batch(clearStatusAfter(2*time.Second), func() tea.Msg {
+				return common.ListsChangedMsg{}
+			})
+		}
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.EditingLists {
+			return m.updateListPicker(msg)
+		}
 		switch msg.String() {
 		case "up", "k":
 			if m.Selected > 0 {
@@ -74,31 +100,21 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					m.Offset = m.Selected - common.DefaultItemsPerPage + 1
 				}
 			}
+		case "a":
+			// Manage the lists of the selected account
+			if len(m.Following) > 0 && m.Selected < len(m.Following) {
+				selectedFollow := m.Following[m.Selected]
+				m.picker = newListPicker(selectedFollow, followDisplayName(selectedFollow))
+				m.EditingLists = true
+				return m, loadLists(m.AccountId, selectedFollow.TargetAccountId)
+			}
 		case "u", "enter":
 			// Unfollow the selected account
 			if len(m.Following) > 0 && m.Selected < len(m.Following) {
 				selectedFollow := m.Following[m.Selected]
 				database := db.GetDB()
 
-				var displayName string
-
-				if selectedFollow.IsLocal {
-					// Local follow - get local account details
-					err, localAcc := database.ReadAccById(selectedFollow.TargetAccountId)
-					if err == nil && localAcc != nil {
-						displayName = "@" + localAcc.Username
-					} else {
-						displayName = "user"
-					}
-				} else {
-					// Remote follow - get remote account details
-					err, remoteAcc := database.ReadRemoteAccountById(selectedFollow.TargetAccountId)
-					if err == nil && remoteAcc != nil {
-						displayName = fmt.Sprintf("@%s@%s", remoteAcc.Username, remoteAcc.Domain)
-					} else {
-						displayName = "user"
-					}
-				}
+				displayName := followDisplayName(selectedFollow)
 
 				// Delete the follow and send Undo activity for remote follows
 				go func() {
@@ -163,6 +179,20 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 func (m Model) View() string {
 	var s strings.Builder
 
+	if m.EditingLists {
+		s.WriteString(m.listPickerView())
+		s.WriteString("\n")
+		if m.Status != "" {
+			s.WriteString(common.ListStatusStyle.Render(m.Status))
+			s.WriteString("\n")
+		}
+		if m.Error != "" {
+			s.WriteString(common.ListErrorStyle.Render(m.Error))
+			s.WriteString("\n")
+		}
+		return s.String()
+	}
+
 	s.WriteString(common.CaptionStyle.Render(fmt.Sprintf("following (%d)", len(m.Following))))
 	s.WriteString("\n\n")
 
@@ -240,6 +270,27 @@ func (m Model) View() string {
 	return s.String()
 }
 
+// followDisplayName returns the @handle of a followed local or remote account
+func followDisplayName(follow domain.Follow) string {
+	database := db.GetDB()
+
+	if follow.IsLocal {
+		// Local follow - get local account details
+		err, localAcc := database.ReadAccById(follow.TargetAccountId)
+		if err == nil && localAcc != nil {
+			return "@" + localAcc.Username
+		}
+		return "user"
+	}
+
+	// Remote follow - get remote account details
+	err, remoteAcc := database.ReadRemoteAccountById(follow.TargetAccountId)
+	if err == nil && remoteAcc != nil {
+		return fmt.Sprintf("@%s@%s", remoteAcc.Username, remoteAcc.Domain)
+	}
+	return "user"
+}
+
 // followingLoadedMsg is sent when following list is loaded
 type followingLoadedMsg struct {
 	following []domain.Follow
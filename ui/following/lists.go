@@ -0,0 +1,230 @@
+package following
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/ui/common"
+	"github.com/google/uuid"
+)
+
+// maxListNameLength limits list names so they fit in the view caption
+const maxListNameLength = 50
+
+// listPicker manages the lists of the selected followed account
+type listPicker struct {
+	member      domain.Follow      // Followed account whose list memberships are edited
+	memberName  string             // Display name of the account (e.g. "@bob@example.com")
+	lists       []domain.List      // All lists of the user
+	memberships map[uuid.UUID]bool // Lists that contain the member
+	selected    int
+	naming      bool // Input mode for creating a new list
+	input       textinput.Model
+}
+
+func newListPicker(member domain.Follow, memberName string) listPicker {
+	ti := textinput.New()
+	ti.Placeholder = "list name"
+	ti.CharLimit = maxListNameLength
+	ti.Width = 40
+
+	return listPicker{
+		member:      member,
+		memberName:  memberName,
+		memberships: map[uuid.UUID]bool{},
+		input:       ti,
+	}
+}
+
+// listsLoadedMsg is sent when the user's lists and the member's memberships are (re)loaded
+type listsLoadedMsg struct {
+	lists       []domain.List
+	memberships map[uuid.UUID]bool
+	status      string
+	err         error
+	changed     bool // true if an action modified the lists
+}
+
+// loadLists loads the account's lists and which of them contain memberId
+func loadLists(accountId, memberId uuid.UUID) tea.Cmd {
+	return listActionCmd(accountId, memberId, nil, "")
+}
+
+// listActionCmd runs action (if any) and reloads the lists afterwards
+func listActionCmd(accountId, memberId uuid.UUID, action func(database db.Store) error, status string) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+		msg := listsLoadedMsg{memberships: map[uuid.UUID]bool{}}
+
+		if action != nil {
+			if err := action(database); err != nil {
+				log.Printf("List update failed: %v", err)
+				msg.err = err
+			} else {
+				msg.status = status
+				msg.changed = true
+			}
+		}
+
+		err, lists := database.ReadListsByAccountId(accountId)
+		if err != nil {
+			log.Printf("Failed to load lists: %v", err)
+			msg.err = err
+			return msg
+		}
+		if lists != nil {
+			msg.lists = *lists
+		}
+
+		err, ids := database.ReadListIdsByMember(accountId, memberId)
+		if err != nil {
+			log.Printf("Failed to load list memberships: %v", err)
+		}
+		for _, id := range ids {
+			msg.memberships[id] = true
+		}
+		return msg
+	}
+}
+
+// updateListPicker handles keys while the list picker is open
+func (m Model) updateListPicker(msg tea.KeyMsg) (Model, tea.Cmd) {
+	p := &m.picker
+
+	if p.naming {
+		switch msg.String() {
+		case "esc":
+			p.naming = false
+			p.input.Blur()
+			p.input.SetValue("")
+			return m, nil
+		case "enter":
+			name := strings.TrimSpace(p.input.Value())
+			if name == "" {
+				m.Error = "List name cannot be empty"
+				return m, clearStatusAfter(2 * time.Second)
+			}
+			for _, list := range p.lists {
+				if strings.EqualFold(list.Name, name) {
+					m.Error = fmt.Sprintf("List %q already exists", list.Name)
+					return m, clearStatusAfter(2 * time.Second)
+				}
+			}
+			p.naming = false
+			p.input.Blur()
+			p.input.SetValue("")
+			list := &domain.List{Id: uuid.New(), AccountId: m.AccountId, Name: name, CreatedAt: time.Now()}
+			member := p.member
+			return m, listActionCmd(m.AccountId, member.TargetAccountId, func(database db.Store) error {
+				if err := database.CreateList(list); err != nil {
+					return err
+				}
+				return database.AddListMember(list.Id, member.TargetAccountId, member.IsLocal)
+			}, fmt.Sprintf("Created list %s with %s", name, p.memberName))
+		default:
+			var cmd tea.Cmd
+			p.input, cmd = p.input.Update(msg)
+			return m, cmd
+		}
+	}
+
+	switch msg.String() {
+	case "esc", "a":
+		m.EditingLists = false
+		return m, nil
+	case "up", "k":
+		if p.selected > 0 {
+			p.selected--
+		}
+	case "down", "j":
+		if p.selected < len(p.lists)-1 {
+			p.selected++
+		}
+	case "n":
+		p.naming = true
+		p.input.Focus()
+		return m, textinput.Blink
+	case "enter", " ":
+		// Add the account to or remove it from the highlighted list
+		if p.selected < len(p.lists) {
+			list := p.lists[p.selected]
+			member := p.member
+			if p.memberships[list.Id] {
+				return m, listActionCmd(m.AccountId, member.TargetAccountId, func(database db.Store) error {
+					return database.RemoveListMember(list.Id, member.TargetAccountId)
+				}, fmt.Sprintf("Removed %s from %s", p.memberName, list.Name))
+			}
+			return m, listActionCmd(m.AccountId, member.TargetAccountId, func(database db.Store) error {
+				return database.AddListMember(list.Id, member.TargetAccountId, member.IsLocal)
+			}, fmt.Sprintf("Added %s to %s", p.memberName, list.Name))
+		}
+	case "h":
+		// Toggle whether the list's members are hidden from the home timeline
+		if p.selected < len(p.lists) {
+			list := p.lists[p.selected]
+			status := fmt.Sprintf("%s members are hidden from home", list.Name)
+			if list.ExcludeFromHome {
+				status = fmt.Sprintf("%s members are shown in home", list.Name)
+			}
+			return m, listActionCmd(m.AccountId, p.member.TargetAccountId, func(database db.Store) error {
+				return database.UpdateListExcludeFromHome(list.Id, !list.ExcludeFromHome)
+			}, status)
+		}
+	case "d":
+		if p.selected < len(p.lists) {
+			list := p.lists[p.selected]
+			return m, listActionCmd(m.AccountId, p.member.TargetAccountId, func(database db.Store) error {
+				return database.DeleteList(list.Id)
+			}, fmt.Sprintf("Deleted list %s", list.Name))
+		}
+	}
+	return m, nil
+}
+
+// listPickerView renders the list picker
+func (m Model) listPickerView() string {
+	var s strings.Builder
+	p := m.picker
+
+	s.WriteString(common.CaptionStyle.Render(fmt.Sprintf("lists for %s", p.memberName)))
+	s.WriteString("\n\n")
+
+	if len(p.lists) == 0 {
+		s.WriteString(common.ListEmptyStyle.Render("You don't have any lists yet.\nPress n to create one."))
+		s.WriteString("\n")
+	}
+
+	for i, list := range p.lists {
+		check := "[ ]"
+		if p.memberships[list.Id] {
+			check = "[x]"
+		}
+		badge := fmt.Sprintf(" (%d)", list.MemberCount)
+		if list.ExcludeFromHome {
+			badge += " [hidden from home]"
+		}
+
+		if i == p.selected {
+			text := common.ListItemSelectedStyle.Render(check + " " + list.Name + badge)
+			s.WriteString(common.ListSelectedPrefix + text)
+		} else {
+			text := check + " " + list.Name + common.ListBadgeStyle.Render(badge)
+			s.WriteString(common.ListUnselectedPrefix + common.ListItemStyle.Render(text))
+		}
+		s.WriteString("\n")
+	}
+
+	if p.naming {
+		s.WriteString("\nNew list:\n")
+		s.WriteString(p.input.View())
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/deemkeen/stegodon/db"
 	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
 	"github.com/deemkeen/stegodon/ui/common"
 	"github.com/deemkeen/stegodon/util"
 	"github.com/google/uuid"
@@ -65,17 +66,22 @@ var (
 type Model struct {
 	AccountId          uuid.UUID
 	Posts              []domain.GlobalTimelinePost
-	Offset             int  // Pagination offset
-	Selected           int  // Currently selected post index
+	Offset             int // Pagination offset
+	Selected           int // Currently selected post index
 	Width              int
 	Height             int
-	isActive           bool     // Track if this view is currently visible
-	tickerRunning      bool     // Track if refresh ticker is already running (prevents multiple ticker chains)
-	showingURL         bool     // Track if URL is displayed instead of content
-	showingEngagement  bool     // Track if engagement info (likes/boosts) is displayed
-	engagementLikers   []string // List of users who liked the selected post
-	engagementBoosters []string // List of users who boosted the selected post
+	isActive           bool          // Track if this view is currently visible
+	reloadPending      bool          // A reload is scheduled, further events wait for it
+	nextCursor         domain.Cursor // Position of the last loaded post, where the next page starts
+	hasMore            bool          // The last page was full, older posts may exist
+	loadingMore        bool          // A next page is being loaded
+	showingURL         bool          // Track if URL is displayed instead of content
+	showingEngagement  bool          // Track if engagement info (likes/boosts) is displayed
+	engagementLikers   []string      // List of users who liked the selected post
+	engagementBoosters []string      // List of users who boosted the selected post
 	LocalDomain        string
+	reactionPicker     common.ReactionPicker
+	revealed           map[string]bool // Posts collapsed by a keyword filter that the user chose to show
 }
 
 func InitialModel(accountId uuid.UUID, width, height int, localDomain string) Model {
@@ -88,6 +94,7 @@ func InitialModel(accountId uuid.UUID, width, height int, localDomain string) Mo
 		Height:      height,
 		isActive:    false,
 		LocalDomain: localDomain,
+		revealed:    map[string]bool{},
 	}
 }
 
@@ -95,12 +102,12 @@ func (m Model) Init() tea.Cmd {
 	return nil
 }
 
-// refreshTickMsg is sent periodically to refresh the timeline
+// refreshTickMsg is sent shortly after an event that changes the timeline
 type refreshTickMsg struct{}
 
-// tickRefresh returns a command that sends refreshTickMsg
+// tickRefresh returns a command that sends refreshTickMsg after events.ReloadDelay
 func tickRefresh() tea.Cmd {
-	return tea.Tick(common.TimelineRefreshSeconds*time.Second, func(t time.Time) tea.Msg {
+	return tea.Tick(events.ReloadDelay, func(t time.Time) tea.Msg {
 		return refreshTickMsg{}
 	})
 }
@@ -109,41 +116,52 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case common.DeactivateViewMsg:
 		m.isActive = false
-		m.tickerRunning = false // Stop ticker chain
 		return m, nil
 
 	case common.ActivateViewMsg:
 		m.isActive = true
-		m.tickerRunning = false // Reset ticker state
 		m.Selected = 0
 		m.Offset = 0
-		return m, loadGlobalPosts()
+		m.reactionPicker.Close()
+		return m, loadGlobalPosts(m.AccountId, domain.Cursor{}, common.HomeTimelinePostLimit)
 
 	case common.SessionState:
 		if msg == common.UpdateNoteList {
-			return m, loadGlobalPosts()
+			return m, m.reload()
+		}
+		return m, nil
+
+	case common.EventMsg:
+		if m.isActive && !m.reloadPending && events.ChangesTimeline(msg.Event, m.AccountId) {
+			m.reloadPending = true
+			return m, tickRefresh()
 		}
 		return m, nil
 
 	case refreshTickMsg:
+		m.reloadPending = false
 		if m.isActive {
-			return m, loadGlobalPosts()
+			return m, m.reload()
 		}
 		return m, nil
 
 	case postsLoadedMsg:
-		m.Posts = msg.posts
+		if msg.before.IsZero() {
+			m.Posts = msg.posts
+		} else {
+			m.loadingMore = false
+			// A reload replaced the posts while this page was loading
+			if msg.before != m.nextCursor {
+				return m, nil
+			}
+			m.Posts = common.AppendPage(m.Posts, msg.posts, func(p domain.GlobalTimelinePost) string { return p.NoteId })
+		}
+		m.nextCursor = msg.next
+		m.hasMore = msg.more
 		if m.Selected >= len(m.Posts) {
 			m.Selected = max(0, len(m.Posts)-1)
 		}
 		m.Offset = m.Selected
-
-		// Only start ticker if active AND no ticker already running
-		// This prevents multiple ticker chains from UpdateNoteList reloads
-		if m.isActive && !m.tickerRunning {
-			m.tickerRunning = true
-			return m, tickRefresh()
-		}
 		return m, nil
 
 	case engagementInfoMsg:
@@ -152,6 +170,14 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		// While the reaction picker is open it consumes all keys
+		if m.reactionPicker.Active {
+			emoji := m.reactionPicker.HandleKey(msg.String())
+			if emoji != "" && len(m.Posts) > 0 && m.Selected < len(m.Posts) {
+				return m, reactCmd(m.Posts[m.Selected], emoji)
+			}
+			return m, nil
+		}
 		switch msg.String() {
 		case "up", "k":
 			if m.Selected > 0 {
@@ -167,6 +193,11 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			}
 			m.showingURL = false
 			m.showingEngagement = false
+			// Load the next page when the last loaded post is reached
+			if common.ShouldLoadMore(m.Selected, len(m.Posts), m.hasMore, m.loadingMore) {
+				m.loadingMore = true
+				return m, loadGlobalPosts(m.AccountId, m.nextCursor, common.HomeTimelinePostLimit)
+			}
 		case "o":
 			// Toggle between showing content and URL
 			// Prefer ObjectURL (web UI link) over ObjectURI (ActivityPub id/JSON)
@@ -180,6 +211,15 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					m.showingURL = !m.showingURL
 				}
 			}
+		case "#":
+			// Open the tag view for the first hashtag of the selected post
+			if len(m.Posts) > 0 && m.Selected < len(m.Posts) {
+				if tags := util.ParseHashtags(m.Posts[m.Selected].Message); len(tags) > 0 {
+					return m, func() tea.Msg {
+						return common.ViewTagMsg{Tag: tags[0]}
+					}
+				}
+			}
 		case "r":
 			// Reply to selected post
 			if len(m.Posts) > 0 && m.Selected < len(m.Posts) {
@@ -297,6 +337,22 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					}
 				}
 			}
+		case "e":
+			// Open the emoji reaction picker for the selected post
+			if len(m.Posts) > 0 && m.Selected < len(m.Posts) {
+				m.showingURL = false
+				m.showingEngagement = false
+				m.reactionPicker.Open()
+			}
+		case "v":
+			// Show or re-collapse a post hidden behind a keyword filter warning
+			if len(m.Posts) > 0 && m.Selected < len(m.Posts) && m.Posts[m.Selected].FilterWarning != "" {
+				if m.revealed == nil {
+					m.revealed = map[string]bool{}
+				}
+				id := m.Posts[m.Selected].NoteId
+				m.revealed[id] = !m.revealed[id]
+			}
 		case "i":
 			// Toggle engagement info display
 			if len(m.Posts) > 0 && m.Selected < len(m.Posts) {
@@ -456,8 +512,12 @@ func (m Model) View() string {
 						processedContent = util.NormalizeEmojis(processedContent)
 					}
 					processedContent = util.LinkifyRawURLsTerminal(processedContent)
+					processedContent = util.CustomEmojiToTerminal(processedContent, post.Emojis, "https://"+m.LocalDomain)
 					highlightedContent := util.HighlightHashtagsTerminal(processedContent)
 					highlightedContent = util.HighlightMentionsTerminal(highlightedContent, m.LocalDomain)
+					if post.FilterWarning != "" && !m.revealed[post.NoteId] {
+						highlightedContent = common.FilterWarning(post.FilterWarning)
+					}
 
 					contentFormatted := selectedBg.Render(selectedContentStyle.Render(highlightedContent))
 					s.WriteString(timeFormatted + "\n")
@@ -469,6 +529,12 @@ func (m Model) View() string {
 					}
 					s.WriteString(authorFormatted + "\n")
 					s.WriteString(contentFormatted)
+					if reactions := common.FormatReactionCounts(post.Reactions); reactions != "" {
+						s.WriteString("\n" + reactions)
+					}
+					if m.reactionPicker.Active {
+						s.WriteString("\n" + m.reactionPicker.View())
+					}
 				}
 			} else {
 				unselectedStyle := lipgloss.NewStyle().
@@ -483,8 +549,12 @@ func (m Model) View() string {
 					// Normalize emojis for remote posts to fix terminal width calculation issues
 					processedContent = util.NormalizeEmojis(processedContent)
 				}
+				processedContent = util.CustomEmojiToTerminal(processedContent, post.Emojis, "https://"+m.LocalDomain)
 				highlightedContent := util.HighlightHashtagsTerminal(processedContent)
 				highlightedContent = util.HighlightMentionsTerminal(highlightedContent, m.LocalDomain)
+				if post.FilterWarning != "" && !m.revealed[post.NoteId] {
+					highlightedContent = common.FilterWarning(post.FilterWarning)
+				}
 
 				var authorFormatted string
 				if !post.IsRemote {
@@ -505,6 +575,9 @@ func (m Model) View() string {
 				}
 				s.WriteString(authorFormatted + "\n")
 				s.WriteString(contentFormatted)
+				if reactions := common.FormatReactionCounts(post.Reactions); reactions != "" {
+					s.WriteString("\n" + reactions)
+				}
 			}
 
 			s.WriteString("\n\n")
@@ -514,9 +587,12 @@ func (m Model) View() string {
 	return s.String()
 }
 
-// postsLoadedMsg is sent when posts are loaded
+// postsLoadedMsg is sent when a page of posts is loaded
 type postsLoadedMsg struct {
-	posts []domain.GlobalTimelinePost
+	before domain.Cursor // Zero for the first page, which replaces the loaded posts
+	posts  []domain.GlobalTimelinePost
+	next   domain.Cursor // Position of the last post read, before keyword filtering
+	more   bool          // The page was full, older posts may exist
 }
 
 // engagementInfoMsg is sent when engagement info is loaded
@@ -525,20 +601,71 @@ type engagementInfoMsg struct {
 	boosters []string
 }
 
-// loadGlobalPosts loads the global timeline
-func loadGlobalPosts() tea.Cmd {
+// reload reloads the timeline from the newest post, keeping as many posts as are loaded
+func (m Model) reload() tea.Cmd {
+	return loadGlobalPosts(m.AccountId, domain.Cursor{}, common.ReloadLimit(len(m.Posts), common.HomeTimelinePostLimit))
+}
+
+// loadGlobalPosts loads a page of the global timeline and applies the account's keyword filters
+func loadGlobalPosts(accountId uuid.UUID, before domain.Cursor, limit int) tea.Cmd {
 	return func() tea.Msg {
 		database := db.GetDB()
-		err, posts := database.ReadGlobalTimelinePosts(common.HomeTimelinePostLimit, 0)
+		err, posts := database.ReadGlobalTimelinePosts(before, limit)
 		if err != nil {
 			log.Printf("Failed to load global timeline: %v", err)
-			return postsLoadedMsg{posts: []domain.GlobalTimelinePost{}}
+			return postsLoadedMsg{before: before, posts: []domain.GlobalTimelinePost{}}
+		}
+		if posts == nil || len(*posts) == 0 {
+			return postsLoadedMsg{before: before, posts: []domain.GlobalTimelinePost{}}
 		}
-		if posts == nil {
-			return postsLoadedMsg{posts: []domain.GlobalTimelinePost{}}
+		msg := postsLoadedMsg{
+			before: before,
+			posts:  *posts,
+			next:   (*posts)[len(*posts)-1].Cursor(),
+			more:   len(*posts) >= limit,
 		}
 
-		return postsLoadedMsg{posts: *posts}
+		err, filters := database.ReadFiltersByAccountId(accountId)
+		if err != nil {
+			log.Printf("Failed to load keyword filters: %v", err)
+			return msg
+		}
+		now := time.Now()
+		kept := make([]domain.GlobalTimelinePost, 0, len(*posts))
+		for _, post := range *posts {
+			if f, ok := domain.MatchFilters(*filters, domain.FilterContextGlobal, post.Message, now); ok {
+				if f.Action == domain.FilterActionHide {
+					continue
+				}
+				post.FilterWarning = f.Phrase
+			}
+			kept = append(kept, post)
+		}
+		msg.posts = kept
+		return msg
+	}
+}
+
+// reactCmd emits a ReactNoteMsg for the given post
+func reactCmd(post domain.GlobalTimelinePost, emoji string) tea.Cmd {
+	noteURI := post.ObjectURI
+	var noteID uuid.UUID
+	// For local posts without ObjectURI, use local: prefix with NoteId
+	if !post.IsRemote && post.NoteId != "" {
+		if id, err := uuid.Parse(post.NoteId); err == nil {
+			noteID = id
+			if noteURI == "" {
+				noteURI = "local:" + post.NoteId
+			}
+		}
+	}
+	return func() tea.Msg {
+		return common.ReactNoteMsg{
+			NoteURI: noteURI,
+			NoteID:  noteID,
+			IsLocal: !post.IsRemote,
+			Emoji:   emoji,
+		}
 	}
 }
 
@@ -8,8 +8,10 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/deemkeen/stegodon/actions"
 	"github.com/deemkeen/stegodon/db"
 	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
 	"github.com/deemkeen/stegodon/ui/common"
 	"github.com/deemkeen/stegodon/util"
 	"github.com/google/uuid"
@@ -60,13 +62,21 @@ type Model struct {
 	Selected           int // Currently selected post index
 	Width              int
 	Height             int
-	isActive           bool     // Track if this view is currently visible (prevents ticker leaks)
-	tickerRunning      bool     // Track if refresh ticker is already running (prevents multiple ticker chains)
-	showingURL         bool     // Track if URL is displayed instead of content for selected post
-	showingEngagement  bool     // Track if engagement info (likes/boosts) is displayed
-	engagementLikers   []string // List of users who liked the selected post
-	engagementBoosters []string // List of users who boosted the selected post
-	LocalDomain        string   // Cached local domain for mention highlighting
+	isActive           bool            // Track if this view is currently visible (only visible timelines reload)
+	reloadPending      bool            // A reload is scheduled, further events wait for it
+	follows            *events.Follows // Accounts whose events reload the timeline, nil until loaded
+	nextCursor         domain.Cursor   // Position of the last loaded post, where the next page starts
+	hasMore            bool            // The last page was full, older posts may exist
+	loadingMore        bool            // A next page is being loaded
+	showingURL         bool            // Track if URL is displayed instead of content for selected post
+	showingEngagement  bool            // Track if engagement info (likes/boosts) is displayed
+	engagementLikers   []string        // List of users who liked the selected post
+	engagementBoosters []string        // List of users who boosted the selected post
+	LocalDomain        string          // Cached local domain for mention highlighting
+	ListId             uuid.UUID       // When set, the model shows this list's timeline instead of home
+	ListName           string
+	reactionPicker     common.ReactionPicker
+	revealed           map[uuid.UUID]bool // Posts collapsed by a keyword filter that the user chose to show
 }
 
 func InitialModel(accountId uuid.UUID, width, height int, localDomain string) Model {
@@ -80,22 +90,35 @@ func InitialModel(accountId uuid.UUID, width, height int, localDomain string) Mo
 		isActive:    false, // Start inactive, will be activated when view is shown
 		showingURL:  false, // Start in content mode
 		LocalDomain: localDomain,
+		revealed:    map[uuid.UUID]bool{},
 	}
 }
 
+// SetList points the model at a list timeline; posts are reloaded on the next ActivateViewMsg
+func (m *Model) SetList(list domain.List) {
+	if m.ListId != list.Id {
+		m.Posts = []domain.HomePost{}
+	}
+	m.ListId = list.Id
+	m.ListName = list.Name
+}
+
 func (m Model) Init() tea.Cmd {
 	// Don't start any commands here - model starts inactive
-	// ActivateViewMsg handler will load data and start ticker when view becomes active
+	// ActivateViewMsg handler will load data when view becomes active
 	return nil
 }
 
-// refreshTickMsg is sent periodically to refresh the timeline
-type refreshTickMsg struct{}
+// refreshTickMsg is sent shortly after an event that changes the timeline
+// listId identifies the timeline the tick belongs to (uuid.Nil for home)
+type refreshTickMsg struct {
+	listId uuid.UUID
+}
 
-// tickRefresh returns a command that sends refreshTickMsg every TimelineRefreshSeconds
-func tickRefresh() tea.Cmd {
-	return tea.Tick(common.TimelineRefreshSeconds*time.Second, func(t time.Time) tea.Msg {
-		return refreshTickMsg{}
+// tickRefresh returns a command that sends refreshTickMsg after events.ReloadDelay
+func tickRefresh(listId uuid.UUID) tea.Cmd {
+	return tea.Tick(events.ReloadDelay, func(t time.Time) tea.Msg {
+		return refreshTickMsg{listId: listId}
 	})
 }
 
@@ -104,53 +127,72 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	case common.DeactivateViewMsg:
 		// View is becoming inactive (user navigated away)
 		m.isActive = false
-		m.tickerRunning = false // Stop ticker chain
 		return m, nil
 
 	case common.ActivateViewMsg:
 		// View is becoming active (user navigated here)
 		m.isActive = true
-		m.tickerRunning = false // Reset ticker state, will be started when data loads
 		// Reset scroll position to top when switching to this view
 		m.Selected = 0
 		m.Offset = 0
 		m.showingURL = false
 		m.showingEngagement = false
-		// Load data first, tick will be scheduled when data arrives
-		return m, loadHomePosts(m.AccountId)
+		m.reactionPicker.Close()
+		return m, loadHomePosts(m.AccountId, m.ListId, domain.Cursor{}, common.HomeTimelinePostLimit)
 
 	case common.SessionState:
 		// Handle UpdateNoteList to refresh when notes are created/updated
 		// Always reload data when notes change, regardless of active state
-		// The isActive flag only controls the ticker chain, not one-time reloads
 		if msg == common.UpdateNoteList {
-			return m, loadHomePosts(m.AccountId)
+			return m, m.reload()
+		}
+		return m, nil
+
+	case common.EventMsg:
+		// Reload the visible timeline once per burst of events; hidden ones reload on activation
+		if m.isActive && !m.reloadPending && events.ChangesHomeTimeline(msg.Event, m.AccountId, m.follows) {
+			m.reloadPending = true
+			return m, tickRefresh(m.ListId)
 		}
 		return m, nil
 
 	case refreshTickMsg:
-		// Only schedule next refresh if view is still active
+		// Ticks of another timeline (home vs. list) are not ours
+		if msg.listId != m.ListId {
+			return m, nil
+		}
+		m.reloadPending = false
 		if m.isActive {
-			return m, loadHomePosts(m.AccountId)
+			return m, m.reload()
 		}
-		// View is inactive, stop the ticker chain
 		return m, nil
 
 	case postsLoadedMsg:
-		m.Posts = msg.posts
+		// Ignore posts loaded for another timeline (home vs. list, or a previous list)
+		if msg.listId != m.ListId {
+			return m, nil
+		}
+		if msg.follows != nil {
+			m.follows = msg.follows
+		}
+		if msg.before.IsZero() {
+			m.Posts = msg.posts
+		} else {
+			m.loadingMore = false
+			// A reload replaced the posts while this page was loading
+			if msg.before != m.nextCursor {
+				return m, nil
+			}
+			m.Posts = common.AppendPage(m.Posts, msg.posts, func(p domain.HomePost) uuid.UUID { return p.ID })
+		}
+		m.nextCursor = msg.next
+		m.hasMore = msg.more
 		// Keep selection within bounds after reload
 		if m.Selected >= len(m.Posts) {
 			m.Selected = max(0, len(m.Posts)-1)
 		}
 		// Keep Offset in sync
 		m.Offset = m.Selected
-
-		// Schedule next tick AFTER data loads (only if still active AND no ticker running)
-		// This prevents multiple ticker chains from UpdateNoteList reloads
-		if m.isActive && !m.tickerRunning {
-			m.tickerRunning = true
-			return m, tickRefresh()
-		}
 		return m, nil
 
 	case engagementInfoMsg:
@@ -160,6 +202,14 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		// While the reaction picker is open it consumes all keys
+		if m.reactionPicker.Active {
+			emoji := m.reactionPicker.HandleKey(msg.String())
+			if emoji != "" && len(m.Posts) > 0 && m.Selected < len(m.Posts) {
+				return m, reactCmd(m.Posts[m.Selected], emoji)
+			}
+			return m, nil
+		}
 		switch msg.String() {
 		case "up", "k":
 			if m.Selected > 0 {
@@ -175,6 +225,11 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			}
 			m.showingURL = false
 			m.showingEngagement = false
+			// Load the next page when the last loaded post is reached
+			if common.ShouldLoadMore(m.Selected, len(m.Posts), m.hasMore, m.loadingMore) {
+				m.loadingMore = true
+				return m, loadHomePosts(m.AccountId, m.ListId, m.nextCursor, common.HomeTimelinePostLimit)
+			}
 		case "o":
 			// Toggle between showing content and URL (only for posts with valid HTTP/HTTPS URLs)
 			// Prefer ObjectURL (web UI link) over ObjectURI (ActivityPub id/JSON)
@@ -188,6 +243,15 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					m.showingURL = !m.showingURL
 				}
 			}
+		case "#":
+			// Open the tag view for the followed hashtag or the first hashtag of the selected post
+			if len(m.Posts) > 0 && m.Selected < len(m.Posts) {
+				if tag := postHashtag(m.Posts[m.Selected]); tag != "" {
+					return m, func() tea.Msg {
+						return common.ViewTagMsg{Tag: tag}
+					}
+				}
+			}
 		case "r":
 			// Reply to selected post
 			if len(m.Posts) > 0 && m.Selected < len(m.Posts) {
@@ -292,6 +356,22 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					}
 				}
 			}
+		case "e":
+			// Open the emoji reaction picker for the selected post
+			if len(m.Posts) > 0 && m.Selected < len(m.Posts) {
+				m.showingURL = false
+				m.showingEngagement = false
+				m.reactionPicker.Open()
+			}
+		case "v":
+			// Show or re-collapse a post hidden behind a keyword filter warning
+			if len(m.Posts) > 0 && m.Selected < len(m.Posts) && m.Posts[m.Selected].FilterWarning != "" {
+				if m.revealed == nil {
+					m.revealed = map[uuid.UUID]bool{}
+				}
+				id := m.Posts[m.Selected].ID
+				m.revealed[id] = !m.revealed[id]
+			}
 		case "i":
 			// Toggle engagement info display (who liked/boosted)
 			if len(m.Posts) > 0 && m.Selected < len(m.Posts) {
@@ -313,10 +393,16 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 func (m Model) View() string {
 	var s strings.Builder
 
-	s.WriteString(common.CaptionStyle.Render(fmt.Sprintf("home (%d posts)", len(m.Posts))))
+	if m.ListId != uuid.Nil {
+		s.WriteString(common.CaptionStyle.Render(fmt.Sprintf("list: %s (%d posts)", m.ListName, len(m.Posts))))
+	} else {
+		s.WriteString(common.CaptionStyle.Render(fmt.Sprintf("home (%d posts)", len(m.Posts))))
+	}
 	s.WriteString("\n\n")
 
-	if len(m.Posts) == 0 {
+	if len(m.Posts) == 0 && m.ListId != uuid.Nil {
+		s.WriteString(emptyStyle.Render("No posts yet.\nAdd accounts to this list from the following view!"))
+	} else if len(m.Posts) == 0 {
 		s.WriteString(emptyStyle.Render("No posts yet.\nFollow some accounts to see their posts here!"))
 	} else {
 		// Calculate right panel width using layout helpers
@@ -354,10 +440,13 @@ func (m Model) View() string {
 				author = "@" + author
 			}
 
-			// Format boost indicator if this is a boosted post
+			// Format boost indicator if this is a boosted post, or the followed
+			// hashtag that brought it into the timeline
 			boostedByLine := ""
 			if post.BoostedBy != "" {
 				boostedByLine = fmt.Sprintf("🔁 %s boosted", post.BoostedBy)
+			} else if post.ViaHashtag != "" {
+				boostedByLine = fmt.Sprintf("via #%s", post.ViaHashtag)
 			}
 
 			// Apply selection highlighting
@@ -450,8 +539,12 @@ func (m Model) View() string {
 						processedContent = util.NormalizeEmojis(processedContent)
 					}
 					processedContent = util.LinkifyRawURLsTerminal(processedContent)
+					processedContent = util.CustomEmojiToTerminal(processedContent, post.Emojis, "https://"+m.LocalDomain)
 					highlightedContent := util.HighlightHashtagsTerminal(processedContent)
 					highlightedContent = util.HighlightMentionsTerminal(highlightedContent, m.LocalDomain)
+					if post.FilterWarning != "" && !m.revealed[post.ID] {
+						highlightedContent = common.FilterWarning(post.FilterWarning)
+					}
 
 					contentFormatted := selectedBg.Render(selectedContentStyle.Render(highlightedContent))
 					s.WriteString(timeFormatted + "\n")
@@ -461,6 +554,12 @@ func (m Model) View() string {
 					}
 					s.WriteString(authorFormatted + "\n")
 					s.WriteString(contentFormatted)
+					if reactions := common.FormatReactionCounts(post.Reactions); reactions != "" {
+						s.WriteString("\n" + reactions)
+					}
+					if m.reactionPicker.Active {
+						s.WriteString("\n" + m.reactionPicker.View())
+					}
 				}
 			} else {
 				unselectedStyle := lipgloss.NewStyle().
@@ -476,8 +575,12 @@ func (m Model) View() string {
 					// Normalize emojis for remote posts to fix terminal width calculation issues
 					processedContent = util.NormalizeEmojis(processedContent)
 				}
+				processedContent = util.CustomEmojiToTerminal(processedContent, post.Emojis, "https://"+m.LocalDomain)
 				highlightedContent := util.HighlightHashtagsTerminal(processedContent)
 				highlightedContent = util.HighlightMentionsTerminal(highlightedContent, m.LocalDomain)
+				if post.FilterWarning != "" && !m.revealed[post.ID] {
+					highlightedContent = common.FilterWarning(post.FilterWarning)
+				}
 
 				// Use different author color for local vs remote
 				var authorFormatted string
@@ -497,6 +600,9 @@ func (m Model) View() string {
 				}
 				s.WriteString(authorFormatted + "\n")
 				s.WriteString(contentFormatted)
+				if reactions := common.FormatReactionCounts(post.Reactions); reactions != "" {
+					s.WriteString("\n" + reactions)
+				}
 			}
 
 			s.WriteString("\n\n")
@@ -506,9 +612,25 @@ func (m Model) View() string {
 	return s.String()
 }
 
-// postsLoadedMsg is sent when posts are loaded
+// postHashtag returns the hashtag a post was shown for, or else its first hashtag
+func postHashtag(post domain.HomePost) string {
+	if post.ViaHashtag != "" {
+		return post.ViaHashtag
+	}
+	if tags := util.ParseHashtags(post.Content); len(tags) > 0 {
+		return tags[0]
+	}
+	return ""
+}
+
+// postsLoadedMsg is sent when a page of posts is loaded
 type postsLoadedMsg struct {
-	posts []domain.HomePost
+	listId  uuid.UUID     // uuid.Nil for the home timeline
+	before  domain.Cursor // Zero for the first page, which replaces the loaded posts
+	posts   []domain.HomePost
+	next    domain.Cursor   // Position of the last post read, before keyword filtering
+	more    bool            // The page was full, older posts may exist
+	follows *events.Follows // Read with the first page, nil otherwise
 }
 
 // engagementInfoMsg is sent when engagement info is loaded
@@ -517,21 +639,77 @@ type engagementInfoMsg struct {
 	boosters []string
 }
 
-// loadHomePosts loads the unified home timeline
-func loadHomePosts(accountId uuid.UUID) tea.Cmd {
+// reload reloads the timeline from the newest post, keeping as many posts as are loaded
+func (m Model) reload() tea.Cmd {
+	return loadHomePosts(m.AccountId, m.ListId, domain.Cursor{}, common.ReloadLimit(len(m.Posts), common.HomeTimelinePostLimit))
+}
+
+// loadHomePosts loads a page of the unified home timeline, or of a list timeline when listId is set.
+// The first page comes with the account's follows, which decide what events reload the timeline.
+func loadHomePosts(accountId uuid.UUID, listId uuid.UUID, before domain.Cursor, limit int) tea.Cmd {
 	return func() tea.Msg {
 		database := db.GetDB()
-		err, posts := database.ReadHomeTimelinePosts(accountId, common.HomeTimelinePostLimit)
-		if err != nil {
-			log.Printf("Failed to load home timeline: %v", err)
-			return postsLoadedMsg{posts: []domain.HomePost{}}
+		msg := readHomePosts(database, accountId, listId, before, limit)
+		if before.IsZero() {
+			follows, err := actions.ReadFollows(database, accountId)
+			if err != nil {
+				log.Printf("Failed to load follows: %v", err)
+			}
+			msg.follows = follows
 		}
+		return msg
+	}
+}
 
-		if posts == nil {
-			return postsLoadedMsg{posts: []domain.HomePost{}}
-		}
+// readHomePosts reads a page of the home or list timeline with the keyword filters applied
+func readHomePosts(database db.Store, accountId uuid.UUID, listId uuid.UUID, before domain.Cursor, limit int) postsLoadedMsg {
+	var err error
+	var posts *[]domain.HomePost
+	if listId != uuid.Nil {
+		err, posts = database.ReadListTimelinePosts(accountId, listId, before, limit)
+	} else {
+		err, posts = database.ReadHomeTimelinePosts(accountId, before, limit)
+	}
+	if err != nil {
+		log.Printf("Failed to load home timeline: %v", err)
+		return postsLoadedMsg{listId: listId, before: before, posts: []domain.HomePost{}}
+	}
+
+	if posts == nil || len(*posts) == 0 {
+		return postsLoadedMsg{listId: listId, before: before, posts: []domain.HomePost{}}
+	}
+	msg := postsLoadedMsg{
+		listId: listId,
+		before: before,
+		posts:  *posts,
+		next:   (*posts)[len(*posts)-1].Cursor(),
+		more:   len(*posts) >= limit,
+	}
 
-		return postsLoadedMsg{posts: *posts}
+	// Keyword filters use the home context for list timelines too
+	err, filters := database.ReadFiltersByAccountId(accountId)
+	if err != nil {
+		log.Printf("Failed to load keyword filters: %v", err)
+		return msg
+	}
+	msg.posts = domain.FilterHomePosts(*posts, *filters, domain.FilterContextHome, time.Now())
+	return msg
+}
+
+// reactCmd emits a ReactNoteMsg for the given post
+func reactCmd(post domain.HomePost, emoji string) tea.Cmd {
+	noteURI := post.ObjectURI
+	// For local posts without ObjectURI, use local: prefix
+	if noteURI == "" && post.IsLocal && post.NoteID != uuid.Nil {
+		noteURI = "local:" + post.NoteID.String()
+	}
+	return func() tea.Msg {
+		return common.ReactNoteMsg{
+			NoteURI: noteURI,
+			NoteID:  post.NoteID,
+			IsLocal: post.IsLocal,
+			Emoji:   emoji,
+		}
 	}
 }
 
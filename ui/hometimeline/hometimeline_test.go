@@ -8,6 +8,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
 	"github.com/deemkeen/stegodon/ui/common"
 	"github.com/google/uuid"
 )
@@ -100,8 +101,64 @@ func TestUpdate_PostsLoaded(t *testing.T) {
 	if m.Posts[0].Author != "testuser" {
 		t.Errorf("Expected first author 'testuser', got '%s'", m.Posts[0].Author)
 	}
-	if cmd == nil {
-		t.Error("Expected tickRefresh command when active")
+	if cmd != nil {
+		t.Error("Expected no polling tick after load, reloads are triggered by events")
+	}
+}
+
+func TestUpdate_EventReloadsActiveTimeline(t *testing.T) {
+	accountId := uuid.New()
+	m := InitialModel(accountId, 120, 40, "")
+	created := common.EventMsg{Event: events.Event{Kind: events.ActivityReceived, ActivityType: "Create"}}
+
+	if _, cmd := m.Update(created); cmd != nil {
+		t.Error("Expected an inactive timeline to ignore events")
+	}
+
+	m.isActive = true
+	m, cmd := m.Update(created)
+	if cmd == nil || !m.reloadPending {
+		t.Fatal("Expected a reload to be scheduled")
+	}
+	// A burst of events causes a single reload
+	if _, cmd := m.Update(created); cmd != nil {
+		t.Error("Expected further events to wait for the pending reload")
+	}
+
+	m, cmd = m.Update(refreshTickMsg{})
+	if cmd == nil || m.reloadPending {
+		t.Error("Expected the tick to reload the posts")
+	}
+
+	// Unrelated events and follows accepted for other accounts are ignored
+	for _, e := range []events.Event{
+		{Kind: events.ActivityReceived, ActivityType: "Follow"},
+		{Kind: events.NotificationCreated, AccountId: accountId},
+		{Kind: events.FollowAccepted, AccountId: uuid.New()},
+	} {
+		if _, cmd := m.Update(common.EventMsg{Event: e}); cmd != nil {
+			t.Errorf("Expected %v to be ignored", e.Kind)
+		}
+	}
+	if _, cmd := m.Update(common.EventMsg{Event: events.Event{Kind: events.FollowAccepted, AccountId: accountId}}); cmd == nil {
+		t.Error("Expected an accepted follow to reload the timeline")
+	}
+}
+
+func TestUpdate_EventsOfUnfollowedAccountsAreIgnored(t *testing.T) {
+	accountId := uuid.New()
+	followed := uuid.New()
+	m := InitialModel(accountId, 120, 40, "")
+	m.isActive = true
+	m, _ = m.Update(postsLoadedMsg{follows: &events.Follows{Accounts: map[uuid.UUID]bool{followed: true}}})
+
+	stranger := common.EventMsg{Event: events.Event{Kind: events.ActivityReceived, ActivityType: "Create", ActorId: uuid.New()}}
+	if _, cmd := m.Update(stranger); cmd != nil {
+		t.Error("Expected an activity of an unfollowed account to be ignored")
+	}
+	friend := common.EventMsg{Event: events.Event{Kind: events.ActivityReceived, ActivityType: "Create", ActorId: followed}}
+	if _, cmd := m.Update(friend); cmd == nil {
+		t.Error("Expected an activity of a followed account to reload the timeline")
 	}
 }
 
@@ -138,7 +195,41 @@ func TestUpdate_RefreshTick_Inactive(t *testing.T) {
 	_, cmd := m.Update(refreshTickMsg{})
 
 	if cmd != nil {
-		t.Error("Expected no command when inactive - ticker should stop")
+		t.Error("Expected no command when inactive")
+	}
+}
+
+func TestUpdate_ListTimelineIgnoresOtherTimelines(t *testing.T) {
+	m := InitialModel(uuid.New(), 120, 40, "")
+	m.SetList(domain.List{Id: uuid.New(), Name: "friends"})
+	m.isActive = true
+
+	posts := []domain.HomePost{{NoteID: uuid.New(), Author: "test", Content: "Home post"}}
+	m, cmd := m.Update(postsLoadedMsg{posts: posts})
+	if len(m.Posts) != 0 || cmd != nil {
+		t.Errorf("Expected home posts to be ignored by the list timeline, got %d posts", len(m.Posts))
+	}
+
+	if _, cmd := m.Update(refreshTickMsg{}); cmd != nil {
+		t.Error("Expected home refresh tick to be ignored by the list timeline")
+	}
+
+	m, _ = m.Update(postsLoadedMsg{listId: m.ListId, posts: posts})
+	if len(m.Posts) != 1 {
+		t.Errorf("Expected list posts to be loaded, got %d", len(m.Posts))
+	}
+}
+
+func TestView_ListTimelineCaption(t *testing.T) {
+	m := InitialModel(uuid.New(), 120, 40, "")
+	m.SetList(domain.List{Id: uuid.New(), Name: "friends"})
+
+	view := m.View()
+	if !strings.Contains(view, "list: friends (0 posts)") {
+		t.Error("Expected list caption in view")
+	}
+	if !strings.Contains(view, "following view") {
+		t.Error("Expected list empty state hint in view")
 	}
 }
 
@@ -1017,3 +1108,104 @@ func TestView_EngagementInfo_NotShowing(t *testing.T) {
 		t.Error("Expected normal content to be displayed")
 	}
 }
+
+func TestUpdate_HashKeyOpensTagView(t *testing.T) {
+	m := InitialModel(uuid.New(), 120, 40, "")
+	m.Posts = []domain.HomePost{
+		{NoteID: uuid.New(), Author: "alice", Content: "Hello #GoLang and #rust", Time: time.Now()},
+		{NoteID: uuid.New(), Author: "bob", Content: "No tags", Time: time.Now()},
+		{NoteID: uuid.New(), Author: "carol", Content: "Hi #rust #golang", Time: time.Now(), ViaHashtag: "golang"},
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'#'}})
+	if cmd == nil {
+		t.Fatal("Expected a command for post with hashtags")
+	}
+	if msg, ok := cmd().(common.ViewTagMsg); !ok || msg.Tag != "golang" {
+		t.Errorf("Expected ViewTagMsg for golang, got %v", msg)
+	}
+
+	m.Selected = 1
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'#'}}); cmd != nil {
+		t.Error("Expected no command for post without hashtags")
+	}
+
+	m.Selected = 2
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'#'}})
+	if msg, ok := cmd().(common.ViewTagMsg); !ok || msg.Tag != "golang" {
+		t.Errorf("Expected ViewTagMsg for the followed tag, got %v", msg)
+	}
+}
+
+func TestView_ViaHashtag(t *testing.T) {
+	m := InitialModel(uuid.New(), 120, 40, "")
+	m.Posts = []domain.HomePost{
+		{NoteID: uuid.New(), Author: "alice", Content: "Hello #golang", Time: time.Now(), ViaHashtag: "golang"},
+	}
+
+	if !strings.Contains(m.View(), "via #golang") {
+		t.Error("Expected 'via #golang' label in view")
+	}
+}
+
+func TestView_FilterWarning(t *testing.T) {
+	m := InitialModel(uuid.New(), 120, 40, "")
+	id := uuid.New()
+	m.Posts = []domain.HomePost{
+		{ID: id, Author: "alice", Content: "The finale twist", Time: time.Now(), FilterWarning: "finale"},
+	}
+
+	view := m.View()
+	if strings.Contains(view, "twist") || !strings.Contains(view, "filtered: finale") {
+		t.Errorf("Expected collapsed post, got: %s", view)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	if !strings.Contains(m.View(), "twist") {
+		t.Error("Expected post content after pressing v")
+	}
+
+	// The reveal survives a reload of the same post
+	m, _ = m.Update(postsLoadedMsg{posts: []domain.HomePost{m.Posts[0]}})
+	if !strings.Contains(m.View(), "twist") {
+		t.Error("Expected revealed post to stay revealed after reload")
+	}
+}
+
+func TestUpdate_LoadMoreAppendsPage(t *testing.T) {
+	m := InitialModel(uuid.New(), 120, 40, "")
+	m.isActive = true
+
+	now := time.Now()
+	first := []domain.HomePost{
+		{ID: uuid.New(), Author: "a", Content: "newest", Time: now},
+		{ID: uuid.New(), Author: "b", Content: "older", Time: now.Add(-time.Minute)},
+	}
+	next := first[1].Cursor()
+	m, _ = m.Update(postsLoadedMsg{posts: first, next: next, more: true})
+
+	// Reaching the last post requests the next page once
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if cmd == nil || !m.loadingMore {
+		t.Fatal("Expected a load-more command at the last post")
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyDown}); cmd != nil {
+		t.Error("Expected no second load while a page is loading")
+	}
+
+	// The page is appended, dropping posts that are already loaded
+	page := []domain.HomePost{first[1], {ID: uuid.New(), Author: "c", Content: "oldest", Time: now.Add(-time.Hour)}}
+	m, _ = m.Update(postsLoadedMsg{before: next, posts: page, next: page[1].Cursor(), more: false})
+	if len(m.Posts) != 3 || m.Posts[2].Content != "oldest" {
+		t.Fatalf("Expected the older page to be appended, got %d posts", len(m.Posts))
+	}
+	if m.loadingMore || m.hasMore {
+		t.Error("Expected loading to finish with no more pages")
+	}
+
+	// A page for a cursor that is no longer current is ignored
+	m, _ = m.Update(postsLoadedMsg{before: next, posts: page})
+	if len(m.Posts) != 3 {
+		t.Errorf("Expected a stale page to be ignored, got %d posts", len(m.Posts))
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/deemkeen/stegodon/activitypub"
 	"github.com/deemkeen/stegodon/db"
 	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
 	"github.com/deemkeen/stegodon/ui/common"
 	"github.com/deemkeen/stegodon/util"
 	"github.com/google/uuid"
@@ -59,13 +60,17 @@ type Model struct {
 	Width            int
 	Height           int
 	userId           uuid.UUID
-	confirmingDelete bool      // True when showing delete confirmation
-	deleteTargetId   uuid.UUID // ID of note pending deletion
-	LocalDomain      string    // Cached local domain for mention highlighting
+	confirmingDelete bool          // True when showing delete confirmation
+	deleteTargetId   uuid.UUID     // ID of note pending deletion
+	LocalDomain      string        // Cached local domain for mention highlighting
+	reloadPending    bool          // A reload is scheduled, further events wait for it
+	nextCursor       domain.Cursor // Position of the last loaded note, where the next page starts
+	hasMore          bool          // The last page was full, older notes may exist
+	loadingMore      bool          // A next page is being loaded
 }
 
 func (m Model) Init() tea.Cmd {
-	return loadNotes(m.userId)
+	return loadNotes(m.userId, domain.Cursor{}, common.HomeTimelinePostLimit)
 }
 
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
@@ -77,17 +82,42 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.Offset = 0
 		m.confirmingDelete = false
 		m.deleteTargetId = uuid.Nil
-		return m, loadNotes(m.userId)
+		return m, loadNotes(m.userId, domain.Cursor{}, common.HomeTimelinePostLimit)
 
 	case common.SessionState:
 		// Handle UpdateNoteList to refresh when notes are created/updated/liked
 		if msg == common.UpdateNoteList {
-			return m, loadNotes(m.userId)
+			return m, m.reload()
 		}
 		return m, nil
 
+	case common.EventMsg:
+		// Own notes written elsewhere (CLI, API, schedule) and interactions with them,
+		// which always come with a notification
+		e := msg.Event
+		if (e.Kind == events.NoteCreated || e.Kind == events.NotificationCreated) && e.AccountId == m.userId && !m.reloadPending {
+			m.reloadPending = true
+			return m, reloadNotesAfter(events.ReloadDelay)
+		}
+		return m, nil
+
+	case reloadNotesMsg:
+		m.reloadPending = false
+		return m, m.reload()
+
 	case notesLoadedMsg:
-		m.Notes = msg.notes
+		if msg.before.IsZero() {
+			m.Notes = msg.notes
+		} else {
+			m.loadingMore = false
+			// A reload replaced the notes while this page was loading
+			if msg.before != m.nextCursor {
+				return m, nil
+			}
+			m.Notes = common.AppendPage(m.Notes, msg.notes, func(n domain.Note) uuid.UUID { return n.Id })
+		}
+		m.nextCursor = msg.next
+		m.hasMore = msg.more
 		// Restore selection after reload, but make sure it's within bounds
 		if m.Selected >= len(m.Notes) {
 			m.Selected = max(0, len(m.Notes)-1)
@@ -126,6 +156,11 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				m.Selected++
 				m.Offset = m.Selected // Keep selected at top
 			}
+			// Load the next page when the last loaded note is reached
+			if common.ShouldLoadMore(m.Selected, len(m.Notes), m.hasMore, m.loadingMore) {
+				m.loadingMore = true
+				return m, loadNotes(m.userId, m.nextCursor, common.HomeTimelinePostLimit)
+			}
 		case "u":
 			// Edit selected note
 			if len(m.Notes) > 0 && m.Selected < len(m.Notes) {
@@ -134,6 +169,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					return common.EditNoteMsg{
 						NoteId:    selectedNote.Id,
 						Message:   selectedNote.Message,
+						Title:     selectedNote.Title,
 						CreatedAt: selectedNote.CreatedAt,
 					}
 				}
@@ -228,6 +264,10 @@ func (m Model) View() string {
 
 			// Unescape HTML entities, convert Markdown links and raw URLs to OSC 8 hyperlinks, and highlight hashtags and mentions
 			unescapedMessage := util.UnescapeHTML(note.Message)
+			if note.IsArticle() {
+				// Articles are listed by title with a short preview
+				unescapedMessage = "article: " + note.Title + "\n" + util.ArticleSummary(note.Message, util.ArticleSummaryLength)
+			}
 			messageWithLinks := util.MarkdownLinksToTerminal(unescapedMessage)
 			messageWithLinks = util.LinkifyRawURLsTerminal(messageWithLinks)
 			messageWithLinksAndHashtags := util.HighlightHashtagsTerminal(messageWithLinks)
@@ -275,26 +315,49 @@ func (m Model) View() string {
 	return s.String()
 }
 
-// notesLoadedMsg is sent when notes are loaded
+// notesLoadedMsg is sent when a page of notes is loaded
 type notesLoadedMsg struct {
-	notes []domain.Note
+	before domain.Cursor // Zero for the first page, which replaces the loaded notes
+	notes  []domain.Note
+	next   domain.Cursor // Position of the last note in the page
+	more   bool          // The page was full, older notes may exist
+}
+
+// reloadNotesMsg is sent shortly after an event that changes the user's notes
+type reloadNotesMsg struct{}
+
+// reloadNotesAfter returns a command that sends reloadNotesMsg after d
+func reloadNotesAfter(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return reloadNotesMsg{}
+	})
 }
 
-// loadNotes loads notes for the given user
-func loadNotes(userId uuid.UUID) tea.Cmd {
+// reload reloads the notes from the newest, keeping as many notes as are loaded
+func (m Model) reload() tea.Cmd {
+	return loadNotes(m.userId, domain.Cursor{}, common.ReloadLimit(len(m.Notes), common.HomeTimelinePostLimit))
+}
+
+// loadNotes loads a page of notes for the given user
+func loadNotes(userId uuid.UUID, before domain.Cursor, limit int) tea.Cmd {
 	return func() tea.Msg {
 		database := db.GetDB()
-		err, notes := database.ReadNotesByUserId(userId)
+		err, notes := database.ReadNotesPageByUserId(userId, before, limit)
 		if err != nil {
 			log.Printf("Failed to load notes: %v", err)
-			return notesLoadedMsg{notes: []domain.Note{}}
+			return notesLoadedMsg{before: before, notes: []domain.Note{}}
 		}
 
-		if notes == nil {
-			return notesLoadedMsg{notes: []domain.Note{}}
+		if notes == nil || len(*notes) == 0 {
+			return notesLoadedMsg{before: before, notes: []domain.Note{}}
 		}
 
-		return notesLoadedMsg{notes: *notes}
+		return notesLoadedMsg{
+			before: before,
+			notes:  *notes,
+			next:   (*notes)[len(*notes)-1].Cursor(),
+			more:   len(*notes) >= limit,
+		}
 	}
 }
 
@@ -12,16 +12,14 @@ import (
 	"github.com/deemkeen/stegodon/activitypub"
 	"github.com/deemkeen/stegodon/db"
 	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
 	"github.com/deemkeen/stegodon/ui/common"
 	"github.com/deemkeen/stegodon/util"
 	"github.com/deemkeen/stegodon/web"
 	"github.com/google/uuid"
 )
 
-const (
-	notificationsLimit = 50
-	refreshInterval    = 30 * time.Second
-)
+const notificationsLimit = 50
 
 type Model struct {
 	AccountId     uuid.UUID
@@ -31,15 +29,21 @@ type Model struct {
 	Width         int
 	Height        int
 	isActive      bool
-	tickerRunning bool // Track if refresh ticker is already running
+	reloadPending bool          // A reload is scheduled, further events wait for it
+	nextCursor    domain.Cursor // Position of the last loaded notification, where the next page starts
+	hasMore       bool          // The last page was full, older notifications may exist
+	loadingMore   bool          // A next page is being loaded
 	UnreadCount   int
 	Status        string
 	Error         string
 }
 
 type notificationsLoadedMsg struct {
+	before        domain.Cursor // Zero for the first page, which replaces the loaded notifications
 	notifications []domain.Notification
 	unreadCount   int
+	next          domain.Cursor // Position of the last notification read, before keyword filtering
+	more          bool          // The page was full, older notifications may exist
 }
 
 type refreshTickMsg struct{}
@@ -168,17 +172,28 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		// Notifications model is always active for badge updates
 		// Just load data when view becomes focused
 		m.isActive = true
-		return m, loadNotifications(m.AccountId)
+		return m, loadNotifications(m.AccountId, domain.Cursor{}, notificationsLimit)
 
 	case common.DeactivateViewMsg:
-		// Don't actually deactivate - keep refreshing for badge
+		// Don't actually deactivate - keep reloading on new notifications for the badge
 		// Just mark as not actively viewing
 		m.isActive = false
 		return m, nil
 
 	case notificationsLoadedMsg:
-		m.Notifications = msg.notifications
 		m.UnreadCount = msg.unreadCount
+		if msg.before.IsZero() {
+			m.Notifications = msg.notifications
+		} else {
+			m.loadingMore = false
+			// A reload replaced the notifications while this page was loading
+			if msg.before != m.nextCursor {
+				return m, nil
+			}
+			m.Notifications = common.AppendPage(m.Notifications, msg.notifications, func(n domain.Notification) uuid.UUID { return n.Id })
+		}
+		m.nextCursor = msg.next
+		m.hasMore = msg.more
 		// Keep selection within bounds
 		if m.Selected >= len(m.Notifications) {
 			m.Selected = len(m.Notifications) - 1
@@ -186,16 +201,20 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		if m.Selected < 0 {
 			m.Selected = 0
 		}
-		// Schedule next tick to keep badge updated (only if not already running)
-		if !m.tickerRunning {
-			m.tickerRunning = true
+		return m, nil
+
+	case common.EventMsg:
+		// New notifications for this account update the list and the header badge
+		if msg.Event.Kind == events.NotificationCreated && msg.Event.AccountId == m.AccountId && !m.reloadPending {
+			m.reloadPending = true
 			return m, tickRefresh()
 		}
 		return m, nil
 
 	case refreshTickMsg:
 		// Always refresh to keep badge count updated
-		return m, loadNotifications(m.AccountId)
+		m.reloadPending = false
+		return m, loadNotifications(m.AccountId, domain.Cursor{}, m.reloadLimit())
 
 	case clearStatusMsg:
 		m.Status = ""
@@ -244,6 +263,11 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					m.Offset = m.Selected - itemsPerPage + 1
 				}
 			}
+			// Load the next page when the last loaded notification is reached
+			if common.ShouldLoadMore(m.Selected, len(m.Notifications), m.hasMore, m.loadingMore) {
+				m.loadingMore = true
+				return m, loadNotifications(m.AccountId, m.nextCursor, notificationsLimit)
+			}
 		case "v":
 			// View notification source (the post/reply)
 			if m.Selected < len(m.Notifications) {
@@ -369,7 +393,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			// Delete notification (mark as read by removing it)
 			if m.Selected < len(m.Notifications) {
 				notif := m.Notifications[m.Selected]
-				return m, deleteNotification(notif.Id, m.AccountId)
+				return m, deleteNotification(notif.Id, m.AccountId, m.reloadLimit())
 			}
 		case "a":
 			// Delete all notifications (mark all as read by removing them)
@@ -435,7 +459,10 @@ func (m Model) View() string {
 		s.WriteString("\n")
 
 		// Show preview for like/reply/mention (indented)
-		if notif.NotePreview != "" && notif.NotificationType != domain.NotificationFollow {
+		if notif.FilterWarning != "" {
+			s.WriteString("  " + common.FilterWarning(notif.FilterWarning))
+			s.WriteString("\n")
+		} else if notif.NotePreview != "" && notif.NotificationType != domain.NotificationFollow {
 			preview := truncate(notif.NotePreview, 60)
 			s.WriteString("  " + common.ListBadgeStyle.Render("\""+preview+"\""))
 			s.WriteString("\n")
@@ -461,14 +488,24 @@ func (m Model) View() string {
 	return s.String()
 }
 
-// loadNotifications loads notifications for an account
-func loadNotifications(accountId uuid.UUID) tea.Cmd {
+// reloadLimit keeps as many notifications as are loaded when reloading from the newest
+func (m Model) reloadLimit() int {
+	return common.ReloadLimit(len(m.Notifications), notificationsLimit)
+}
+
+// loadNotifications loads a page of notifications for an account
+func loadNotifications(accountId uuid.UUID, before domain.Cursor, limit int) tea.Cmd {
 	return func() tea.Msg {
 		database := db.GetDB()
-		err, notifications := database.ReadNotificationsByAccountId(accountId, notificationsLimit)
+		err, notifications := database.ReadNotificationsByAccountId(accountId, before, limit)
 		if err != nil {
 			log.Printf("Failed to load notifications: %v", err)
-			return notificationsLoadedMsg{notifications: []domain.Notification{}, unreadCount: 0}
+			return notificationsLoadedMsg{before: before, notifications: []domain.Notification{}, unreadCount: 0}
+		}
+		msg := notificationsLoadedMsg{before: before, notifications: *notifications}
+		if len(*notifications) > 0 {
+			msg.next = (*notifications)[len(*notifications)-1].Cursor()
+			msg.more = len(*notifications) >= limit
 		}
 
 		// Get unread count
@@ -477,23 +514,54 @@ func loadNotifications(accountId uuid.UUID) tea.Cmd {
 			log.Printf("Failed to get unread count: %v", err)
 			unreadCount = 0
 		}
+		msg.unreadCount = unreadCount
 
-		return notificationsLoadedMsg{
-			notifications: *notifications,
-			unreadCount:   unreadCount,
+		err, filters := database.ReadFiltersByAccountId(accountId)
+		if err != nil {
+			log.Printf("Failed to load keyword filters: %v", err)
+			return msg
 		}
+		kept, hiddenUnread := filterNotifications(*notifications, *filters, time.Now())
+		msg.notifications = kept
+		msg.unreadCount = max(0, unreadCount-hiddenUnread)
+		return msg
 	}
 }
 
+// filterNotifications applies keyword filters (notifications context) to note previews.
+// It returns the notifications to show and how many unread ones were hidden.
+func filterNotifications(notifications []domain.Notification, filters []domain.Filter, now time.Time) ([]domain.Notification, int) {
+	if len(filters) == 0 {
+		return notifications, 0
+	}
+	kept := make([]domain.Notification, 0, len(notifications))
+	hiddenUnread := 0
+	for _, notif := range notifications {
+		if notif.NotePreview != "" && notif.NotificationType != domain.NotificationFollow {
+			if f, ok := domain.MatchFilters(filters, domain.FilterContextNotifications, notif.NotePreview, now); ok {
+				if f.Action == domain.FilterActionHide {
+					if !notif.Read {
+						hiddenUnread++
+					}
+					continue
+				}
+				notif.FilterWarning = f.Phrase
+			}
+		}
+		kept = append(kept, notif)
+	}
+	return kept, hiddenUnread
+}
+
 // deleteNotification deletes a single notification
-func deleteNotification(notificationId uuid.UUID, accountId uuid.UUID) tea.Cmd {
+func deleteNotification(notificationId uuid.UUID, accountId uuid.UUID, limit int) tea.Cmd {
 	return func() tea.Msg {
 		database := db.GetDB()
-		if err := database.DeleteNotification(notificationId); err != nil {
+		if err := database.DeleteNotification(notificationId, accountId); err != nil {
 			log.Printf("Failed to delete notification: %v", err)
 		}
 		// Reload notifications to update the view
-		return loadNotifications(accountId)()
+		return loadNotifications(accountId, domain.Cursor{}, limit)()
 	}
 }
 
@@ -505,13 +573,13 @@ func deleteAllNotifications(accountId uuid.UUID) tea.Cmd {
 			log.Printf("Failed to delete all notifications: %v", err)
 		}
 		// Reload notifications to update the view
-		return loadNotifications(accountId)()
+		return loadNotifications(accountId, domain.Cursor{}, notificationsLimit)()
 	}
 }
 
-// tickRefresh returns a command that triggers a refresh after a delay
+// tickRefresh returns a command that triggers a refresh after events.ReloadDelay
 func tickRefresh() tea.Cmd {
-	return tea.Tick(refreshInterval, func(t time.Time) tea.Msg {
+	return tea.Tick(events.ReloadDelay, func(t time.Time) tea.Msg {
 		return refreshTickMsg{}
 	})
 }
@@ -545,8 +613,5 @@ func formatTimeAgo(t time.Time) string {
 
 // truncate truncates a string to a maximum length
 func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	return s[:maxLen] + "..."
+	return util.TruncatePreview(s, maxLen)
 }
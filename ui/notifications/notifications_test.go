@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
 	"github.com/deemkeen/stegodon/ui/common"
 	"github.com/google/uuid"
 )
@@ -100,9 +101,33 @@ func TestUpdate_NotificationsLoadedMsg(t *testing.T) {
 	if newModel.UnreadCount != 1 {
 		t.Errorf("Expected unread count 1, got %d", newModel.UnreadCount)
 	}
-	// Should return ticker command to keep refreshing
+	// Reloads are triggered by events, not by polling
+	if cmd != nil {
+		t.Errorf("Expected no ticker cmd after loading notifications")
+	}
+}
+
+func TestUpdate_NotificationCreatedEvent(t *testing.T) {
+	accountId := uuid.New()
+	model := InitialModel(accountId, 100, 40)
+
+	// Notifications of other accounts are ignored
+	other := common.EventMsg{Event: events.Event{Kind: events.NotificationCreated, AccountId: uuid.New()}}
+	if _, cmd := model.Update(other); cmd != nil {
+		t.Error("Expected notifications of other accounts to be ignored")
+	}
+
+	// Own notifications reload even when the view is hidden, for the badge
+	own := common.EventMsg{Event: events.Event{Kind: events.NotificationCreated, AccountId: accountId}}
+	model, cmd := model.Update(own)
 	if cmd == nil {
-		t.Errorf("Expected ticker cmd after loading notifications")
+		t.Fatal("Expected a reload to be scheduled")
+	}
+	if _, cmd := model.Update(own); cmd != nil {
+		t.Error("Expected further events to wait for the pending reload")
+	}
+	if model, _ = model.Update(refreshTickMsg{}); model.reloadPending {
+		t.Error("Expected the tick to clear the pending reload")
 	}
 }
 
@@ -632,3 +657,32 @@ type testError struct {
 func (e *testError) Error() string {
 	return e.msg
 }
+
+func TestFilterNotifications(t *testing.T) {
+	notifications := []domain.Notification{
+		{Id: uuid.New(), NotificationType: domain.NotificationReply, NotePreview: "election results are in"},
+		{Id: uuid.New(), NotificationType: domain.NotificationMention, NotePreview: "Spoiler: he dies", Read: true},
+		{Id: uuid.New(), NotificationType: domain.NotificationFollow},
+		{Id: uuid.New(), NotificationType: domain.NotificationLike, NotePreview: "my cat"},
+	}
+	filters := []domain.Filter{
+		{Phrase: "election", Contexts: []string{domain.FilterContextNotifications}, Action: domain.FilterActionHide},
+		{Phrase: "spoiler", WholeWord: true, Contexts: []string{domain.FilterContextNotifications}, Action: domain.FilterActionWarn},
+		{Phrase: "cat", Contexts: []string{domain.FilterContextHome}, Action: domain.FilterActionHide},
+	}
+
+	kept, hiddenUnread := filterNotifications(notifications, filters, time.Now())
+	if len(kept) != 3 {
+		t.Fatalf("Expected 3 notifications, got %d", len(kept))
+	}
+	if hiddenUnread != 1 {
+		t.Errorf("Expected 1 hidden unread notification, got %d", hiddenUnread)
+	}
+	if kept[0].FilterWarning != "spoiler" {
+		t.Errorf("Expected mention to be collapsed, got warning %q", kept[0].FilterWarning)
+	}
+	// Filters of other contexts don't apply
+	if kept[2].FilterWarning != "" {
+		t.Errorf("Expected home filter to be ignored, got warning %q", kept[2].FilterWarning)
+	}
+}
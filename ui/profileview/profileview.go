@@ -1,6 +1,7 @@
 package profileview
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -8,6 +9,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/deemkeen/stegodon/activitypub"
 	"github.com/deemkeen/stegodon/db"
 	"github.com/deemkeen/stegodon/domain"
 	"github.com/deemkeen/stegodon/ui/common"
@@ -71,8 +73,11 @@ const (
 type Model struct {
 	AccountId      uuid.UUID
 	ProfileUser    *domain.Account
+	RemoteUser     *domain.RemoteAccount // Set when showing a remote account (ProfileUser then mirrors its profile)
 	Posts          []domain.Note
 	IsFollowing    bool
+	FollowPending  bool                // Remote follow request not accepted yet
+	ReturnView     common.SessionState // View to return to on Esc (default: LocalUsersView)
 	Selected       int
 	Offset         int
 	Width          int
@@ -98,6 +103,7 @@ func InitialModel(accountId uuid.UUID, width, height int, localDomain string) Mo
 		Status:      "",
 		Error:       "",
 		LocalDomain: localDomain,
+		ReturnView:  common.LocalUsersView,
 	}
 }
 
@@ -107,11 +113,13 @@ func (m Model) Init() tea.Cmd {
 
 // profileLoadedMsg is sent when profile data is loaded
 type profileLoadedMsg struct {
-	account     *domain.Account
-	posts       []domain.Note
-	isFollowing bool
-	avatarStr   string
-	err         error
+	account       *domain.Account
+	remote        *domain.RemoteAccount
+	posts         []domain.Note
+	isFollowing   bool
+	followPending bool
+	avatarStr     string
+	err           error
 }
 
 // clearStatusMsg is sent after a delay to clear status messages
@@ -120,6 +128,7 @@ type clearStatusMsg struct{}
 // followToggledMsg is sent after follow/unfollow completes
 type followToggledMsg struct {
 	isFollowing bool
+	pending     bool
 	username    string
 	err         error
 }
@@ -133,8 +142,12 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.Selected = 0
 		m.Offset = 0
 		m.ProfileUser = nil
+		m.RemoteUser = nil
 		m.Posts = nil
 		m.AvatarRendered = ""
+		if msg.RemoteActorURI != "" {
+			return m, loadRemoteProfile(m.AccountId, msg.RemoteActorURI)
+		}
 		return m, loadProfile(m.AccountId, msg.Username)
 
 	case profileLoadedMsg:
@@ -144,8 +157,10 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, nil
 		}
 		m.ProfileUser = msg.account
+		m.RemoteUser = msg.remote
 		m.Posts = msg.posts
 		m.IsFollowing = msg.isFollowing
+		m.FollowPending = msg.followPending
 		m.AvatarRendered = msg.avatarStr
 		m.Selected = 0
 		m.Offset = 0
@@ -157,7 +172,10 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, clearStatusAfter(2 * time.Second)
 		}
 		m.IsFollowing = msg.isFollowing
-		if msg.isFollowing {
+		m.FollowPending = msg.pending
+		if msg.pending {
+			m.Status = fmt.Sprintf("Sent follow request to @%s", msg.username)
+		} else if msg.isFollowing {
 			m.Status = fmt.Sprintf("Following @%s", msg.username)
 		} else {
 			m.Status = fmt.Sprintf("Unfollowed @%s", msg.username)
@@ -190,11 +208,15 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				if noteURI == "" {
 					noteURI = "local:" + post.Id.String()
 				}
+				isLocal := m.RemoteUser == nil
+				if !isLocal && post.ObjectURI == "" {
+					return m, nil
+				}
 				return m, func() tea.Msg {
 					return common.ViewThreadMsg{
 						NoteURI:   noteURI,
 						NoteID:    post.Id,
-						IsLocal:   true,
+						IsLocal:   isLocal,
 						Author:    post.CreatedBy,
 						Content:   post.Message,
 						CreatedAt: post.CreatedAt,
@@ -203,12 +225,16 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			}
 		case "f":
 			// Toggle follow/unfollow
+			if m.RemoteUser != nil {
+				return m, toggleRemoteFollow(m.AccountId, m.RemoteUser, m.IsFollowing || m.FollowPending)
+			}
 			if m.ProfileUser != nil {
 				return m, toggleFollow(m.AccountId, m.ProfileUser, m.IsFollowing)
 			}
 		case "esc":
+			returnView := m.ReturnView
 			return m, func() tea.Msg {
-				return common.LocalUsersView
+				return returnView
 			}
 		}
 	}
@@ -259,10 +285,11 @@ func (m Model) View() string {
 		headerText.WriteString("\n")
 	}
 
-	// Metadata line: join date + follow status
-	joinDuration := time.Since(m.ProfileUser.CreatedAt)
+	// Metadata line: join date (local) or server (remote) + follow status
 	var joinStr string
-	if joinDuration < common.HoursPerDay*time.Hour {
+	if m.RemoteUser != nil {
+		joinStr = "remote account on " + m.RemoteUser.Domain
+	} else if joinDuration := time.Since(m.ProfileUser.CreatedAt); joinDuration < common.HoursPerDay*time.Hour {
 		joinStr = fmt.Sprintf("joined %dh ago", int(joinDuration.Hours()))
 	} else {
 		joinStr = fmt.Sprintf("joined %dd ago", int(joinDuration.Hours()/common.HoursPerDay))
@@ -271,6 +298,8 @@ func (m Model) View() string {
 	var followBadge string
 	if m.IsFollowing {
 		followBadge = followBadgeStyle.Render("following")
+	} else if m.FollowPending {
+		followBadge = notFollowBadgeStyle.Render("follow pending")
 	} else {
 		followBadge = notFollowBadgeStyle.Render("not following")
 	}
@@ -296,7 +325,10 @@ func (m Model) View() string {
 	s.WriteString(common.CaptionStyle.Render(fmt.Sprintf("recent posts (%d)", postCount)))
 	s.WriteString("\n")
 
-	if postCount == 0 {
+	if postCount == 0 && m.RemoteUser != nil {
+		s.WriteString(emptyStyle.Render("No posts from this account on this server yet."))
+		s.WriteString("\n")
+	} else if postCount == 0 {
 		s.WriteString(emptyStyle.Render("No posts yet."))
 		s.WriteString("\n")
 	} else {
@@ -425,6 +457,123 @@ func loadProfile(viewerAccountId uuid.UUID, username string) tea.Cmd {
 	}
 }
 
+// loadRemoteProfile loads a remote account, the posts of it stored on this server, and follow status
+func loadRemoteProfile(viewerAccountId uuid.UUID, actorURI string) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+
+		err, remote := database.ReadRemoteAccountByActorURI(actorURI)
+		if err != nil || remote == nil {
+			return profileLoadedMsg{err: fmt.Errorf("user not found")}
+		}
+
+		handle := remote.Username + "@" + remote.Domain
+		account := &domain.Account{
+			Id:          remote.Id,
+			Username:    handle,
+			DisplayName: remote.DisplayName,
+			Summary:     util.UnescapeHTML(util.StripHTMLTags(remote.Summary)),
+			AvatarURL:   remote.AvatarURL,
+		}
+
+		var posts []domain.Note
+		if err, activities := database.ReadPostsByActorURI(actorURI, maxProfilePosts); err != nil {
+			log.Printf("Failed to load posts for profile %s: %v", handle, err)
+		} else if activities != nil {
+			for _, activity := range *activities {
+				posts = append(posts, domain.Note{
+					Id:         activity.Id,
+					CreatedBy:  handle,
+					Message:    activityContent(activity.RawJSON),
+					CreatedAt:  activity.CreatedAt,
+					ObjectURI:  activity.ObjectURI,
+					ReplyCount: activity.ReplyCount,
+					LikeCount:  activity.LikeCount,
+					BoostCount: activity.BoostCount,
+				})
+			}
+		}
+
+		isFollowing, followPending := false, false
+		if err, follow := database.ReadFollowByAccountIds(viewerAccountId, remote.Id); err == nil && follow != nil {
+			isFollowing = follow.Accepted
+			followPending = !follow.Accepted
+		}
+
+		var avatarStr string
+		if remote.AvatarURL != "" {
+			img := util.LoadAvatarImage(remote.AvatarURL)
+			if img != nil {
+				avatarStr = util.RenderImageToHalfBlocks(img, avatarCols, avatarRows)
+			}
+		}
+
+		return profileLoadedMsg{
+			account:       account,
+			remote:        remote,
+			posts:         posts,
+			isFollowing:   isFollowing,
+			followPending: followPending,
+			avatarStr:     avatarStr,
+		}
+	}
+}
+
+// activityContent extracts the plain text of a stored Create activity
+func activityContent(rawJSON string) string {
+	var create struct {
+		Object struct {
+			Content string `json:"content"`
+		} `json:"object"`
+	}
+	if err := json.Unmarshal([]byte(rawJSON), &create); err != nil {
+		return ""
+	}
+	return util.StripHTMLTags(create.Object.Content)
+}
+
+// toggleRemoteFollow sends a follow request to a remote account, or unfollows it
+func toggleRemoteFollow(viewerAccountId uuid.UUID, remote *domain.RemoteAccount, isFollowing bool) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+		handle := remote.Username + "@" + remote.Domain
+
+		err, localAccount := database.ReadAccById(viewerAccountId)
+		if err != nil {
+			return followToggledMsg{err: err}
+		}
+		conf, err := util.ReadConf()
+		if err != nil {
+			return followToggledMsg{err: err}
+		}
+
+		if isFollowing {
+			err, follow := database.ReadFollowByAccountIds(viewerAccountId, remote.Id)
+			if err != nil || follow == nil {
+				return followToggledMsg{isFollowing: false, username: handle}
+			}
+			if conf.Conf.WithAp {
+				if err := activitypub.SendUndo(localAccount, follow, remote, conf); err != nil {
+					// Continue with the local delete even if the remote server is not told
+					log.Printf("Warning: Failed to send Undo activity: %v", err)
+				}
+			}
+			if err := database.DeleteFollowByURI(follow.URI); err != nil {
+				return followToggledMsg{err: err}
+			}
+			return followToggledMsg{isFollowing: false, username: handle}
+		}
+
+		if !conf.Conf.WithAp {
+			return followToggledMsg{err: fmt.Errorf("federation is disabled on this server")}
+		}
+		if err := activitypub.SendFollow(localAccount, remote.ActorURI, conf); err != nil {
+			return followToggledMsg{err: err}
+		}
+		return followToggledMsg{pending: true, username: handle}
+	}
+}
+
 // toggleFollow follows or unfollows the profile user
 func toggleFollow(viewerAccountId uuid.UUID, profileUser *domain.Account, isFollowing bool) tea.Cmd {
 	return func() tea.Msg {
@@ -593,3 +593,69 @@ func TestFormatTime(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdate_EscapeReturnsToReturnView(t *testing.T) {
+	m := InitialModel(uuid.New(), 120, 40, "")
+	m.ReturnView = common.ResolveView
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if cmd == nil {
+		t.Fatal("Expected command for escape")
+	}
+	if msg := cmd(); msg != common.ResolveView {
+		t.Errorf("Expected ResolveView, got %v", msg)
+	}
+}
+
+func TestRemoteProfile(t *testing.T) {
+	m := InitialModel(uuid.New(), 120, 40, "example.com")
+	remote := &domain.RemoteAccount{
+		Id:       uuid.New(),
+		Username: "bob",
+		Domain:   "remote.example",
+		ActorURI: "https://remote.example/users/bob",
+	}
+	activityId := uuid.New()
+	m, _ = m.Update(profileLoadedMsg{
+		account: &domain.Account{Id: remote.Id, Username: "bob@remote.example", DisplayName: "Bob"},
+		remote:  remote,
+		posts: []domain.Note{{
+			Id:        activityId,
+			CreatedBy: "bob@remote.example",
+			Message:   "Hello from afar",
+			CreatedAt: time.Now(),
+			ObjectURI: "https://remote.example/notes/1",
+		}},
+		followPending: true,
+	})
+
+	view := m.View()
+	for _, want := range []string{"@bob@remote.example", "remote account on remote.example", "follow pending", "Hello from afar"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in view", want)
+		}
+	}
+
+	// Remote posts open as remote threads
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected command for enter")
+	}
+	viewMsg, ok := cmd().(common.ViewThreadMsg)
+	if !ok {
+		t.Fatal("Expected ViewThreadMsg")
+	}
+	if viewMsg.IsLocal || viewMsg.NoteURI != "https://remote.example/notes/1" || viewMsg.NoteID != activityId {
+		t.Errorf("Unexpected thread message: %+v", viewMsg)
+	}
+}
+
+func TestActivityContent(t *testing.T) {
+	raw := `{"type":"Create","object":{"type":"Note","content":"<p>Hello <b>world</b></p>"}}`
+	if got := activityContent(raw); got != "Hello world" {
+		t.Errorf("Expected plain text content, got %q", got)
+	}
+	if got := activityContent("not json"); got != "" {
+		t.Errorf("Expected empty content for invalid JSON, got %q", got)
+	}
+}
@@ -0,0 +1,186 @@
+package resolve
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/deemkeen/stegodon/ui/common"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/deemkeen/stegodon/web"
+	"github.com/google/uuid"
+)
+
+type Model struct {
+	TextInput  textinput.Model
+	AccountId  uuid.UUID
+	Status     string
+	Error      string
+	ReturnView common.SessionState // View to return to on Esc
+	resolving  bool
+}
+
+func InitialModel(accountId uuid.UUID) Model {
+	ti := textinput.New()
+	ti.Placeholder = "https://mastodon.social/@user/123 or @user@domain"
+	ti.Prompt = common.ListSelectedPrefix
+	ti.Focus()
+	ti.CharLimit = 500
+	ti.Width = 60
+
+	return Model{
+		TextInput:  ti,
+		AccountId:  accountId,
+		ReturnView: common.HomeTimelineView,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// resolvedMsg is sent when a pasted URL was resolved
+type resolvedMsg struct {
+	open tea.Msg // ViewThreadMsg or ViewProfileMsg to open the result
+	err  error
+}
+
+// clearStatusMsg is sent after a delay to clear status/error messages
+type clearStatusMsg struct{}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case clearStatusMsg:
+		m.Error = ""
+		return m, nil
+
+	case resolvedMsg:
+		m.resolving = false
+		m.Status = ""
+		if msg.err != nil {
+			m.Error = fmt.Sprintf("Failed: %v", msg.err)
+			return m, clearStatusAfter(3 * time.Second)
+		}
+		m.TextInput.SetValue("")
+		open := msg.open
+		return m, func() tea.Msg { return open }
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			if m.resolving {
+				return m, nil
+			}
+			input := strings.TrimSpace(m.TextInput.Value())
+			if input == "" {
+				m.Error = "Please paste a post or profile URL"
+				return m, clearStatusAfter(2 * time.Second)
+			}
+			m.resolving = true
+			m.Status = "Resolving " + input + "..."
+			m.Error = ""
+			return m, resolveCmd(input)
+		case "esc":
+			m.TextInput.SetValue("")
+			m.Status = ""
+			m.Error = ""
+			returnView := m.ReturnView
+			return m, func() tea.Msg { return returnView }
+		}
+	}
+
+	m.TextInput, cmd = m.TextInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) View() string {
+	var s strings.Builder
+
+	s.WriteString(common.CaptionStyle.Render("open url"))
+	s.WriteString("\n\n")
+	s.WriteString("Paste the URL of a post or profile from any fediverse server:\n")
+	s.WriteString("(e.g., https://mastodon.social/@user/1234, https://mastodon.social/@user, or @user@mastodon.social)\n\n")
+	s.WriteString(m.TextInput.View())
+	s.WriteString("\n\n")
+
+	if m.Status != "" {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(common.COLOR_SUCCESS)).Render(m.Status))
+		s.WriteString("\n")
+	}
+
+	if m.Error != "" {
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(common.COLOR_ERROR)).Render(m.Error))
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+// clearStatusAfter returns a command that sends clearStatusMsg after a duration
+func clearStatusAfter(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return clearStatusMsg{}
+	})
+}
+
+// resolveCmd looks up the pasted URL and returns the message that opens it
+func resolveCmd(input string) tea.Cmd {
+	return func() tea.Msg {
+		conf, err := util.ReadConf()
+		if err != nil {
+			return resolvedMsg{err: fmt.Errorf("failed to read config: %w", err)}
+		}
+		result, err := web.ResolveURL(input, conf)
+		if err != nil {
+			return resolvedMsg{err: err}
+		}
+		return resolvedMsg{open: openMsg(result)}
+	}
+}
+
+// openMsg returns the message that opens a resolved post in the thread view
+// or a resolved account in the profile view
+func openMsg(result *web.ResolveResult) tea.Msg {
+	switch {
+	case result.Note != nil:
+		note := result.Note
+		noteURI := note.ObjectURI
+		if noteURI == "" {
+			noteURI = "local:" + note.Id.String()
+		}
+		return common.ViewThreadMsg{
+			NoteURI:   noteURI,
+			NoteID:    note.Id,
+			IsLocal:   true,
+			Author:    note.CreatedBy,
+			Content:   note.Message,
+			CreatedAt: note.CreatedAt,
+		}
+	case result.Activity != nil:
+		author := ""
+		if result.RemoteAccount != nil {
+			author = "@" + result.RemoteAccount.Username + "@" + result.RemoteAccount.Domain
+		}
+		return common.ViewThreadMsg{
+			NoteURI:   result.Activity.ObjectURI,
+			NoteID:    result.Activity.Id,
+			IsLocal:   false,
+			Author:    author,
+			CreatedAt: result.Activity.CreatedAt,
+		}
+	case result.Account != nil:
+		return common.ViewProfileMsg{
+			Username:  result.Account.Username,
+			AccountId: result.Account.Id,
+		}
+	default:
+		return common.ViewProfileMsg{
+			RemoteActorURI: result.RemoteAccount.ActorURI,
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package resolve
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/ui/common"
+	"github.com/deemkeen/stegodon/web"
+	"github.com/google/uuid"
+)
+
+func TestEnterWithEmptyInput(t *testing.T) {
+	m := InitialModel(uuid.New())
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.Error == "" || m.resolving {
+		t.Errorf("Expected error and no lookup for empty input, got error %q", m.Error)
+	}
+	if cmd == nil {
+		t.Error("Expected command to clear the error")
+	}
+}
+
+func TestEscReturnsToReturnView(t *testing.T) {
+	m := InitialModel(uuid.New())
+	m.ReturnView = common.GlobalPostsView
+	m.TextInput.SetValue("https://example.com")
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if m.TextInput.Value() != "" {
+		t.Error("Expected input to be cleared")
+	}
+	if cmd == nil || cmd() != common.GlobalPostsView {
+		t.Error("Expected to return to GlobalPostsView")
+	}
+}
+
+func TestResolvedMsg(t *testing.T) {
+	m := InitialModel(uuid.New())
+	m.resolving = true
+	m.TextInput.SetValue("https://remote.example/@bob")
+
+	failed, _ := m.Update(resolvedMsg{err: errors.New("HTTP 404")})
+	if failed.resolving || failed.Error == "" || failed.TextInput.Value() == "" {
+		t.Errorf("Expected error and kept input, got error %q", failed.Error)
+	}
+
+	open := common.ViewProfileMsg{RemoteActorURI: "https://remote.example/users/bob"}
+	m, cmd := m.Update(resolvedMsg{open: open})
+	if m.resolving || m.TextInput.Value() != "" {
+		t.Error("Expected input to be cleared after resolving")
+	}
+	if cmd == nil || cmd() != open {
+		t.Error("Expected command that opens the result")
+	}
+}
+
+func TestOpenMsg(t *testing.T) {
+	now := time.Now()
+	noteId := uuid.New()
+	msg := openMsg(&web.ResolveResult{Note: &domain.Note{Id: noteId, CreatedBy: "alice", Message: "hi", CreatedAt: now}})
+	thread, ok := msg.(common.ViewThreadMsg)
+	if !ok || !thread.IsLocal || thread.NoteURI != "local:"+noteId.String() || thread.NoteID != noteId {
+		t.Errorf("Unexpected message for local post: %+v", msg)
+	}
+
+	activity := &domain.Activity{Id: uuid.New(), ObjectURI: "https://remote.example/notes/1", CreatedAt: now}
+	remote := &domain.RemoteAccount{Username: "bob", Domain: "remote.example", ActorURI: "https://remote.example/users/bob"}
+	msg = openMsg(&web.ResolveResult{Activity: activity, RemoteAccount: remote})
+	thread, ok = msg.(common.ViewThreadMsg)
+	if !ok || thread.IsLocal || thread.NoteURI != activity.ObjectURI || thread.Author != "@bob@remote.example" {
+		t.Errorf("Unexpected message for remote post: %+v", msg)
+	}
+
+	msg = openMsg(&web.ResolveResult{Account: &domain.Account{Id: uuid.New(), Username: "alice"}})
+	if profile, ok := msg.(common.ViewProfileMsg); !ok || profile.Username != "alice" {
+		t.Errorf("Unexpected message for local account: %+v", msg)
+	}
+
+	msg = openMsg(&web.ResolveResult{RemoteAccount: remote})
+	if profile, ok := msg.(common.ViewProfileMsg); !ok || profile.RemoteActorURI != remote.ActorURI {
+		t.Errorf("Unexpected message for remote account: %+v", msg)
+	}
+}
@@ -0,0 +1,206 @@
+package scheduled
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/ui/common"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+// previewLength is the number of characters of a scheduled note shown in the list
+const previewLength = 60
+
+type Model struct {
+	AccountId  uuid.UUID
+	Notes      []domain.ScheduledNote
+	Selected   int
+	Status     string
+	Error      string
+	ReturnView common.SessionState // View to return to on Esc
+}
+
+func InitialModel(accountId uuid.UUID) Model {
+	return Model{
+		AccountId:  accountId,
+		ReturnView: common.CreateNoteView,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return loadScheduledNotesCmd(m.AccountId)
+}
+
+// scheduledNotesLoadedMsg is sent when the queue is (re)loaded
+type scheduledNotesLoadedMsg struct {
+	notes  []domain.ScheduledNote
+	status string
+	err    error
+}
+
+// clearStatusMsg is sent after a delay to clear status/error messages
+type clearStatusMsg struct{}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case clearStatusMsg:
+		m.Status = ""
+		m.Error = ""
+		return m, nil
+
+	case scheduledNotesLoadedMsg:
+		m.Notes = msg.notes
+		if m.Selected >= len(m.Notes) {
+			m.Selected = max(len(m.Notes)-1, 0)
+		}
+		if msg.err != nil {
+			m.Error = fmt.Sprintf("Failed: %v", msg.err)
+			return m, clearStatusAfter(3 * time.Second)
+		}
+		if msg.status != "" {
+			m.Status = msg.status
+			return m, clearStatusAfter(2 * time.Second)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if m.Selected > 0 {
+				m.Selected--
+			}
+		case "down", "j":
+			if m.Selected < len(m.Notes)-1 {
+				m.Selected++
+			}
+		case "e", "enter":
+			if m.Selected < len(m.Notes) {
+				note := m.Notes[m.Selected]
+				return m, func() tea.Msg {
+					return common.EditScheduledNoteMsg{
+						Id:          note.Id,
+						Message:     note.Message,
+						ScheduledAt: note.ScheduledAt,
+					}
+				}
+			}
+		case "d":
+			if m.Selected < len(m.Notes) {
+				note := m.Notes[m.Selected]
+				return m, scheduledActionCmd(m.AccountId, func(database db.Store) error {
+					return database.DeleteScheduledNote(note.Id, m.AccountId)
+				}, "Cancelled scheduled note")
+			}
+		case "esc":
+			m.Status = ""
+			m.Error = ""
+			returnView := m.ReturnView
+			return m, func() tea.Msg { return returnView }
+		}
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	var s strings.Builder
+
+	s.WriteString(common.CaptionStyle.Render("scheduled notes"))
+	s.WriteString("\n\n")
+
+	if len(m.Notes) == 0 {
+		s.WriteString(common.ListEmptyStyle.Render("No scheduled notes.\nAdd a time with ctrl+t when writing a note."))
+		s.WriteString("\n")
+	}
+
+	now := time.Now()
+	for i, note := range m.Notes {
+		preview := strings.ReplaceAll(util.StripHTMLTags(note.Message), "\n", " ")
+		if len([]rune(preview)) > previewLength {
+			preview = string([]rune(preview)[:previewLength-3]) + "..."
+		}
+		badge := fmt.Sprintf(" %s (%s)", note.ScheduledAt.Local().Format(util.ScheduleTimeFormat), formatDue(note.ScheduledAt, now))
+
+		if i == m.Selected {
+			s.WriteString(common.ListSelectedPrefix + common.ListItemSelectedStyle.Render(preview+badge))
+		} else {
+			s.WriteString(common.ListUnselectedPrefix + common.ListItemStyle.Render(preview) + common.ListBadgeStyle.Render(badge))
+		}
+		s.WriteString("\n")
+	}
+
+	if m.Status != "" {
+		s.WriteString("\n")
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(common.COLOR_SUCCESS)).Render(m.Status))
+		s.WriteString("\n")
+	}
+
+	if m.Error != "" {
+		s.WriteString("\n")
+		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(common.COLOR_ERROR)).Render(m.Error))
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+// formatDue describes how long until a scheduled note is published
+func formatDue(at, now time.Time) string {
+	remaining := at.Sub(now)
+	switch {
+	case remaining <= 0:
+		return "publishing"
+	case remaining < time.Hour:
+		return fmt.Sprintf("in %dm", int(remaining.Minutes())+1)
+	case remaining < common.HoursPerDay*time.Hour:
+		return fmt.Sprintf("in %dh", int(remaining.Hours()))
+	default:
+		return fmt.Sprintf("in %dd", int(remaining.Hours()/common.HoursPerDay))
+	}
+}
+
+// clearStatusAfter returns a command that sends clearStatusMsg after a duration
+func clearStatusAfter(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return clearStatusMsg{}
+	})
+}
+
+// loadScheduledNotesCmd loads the account's scheduled notes
+func loadScheduledNotesCmd(accountId uuid.UUID) tea.Cmd {
+	return scheduledActionCmd(accountId, nil, "")
+}
+
+// scheduledActionCmd runs action (if any) and reloads the queue afterwards
+func scheduledActionCmd(accountId uuid.UUID, action func(database db.Store) error, status string) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+		msg := scheduledNotesLoadedMsg{}
+
+		if action != nil {
+			if err := action(database); err != nil {
+				log.Printf("Scheduled note update failed: %v", err)
+				msg.err = err
+			} else {
+				msg.status = status
+			}
+		}
+
+		err, notes := database.ReadScheduledNotesByAccountId(accountId)
+		if err != nil {
+			log.Printf("Failed to load scheduled notes: %v", err)
+			msg.err = err
+			return msg
+		}
+		if notes != nil {
+			msg.notes = *notes
+		}
+		return msg
+	}
+}
@@ -0,0 +1,85 @@
+package scheduled
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/ui/common"
+	"github.com/google/uuid"
+)
+
+func testNotes() []domain.ScheduledNote {
+	now := time.Now()
+	return []domain.ScheduledNote{
+		{Id: uuid.New(), Message: "soon", ScheduledAt: now.Add(30 * time.Minute)},
+		{Id: uuid.New(), Message: "tomorrow", ScheduledAt: now.Add(26 * time.Hour)},
+	}
+}
+
+func TestNavigationAndEdit(t *testing.T) {
+	m := InitialModel(uuid.New())
+	m, _ = m.Update(scheduledNotesLoadedMsg{notes: testNotes()})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if m.Selected != 1 {
+		t.Fatalf("Expected selection to stop at the last note, got %d", m.Selected)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected edit command")
+	}
+	edit, ok := cmd().(common.EditScheduledNoteMsg)
+	if !ok || edit.Id != m.Notes[1].Id || edit.Message != "tomorrow" {
+		t.Errorf("Expected EditScheduledNoteMsg for the selected note, got %#v", edit)
+	}
+}
+
+func TestLoadedMsgClampsSelection(t *testing.T) {
+	m := InitialModel(uuid.New())
+	m.Selected = 5
+
+	m, _ = m.Update(scheduledNotesLoadedMsg{notes: testNotes()[:1], status: "Cancelled scheduled note"})
+	if m.Selected != 0 {
+		t.Errorf("Expected selection 0, got %d", m.Selected)
+	}
+	if m.Status == "" {
+		t.Error("Expected status after an action")
+	}
+
+	m, _ = m.Update(scheduledNotesLoadedMsg{err: errors.New("db locked")})
+	if m.Error == "" {
+		t.Error("Expected error to be shown")
+	}
+}
+
+func TestEscReturnsToReturnView(t *testing.T) {
+	m := InitialModel(uuid.New())
+	m.ReturnView = common.MyPostsView
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if cmd == nil || cmd() != common.MyPostsView {
+		t.Error("Expected to return to MyPostsView")
+	}
+}
+
+func TestView(t *testing.T) {
+	m := InitialModel(uuid.New())
+	if !strings.Contains(m.View(), "No scheduled notes") {
+		t.Error("Expected empty state")
+	}
+
+	m, _ = m.Update(scheduledNotesLoadedMsg{notes: testNotes()})
+	view := m.View()
+	if !strings.Contains(view, "soon") || !strings.Contains(view, "in 30m") && !strings.Contains(view, "in 31m") {
+		t.Errorf("Expected note preview and due time, got:\n%s", view)
+	}
+	if !strings.Contains(view, "in 1d") {
+		t.Errorf("Expected due time in days, got:\n%s", view)
+	}
+}
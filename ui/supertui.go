@@ -8,13 +8,17 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/deemkeen/stegodon/actions"
 	"github.com/deemkeen/stegodon/activitypub"
 	"github.com/deemkeen/stegodon/db"
 	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/events"
+	"github.com/deemkeen/stegodon/metrics"
 	"github.com/deemkeen/stegodon/ui/accountsettings"
 	"github.com/deemkeen/stegodon/ui/admin"
 	"github.com/deemkeen/stegodon/ui/common"
 	"github.com/deemkeen/stegodon/ui/createuser"
+	"github.com/deemkeen/stegodon/ui/drafts"
 	"github.com/deemkeen/stegodon/ui/followers"
 	"github.com/deemkeen/stegodon/ui/following"
 	"github.com/deemkeen/stegodon/ui/followuser"
@@ -26,6 +30,9 @@ import (
 	"github.com/deemkeen/stegodon/ui/notifications"
 	"github.com/deemkeen/stegodon/ui/profileview"
 	"github.com/deemkeen/stegodon/ui/relay"
+	"github.com/deemkeen/stegodon/ui/resolve"
+	"github.com/deemkeen/stegodon/ui/scheduled"
+	"github.com/deemkeen/stegodon/ui/tagview"
 	"github.com/deemkeen/stegodon/ui/threadview"
 	"github.com/deemkeen/stegodon/ui/writenote"
 	"github.com/deemkeen/stegodon/util"
@@ -57,19 +64,61 @@ type MainModel struct {
 	followersModel       followers.Model
 	followingModel       following.Model
 	homeTimelineModel    hometimeline.Model
+	listTimelineModel    hometimeline.Model // Home timeline model pointed at the current list
+	lists                []domain.List      // User's lists, one tab each after home
+	listIndex            int                // Index of the list shown in ListTimelineView
 	localUsersModel      localusers.Model
 	adminModel           admin.Model
 	relayModel           relay.Model
 	accountSettingsModel accountsettings.Model
 	threadViewModel      threadview.Model
 	profileViewModel     profileview.Model
+	tagViewModel         tagview.Model
 	notificationsModel   notifications.Model
+	resolveModel         resolve.Model
+	scheduledModel       scheduled.Model
+	draftsModel          drafts.Model
+	views                *metrics.ViewTracker // Reports the current view to the metrics (nil when untracked)
+	events               *events.Subscription // Live updates from the event bus (nil when not subscribed)
 }
 
 type userUpdateErrorMsg struct {
 	err error
 }
 
+// userListsLoadedMsg is sent when the user's lists are (re)loaded for the tab cycle
+type userListsLoadedMsg struct {
+	lists []domain.List
+}
+
+// loadUserListsCmd loads the user's lists
+func loadUserListsCmd(accountId uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		err, lists := db.GetDB().ReadListsByAccountId(accountId)
+		if err != nil || lists == nil {
+			if err != nil {
+				log.Printf("Failed to load lists: %v", err)
+			}
+			return userListsLoadedMsg{}
+		}
+		return userListsLoadedMsg{lists: *lists}
+	}
+}
+
+// showListTimeline points the list timeline at the list at index and activates it.
+// An index out of range deactivates the list timeline instead.
+func (m *MainModel) showListTimeline(index int) tea.Cmd {
+	var cmd tea.Cmd
+	if index < 0 || index >= len(m.lists) {
+		m.listTimelineModel, cmd = m.listTimelineModel.Update(common.DeactivateViewMsg{})
+		return cmd
+	}
+	m.listIndex = index
+	m.listTimelineModel.SetList(m.lists[index])
+	m.listTimelineModel, cmd = m.listTimelineModel.Update(common.ActivateViewMsg{})
+	return cmd
+}
+
 func updateUserModelCmd(acc *domain.Account) tea.Cmd {
 	return func() tea.Msg {
 		acc.FirstTimeLogin = domain.FALSE
@@ -107,13 +156,18 @@ func NewModel(acc domain.Account, width int, height int) MainModel {
 	followersModel := followers.InitialModel(acc.Id, width, height)
 	followingModel := following.InitialModel(acc.Id, width, height)
 	homeTimelineModel := hometimeline.InitialModel(acc.Id, width, height, localDomain)
+	listTimelineModel := hometimeline.InitialModel(acc.Id, width, height, localDomain)
 	localUsersModel := localusers.InitialModel(acc.Id, width, height)
 	adminModel := admin.InitialModel(acc.Id, width, height)
 	relayModel := relay.InitialModel(acc.Id, &acc, config, width, height)
 	accountSettingsModel := accountsettings.InitialModel(&acc)
 	threadViewModel := threadview.InitialModel(acc.Id, width, height, localDomain)
 	profileViewModel := profileview.InitialModel(acc.Id, width, height, localDomain)
+	tagViewModel := tagview.InitialModel(acc.Id, width, height, localDomain)
 	notificationsModel := notifications.InitialModel(acc.Id, width, height)
+	resolveModel := resolve.InitialModel(acc.Id)
+	scheduledModel := scheduled.InitialModel(acc.Id)
+	draftsModel := drafts.InitialModel(acc.Id)
 
 	m := MainModel{state: common.CreateUserView}
 	m.config = config
@@ -125,13 +179,18 @@ func NewModel(acc domain.Account, width int, height int) MainModel {
 	m.followersModel = followersModel
 	m.followingModel = followingModel
 	m.homeTimelineModel = homeTimelineModel
+	m.listTimelineModel = listTimelineModel
 	m.localUsersModel = localUsersModel
 	m.adminModel = adminModel
 	m.relayModel = relayModel
 	m.accountSettingsModel = accountSettingsModel
 	m.threadViewModel = threadViewModel
 	m.profileViewModel = profileViewModel
+	m.tagViewModel = tagViewModel
 	m.notificationsModel = notificationsModel
+	m.resolveModel = resolveModel
+	m.scheduledModel = scheduledModel
+	m.draftsModel = draftsModel
 	m.headerModel = headerModel
 	m.account = acc
 	m.width = width
@@ -149,6 +208,12 @@ func (m MainModel) Init() tea.Cmd {
 	// Also activates notifications model to start badge refresh
 	cmds = append(cmds, func() tea.Msg { return common.ActivateViewMsg{} })
 
+	// Load lists so their timelines join the tab cycle
+	cmds = append(cmds, loadUserListsCmd(m.account.Id))
+
+	// Start listening for live updates
+	cmds = append(cmds, common.WaitForEvent(m.events))
+
 	if m.account.FirstTimeLogin == domain.TRUE {
 		cmds = append(cmds, func() tea.Msg {
 			return common.CreateUserView
@@ -164,7 +229,27 @@ func (m MainModel) Init() tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
+// TrackViews reports the session's current view to the metrics from now on
+func (m *MainModel) TrackViews(views *metrics.ViewTracker) {
+	m.views = views
+	views.Set(m.state.String())
+}
+
+// SubscribeEvents lets the views update live from the event bus.
+// Must be called before the program starts; the caller closes sub when the session ends.
+func (m *MainModel) SubscribeEvents(sub *events.Subscription) {
+	m.events = sub
+}
+
 func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	if next, ok := model.(MainModel); ok && next.state != m.state {
+		next.views.Set(next.state.String())
+	}
+	return model, cmd
+}
+
+func (m MainModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
@@ -189,6 +274,8 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.globalPostsModel.Height = msg.Height
 		m.homeTimelineModel.Width = msg.Width
 		m.homeTimelineModel.Height = msg.Height
+		m.listTimelineModel.Width = msg.Width
+		m.listTimelineModel.Height = msg.Height
 		m.followersModel.Width = msg.Width
 		m.followersModel.Height = msg.Height
 		m.followingModel.Width = msg.Width
@@ -199,6 +286,8 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.threadViewModel.Height = msg.Height
 		m.profileViewModel.Width = msg.Width
 		m.profileViewModel.Height = msg.Height
+		m.tagViewModel.Width = msg.Width
+		m.tagViewModel.Height = msg.Height
 		return m, nil
 
 	case tea.MouseMsg:
@@ -230,6 +319,8 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.state = common.CreateUserView
 		case common.HomeTimelineView:
 			m.state = common.HomeTimelineView
+		case common.ListTimelineView:
+			m.state = common.ListTimelineView
 		case common.MyPostsView:
 			m.state = common.MyPostsView
 		case common.CreateNoteView:
@@ -248,12 +339,48 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.state = common.ThreadView
 		case common.ProfileView:
 			m.state = common.ProfileView
+		case common.TagView:
+			m.state = common.TagView
+		case common.GlobalPostsView:
+			m.state = common.GlobalPostsView
+		case common.ResolveView:
+			m.state = common.ResolveView
+		case common.ScheduledNotesView:
+			// Reload the queue, e.g. after a scheduled note was edited
+			m.state = common.ScheduledNotesView
+			cmds = append(cmds, m.scheduledModel.Init())
 		case common.UpdateNoteList:
 			// Route to models that need to refresh (handled by SessionState routing below)
 			// Note: This message is also a SessionState, so it will trigger reloads
 			// in myposts and hometimeline via the SessionState routing
 		}
 
+	case common.EventMsg:
+		// Each view decides whether the event concerns it, then wait for the next one
+		cmds = append(cmds, common.WaitForEvent(m.events))
+		m.homeTimelineModel, cmd = m.homeTimelineModel.Update(msg)
+		cmds = append(cmds, cmd)
+		m.listTimelineModel, cmd = m.listTimelineModel.Update(msg)
+		cmds = append(cmds, cmd)
+		m.globalPostsModel, cmd = m.globalPostsModel.Update(msg)
+		cmds = append(cmds, cmd)
+		m.notificationsModel, cmd = m.notificationsModel.Update(msg)
+		cmds = append(cmds, cmd)
+		m.myPostsModel, cmd = m.myPostsModel.Update(msg)
+		cmds = append(cmds, cmd)
+		return m, tea.Batch(cmds...)
+
+	case common.ListsChangedMsg:
+		// Lists were edited in the following view, refresh the tab cycle
+		return m, loadUserListsCmd(m.account.Id)
+
+	case userListsLoadedMsg:
+		m.lists = msg.lists
+		if m.listIndex >= len(m.lists) {
+			m.listIndex = 0
+		}
+		return m, nil
+
 	case common.EditNoteMsg:
 		// Route EditNote message to writenote model and switch to CreateNoteView
 		m.createModel, cmd = m.createModel.Update(msg)
@@ -261,6 +388,18 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Return single command directly instead of batching
 		return m, cmd
 
+	case common.EditScheduledNoteMsg:
+		// Route EditScheduledNote message to writenote model and switch to CreateNoteView
+		m.createModel, cmd = m.createModel.Update(msg)
+		m.state = common.CreateNoteView
+		return m, cmd
+
+	case common.ResumeDraftMsg:
+		// Route ResumeDraft message to writenote model and switch to CreateNoteView
+		m.createModel, cmd = m.createModel.Update(msg)
+		m.state = common.CreateNoteView
+		return m, cmd
+
 	case common.DeleteNoteMsg:
 		// Note was deleted, reload the list
 		localDomain := ""
@@ -283,6 +422,12 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Set return view based on where the thread was opened from
 		if m.state == common.ProfileView {
 			m.threadViewModel.ReturnView = common.ProfileView
+		} else if m.state == common.TagView {
+			m.threadViewModel.ReturnView = common.TagView
+		} else if m.state == common.ListTimelineView {
+			m.threadViewModel.ReturnView = common.ListTimelineView
+		} else if m.state == common.ResolveView {
+			m.threadViewModel.ReturnView = common.ResolveView
 		} else {
 			m.threadViewModel.ReturnView = common.HomeTimelineView
 		}
@@ -292,11 +437,31 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case common.ViewProfileMsg:
+		// Return to the resolve prompt if the profile was opened by URL
+		if m.state == common.ResolveView {
+			m.profileViewModel.ReturnView = common.ResolveView
+		} else {
+			m.profileViewModel.ReturnView = common.LocalUsersView
+		}
 		// Route ViewProfile message to profileview model and switch to ProfileView
 		m.profileViewModel, cmd = m.profileViewModel.Update(msg)
 		m.state = common.ProfileView
 		return m, cmd
 
+	case common.ViewTagMsg:
+		// Return to the timeline the tag was opened from
+		if m.state == common.GlobalPostsView {
+			m.tagViewModel.ReturnView = common.GlobalPostsView
+		} else if m.state == common.ListTimelineView {
+			m.tagViewModel.ReturnView = common.ListTimelineView
+		} else {
+			m.tagViewModel.ReturnView = common.HomeTimelineView
+		}
+		// Route ViewTag message to tagview model and switch to TagView
+		m.tagViewModel, cmd = m.tagViewModel.Update(msg)
+		m.state = common.TagView
+		return m, cmd
+
 	case common.LikeNoteMsg:
 		// Handle like/unlike
 		return m, likeNoteCmd(m.account.Id, msg.NoteURI, msg.NoteID, msg.IsLocal, &m.account)
@@ -305,6 +470,10 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Handle boost/unboost
 		return m, boostNoteCmd(m.account.Id, msg.NoteURI, msg.NoteID, msg.IsLocal, &m.account)
 
+	case common.ReactNoteMsg:
+		// Handle emoji reaction (picking the same emoji again removes it)
+		return m, reactNoteCmd(m.account.Id, msg, &m.account)
+
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c":
@@ -323,6 +492,10 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					cmds = append(cmds, func() tea.Msg { return common.DeactivateViewMsg{} })
 				}
 
+				if oldState == common.ListTimelineView {
+					cmds = append(cmds, m.showListTimeline(-1))
+				}
+
 				// Deactivate accountsettings if we were in it (stops avatar polling)
 				if oldState == common.AccountSettingsView {
 					cmds = append(cmds, func() tea.Msg { return common.DeactivateAccountSettingsMsg{} })
@@ -330,9 +503,31 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				// Note: No need to activate notifications - it's always active
 			}
+		case "ctrl+r":
+			// Open a post or profile by URL (global shortcut, works from any view)
+			// The previous view keeps its state so esc can return to it
+			if m.state != common.ResolveView && m.state != common.CreateUserView {
+				m.resolveModel.ReturnView = m.state
+				m.state = common.ResolveView
+				return m, m.resolveModel.Init()
+			}
+		case "ctrl+q":
+			// Open the queue of scheduled notes (global shortcut, works from any view)
+			if m.state != common.ScheduledNotesView && m.state != common.CreateUserView {
+				m.scheduledModel.ReturnView = m.state
+				m.state = common.ScheduledNotesView
+				return m, m.scheduledModel.Init()
+			}
+		case "ctrl+o":
+			// Open the drafts list (global shortcut, works from any view)
+			if m.state != common.DraftsView && m.state != common.CreateUserView {
+				m.draftsModel.ReturnView = m.state
+				m.state = common.DraftsView
+				return m, m.draftsModel.Init()
+			}
 		case "tab":
 			// Cycle through main views (excluding create user)
-			// Order: write -> home -> my posts -> [global posts] -> [follow] -> followers -> following -> users -> [admin -> relay] -> delete
+			// Order: write -> home -> [lists] -> my posts -> [global posts] -> [follow] -> followers -> following -> users -> [admin -> relay] -> delete
 			// AP-only views: follow remote user, relay management
 			// Optional views: global posts (when ShowGlobal is enabled)
 			if m.state == common.CreateUserView {
@@ -348,7 +543,19 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case common.CreateNoteView:
 				m.state = common.HomeTimelineView
 			case common.HomeTimelineView:
-				m.state = common.MyPostsView
+				if len(m.lists) > 0 {
+					m.state = common.ListTimelineView
+					cmds = append(cmds, m.showListTimeline(0))
+				} else {
+					m.state = common.MyPostsView
+				}
+			case common.ListTimelineView:
+				// Each list gets its own tab before my posts
+				if m.listIndex < len(m.lists)-1 {
+					cmds = append(cmds, m.showListTimeline(m.listIndex+1))
+				} else {
+					m.state = common.MyPostsView
+				}
 			case common.MyPostsView:
 				if m.config.Conf.ShowGlobal {
 					m.state = common.GlobalPostsView
@@ -388,6 +595,10 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case common.NotificationsView:
 				m.state = common.CreateNoteView
 			}
+			// Stop the list timeline's refresh when leaving the list tabs
+			if oldState == common.ListTimelineView && m.state != common.ListTimelineView {
+				cmds = append(cmds, m.showListTimeline(-1))
+			}
 			// Handle focus changes for writenote textarea
 			if oldState == common.CreateNoteView {
 				m.createModel.Blur()
@@ -449,8 +660,19 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.state = common.AccountSettingsView
 			case common.HomeTimelineView:
 				m.state = common.CreateNoteView
+			case common.ListTimelineView:
+				if m.listIndex > 0 {
+					cmds = append(cmds, m.showListTimeline(m.listIndex-1))
+				} else {
+					m.state = common.HomeTimelineView
+				}
 			case common.MyPostsView:
-				m.state = common.HomeTimelineView
+				if len(m.lists) > 0 {
+					m.state = common.ListTimelineView
+					cmds = append(cmds, m.showListTimeline(len(m.lists)-1))
+				} else {
+					m.state = common.HomeTimelineView
+				}
 			case common.GlobalPostsView:
 				m.state = common.MyPostsView
 			case common.FollowUserView:
@@ -486,6 +708,10 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.state = common.LocalUsersView
 				}
 			}
+			// Stop the list timeline's refresh when leaving the list tabs
+			if oldState == common.ListTimelineView && m.state != common.ListTimelineView {
+				cmds = append(cmds, m.showListTimeline(-1))
+			}
 			// Handle focus changes for writenote textarea
 			if oldState == common.CreateNoteView {
 				m.createModel.Blur()
@@ -579,9 +805,11 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 		m.createModel, cmd = m.createModel.Update(msg)
 		cmds = append(cmds, cmd)
-		// Also route SessionState to home timeline for UpdateNoteList handling
+		// Also route SessionState to home and list timelines for UpdateNoteList handling
 		m.homeTimelineModel, cmd = m.homeTimelineModel.Update(msg)
 		cmds = append(cmds, cmd)
+		m.listTimelineModel, cmd = m.listTimelineModel.Update(msg)
+		cmds = append(cmds, cmd)
 		// Route SessionState to threadview for like count updates
 		m.threadViewModel, cmd = m.threadViewModel.Update(msg)
 		cmds = append(cmds, cmd)
@@ -609,10 +837,12 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.localUsersModel, cmd = m.localUsersModel.Update(msg)
 		cmds = append(cmds, cmd)
 
-		// Always route to home timeline and notifications - they have internal isActive state
+		// Always route to home/list timelines and notifications - they have internal isActive state
 		// that controls whether they process messages (prevents ticker leaks)
 		m.homeTimelineModel, cmd = m.homeTimelineModel.Update(msg)
 		cmds = append(cmds, cmd)
+		m.listTimelineModel, cmd = m.listTimelineModel.Update(msg)
+		cmds = append(cmds, cmd)
 		m.notificationsModel, cmd = m.notificationsModel.Update(msg)
 		cmds = append(cmds, cmd)
 
@@ -630,6 +860,9 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case common.ProfileView:
 			m.profileViewModel, cmd = m.profileViewModel.Update(msg)
 			cmds = append(cmds, cmd)
+		case common.TagView:
+			m.tagViewModel, cmd = m.tagViewModel.Update(msg)
+			cmds = append(cmds, cmd)
 		}
 	}
 
@@ -645,6 +878,9 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case common.HomeTimelineView:
 		m.homeTimelineModel, cmd = m.homeTimelineModel.Update(msg)
 		cmds = append(cmds, cmd)
+	case common.ListTimelineView:
+		m.listTimelineModel, cmd = m.listTimelineModel.Update(msg)
+		cmds = append(cmds, cmd)
 	case common.MyPostsView:
 		m.myPostsModel, cmd = m.myPostsModel.Update(msg)
 		cmds = append(cmds, cmd)
@@ -682,9 +918,21 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case common.ProfileView:
 		m.profileViewModel, cmd = m.profileViewModel.Update(msg)
 		cmds = append(cmds, cmd)
+	case common.TagView:
+		m.tagViewModel, cmd = m.tagViewModel.Update(msg)
+		cmds = append(cmds, cmd)
 	case common.NotificationsView:
 		m.notificationsModel, cmd = m.notificationsModel.Update(msg)
 		cmds = append(cmds, cmd)
+	case common.ResolveView:
+		m.resolveModel, cmd = m.resolveModel.Update(msg)
+		cmds = append(cmds, cmd)
+	case common.ScheduledNotesView:
+		m.scheduledModel, cmd = m.scheduledModel.Update(msg)
+		cmds = append(cmds, cmd)
+	case common.DraftsView:
+		m.draftsModel, cmd = m.draftsModel.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	//  Filter out nil commands to minimize tea.Batch() goroutine accumulation
@@ -757,6 +1005,14 @@ func (m MainModel) View() string {
 		Margin(1).
 		Render(m.homeTimelineModel.View())
 
+	listTimelineStyleStr := lipgloss.NewStyle().
+		MaxHeight(availableHeight).
+		Height(availableHeight).
+		Width(rightPanelWidth).
+		MaxWidth(rightPanelWidth).
+		Margin(1).
+		Render(m.listTimelineModel.View())
+
 	myPostsStyleStr := lipgloss.NewStyle().
 		MaxHeight(availableHeight).
 		Height(availableHeight).
@@ -781,6 +1037,30 @@ func (m MainModel) View() string {
 		Margin(1).
 		Render(m.followModel.View())
 
+	resolveStyleStr := lipgloss.NewStyle().
+		MaxHeight(availableHeight).
+		Height(availableHeight).
+		Width(rightPanelWidth).
+		MaxWidth(rightPanelWidth).
+		Margin(1).
+		Render(m.resolveModel.View())
+
+	scheduledStyleStr := lipgloss.NewStyle().
+		MaxHeight(availableHeight).
+		Height(availableHeight).
+		Width(rightPanelWidth).
+		MaxWidth(rightPanelWidth).
+		Margin(1).
+		Render(m.scheduledModel.View())
+
+	draftsStyleStr := lipgloss.NewStyle().
+		MaxHeight(availableHeight).
+		Height(availableHeight).
+		Width(rightPanelWidth).
+		MaxWidth(rightPanelWidth).
+		Margin(1).
+		Render(m.draftsModel.View())
+
 	followersStyleStr := lipgloss.NewStyle().
 		MaxHeight(availableHeight).
 		Height(availableHeight).
@@ -845,6 +1125,14 @@ func (m MainModel) View() string {
 		Margin(1).
 		Render(m.profileViewModel.View())
 
+	tagViewStyleStr := lipgloss.NewStyle().
+		MaxHeight(availableHeight).
+		Height(availableHeight).
+		Width(rightPanelWidth).
+		MaxWidth(rightPanelWidth).
+		Margin(1).
+		Render(m.tagViewModel.View())
+
 	notificationsStyleStr := lipgloss.NewStyle().
 		MaxHeight(availableHeight).
 		Height(availableHeight).
@@ -872,6 +1160,10 @@ func (m MainModel) View() string {
 			s += lipgloss.JoinHorizontal(lipgloss.Top,
 				modelStyle.Render(createStyleStr),
 				focusedModelStyle.Render(homeTimelineStyleStr))
+		case common.ListTimelineView:
+			s += lipgloss.JoinHorizontal(lipgloss.Top,
+				modelStyle.Render(createStyleStr),
+				focusedModelStyle.Render(listTimelineStyleStr))
 		case common.MyPostsView:
 			s += lipgloss.JoinHorizontal(lipgloss.Top,
 				modelStyle.Render(createStyleStr),
@@ -923,27 +1215,47 @@ func (m MainModel) View() string {
 			s += lipgloss.JoinHorizontal(lipgloss.Top,
 				modelStyle.Render(createStyleStr),
 				focusedModelStyle.Render(profileViewStyleStr))
+		case common.TagView:
+			s += lipgloss.JoinHorizontal(lipgloss.Top,
+				modelStyle.Render(createStyleStr),
+				focusedModelStyle.Render(tagViewStyleStr))
 		case common.NotificationsView:
 			s += lipgloss.JoinHorizontal(lipgloss.Top,
 				modelStyle.Render(createStyleStr),
 				focusedModelStyle.Render(notificationsStyleStr))
+		case common.ResolveView:
+			s += lipgloss.JoinHorizontal(lipgloss.Top,
+				modelStyle.Render(createStyleStr),
+				focusedModelStyle.Render(resolveStyleStr))
+		case common.ScheduledNotesView:
+			s += lipgloss.JoinHorizontal(lipgloss.Top,
+				modelStyle.Render(createStyleStr),
+				focusedModelStyle.Render(scheduledStyleStr))
+		case common.DraftsView:
+			s += lipgloss.JoinHorizontal(lipgloss.Top,
+				modelStyle.Render(createStyleStr),
+				focusedModelStyle.Render(draftsStyleStr))
 		}
 
 		// Help text
 		var viewCommands string
 		switch m.state {
-		case common.HomeTimelineView:
-			viewCommands = "↑/↓ • enter: thread • r: reply • l: ⭐ • b: 🔁 • i: info • o: link"
+		case common.HomeTimelineView, common.ListTimelineView:
+			viewCommands = "↑/↓ • enter: thread • r: reply • l: ⭐ • b: 🔁 • e: react • i: info • o: link • #: tag • v: show filtered"
 		case common.MyPostsView:
 			viewCommands = "↑/↓ • u: edit • d: delete • l: ⭐ • b: 🔁"
 		case common.GlobalPostsView:
-			viewCommands = "↑/↓ • enter: thread • r: reply • l: ⭐ • b: 🔁 • i: info • o: link • f: follow"
+			viewCommands = "↑/↓ • enter: thread • r: reply • l: ⭐ • b: 🔁 • e: react • i: info • o: link • #: tag • v: show filtered • f: follow"
 		case common.FollowUserView:
 			viewCommands = "enter: follow"
 		case common.FollowersView:
 			viewCommands = "↑/↓: scroll • f: follow back"
 		case common.FollowingView:
-			viewCommands = "↑/↓ • u/enter: unfollow"
+			if m.followingModel.EditingLists {
+				viewCommands = "↑/↓ • enter: add/remove • n: new list • h: hide from home • d: delete list • esc: back"
+			} else {
+				viewCommands = "↑/↓ • u/enter: unfollow • a: lists"
+			}
 		case common.LocalUsersView:
 			viewCommands = "↑/↓ • enter: profile • f: follow"
 		case common.AdminPanelView:
@@ -963,26 +1275,59 @@ func (m MainModel) View() string {
 				viewCommands = "e: edit • esc: back"
 			case 4: // BansView
 				viewCommands = "↑/↓ • u: unban • esc: back"
+			case 5: // EmojisView
+				viewCommands = "↑/↓ • g: upload • r: refresh • d: delete • esc: back"
+			case 6: // InboxQueueView
+				viewCommands = "↑/↓ • r: retry • d: drop • R: refresh • esc: back"
+			case 7: // RemoteAccountsView
+				viewCommands = "r: refresh now • R: reload • esc: back"
+			case 8: // RetentionView
+				viewCommands = "p: prune now • R: dry run • esc: back"
+			case 9: // StatusView
+				viewCommands = "R: reload • esc: back"
 			default:
 				viewCommands = "↑/↓ • enter: select"
 			}
 		case common.RelayManagementView:
 			viewCommands = "↑/↓ • a: add • d: delete • r: retry"
 		case common.AccountSettingsView:
-			viewCommands = "↑/↓ • e: name • b: bio • a: avatar • d: delete"
+			// Context-aware help based on account settings view state
+			switch m.accountSettingsModel.ViewState {
+			case accountsettings.FiltersView:
+				viewCommands = "↑/↓ • n: new filter • d: delete • esc: back"
+			case accountsettings.FilterFormView:
+				viewCommands = "w: whole word • 1-4: contexts • a: action • x: expiry • enter: save • esc: back"
+			case accountsettings.AppsView:
+				viewCommands = "↑/↓ • n: enter code • d: revoke • esc: back"
+			case accountsettings.AppCodeView:
+				viewCommands = "enter: continue • esc: back"
+			case accountsettings.AppConfirmView:
+				viewCommands = "y: allow • n: deny"
+			default:
+				viewCommands = "↑/↓ • e: name • b: bio • a: avatar • f: filters • o: apps • d: delete"
+			}
 		case common.ThreadView:
-			viewCommands = "↑/↓ • enter: thread • r: reply • l: ⭐ • b: 🔁 • o: URL • esc: back"
+			viewCommands = "↑/↓ • enter: thread • r: reply • l: ⭐ • b: 🔁 • o: URL • v: show filtered • esc: back"
 		case common.ProfileView:
 			viewCommands = "↑/↓ • enter: thread • f: follow • esc: back"
+		case common.TagView:
+			viewCommands = "↑/↓ • enter: thread • f: follow tag • esc: back"
 		case common.NotificationsView:
 			viewCommands = "j/k: nav • v: view • f: follow • enter: del • a: del all"
+		case common.ResolveView:
+			viewCommands = "enter: open • esc: back"
+		case common.ScheduledNotesView:
+			viewCommands = "↑/↓ • e/enter: edit • d: cancel • esc: back"
+		case common.DraftsView:
+			viewCommands = "↑/↓ • enter: resume • d: discard • esc: back"
 		default:
 			viewCommands = " "
 		}
 
 		var helpText string
-		if m.state == common.ThreadView || m.state == common.ProfileView {
-			// Thread and profile views don't use tab navigation
+		if m.state == common.ThreadView || m.state == common.ProfileView || m.state == common.TagView || m.state == common.ResolveView ||
+			m.state == common.ScheduledNotesView || m.state == common.DraftsView {
+			// Thread, profile, tag, resolve, scheduled and drafts views don't use tab navigation
 			helpText = fmt.Sprintf(
 				"focused > %s\t\tkeys > %s • ctrl-c: exit",
 				model, viewCommands)
@@ -1018,6 +1363,8 @@ func (m MainModel) currentFocusedModel() string {
 		return "write"
 	case common.HomeTimelineView:
 		return "home"
+	case common.ListTimelineView:
+		return "list"
 	case common.MyPostsView:
 		return "my posts"
 	case common.GlobalPostsView:
@@ -1040,8 +1387,16 @@ func (m MainModel) currentFocusedModel() string {
 		return "thread"
 	case common.ProfileView:
 		return "profile"
+	case common.TagView:
+		return "tag"
 	case common.NotificationsView:
 		return "notifications"
+	case common.ResolveView:
+		return "open url"
+	case common.ScheduledNotesView:
+		return "scheduled"
+	case common.DraftsView:
+		return "drafts"
 	default:
 		return "create user"
 	}
@@ -1084,6 +1439,40 @@ func getViewInitCmd(state common.SessionState, m *MainModel) tea.Cmd {
 
 // likeNoteCmd handles liking/unliking a note
 func likeNoteCmd(accountId uuid.UUID, noteURI string, noteID uuid.UUID, isLocal bool, account *domain.Account) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+		target, err := readActionTarget(database, noteURI, noteID, isLocal)
+		if err != nil {
+			log.Printf("Failed to find post for like: %v", err)
+			return common.UpdateNoteList
+		}
+		hasLike, err := actions.HasLike(database, accountId, target)
+		if err != nil {
+			log.Printf("Failed to check existing like: %v", err)
+			return common.UpdateNoteList
+		}
+		conf, err := util.ReadConf()
+		if err != nil {
+			log.Printf("Failed to read config for like: %v", err)
+			return common.UpdateNoteList
+		}
+		if err := actions.SetLike(database, conf, account, target, !hasLike); err != nil {
+			log.Printf("Failed to update like: %v", err)
+		}
+		return common.UpdateNoteList
+	}
+}
+
+// readActionTarget looks up the post a like or boost message refers to
+func readActionTarget(database db.Store, noteURI string, noteID uuid.UUID, isLocal bool) (*actions.Target, error) {
+	if isLocal && noteID != uuid.Nil {
+		return actions.ReadTarget(database, noteID)
+	}
+	return actions.ReadTargetByURI(database, noteURI)
+}
+
+// reactNoteCmd handles adding, replacing and removing an emoji reaction on a note
+func reactNoteCmd(accountId uuid.UUID, msg common.ReactNoteMsg, account *domain.Account) tea.Cmd {
 	return func() tea.Msg {
 		database := db.GetDB()
 
@@ -1092,205 +1481,134 @@ func likeNoteCmd(accountId uuid.UUID, noteURI string, noteID uuid.UUID, isLocal
 		var actualNoteURI string
 		var isRemotePost bool
 
-		if isLocal && noteID != uuid.Nil {
-			actualNoteID = noteID
-			// Get the note's ObjectURI for federation
-			err, note := database.ReadNoteId(noteID)
+		noteURI := msg.NoteURI
+		if msg.IsLocal && msg.NoteID != uuid.Nil {
+			actualNoteID = msg.NoteID
+		} else if strings.HasPrefix(noteURI, "local:") {
+			parsedID, err := uuid.Parse(strings.TrimPrefix(noteURI, "local:"))
 			if err != nil {
-				log.Printf("Failed to read note for like: %v", err)
-				return common.UpdateNoteList
-			}
-			actualNoteURI = note.ObjectURI
-		} else if noteURI != "" && !strings.HasPrefix(noteURI, "local:") {
-			// Remote note - find it by ObjectURI
-			err, activity := database.ReadActivityByObjectURI(noteURI)
-			if err != nil || activity == nil {
-				log.Printf("Failed to find activity for like: %v", err)
+				log.Printf("Failed to parse local note ID: %v", err)
 				return common.UpdateNoteList
 			}
+			actualNoteID = parsedID
+		} else if noteURI != "" {
 			actualNoteURI = noteURI
 			isRemotePost = true
 			// Try to find a local note with this URI (federated back)
 			err, localNote := database.ReadNoteByURI(noteURI)
 			if err == nil && localNote != nil {
 				actualNoteID = localNote.Id
-				isRemotePost = false // It's actually a local post that was federated back
-			}
-		} else if strings.HasPrefix(noteURI, "local:") {
-			// Parse local: prefix
-			idStr := strings.TrimPrefix(noteURI, "local:")
-			parsedID, err := uuid.Parse(idStr)
-			if err != nil {
-				log.Printf("Failed to parse local note ID: %v", err)
-				return common.UpdateNoteList
+				isRemotePost = false
 			}
-			actualNoteID = parsedID
-			// Get the note's ObjectURI for federation
-			err, note := database.ReadNoteId(parsedID)
-			if err != nil {
-				log.Printf("Failed to read note for like: %v", err)
+		} else {
+			return common.UpdateNoteList
+		}
+
+		var note *domain.Note
+		if !isRemotePost {
+			var err error
+			err, note = database.ReadNoteId(actualNoteID)
+			if err != nil || note == nil {
+				log.Printf("Failed to read note for reaction: %v", err)
 				return common.UpdateNoteList
 			}
 			actualNoteURI = note.ObjectURI
 		}
 
-		// Check if we already liked this post
-		var hasLike bool
+		// Look up any existing reaction by this account
+		var existing *domain.Reaction
 		var err error
 		if isRemotePost {
-			hasLike, err = database.HasLikeByObjectURI(accountId, actualNoteURI)
+			err, existing = database.ReadReactionByAccountAndObjectURI(accountId, actualNoteURI)
 		} else {
-			hasLike, err = database.HasLike(accountId, actualNoteID)
+			err, existing = database.ReadReactionByAccountAndNote(accountId, actualNoteID)
 		}
 		if err != nil {
-			log.Printf("Failed to check existing like: %v", err)
+			log.Printf("Failed to check existing reaction: %v", err)
 			return common.UpdateNoteList
 		}
 
-		if hasLike {
-			// Unlike - remove the like
-			var existingLike *domain.Like
-			if isRemotePost {
-				err, existingLike = database.ReadLikeByAccountAndObjectURI(accountId, actualNoteURI)
-			} else {
-				err, existingLike = database.ReadLikeByAccountAndNote(accountId, actualNoteID)
-			}
-			if err != nil {
-				log.Printf("Failed to read existing like: %v", err)
-				return common.UpdateNoteList
-			}
+		conf, confErr := util.ReadConf()
+		federate := confErr == nil && conf.Conf.WithAp && actualNoteURI != ""
 
-			// Delete the like
-			if isRemotePost {
-				if err := database.DeleteLikeByAccountAndObjectURI(accountId, actualNoteURI); err != nil {
-					log.Printf("Failed to delete like: %v", err)
-					return common.UpdateNoteList
-				}
-				// Decrement like count on the activity
-				if err := database.DecrementLikeCountByObjectURI(actualNoteURI); err != nil {
-					log.Printf("Failed to decrement activity like count: %v", err)
-				}
-			} else {
-				if err := database.DeleteLikeByAccountAndNote(accountId, actualNoteID); err != nil {
-					log.Printf("Failed to delete like: %v", err)
-					return common.UpdateNoteList
-				}
-				// Decrement like count on the note
-				if err := database.DecrementLikeCountByNoteId(actualNoteID); err != nil {
-					log.Printf("Failed to decrement like count: %v", err)
-				}
+		if existing != nil {
+			if err := database.DeleteReactionById(existing.Id); err != nil {
+				log.Printf("Failed to delete reaction: %v", err)
+				return common.UpdateNoteList
 			}
+			log.Printf("Removed reaction %s from post %s", existing.Emoji, actualNoteURI)
 
-			log.Printf("Unliked post %s", actualNoteURI)
-
-			// Send Undo Like to remote server (background task)
-			if actualNoteURI != "" && existingLike != nil {
-				go func() {
-					conf, err := util.ReadConf()
-					if err != nil {
-						log.Printf("Failed to read config for unlike federation: %v", err)
-						return
-					}
-
-					if !conf.Conf.WithAp {
-						return
+			if federate && existing.URI != "" {
+				go func(r domain.Reaction) {
+					if err := activitypub.SendUndoEmojiReact(account, actualNoteURI, r.Emoji, r.URI, conf); err != nil {
+						log.Printf("Failed to federate reaction removal: %v", err)
 					}
-
-					if err := activitypub.SendUndoLike(account, actualNoteURI, existingLike.URI, conf); err != nil {
-						log.Printf("Failed to federate unlike: %v", err)
-					} else {
-						log.Printf("Unlike federated successfully")
-					}
-				}()
-			}
-		} else {
-			// Like - create a new like
-			likeURI := ""
-			conf, err := util.ReadConf()
-			if err == nil && conf.Conf.WithAp {
-				likeURI = fmt.Sprintf("https://%s/activities/%s", conf.Conf.SslDomain, uuid.New().String())
+				}(*existing)
 			}
 
-			like := &domain.Like{
-				Id:        uuid.New(),
-				AccountId: accountId,
-				NoteId:    actualNoteID, // Will be uuid.Nil for remote posts
-				URI:       likeURI,
-				CreatedAt: time.Now(),
+			// Picking the same emoji again just removes the reaction
+			if existing.Emoji == msg.Emoji {
+				return common.UpdateNoteList
 			}
+		}
 
-			// Create the like
-			if isRemotePost {
-				if err := database.CreateLikeByObjectURI(like, actualNoteURI); err != nil {
-					log.Printf("Failed to create like: %v", err)
-					return common.UpdateNoteList
-				}
-				// Increment like count on the activity
-				if err := database.IncrementLikeCountByObjectURI(actualNoteURI); err != nil {
-					log.Printf("Failed to increment activity like count: %v", err)
-				}
-			} else {
-				if err := database.CreateLike(like); err != nil {
-					log.Printf("Failed to create like: %v", err)
-					return common.UpdateNoteList
-				}
-				// Increment like count on the note
-				if err := database.IncrementLikeCountByNoteId(actualNoteID); err != nil {
-					log.Printf("Failed to increment like count: %v", err)
-				}
+		reactionURI := ""
+		if confErr == nil && conf.Conf.WithAp {
+			reactionURI = fmt.Sprintf("https://%s/activities/%s", conf.Conf.SslDomain, uuid.New().String())
+		}
 
-				// Create notification for local note author
-				err, note := database.ReadNoteId(actualNoteID)
-				if err == nil && note != nil {
-					err, noteAuthor := database.ReadAccByUsername(note.CreatedBy)
-					if err == nil && noteAuthor != nil && noteAuthor.Id != accountId {
-						// Only notify if liker is not the author
-						preview := note.Message
-						if len(preview) > 100 {
-							preview = preview[:100] + "..."
-						}
-						notification := &domain.Notification{
-							Id:               uuid.New(),
-							AccountId:        noteAuthor.Id,
-							NotificationType: domain.NotificationLike,
-							ActorId:          accountId,
-							ActorUsername:    account.Username,
-							ActorDomain:      "", // Empty for local users
-							NoteId:           note.Id,
-							NoteURI:          note.ObjectURI,
-							NotePreview:      preview,
-							Read:             false,
-							CreatedAt:        time.Now(),
-						}
-						if err := database.CreateNotification(notification); err != nil {
-							log.Printf("Failed to create like notification: %v", err)
-						}
-					}
+		reaction := &domain.Reaction{
+			Id:        uuid.New(),
+			AccountId: accountId,
+			NoteId:    actualNoteID, // uuid.Nil for remote posts
+			ObjectURI: actualNoteURI,
+			Emoji:     msg.Emoji,
+			URI:       reactionURI,
+			CreatedAt: time.Now(),
+		}
+		if isRemotePost {
+			reaction.NoteId = uuid.Nil
+		}
+		if err := database.CreateReaction(reaction); err != nil {
+			log.Printf("Failed to create reaction: %v", err)
+			return common.UpdateNoteList
+		}
+		log.Printf("Reacted %s to post %s", msg.Emoji, actualNoteURI)
+
+		// Create notification for local note author
+		if note != nil {
+			err, noteAuthor := database.ReadAccByUsername(note.CreatedBy)
+			if err == nil && noteAuthor != nil && noteAuthor.Id != accountId {
+				preview := util.TruncatePreview(note.Message, 100)
+				notification := &domain.Notification{
+					Id:               uuid.New(),
+					AccountId:        noteAuthor.Id,
+					NotificationType: domain.NotificationReaction,
+					ActorId:          accountId,
+					ActorUsername:    account.Username,
+					ActorDomain:      "", // Empty for local users
+					NoteId:           note.Id,
+					NoteURI:          note.ObjectURI,
+					NotePreview:      preview,
+					Emoji:            msg.Emoji,
+					Read:             false,
+					CreatedAt:        time.Now(),
+				}
+				if err := database.CreateNotification(notification); err != nil {
+					log.Printf("Failed to create reaction notification: %v", err)
 				}
 			}
+		}
 
-			log.Printf("Liked post %s", actualNoteURI)
-
-			// Send Like to remote server (background task)
-			if actualNoteURI != "" {
-				go func() {
-					conf, err := util.ReadConf()
-					if err != nil {
-						log.Printf("Failed to read config for like federation: %v", err)
-						return
-					}
-
-					if !conf.Conf.WithAp {
-						return
-					}
-
-					if err := activitypub.SendLike(account, actualNoteURI, conf); err != nil {
-						log.Printf("Failed to federate like: %v", err)
-					} else {
-						log.Printf("Like federated successfully")
-					}
-				}()
-			}
+		// Send EmojiReact to remote server (background task)
+		if federate && reactionURI != "" {
+			go func() {
+				if err := activitypub.SendEmojiReact(account, actualNoteURI, msg.Emoji, reactionURI, conf); err != nil {
+					log.Printf("Failed to federate reaction: %v", err)
+				} else {
+					log.Printf("Reaction federated successfully")
+				}
+			}()
 		}
 
 		return common.UpdateNoteList
@@ -1301,213 +1619,24 @@ func likeNoteCmd(accountId uuid.UUID, noteURI string, noteID uuid.UUID, isLocal
 func boostNoteCmd(accountId uuid.UUID, noteURI string, noteID uuid.UUID, isLocal bool, account *domain.Account) tea.Cmd {
 	return func() tea.Msg {
 		database := db.GetDB()
-
-		// Determine the actual note ID and URI to use
-		var actualNoteID uuid.UUID
-		var actualNoteURI string
-		var isRemotePost bool
-
-		if isLocal && noteID != uuid.Nil {
-			actualNoteID = noteID
-			// Get the note's ObjectURI for federation
-			err, note := database.ReadNoteId(noteID)
-			if err != nil {
-				log.Printf("Failed to read note for boost: %v", err)
-				return common.UpdateNoteList
-			}
-			actualNoteURI = note.ObjectURI
-		} else if noteURI != "" && !strings.HasPrefix(noteURI, "local:") {
-			// Remote note - find it by ObjectURI
-			err, activity := database.ReadActivityByObjectURI(noteURI)
-			if err != nil || activity == nil {
-				log.Printf("Failed to find activity for boost: %v", err)
-				return common.UpdateNoteList
-			}
-			actualNoteURI = noteURI
-			isRemotePost = true
-			// Try to find a local note with this URI (federated back)
-			err, localNote := database.ReadNoteByURI(noteURI)
-			if err == nil && localNote != nil {
-				actualNoteID = localNote.Id
-				isRemotePost = false // It's actually a local post that was federated back
-			}
-		} else if strings.HasPrefix(noteURI, "local:") {
-			// Parse local: prefix
-			idStr := strings.TrimPrefix(noteURI, "local:")
-			parsedID, err := uuid.Parse(idStr)
-			if err != nil {
-				log.Printf("Failed to parse local note ID: %v", err)
-				return common.UpdateNoteList
-			}
-			actualNoteID = parsedID
-			// Get the note's ObjectURI for federation
-			err, note := database.ReadNoteId(parsedID)
-			if err != nil {
-				log.Printf("Failed to read note for boost: %v", err)
-				return common.UpdateNoteList
-			}
-			actualNoteURI = note.ObjectURI
-		}
-
-		// Check if we already boosted this post
-		var hasBoost bool
-		var err error
-		if isRemotePost {
-			hasBoost, err = database.HasBoostByObjectURI(accountId, actualNoteURI)
-		} else {
-			hasBoost, err = database.HasBoost(accountId, actualNoteID)
+		target, err := readActionTarget(database, noteURI, noteID, isLocal)
+		if err != nil {
+			log.Printf("Failed to find post for boost: %v", err)
+			return common.UpdateNoteList
 		}
+		hasBoost, err := actions.HasBoost(database, accountId, target)
 		if err != nil {
 			log.Printf("Failed to check existing boost: %v", err)
 			return common.UpdateNoteList
 		}
-
-		if hasBoost {
-			// Unboost - remove the boost
-			var existingBoost *domain.Boost
-			if isRemotePost {
-				err, existingBoost = database.ReadBoostByAccountAndObjectURI(accountId, actualNoteURI)
-			} else {
-				err, existingBoost = database.ReadBoostByAccountAndNote(accountId, actualNoteID)
-			}
-			if err != nil {
-				log.Printf("Failed to read existing boost: %v", err)
-				return common.UpdateNoteList
-			}
-
-			// Delete the boost
-			if isRemotePost {
-				if err := database.DeleteBoostByAccountAndObjectURI(accountId, actualNoteURI); err != nil {
-					log.Printf("Failed to delete boost: %v", err)
-					return common.UpdateNoteList
-				}
-				// Decrement boost count on the activity
-				if err := database.DecrementBoostCountByObjectURI(actualNoteURI); err != nil {
-					log.Printf("Failed to decrement activity boost count: %v", err)
-				}
-			} else {
-				if err := database.DeleteBoostByAccountAndNote(accountId, actualNoteID); err != nil {
-					log.Printf("Failed to delete boost: %v", err)
-					return common.UpdateNoteList
-				}
-				// Decrement boost count on the note
-				if err := database.DecrementBoostCountByNoteId(actualNoteID); err != nil {
-					log.Printf("Failed to decrement boost count: %v", err)
-				}
-			}
-
-			log.Printf("Unboosted post %s", actualNoteURI)
-
-			// Send Undo Announce to remote server (background task)
-			if actualNoteURI != "" && existingBoost != nil {
-				go func() {
-					conf, err := util.ReadConf()
-					if err != nil {
-						log.Printf("Failed to read config for unboost federation: %v", err)
-						return
-					}
-
-					if !conf.Conf.WithAp {
-						return
-					}
-
-					if err := activitypub.SendUndoAnnounce(account, actualNoteURI, existingBoost.URI, conf); err != nil {
-						log.Printf("Failed to federate unboost: %v", err)
-					} else {
-						log.Printf("Unboost federated successfully")
-					}
-				}()
-			}
-		} else {
-			// Boost - create a new boost
-			boostURI := ""
-			conf, err := util.ReadConf()
-			if err == nil && conf.Conf.WithAp {
-				boostURI = fmt.Sprintf("https://%s/activities/%s", conf.Conf.SslDomain, uuid.New().String())
-			}
-
-			boost := &domain.Boost{
-				Id:        uuid.New(),
-				AccountId: accountId,
-				NoteId:    actualNoteID, // Will be uuid.Nil for remote posts
-				URI:       boostURI,
-				CreatedAt: time.Now(),
-			}
-
-			// Create the boost
-			if isRemotePost {
-				if err := database.CreateBoostByObjectURI(boost, actualNoteURI); err != nil {
-					log.Printf("Failed to create boost: %v", err)
-					return common.UpdateNoteList
-				}
-				// Increment boost count on the activity
-				if err := database.IncrementBoostCountByObjectURI(actualNoteURI); err != nil {
-					log.Printf("Failed to increment activity boost count: %v", err)
-				}
-			} else {
-				if err := database.CreateBoost(boost); err != nil {
-					log.Printf("Failed to create boost: %v", err)
-					return common.UpdateNoteList
-				}
-				// Increment boost count on the note
-				if err := database.IncrementBoostCountByNoteId(actualNoteID); err != nil {
-					log.Printf("Failed to increment boost count: %v", err)
-				}
-
-				// Create notification for local note author
-				err, note := database.ReadNoteId(actualNoteID)
-				if err == nil && note != nil {
-					err, noteAuthor := database.ReadAccByUsername(note.CreatedBy)
-					if err == nil && noteAuthor != nil && noteAuthor.Id != accountId {
-						// Only notify if booster is not the author
-						preview := note.Message
-						if len(preview) > 100 {
-							preview = preview[:100] + "..."
-						}
-						notification := &domain.Notification{
-							Id:               uuid.New(),
-							AccountId:        noteAuthor.Id,
-							NotificationType: domain.NotificationBoost,
-							ActorId:          accountId,
-							ActorUsername:    account.Username,
-							ActorDomain:      "", // Empty for local users
-							NoteId:           note.Id,
-							NoteURI:          note.ObjectURI,
-							NotePreview:      preview,
-							Read:             false,
-							CreatedAt:        time.Now(),
-						}
-						if err := database.CreateNotification(notification); err != nil {
-							log.Printf("Failed to create boost notification: %v", err)
-						}
-					}
-				}
-			}
-
-			log.Printf("Boosted post %s", actualNoteURI)
-
-			// Send Announce to remote server (background task)
-			if actualNoteURI != "" {
-				go func() {
-					conf, err := util.ReadConf()
-					if err != nil {
-						log.Printf("Failed to read config for boost federation: %v", err)
-						return
-					}
-
-					if !conf.Conf.WithAp {
-						return
-					}
-
-					if err := activitypub.SendAnnounce(account, actualNoteURI, boostURI, conf); err != nil {
-						log.Printf("Failed to federate boost: %v", err)
-					} else {
-						log.Printf("Boost federated successfully")
-					}
-				}()
-			}
+		conf, err := util.ReadConf()
+		if err != nil {
+			log.Printf("Failed to read config for boost: %v", err)
+			return common.UpdateNoteList
+		}
+		if err := actions.SetBoost(database, conf, account, target, !hasBoost); err != nil {
+			log.Printf("Failed to update boost: %v", err)
 		}
-
 		return common.UpdateNoteList
 	}
 }
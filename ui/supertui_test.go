@@ -535,6 +535,43 @@ func TestShiftTabNavigationWithTimelineActivation(t *testing.T) {
 	// The important thing is no panic occurred
 }
 
+// TestTabNavigationThroughListTimelines verifies that each list gets its own
+// tab between home and my posts, in both directions
+func TestTabNavigationThroughListTimelines(t *testing.T) {
+	account := domain.Account{
+		Id:       uuid.New(),
+		Username: "testuser",
+	}
+
+	model := NewModel(account, 100, 30)
+	updatedModel, _ := model.Update(userListsLoadedMsg{lists: []domain.List{
+		{Id: uuid.New(), Name: "friends"},
+		{Id: uuid.New(), Name: "news"},
+	}})
+	mainModel := updatedModel.(MainModel)
+	mainModel.state = common.HomeTimelineView
+
+	for _, name := range []string{"friends", "news"} {
+		updatedModel, _ = mainModel.Update(tea.KeyMsg{Type: tea.KeyTab})
+		mainModel = updatedModel.(MainModel)
+		if mainModel.state != common.ListTimelineView || mainModel.listTimelineModel.ListName != name {
+			t.Fatalf("Expected list timeline %q, got state %v list %q", name, mainModel.state, mainModel.listTimelineModel.ListName)
+		}
+	}
+
+	updatedModel, _ = mainModel.Update(tea.KeyMsg{Type: tea.KeyTab})
+	mainModel = updatedModel.(MainModel)
+	if mainModel.state != common.MyPostsView {
+		t.Errorf("Expected MyPostsView after the last list, got %v", mainModel.state)
+	}
+
+	updatedModel, _ = mainModel.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	mainModel = updatedModel.(MainModel)
+	if mainModel.state != common.ListTimelineView || mainModel.listTimelineModel.ListName != "news" {
+		t.Errorf("Expected last list after shift-tab from MyPosts, got state %v list %q", mainModel.state, mainModel.listTimelineModel.ListName)
+	}
+}
+
 // TestNKeyNavigationToNotifications verifies 'n' key navigates to notifications
 // and handles timeline deactivation correctly
 func TestNKeyNavigationToNotifications(t *testing.T) {
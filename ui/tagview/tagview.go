@@ -0,0 +1,414 @@
+package tagview
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/ui/common"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+var (
+	tagStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(common.COLOR_HASHTAG)).
+			Bold(true)
+
+	metadataStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(common.COLOR_DIM))
+
+	followBadgeStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(common.COLOR_SUCCESS))
+
+	notFollowBadgeStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(common.COLOR_DIM))
+
+	separatorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(common.COLOR_DIM))
+
+	postTimeStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(common.COLOR_DIM))
+
+	postAuthorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(common.COLOR_USERNAME)).
+			Bold(true)
+
+	postContentStyle = lipgloss.NewStyle()
+
+	selectedPostTimeStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(common.COLOR_WHITE))
+
+	selectedPostAuthorStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(common.COLOR_WHITE)).
+				Bold(true)
+
+	selectedPostContentStyle = lipgloss.NewStyle().
+					Foreground(lipgloss.Color(common.COLOR_WHITE))
+
+	emptyStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(common.COLOR_DIM)).
+			Italic(true)
+)
+
+const maxTagPosts = 50
+
+type Model struct {
+	AccountId    uuid.UUID
+	Tag          string
+	Posts        []domain.HomePost
+	FollowedTags []string
+	IsFollowing  bool
+	Selected     int
+	Offset       int
+	Width        int
+	Height       int
+	loading      bool
+	Status       string
+	Error        string
+	LocalDomain  string
+	ReturnView   common.SessionState // View to return to on Esc (default: HomeTimelineView)
+}
+
+func InitialModel(accountId uuid.UUID, width, height int, localDomain string) Model {
+	return Model{
+		AccountId:   accountId,
+		Posts:       []domain.HomePost{},
+		Width:       width,
+		Height:      height,
+		LocalDomain: localDomain,
+		ReturnView:  common.HomeTimelineView,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// tagLoadedMsg is sent when the posts and follow status for a tag are loaded
+type tagLoadedMsg struct {
+	tag          string
+	posts        []domain.HomePost
+	isFollowing  bool
+	followedTags []string
+	err          error
+}
+
+// clearStatusMsg is sent after a delay to clear status messages
+type clearStatusMsg struct{}
+
+// followTagToggledMsg is sent after follow/unfollow of the tag completes
+type followTagToggledMsg struct {
+	isFollowing  bool
+	tag          string
+	followedTags []string
+	err          error
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case common.ViewTagMsg:
+		m.Tag = msg.Tag
+		m.loading = true
+		m.Error = ""
+		m.Status = ""
+		m.Selected = 0
+		m.Offset = 0
+		m.Posts = nil
+		return m, loadTag(m.AccountId, msg.Tag)
+
+	case tagLoadedMsg:
+		// Ignore stale results if another tag was opened in the meantime
+		if msg.tag != m.Tag {
+			return m, nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.Error = msg.err.Error()
+			return m, nil
+		}
+		m.Posts = msg.posts
+		m.IsFollowing = msg.isFollowing
+		m.FollowedTags = msg.followedTags
+		m.Selected = 0
+		m.Offset = 0
+		return m, nil
+
+	case followTagToggledMsg:
+		if msg.err != nil {
+			m.Error = fmt.Sprintf("Failed to toggle follow: %v", msg.err)
+			return m, clearStatusAfter(2 * time.Second)
+		}
+		m.IsFollowing = msg.isFollowing
+		m.FollowedTags = msg.followedTags
+		if msg.isFollowing {
+			m.Status = fmt.Sprintf("Following #%s", msg.tag)
+		} else {
+			m.Status = fmt.Sprintf("Unfollowed #%s", msg.tag)
+		}
+		m.Error = ""
+		return m, clearStatusAfter(2 * time.Second)
+
+	case clearStatusMsg:
+		m.Status = ""
+		m.Error = ""
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if m.Selected > 0 {
+				m.Selected--
+				m.Offset = m.Selected
+			}
+		case "down", "j":
+			if m.Selected < len(m.Posts)-1 {
+				m.Selected++
+				m.Offset = m.Selected
+			}
+		case "enter":
+			// View thread for selected post
+			if len(m.Posts) > 0 && m.Selected >= 0 && m.Selected < len(m.Posts) {
+				post := m.Posts[m.Selected]
+				noteURI := post.ObjectURI
+				if noteURI == "" && post.IsLocal {
+					noteURI = "local:" + post.NoteID.String()
+				}
+				if noteURI == "" {
+					return m, nil
+				}
+				return m, func() tea.Msg {
+					return common.ViewThreadMsg{
+						NoteURI:   noteURI,
+						NoteID:    post.NoteID,
+						IsLocal:   post.IsLocal,
+						Author:    post.Author,
+						Content:   post.Content,
+						CreatedAt: post.Time,
+					}
+				}
+			}
+		case "f":
+			// Toggle follow/unfollow of the tag
+			if m.Tag != "" && !m.loading {
+				return m, toggleFollowTag(m.AccountId, m.Tag, m.IsFollowing)
+			}
+		case "esc":
+			returnView := m.ReturnView
+			return m, func() tea.Msg {
+				return returnView
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	var s strings.Builder
+
+	s.WriteString(common.CaptionStyle.Render("tag"))
+	s.WriteString("\n")
+
+	if m.Tag == "" {
+		s.WriteString(emptyStyle.Render("No tag selected"))
+		return s.String()
+	}
+
+	if m.loading {
+		s.WriteString(emptyStyle.Render("Loading #" + m.Tag + "..."))
+		return s.String()
+	}
+
+	// Calculate content width
+	leftPanelWidth := common.CalculateLeftPanelWidth(m.Width)
+	rightPanelWidth := common.CalculateRightPanelWidth(m.Width, leftPanelWidth)
+	contentWidth := common.CalculateContentWidth(rightPanelWidth, 2)
+
+	// Tag header: name + follow status
+	var followBadge string
+	if m.IsFollowing {
+		followBadge = followBadgeStyle.Render("following")
+	} else {
+		followBadge = notFollowBadgeStyle.Render("not following")
+	}
+	s.WriteString(tagStyle.Render("#"+m.Tag) + metadataStyle.Render(" · ") + followBadge)
+	s.WriteString("\n")
+
+	if len(m.FollowedTags) > 0 {
+		followed := make([]string, len(m.FollowedTags))
+		for i, tag := range m.FollowedTags {
+			followed[i] = "#" + tag
+		}
+		s.WriteString(metadataStyle.Render("followed tags: " + strings.Join(followed, " ")))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+
+	// Separator
+	sep := strings.Repeat("─", contentWidth)
+	s.WriteString(separatorStyle.Render(sep))
+	s.WriteString("\n")
+
+	postCount := len(m.Posts)
+	s.WriteString(common.CaptionStyle.Render(fmt.Sprintf("recent posts (%d)", postCount)))
+	s.WriteString("\n")
+
+	if postCount == 0 {
+		s.WriteString(emptyStyle.Render("No posts with this tag yet."))
+		s.WriteString("\n")
+	} else {
+		start := m.Offset
+		end := min(start+common.DefaultItemsPerPage, postCount)
+
+		for i := start; i < end; i++ {
+			post := m.Posts[i]
+			isSelected := i == m.Selected
+
+			timeStr := formatTime(post.Time)
+
+			author := post.Author
+			if !strings.HasPrefix(author, "@") {
+				author = "@" + author
+			}
+
+			processedContent := post.Content
+			processedContent = util.TruncateContent(processedContent, common.MaxDisplayContentLength)
+			if post.IsLocal {
+				processedContent = util.UnescapeHTML(processedContent)
+				processedContent = util.MarkdownLinksToTerminal(processedContent)
+			} else {
+				processedContent = util.NormalizeEmojis(processedContent)
+			}
+			processedContent = util.LinkifyRawURLsTerminal(processedContent)
+			processedContent = util.CustomEmojiToTerminal(processedContent, post.Emojis, "https://"+m.LocalDomain)
+			highlightedContent := util.HighlightHashtagsTerminal(processedContent)
+			highlightedContent = util.HighlightMentionsTerminal(highlightedContent, m.LocalDomain)
+
+			if isSelected {
+				selectedBg := lipgloss.NewStyle().
+					Background(lipgloss.Color(common.COLOR_ACCENT)).
+					Width(contentWidth)
+
+				s.WriteString(selectedBg.Render(selectedPostTimeStyle.Render(timeStr)) + "\n")
+				s.WriteString(selectedBg.Render(selectedPostAuthorStyle.Render(author)) + "\n")
+				s.WriteString(selectedBg.Render(selectedPostContentStyle.Render(highlightedContent)))
+			} else {
+				unselectedStyle := lipgloss.NewStyle().Width(contentWidth)
+
+				s.WriteString(unselectedStyle.Render(postTimeStyle.Render(timeStr)) + "\n")
+				s.WriteString(unselectedStyle.Render(postAuthorStyle.Render(author)) + "\n")
+				s.WriteString(unselectedStyle.Render(postContentStyle.Render(highlightedContent)))
+			}
+			s.WriteString("\n\n")
+		}
+
+		// Pagination info
+		if postCount > common.DefaultItemsPerPage {
+			paginationText := fmt.Sprintf("showing %d-%d of %d", start+1, end, postCount)
+			s.WriteString(common.ListBadgeStyle.Render(paginationText))
+			s.WriteString("\n")
+		}
+	}
+
+	if m.Status != "" {
+		s.WriteString(common.ListStatusStyle.Render(m.Status))
+		s.WriteString("\n")
+	}
+
+	if m.Error != "" {
+		s.WriteString(common.ListErrorStyle.Render(m.Error))
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+// loadTag fetches posts carrying the tag and the viewer's follow status
+func loadTag(accountId uuid.UUID, tag string) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+
+		err, posts := database.ReadHashtagTimelinePosts(tag, domain.Cursor{}, maxTagPosts)
+		if err != nil {
+			log.Printf("Failed to load posts for #%s: %v", tag, err)
+			return tagLoadedMsg{tag: tag, err: fmt.Errorf("failed to load posts for #%s", tag)}
+		}
+
+		isFollowing, err := database.IsFollowingHashtag(accountId, tag)
+		if err != nil {
+			log.Printf("Failed to check hashtag follow status: %v", err)
+			isFollowing = false
+		}
+
+		err, followedTags := database.ReadFollowedHashtags(accountId)
+		if err != nil {
+			log.Printf("Failed to read followed hashtags: %v", err)
+		}
+
+		return tagLoadedMsg{
+			tag:          tag,
+			posts:        *posts,
+			isFollowing:  isFollowing,
+			followedTags: followedTags,
+		}
+	}
+}
+
+// toggleFollowTag follows or unfollows a hashtag
+func toggleFollowTag(accountId uuid.UUID, tag string, isFollowing bool) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+
+		var err error
+		if isFollowing {
+			err = database.UnfollowHashtag(accountId, tag)
+		} else {
+			err = database.FollowHashtag(accountId, tag)
+		}
+		if err != nil {
+			return followTagToggledMsg{err: err}
+		}
+
+		err, followedTags := database.ReadFollowedHashtags(accountId)
+		if err != nil {
+			log.Printf("Failed to read followed hashtags: %v", err)
+		}
+
+		return followTagToggledMsg{isFollowing: !isFollowing, tag: tag, followedTags: followedTags}
+	}
+}
+
+func clearStatusAfter(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return clearStatusMsg{}
+	})
+}
+
+func formatTime(t time.Time) string {
+	duration := time.Since(t)
+
+	if duration < time.Minute {
+		return "just now"
+	} else if duration < time.Hour {
+		mins := int(duration.Minutes())
+		return fmt.Sprintf("%dm ago", mins)
+	} else if duration < common.HoursPerDay*time.Hour {
+		hours := int(duration.Hours())
+		return fmt.Sprintf("%dh ago", hours)
+	} else {
+		days := int(duration.Hours() / common.HoursPerDay)
+		return fmt.Sprintf("%dd ago", days)
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,148 @@
+package tagview
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/ui/common"
+	"github.com/google/uuid"
+)
+
+func TestInitialModel(t *testing.T) {
+	accountId := uuid.New()
+	m := InitialModel(accountId, 120, 40, "example.com")
+
+	if m.AccountId != accountId {
+		t.Errorf("Expected AccountId %v, got %v", accountId, m.AccountId)
+	}
+	if m.Tag != "" {
+		t.Errorf("Expected empty Tag, got %q", m.Tag)
+	}
+	if m.ReturnView != common.HomeTimelineView {
+		t.Errorf("Expected ReturnView HomeTimelineView, got %v", m.ReturnView)
+	}
+	if m.LocalDomain != "example.com" {
+		t.Errorf("Expected LocalDomain 'example.com', got '%s'", m.LocalDomain)
+	}
+}
+
+func TestUpdate_ViewTagMsg(t *testing.T) {
+	m := InitialModel(uuid.New(), 120, 40, "")
+
+	m, cmd := m.Update(common.ViewTagMsg{Tag: "golang"})
+
+	if m.Tag != "golang" {
+		t.Errorf("Expected Tag 'golang', got %q", m.Tag)
+	}
+	if !m.loading {
+		t.Error("Expected loading to be true after ViewTagMsg")
+	}
+	if cmd == nil {
+		t.Error("Expected a load command to be returned")
+	}
+}
+
+func TestUpdate_TagLoaded(t *testing.T) {
+	m := InitialModel(uuid.New(), 120, 40, "")
+	m.Tag = "golang"
+	m.loading = true
+
+	posts := []domain.HomePost{
+		{ID: uuid.New(), Author: "alice", Content: "Hello #golang", Time: time.Now(), IsLocal: true},
+	}
+	m, _ = m.Update(tagLoadedMsg{tag: "golang", posts: posts, isFollowing: true, followedTags: []string{"golang"}})
+
+	if m.loading {
+		t.Error("Expected loading to be false")
+	}
+	if len(m.Posts) != 1 {
+		t.Errorf("Expected 1 post, got %d", len(m.Posts))
+	}
+	if !m.IsFollowing {
+		t.Error("Expected IsFollowing to be true")
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "#golang") {
+		t.Error("Expected tag name in view")
+	}
+	if !strings.Contains(view, "following") {
+		t.Error("Expected follow badge in view")
+	}
+}
+
+func TestUpdate_TagLoaded_IgnoresStaleTag(t *testing.T) {
+	m := InitialModel(uuid.New(), 120, 40, "")
+	m.Tag = "rust"
+	m.loading = true
+
+	m, _ = m.Update(tagLoadedMsg{tag: "golang", posts: []domain.HomePost{{Author: "alice"}}})
+
+	if !m.loading {
+		t.Error("Expected stale result to be ignored")
+	}
+	if len(m.Posts) != 0 {
+		t.Errorf("Expected no posts, got %d", len(m.Posts))
+	}
+}
+
+func TestUpdate_FollowTagToggled(t *testing.T) {
+	m := InitialModel(uuid.New(), 120, 40, "")
+	m.Tag = "golang"
+
+	m, cmd := m.Update(followTagToggledMsg{isFollowing: true, tag: "golang", followedTags: []string{"golang"}})
+	if !m.IsFollowing {
+		t.Error("Expected IsFollowing to be true")
+	}
+	if !strings.Contains(m.Status, "Following #golang") {
+		t.Errorf("Expected follow status, got %q", m.Status)
+	}
+	if cmd == nil {
+		t.Error("Expected clear status command")
+	}
+
+	m, _ = m.Update(followTagToggledMsg{err: errors.New("boom")})
+	if !m.IsFollowing {
+		t.Error("Expected follow state to be unchanged on error")
+	}
+	if m.Error == "" {
+		t.Error("Expected error to be set")
+	}
+}
+
+func TestUpdate_EscReturnsToReturnView(t *testing.T) {
+	m := InitialModel(uuid.New(), 120, 40, "")
+	m.ReturnView = common.GlobalPostsView
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("Expected a command on esc")
+	}
+	if msg := cmd(); msg != common.GlobalPostsView {
+		t.Errorf("Expected GlobalPostsView, got %v", msg)
+	}
+}
+
+func TestUpdate_EnterOpensThread(t *testing.T) {
+	m := InitialModel(uuid.New(), 120, 40, "")
+	noteId := uuid.New()
+	m.Posts = []domain.HomePost{
+		{ID: noteId, NoteID: noteId, Author: "alice", Content: "Hello", Time: time.Now(), IsLocal: true},
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected a command on enter")
+	}
+	msg, ok := cmd().(common.ViewThreadMsg)
+	if !ok {
+		t.Fatal("Expected ViewThreadMsg")
+	}
+	if msg.NoteURI != "local:"+noteId.String() {
+		t.Errorf("Expected local note URI, got %s", msg.NoteURI)
+	}
+}
@@ -82,6 +82,8 @@ type ThreadPost struct {
 	ReplyCount int    // Number of replies to this post
 	LikeCount  int    // Number of likes on this post
 	BoostCount int    // Number of boosts on this post
+
+	FilterWarning string // If non-empty, the post matched this keyword filter phrase and is collapsed
 }
 
 // Model represents the thread view state
@@ -109,6 +111,7 @@ type Model struct {
 	pendingOffset    int    // Offset to restore after reload
 	LocalDomain      string // Cached local domain for mention highlighting
 	ReturnView       common.SessionState // View to return to on Esc (default: HomeTimelineView)
+	revealed         map[uuid.UUID]bool  // Posts collapsed by a keyword filter that the user chose to show
 }
 
 // InitialModel creates a new thread view model
@@ -129,6 +132,7 @@ func InitialModel(accountId uuid.UUID, width, height int, localDomain string) Mo
 		pendingOffset:    -2,
 		LocalDomain:      localDomain,
 		ReturnView:       common.HomeTimelineView,
+		revealed:         map[uuid.UUID]bool{},
 	}
 }
 
@@ -444,6 +448,50 @@ func loadThreadByID(noteID uuid.UUID, noteURI string, author string, content str
 	}
 }
 
+// withFilters applies the account's keyword filters (thread context) to the thread loaded by load
+func withFilters(accountId uuid.UUID, load tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		msg := load()
+		loaded, ok := msg.(threadLoadedMsg)
+		if !ok || loaded.err != nil {
+			return msg
+		}
+		err, filters := db.GetDB().ReadFiltersByAccountId(accountId)
+		if err != nil {
+			log.Printf("Failed to load keyword filters: %v", err)
+			return msg
+		}
+		return applyFilters(loaded, *filters, time.Now())
+	}
+}
+
+// applyFilters drops replies matching hide filters and collapses replies matching warn filters.
+// The parent post is never dropped, since the thread would be empty without it; it is only collapsed.
+func applyFilters(msg threadLoadedMsg, filters []domain.Filter, now time.Time) threadLoadedMsg {
+	if len(filters) == 0 {
+		return msg
+	}
+	if msg.parent != nil {
+		parent := *msg.parent
+		if f, ok := domain.MatchFilters(filters, domain.FilterContextThread, parent.Content, now); ok {
+			parent.FilterWarning = f.Phrase
+		}
+		msg.parent = &parent
+	}
+	replies := make([]ThreadPost, 0, len(msg.replies))
+	for _, reply := range msg.replies {
+		if f, ok := domain.MatchFilters(filters, domain.FilterContextThread, reply.Content, now); ok {
+			if f.Action == domain.FilterActionHide {
+				continue
+			}
+			reply.FilterWarning = f.Phrase
+		}
+		replies = append(replies, reply)
+	}
+	msg.replies = replies
+	return msg
+}
+
 // parseActivityContent extracts content and author from an activity's raw JSON
 func parseActivityContent(activity *domain.Activity) (string, string) {
 	content := ""
@@ -494,9 +542,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			m.pendingOffset = m.Offset
 			// Reload the thread to get updated like counts
 			if m.parentIsLocal && m.parentNoteID != uuid.Nil {
-				return m, loadThreadByID(m.parentNoteID, m.ParentURI, m.parentAuthor, m.parentContent, m.parentCreatedAt)
+				return m, withFilters(m.AccountId, loadThreadByID(m.parentNoteID, m.ParentURI, m.parentAuthor, m.parentContent, m.parentCreatedAt))
 			}
-			return m, loadThread(m.ParentURI)
+			return m, withFilters(m.AccountId, loadThread(m.ParentURI))
 		}
 		return m, nil
 
@@ -512,9 +560,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.parentCreatedAt = msg.CreatedAt
 		// For local notes, use loadThreadByID which doesn't rely on object_uri in DB
 		if msg.IsLocal && msg.NoteID != uuid.Nil {
-			return m, loadThreadByID(msg.NoteID, msg.NoteURI, msg.Author, msg.Content, msg.CreatedAt)
+			return m, withFilters(m.AccountId, loadThreadByID(msg.NoteID, msg.NoteURI, msg.Author, msg.Content, msg.CreatedAt))
 		}
-		return m, loadThread(msg.NoteURI)
+		return m, withFilters(m.AccountId, loadThread(msg.NoteURI))
 
 	case threadLoadedMsg:
 		m.loading = false
@@ -638,6 +686,18 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					}
 				}
 			}
+		case "v":
+			// Show or re-collapse a post hidden behind a keyword filter warning
+			post := m.ParentPost
+			if m.Selected >= 0 && m.Selected < len(m.Replies) {
+				post = &m.Replies[m.Selected]
+			}
+			if post != nil && post.FilterWarning != "" {
+				if m.revealed == nil {
+					m.revealed = map[uuid.UUID]bool{}
+				}
+				m.revealed[post.ID] = !m.revealed[post.ID]
+			}
 		case "esc", "q":
 			// Go back to the view that opened this thread
 			returnView := m.ReturnView
@@ -845,6 +905,9 @@ func (m Model) View() string {
 		processedContent = util.LinkifyRawURLsTerminal(processedContent)
 		highlightedContent := util.HighlightHashtagsTerminal(processedContent)
 		highlightedContent = util.HighlightMentionsTerminal(highlightedContent, m.LocalDomain)
+		if post.FilterWarning != "" && !m.revealed[post.ID] {
+			highlightedContent = common.FilterWarning(post.FilterWarning)
+		}
 
 		if isSelected {
 			// Create a style that fills the full width (same approach as myposts/hometimeline)
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/deemkeen/stegodon/domain"
 	"github.com/deemkeen/stegodon/ui/common"
 	"github.com/deemkeen/stegodon/util"
 	"github.com/google/uuid"
@@ -777,3 +778,47 @@ func TestView_ReplyIndentAdaptiveWidth(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyFilters(t *testing.T) {
+	parent := &ThreadPost{ID: uuid.New(), Content: "Spoilers for the finale", IsParent: true}
+	msg := threadLoadedMsg{
+		parent: parent,
+		replies: []ThreadPost{
+			{ID: uuid.New(), Content: "no spoilers here please"},
+			{ID: uuid.New(), Content: "buy crypto now"},
+			{ID: uuid.New(), Content: "nice post"},
+		},
+	}
+	filters := []domain.Filter{
+		{Phrase: "spoilers", Contexts: []string{domain.FilterContextThread}, Action: domain.FilterActionWarn},
+		{Phrase: "crypto", Contexts: []string{domain.FilterContextThread}, Action: domain.FilterActionHide},
+	}
+
+	filtered := applyFilters(msg, filters, time.Now())
+
+	// The parent is collapsed even by a hide filter, never dropped
+	if filtered.parent == nil || filtered.parent.FilterWarning != "spoilers" {
+		t.Errorf("Expected parent collapsed with warning, got %+v", filtered.parent)
+	}
+	if parent.FilterWarning != "" {
+		t.Error("applyFilters should not modify the original parent")
+	}
+	if len(filtered.replies) != 2 {
+		t.Fatalf("Expected hidden reply to be removed, got %d replies", len(filtered.replies))
+	}
+	if filtered.replies[0].FilterWarning != "spoilers" || filtered.replies[1].FilterWarning != "" {
+		t.Errorf("Unexpected warnings: %q, %q", filtered.replies[0].FilterWarning, filtered.replies[1].FilterWarning)
+	}
+
+	// v reveals and re-collapses the selected post
+	m := InitialModel(uuid.New(), 120, 40, "example.com")
+	m, _ = m.Update(filtered)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	if !m.revealed[parent.ID] {
+		t.Error("Expected parent to be revealed after v")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	if m.revealed[parent.ID] {
+		t.Error("Expected parent to be collapsed again after second v")
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/deemkeen/stegodon/activitypub"
@@ -20,6 +21,13 @@ import (
 
 const maxAutocompleteSuggestions = 5
 
+// draftAutosaveDelay is how long after a change the draft is saved
+const draftAutosaveDelay = 3 * time.Second
+
+// noteMaxHeight is the textarea line limit for short notes (the textarea default);
+// articles have no line limit
+const noteMaxHeight = 99
+
 // maxLetters is the maximum number of visible characters allowed in a note
 // This value is loaded from configuration (with STEGODON_MAX_CHARS env var support)
 // and stored here for efficient access without repeated function calls
@@ -65,6 +73,22 @@ type Model struct {
 	autocompleteIndex      int                // Currently selected suggestion
 	mentionStartPos        int                // Position where @ was typed
 	localDomain            string             // Local domain for identifying local users
+	// Schedule fields
+	scheduleInput      textinput.Model // Publish time (+2h, 07:30, 2006-01-02 15:04)
+	showSchedule       bool            // True when the schedule field is visible
+	editingScheduledId uuid.UUID       // ID of scheduled note being edited
+	Status             string          // Confirmation message to display
+	// Draft fields
+	draftId          uuid.UUID // ID of the draft being written (uuid.Nil until first autosave)
+	draftCreatedAt   time.Time // Creation time of the draft
+	draftDirty       bool      // True when the text changed since the last autosave
+	draftSavePending bool      // True while an autosave tick is scheduled
+	draftChecked     bool      // True once the last draft was looked up for restoring
+	// Article fields
+	isArticle  bool            // True when writing a long-form article
+	titleInput textinput.Model // Article title
+	// Thread fields
+	isThread bool // True when long text is split into a thread of notes
 	// Server message
 	serverMessage *domain.ServerMessage // Message from server admin to display
 }
@@ -97,6 +121,18 @@ func InitialNote(contentWidth int, userId uuid.UUID) Model {
 	// Load autocomplete candidates
 	candidates := loadAutocompleteCandidates(localDomain)
 
+	si := textinput.New()
+	si.Placeholder = "+2h, 07:30 or " + util.ScheduleTimeFormat
+	si.Prompt = "publish at: "
+	si.CharLimit = 25
+	si.Width = 20
+
+	tti := textinput.New()
+	tti.Placeholder = "article title"
+	tti.Prompt = "title: "
+	tti.CharLimit = util.MaxArticleTitleLength
+	tti.Width = common.TextInputDefaultWidth - len(tti.Prompt)
+
 	return Model{
 		Textarea:               ti,
 		Err:                    nil,
@@ -117,6 +153,10 @@ func InitialNote(contentWidth int, userId uuid.UUID) Model {
 		autocompleteIndex:      0,
 		mentionStartPos:        -1,
 		localDomain:            localDomain,
+		scheduleInput:          si,
+		showSchedule:           false,
+		editingScheduledId:     uuid.Nil,
+		titleInput:             tti,
 	}
 }
 
@@ -156,169 +196,143 @@ func createNoteModelCmd(note *domain.SaveNote) tea.Cmd {
 	return func() tea.Msg {
 		database := db.GetDB()
 
-		// Create note in database and get the created note ID
-		// Use CreateNoteWithReply to support replies
-		noteId, err := database.CreateNoteWithReply(note.UserId, note.Message, note.InReplyToURI)
+		noteId, err := saveNote(database, note)
 		if err != nil {
 			log.Println("Note could not be saved!")
 			return common.UpdateNoteList
 		}
 
-		// Create reply notification if replying to a local note
-		if note.InReplyToURI != "" {
-			// Check if this is a local note URI
-			if strings.HasPrefix(note.InReplyToURI, "local:") || strings.HasPrefix(note.InReplyToURI, "https://") {
-				var parentNote *domain.Note
-				var readErr error
-
-				// Try to read parent note
-				if strings.HasPrefix(note.InReplyToURI, "local:") {
-					noteIdStr := strings.TrimPrefix(note.InReplyToURI, "local:")
-					parentNoteId, parseErr := uuid.Parse(noteIdStr)
-					if parseErr == nil {
-						readErr, parentNote = database.ReadNoteId(parentNoteId)
-					}
-				} else {
-					readErr, parentNote = database.ReadNoteByURI(note.InReplyToURI)
-				}
-
-				// Create notification if parent note exists and is local
-				if readErr == nil && parentNote != nil {
-					readErr, parentAuthor := database.ReadAccByUsername(parentNote.CreatedBy)
-					if readErr == nil && parentAuthor != nil && parentAuthor.Id != note.UserId {
-						// Only notify if replier is not the parent author
-						readErr, replier := database.ReadAccById(note.UserId)
-						if readErr == nil && replier != nil {
-							preview := util.StripHTMLTags(note.Message)
-							if len(preview) > 100 {
-								preview = preview[:100] + "..."
-							}
-							notification := &domain.Notification{
-								Id:               uuid.New(),
-								AccountId:        parentAuthor.Id,
-								NotificationType: domain.NotificationReply,
-								ActorId:          replier.Id,
-								ActorUsername:    replier.Username,
-								ActorDomain:      "", // Empty for local users
-								NoteId:           noteId,
-								NotePreview:      preview,
-								Read:             false,
-								CreatedAt:        time.Now(),
-							}
-							if err := database.CreateNotification(notification); err != nil {
-								log.Printf("Failed to create reply notification: %v", err)
-							}
-						}
-					}
-				}
-			}
-		}
+		// Federate the note via ActivityPub (background task)
+		go federateCreatedNote(database, noteId, note.UserId)
 
-		// Create mention notifications for local users
-		mentions := util.ParseMentions(note.Message)
-		if len(mentions) > 0 {
-			conf, confErr := util.ReadConf()
-			if confErr == nil && conf != nil {
-				readErr, author := database.ReadAccById(note.UserId)
-				if readErr == nil && author != nil {
-					preview := util.StripHTMLTags(note.Message)
-					if len(preview) > 100 {
-						preview = preview[:100] + "..."
-					}
+		return common.UpdateNoteList
+	}
+}
 
-					for _, mention := range mentions {
-						// Check if this is a local user
-						if mention.Domain == "" || mention.Domain == conf.Conf.SslDomain {
-							readErr, mentionedUser := database.ReadAccByUsername(mention.Username)
-							if readErr == nil && mentionedUser != nil && mentionedUser.Id != note.UserId {
-								// Only notify if mentioner is not the mentioned user
-								notification := &domain.Notification{
-									Id:               uuid.New(),
-									AccountId:        mentionedUser.Id,
-									NotificationType: domain.NotificationMention,
-									ActorId:          author.Id,
-									ActorUsername:    author.Username,
-									ActorDomain:      "", // Empty for local users
-									NoteId:           noteId,
-									NotePreview:      preview,
-									Read:             false,
-									CreatedAt:        time.Now(),
-								}
-								if err := database.CreateNotification(notification); err != nil {
-									log.Printf("Failed to create mention notification: %v", err)
-								}
-							}
-						}
-					}
-				}
-			}
-		}
+// createThreadCmd posts the notes of a thread, each one a reply to the previous
+// (the first one optionally replying to replyURI), and federates them in order
+func createThreadCmd(userId uuid.UUID, messages []string, replyURI string) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
 
-		// Link hashtags to the note
-		hashtags := util.ParseHashtags(note.Message)
-		if len(hashtags) > 0 {
-			hashtagIds := make([]int64, 0, len(hashtags))
-			for _, tag := range hashtags {
-				hashtagId, err := database.CreateOrUpdateHashtag(tag)
-				if err != nil {
-					log.Printf("Failed to create/update hashtag %s: %v", tag, err)
-					continue
-				}
-				hashtagIds = append(hashtagIds, hashtagId)
-			}
-			if len(hashtagIds) > 0 {
-				if err := database.LinkNoteHashtags(noteId, hashtagIds); err != nil {
-					log.Printf("Failed to link hashtags to note: %v", err)
-				}
+		noteIds := make([]uuid.UUID, 0, len(messages))
+		for _, message := range messages {
+			noteId, err := saveNote(database, &domain.SaveNote{UserId: userId, Message: message, InReplyToURI: replyURI})
+			if err != nil {
+				log.Printf("Thread note %d/%d could not be saved: %v", len(noteIds)+1, len(messages), err)
+				break
 			}
+			noteIds = append(noteIds, noteId)
+			replyURI = resolveReplyURI("local:" + noteId.String())
 		}
 
-		// Federate the note via ActivityPub (background task)
+		// Federate the notes one after another so they arrive in order (background task)
 		go func() {
-			// Get the created note from database with actual ID, timestamps, and reply info
-			err, createdNote := database.ReadNoteIdWithReplyInfo(noteId)
-			if err != nil {
-				log.Printf("Failed to read created note for federation: %v", err)
-				return
+			for _, noteId := range noteIds {
+				federateCreatedNote(database, noteId, userId)
 			}
+		}()
 
-			// Get the account
-			err, account := database.ReadAccById(note.UserId)
-			if err != nil {
-				log.Printf("Failed to get account for federation: %v", err)
-				return
-			}
+		return common.UpdateNoteList
+	}
+}
 
-			// Get config
-			conf, err := util.ReadConf()
-			if err != nil {
-				log.Printf("Failed to read config for federation: %v", err)
-				return
-			}
+// saveNote stores a new note (or reply), notifies local users it replies to or
+// mentions and links its hashtags
+func saveNote(database db.Store, note *domain.SaveNote) (uuid.UUID, error) {
+	// Create note in database and get the created note ID
+	// Use CreateNoteWithReply to support replies
+	noteId, err := database.CreateNoteWithReply(note.UserId, note.Message, note.InReplyToURI)
+	if err != nil {
+		return noteId, err
+	}
 
-			// Only federate if ActivityPub is enabled
-			if !conf.Conf.WithAp {
-				return
-			}
+	noteCreated(database, noteId, note.UserId, note.Message, note.InReplyToURI)
+	return noteId, nil
+}
 
-			// Send Create activity to all followers with the actual note from database
-			if err := activitypub.SendCreate(createdNote, account, conf); err != nil {
-				log.Printf("Failed to federate note: %v", err)
-			} else {
-				log.Printf("Note federated successfully for %s", account.Username)
-			}
-		}()
+// noteCreated notifies and links what a new note or article refers to, see activitypub.NoteCreated
+func noteCreated(database db.Store, noteId uuid.UUID, userId uuid.UUID, message string, inReplyToURI string) {
+	err, author := database.ReadAccById(userId)
+	if err != nil || author == nil {
+		log.Printf("Failed to read author of note %s: %v", noteId, err)
+		return
+	}
+	conf, err := util.ReadConf()
+	if err != nil {
+		log.Printf("Failed to read config: %v", err)
+		return
+	}
+	activitypub.NoteCreated(noteId, author, message, inReplyToURI, conf)
+}
+
+func createArticleCmd(userId uuid.UUID, title string, message string) tea.Cmd {
+	return func() tea.Msg {
+		database := db.GetDB()
+
+		noteId, err := database.CreateArticle(userId, title, message)
+		if err != nil {
+			log.Printf("Article could not be saved: %v", err)
+			return common.UpdateNoteList
+		}
+
+		noteCreated(database, noteId, userId, message, "")
+
+		// Federate the article via ActivityPub (background task)
+		go federateCreatedNote(database, noteId, userId)
 
 		return common.UpdateNoteList
 	}
 }
 
-func updateNoteModelCmd(noteId uuid.UUID, message string) tea.Cmd {
+// federateCreatedNote sends the Create activity for a new note to all followers
+func federateCreatedNote(database db.Store, noteId uuid.UUID, userId uuid.UUID) {
+	// Get the created note from database with actual ID, timestamps, and reply info
+	err, createdNote := database.ReadNoteIdWithReplyInfo(noteId)
+	if err != nil {
+		log.Printf("Failed to read created note for federation: %v", err)
+		return
+	}
+
+	// Get the account
+	err, account := database.ReadAccById(userId)
+	if err != nil {
+		log.Printf("Failed to get account for federation: %v", err)
+		return
+	}
+
+	// Get config
+	conf, err := util.ReadConf()
+	if err != nil {
+		log.Printf("Failed to read config for federation: %v", err)
+		return
+	}
+
+	// Only federate if ActivityPub is enabled
+	if !conf.Conf.WithAp {
+		return
+	}
+
+	// Send Create activity to all followers with the actual note from database
+	if err := activitypub.SendCreate(createdNote, account, conf); err != nil {
+		log.Printf("Failed to federate note: %v", err)
+	} else {
+		log.Printf("Note federated successfully for %s", account.Username)
+	}
+}
+
+// updateNoteModelCmd saves an edited note; a non-empty title saves it as an article
+func updateNoteModelCmd(noteId uuid.UUID, title string, message string) tea.Cmd {
 	return func() tea.Msg {
 		database := db.GetDB()
 
 		// Update note in database
-		err := database.UpdateNote(noteId, message)
+		var err error
+		if title != "" {
+			err = database.UpdateArticle(noteId, title, message)
+		} else {
+			err = database.UpdateNote(noteId, message)
+		}
 		if err != nil {
 			log.Printf("Note could not be updated: %v", err)
 			return common.UpdateNoteList
@@ -326,27 +340,8 @@ func updateNoteModelCmd(noteId uuid.UUID, message string) tea.Cmd {
 
 		log.Printf("Note %s updated successfully", noteId)
 
-		// Update hashtags for the note
-		// First, we need to unlink old hashtags and link new ones
-		// For simplicity, we'll just create/update new hashtags and link them
-		// The old links will remain (could be cleaned up with a separate function)
-		hashtags := util.ParseHashtags(message)
-		if len(hashtags) > 0 {
-			hashtagIds := make([]int64, 0, len(hashtags))
-			for _, tag := range hashtags {
-				hashtagId, err := database.CreateOrUpdateHashtag(tag)
-				if err != nil {
-					log.Printf("Failed to create/update hashtag %s: %v", tag, err)
-					continue
-				}
-				hashtagIds = append(hashtagIds, hashtagId)
-			}
-			if len(hashtagIds) > 0 {
-				if err := database.LinkNoteHashtags(noteId, hashtagIds); err != nil {
-					log.Printf("Failed to link hashtags to note: %v", err)
-				}
-			}
-		}
+		// Link the hashtags of the new text. The links of removed hashtags remain.
+		activitypub.LinkHashtags(noteId, message)
 
 		// Federate the update via ActivityPub (background task)
 		go func() {
@@ -388,7 +383,84 @@ func updateNoteModelCmd(noteId uuid.UUID, message string) tea.Cmd {
 	}
 }
 
+// scheduledNoteSavedMsg is sent when a scheduled note was stored
+type scheduledNoteSavedMsg struct {
+	scheduledAt time.Time
+	err         error
+}
+
+func createScheduledNoteCmd(userId uuid.UUID, message string, scheduledAt time.Time) tea.Cmd {
+	return func() tea.Msg {
+		note := &domain.ScheduledNote{
+			Id:          uuid.New(),
+			AccountId:   userId,
+			Message:     message,
+			ScheduledAt: scheduledAt,
+			CreatedAt:   time.Now(),
+		}
+		if err := db.GetDB().CreateScheduledNote(note); err != nil {
+			log.Printf("Scheduled note could not be saved: %v", err)
+			return scheduledNoteSavedMsg{err: err}
+		}
+		return scheduledNoteSavedMsg{scheduledAt: scheduledAt}
+	}
+}
+
+func updateScheduledNoteCmd(id, userId uuid.UUID, message string, scheduledAt time.Time) tea.Cmd {
+	return func() tea.Msg {
+		if err := db.GetDB().UpdateScheduledNote(id, userId, message, scheduledAt); err != nil {
+			log.Printf("Scheduled note could not be updated: %v", err)
+			return scheduledNoteSavedMsg{err: err}
+		}
+		// Back to the queue, which reloads on activation
+		return common.ScheduledNotesView
+	}
+}
+
+// draftAutosaveMsg is sent draftAutosaveDelay after the text changed
+type draftAutosaveMsg struct{}
+
+// draftLoadedMsg carries the most recent draft to restore after login
+type draftLoadedMsg struct {
+	draft *domain.Draft
+}
+
+func saveDraftCmd(draft domain.Draft) tea.Cmd {
+	return func() tea.Msg {
+		if err := db.GetDB().SaveDraft(&draft); err != nil {
+			log.Printf("Draft could not be saved: %v", err)
+		}
+		return nil
+	}
+}
+
+func deleteDraftCmd(id, userId uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		if err := db.GetDB().DeleteDraft(id, userId); err != nil {
+			log.Printf("Draft could not be deleted: %v", err)
+		}
+		return nil
+	}
+}
+
+func loadLatestDraftCmd(userId uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		err, drafts := db.GetDB().ReadDraftsByAccountId(userId)
+		if err != nil {
+			log.Printf("Failed to load drafts: %v", err)
+			return draftLoadedMsg{}
+		}
+		if drafts == nil || len(*drafts) == 0 {
+			return draftLoadedMsg{}
+		}
+		return draftLoadedMsg{draft: &(*drafts)[0]}
+	}
+}
+
 func (m Model) Init() tea.Cmd {
+	if !m.draftChecked {
+		return tea.Batch(textarea.Blink, loadServerMessage(), loadLatestDraftCmd(m.userId))
+	}
 	return tea.Batch(textarea.Blink, loadServerMessage())
 }
 
@@ -420,6 +492,160 @@ func (m Model) IsReplying() bool {
 	return m.isReplying
 }
 
+// isDrafting reports whether the text is a new note or reply, which are autosaved as drafts
+func (m Model) isDrafting() bool {
+	return !m.isEditing && m.editingScheduledId == uuid.Nil
+}
+
+// markDraftDirty records a change to the text and schedules an autosave if none is pending
+func (m *Model) markDraftDirty() tea.Cmd {
+	if !m.isDrafting() {
+		return nil
+	}
+	m.draftDirty = true
+	if m.draftSavePending {
+		return nil
+	}
+	m.draftSavePending = true
+	return tea.Tick(draftAutosaveDelay, func(time.Time) tea.Msg {
+		return draftAutosaveMsg{}
+	})
+}
+
+// autosaveDraft returns a command saving the current text as a draft if it changed.
+// Clearing the text discards the draft.
+func (m *Model) autosaveDraft() tea.Cmd {
+	if !m.draftDirty || !m.isDrafting() {
+		return nil
+	}
+	m.draftDirty = false
+
+	value := m.Textarea.Value()
+	if strings.TrimSpace(value) == "" {
+		return m.discardDraft()
+	}
+
+	now := time.Now()
+	if m.draftId == uuid.Nil {
+		m.draftId = uuid.New()
+		m.draftCreatedAt = now
+	}
+	draft := domain.Draft{
+		Id:        m.draftId,
+		AccountId: m.userId,
+		Message:   value,
+		CreatedAt: m.draftCreatedAt,
+		UpdatedAt: now,
+	}
+	if m.isArticle {
+		draft.Title = strings.TrimSpace(m.titleInput.Value())
+	}
+	if m.isReplying {
+		draft.InReplyToURI = resolveReplyURI(m.replyToURI)
+		draft.ReplyToAuthor = m.replyToAuthor
+		draft.ReplyToPreview = m.replyToPreview
+	}
+	return saveDraftCmd(draft)
+}
+
+// flushDraft saves pending changes and detaches the draft before the text is replaced
+func (m *Model) flushDraft() tea.Cmd {
+	cmd := m.autosaveDraft()
+	m.draftId = uuid.Nil
+	m.draftDirty = false
+	return cmd
+}
+
+// discardDraft deletes the current draft once its note was sent or cancelled
+func (m *Model) discardDraft() tea.Cmd {
+	id := m.draftId
+	m.draftId = uuid.Nil
+	m.draftDirty = false
+	if id == uuid.Nil {
+		return nil
+	}
+	return deleteDraftCmd(id, m.userId)
+}
+
+// resumeDraft loads a draft (and its reply context) into the editor
+func (m *Model) resumeDraft(d common.ResumeDraftMsg) {
+	m.isEditing = false
+	m.editingNoteId = uuid.Nil
+	m.originalCreatedAt = time.Time{}
+	m.clearSchedule()
+	m.isThread = d.Title == "" && (util.CountVisibleChars(d.Message) > maxLetters || util.HasThreadSeparator(d.Message))
+	m.setArticleMode(d.Title != "")
+	m.titleInput.SetValue(d.Title)
+	m.isReplying = d.InReplyToURI != ""
+	m.replyToURI = d.InReplyToURI
+	m.replyToAuthor = d.ReplyToAuthor
+	m.replyToPreview = d.ReplyToPreview
+	m.showAutocomplete = false
+	m.Textarea.SetValue(d.Message)
+	m.Textarea.Focus()
+	m.lettersLeft = m.CharCount()
+	m.draftId = d.Id
+	m.draftCreatedAt = d.CreatedAt
+	m.draftDirty = false
+	m.Error = ""
+}
+
+// resolveReplyURI turns a local: reply URI into the note's ActivityPub URI when a domain is configured
+func resolveReplyURI(replyURI string) string {
+	if !strings.HasPrefix(replyURI, "local:") {
+		return replyURI
+	}
+	noteIdStr := strings.TrimPrefix(replyURI, "local:")
+	if conf, err := util.ReadConf(); err == nil && conf.Conf.SslDomain != "" && conf.Conf.SslDomain != "example.com" {
+		// Construct proper ActivityPub URI
+		return fmt.Sprintf("https://%s/notes/%s", conf.Conf.SslDomain, noteIdStr)
+	}
+	// No valid domain configured, keep local: prefix
+	// This will only work for local thread views
+	return replyURI
+}
+
+// clearSchedule hides the schedule field and leaves scheduled note edit mode
+func (m *Model) clearSchedule() {
+	m.showSchedule = false
+	m.editingScheduledId = uuid.Nil
+	m.scheduleInput.SetValue("")
+	m.scheduleInput.Blur()
+}
+
+// setArticleMode switches between writing a short note and a long-form article.
+// Articles have a title and are not bound by the note length limits.
+func (m *Model) setArticleMode(on bool) {
+	m.isArticle = on
+	if !on {
+		m.titleInput.SetValue("")
+		m.titleInput.Blur()
+	}
+	m.updateTextLimits()
+}
+
+// setThreadMode switches between writing a single note and a thread: long text
+// that is split into numbered notes (see util.SplitThread) when posted.
+func (m *Model) setThreadMode(on bool) {
+	m.isThread = on
+	m.updateTextLimits()
+}
+
+// updateTextLimits lifts the note limits of the textarea for articles and threads
+func (m *Model) updateTextLimits() {
+	switch {
+	case m.isArticle:
+		m.Textarea.CharLimit = util.MaxArticleLength
+		m.Textarea.MaxHeight = 0
+	case m.isThread:
+		m.Textarea.CharLimit = util.MaxThreadParts * common.MaxNoteDBLength
+		m.Textarea.MaxHeight = 0
+	default:
+		m.Textarea.CharLimit = common.MaxNoteDBLength
+		m.Textarea.MaxHeight = noteMaxHeight
+	}
+}
+
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	var cmd tea.Cmd
@@ -429,13 +655,87 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.serverMessage = msg.message
 		return m, nil
 
+	case draftAutosaveMsg:
+		m.draftSavePending = false
+		return m, m.autosaveDraft()
+
+	case draftLoadedMsg:
+		// Restore the last draft once after login, unless something is being written already
+		if m.draftChecked {
+			return m, nil
+		}
+		m.draftChecked = true
+		if msg.draft == nil || m.Textarea.Value() != "" || !m.isDrafting() || m.isReplying {
+			return m, nil
+		}
+		d := msg.draft
+		m.resumeDraft(common.ResumeDraftMsg{
+			Id:             d.Id,
+			Message:        d.Message,
+			Title:          d.Title,
+			InReplyToURI:   d.InReplyToURI,
+			ReplyToAuthor:  d.ReplyToAuthor,
+			ReplyToPreview: d.ReplyToPreview,
+			CreatedAt:      d.CreatedAt,
+		})
+		m.Status = "Restored your last draft (ctrl+o: all drafts)"
+		return m, nil
+
+	case common.ResumeDraftMsg:
+		cmd = m.flushDraft()
+		m.resumeDraft(msg)
+		return m, cmd
+
+	case common.DraftDiscardedMsg:
+		// The text stays in the editor and is saved as a new draft on the next change
+		if m.draftId == msg.Id {
+			m.draftId = uuid.Nil
+		}
+		return m, nil
+
+	case scheduledNoteSavedMsg:
+		if msg.err != nil {
+			m.Error = fmt.Sprintf("Could not schedule note: %v", msg.err)
+			return m, nil
+		}
+		m.Status = "Scheduled for " + msg.scheduledAt.Format(util.ScheduleTimeFormat)
+		return m, nil
+
+	case common.EditScheduledNoteMsg:
+		// Enter scheduled note edit mode: populate textarea and publish time
+		cmd = m.flushDraft()
+		m.isEditing = false
+		m.editingNoteId = uuid.Nil
+		m.originalCreatedAt = time.Time{}
+		m.isReplying = false
+		m.replyToURI = ""
+		m.replyToAuthor = ""
+		m.replyToPreview = ""
+		m.showAutocomplete = false
+		m.isThread = false
+		m.setArticleMode(false)
+		m.editingScheduledId = msg.Id
+		m.showSchedule = true
+		m.scheduleInput.SetValue(msg.ScheduledAt.Local().Format(util.ScheduleTimeFormat))
+		m.scheduleInput.Blur()
+		m.Textarea.SetValue(msg.Message)
+		m.Textarea.Focus()
+		m.Error = ""
+		m.Status = ""
+		return m, cmd
+
 	case common.EditNoteMsg:
 		// Enter edit mode: populate textarea with existing note
+		cmd = m.flushDraft()
 		m.isEditing = true
 		m.editingNoteId = msg.NoteId
 		m.originalCreatedAt = msg.CreatedAt
+		m.isThread = false
+		m.setArticleMode(msg.Title != "")
+		m.titleInput.SetValue(msg.Title)
 		m.Textarea.SetValue(msg.Message)
 		m.Textarea.Focus()
+		m.clearSchedule()
 		// Clear reply mode if active
 		m.isReplying = false
 		m.replyToURI = ""
@@ -443,10 +743,11 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.replyToPreview = ""
 		// Clear autocomplete
 		m.showAutocomplete = false
-		return m, nil
+		return m, cmd
 
 	case common.ReplyToNoteMsg:
 		// Enter reply mode
+		cmd = m.flushDraft()
 		m.isReplying = true
 		m.replyToURI = msg.NoteURI
 		m.replyToAuthor = msg.Author
@@ -456,16 +757,75 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.editingNoteId = uuid.Nil
 		m.originalCreatedAt = time.Time{}
 		// Clear textarea and focus
+		m.isThread = false
+		m.setArticleMode(false)
 		m.Textarea.SetValue("")
 		m.Textarea.Focus()
+		m.clearSchedule()
 		// Clear autocomplete
 		m.showAutocomplete = false
-		return m, nil
+		return m, cmd
 
 	case tea.KeyMsg:
 		// Clear error when user starts typing
 		if msg.Type == tea.KeyRunes || msg.Type == tea.KeyBackspace {
 			m.Error = ""
+			m.Status = ""
+		}
+
+		// Keys typed into the article title field (ctrl+s still saves the article)
+		if m.titleInput.Focused() {
+			switch msg.Type {
+			case tea.KeyEnter, tea.KeyDown:
+				m.titleInput.Blur()
+				m.Textarea.Focus()
+				return m, nil
+			case tea.KeyEsc:
+				if !m.isEditing {
+					// Back to a short note
+					m.setArticleMode(false)
+				}
+				m.titleInput.Blur()
+				m.Textarea.Focus()
+				return m, m.markDraftDirty()
+			case tea.KeyCtrlS, tea.KeyCtrlC:
+				// Handled below
+			default:
+				titleBefore := m.titleInput.Value()
+				var cmd tea.Cmd
+				m.titleInput, cmd = m.titleInput.Update(msg)
+				if m.titleInput.Value() != titleBefore {
+					if draftCmd := m.markDraftDirty(); draftCmd != nil {
+						return m, tea.Batch(cmd, draftCmd)
+					}
+				}
+				return m, cmd
+			}
+		}
+
+		// Keys typed into the schedule field (ctrl+s still saves the note)
+		if m.scheduleInput.Focused() {
+			switch msg.Type {
+			case tea.KeyEnter:
+				m.scheduleInput.Blur()
+				m.Textarea.Focus()
+				return m, nil
+			case tea.KeyEsc:
+				if m.editingScheduledId != uuid.Nil {
+					// A scheduled note always needs a time, just leave the field
+					m.scheduleInput.Blur()
+				} else {
+					m.clearSchedule()
+				}
+				m.Textarea.Focus()
+				return m, nil
+			case tea.KeyCtrlS, tea.KeyCtrlC:
+				// Handled below
+			default:
+				var cmd tea.Cmd
+				m.scheduleInput, cmd = m.scheduleInput.Update(msg)
+				return m, cmd
+			}
 		}
 
 		// Handle autocomplete navigation when popup is visible
@@ -487,7 +847,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					m.insertAutocompleteSuggestion()
 				}
 				m.showAutocomplete = false
-				return m, nil
+				return m, m.markDraftDirty()
 			case tea.KeyEsc:
 				// Close autocomplete
 				m.showAutocomplete = false
@@ -496,11 +856,58 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		}
 
 		switch msg.Type {
+		case tea.KeyCtrlG:
+			// Toggle thread mode (new notes and replies only)
+			if m.isEditing || m.isArticle || m.showSchedule {
+				m.Error = "Only new notes and replies can be written as threads"
+				return m, nil
+			}
+			m.Error = ""
+			m.setThreadMode(!m.isThread)
+			return m, nil
+		case tea.KeyCtrlL:
+			// Switch to article mode (new notes only), or jump to the title
+			if !m.isArticle && (m.isEditing || m.isReplying || m.showSchedule || m.isThread) {
+				m.Error = "Only new notes can be written as articles"
+				return m, nil
+			}
+			if !m.isArticle {
+				m.setArticleMode(true)
+			}
+			m.Textarea.Blur()
+			return m, m.titleInput.Focus()
+		case tea.KeyCtrlT:
+			// Toggle the schedule field (new notes only)
+			if m.isEditing || m.isReplying {
+				m.Error = "Only new notes can be scheduled"
+				return m, nil
+			}
+			if m.isArticle {
+				m.Error = "Articles cannot be scheduled"
+				return m, nil
+			}
+			if m.isThread {
+				m.Error = "Threads cannot be scheduled"
+				return m, nil
+			}
+			if !m.scheduleInput.Focused() {
+				m.showSchedule = true
+				m.Textarea.Blur()
+				return m, m.scheduleInput.Focus()
+			}
+			return m, nil
 		case tea.KeyCtrlA:
 			if m.Textarea.Focused() {
 				m.Textarea.Blur()
 			}
 		case tea.KeyCtrlS:
+			if m.isArticle {
+				return m.saveArticle()
+			}
+			if m.isThread {
+				return m.saveThread()
+			}
+
 			rawValue := m.Textarea.Value()
 
 			// Validate that note is not empty (trim whitespace for validation)
@@ -526,6 +933,23 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			// Normalize input after validation
 			value := util.NormalizeInput(rawValue)
 
+			if m.editingScheduledId != uuid.Nil || strings.TrimSpace(m.scheduleInput.Value()) != "" {
+				scheduledAt, err := util.ParseScheduleTime(m.scheduleInput.Value(), time.Now())
+				if err != nil {
+					m.Error = err.Error()
+					return m, nil
+				}
+				id := m.editingScheduledId
+				m.Textarea.SetValue("")
+				m.Error = ""
+				m.clearSchedule()
+				m.Textarea.Focus()
+				if id != uuid.Nil {
+					return m, updateScheduledNoteCmd(id, m.userId, value, scheduledAt)
+				}
+				return m, tea.Batch(createScheduledNoteCmd(m.userId, value, scheduledAt), m.discardDraft())
+			}
+
 			if m.isEditing {
 				// Update existing note
 				noteId := m.editingNoteId
@@ -535,23 +959,11 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				m.isEditing = false
 				m.editingNoteId = uuid.Nil
 				m.originalCreatedAt = time.Time{}
-				return m, updateNoteModelCmd(noteId, value)
+				return m, updateNoteModelCmd(noteId, "", value)
 			} else if m.isReplying {
 				// Create reply note with inReplyTo
-				replyURI := m.replyToURI
-
 				// If this is a local: URI, resolve it to a proper ActivityPub URI
-				if strings.HasPrefix(replyURI, "local:") {
-					noteIdStr := strings.TrimPrefix(replyURI, "local:")
-					if conf, err := util.ReadConf(); err == nil && conf.Conf.SslDomain != "" && conf.Conf.SslDomain != "example.com" {
-						// Construct proper ActivityPub URI
-						replyURI = fmt.Sprintf("https://%s/notes/%s", conf.Conf.SslDomain, noteIdStr)
-					} else {
-						// No valid domain configured, keep local: prefix
-						// This will only work for local thread views
-						replyURI = "local:" + noteIdStr
-					}
-				}
+				replyURI := resolveReplyURI(m.replyToURI)
 
 				note := domain.SaveNote{
 					UserId:       m.userId,
@@ -565,7 +977,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				m.replyToURI = ""
 				m.replyToAuthor = ""
 				m.replyToPreview = ""
-				return m, createNoteModelCmd(&note)
+				return m, tea.Batch(createNoteModelCmd(&note), m.discardDraft())
 			} else {
 				// Create new note
 				note := domain.SaveNote{
@@ -574,16 +986,26 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				}
 				m.Textarea.SetValue("")
 				m.Error = ""
-				return m, createNoteModelCmd(&note)
+				return m, tea.Batch(createNoteModelCmd(&note), m.discardDraft())
 			}
 		case tea.KeyCtrlC:
 			return m, tea.Quit
 		case tea.KeyEsc:
-			// Cancel edit mode or reply mode
+			// Cancel edit mode, reply mode or scheduling
+			if m.editingScheduledId != uuid.Nil {
+				m.clearSchedule()
+				m.Textarea.SetValue("")
+				return m, func() tea.Msg { return common.ScheduledNotesView }
+			}
+			if m.showSchedule {
+				m.clearSchedule()
+				return m, nil
+			}
 			if m.isEditing {
 				m.isEditing = false
 				m.editingNoteId = uuid.Nil
 				m.originalCreatedAt = time.Time{}
+				m.setArticleMode(false)
 				m.Textarea.SetValue("")
 				return m, nil
 			}
@@ -592,11 +1014,16 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				m.replyToURI = ""
 				m.replyToAuthor = ""
 				m.replyToPreview = ""
+				m.setThreadMode(false)
 				m.Textarea.SetValue("")
+				return m, m.discardDraft()
+			}
+			if m.isThread {
+				m.setThreadMode(false)
 				return m, nil
 			}
 		default:
-			if !m.Textarea.Focused() {
+			if !m.Textarea.Focused() && !m.scheduleInput.Focused() && !m.titleInput.Focused() {
 				cmd = m.Textarea.Focus()
 				cmds = append(cmds, cmd)
 			}
@@ -612,6 +1039,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		return m, nil
 	}
 
+	valueBefore := m.Textarea.Value()
 	m.Textarea, cmd = m.Textarea.Update(msg)
 
 	// Check if visible character count exceeds maxChars
@@ -634,6 +1062,13 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.Textarea.CursorEnd()
 	}
 
+	// Autosave the draft shortly after the text changed
+	if m.Textarea.Value() != valueBefore {
+		if draftCmd := m.markDraftDirty(); draftCmd != nil {
+			cmds = append(cmds, draftCmd)
+		}
+	}
+
 	// Handle autocomplete trigger and filtering
 	m.updateAutocomplete()
 
@@ -651,6 +1086,59 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	}
 }
 
+// saveArticle validates the article and publishes it, or saves the changes when editing.
+// Articles keep their markdown (including line breaks) as written.
+func (m Model) saveArticle() (Model, tea.Cmd) {
+	title := strings.TrimSpace(m.titleInput.Value())
+	body := m.Textarea.Value()
+	if err := util.ValidateArticle(title, body); err != nil {
+		m.Error = err.Error()
+		return m, nil
+	}
+
+	m.Textarea.SetValue("")
+	m.Error = ""
+	m.setArticleMode(false)
+	m.Textarea.Focus()
+
+	if m.isEditing {
+		noteId := m.editingNoteId
+		m.isEditing = false
+		m.editingNoteId = uuid.Nil
+		m.originalCreatedAt = time.Time{}
+		return m, updateNoteModelCmd(noteId, title, body)
+	}
+	return m, tea.Batch(createArticleCmd(m.userId, title, body), m.discardDraft())
+}
+
+// saveThread splits the text into numbered notes and posts them as a thread,
+// continuing the reply if one is being written
+func (m Model) saveThread() (Model, tea.Cmd) {
+	parts, err := util.SplitThread(m.Textarea.Value(), maxLetters)
+	if err != nil {
+		m.Error = err.Error()
+		return m, nil
+	}
+	messages := make([]string, len(parts))
+	for i, part := range parts {
+		messages[i] = util.NormalizeInput(part)
+	}
+
+	replyURI := ""
+	if m.isReplying {
+		replyURI = resolveReplyURI(m.replyToURI)
+	}
+
+	m.Textarea.SetValue("")
+	m.Error = ""
+	m.setThreadMode(false)
+	m.isReplying = false
+	m.replyToURI = ""
+	m.replyToAuthor = ""
+	m.replyToPreview = ""
+	return m, tea.Batch(createThreadCmd(m.userId, messages, replyURI), m.discardDraft())
+}
+
 // updateAutocomplete checks if we should show/hide/update autocomplete suggestions
 func (m *Model) updateAutocomplete() {
 	value := m.Textarea.Value()
@@ -789,8 +1277,20 @@ func (m Model) View() string {
 		linkIndicator = "\n" + linkStyle.Render(fmt.Sprintf("✓ %d markdown link%s detected", linkCount, plural))
 	}
 
-	helpText := "post message: ctrl+s"
-	if m.isEditing {
+	helpText := "post message: ctrl+s\nschedule: ctrl+t\narticle: ctrl+l\nthread: ctrl+g\ndrafts: ctrl+o"
+	if m.isThread && m.isReplying {
+		helpText = "post thread as reply: ctrl+s\nsingle note: ctrl+g\ncancel: esc"
+	} else if m.isThread {
+		helpText = "post thread: ctrl+s\nsingle note: esc"
+	} else if m.isArticle && m.isEditing {
+		helpText = "save changes: ctrl+s\nedit title: ctrl+l\ncancel: esc"
+	} else if m.isArticle {
+		helpText = "post article: ctrl+s\nedit title: ctrl+l\nback to note: esc in title"
+	} else if m.editingScheduledId != uuid.Nil {
+		helpText = "save scheduled note: ctrl+s\nedit time: ctrl+t\ncancel: esc"
+	} else if m.showSchedule {
+		helpText = "schedule message: ctrl+s\nunschedule: esc"
+	} else if m.isEditing {
 		helpText = "save changes: ctrl+s\ncancel: esc"
 	} else if m.isReplying {
 		helpText = "post reply: ctrl+s\ncancel: esc"
@@ -801,11 +1301,26 @@ func (m Model) View() string {
 
 	// Build the help section with proper formatting
 	helpLines := fmt.Sprintf("characters left: %d\n\n%s", m.lettersLeft, helpText)
+	if m.isArticle {
+		helpLines = fmt.Sprintf("article length: %d/%d\n\n%s", len(m.Textarea.Value()), util.MaxArticleLength, helpText)
+	} else if m.isThread {
+		helpLines = fmt.Sprintf("%s\n\n%s", m.threadInfo(), helpText)
+	}
 	charsLeft := common.HelpStyle.Render(lipgloss.NewStyle().PaddingLeft(5).Render(helpLines))
 
 	captionText := "new note"
-	if m.isEditing {
+	if m.isArticle && m.isEditing {
+		captionText = "edit article"
+	} else if m.isArticle {
+		captionText = "new article"
+	} else if m.editingScheduledId != uuid.Nil {
+		captionText = "edit scheduled note"
+	} else if m.showSchedule {
+		captionText = "schedule note"
+	} else if m.isEditing {
 		captionText = "edit note"
+	} else if m.isThread && !m.isReplying {
+		captionText = "new thread"
 	} else if m.isReplying {
 		// replyToAuthor already has @ prefix for remote users (@user@domain)
 		// but not for local users, so we need to check
@@ -815,6 +1330,9 @@ func (m Model) View() string {
 			captionText = "reply to @" + m.replyToAuthor
 		}
 	}
+	if m.isThread && m.isReplying {
+		captionText += " (thread)"
+	}
 	caption := common.CaptionStyle.PaddingLeft(5).Render(captionText)
 
 	// Show reply context if replying
@@ -832,7 +1350,19 @@ func (m Model) View() string {
 		replyContext = replyStyle.Render("\""+preview+"\"") + "\n\n"
 	}
 
-	// Add error message if present
+	// Show the title field above the body when writing an article
+	titleSection := ""
+	if m.isArticle {
+		titleSection = lipgloss.NewStyle().PaddingLeft(5).Render(m.titleInput.View()) + "\n\n"
+	}
+
+	// Show the publish time field when scheduling
+	scheduleSection := ""
+	if m.showSchedule {
+		scheduleSection = "\n" + lipgloss.NewStyle().PaddingLeft(5).Render(m.scheduleInput.View())
+	}
+
+	// Add error or status message if present
 	errorSection := ""
 	if m.Error != "" {
 		errorStyle := lipgloss.NewStyle().
@@ -840,6 +1370,11 @@ func (m Model) View() string {
 			Bold(true).
 			PaddingLeft(5)
 		errorSection = "\n" + errorStyle.Render(m.Error)
+	} else if m.Status != "" {
+		statusStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(common.COLOR_SUCCESS)).
+			PaddingLeft(5)
+		errorSection = "\n" + statusStyle.Render(m.Status)
 	}
 
 	// Add server message if enabled
@@ -857,7 +1392,22 @@ func (m Model) View() string {
 				Render(m.serverMessage.Message)
 	}
 
-	return fmt.Sprintf("%s\n\n%s%s%s%s%s\n\n%s%s", caption, replyContext, styledTextarea, autocompletePopup, linkIndicator, errorSection, charsLeft, serverMessageSection)
+	return fmt.Sprintf("%s\n\n%s%s%s%s%s%s%s\n\n%s%s", caption, replyContext, titleSection, styledTextarea, autocompletePopup, scheduleSection, linkIndicator, errorSection, charsLeft, serverMessageSection)
+}
+
+// threadInfo describes how the text will be split into a thread
+func (m Model) threadInfo() string {
+	if strings.TrimSpace(m.Textarea.Value()) == "" {
+		return fmt.Sprintf("thread: split on %q lines or automatically", util.ThreadSeparator)
+	}
+	parts, err := util.SplitThread(m.Textarea.Value(), maxLetters)
+	if err != nil {
+		return strings.ToLower(err.Error())
+	}
+	if len(parts) == 1 {
+		return "thread: 1 note"
+	}
+	return fmt.Sprintf("thread: %d notes", len(parts))
 }
 
 // renderAutocompletePopup renders the autocomplete suggestion list
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/deemkeen/stegodon/domain"
 	"github.com/deemkeen/stegodon/ui/common"
 	"github.com/deemkeen/stegodon/util"
 	"github.com/google/uuid"
@@ -543,3 +544,341 @@ func TestMentionCandidateDisplayVsFullMention(t *testing.T) {
 		t.Error("Remote user's DisplayMention should equal FullMention")
 	}
 }
+
+func TestScheduleFieldToggle(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if !m.showSchedule || !m.scheduleInput.Focused() {
+		t.Fatal("Expected ctrl+t to show and focus the schedule field")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("+2h")})
+	if m.scheduleInput.Value() != "+2h" {
+		t.Errorf("Expected typing to go to the schedule field, got %q", m.scheduleInput.Value())
+	}
+	if m.Textarea.Value() != "" {
+		t.Errorf("Expected textarea to stay empty, got %q", m.Textarea.Value())
+	}
+	if !strings.Contains(m.View(), "schedule note") {
+		t.Error("Expected caption 'schedule note'")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showSchedule || m.scheduleInput.Value() != "" {
+		t.Error("Expected esc to remove the schedule")
+	}
+}
+
+func TestScheduleInvalidTime(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+	m.Textarea.SetValue("good morning")
+	m.showSchedule = true
+	m.scheduleInput.SetValue("tomorrow-ish")
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.Error == "" {
+		t.Error("Expected error for an invalid publish time")
+	}
+	if cmd != nil {
+		t.Error("Expected no save command for an invalid publish time")
+	}
+	if m.Textarea.Value() != "good morning" {
+		t.Error("Expected the note to be kept after a failed save")
+	}
+}
+
+func TestScheduleNotAllowedWhenReplying(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+	m, _ = m.Update(common.ReplyToNoteMsg{NoteURI: "https://example.com/notes/1", Author: "bob"})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if m.showSchedule {
+		t.Error("Expected replies not to be schedulable")
+	}
+	if m.Error == "" {
+		t.Error("Expected an error explaining why")
+	}
+}
+
+func TestEditScheduledNote(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+	id := uuid.New()
+	at := time.Date(2030, 1, 2, 7, 30, 0, 0, time.Local)
+
+	m, _ = m.Update(common.EditScheduledNoteMsg{Id: id, Message: "good morning", ScheduledAt: at})
+	if m.editingScheduledId != id || !m.showSchedule {
+		t.Fatal("Expected scheduled note edit mode")
+	}
+	if m.Textarea.Value() != "good morning" {
+		t.Errorf("Expected message in textarea, got %q", m.Textarea.Value())
+	}
+	if m.scheduleInput.Value() != "2030-01-02 07:30" {
+		t.Errorf("Expected publish time in schedule field, got %q", m.scheduleInput.Value())
+	}
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.editingScheduledId != uuid.Nil || m.Textarea.Value() != "" {
+		t.Error("Expected esc to leave scheduled note edit mode")
+	}
+	if cmd == nil || cmd() != common.ScheduledNotesView {
+		t.Error("Expected to return to the scheduled notes view")
+	}
+}
+
+func TestScheduledNoteSavedMsg(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+	at := time.Date(2030, 1, 2, 7, 30, 0, 0, time.Local)
+
+	m, _ = m.Update(scheduledNoteSavedMsg{scheduledAt: at})
+	if !strings.Contains(m.View(), "Scheduled for 2030-01-02 07:30") {
+		t.Error("Expected confirmation with the publish time")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	if m.Status != "" {
+		t.Error("Expected confirmation to clear when typing")
+	}
+}
+
+func TestTypingSchedulesDraftAutosave(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	if !m.draftDirty || !m.draftSavePending || cmd == nil {
+		t.Fatal("Expected typing to schedule an autosave")
+	}
+
+	// Further typing doesn't schedule another tick
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	if !m.draftSavePending {
+		t.Error("Expected the autosave to still be pending")
+	}
+
+	m, cmd = m.Update(draftAutosaveMsg{})
+	if m.draftSavePending || m.draftDirty {
+		t.Error("Expected autosave to clear the pending and dirty flags")
+	}
+	if m.draftId == uuid.Nil || cmd == nil {
+		t.Error("Expected the draft to get an id and be saved")
+	}
+
+	// Nothing changed since the last save
+	_, cmd = m.Update(draftAutosaveMsg{})
+	if cmd != nil {
+		t.Error("Expected no save without changes")
+	}
+}
+
+func TestEditingIsNotSavedAsDraft(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+	m, _ = m.Update(common.EditNoteMsg{NoteId: uuid.New(), Message: "published", CreatedAt: time.Now()})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("!")})
+	if m.draftDirty || m.draftSavePending {
+		t.Error("Expected edits of published notes not to be autosaved")
+	}
+}
+
+func TestRestoreLastDraft(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+	draft := &domain.Draft{
+		Id:             uuid.New(),
+		Message:        "@bob agreed",
+		InReplyToURI:   "https://remote.example/notes/1",
+		ReplyToAuthor:  "@bob@remote.example",
+		ReplyToPreview: "thoughts?",
+	}
+
+	m, _ = m.Update(draftLoadedMsg{draft: draft})
+	if m.Textarea.Value() != "@bob agreed" || m.draftId != draft.Id {
+		t.Fatalf("Expected draft to be restored, got %q", m.Textarea.Value())
+	}
+	if !m.isReplying || m.replyToURI != draft.InReplyToURI || m.replyToAuthor != draft.ReplyToAuthor {
+		t.Error("Expected reply context to be restored")
+	}
+	if !strings.Contains(m.View(), "reply to @bob@remote.example") {
+		t.Error("Expected reply caption")
+	}
+
+	// Only restored once per session
+	m.Textarea.SetValue("")
+	m, _ = m.Update(draftLoadedMsg{draft: draft})
+	if m.Textarea.Value() != "" {
+		t.Error("Expected the draft not to be restored twice")
+	}
+}
+
+func TestRestoreDoesNotOverwriteText(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+	m.Textarea.SetValue("already typing")
+
+	m, _ = m.Update(draftLoadedMsg{draft: &domain.Draft{Id: uuid.New(), Message: "old draft"}})
+	if m.Textarea.Value() != "already typing" {
+		t.Errorf("Expected current text to be kept, got %q", m.Textarea.Value())
+	}
+}
+
+func TestResumeDraft(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+	m.Textarea.SetValue("current")
+	m.draftId = uuid.New()
+	m.draftDirty = true
+
+	id := uuid.New()
+	m, cmd := m.Update(common.ResumeDraftMsg{Id: id, Message: "resumed"})
+	if cmd == nil {
+		t.Error("Expected the current text to be saved before switching drafts")
+	}
+	if m.Textarea.Value() != "resumed" || m.draftId != id || m.isReplying {
+		t.Errorf("Expected resumed draft, got %q", m.Textarea.Value())
+	}
+
+	// Discarding the open draft from the list detaches it
+	m, _ = m.Update(common.DraftDiscardedMsg{Id: id})
+	if m.draftId != uuid.Nil || m.Textarea.Value() != "resumed" {
+		t.Error("Expected the draft to be detached but the text kept")
+	}
+}
+
+func TestArticleModeToggle(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	if !m.isArticle || !m.titleInput.Focused() {
+		t.Fatal("Expected ctrl+l to switch to article mode and focus the title")
+	}
+	if m.Textarea.CharLimit != util.MaxArticleLength || m.Textarea.MaxHeight != 0 {
+		t.Error("Expected article mode to lift the note limits")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("My Post")})
+	if m.titleInput.Value() != "My Post" || m.Textarea.Value() != "" {
+		t.Errorf("Expected typing to go to the title field, got %q", m.titleInput.Value())
+	}
+	if !strings.Contains(m.View(), "new article") {
+		t.Error("Expected caption 'new article'")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.isArticle || m.titleInput.Value() != "" || m.Textarea.CharLimit != common.MaxNoteDBLength {
+		t.Error("Expected esc in the title to go back to a short note")
+	}
+}
+
+func TestArticleKeepsLineBreaks(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+	m.setArticleMode(true)
+	m.Textarea.SetValue("para one\n\npara two")
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.Error == "" || cmd != nil {
+		t.Error("Expected an article without a title to be rejected")
+	}
+	if m.Textarea.Value() != "para one\n\npara two" {
+		t.Errorf("Expected the article to be kept after a failed save, got %q", m.Textarea.Value())
+	}
+}
+
+func TestArticleNotAllowedWhenReplying(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+	m, _ = m.Update(common.ReplyToNoteMsg{NoteURI: "https://example.com/notes/1", Author: "bob"})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	if m.isArticle || m.Error == "" {
+		t.Error("Expected replies not to be written as articles")
+	}
+}
+
+func TestEditArticle(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+	m, _ = m.Update(common.EditNoteMsg{NoteId: uuid.New(), Message: "body", Title: "Title"})
+
+	if !m.isArticle || !m.isEditing || m.titleInput.Value() != "Title" {
+		t.Fatal("Expected editing an article to open it in article mode")
+	}
+	if !strings.Contains(m.View(), "edit article") {
+		t.Error("Expected caption 'edit article'")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.isArticle || m.isEditing {
+		t.Error("Expected esc to cancel editing the article")
+	}
+}
+
+func TestThreadModeToggle(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	if !m.isThread || m.Textarea.CharLimit != util.MaxThreadParts*common.MaxNoteDBLength {
+		t.Fatal("Expected ctrl+g to switch to thread mode and lift the note limit")
+	}
+
+	m.Textarea.SetValue("First note.\n---\nSecond note.")
+	view := m.View()
+	if !strings.Contains(view, "new thread") || !strings.Contains(view, "thread: 2 notes") {
+		t.Errorf("Expected thread caption and note count, got:\n%s", view)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.isThread || m.Textarea.CharLimit != common.MaxNoteDBLength {
+		t.Error("Expected esc to go back to a single note")
+	}
+	if m.Textarea.Value() == "" {
+		t.Error("Expected the text to be kept when leaving thread mode")
+	}
+}
+
+func TestThreadEmpty(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+	m.setThreadMode(true)
+	m.Textarea.SetValue("---")
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.Error == "" || cmd != nil {
+		t.Error("Expected an empty thread to be rejected")
+	}
+}
+
+func TestThreadNotAllowedWhenEditing(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+	m, _ = m.Update(common.EditNoteMsg{NoteId: uuid.New(), Message: "posted"})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	if m.isThread || m.Error == "" {
+		t.Error("Expected edited notes not to become threads")
+	}
+}
+
+func TestThreadAsReply(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+	m, _ = m.Update(common.ReplyToNoteMsg{NoteURI: "https://example.com/notes/1", Author: "bob"})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	if !m.isThread || !m.isReplying {
+		t.Fatal("Expected replies to be writable as threads")
+	}
+	if !strings.Contains(m.View(), "reply to @bob (thread)") {
+		t.Error("Expected reply thread caption")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if m.showSchedule {
+		t.Error("Expected threads not to be schedulable")
+	}
+}
+
+func TestResumeLongDraftOpensThread(t *testing.T) {
+	m := InitialNote(100, uuid.New())
+
+	m, _ = m.Update(common.ResumeDraftMsg{Id: uuid.New(), Message: strings.Repeat("long text ", 100)})
+	if !m.isThread {
+		t.Error("Expected a draft that is too long for one note to open as a thread")
+	}
+
+	m, _ = m.Update(common.ResumeDraftMsg{Id: uuid.New(), Message: "short"})
+	if m.isThread {
+		t.Error("Expected a short draft to open as a note")
+	}
+}
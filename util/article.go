@@ -0,0 +1,82 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/html"
+	"github.com/gomarkdown/markdown/parser"
+)
+
+const (
+	// MaxArticleTitleLength is the maximum length of an article title in characters
+	MaxArticleTitleLength = 200
+	// MaxArticleLength is the maximum length of an article body (markdown) in bytes
+	MaxArticleLength = 100000
+	// ArticleSummaryLength is the number of characters of an article used as its summary preview
+	ArticleSummaryLength = 280
+)
+
+var whitespaceRunRegex = regexp.MustCompile(`\s+`)
+
+// ValidateArticle checks an article's title and markdown body.
+// Returns an error describing the first problem found.
+func ValidateArticle(title, body string) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return fmt.Errorf("Article needs a title")
+	}
+	if utf8.RuneCountInString(title) > MaxArticleTitleLength {
+		return fmt.Errorf("Article title too long (max %d characters)", MaxArticleTitleLength)
+	}
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("Article body is empty")
+	}
+	if len(body) > MaxArticleLength {
+		return fmt.Errorf("Article too long (max %d characters)", MaxArticleLength)
+	}
+	return nil
+}
+
+// ArticleToHTML renders an article's markdown body to HTML.
+// Raw HTML in the source is dropped and only safe link schemes are kept,
+// since the result is served on the web and federated as-is.
+func ArticleToHTML(md string) string {
+	extensions := parser.CommonExtensions | parser.AutoHeadingIDs | parser.NoEmptyLineBeforeBlock | parser.Strikethrough
+	p := parser.NewWithExtensions(extensions)
+	doc := p.Parse([]byte(md))
+
+	htmlFlags := html.CommonFlags | html.HrefTargetBlank | html.SkipHTML | html.Safelink | html.NofollowLinks
+	renderer := html.NewRenderer(html.RendererOptions{Flags: htmlFlags})
+
+	return strings.TrimSpace(string(markdown.Render(doc, renderer)))
+}
+
+// ArticleSummary returns a plain-text preview of an article's markdown body,
+// cut at a word boundary to at most maxLen characters.
+func ArticleSummary(md string, maxLen int) string {
+	text := StripHTMLTags(ArticleToHTML(md))
+	text = strings.TrimSpace(whitespaceRunRegex.ReplaceAllString(text, " "))
+
+	runes := []rune(text)
+	if len(runes) <= maxLen {
+		return text
+	}
+	cut := string(runes[:maxLen-1])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " .,;:") + "…"
+}
+
+// SplitArticleSource splits article source into a title and body: a leading
+// markdown heading ("# Title") or the first non-empty line becomes the title.
+func SplitArticleSource(source string) (title, body string) {
+	source = strings.TrimLeft(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	first, rest, _ := strings.Cut(source, "\n")
+	title = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(first), "#"))
+	return title, strings.Trim(rest, "\n")
+}
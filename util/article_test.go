@@ -0,0 +1,86 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateArticle(t *testing.T) {
+	tests := []struct {
+		name    string
+		title   string
+		body    string
+		wantErr bool
+	}{
+		{"valid", "Hello", "Some *markdown* text.", false},
+		{"missing title", "  ", "body", true},
+		{"empty body", "Title", "\n\n", true},
+		{"title too long", strings.Repeat("t", MaxArticleTitleLength+1), "body", true},
+		{"body too long", "Title", strings.Repeat("b", MaxArticleLength+1), true},
+		{"long body allowed", "Title", strings.Repeat("b", 5000), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateArticle(tt.title, tt.body)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateArticle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestArticleToHTML(t *testing.T) {
+	out := ArticleToHTML("## Section\n\nSome **bold** text and a [link](https://example.com).\n\n- one\n- two")
+
+	for _, want := range []string{`<h2 id="section">Section</h2>`, "<strong>bold</strong>", `href="https://example.com"`, "<li>one</li>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output, got: %s", want, out)
+		}
+	}
+}
+
+func TestArticleToHTMLDropsUnsafeContent(t *testing.T) {
+	out := ArticleToHTML("Hi <script>alert(1)</script>\n\n[x](javascript:alert(1))")
+
+	if strings.Contains(out, "<script>") {
+		t.Errorf("Expected raw HTML to be dropped, got: %s", out)
+	}
+	if strings.Contains(out, "javascript:") {
+		t.Errorf("Expected unsafe link to be dropped, got: %s", out)
+	}
+}
+
+func TestArticleSummary(t *testing.T) {
+	if got := ArticleSummary("# Heading\n\nShort *text*.", 100); got != "Heading Short text." {
+		t.Errorf("Unexpected summary: %q", got)
+	}
+
+	got := ArticleSummary("one two three four five six", 12)
+	if got != "one two…" {
+		t.Errorf("Expected cut at word boundary, got %q", got)
+	}
+}
+
+func TestSplitArticleSource(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		wantTitle string
+		wantBody  string
+	}{
+		{"heading", "# My Post\n\nFirst paragraph.\n", "My Post", "First paragraph."},
+		{"plain first line", "My Post\nBody", "My Post", "Body"},
+		{"leading blank lines", "\n\n## Title\r\nBody", "Title", "Body"},
+		{"title only", "Just a title", "Just a title", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, body := SplitArticleSource(tt.source)
+			if title != tt.wantTitle || body != tt.wantBody {
+				t.Errorf("SplitArticleSource() = (%q, %q), want (%q, %q)", title, body, tt.wantTitle, tt.wantBody)
+			}
+		})
+	}
+}
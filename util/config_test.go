@@ -131,6 +131,37 @@ func TestReadConfMissingFile(t *testing.T) {
 	}
 }
 
+func TestReadConfActorRefreshHours(t *testing.T) {
+	yamlContent := `
+conf:
+  host: 127.0.0.1
+  actorRefreshHours: 12
+`
+	if err := os.WriteFile("config.yaml", []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+	defer os.Remove("config.yaml")
+
+	config, err := ReadConf()
+	if err != nil {
+		t.Fatalf("ReadConf failed: %v", err)
+	}
+	if config.Conf.ActorRefreshHours != 12 {
+		t.Errorf("Expected ActorRefreshHours 12 from YAML, got %d", config.Conf.ActorRefreshHours)
+	}
+
+	os.Setenv("STEGODON_ACTOR_REFRESH_HOURS", "0")
+	defer os.Unsetenv("STEGODON_ACTOR_REFRESH_HOURS")
+
+	config, err = ReadConf()
+	if err != nil {
+		t.Fatalf("ReadConf failed: %v", err)
+	}
+	if config.Conf.ActorRefreshHours != 72 {
+		t.Errorf("Expected default ActorRefreshHours 72 for invalid env value, got %d", config.Conf.ActorRefreshHours)
+	}
+}
+
 func TestReadConfInvalidYaml(t *testing.T) {
 	// Create an invalid YAML file
 	invalidYaml := `
@@ -242,3 +273,133 @@ func TestAppConfigStruct(t *testing.T) {
 		t.Error("Expected WithAp to be true")
 	}
 }
+
+func TestReadConfRetentionDays(t *testing.T) {
+	yamlContent := `
+conf:
+  host: 127.0.0.1
+  retentionDays: 90
+`
+	if err := os.WriteFile("config.yaml", []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+	defer os.Remove("config.yaml")
+
+	config, err := ReadConf()
+	if err != nil {
+		t.Fatalf("ReadConf failed: %v", err)
+	}
+	if config.Conf.RetentionDays != 90 {
+		t.Errorf("Expected RetentionDays 90 from YAML, got %d", config.Conf.RetentionDays)
+	}
+
+	os.Setenv("STEGODON_RETENTION_DAYS", "-5")
+	defer os.Unsetenv("STEGODON_RETENTION_DAYS")
+
+	config, err = ReadConf()
+	if err != nil {
+		t.Fatalf("ReadConf failed: %v", err)
+	}
+	if config.Conf.RetentionDays != 0 {
+		t.Errorf("Expected negative RetentionDays to disable pruning, got %d", config.Conf.RetentionDays)
+	}
+}
+
+func TestReadConfMetricsAddr(t *testing.T) {
+	yamlContent := `
+conf:
+  host: 127.0.0.1
+  metricsAddr: 127.0.0.1:9100
+`
+	if err := os.WriteFile("config.yaml", []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+	defer os.Remove("config.yaml")
+
+	config, err := ReadConf()
+	if err != nil {
+		t.Fatalf("ReadConf failed: %v", err)
+	}
+	if config.Conf.MetricsAddr != "127.0.0.1:9100" {
+		t.Errorf("Expected MetricsAddr from YAML, got %q", config.Conf.MetricsAddr)
+	}
+
+	os.Setenv("STEGODON_METRICS_ADDR", ":9200")
+	defer os.Unsetenv("STEGODON_METRICS_ADDR")
+
+	config, err = ReadConf()
+	if err != nil {
+		t.Fatalf("ReadConf failed: %v", err)
+	}
+	if config.Conf.MetricsAddr != ":9200" {
+		t.Errorf("Expected MetricsAddr from env, got %q", config.Conf.MetricsAddr)
+	}
+}
+
+func TestReadConfDatabaseURL(t *testing.T) {
+	yamlContent := `
+conf:
+  host: 127.0.0.1
+  databaseUrl: postgres://stegodon@localhost/stegodon
+`
+	if err := os.WriteFile("config.yaml", []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+	defer os.Remove("config.yaml")
+
+	config, err := ReadConf()
+	if err != nil {
+		t.Fatalf("ReadConf failed: %v", err)
+	}
+	if config.Conf.DatabaseURL != "postgres://stegodon@localhost/stegodon" {
+		t.Errorf("Expected DatabaseURL from YAML, got %q", config.Conf.DatabaseURL)
+	}
+
+	os.Setenv("STEGODON_DATABASE_URL", "postgres://other@db.example/stegodon")
+	defer os.Unsetenv("STEGODON_DATABASE_URL")
+
+	config, err = ReadConf()
+	if err != nil {
+		t.Fatalf("ReadConf failed: %v", err)
+	}
+	if config.Conf.DatabaseURL != "postgres://other@db.example/stegodon" {
+		t.Errorf("Expected DatabaseURL from env, got %q", config.Conf.DatabaseURL)
+	}
+}
+
+func TestReadConfBackup(t *testing.T) {
+	yamlContent := `
+conf:
+  host: 127.0.0.1
+  backupHours: 24
+  backupDir: /var/backups/stegodon
+`
+	if err := os.WriteFile("config.yaml", []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+	defer os.Remove("config.yaml")
+
+	config, err := ReadConf()
+	if err != nil {
+		t.Fatalf("ReadConf failed: %v", err)
+	}
+	if config.Conf.BackupHours != 24 || config.Conf.BackupDir != "/var/backups/stegodon" {
+		t.Errorf("Expected backup settings from YAML, got %d hours in %q", config.Conf.BackupHours, config.Conf.BackupDir)
+	}
+	if config.Conf.BackupKeep != 7 {
+		t.Errorf("Expected BackupKeep to default to 7, got %d", config.Conf.BackupKeep)
+	}
+
+	os.Setenv("STEGODON_BACKUP_HOURS", "-1")
+	os.Setenv("STEGODON_BACKUP_KEEP", "3")
+	defer os.Unsetenv("STEGODON_BACKUP_HOURS")
+	defer os.Unsetenv("STEGODON_BACKUP_KEEP")
+
+	config, err = ReadConf()
+	if err != nil {
+		t.Fatalf("ReadConf failed: %v", err)
+	}
+	if config.Conf.BackupHours != 0 || config.Conf.BackupKeep != 3 {
+		t.Errorf("Expected backups disabled with 3 kept, got %d hours, %d kept", config.Conf.BackupHours, config.Conf.BackupKeep)
+	}
+}
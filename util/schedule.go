@@ -0,0 +1,88 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScheduleTimeFormat is the format scheduled times are shown and edited in (server time zone)
+const ScheduleTimeFormat = "2006-01-02 15:04"
+
+// scheduleLayouts are the accepted absolute time formats, tried in order
+var scheduleLayouts = []string{
+	time.RFC3339,
+	ScheduleTimeFormat,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+}
+
+// ParseScheduleTime parses when a note should be published, relative to now:
+//   - relative: "+30m", "+2h", "+1d", "+1h30m"
+//   - absolute: "2006-01-02 15:04", "2006-01-02T15:04" or RFC3339
+//   - time of day: "07:30" (today, or tomorrow if that time has passed)
+//
+// Absolute times without a zone are in the server's time zone. The result must lie in the future.
+func ParseScheduleTime(input string, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return time.Time{}, fmt.Errorf("no time given")
+	}
+
+	var at time.Time
+	switch {
+	case strings.HasPrefix(input, "+"):
+		d, err := parseScheduleDuration(input[1:])
+		if err != nil {
+			return time.Time{}, err
+		}
+		at = now.Add(d)
+	case len(input) == 5 && input[2] == ':':
+		clock, err := time.ParseInLocation("15:04", input, now.Location())
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time of day %q", input)
+		}
+		at = time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+	default:
+		parsed := false
+		for _, layout := range scheduleLayouts {
+			if t, err := time.ParseInLocation(layout, input, now.Location()); err == nil {
+				at, parsed = t, true
+				break
+			}
+		}
+		if !parsed {
+			return time.Time{}, fmt.Errorf("invalid time %q (use +2h, 07:30 or %s)", input, ScheduleTimeFormat)
+		}
+	}
+
+	if !at.After(now) {
+		return time.Time{}, fmt.Errorf("scheduled time %s is in the past", at.Format(ScheduleTimeFormat))
+	}
+	return at, nil
+}
+
+// parseScheduleDuration parses a Go duration with an additional "d" (day) unit, e.g. "1d12h"
+func parseScheduleDuration(s string) (time.Duration, error) {
+	var total time.Duration
+	if i := strings.Index(s, "d"); i >= 0 {
+		days, err := strconv.Atoi(s[:i])
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid duration %q", "+"+s)
+		}
+		total = time.Duration(days) * 24 * time.Hour
+		s = s[i+1:]
+		if s == "" {
+			return total, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", "+"+s)
+	}
+	return total + d, nil
+}
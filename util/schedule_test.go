@@ -0,0 +1,41 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 22, 15, 0, 0, time.UTC)
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"+2h", now.Add(2 * time.Hour)},
+		{"+45m", now.Add(45 * time.Minute)},
+		{"+1d", now.Add(24 * time.Hour)},
+		{"+1d6h", now.Add(30 * time.Hour)},
+		{"07:30", time.Date(2026, 3, 11, 7, 30, 0, 0, time.UTC)},
+		{"23:00", time.Date(2026, 3, 10, 23, 0, 0, 0, time.UTC)},
+		{"2026-03-11 08:00", time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC)},
+		{"2026-03-11T08:00", time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC)},
+		{"2026-03-11T08:00:00+01:00", time.Date(2026, 3, 11, 7, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseScheduleTime(tt.input, now)
+		if err != nil {
+			t.Errorf("ParseScheduleTime(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseScheduleTime(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "tomorrow", "+2x", "+-1h", "2026-03-09 08:00", "+0m", "25:99"} {
+		if _, err := ParseScheduleTime(input, now); err == nil {
+			t.Errorf("ParseScheduleTime(%q): expected error", input)
+		}
+	}
+}
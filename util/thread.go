@@ -0,0 +1,180 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// ThreadSeparator on a line of its own starts the next note of a thread
+	ThreadSeparator = "---"
+	// MaxThreadParts is the maximum number of notes a thread can be split into
+	MaxThreadParts = 20
+)
+
+// sentenceEndRegex matches the end of a sentence (punctuation, closing quotes, whitespace)
+var sentenceEndRegex = regexp.MustCompile(`[.!?…]+["')\]]*\s+`)
+
+// threadSplitLevel is one way of breaking text into smaller units
+type threadSplitLevel struct {
+	split func(string) []string
+	join  string
+}
+
+// threadSplitLevels are tried in order until every unit fits: paragraphs, sentences, words
+var threadSplitLevels = []threadSplitLevel{
+	{split: splitParagraphs, join: "\n\n"},
+	{split: splitSentences, join: " "},
+	{split: strings.Fields, join: " "},
+}
+
+// SplitThread splits text that is too long for one note into numbered notes
+// ("… 1/3") of at most maxChars visible characters each. Lines containing only
+// "---" force a split; otherwise text is broken on paragraph and sentence
+// boundaries, falling back to words. Text that fits in one note is returned as is.
+func SplitThread(text string, maxChars int) ([]string, error) {
+	chunks := splitThreadChunks(text)
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("Thread is empty")
+	}
+	if len(chunks) == 1 && fitsNote(chunks[0], maxChars) {
+		return chunks, nil
+	}
+
+	// The numbering takes space in every note, so split again if the
+	// number of notes turns out to need more digits than reserved
+	count := 9
+	for {
+		suffixLen := len(threadSuffix(count, count))
+		var parts []string
+		for _, chunk := range chunks {
+			parts = append(parts, splitToFit(chunk, 0, func(s string) bool {
+				return fitsNote(s, maxChars-suffixLen)
+			})...)
+		}
+		if len(parts) > MaxThreadParts {
+			return nil, fmt.Errorf("Thread too long (%d notes, max %d)", len(parts), MaxThreadParts)
+		}
+		if len(threadSuffix(len(parts), len(parts))) > suffixLen {
+			count = len(parts)
+			continue
+		}
+		for i := range parts {
+			parts[i] += threadSuffix(i+1, len(parts))
+		}
+		return parts, nil
+	}
+}
+
+// HasThreadSeparator reports whether text contains a manual thread split ("---" line)
+func HasThreadSeparator(text string) bool {
+	return len(splitThreadChunks(text)) > 1
+}
+
+// threadSuffix is the numbering appended to each note of a thread
+func threadSuffix(n, total int) string {
+	return fmt.Sprintf(" %d/%d", n, total)
+}
+
+// fitsNote checks a note against both the visible character limit and the database limit
+func fitsNote(text string, maxChars int) bool {
+	return CountVisibleChars(text) <= maxChars && ValidateNoteLength(text) == nil
+}
+
+// splitThreadChunks splits text on "---" separator lines, dropping empty chunks
+func splitThreadChunks(text string) []string {
+	var chunks []string
+	var current []string
+	flush := func() {
+		if chunk := strings.TrimSpace(strings.Join(current, "\n")); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		current = nil
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == ThreadSeparator {
+			flush()
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+	return chunks
+}
+
+// splitToFit breaks text into pieces that fit, packing as many units of the
+// current level into each piece as possible and going one level finer for
+// units that are too long on their own
+func splitToFit(text string, level int, fits func(string) bool) []string {
+	if fits(text) {
+		return []string{text}
+	}
+	if level == len(threadSplitLevels) {
+		return splitRunesToFit(text, fits)
+	}
+
+	lvl := threadSplitLevels[level]
+	var parts []string
+	current := ""
+	for _, unit := range lvl.split(text) {
+		if current != "" && fits(current+lvl.join+unit) {
+			current += lvl.join + unit
+			continue
+		}
+		if current != "" {
+			parts = append(parts, current)
+		}
+		pieces := splitToFit(unit, level+1, fits)
+		if len(pieces) == 0 {
+			continue
+		}
+		parts = append(parts, pieces[:len(pieces)-1]...)
+		current = pieces[len(pieces)-1]
+	}
+	if current != "" {
+		parts = append(parts, current)
+	}
+	return parts
+}
+
+// splitRunesToFit hard-wraps a single word that is too long for a note
+func splitRunesToFit(text string, fits func(string) bool) []string {
+	var parts []string
+	var current []rune
+	for _, r := range text {
+		if len(current) > 0 && !fits(string(append(current, r))) {
+			parts = append(parts, string(current))
+			current = nil
+		}
+		current = append(current, r)
+	}
+	return append(parts, string(current))
+}
+
+// splitParagraphs splits text on blank lines
+func splitParagraphs(text string) []string {
+	var paragraphs []string
+	for _, p := range strings.Split(text, "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return paragraphs
+}
+
+// splitSentences splits text after sentence-ending punctuation
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for _, loc := range sentenceEndRegex.FindAllStringIndex(text, -1) {
+		if s := strings.TrimSpace(text[start:loc[1]]); s != "" {
+			sentences = append(sentences, s)
+		}
+		start = loc[1]
+	}
+	if s := strings.TrimSpace(text[start:]); s != "" {
+		sentences = append(sentences, s)
+	}
+	return sentences
+}
@@ -0,0 +1,103 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitThreadShortText(t *testing.T) {
+	parts, err := SplitThread("  Just one note.  ", 150)
+	if err != nil {
+		t.Fatalf("SplitThread() error: %v", err)
+	}
+	if len(parts) != 1 || parts[0] != "Just one note." {
+		t.Errorf("Expected a single unnumbered note, got %q", parts)
+	}
+}
+
+func TestSplitThreadManualSeparator(t *testing.T) {
+	parts, err := SplitThread("First part.\n---\nSecond part.\n\n---\n\n---\nThird part.", 150)
+	if err != nil {
+		t.Fatalf("SplitThread() error: %v", err)
+	}
+	want := []string{"First part. 1/3", "Second part. 2/3", "Third part. 3/3"}
+	if strings.Join(parts, "|") != strings.Join(want, "|") {
+		t.Errorf("SplitThread() = %q, want %q", parts, want)
+	}
+}
+
+func TestSplitThreadSentences(t *testing.T) {
+	text := "This is the first sentence of the thread. This is the second one! Is this the third? And here is the fourth sentence."
+	parts, err := SplitThread(text, 50)
+	if err != nil {
+		t.Fatalf("SplitThread() error: %v", err)
+	}
+	if len(parts) < 2 {
+		t.Fatalf("Expected several notes, got %q", parts)
+	}
+	for i, part := range parts {
+		if CountVisibleChars(part) > 50 {
+			t.Errorf("Part %d too long (%d chars): %q", i, CountVisibleChars(part), part)
+		}
+	}
+	if parts[0] != "This is the first sentence of the thread. 1/3" {
+		t.Errorf("Expected split on a sentence boundary, got %q", parts[0])
+	}
+	if !strings.HasSuffix(parts[len(parts)-1], "3/3") {
+		t.Errorf("Expected numbering on the last note, got %q", parts[len(parts)-1])
+	}
+}
+
+func TestSplitThreadParagraphsAndWords(t *testing.T) {
+	text := "Short paragraph.\n\n" + strings.Repeat("word ", 40) + "\n\nEnd."
+	parts, err := SplitThread(text, 60)
+	if err != nil {
+		t.Fatalf("SplitThread() error: %v", err)
+	}
+	for i, part := range parts {
+		if CountVisibleChars(part) > 60 {
+			t.Errorf("Part %d too long: %q", i, part)
+		}
+		if strings.Contains(part, "wor ") || strings.HasPrefix(part, "d ") {
+			t.Errorf("Expected words not to be cut, got %q", part)
+		}
+	}
+	if !strings.HasPrefix(parts[0], "Short paragraph.") {
+		t.Errorf("Unexpected first note %q", parts[0])
+	}
+}
+
+func TestSplitThreadLongWord(t *testing.T) {
+	parts, err := SplitThread(strings.Repeat("x", 120), 50)
+	if err != nil {
+		t.Fatalf("SplitThread() error: %v", err)
+	}
+	if len(parts) != 3 {
+		t.Errorf("Expected a long word to be hard-wrapped into 3 notes, got %q", parts)
+	}
+}
+
+func TestSplitThreadTooLong(t *testing.T) {
+	if _, err := SplitThread(strings.Repeat("This is a sentence. ", 500), 50); err == nil {
+		t.Error("Expected an error for a thread with too many notes")
+	}
+	if _, err := SplitThread(" \n---\n ", 50); err == nil {
+		t.Error("Expected an error for an empty thread")
+	}
+}
+
+func TestSplitThreadNumberingWidth(t *testing.T) {
+	// 12 notes need a wider suffix ("10/12") than first reserved
+	parts, err := SplitThread(strings.Repeat("abcdefghi ", 60), 50)
+	if err != nil {
+		t.Fatalf("SplitThread() error: %v", err)
+	}
+	if len(parts) < 10 {
+		t.Fatalf("Expected at least 10 notes, got %d", len(parts))
+	}
+	for i, part := range parts {
+		if CountVisibleChars(part) > 50 {
+			t.Errorf("Part %d too long (%d chars): %q", i, CountVisibleChars(part), part)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"html"
 	"log"
+	"net/url"
 	"regexp"
 	"strings"
 	"unicode/utf8"
@@ -29,6 +30,7 @@ var hashtagRegex = regexp.MustCompile(`#([a-zA-Z][a-zA-Z0-9_]*)`)
 var mentionRegex = regexp.MustCompile(`@([a-zA-Z0-9_]+)@([a-zA-Z0-9.-]+\.[a-zA-Z]{2,})`)
 var markdownLinkRegex = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
 var htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+var emojiShortcodeRegex = regexp.MustCompile(`:([a-zA-Z0-9_]{2,64}):`)
 
 // ANSI color codes for terminal highlighting (must match ui/common/styles.go values)
 const (
@@ -618,6 +620,193 @@ func MentionsToActivityPubHTML(text string, mentionURIs map[string]string) strin
 	})
 }
 
+// ParseEmojiShortcodes extracts :shortcode: custom emoji references from text.
+// Returns deduplicated shortcodes (without colons) preserving order of first occurrence.
+func ParseEmojiShortcodes(text string) []string {
+	matches := emojiShortcodeRegex.FindAllStringSubmatch(text, -1)
+
+	seen := make(map[string]bool)
+	shortcodes := make([]string, 0, len(matches))
+
+	for _, match := range matches {
+		if len(match) >= 2 && !seen[match[1]] {
+			seen[match[1]] = true
+			shortcodes = append(shortcodes, match[1])
+		}
+	}
+
+	return shortcodes
+}
+
+// CustomEmojiToHTML replaces known :shortcode: references with inline <img> tags.
+// The emojis map is keyed by shortcode (without colons) and holds the image URL.
+// Only text between tags is replaced, so shortcodes inside linkified URLs stay part of
+// the href. Unknown shortcodes and emoji with unsafe image URLs are left untouched.
+func CustomEmojiToHTML(text string, emojis map[string]string) string {
+	if len(emojis) == 0 {
+		return text
+	}
+	replace := func(s string) string {
+		return emojiShortcodeRegex.ReplaceAllStringFunc(s, func(match string) string {
+			shortcode := strings.Trim(match, ":")
+			imageURL, ok := emojis[shortcode]
+			if !ok || !isEmojiImageURL(imageURL) {
+				return match
+			}
+			return fmt.Sprintf(`<img src="%s" alt="%s" title="%s" class="custom-emoji" loading="lazy">`,
+				html.EscapeString(imageURL), match, match)
+		})
+	}
+
+	var b strings.Builder
+	for text != "" {
+		start := strings.IndexByte(text, '<')
+		if start < 0 {
+			b.WriteString(replace(text))
+			break
+		}
+		end := strings.IndexByte(text[start:], '>')
+		if end < 0 {
+			b.WriteString(replace(text))
+			break
+		}
+		end += start + 1
+		b.WriteString(replace(text[:start]))
+		b.WriteString(text[start:end])
+		text = text[end:]
+	}
+	return b.String()
+}
+
+// isEmojiImageURL reports whether an emoji image URL is safe to put in a src attribute:
+// an absolute http(s) URL, or a local path like /emojis/blobcat.png
+func isEmojiImageURL(imageURL string) bool {
+	if strings.HasPrefix(imageURL, "/") && !strings.HasPrefix(imageURL, "//") {
+		_, err := url.Parse(imageURL)
+		return err == nil
+	}
+	return isRemoteImageURL(imageURL)
+}
+
+// isRemoteImageURL reports whether a URL from a remote server is an absolute http(s) URL
+func isRemoteImageURL(imageURL string) bool {
+	u, err := url.Parse(imageURL)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Scheme == "http" || u.Scheme == "https"
+}
+
+// CustomEmojiToTerminal renders known :shortcode: references as OSC 8 hyperlinks to the emoji image.
+// The text stays :shortcode: since terminals can't show inline images.
+// Relative image URLs (local emoji) are resolved against baseURL.
+func CustomEmojiToTerminal(text string, emojis map[string]string, baseURL string) string {
+	if len(emojis) == 0 {
+		return text
+	}
+	return emojiShortcodeRegex.ReplaceAllStringFunc(text, func(match string) string {
+		shortcode := strings.Trim(match, ":")
+		imageURL, ok := emojis[shortcode]
+		if !ok || imageURL == "" {
+			return match
+		}
+		if strings.HasPrefix(imageURL, "/") {
+			imageURL = baseURL + imageURL
+		}
+		// OSC 8 format with link color, like markdown links
+		return fmt.Sprintf("\033[38;2;"+ansiLinkRGB+"m\033]8;;%s\033\\%s\033]8;;\033\\\033[39m", imageURL, match)
+	})
+}
+
+// ExtractEmojiTagsFromJSON extracts custom emoji from the Emoji tags of an ActivityPub Create activity.
+// Returns a shortcode -> image URL map, or nil if the object has no Emoji tags.
+// Icons that are not absolute http(s) URLs are skipped.
+func ExtractEmojiTagsFromJSON(rawJSON string) map[string]string {
+	if !strings.Contains(rawJSON, `"Emoji"`) {
+		return nil
+	}
+
+	var activityWrapper struct {
+		Object struct {
+			Tag []struct {
+				Type string `json:"type"`
+				Name string `json:"name"`
+				Icon struct {
+					URL string `json:"url"`
+				} `json:"icon"`
+			} `json:"tag"`
+		} `json:"object"`
+	}
+	if err := json.Unmarshal([]byte(rawJSON), &activityWrapper); err != nil {
+		return nil
+	}
+
+	var emojis map[string]string
+	for _, tag := range activityWrapper.Object.Tag {
+		if tag.Type != "Emoji" || !isRemoteImageURL(tag.Icon.URL) {
+			continue
+		}
+		shortcode := strings.Trim(tag.Name, ":")
+		if shortcode == "" {
+			continue
+		}
+		if emojis == nil {
+			emojis = make(map[string]string)
+		}
+		emojis[shortcode] = tag.Icon.URL
+	}
+	return emojis
+}
+
+// NormalizeHashtag strips a leading # and lowercases a hashtag name.
+// Returns false if the result is not a valid hashtag (must start with a letter,
+// followed by letters, numbers, or underscores).
+func NormalizeHashtag(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	if len(tag) == 0 || len(tag) > 100 {
+		return "", false
+	}
+	if match := hashtagRegex.FindString("#" + tag); match != "#"+tag {
+		return "", false
+	}
+	return tag, true
+}
+
+// ExtractHashtagsFromJSON extracts hashtag names from the Hashtag tags of an ActivityPub Create activity.
+// Returns lowercase, deduplicated names without the leading #.
+func ExtractHashtagsFromJSON(rawJSON string) []string {
+	if !strings.Contains(rawJSON, `"Hashtag"`) {
+		return nil
+	}
+
+	var activityWrapper struct {
+		Object struct {
+			Tag []struct {
+				Type string `json:"type"`
+				Name string `json:"name"`
+			} `json:"tag"`
+		} `json:"object"`
+	}
+	if err := json.Unmarshal([]byte(rawJSON), &activityWrapper); err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var tags []string
+	for _, tag := range activityWrapper.Object.Tag {
+		if tag.Type != "Hashtag" {
+			continue
+		}
+		name, ok := NormalizeHashtag(tag.Name)
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		tags = append(tags, name)
+	}
+	return tags
+}
+
 // ReplacePlaceholders replaces template placeholders in text with actual values
 // Currently supports: {{SSH_PORT}}
 func ReplacePlaceholders(text string, sshPort int) string {
@@ -885,8 +1074,19 @@ func NormalizeEmojis(text string) string {
 // TruncateContent truncates content to maxLen characters, adding "[more]" indicator.
 // Users can press 'o' to view full content via original link.
 func TruncateContent(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	return truncateRunes(s, maxLen, "… [more]")
+}
+
+// TruncatePreview shortens s to maxLen characters, adding "..." if it was cut.
+// It counts runes, so multi-byte characters are never split.
+func TruncatePreview(s string, maxLen int) string {
+	return truncateRunes(s, maxLen, "...")
+}
+
+// truncateRunes cuts s after maxLen runes and appends suffix, or returns s if it is short enough
+func truncateRunes(s string, maxLen int, suffix string) string {
+	if utf8.RuneCountInString(s) <= maxLen {
 		return s
 	}
-	return s[:maxLen] + "… [more]"
+	return string([]rune(s)[:maxLen]) + suffix
 }
@@ -2033,3 +2033,172 @@ func TestSanitizeRemoteContent_CJKPunctuation(t *testing.T) {
 		})
 	}
 }
+
+func TestParseEmojiShortcodes(t *testing.T) {
+	result := ParseEmojiShortcodes("hello :blobcat: and :party_parrot: again :blobcat: at 12:30:45")
+
+	expected := []string{"blobcat", "party_parrot", "30"}
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+	for i, shortcode := range expected {
+		if result[i] != shortcode {
+			t.Errorf("Expected %q at %d, got %q", shortcode, i, result[i])
+		}
+	}
+}
+
+func TestCustomEmojiToHTML(t *testing.T) {
+	emojis := map[string]string{"blobcat": "https://remote.example/emoji/blobcat.png"}
+	result := CustomEmojiToHTML("hi :blobcat: :unknown:", emojis)
+
+	expected := `hi <img src="https://remote.example/emoji/blobcat.png" alt=":blobcat:" title=":blobcat:" class="custom-emoji" loading="lazy"> :unknown:`
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestCustomEmojiToHTML_NoEmojis(t *testing.T) {
+	input := "time is 12:30:45"
+	if result := CustomEmojiToHTML(input, nil); result != input {
+		t.Errorf("Expected unchanged text, got %q", result)
+	}
+}
+
+func TestCustomEmojiToHTML_ShortcodeInLink(t *testing.T) {
+	// A shortcode at the end of a linkified URL must not break out of the href
+	emojis := map[string]string{"blobcat": "x onmouseover=alert(document.cookie) y"}
+	text := LinkifyRawURLsHTML(MarkdownLinksToHTML("https://evil.example/:blobcat:"))
+	result := CustomEmojiToHTML(text, emojis)
+
+	if result != text {
+		t.Errorf("Expected the link to be left alone, got %q", result)
+	}
+	if strings.Contains(result, "onmouseover") {
+		t.Errorf("Expected the icon URL to be rejected, got %q", result)
+	}
+
+	// Valid emoji inside the link text are still rendered, the href is kept intact
+	emojis = map[string]string{"blobcat": "https://remote.example/emoji/blobcat.png"}
+	result = CustomEmojiToHTML(text, emojis)
+	if !strings.Contains(result, `<a href="https://evil.example/:blobcat:"`) {
+		t.Errorf("Expected the href to keep the shortcode, got %q", result)
+	}
+	if !strings.Contains(result, `>https://evil.example/<img src="https://remote.example/emoji/blobcat.png"`) {
+		t.Errorf("Expected the emoji in the link text, got %q", result)
+	}
+}
+
+func TestCustomEmojiToHTML_RejectsUnsafeURLs(t *testing.T) {
+	for _, imageURL := range []string{"javascript:alert(1)", "//evil.example/x.png", "x onerror=alert(1)", "data:image/png;base64,AAAA"} {
+		result := CustomEmojiToHTML("hi :blobcat:", map[string]string{"blobcat": imageURL})
+		if result != "hi :blobcat:" {
+			t.Errorf("Expected %q to be rejected, got %q", imageURL, result)
+		}
+	}
+	if result := CustomEmojiToHTML(":blobcat:", map[string]string{"blobcat": "/emojis/blobcat.png"}); !strings.Contains(result, `src="/emojis/blobcat.png"`) {
+		t.Errorf("Expected local emoji paths to be allowed, got %q", result)
+	}
+}
+
+func TestCustomEmojiToTerminal(t *testing.T) {
+	emojis := map[string]string{"blobcat": "/emojis/blobcat.png"}
+	result := CustomEmojiToTerminal("hi :blobcat:", emojis, "https://example.com")
+
+	if !strings.Contains(result, "\033]8;;https://example.com/emojis/blobcat.png\033\\:blobcat:\033]8;;\033\\") {
+		t.Errorf("Expected OSC 8 link to resolved emoji URL, got %q", result)
+	}
+	if !strings.HasPrefix(result, "hi ") {
+		t.Errorf("Expected surrounding text to be preserved, got %q", result)
+	}
+}
+
+func TestExtractEmojiTagsFromJSON(t *testing.T) {
+	rawJSON := `{"type":"Create","object":{"content":"hi :blobcat:","tag":[
+		{"type":"Hashtag","name":"#cats"},
+		{"type":"Emoji","name":":blobcat:","icon":{"type":"Image","url":"https://remote.example/emoji/blobcat.png"}}
+	]}}`
+
+	emojis := ExtractEmojiTagsFromJSON(rawJSON)
+	if len(emojis) != 1 {
+		t.Fatalf("Expected 1 emoji, got %v", emojis)
+	}
+	if emojis["blobcat"] != "https://remote.example/emoji/blobcat.png" {
+		t.Errorf("Unexpected emoji URL: %q", emojis["blobcat"])
+	}
+
+	if ExtractEmojiTagsFromJSON(`{"type":"Create","object":{"content":"hi"}}`) != nil {
+		t.Error("Expected nil for activity without Emoji tags")
+	}
+
+	unsafe := `{"type":"Create","object":{"tag":[
+		{"type":"Emoji","name":":blobcat:","icon":{"url":"x onmouseover=alert(document.cookie) y"}},
+		{"type":"Emoji","name":":parrot:","icon":{"url":"javascript:alert(1)"}}
+	]}}`
+	if emojis := ExtractEmojiTagsFromJSON(unsafe); len(emojis) != 0 {
+		t.Errorf("Expected icons that are not http(s) URLs to be skipped, got %v", emojis)
+	}
+}
+
+func TestNormalizeHashtag(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{"golang", "golang", true},
+		{"#GoLang", "golang", true},
+		{" #go_lang2 ", "go_lang2", true},
+		{"", "", false},
+		{"#", "", false},
+		{"2cool", "", false},
+		{"go-lang", "", false},
+		{"go lang", "", false},
+	}
+
+	for _, tt := range tests {
+		result, ok := NormalizeHashtag(tt.input)
+		if ok != tt.valid || result != tt.expected {
+			t.Errorf("NormalizeHashtag(%q) = (%q, %v), expected (%q, %v)", tt.input, result, ok, tt.expected, tt.valid)
+		}
+	}
+}
+
+func TestExtractHashtagsFromJSON(t *testing.T) {
+	rawJSON := `{"type":"Create","object":{"content":"hi","tag":[
+		{"type":"Hashtag","name":"#Cats","href":"https://remote.example/tags/cats"},
+		{"type":"Mention","name":"@alice@example.com"},
+		{"type":"Hashtag","name":"#cats"},
+		{"type":"Hashtag","name":"#dogs"}
+	]}}`
+
+	tags := ExtractHashtagsFromJSON(rawJSON)
+	if len(tags) != 2 || tags[0] != "cats" || tags[1] != "dogs" {
+		t.Errorf("Expected [cats dogs], got %v", tags)
+	}
+
+	if tags := ExtractHashtagsFromJSON(`{"type":"Create","object":{"content":"#cats"}}`); tags != nil {
+		t.Errorf("Expected nil for activity without Hashtag tags, got %v", tags)
+	}
+}
+
+func TestTruncatePreview(t *testing.T) {
+	tests := []struct {
+		input    string
+		maxLen   int
+		expected string
+	}{
+		{"hello", 10, "hello"},
+		{"hello world", 5, "hello..."},
+		{"héllo wörld", 5, "héllo..."},
+		{"日本語のテキスト", 3, "日本語..."},
+		{"🦕🦖🦕", 2, "🦕🦖..."},
+		{"🦕🦖", 2, "🦕🦖"},
+	}
+
+	for _, tt := range tests {
+		if result := TruncatePreview(tt.input, tt.maxLen); result != tt.expected {
+			t.Errorf("TruncatePreview(%q, %d) = %q, expected %q", tt.input, tt.maxLen, result, tt.expected)
+		}
+	}
+}
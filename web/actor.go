@@ -7,6 +7,7 @@ import (
 
 	"strings"
 
+	"github.com/deemkeen/stegodon/activitypub"
 	"github.com/deemkeen/stegodon/db"
 	"github.com/deemkeen/stegodon/util"
 	"github.com/google/uuid"
@@ -207,9 +208,13 @@ func GetNoteObject(noteId uuid.UUID, conf *util.AppConfig) (error, string) {
 		contentHTML = util.MentionsToActivityPubHTML(contentHTML, mentionURIs)
 	}
 
+	// Add Emoji tags for local custom emoji used in the note
+	emojiTags := activitypub.EmojiTags(note.Message, baseURL, localEmojiMap())
+	tags = append(tags, emojiTags...)
+
 	// Build the Note object
 	noteObj := map[string]any{
-		"@context":     "https://www.w3.org/ns/activitystreams",
+		"@context":     activitypub.NoteContext(len(hashtags) > 0, len(emojiTags) > 0),
 		"id":           noteURI,
 		"type":         "Note",
 		"attributedTo": actorURI,
@@ -233,6 +238,9 @@ func GetNoteObject(noteId uuid.UUID, conf *util.AppConfig) (error, string) {
 		noteObj["updated"] = note.EditedAt.Format(time.RFC3339)
 	}
 
+	// Long-form notes are served as Articles
+	activitypub.ApplyArticle(noteObj, note)
+
 	jsonBytes, err := json.Marshal(noteObj)
 	if err != nil {
 		return err, "{}"
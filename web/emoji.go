@@ -0,0 +1,121 @@
+package web
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	maxEmojiSize  = 128 // Max dimension for custom emoji
+	emojisDirName = "emojis"
+)
+
+// shortcodeRegex matches valid custom emoji shortcodes (without colons)
+var shortcodeRegex = regexp.MustCompile(`^[a-zA-Z0-9_]{2,64}$`)
+
+// isValidShortcode checks if a shortcode can be used for a custom emoji
+func isValidShortcode(shortcode string) bool {
+	return shortcodeRegex.MatchString(shortcode)
+}
+
+// getEmojisDir returns the path to the custom emoji directory
+func getEmojisDir() (string, error) {
+	configDir, err := util.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	emojisDir := filepath.Join(configDir, emojisDirName)
+
+	// Create directory if it doesn't exist
+	if err := os.MkdirAll(emojisDir, 0755); err != nil {
+		return "", err
+	}
+
+	return emojisDir, nil
+}
+
+// localEmojiMap returns all local custom emoji as a shortcode -> image URL map
+func localEmojiMap() map[string]string {
+	err, emojis := db.GetDB().ReadAllCustomEmojis()
+	if err != nil {
+		log.Printf("Failed to read custom emoji: %v", err)
+		return nil
+	}
+	if emojis == nil {
+		return nil
+	}
+	return domain.EmojiMap(*emojis)
+}
+
+// saveCustomEmoji resizes and stores an uploaded emoji image and registers its shortcode
+func saveCustomEmoji(img image.Image, shortcode string, accountId uuid.UUID) error {
+	emojisDir, err := getEmojisDir()
+	if err != nil {
+		return fmt.Errorf("failed to get emoji directory: %w", err)
+	}
+
+	// Save as PNG for consistency
+	filename := fmt.Sprintf("%s.png", shortcode)
+	outFile, err := os.Create(filepath.Join(emojisDir, filename))
+	if err != nil {
+		return fmt.Errorf("failed to create emoji file: %w", err)
+	}
+	defer outFile.Close()
+
+	if err := png.Encode(outFile, resizeImage(img, maxEmojiSize)); err != nil {
+		return fmt.Errorf("failed to encode emoji as PNG: %w", err)
+	}
+
+	return db.GetDB().SaveCustomEmoji(&domain.CustomEmoji{
+		Id:        uuid.New(),
+		Shortcode: shortcode,
+		ImageURL:  fmt.Sprintf("/emojis/%s", filename),
+		MediaType: "image/png",
+		CreatedBy: accountId,
+		CreatedAt: time.Now(),
+	})
+}
+
+// ServeEmoji serves custom emoji images from the emoji directory
+func ServeEmoji(c *gin.Context, conf *util.AppConfig) {
+	filename := c.Param("filename")
+
+	// Security: only allow <shortcode>.png filenames
+	if !strings.HasSuffix(filename, ".png") || !isValidShortcode(strings.TrimSuffix(filename, ".png")) {
+		c.Status(404)
+		return
+	}
+
+	emojisDir, err := getEmojisDir()
+	if err != nil {
+		c.Status(500)
+		return
+	}
+
+	emojiPath := filepath.Join(emojisDir, filename)
+	if _, err := os.Stat(emojiPath); os.IsNotExist(err) {
+		c.Status(404)
+		return
+	} else if err != nil {
+		c.Status(500)
+		return
+	}
+
+	c.Header("Content-Type", "image/png")
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Header("Access-Control-Allow-Origin", "*")
+	c.File(emojiPath)
+}
@@ -0,0 +1,30 @@
+package web
+
+import "testing"
+
+func TestIsValidShortcode(t *testing.T) {
+	tests := []struct {
+		shortcode string
+		expected  bool
+	}{
+		{"blobcat", true},
+		{"party_parrot", true},
+		{"Cat2", true},
+		{"ok", true},
+
+		{"", false},
+		{"x", false},             // Too short
+		{":blobcat:", false},     // Colons are not part of the shortcode
+		{"blob-cat", false},      // Dash not allowed
+		{"../etc/passwd", false}, // Path traversal attempt
+		{"blob cat", false},      // Whitespace not allowed
+		{"emoji.png", false},     // Extension not allowed
+	}
+
+	for _, tt := range tests {
+		result := isValidShortcode(tt.shortcode)
+		if result != tt.expected {
+			t.Errorf("isValidShortcode(%q) = %v, expected %v", tt.shortcode, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package web
+
+import (
+	"log"
+
+	"github.com/deemkeen/stegodon/health"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterHealthRoutes adds the liveness, readiness and status endpoints.
+// They are registered in every mode, including SSH-only.
+func RegisterHealthRoutes(g *gin.Engine, source health.Source) {
+	// Liveness: the process is up and serving HTTP
+	g.GET("/healthz", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "ok"})
+	})
+
+	// Readiness: the database answers, migrations are done and SSH is listening
+	g.GET("/readyz", func(c *gin.Context) {
+		ready, checks := health.Ready(source)
+		code, state := 200, "ready"
+		if !ready {
+			code, state = 503, "unavailable"
+		}
+		// The endpoint is public, so errors go to the log and /status only
+		public := make([]health.Check, len(checks))
+		for i, check := range checks {
+			if !check.OK {
+				log.Printf("Readiness: %s check failed: %s", check.Name, check.Error)
+			}
+			public[i] = health.Check{Name: check.Name, OK: check.OK}
+		}
+		c.JSON(code, gin.H{"status": state, "checks": public})
+	})
+
+	// Server status for admins, authenticated with an API token
+	g.GET("/status", MastodonAuthMiddleware("read"), func(c *gin.Context) {
+		if !apiAccount(c).IsAdmin {
+			apiError(c, 403, "This method requires an admin account")
+			return
+		}
+		status, err := health.ReadStatus(source)
+		if err != nil {
+			log.Printf("Status: Failed to read part of the server status: %v", err)
+		}
+		c.JSON(200, status)
+	})
+}
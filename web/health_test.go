@@ -0,0 +1,75 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/health"
+	"github.com/gin-gonic/gin"
+)
+
+// mockHealthSource implements health.Source for testing
+type mockHealthSource struct{}
+
+func (mockHealthSource) Ping() error { return nil }
+func (mockHealthSource) ReadSchemaStatus() (error, *domain.SchemaStatus) {
+	return nil, &domain.SchemaStatus{}
+}
+func (mockHealthSource) ReadDatabaseSize() (int64, error) { return 4096, nil }
+func (mockHealthSource) ReadDeliveryQueueStats() (int, time.Time, error) {
+	return 0, time.Time{}, nil
+}
+func (mockHealthSource) CountInboxQueue() (int, int, error)      { return 0, 0, nil }
+func (mockHealthSource) ReadAllRelays() (error, *[]domain.Relay) { return nil, &[]domain.Relay{} }
+
+func TestRegisterHealthRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	g := gin.New()
+	RegisterHealthRoutes(g, mockHealthSource{})
+
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+	if w.Code != 200 {
+		t.Errorf("Expected 200 from /healthz, got %d", w.Code)
+	}
+
+	// Not ready until the SSH server listens
+	health.SetSSHListening(false)
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != 503 {
+		t.Errorf("Expected 503 from /readyz, got %d", w.Code)
+	}
+	var body struct {
+		Status string         `json:"status"`
+		Checks []health.Check `json:"checks"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to parse /readyz response: %v", err)
+	}
+	if body.Status != "unavailable" || len(body.Checks) != 3 {
+		t.Errorf("Unexpected /readyz response: %s", w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "error") {
+		t.Errorf("Expected /readyz to leave out check errors, got: %s", w.Body.String())
+	}
+
+	health.SetSSHListening(true)
+	defer health.SetSSHListening(false)
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+	if w.Code != 200 {
+		t.Errorf("Expected 200 from /readyz, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Status requires a token
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest("GET", "/status", nil))
+	if w.Code != 401 {
+		t.Errorf("Expected 401 from /status without token, got %d", w.Code)
+	}
+}
@@ -0,0 +1,550 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/deemkeen/stegodon/actions"
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+// Mastodon client API entities (https://docs.joinmastodon.org/entities/).
+// Only the fields stegodon can fill are populated; the rest keep their zero values
+// so clients that expect them still parse the response.
+
+type mastodonAccount struct {
+	ID             string          `json:"id"`
+	Username       string          `json:"username"`
+	Acct           string          `json:"acct"`
+	DisplayName    string          `json:"display_name"`
+	Locked         bool            `json:"locked"`
+	Bot            bool            `json:"bot"`
+	Discoverable   bool            `json:"discoverable"`
+	Group          bool            `json:"group"`
+	CreatedAt      string          `json:"created_at"`
+	Note           string          `json:"note"`
+	URL            string          `json:"url"`
+	URI            string          `json:"uri"`
+	Avatar         string          `json:"avatar"`
+	AvatarStatic   string          `json:"avatar_static"`
+	Header         string          `json:"header"`
+	HeaderStatic   string          `json:"header_static"`
+	FollowersCount int             `json:"followers_count"`
+	FollowingCount int             `json:"following_count"`
+	StatusesCount  int             `json:"statuses_count"`
+	LastStatusAt   *string         `json:"last_status_at"`
+	Emojis         []mastodonEmoji `json:"emojis"`
+	Fields         []any           `json:"fields"`
+	Source         *mastodonSource `json:"source,omitempty"`
+}
+
+type mastodonSource struct {
+	Note      string `json:"note"`
+	Privacy   string `json:"privacy"`
+	Sensitive bool   `json:"sensitive"`
+	Language  string `json:"language"`
+	Fields    []any  `json:"fields"`
+}
+
+type mastodonEmoji struct {
+	Shortcode       string `json:"shortcode"`
+	URL             string `json:"url"`
+	StaticURL       string `json:"static_url"`
+	VisibleInPicker bool   `json:"visible_in_picker"`
+}
+
+type mastodonTag struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type mastodonStatus struct {
+	ID                 string           `json:"id"`
+	URI                string           `json:"uri"`
+	URL                string           `json:"url"`
+	CreatedAt          string           `json:"created_at"`
+	EditedAt           *string          `json:"edited_at"`
+	Account            *mastodonAccount `json:"account"`
+	Content            string           `json:"content"`
+	Visibility         string           `json:"visibility"`
+	Sensitive          bool             `json:"sensitive"`
+	SpoilerText        string           `json:"spoiler_text"`
+	MediaAttachments   []any            `json:"media_attachments"`
+	Mentions           []any            `json:"mentions"`
+	Tags               []mastodonTag    `json:"tags"`
+	Emojis             []mastodonEmoji  `json:"emojis"`
+	RepliesCount       int              `json:"replies_count"`
+	ReblogsCount       int              `json:"reblogs_count"`
+	FavouritesCount    int              `json:"favourites_count"`
+	InReplyToID        *string          `json:"in_reply_to_id"`
+	InReplyToAccountID *string          `json:"in_reply_to_account_id"`
+	Reblog             *mastodonStatus  `json:"reblog"`
+	Language           *string          `json:"language"`
+	Favourited         bool             `json:"favourited"`
+	Reblogged          bool             `json:"reblogged"`
+	Muted              bool             `json:"muted"`
+	Bookmarked         bool             `json:"bookmarked"`
+	Card               any              `json:"card"`
+	Poll               any              `json:"poll"`
+	Text               string           `json:"text,omitempty"` // Source text, only returned when deleting
+}
+
+type mastodonNotification struct {
+	ID        string           `json:"id"`
+	Type      string           `json:"type"`
+	CreatedAt string           `json:"created_at"`
+	Account   *mastodonAccount `json:"account"`
+	Status    *mastodonStatus  `json:"status,omitempty"`
+	Emoji     string           `json:"emoji,omitempty"`
+}
+
+type mastodonRelationship struct {
+	ID                  string `json:"id"`
+	Following           bool   `json:"following"`
+	ShowingReblogs      bool   `json:"showing_reblogs"`
+	Notifying           bool   `json:"notifying"`
+	FollowedBy          bool   `json:"followed_by"`
+	Blocking            bool   `json:"blocking"`
+	BlockedBy           bool   `json:"blocked_by"`
+	Muting              bool   `json:"muting"`
+	MutingNotifications bool   `json:"muting_notifications"`
+	Requested           bool   `json:"requested"`
+	DomainBlocking      bool   `json:"domain_blocking"`
+	Endorsed            bool   `json:"endorsed"`
+	Note                string `json:"note"`
+}
+
+type mastodonApplication struct {
+	ID           string   `json:"id,omitempty"`
+	Name         string   `json:"name"`
+	Website      *string  `json:"website"`
+	RedirectURI  string   `json:"redirect_uri,omitempty"`
+	RedirectURIs []string `json:"redirect_uris,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	ClientID     string   `json:"client_id,omitempty"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	VapidKey     string   `json:"vapid_key,omitempty"`
+}
+
+// mastodonNotificationTypes maps stegodon notification types to Mastodon ones
+var mastodonNotificationTypes = map[domain.NotificationType]string{
+	domain.NotificationFollow:   "follow",
+	domain.NotificationLike:     "favourite",
+	domain.NotificationBoost:    "reblog",
+	domain.NotificationReply:    "mention",
+	domain.NotificationMention:  "mention",
+	domain.NotificationReaction: "pleroma:emoji_reaction",
+}
+
+// newMastodonApplication converts a registered app. Credentials are only included right after registration.
+func newMastodonApplication(app *domain.OAuthApp, withCredentials bool) mastodonApplication {
+	entity := mastodonApplication{
+		Name:   app.Name,
+		Scopes: app.Scopes,
+	}
+	if app.Website != "" {
+		website := app.Website
+		entity.Website = &website
+	}
+	if withCredentials {
+		entity.ID = app.Id.String()
+		entity.RedirectURI = strings.Join(app.RedirectURIs, "\n")
+		entity.RedirectURIs = app.RedirectURIs
+		entity.ClientID = app.ClientId
+		entity.ClientSecret = app.ClientSecret
+	}
+	return entity
+}
+
+// mastodonTime formats a timestamp the way Mastodon does
+func mastodonTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// mastodonContentHTML converts a stegodon message into the HTML content field,
+// using the same link, hashtag and mention rendering as the web UI
+func mastodonContentHTML(message, localDomain string) string {
+	text := strings.NewReplacer("<", "&lt;", ">", "&gt;").Replace(message)
+	text = util.MarkdownLinksToHTML(text)
+	text = util.LinkifyRawURLsHTML(text)
+	text = util.HighlightHashtagsHTML(text)
+	text = util.HighlightMentionsHTML(text, localDomain)
+
+	var paragraphs []string
+	for _, p := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			paragraphs = append(paragraphs, "<p>"+strings.ReplaceAll(p, "\n", "<br>")+"</p>")
+		}
+	}
+	return strings.Join(paragraphs, "")
+}
+
+// mastodonEmojis converts a shortcode -> URL map, sorted for stable output
+func mastodonEmojis(emojis map[string]string) []mastodonEmoji {
+	result := []mastodonEmoji{}
+	for shortcode, url := range emojis {
+		result = append(result, mastodonEmoji{Shortcode: shortcode, URL: url, StaticURL: url, VisibleInPicker: true})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Shortcode < result[j].Shortcode })
+	return result
+}
+
+// splitHandle splits "@user", "user" or "@user@domain" into username and domain
+func splitHandle(handle string) (string, string) {
+	username, userDomain, _ := strings.Cut(strings.TrimPrefix(handle, "@"), "@")
+	return username, userDomain
+}
+
+// reblogStatusId derives a stable id for the reblog wrapper of a boosted post
+func reblogStatusId(postId uuid.UUID, boostedBy string) uuid.UUID {
+	return uuid.NewSHA1(postId, []byte("reblog:"+boostedBy))
+}
+
+// mastodonRenderer builds API entities for one request, caching account lookups
+type mastodonRenderer struct {
+	database db.Store
+	conf     *util.AppConfig
+	viewer   *domain.Account // nil for unauthenticated requests
+	accounts map[string]*mastodonAccount
+}
+
+func newMastodonRenderer(database db.Store, conf *util.AppConfig, viewer *domain.Account) *mastodonRenderer {
+	return &mastodonRenderer{
+		database: database,
+		conf:     conf,
+		viewer:   viewer,
+		accounts: map[string]*mastodonAccount{},
+	}
+}
+
+// baseURL returns the public URL of this instance
+func (r *mastodonRenderer) baseURL() string {
+	return "https://" + r.conf.Conf.SslDomain
+}
+
+// isLocalDomain reports whether a handle's domain refers to this instance
+func (r *mastodonRenderer) isLocalDomain(userDomain string) bool {
+	return userDomain == "" || strings.EqualFold(userDomain, r.conf.Conf.SslDomain)
+}
+
+// localAccount converts a local account
+func (r *mastodonRenderer) localAccount(acc *domain.Account) *mastodonAccount {
+	if cached, ok := r.accounts[acc.Username]; ok {
+		return cached
+	}
+
+	displayName := acc.DisplayName
+	if displayName == "" {
+		displayName = acc.Username
+	}
+	avatar := acc.AvatarURL
+	if avatar == "" {
+		avatar = r.baseURL() + "/static/stegologo.png"
+	} else if strings.HasPrefix(avatar, "/") {
+		avatar = r.baseURL() + avatar
+	}
+
+	entity := &mastodonAccount{
+		ID:           acc.Id.String(),
+		Username:     acc.Username,
+		Acct:         acc.Username,
+		DisplayName:  displayName,
+		Discoverable: true,
+		CreatedAt:    mastodonTime(acc.CreatedAt),
+		Note:         mastodonContentHTML(acc.Summary, r.conf.Conf.SslDomain),
+		URL:          r.baseURL() + "/u/" + acc.Username,
+		URI:          getIRI(r.conf.Conf.SslDomain, acc.Username, id),
+		Avatar:       avatar,
+		AvatarStatic: avatar,
+		Emojis:       []mastodonEmoji{},
+		Fields:       []any{},
+	}
+	if err, followers := r.database.ReadFollowersByAccountId(acc.Id); err == nil && followers != nil {
+		entity.FollowersCount = len(*followers)
+	}
+	if err, following := r.database.ReadFollowingByAccountId(acc.Id); err == nil && following != nil {
+		entity.FollowingCount = len(*following)
+	}
+	if err, notes := r.database.ReadNotesByUsername(acc.Username); err == nil && notes != nil {
+		entity.StatusesCount = len(*notes)
+		if len(*notes) > 0 {
+			last := (*notes)[0].CreatedAt
+			for _, note := range *notes {
+				if note.CreatedAt.After(last) {
+					last = note.CreatedAt
+				}
+			}
+			day := last.UTC().Format("2006-01-02")
+			entity.LastStatusAt = &day
+		}
+	}
+
+	r.accounts[acc.Username] = entity
+	return entity
+}
+
+// remoteAccount converts a cached remote account
+func (r *mastodonRenderer) remoteAccount(acc *domain.RemoteAccount) *mastodonAccount {
+	acct := acc.Username + "@" + acc.Domain
+	if cached, ok := r.accounts[acct]; ok {
+		return cached
+	}
+
+	displayName := acc.DisplayName
+	if displayName == "" {
+		displayName = acc.Username
+	}
+	avatar := acc.AvatarURL
+	if avatar == "" {
+		avatar = r.baseURL() + "/static/stegologo.png"
+	}
+
+	entity := &mastodonAccount{
+		ID:           acc.Id.String(),
+		Username:     acc.Username,
+		Acct:         acct,
+		DisplayName:  displayName,
+		CreatedAt:    mastodonTime(acc.LastFetchedAt),
+		Note:         acc.Summary,
+		URL:          fmt.Sprintf("https://%s/@%s", acc.Domain, acc.Username),
+		URI:          acc.ActorURI,
+		Avatar:       avatar,
+		AvatarStatic: avatar,
+		Emojis:       []mastodonEmoji{},
+		Fields:       []any{},
+	}
+	r.accounts[acct] = entity
+	return entity
+}
+
+// accountByHandle resolves "@user" or "@user@domain" to an account entity
+func (r *mastodonRenderer) accountByHandle(handle string) *mastodonAccount {
+	username, userDomain := splitHandle(handle)
+	if r.isLocalDomain(userDomain) {
+		if cached, ok := r.accounts[username]; ok {
+			return cached
+		}
+		err, acc := r.database.ReadAccByUsername(username)
+		if err != nil || acc == nil {
+			return nil
+		}
+		return r.localAccount(acc)
+	}
+
+	if cached, ok := r.accounts[username+"@"+userDomain]; ok {
+		return cached
+	}
+	err, acc := r.database.ReadRemoteAccountByHandle(username, userDomain)
+	if err != nil || acc == nil {
+		return nil
+	}
+	return r.remoteAccount(acc)
+}
+
+// accountById resolves a local or remote account id to an account entity
+func (r *mastodonRenderer) accountById(accountId uuid.UUID) *mastodonAccount {
+	if err, acc := r.database.ReadAccById(accountId); err == nil && acc != nil {
+		return r.localAccount(acc)
+	}
+	if err, acc := r.database.ReadRemoteAccountById(accountId); err == nil && acc != nil {
+		return r.remoteAccount(acc)
+	}
+	return nil
+}
+
+// status converts a timeline post. Boosted posts are wrapped in a reblog status.
+func (r *mastodonRenderer) status(post domain.HomePost, inReplyToURI string) *mastodonStatus {
+	account := r.accountByHandle(post.Author)
+	if account == nil {
+		return nil
+	}
+
+	status := &mastodonStatus{
+		ID:               post.ID.String(),
+		URI:              post.ObjectURI,
+		URL:              post.ObjectURL,
+		CreatedAt:        mastodonTime(post.Time),
+		Account:          account,
+		Content:          mastodonContentHTML(post.Content, r.conf.Conf.SslDomain),
+		Visibility:       "public",
+		MediaAttachments: []any{},
+		Mentions:         []any{},
+		Tags:             []mastodonTag{},
+		Emojis:           mastodonEmojis(post.Emojis),
+		RepliesCount:     post.ReplyCount,
+		ReblogsCount:     post.BoostCount,
+		FavouritesCount:  post.LikeCount,
+	}
+	if post.IsLocal {
+		localURL := fmt.Sprintf("%s/u/%s/%s", r.baseURL(), account.Username, post.NoteID)
+		if status.URI == "" {
+			status.URI = localURL
+		}
+		if status.URL == "" {
+			status.URL = localURL
+		}
+	} else if status.URL == "" {
+		status.URL = status.URI
+	}
+	for _, tag := range util.ParseHashtags(post.Content) {
+		status.Tags = append(status.Tags, mastodonTag{Name: tag, URL: r.baseURL() + "/tags/" + tag})
+	}
+
+	if inReplyToURI != "" {
+		if parentId, parentAccountId := r.resolveReplyParent(inReplyToURI); parentId != "" {
+			status.InReplyToID = &parentId
+			if parentAccountId != "" {
+				status.InReplyToAccountID = &parentAccountId
+			}
+		}
+	}
+
+	if r.viewer != nil {
+		if post.IsLocal {
+			status.Favourited, _ = r.database.HasLike(r.viewer.Id, post.NoteID)
+			status.Reblogged, _ = r.database.HasBoost(r.viewer.Id, post.NoteID)
+		} else if post.ObjectURI != "" {
+			status.Favourited, _ = r.database.HasLikeByObjectURI(r.viewer.Id, post.ObjectURI)
+			status.Reblogged, _ = r.database.HasBoostByObjectURI(r.viewer.Id, post.ObjectURI)
+		}
+	}
+
+	if post.BoostedBy == "" {
+		return status
+	}
+	booster := r.accountByHandle(post.BoostedBy)
+	if booster == nil {
+		return status
+	}
+	return &mastodonStatus{
+		ID:               reblogStatusId(post.ID, post.BoostedBy).String(),
+		URI:              status.URI,
+		URL:              status.URL,
+		CreatedAt:        status.CreatedAt,
+		Account:          booster,
+		Visibility:       "public",
+		MediaAttachments: []any{},
+		Mentions:         []any{},
+		Tags:             []mastodonTag{},
+		Emojis:           []mastodonEmoji{},
+		Reblog:           status,
+		Favourited:       status.Favourited,
+		Reblogged:        status.Reblogged,
+	}
+}
+
+// statuses converts timeline posts, skipping those whose author is unknown
+func (r *mastodonRenderer) statuses(posts []domain.HomePost) []*mastodonStatus {
+	result := []*mastodonStatus{}
+	for _, post := range posts {
+		if status := r.status(post, r.inReplyTo(post)); status != nil {
+			result = append(result, status)
+		}
+	}
+	return result
+}
+
+// inReplyTo returns the URI a timeline post replies to, read like actions.ReadPost does for single statuses
+func (r *mastodonRenderer) inReplyTo(post domain.HomePost) string {
+	if post.IsLocal {
+		if err, note := r.database.ReadNoteIdWithReplyInfo(post.NoteID); err == nil && note != nil {
+			return note.InReplyToURI
+		}
+		return ""
+	}
+	if err, activity := r.database.ReadActivityById(post.ID); err == nil && activity != nil && activity.ActivityType == "Create" {
+		return actions.ActivityInReplyTo(activity.RawJSON)
+	}
+	if post.ObjectURI != "" {
+		if err, activity := r.database.ReadActivityByObjectURI(post.ObjectURI); err == nil && activity != nil {
+			return actions.ActivityInReplyTo(activity.RawJSON)
+		}
+	}
+	return ""
+}
+
+// statusById returns the status for a local note id or a remote activity id
+func (r *mastodonRenderer) statusById(statusId uuid.UUID) *mastodonStatus {
+	post, inReplyToURI := actions.ReadPost(r.database, r.conf, statusId)
+	if post == nil {
+		return nil
+	}
+	return r.status(*post, inReplyToURI)
+}
+
+// resolveReplyParent maps an inReplyTo URI to the parent's status id and account id
+func (r *mastodonRenderer) resolveReplyParent(inReplyToURI string) (string, string) {
+	var note *domain.Note
+	if strings.HasPrefix(inReplyToURI, "local:") {
+		if noteId, err := uuid.Parse(strings.TrimPrefix(inReplyToURI, "local:")); err == nil {
+			_, note = r.database.ReadNoteId(noteId)
+		}
+	} else {
+		_, note = r.database.ReadNoteByURI(inReplyToURI)
+	}
+	if note != nil {
+		if account := r.accountByHandle(note.CreatedBy); account != nil {
+			return note.Id.String(), account.ID
+		}
+		return note.Id.String(), ""
+	}
+
+	if err, activity := r.database.ReadActivityByObjectURI(inReplyToURI); err == nil && activity != nil {
+		if err, remote := r.database.ReadRemoteAccountByActorURI(activity.ActorURI); err == nil && remote != nil {
+			return activity.Id.String(), remote.Id.String()
+		}
+		return activity.Id.String(), ""
+	}
+	return "", ""
+}
+
+// notification converts a notification, returning nil if its actor is unknown
+func (r *mastodonRenderer) notification(n domain.Notification) *mastodonNotification {
+	account := r.accountById(n.ActorId)
+	if account == nil {
+		account = r.accountByHandle(n.ActorHandle())
+	}
+	if account == nil {
+		log.Printf("Skipping notification %s: unknown actor %s", n.Id, n.ActorHandle())
+		return nil
+	}
+
+	notificationType, ok := mastodonNotificationTypes[n.NotificationType]
+	if !ok {
+		notificationType = string(n.NotificationType)
+	}
+
+	entity := &mastodonNotification{
+		ID:        n.Id.String(),
+		Type:      notificationType,
+		CreatedAt: mastodonTime(n.CreatedAt),
+		Account:   account,
+		Emoji:     n.Emoji,
+	}
+	if n.NoteId != uuid.Nil {
+		entity.Status = r.statusById(n.NoteId)
+	}
+	return entity
+}
+
+// relationship describes how the viewer relates to an account
+func (r *mastodonRenderer) relationship(targetId uuid.UUID) mastodonRelationship {
+	rel := mastodonRelationship{ID: targetId.String(), ShowingReblogs: true}
+	if r.viewer == nil {
+		return rel
+	}
+	if err, follow := r.database.ReadFollowByAccountIds(r.viewer.Id, targetId); err == nil && follow != nil {
+		rel.Following = follow.Accepted
+		rel.Requested = !follow.Accepted
+	}
+	if err, follow := r.database.ReadFollowByAccountIds(targetId, r.viewer.Id); err == nil && follow != nil {
+		rel.FollowedBy = follow.Accepted
+	}
+	return rel
+}
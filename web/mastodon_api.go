@@ -0,0 +1,1089 @@
+package web
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deemkeen/stegodon/actions"
+	"github.com/deemkeen/stegodon/activitypub"
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// apiDefaultLimit and apiMaxLimit bound the page size of list endpoints
+	apiDefaultLimit = 20
+	apiMaxLimit     = 40
+	// apiThreadWindow bounds how many replies of a thread are collected
+	apiThreadWindow = 400
+	// apiTokenTouchInterval limits how often a token's last use is written
+	apiTokenTouchInterval = time.Minute
+)
+
+// Context keys set by the API authentication middleware
+const (
+	apiTokenKey   = "apiToken"
+	apiAccountKey = "apiAccount"
+)
+
+// apiError writes a Mastodon-style error response
+func apiError(c *gin.Context, status int, message string) {
+	c.AbortWithStatusJSON(status, gin.H{"error": message})
+}
+
+// authenticateMastodonRequest validates the bearer token of a request, if any.
+// Returns a nil token (and no error) when the request carries no token.
+func authenticateMastodonRequest(c *gin.Context) (*domain.OAuthToken, *domain.Account, error) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		return nil, nil, nil
+	}
+	scheme, accessToken, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(accessToken) == "" {
+		return nil, nil, fmt.Errorf("The access token is invalid")
+	}
+
+	database := db.GetDB()
+	err, token := database.ReadOAuthTokenByHash(hashOAuthToken(strings.TrimSpace(accessToken)))
+	if err != nil || token == nil {
+		return nil, nil, fmt.Errorf("The access token is invalid")
+	}
+
+	if time.Since(token.LastUsedAt) > apiTokenTouchInterval {
+		if err := database.TouchOAuthToken(token.Id); err != nil {
+			log.Printf("Failed to update token last use: %v", err)
+		}
+	}
+
+	if token.AccountId == uuid.Nil {
+		return token, nil, nil
+	}
+	err, account := database.ReadAccById(token.AccountId)
+	if err != nil || account == nil {
+		return nil, nil, fmt.Errorf("The access token is invalid")
+	}
+	if account.Banned {
+		return nil, nil, fmt.Errorf("Your login is currently disabled")
+	}
+	return token, account, nil
+}
+
+// MastodonAuthMiddleware authenticates API requests. With a scope, a user token granting
+// that scope is required; without one, a token is optional but must be valid if present.
+func MastodonAuthMiddleware(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, account, err := authenticateMastodonRequest(c)
+		if err != nil {
+			apiError(c, 401, err.Error())
+			return
+		}
+		if scope != "" {
+			if account == nil {
+				apiError(c, 401, "This method requires an authenticated user")
+				return
+			}
+			if !domain.HasOAuthScope(token.Scopes, scope) {
+				apiError(c, 403, "This action is outside the authorized scopes")
+				return
+			}
+		}
+		if token != nil {
+			c.Set(apiTokenKey, token)
+		}
+		if account != nil {
+			c.Set(apiAccountKey, account)
+		}
+		c.Next()
+	}
+}
+
+// apiAccount returns the authenticated account, or nil
+func apiAccount(c *gin.Context) *domain.Account {
+	if account, ok := c.Get(apiAccountKey); ok {
+		return account.(*domain.Account)
+	}
+	return nil
+}
+
+// apiLimit returns the requested page size
+func apiLimit(c *gin.Context) int {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		return apiDefaultLimit
+	}
+	return min(limit, apiMaxLimit)
+}
+
+// paginateById returns one page of a newest-first list using Mastodon's id cursors:
+// max_id returns items after (older than) the given id, since_id the newest items before it,
+// and min_id the items directly before it.
+func paginateById[T any](items []T, idOf func(T) string, maxId, sinceId, minId string, limit int) []T {
+	indexOf := func(target string) int {
+		for i, item := range items {
+			if idOf(item) == target {
+				return i
+			}
+		}
+		return -1
+	}
+
+	if maxId != "" {
+		i := indexOf(maxId)
+		if i < 0 {
+			return []T{}
+		}
+		items = items[i+1:]
+	}
+	if minId != "" {
+		if i := indexOf(minId); i >= 0 {
+			items = items[:i]
+		}
+		if len(items) > limit {
+			items = items[len(items)-limit:]
+		}
+		return items
+	}
+	if sinceId != "" {
+		if i := indexOf(sinceId); i >= 0 {
+			items = items[:i]
+		}
+	}
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// setLinkHeader adds Mastodon's pagination Link header for a newest-first page.
+// The links point at the configured domain, never at the Host headers of the request.
+func setLinkHeader(c *gin.Context, conf *util.AppConfig, firstId, lastId string) {
+	if firstId == "" || lastId == "" {
+		return
+	}
+	base := "https://" + conf.Conf.SslDomain + c.Request.URL.Path
+	query := c.Request.URL.Query()
+	query.Del("max_id")
+	query.Del("since_id")
+	query.Del("min_id")
+
+	next := url.Values{}
+	prev := url.Values{}
+	for key, values := range query {
+		next[key] = values
+		prev[key] = values
+	}
+	next.Set("max_id", lastId)
+	prev.Set("min_id", firstId)
+	c.Header("Link", fmt.Sprintf(`<%s?%s>; rel="next", <%s?%s>; rel="prev"`, base, next.Encode(), base, prev.Encode()))
+}
+
+// apiCursor reads a pagination parameter as a position in a newest-first list. The Link
+// header carries cursors (see domain.Cursor.String); plain ids, which clients also send,
+// are looked up with find. ok is false for a value naming no known item.
+func apiCursor(c *gin.Context, param string, find func(uuid.UUID) (domain.Cursor, bool)) (domain.Cursor, bool) {
+	value := c.Query(param)
+	if cursor, err := domain.ParseCursor(value); err == nil {
+		return cursor, true
+	}
+	if id, err := uuid.Parse(value); err == nil {
+		return find(id)
+	}
+	return domain.Cursor{}, false
+}
+
+// pageByCursor reads the page of a newest-first list selected by Mastodon's id cursors:
+// maxId returns items older than it, sinceId the newest items newer than it and minId the
+// items directly newer than it. read returns up to limit items older than a cursor, and
+// batches are read until the page is full, so items rejected by keep do not shorten it.
+func pageByCursor[T any](read func(before domain.Cursor, limit int) ([]T, error), cursorOf func(T) domain.Cursor,
+	keep func(T) bool, maxId, sinceId, minId domain.Cursor, limit int) ([]T, error) {
+	lower := sinceId
+	if !minId.IsZero() {
+		lower = minId
+	}
+
+	page := []T{}
+	before := maxId
+	for {
+		batch, err := read(before, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range batch {
+			if !lower.IsZero() && !cursorOf(item).After(lower) {
+				return page, nil
+			}
+			if keep != nil && !keep(item) {
+				continue
+			}
+			page = append(page, item)
+			// min_id pages end next to the cursor, so older items make room for newer ones
+			if len(page) > limit {
+				page = page[1:]
+			}
+			if len(page) == limit && minId.IsZero() {
+				return page, nil
+			}
+		}
+		if len(batch) < limit {
+			return page, nil
+		}
+		before = cursorOf(batch[len(batch)-1])
+	}
+}
+
+// readApiPage reads the page of a list request using its max_id, since_id, min_id and limit.
+// Parameters naming unknown items select an empty page.
+func readApiPage[T any](c *gin.Context, read func(before domain.Cursor, limit int) ([]T, error), cursorOf func(T) domain.Cursor,
+	keep func(T) bool, find func(uuid.UUID) (domain.Cursor, bool)) ([]T, error) {
+	maxId, maxOk := apiCursor(c, "max_id", find)
+	sinceId, sinceOk := apiCursor(c, "since_id", find)
+	minId, minOk := apiCursor(c, "min_id", find)
+	if !maxOk || !sinceOk || !minOk {
+		return []T{}, nil
+	}
+	return pageByCursor(read, cursorOf, keep, maxId, sinceId, minId, apiLimit(c))
+}
+
+// respondStatuses renders a page of timeline posts and writes it with pagination headers
+func respondStatuses(c *gin.Context, conf *util.AppConfig, renderer *mastodonRenderer, posts []domain.HomePost) {
+	if len(posts) > 0 {
+		setLinkHeader(c, conf, posts[0].Cursor().String(), posts[len(posts)-1].Cursor().String())
+	}
+	c.JSON(200, renderer.statuses(posts))
+}
+
+// statusCursor looks up the timeline position of a status id sent as a pagination parameter
+func statusCursor(database db.Store) func(uuid.UUID) (domain.Cursor, bool) {
+	return func(statusId uuid.UUID) (domain.Cursor, bool) {
+		if err, note := database.ReadNoteId(statusId); err == nil && note != nil {
+			return note.Cursor(), true
+		}
+		if err, activity := database.ReadActivityById(statusId); err == nil && activity != nil {
+			return domain.Cursor{CreatedAt: activity.CreatedAt, Id: activity.Id}, true
+		}
+		return domain.Cursor{}, false
+	}
+}
+
+// parseApiId parses a path id, writing a 404 when it is malformed
+func parseApiId(c *gin.Context) (uuid.UUID, bool) {
+	parsed, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		apiError(c, 404, "Record not found")
+		return uuid.Nil, false
+	}
+	return parsed, true
+}
+
+// HandleMastodonInstance returns the v1 instance entity (GET /api/v1/instance)
+func HandleMastodonInstance(c *gin.Context, conf *util.AppConfig) {
+	database := db.GetDB()
+	userCount, _ := database.CountAccounts()
+	statusCount, _ := database.CountLocalPosts()
+
+	c.JSON(200, gin.H{
+		"uri":               conf.Conf.SslDomain,
+		"title":             "stegodon",
+		"short_description": "A single-binary microblog you use over SSH",
+		"description":       "A single-binary microblog you use over SSH",
+		"email":             "",
+		"version":           "4.0.0 (compatible; stegodon " + util.GetVersion() + ")",
+		"urls":              gin.H{},
+		"stats": gin.H{
+			"user_count":   userCount,
+			"status_count": statusCount,
+			"domain_count": 0,
+		},
+		"thumbnail":         "https://" + conf.Conf.SslDomain + "/static/stegologo.png",
+		"languages":         []string{"en"},
+		"registrations":     false,
+		"approval_required": false,
+		"invites_enabled":   false,
+		"configuration":     mastodonInstanceConfiguration(conf),
+		"contact_account":   nil,
+		"rules":             []any{},
+	})
+}
+
+// HandleMastodonInstanceV2 returns the v2 instance entity (GET /api/v2/instance)
+func HandleMastodonInstanceV2(c *gin.Context, conf *util.AppConfig) {
+	database := db.GetDB()
+	activeMonth, _ := database.CountActiveUsersMonth()
+
+	c.JSON(200, gin.H{
+		"domain":      conf.Conf.SslDomain,
+		"title":       "stegodon",
+		"version":     "4.0.0 (compatible; stegodon " + util.GetVersion() + ")",
+		"source_url":  "https://github.com/deemkeen/stegodon",
+		"description": "A single-binary microblog you use over SSH",
+		"usage":       gin.H{"users": gin.H{"active_month": activeMonth}},
+		"thumbnail":   gin.H{"url": "https://" + conf.Conf.SslDomain + "/static/stegologo.png"},
+		"languages":   []string{"en"},
+		"configuration": func() gin.H {
+			configuration := mastodonInstanceConfiguration(conf)
+			configuration["urls"] = gin.H{}
+			return configuration
+		}(),
+		"registrations": gin.H{"enabled": false, "approval_required": false, "message": "Sign up over SSH"},
+		"contact":       gin.H{"email": "", "account": nil},
+		"rules":         []any{},
+	})
+}
+
+// mastodonInstanceConfiguration describes the server's limits to clients
+func mastodonInstanceConfiguration(conf *util.AppConfig) gin.H {
+	return gin.H{
+		"statuses": gin.H{
+			"max_characters":              conf.Conf.MaxChars,
+			"max_media_attachments":       0,
+			"characters_reserved_per_url": 0,
+		},
+		"media_attachments": gin.H{
+			"supported_mime_types": []string{},
+		},
+		"polls": gin.H{
+			"max_options": 0,
+		},
+	}
+}
+
+// HandleMastodonVerifyCredentials returns the authenticated account (GET /api/v1/accounts/verify_credentials)
+func HandleMastodonVerifyCredentials(c *gin.Context, conf *util.AppConfig) {
+	account := apiAccount(c)
+	entity := newMastodonRenderer(db.GetDB(), conf, account).localAccount(account)
+	withSource := *entity
+	withSource.Source = &mastodonSource{Note: account.Summary, Privacy: "public", Fields: []any{}}
+	c.JSON(200, withSource)
+}
+
+// HandleMastodonAccount returns a local or remote account (GET /api/v1/accounts/:id)
+func HandleMastodonAccount(c *gin.Context, conf *util.AppConfig) {
+	accountId, ok := parseApiId(c)
+	if !ok {
+		return
+	}
+	entity := newMastodonRenderer(db.GetDB(), conf, apiAccount(c)).accountById(accountId)
+	if entity == nil {
+		apiError(c, 404, "Record not found")
+		return
+	}
+	c.JSON(200, entity)
+}
+
+// HandleMastodonAccountLookup finds an account by its acct handle (GET /api/v1/accounts/lookup)
+func HandleMastodonAccountLookup(c *gin.Context, conf *util.AppConfig) {
+	acct := c.Query("acct")
+	if acct == "" {
+		apiError(c, 422, "Missing acct parameter")
+		return
+	}
+	entity := newMastodonRenderer(db.GetDB(), conf, apiAccount(c)).accountByHandle(acct)
+	if entity == nil {
+		apiError(c, 404, "Record not found")
+		return
+	}
+	c.JSON(200, entity)
+}
+
+// HandleMastodonAccountStatuses lists a local account's posts (GET /api/v1/accounts/:id/statuses).
+// Remote accounts return an empty list, as their outboxes are not stored.
+func HandleMastodonAccountStatuses(c *gin.Context, conf *util.AppConfig) {
+	accountId, ok := parseApiId(c)
+	if !ok {
+		return
+	}
+	database := db.GetDB()
+	renderer := newMastodonRenderer(database, conf, apiAccount(c))
+
+	err, account := database.ReadAccById(accountId)
+	if err != nil || account == nil {
+		if err, remote := database.ReadRemoteAccountById(accountId); err == nil && remote != nil {
+			c.JSON(200, []any{})
+			return
+		}
+		apiError(c, 404, "Record not found")
+		return
+	}
+
+	// Pinned posts are not supported
+	if c.Query("pinned") == "true" {
+		c.JSON(200, []any{})
+		return
+	}
+
+	excludeReplies := c.Query("exclude_replies") == "true"
+	notes, err := readApiPage(c, func(before domain.Cursor, limit int) ([]domain.Note, error) {
+		err, notes := database.ReadPublicNotesByUsername(account.Username, before, limit)
+		if err != nil {
+			return nil, err
+		}
+		return *notes, nil
+	}, domain.Note.Cursor, func(note domain.Note) bool {
+		return !excludeReplies || note.InReplyToURI == ""
+	}, statusCursor(database))
+	if err != nil {
+		log.Printf("Failed to read notes of %s: %v", account.Username, err)
+		apiError(c, 500, "Failed to load statuses")
+		return
+	}
+
+	if len(notes) > 0 {
+		setLinkHeader(c, conf, notes[0].Cursor().String(), notes[len(notes)-1].Cursor().String())
+	}
+	statuses := []*mastodonStatus{}
+	for _, note := range notes {
+		if status := renderer.status(actions.NotePost(database, conf, &note), note.InReplyToURI); status != nil {
+			statuses = append(statuses, status)
+		}
+	}
+	c.JSON(200, statuses)
+}
+
+// respondFollowAccounts writes the accounts on one side of a follow list
+func respondFollowAccounts(c *gin.Context, conf *util.AppConfig, follows *[]domain.Follow, followers bool) {
+	renderer := newMastodonRenderer(db.GetDB(), conf, apiAccount(c))
+	accounts := []*mastodonAccount{}
+	if follows != nil {
+		for _, follow := range *follows {
+			if !follow.Accepted {
+				continue
+			}
+			other := follow.TargetAccountId
+			if followers {
+				other = follow.AccountId
+			}
+			if entity := renderer.accountById(other); entity != nil {
+				accounts = append(accounts, entity)
+			}
+		}
+	}
+	page := paginateById(accounts, func(a *mastodonAccount) string { return a.ID },
+		c.Query("max_id"), c.Query("since_id"), c.Query("min_id"), apiLimit(c))
+	if len(page) > 0 {
+		setLinkHeader(c, conf, page[0].ID, page[len(page)-1].ID)
+	}
+	c.JSON(200, page)
+}
+
+// HandleMastodonFollowers lists a local account's followers (GET /api/v1/accounts/:id/followers)
+func HandleMastodonFollowers(c *gin.Context, conf *util.AppConfig) {
+	accountId, ok := parseApiId(c)
+	if !ok {
+		return
+	}
+	err, follows := db.GetDB().ReadFollowersByAccountId(accountId)
+	if err != nil {
+		apiError(c, 500, "Failed to load followers")
+		return
+	}
+	respondFollowAccounts(c, conf, follows, true)
+}
+
+// HandleMastodonFollowing lists the accounts a local account follows (GET /api/v1/accounts/:id/following)
+func HandleMastodonFollowing(c *gin.Context, conf *util.AppConfig) {
+	accountId, ok := parseApiId(c)
+	if !ok {
+		return
+	}
+	err, follows := db.GetDB().ReadFollowingByAccountId(accountId)
+	if err != nil {
+		apiError(c, 500, "Failed to load following")
+		return
+	}
+	respondFollowAccounts(c, conf, follows, false)
+}
+
+// HandleMastodonFollow follows an account (POST /api/v1/accounts/:id/follow)
+func HandleMastodonFollow(c *gin.Context, conf *util.AppConfig) {
+	handleMastodonFollowChange(c, conf, true)
+}
+
+// HandleMastodonUnfollow unfollows an account (POST /api/v1/accounts/:id/unfollow)
+func HandleMastodonUnfollow(c *gin.Context, conf *util.AppConfig) {
+	handleMastodonFollowChange(c, conf, false)
+}
+
+func handleMastodonFollowChange(c *gin.Context, conf *util.AppConfig, follow bool) {
+	targetId, ok := parseApiId(c)
+	if !ok {
+		return
+	}
+	database := db.GetDB()
+	account := apiAccount(c)
+
+	var err error
+	if follow {
+		err = actions.Follow(database, conf, account, targetId)
+	} else {
+		err = actions.Unfollow(database, conf, account, targetId)
+	}
+	if errors.Is(err, actions.ErrNotFound) {
+		apiError(c, 404, "Record not found")
+		return
+	}
+	if err != nil {
+		log.Printf("API follow change for %s failed: %v", account.Username, err)
+		apiError(c, 422, err.Error())
+		return
+	}
+	c.JSON(200, newMastodonRenderer(database, conf, account).relationship(targetId))
+}
+
+// HandleMastodonRelationships returns the relationships to the given accounts (GET /api/v1/accounts/relationships)
+func HandleMastodonRelationships(c *gin.Context, conf *util.AppConfig) {
+	renderer := newMastodonRenderer(db.GetDB(), conf, apiAccount(c))
+	relationships := []mastodonRelationship{}
+	query := c.Request.URL.Query()
+	for _, value := range append(query["id[]"], query["id"]...) {
+		if targetId, err := uuid.Parse(value); err == nil {
+			relationships = append(relationships, renderer.relationship(targetId))
+		}
+	}
+	c.JSON(200, relationships)
+}
+
+// HandleMastodonCreateStatus posts a new status (POST /api/v1/statuses)
+func HandleMastodonCreateStatus(c *gin.Context, conf *util.AppConfig) {
+	database := db.GetDB()
+	account := apiAccount(c)
+
+	rawValue := apiParam(c, "status")
+	if len(apiParams(c)["media_ids"]) > 0 {
+		apiError(c, 422, "Media attachments are not supported")
+		return
+	}
+	if rawValue == "" {
+		apiError(c, 422, "Validation failed: Text can't be blank")
+		return
+	}
+	if visibleChars := util.CountVisibleChars(rawValue); conf.Conf.MaxChars > 0 && visibleChars > conf.Conf.MaxChars {
+		apiError(c, 422, fmt.Sprintf("Validation failed: Text is too long (%d visible characters, max %d)", visibleChars, conf.Conf.MaxChars))
+		return
+	}
+	if err := util.ValidateNoteLength(rawValue); err != nil {
+		apiError(c, 422, "Validation failed: "+err.Error())
+		return
+	}
+
+	var inReplyToURI string
+	if inReplyToId := apiParam(c, "in_reply_to_id"); inReplyToId != "" {
+		parentId, err := uuid.Parse(inReplyToId)
+		if err != nil {
+			apiError(c, 404, "Record not found")
+			return
+		}
+		target, err := actions.ReadTarget(database, parentId)
+		if err != nil {
+			apiError(c, 404, "Record not found")
+			return
+		}
+		inReplyToURI = target.ReplyURI()
+	}
+
+	noteId, err := actions.CreateNote(database, conf, account, util.NormalizeInput(rawValue), inReplyToURI)
+	if err != nil {
+		log.Printf("API status by %s could not be saved: %v", account.Username, err)
+		apiError(c, 500, "Failed to save status")
+		return
+	}
+	log.Printf("Note %s posted by %s via the client API", noteId, account.Username)
+
+	status := newMastodonRenderer(database, conf, account).statusById(noteId)
+	if status == nil {
+		apiError(c, 500, "Failed to load status")
+		return
+	}
+	c.JSON(200, status)
+}
+
+// HandleMastodonStatus returns a single status (GET /api/v1/statuses/:id)
+func HandleMastodonStatus(c *gin.Context, conf *util.AppConfig) {
+	statusId, ok := parseApiId(c)
+	if !ok {
+		return
+	}
+	status := newMastodonRenderer(db.GetDB(), conf, apiAccount(c)).statusById(statusId)
+	if status == nil {
+		apiError(c, 404, "Record not found")
+		return
+	}
+	c.JSON(200, status)
+}
+
+// HandleMastodonStatusContext returns a status's ancestors and replies (GET /api/v1/statuses/:id/context)
+func HandleMastodonStatusContext(c *gin.Context, conf *util.AppConfig) {
+	statusId, ok := parseApiId(c)
+	if !ok {
+		return
+	}
+	database := db.GetDB()
+	renderer := newMastodonRenderer(database, conf, apiAccount(c))
+
+	status := renderer.statusById(statusId)
+	if status == nil {
+		apiError(c, 404, "Record not found")
+		return
+	}
+
+	// Walk up the reply chain
+	ancestors := []*mastodonStatus{}
+	for parent := status; parent.InReplyToID != nil && len(ancestors) < apiMaxLimit; {
+		parentId, _ := uuid.Parse(*parent.InReplyToID)
+		if parent = renderer.statusById(parentId); parent == nil {
+			break
+		}
+		ancestors = append([]*mastodonStatus{parent}, ancestors...)
+	}
+
+	// Walk down the replies, breadth first
+	descendants := []*mastodonStatus{}
+	queue := []*mastodonStatus{status}
+	for len(queue) > 0 && len(descendants) < apiThreadWindow {
+		current := queue[0]
+		queue = queue[1:]
+		for _, reply := range renderer.replies(current) {
+			descendants = append(descendants, reply)
+			queue = append(queue, reply)
+		}
+	}
+
+	c.JSON(200, gin.H{"ancestors": ancestors, "descendants": descendants})
+}
+
+// replies returns the direct local and remote replies to a status
+func (r *mastodonRenderer) replies(status *mastodonStatus) []*mastodonStatus {
+	statusId, _ := uuid.Parse(status.ID)
+	result := []*mastodonStatus{}
+	for _, reply := range actions.ReadReplies(r.database, r.conf, statusId, status.URI) {
+		if s := r.status(reply.Post, reply.InReplyToURI); s != nil {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// HandleMastodonDeleteStatus deletes one of the user's statuses (DELETE /api/v1/statuses/:id)
+func HandleMastodonDeleteStatus(c *gin.Context, conf *util.AppConfig) {
+	statusId, ok := parseApiId(c)
+	if !ok {
+		return
+	}
+	database := db.GetDB()
+	account := apiAccount(c)
+	renderer := newMastodonRenderer(database, conf, account)
+
+	err, note := database.ReadNoteIdWithReplyInfo(statusId)
+	if err != nil || note == nil || note.CreatedBy != account.Username {
+		apiError(c, 404, "Record not found")
+		return
+	}
+	// Deleting returns the deleted status so clients can offer "delete and redraft"
+	status := renderer.status(actions.NotePost(database, conf, note), note.InReplyToURI)
+	if err := actions.DeleteNote(database, conf, account, statusId); err != nil {
+		log.Printf("API delete of note %s failed: %v", statusId, err)
+		apiError(c, 500, "Failed to delete status")
+		return
+	}
+	status.Text = note.Message
+	c.JSON(200, status)
+}
+
+// HandleMastodonFavourite likes a status (POST /api/v1/statuses/:id/favourite)
+func HandleMastodonFavourite(c *gin.Context, conf *util.AppConfig) {
+	handleMastodonStatusAction(c, conf, func(database db.Store, account *domain.Account, target *actions.Target) error {
+		return actions.SetLike(database, conf, account, target, true)
+	})
+}
+
+// HandleMastodonUnfavourite removes a like (POST /api/v1/statuses/:id/unfavourite)
+func HandleMastodonUnfavourite(c *gin.Context, conf *util.AppConfig) {
+	handleMastodonStatusAction(c, conf, func(database db.Store, account *domain.Account, target *actions.Target) error {
+		return actions.SetLike(database, conf, account, target, false)
+	})
+}
+
+// HandleMastodonReblog boosts a status (POST /api/v1/statuses/:id/reblog)
+func HandleMastodonReblog(c *gin.Context, conf *util.AppConfig) {
+	handleMastodonStatusAction(c, conf, func(database db.Store, account *domain.Account, target *actions.Target) error {
+		return actions.SetBoost(database, conf, account, target, true)
+	})
+}
+
+// HandleMastodonUnreblog removes a boost (POST /api/v1/statuses/:id/unreblog)
+func HandleMastodonUnreblog(c *gin.Context, conf *util.AppConfig) {
+	handleMastodonStatusAction(c, conf, func(database db.Store, account *domain.Account, target *actions.Target) error {
+		return actions.SetBoost(database, conf, account, target, false)
+	})
+}
+
+// handleMastodonStatusAction resolves the status, applies the action and returns the updated status
+func handleMastodonStatusAction(c *gin.Context, conf *util.AppConfig, action func(db.Store, *domain.Account, *actions.Target) error) {
+	statusId, ok := parseApiId(c)
+	if !ok {
+		return
+	}
+	database := db.GetDB()
+	account := apiAccount(c)
+
+	target, err := actions.ReadTarget(database, statusId)
+	if err != nil {
+		apiError(c, 404, "Record not found")
+		return
+	}
+	if err := action(database, account, target); err != nil {
+		log.Printf("API action on %s by %s failed: %v", statusId, account.Username, err)
+		apiError(c, 500, "Failed to update status")
+		return
+	}
+
+	status := newMastodonRenderer(database, conf, account).statusById(statusId)
+	if status == nil {
+		apiError(c, 404, "Record not found")
+		return
+	}
+	c.JSON(200, status)
+}
+
+// HandleMastodonHomeTimeline returns the home timeline (GET /api/v1/timelines/home)
+func HandleMastodonHomeTimeline(c *gin.Context, conf *util.AppConfig) {
+	database := db.GetDB()
+	account := apiAccount(c)
+	posts, err := readApiPage(c, func(before domain.Cursor, limit int) ([]domain.HomePost, error) {
+		err, posts := database.ReadHomeTimelinePosts(account.Id, before, limit)
+		if err != nil {
+			return nil, err
+		}
+		return *posts, nil
+	}, domain.HomePost.Cursor, nil, statusCursor(database))
+	if err != nil {
+		log.Printf("Failed to read home timeline for %s: %v", account.Username, err)
+		apiError(c, 500, "Failed to load timeline")
+		return
+	}
+	respondStatuses(c, conf, newMastodonRenderer(database, conf, account), posts)
+}
+
+// HandleMastodonPublicTimeline returns local and federated posts (GET /api/v1/timelines/public)
+func HandleMastodonPublicTimeline(c *gin.Context, conf *util.AppConfig) {
+	database := db.GetDB()
+	localOnly := c.Query("local") == "true"
+	remoteOnly := c.Query("remote") == "true"
+	globalPosts, err := readApiPage(c, func(before domain.Cursor, limit int) ([]domain.GlobalTimelinePost, error) {
+		err, posts := database.ReadGlobalTimelinePosts(before, limit)
+		if err != nil {
+			return nil, err
+		}
+		return *posts, nil
+	}, domain.GlobalTimelinePost.Cursor, func(post domain.GlobalTimelinePost) bool {
+		return !(localOnly && post.IsRemote) && !(remoteOnly && !post.IsRemote)
+	}, statusCursor(database))
+	if err != nil {
+		log.Printf("Failed to read public timeline: %v", err)
+		apiError(c, 500, "Failed to load timeline")
+		return
+	}
+
+	var posts []domain.HomePost
+	for _, post := range globalPosts {
+		postId, _ := uuid.Parse(post.NoteId)
+		homePost := domain.HomePost{
+			ID:         postId,
+			Author:     post.Username,
+			Content:    post.Message,
+			Time:       post.CreatedAt,
+			ObjectURI:  post.ObjectURI,
+			ObjectURL:  post.ObjectURL,
+			IsLocal:    !post.IsRemote,
+			ReplyCount: post.ReplyCount,
+			LikeCount:  post.LikeCount,
+			BoostCount: post.BoostCount,
+			BoostedBy:  post.BoostedBy,
+			Emojis:     post.Emojis,
+		}
+		if homePost.IsLocal {
+			homePost.NoteID = postId
+		}
+		posts = append(posts, homePost)
+	}
+	respondStatuses(c, conf, newMastodonRenderer(database, conf, apiAccount(c)), posts)
+}
+
+// HandleMastodonTagTimeline returns posts with a hashtag (GET /api/v1/timelines/tag/:hashtag)
+func HandleMastodonTagTimeline(c *gin.Context, conf *util.AppConfig) {
+	database := db.GetDB()
+	tag := strings.ToLower(strings.TrimPrefix(c.Param("hashtag"), "#"))
+	posts, err := readApiPage(c, func(before domain.Cursor, limit int) ([]domain.HomePost, error) {
+		err, posts := database.ReadHashtagTimelinePosts(tag, before, limit)
+		if err != nil {
+			return nil, err
+		}
+		return *posts, nil
+	}, domain.HomePost.Cursor, nil, statusCursor(database))
+	if err != nil {
+		log.Printf("Failed to read tag timeline for #%s: %v", tag, err)
+		apiError(c, 500, "Failed to load timeline")
+		return
+	}
+	respondStatuses(c, conf, newMastodonRenderer(database, conf, apiAccount(c)), posts)
+}
+
+// HandleMastodonNotifications lists the user's notifications (GET /api/v1/notifications)
+func HandleMastodonNotifications(c *gin.Context, conf *util.AppConfig) {
+	database := db.GetDB()
+	account := apiAccount(c)
+	query := c.Request.URL.Query()
+	types := map[string]bool{}
+	for _, t := range append(query["types[]"], query["types"]...) {
+		types[t] = true
+	}
+	excluded := map[string]bool{}
+	for _, t := range append(query["exclude_types[]"], query["exclude_types"]...) {
+		excluded[t] = true
+	}
+
+	notifications, err := readApiPage(c, func(before domain.Cursor, limit int) ([]domain.Notification, error) {
+		err, notifications := database.ReadNotificationsByAccountId(account.Id, before, limit)
+		if err != nil {
+			return nil, err
+		}
+		return *notifications, nil
+	}, domain.Notification.Cursor, func(n domain.Notification) bool {
+		notificationType := mastodonNotificationTypes[n.NotificationType]
+		return (len(types) == 0 || types[notificationType]) && !excluded[notificationType]
+	}, func(notificationId uuid.UUID) (domain.Cursor, bool) {
+		err, n := database.ReadNotificationById(notificationId, account.Id)
+		if err != nil || n == nil {
+			return domain.Cursor{}, false
+		}
+		return n.Cursor(), true
+	})
+	if err != nil {
+		log.Printf("Failed to read notifications for %s: %v", account.Username, err)
+		apiError(c, 500, "Failed to load notifications")
+		return
+	}
+
+	if len(notifications) > 0 {
+		setLinkHeader(c, conf, notifications[0].Cursor().String(), notifications[len(notifications)-1].Cursor().String())
+	}
+	renderer := newMastodonRenderer(database, conf, account)
+	entities := []*mastodonNotification{}
+	for _, n := range notifications {
+		if entity := renderer.notification(n); entity != nil {
+			entities = append(entities, entity)
+		}
+	}
+	c.JSON(200, entities)
+}
+
+// HandleMastodonClearNotifications deletes all of the user's notifications (POST /api/v1/notifications/clear)
+func HandleMastodonClearNotifications(c *gin.Context, conf *util.AppConfig) {
+	account := apiAccount(c)
+	if err := db.GetDB().DeleteAllNotifications(account.Id); err != nil {
+		log.Printf("Failed to clear notifications for %s: %v", account.Username, err)
+		apiError(c, 500, "Failed to clear notifications")
+		return
+	}
+	c.JSON(200, gin.H{})
+}
+
+// HandleMastodonDismissNotification deletes one notification (POST /api/v1/notifications/:id/dismiss)
+func HandleMastodonDismissNotification(c *gin.Context, conf *util.AppConfig) {
+	notificationId, ok := parseApiId(c)
+	if !ok {
+		return
+	}
+	database := db.GetDB()
+	account := apiAccount(c)
+
+	// Only the owner may dismiss a notification
+	err := database.DeleteNotification(notificationId, account.Id)
+	if errors.Is(err, sql.ErrNoRows) {
+		apiError(c, 404, "Record not found")
+		return
+	}
+	if err != nil {
+		apiError(c, 500, "Failed to dismiss notification")
+		return
+	}
+	c.JSON(200, gin.H{})
+}
+
+// HandleMastodonSearch searches accounts, statuses and hashtags (GET /api/v2/search)
+func HandleMastodonSearch(c *gin.Context, conf *util.AppConfig) {
+	database := db.GetDB()
+	renderer := newMastodonRenderer(database, conf, apiAccount(c))
+	q := strings.TrimSpace(c.Query("q"))
+	searchType := c.Query("type")
+	limit := apiLimit(c)
+
+	accounts := []*mastodonAccount{}
+	statuses := []*mastodonStatus{}
+	hashtags := []mastodonTag{}
+	if q == "" {
+		c.JSON(200, gin.H{"accounts": accounts, "statuses": statuses, "hashtags": hashtags})
+		return
+	}
+
+	wants := func(t string) bool { return searchType == "" || searchType == t }
+
+	if wants("statuses") && (strings.HasPrefix(q, "https://") || strings.HasPrefix(q, "http://")) {
+		if err, note := database.ReadNoteByURI(q); err == nil && note != nil {
+			if status := renderer.statusById(note.Id); status != nil {
+				statuses = append(statuses, status)
+			}
+		} else if err, activity := database.ReadActivityByObjectURI(q); err == nil && activity != nil {
+			if status := renderer.statusById(activity.Id); status != nil {
+				statuses = append(statuses, status)
+			}
+		} else if c.Query("resolve") == "true" && apiAccount(c) != nil {
+			if resolved, err := ResolveURL(q, conf); err != nil {
+				log.Printf("Search: Failed to resolve %s: %v", q, err)
+			} else if resolved.Activity != nil {
+				if status := renderer.statusById(resolved.Activity.Id); status != nil {
+					statuses = append(statuses, status)
+				}
+			} else if resolved.RemoteAccount != nil && wants("accounts") {
+				accounts = append(accounts, renderer.remoteAccount(resolved.RemoteAccount))
+			}
+		}
+	}
+
+	if wants("accounts") {
+		if strings.Count(strings.TrimPrefix(q, "@"), "@") == 1 {
+			entity := renderer.accountByHandle(q)
+			if entity == nil && c.Query("resolve") == "true" && apiAccount(c) != nil {
+				entity = resolveMastodonAccount(renderer, q)
+			}
+			if entity != nil {
+				accounts = append(accounts, entity)
+			}
+		} else if err, locals := database.ReadAllAccounts(); err == nil && locals != nil {
+			needle := strings.ToLower(strings.TrimPrefix(q, "@"))
+			for _, acc := range *locals {
+				if len(accounts) >= limit {
+					break
+				}
+				if strings.Contains(strings.ToLower(acc.Username), needle) || strings.Contains(strings.ToLower(acc.DisplayName), needle) {
+					accounts = append(accounts, renderer.localAccount(&acc))
+				}
+			}
+		}
+	}
+
+	if wants("hashtags") {
+		tag := strings.ToLower(strings.TrimPrefix(q, "#"))
+		if count, err := database.CountNotesByHashtag(tag); err == nil && count > 0 {
+			hashtags = append(hashtags, mastodonTag{Name: tag, URL: renderer.baseURL() + "/tags/" + tag})
+		}
+	}
+
+	c.JSON(200, gin.H{"accounts": accounts, "statuses": statuses, "hashtags": hashtags})
+}
+
+// resolveMastodonAccount looks up a remote account that is not cached yet via WebFinger
+func resolveMastodonAccount(renderer *mastodonRenderer, handle string) *mastodonAccount {
+	if !renderer.conf.Conf.WithAp {
+		return nil
+	}
+	username, userDomain := splitHandle(handle)
+	if renderer.isLocalDomain(userDomain) {
+		return nil
+	}
+	actorURI, err := ResolveWebFinger(username, userDomain)
+	if err != nil {
+		log.Printf("Search: WebFinger lookup of %s@%s failed: %v", username, userDomain, err)
+		return nil
+	}
+	remote, err := activitypub.GetOrFetchActor(actorURI)
+	if err != nil {
+		log.Printf("Search: Failed to fetch actor %s: %v", actorURI, err)
+		return nil
+	}
+	return renderer.remoteAccount(remote)
+}
+
+// mastodonCORSMiddleware allows browser based clients (e.g. Elk) to call the API
+func mastodonCORSMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Access-Control-Allow-Origin", "*")
+		c.Header("Access-Control-Allow-Headers", "Authorization, Content-Type, Idempotency-Key")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Expose-Headers", "Link")
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(204)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RegisterMastodonRoutes adds the OAuth endpoints and the Mastodon client API subset
+func RegisterMastodonRoutes(g *gin.Engine, conf *util.AppConfig) {
+	handle := func(h func(*gin.Context, *util.AppConfig)) gin.HandlerFunc {
+		return func(c *gin.Context) { h(c, conf) }
+	}
+	cors := mastodonCORSMiddleware()
+	public := MastodonAuthMiddleware("")
+
+	// OAuth authorization code flow, confirmed in the TUI
+	g.GET("/oauth/authorize", handle(HandleOAuthAuthorize))
+	g.GET("/oauth/authorize/:id", handle(HandleOAuthAuthorizeStatus))
+	oauth := g.Group("/oauth", cors)
+	oauth.OPTIONS("/*path")
+	oauth.POST("/token", handle(HandleOAuthToken))
+	oauth.POST("/revoke", handle(HandleOAuthRevoke))
+
+	api := g.Group("/api", cors)
+	api.OPTIONS("/*path")
+
+	api.POST("/v1/apps", handle(HandleOAuthCreateApp))
+	api.GET("/v1/instance", handle(HandleMastodonInstance))
+	api.GET("/v2/instance", handle(HandleMastodonInstanceV2))
+
+	// Accounts
+	api.GET("/v1/accounts/verify_credentials", MastodonAuthMiddleware("read:accounts"), handle(HandleMastodonVerifyCredentials))
+	api.GET("/v1/accounts/relationships", MastodonAuthMiddleware("read:follows"), handle(HandleMastodonRelationships))
+	api.GET("/v1/accounts/lookup", public, handle(HandleMastodonAccountLookup))
+	api.GET("/v1/accounts/:id", public, handle(HandleMastodonAccount))
+	api.GET("/v1/accounts/:id/statuses", public, handle(HandleMastodonAccountStatuses))
+	api.GET("/v1/accounts/:id/followers", public, handle(HandleMastodonFollowers))
+	api.GET("/v1/accounts/:id/following", public, handle(HandleMastodonFollowing))
+	api.POST("/v1/accounts/:id/follow", MastodonAuthMiddleware("write:follows"), handle(HandleMastodonFollow))
+	api.POST("/v1/accounts/:id/unfollow", MastodonAuthMiddleware("write:follows"), handle(HandleMastodonUnfollow))
+
+	// Statuses
+	api.POST("/v1/statuses", MastodonAuthMiddleware("write:statuses"), handle(HandleMastodonCreateStatus))
+	api.GET("/v1/statuses/:id", public, handle(HandleMastodonStatus))
+	api.GET("/v1/statuses/:id/context", public, handle(HandleMastodonStatusContext))
+	api.DELETE("/v1/statuses/:id", MastodonAuthMiddleware("write:statuses"), handle(HandleMastodonDeleteStatus))
+	api.POST("/v1/statuses/:id/favourite", MastodonAuthMiddleware("write:favourites"), handle(HandleMastodonFavourite))
+	api.POST("/v1/statuses/:id/unfavourite", MastodonAuthMiddleware("write:favourites"), handle(HandleMastodonUnfavourite))
+	api.POST("/v1/statuses/:id/reblog", MastodonAuthMiddleware("write:statuses"), handle(HandleMastodonReblog))
+	api.POST("/v1/statuses/:id/unreblog", MastodonAuthMiddleware("write:statuses"), handle(HandleMastodonUnreblog))
+
+	// Timelines
+	api.GET("/v1/timelines/home", MastodonAuthMiddleware("read:statuses"), handle(HandleMastodonHomeTimeline))
+	api.GET("/v1/timelines/public", public, handle(HandleMastodonPublicTimeline))
+	api.GET("/v1/timelines/tag/:hashtag", public, handle(HandleMastodonTagTimeline))
+
+	// Notifications
+	api.GET("/v1/notifications", MastodonAuthMiddleware("read:notifications"), handle(HandleMastodonNotifications))
+	api.POST("/v1/notifications/clear", MastodonAuthMiddleware("write:notifications"), handle(HandleMastodonClearNotifications))
+	api.POST("/v1/notifications/:id/dismiss", MastodonAuthMiddleware("write:notifications"), handle(HandleMastodonDismissNotification))
+
+	// Search
+	api.GET("/v2/search", MastodonAuthMiddleware("read:search"), handle(HandleMastodonSearch))
+
+	log.Println("Mastodon client API routes enabled")
+}
@@ -0,0 +1,326 @@
+package web
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRegisterMastodonRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	g := gin.New()
+	conf := &util.AppConfig{}
+	conf.Conf.SslDomain = "example.com"
+	RegisterMastodonRoutes(g, conf)
+
+	// CORS preflight for browser clients
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest("OPTIONS", "/api/v1/statuses", nil))
+	if w.Code != 204 {
+		t.Errorf("Expected 204 for preflight, got %d", w.Code)
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Error("Expected CORS header on preflight")
+	}
+
+	// Endpoints with a scope require a token
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/timelines/home", nil))
+	if w.Code != 401 {
+		t.Errorf("Expected 401 without token, got %d", w.Code)
+	}
+
+	// Only bearer tokens are accepted
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/v1/timelines/public", nil)
+	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	g.ServeHTTP(w, req)
+	if w.Code != 401 {
+		t.Errorf("Expected 401 for non-bearer authorization, got %d", w.Code)
+	}
+}
+
+func TestPaginateById(t *testing.T) {
+	items := []string{"9", "8", "7", "6", "5", "4", "3"}
+	id := func(s string) string { return s }
+
+	tests := []struct {
+		name                  string
+		maxId, sinceId, minId string
+		limit                 int
+		want                  string
+	}{
+		{"first page", "", "", "", 3, "9,8,7"},
+		{"max_id", "7", "", "", 3, "6,5,4"},
+		{"max_id at end", "3", "", "", 3, ""},
+		{"unknown max_id", "x", "", "", 3, ""},
+		{"since_id returns newest", "", "4", "", 2, "9,8"},
+		{"min_id returns closest", "", "", "4", 2, "6,5"},
+		{"max_id and since_id", "8", "5", "", 10, "7,6"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(paginateById(items, id, tt.maxId, tt.sinceId, tt.minId, tt.limit), ",")
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageByCursor(t *testing.T) {
+	// Notes 9 (newest) down to 1, one minute apart
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	var notes []domain.Note
+	for i := 9; i >= 1; i-- {
+		notes = append(notes, domain.Note{Id: uuid.New(), Message: strconv.Itoa(i), CreatedAt: base.Add(time.Duration(i) * time.Minute)})
+	}
+	cursor := func(message string) domain.Cursor {
+		for _, note := range notes {
+			if note.Message == message {
+				return note.Cursor()
+			}
+		}
+		return domain.Cursor{}
+	}
+	reads := 0
+	read := func(before domain.Cursor, limit int) ([]domain.Note, error) {
+		reads++
+		var page []domain.Note
+		for _, note := range notes {
+			if (before.IsZero() || before.After(note.Cursor())) && len(page) < limit {
+				page = append(page, note)
+			}
+		}
+		return page, nil
+	}
+	odd := func(note domain.Note) bool { n, _ := strconv.Atoi(note.Message); return n%2 == 1 }
+
+	tests := []struct {
+		name                  string
+		maxId, sinceId, minId string
+		keep                  func(domain.Note) bool
+		limit                 int
+		want                  string
+	}{
+		{"first page", "", "", "", nil, 3, "9,8,7"},
+		{"max_id", "7", "", "", nil, 3, "6,5,4"},
+		{"max_id at end", "1", "", "", nil, 3, ""},
+		{"since_id returns newest", "", "4", "", nil, 2, "9,8"},
+		{"min_id returns closest", "", "", "4", nil, 2, "6,5"},
+		{"max_id and since_id", "8", "5", "", nil, 10, "7,6"},
+		{"filtered pages are filled", "", "", "", odd, 3, "9,7,5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := pageByCursor(read, domain.Note.Cursor, tt.keep, cursor(tt.maxId), cursor(tt.sinceId), cursor(tt.minId), tt.limit)
+			if err != nil {
+				t.Fatalf("pageByCursor failed: %v", err)
+			}
+			var got []string
+			for _, note := range page {
+				got = append(got, note.Message)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("got %q, want %q", strings.Join(got, ","), tt.want)
+			}
+		})
+	}
+
+	// Only the batches up to the requested page are read
+	reads = 0
+	pageByCursor(read, domain.Note.Cursor, nil, domain.Cursor{}, domain.Cursor{}, domain.Cursor{}, 2)
+	if reads != 1 {
+		t.Errorf("Expected a single read for the first page, got %d", reads)
+	}
+}
+
+func TestMastodonContentHTML(t *testing.T) {
+	got := mastodonContentHTML("hello <script>x</script> #go\n\nsecond\nline", "example.com")
+	if strings.Contains(got, "<script>") {
+		t.Errorf("Expected markup to be escaped, got %q", got)
+	}
+	if !strings.Contains(got, `/tags/go`) {
+		t.Errorf("Expected hashtag link, got %q", got)
+	}
+	if !strings.HasPrefix(got, "<p>") || !strings.Contains(got, "</p><p>second<br>line</p>") {
+		t.Errorf("Expected paragraphs and line breaks, got %q", got)
+	}
+	if mastodonContentHTML("  ", "example.com") != "" {
+		t.Error("Expected empty content for blank message")
+	}
+}
+
+func TestSplitHandle(t *testing.T) {
+	tests := []struct{ handle, username, domain string }{
+		{"alice", "alice", ""},
+		{"@alice", "alice", ""},
+		{"@bob@remote.example", "bob", "remote.example"},
+		{"bob@remote.example", "bob", "remote.example"},
+	}
+	for _, tt := range tests {
+		username, userDomain := splitHandle(tt.handle)
+		if username != tt.username || userDomain != tt.domain {
+			t.Errorf("splitHandle(%q) = %q, %q", tt.handle, username, userDomain)
+		}
+	}
+}
+
+func TestOAuthRedirectURL(t *testing.T) {
+	got := oauthRedirectURL("https://app.example/callback?x=1", "abc", "st ate")
+	if got != "https://app.example/callback?code=abc&state=st+ate&x=1" {
+		t.Errorf("Unexpected redirect URL: %s", got)
+	}
+	got = oauthRedirectURL("tusky://oauth", "abc", "")
+	if got != "tusky://oauth?code=abc" {
+		t.Errorf("Unexpected redirect URL: %s", got)
+	}
+}
+
+func TestValidateOAuthScopes(t *testing.T) {
+	scopes, err := validateOAuthScopes("", nil)
+	if err != nil || len(scopes) != 1 || scopes[0] != "read" {
+		t.Errorf("Expected default read scope, got %v (err=%v)", scopes, err)
+	}
+	scopes, err = validateOAuthScopes("", []string{"read", "write"})
+	if err != nil || len(scopes) != 2 {
+		t.Errorf("Expected app scopes, got %v (err=%v)", scopes, err)
+	}
+	if _, err := validateOAuthScopes("read admin:read", nil); err == nil {
+		t.Error("Expected unknown scope to be rejected")
+	}
+	if _, err := validateOAuthScopes("write:statuses", []string{"read"}); err == nil {
+		t.Error("Expected unregistered scope to be rejected")
+	}
+	if _, err := validateOAuthScopes("read:statuses write:favourites", []string{"read", "write"}); err != nil {
+		t.Errorf("Expected sub-scopes of registered scopes to be allowed: %v", err)
+	}
+}
+
+func TestGenerateOAuthUserCode(t *testing.T) {
+	pattern := regexp.MustCompile(`^[A-HJ-NP-Z2-9]{4}-[A-HJ-NP-Z2-9]{4}$`)
+	for i := 0; i < 20; i++ {
+		code := generateOAuthUserCode()
+		if !pattern.MatchString(code) {
+			t.Fatalf("Unexpected user code format: %s", code)
+		}
+		if domain.NormalizeOAuthUserCode(strings.ToLower(strings.ReplaceAll(code, "-", ""))) != code {
+			t.Fatalf("Expected typed code to normalize back to %s", code)
+		}
+	}
+	if hashOAuthToken("a") == hashOAuthToken("b") || len(hashOAuthToken("a")) != 64 {
+		t.Error("Expected distinct sha256 hex hashes")
+	}
+}
+
+func TestApiParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"json", "application/json", `{"client_name":"Elk","redirect_uris":["https://elk.zone/cb"],"scopes":"read write"}`},
+		{"form", "application/x-www-form-urlencoded", "client_name=Elk&redirect_uris=https%3A%2F%2Felk.zone%2Fcb&scopes=read+write"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("POST", "/api/v1/apps", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", tt.contentType)
+
+			if got := apiParam(c, "client_name"); got != "Elk" {
+				t.Errorf("client_name = %q", got)
+			}
+			if got := apiParams(c)["redirect_uris"]; len(got) != 1 || got[0] != "https://elk.zone/cb" {
+				t.Errorf("redirect_uris = %v", got)
+			}
+			if got := apiParam(c, "scopes"); got != "read write" {
+				t.Errorf("scopes = %q", got)
+			}
+		})
+	}
+}
+
+func TestSetLinkHeaderUsesConfiguredDomain(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	conf := &util.AppConfig{}
+	conf.Conf.SslDomain = "stegodon.example"
+
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest("GET", "/api/v1/timelines/home?limit=2&max_id=9", nil)
+	c.Request.Host = "evil.example"
+	c.Request.Header.Set("X-Forwarded-Host", "evil.example")
+
+	setLinkHeader(c, conf, "5", "4")
+	want := `<https://stegodon.example/api/v1/timelines/home?limit=2&max_id=4>; rel="next", <https://stegodon.example/api/v1/timelines/home?limit=2&min_id=5>; rel="prev"`
+	if got := recorder.Header().Get("Link"); got != want {
+		t.Errorf("Link = %s, want %s", got, want)
+	}
+}
+
+func TestReblogStatusIdIsStable(t *testing.T) {
+	post := domain.HomePost{BoostedBy: "@alice"}
+	if reblogStatusId(post.ID, "@alice") != reblogStatusId(post.ID, "@alice") {
+		t.Error("Expected stable reblog id")
+	}
+	if reblogStatusId(post.ID, "@alice") == reblogStatusId(post.ID, "@bob") {
+		t.Error("Expected different ids for different boosters")
+	}
+}
+
+func TestStatusesResolveInReplyTo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "database.db")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatalf("Failed to create database file: %v", err)
+	}
+	database, err := db.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+	if err := database.CreateDB(); err != nil {
+		t.Fatalf("CreateDB failed: %v", err)
+	}
+	if err := database.RunMigrations(); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+	if err := database.CreateAccountWithPublicKey("alice", "ssh-ed25519 AAAAalice"); err != nil {
+		t.Fatalf("Failed to create account: %v", err)
+	}
+	_, alice := database.ReadAccByUsername("alice")
+	parentId, _ := database.CreateNote(alice.Id, "first")
+	replyId, err := database.CreateNoteWithReply(alice.Id, "second", "local:"+parentId.String())
+	if err != nil {
+		t.Fatalf("CreateNoteWithReply failed: %v", err)
+	}
+
+	conf := &util.AppConfig{}
+	conf.Conf.SslDomain = "stegodon.example"
+	statuses := newMastodonRenderer(database, conf, nil).statuses([]domain.HomePost{
+		{ID: replyId, NoteID: replyId, Author: "alice", Content: "second", IsLocal: true},
+		{ID: parentId, NoteID: parentId, Author: "alice", Content: "first", IsLocal: true},
+	})
+	if len(statuses) != 2 {
+		t.Fatalf("Expected 2 statuses, got %d", len(statuses))
+	}
+	if statuses[0].InReplyToID == nil || *statuses[0].InReplyToID != parentId.String() {
+		t.Errorf("Expected the reply to point at %s, got %v", parentId, statuses[0].InReplyToID)
+	}
+	if statuses[1].InReplyToID != nil {
+		t.Errorf("Expected no in_reply_to_id for a top-level post, got %s", *statuses[1].InReplyToID)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/deemkeen/stegodon/metrics"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/time/rate"
 )
@@ -61,6 +62,21 @@ func (rl *RateLimiter) cleanupOldLimiters() {
 	}
 }
 
+// MetricsMiddleware records every request by its route pattern (e.g. /users/:actor),
+// so the metrics stay bounded no matter which paths are requested
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
+
 // RateLimitMiddleware creates a Gin middleware for rate limiting
 func RateLimitMiddleware(rl *RateLimiter) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/deemkeen/stegodon/metrics"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/time/rate"
 )
@@ -594,3 +595,27 @@ func TestLemmyFederationFix(t *testing.T) {
 		t.Errorf("Expected redirect to /u/demigodrick, got %s", location)
 	}
 }
+
+func TestMetricsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(MetricsMiddleware())
+	router.GET("/metrics-test/:id", func(c *gin.Context) {
+		c.String(http.StatusTeapot, "ok")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/metrics-test/123", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/metrics-test-missing/123", nil))
+
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	if !strings.Contains(body, `stegodon_http_requests_total{method="GET",route="/metrics-test/:id",status="418"} 1`) {
+		t.Error("Expected request to be counted by its route pattern")
+	}
+	if !strings.Contains(body, `route="unmatched",status="404"`) {
+		t.Error("Expected unknown paths to be counted as unmatched")
+	}
+}
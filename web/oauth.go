@@ -0,0 +1,424 @@
+package web
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// oauthAuthorizationTTL is how long a user has to confirm the code in the TUI
+	oauthAuthorizationTTL = 10 * time.Minute
+	// oauthUserCodeAlphabet avoids characters that are easy to confuse (0/O, 1/I)
+	oauthUserCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	// oauthDefaultScope is granted when a client does not ask for specific scopes
+	oauthDefaultScope = "read"
+)
+
+// oauthSupportedScopes are the scopes apps may request
+var oauthSupportedScopes = []string{"read", "write", "follow", "push"}
+
+// apiParams returns the request parameters. Mastodon clients send them as query string,
+// form data or JSON, so all three are merged.
+func apiParams(c *gin.Context) url.Values {
+	if cached, ok := c.Get("apiParams"); ok {
+		return cached.(url.Values)
+	}
+
+	params := url.Values{}
+	for key, values := range c.Request.URL.Query() {
+		params[key] = append(params[key], values...)
+	}
+
+	if strings.HasPrefix(c.ContentType(), "application/json") {
+		var body map[string]any
+		if err := json.NewDecoder(c.Request.Body).Decode(&body); err == nil {
+			for key, value := range body {
+				switch v := value.(type) {
+				case []any:
+					for _, item := range v {
+						params.Add(key, fmt.Sprint(item))
+					}
+				case nil:
+				default:
+					params.Add(key, fmt.Sprint(v))
+				}
+			}
+		}
+	} else if c.Request.Method == "POST" || c.Request.Method == "PUT" || c.Request.Method == "PATCH" {
+		if err := c.Request.ParseMultipartForm(1 << 20); err != nil {
+			c.Request.ParseForm()
+		}
+		for key, values := range c.Request.PostForm {
+			// Form arrays are sent as key[]
+			key = strings.TrimSuffix(key, "[]")
+			params[key] = append(params[key], values...)
+		}
+	}
+
+	c.Set("apiParams", params)
+	return params
+}
+
+// apiParam returns a single request parameter
+func apiParam(c *gin.Context, key string) string {
+	return strings.TrimSpace(apiParams(c).Get(key))
+}
+
+// generateOAuthSecret returns a random URL-safe secret for client secrets, codes and tokens
+func generateOAuthSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatalf("Failed to read random bytes: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// generateOAuthUserCode returns a short code like ABCD-EFGH for typing into the TUI
+func generateOAuthUserCode() string {
+	var b strings.Builder
+	alphabetSize := big.NewInt(int64(len(oauthUserCodeAlphabet)))
+	for i := 0; i < 8; i++ {
+		if i == 4 {
+			b.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			log.Fatalf("Failed to read random bytes: %v", err)
+		}
+		b.WriteByte(oauthUserCodeAlphabet[n.Int64()])
+	}
+	return b.String()
+}
+
+// hashOAuthToken returns the hash under which an access token is stored
+func hashOAuthToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// validateOAuthScopes checks requested scopes against the supported ones and the app's registered scopes.
+// An empty request falls back to the app's scopes.
+func validateOAuthScopes(requested string, allowed []string) ([]string, error) {
+	scopes := domain.ParseOAuthScopes(requested)
+	if len(scopes) == 0 {
+		if len(allowed) > 0 {
+			return allowed, nil
+		}
+		return []string{oauthDefaultScope}, nil
+	}
+	for _, scope := range scopes {
+		base, _, _ := strings.Cut(scope, ":")
+		supported := false
+		for _, s := range oauthSupportedScopes {
+			if s == base {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return nil, fmt.Errorf("unknown scope %q", scope)
+		}
+		if allowed != nil && !domain.HasOAuthScope(allowed, scope) {
+			return nil, fmt.Errorf("scope %q was not registered for this app", scope)
+		}
+	}
+	return scopes, nil
+}
+
+// oauthError writes an OAuth error response
+func oauthError(c *gin.Context, status int, code, description string) {
+	c.JSON(status, gin.H{"error": code, "error_description": description})
+}
+
+// HandleOAuthCreateApp registers a client application (POST /api/v1/apps)
+func HandleOAuthCreateApp(c *gin.Context, conf *util.AppConfig) {
+	name := apiParam(c, "client_name")
+	if name == "" {
+		c.JSON(422, gin.H{"error": "Validation failed: Application name can't be blank"})
+		return
+	}
+
+	var redirectURIs []string
+	for _, value := range apiParams(c)["redirect_uris"] {
+		redirectURIs = append(redirectURIs, strings.Fields(value)...)
+	}
+	if len(redirectURIs) == 0 {
+		c.JSON(422, gin.H{"error": "Validation failed: Redirect URI can't be blank"})
+		return
+	}
+	for _, uri := range redirectURIs {
+		if uri == domain.OAuthOOBRedirect {
+			continue
+		}
+		if u, err := url.Parse(uri); err != nil || u.Scheme == "" {
+			c.JSON(422, gin.H{"error": "Validation failed: Redirect URI must be an absolute URI"})
+			return
+		}
+	}
+
+	scopes, err := validateOAuthScopes(apiParam(c, "scopes"), nil)
+	if err != nil {
+		c.JSON(422, gin.H{"error": "Validation failed: " + err.Error()})
+		return
+	}
+
+	app := &domain.OAuthApp{
+		Id:           uuid.New(),
+		ClientId:     generateOAuthSecret(),
+		ClientSecret: generateOAuthSecret(),
+		Name:         name,
+		Website:      apiParam(c, "website"),
+		RedirectURIs: redirectURIs,
+		Scopes:       scopes,
+		CreatedAt:    time.Now(),
+	}
+	if err := db.GetDB().CreateOAuthApp(app); err != nil {
+		log.Printf("Failed to create OAuth app: %v", err)
+		c.JSON(500, gin.H{"error": "Failed to register application"})
+		return
+	}
+
+	log.Printf("Registered OAuth app %q", app.Name)
+	c.JSON(200, newMastodonApplication(app, true))
+}
+
+// HandleOAuthAuthorize starts the authorization code flow (GET /oauth/authorize).
+// Instead of a login form, the user confirms a short code in the SSH TUI.
+func HandleOAuthAuthorize(c *gin.Context, conf *util.AppConfig) {
+	database := db.GetDB()
+
+	if responseType := c.Query("response_type"); responseType != "" && responseType != "code" {
+		c.HTML(400, "oauth.html", gin.H{"Error": "Unsupported response type."})
+		return
+	}
+
+	err, app := database.ReadOAuthAppByClientId(c.Query("client_id"))
+	if err != nil || app == nil {
+		c.HTML(400, "oauth.html", gin.H{"Error": "Unknown client application."})
+		return
+	}
+
+	redirectURI := c.Query("redirect_uri")
+	if redirectURI == "" && len(app.RedirectURIs) == 1 {
+		redirectURI = app.RedirectURIs[0]
+	}
+	if !app.AllowsRedirect(redirectURI) {
+		c.HTML(400, "oauth.html", gin.H{"AppName": app.Name, "Error": "The redirect URI is not registered for this application."})
+		return
+	}
+
+	scopes, err := validateOAuthScopes(c.Query("scope"), app.Scopes)
+	if err != nil {
+		c.HTML(400, "oauth.html", gin.H{"AppName": app.Name, "Error": "Invalid scope: " + err.Error()})
+		return
+	}
+
+	now := time.Now()
+	auth := &domain.OAuthAuthorization{
+		Id:          uuid.New(),
+		AppId:       app.Id,
+		UserCode:    generateOAuthUserCode(),
+		RedirectURI: redirectURI,
+		Scopes:      scopes,
+		State:       c.Query("state"),
+		ExpiresAt:   now.Add(oauthAuthorizationTTL),
+		CreatedAt:   now,
+	}
+	if err := database.CreateOAuthAuthorization(auth); err != nil {
+		log.Printf("Failed to create OAuth authorization: %v", err)
+		c.HTML(500, "oauth.html", gin.H{"AppName": app.Name, "Error": "Failed to start authorization."})
+		return
+	}
+
+	c.Redirect(302, "/oauth/authorize/"+auth.Id.String())
+}
+
+// HandleOAuthAuthorizeStatus shows the code to confirm and, once confirmed, sends the user back to the app
+func HandleOAuthAuthorizeStatus(c *gin.Context, conf *util.AppConfig) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.HTML(404, "oauth.html", gin.H{"Error": "Authorization request not found."})
+		return
+	}
+
+	err, auth := db.GetDB().ReadOAuthAuthorizationById(id)
+	if err != nil || auth == nil {
+		c.HTML(404, "oauth.html", gin.H{"Error": "Authorization request not found. It may have expired or already been used."})
+		return
+	}
+
+	data := gin.H{
+		"AppName": auth.AppName,
+		"Scopes":  strings.Join(auth.Scopes, " "),
+	}
+
+	switch auth.Status {
+	case domain.OAuthAuthorizationApproved:
+		if auth.RedirectURI == domain.OAuthOOBRedirect {
+			data["Code"] = auth.Code
+			c.HTML(200, "oauth.html", data)
+			return
+		}
+		c.Redirect(302, oauthRedirectURL(auth.RedirectURI, auth.Code, auth.State))
+	case domain.OAuthAuthorizationDenied:
+		data["Error"] = "Access was denied."
+		c.HTML(403, "oauth.html", data)
+	default:
+		if auth.IsExpired(time.Now()) {
+			data["Error"] = "This code has expired. Please start the login from the app again."
+			c.HTML(410, "oauth.html", data)
+			return
+		}
+		data["Pending"] = true
+		data["UserCode"] = auth.UserCode
+		data["SshHost"] = sshHostForDisplay(conf)
+		c.HTML(200, "oauth.html", data)
+	}
+}
+
+// oauthRedirectURL appends the authorization code and state to the app's redirect URI
+func oauthRedirectURL(redirectURI, code, state string) string {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return redirectURI
+	}
+	q := u.Query()
+	q.Set("code", code)
+	if state != "" {
+		q.Set("state", state)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// sshHostForDisplay returns the ssh command arguments for connecting to this instance
+func sshHostForDisplay(conf *util.AppConfig) string {
+	host := conf.Conf.SslDomain
+	if host == "" {
+		host = conf.Conf.Host
+	}
+	if conf.Conf.SshPort != 0 && conf.Conf.SshPort != 22 {
+		return fmt.Sprintf("-p %d %s", conf.Conf.SshPort, host)
+	}
+	return host
+}
+
+// authenticateOAuthClient checks client credentials sent with a token or revoke request
+func authenticateOAuthClient(c *gin.Context) *domain.OAuthApp {
+	clientId := apiParam(c, "client_id")
+	clientSecret := apiParam(c, "client_secret")
+	if user, pass, ok := c.Request.BasicAuth(); ok {
+		clientId, clientSecret = user, pass
+	}
+
+	err, app := db.GetDB().ReadOAuthAppByClientId(clientId)
+	if err != nil || app == nil {
+		return nil
+	}
+	if subtle.ConstantTimeCompare([]byte(app.ClientSecret), []byte(clientSecret)) != 1 {
+		return nil
+	}
+	return app
+}
+
+// HandleOAuthToken exchanges an authorization code or client credentials for an access token (POST /oauth/token)
+func HandleOAuthToken(c *gin.Context, conf *util.AppConfig) {
+	database := db.GetDB()
+
+	app := authenticateOAuthClient(c)
+	if app == nil {
+		oauthError(c, 401, "invalid_client", "Client authentication failed")
+		return
+	}
+
+	token := &domain.OAuthToken{
+		Id:        uuid.New(),
+		AppId:     app.Id,
+		CreatedAt: time.Now(),
+	}
+
+	switch apiParam(c, "grant_type") {
+	case "authorization_code":
+		err, auth := database.ReadOAuthAuthorizationByCode(apiParam(c, "code"))
+		if err != nil || auth == nil || auth.AppId != app.Id {
+			oauthError(c, 400, "invalid_grant", "The authorization code is invalid")
+			return
+		}
+		// Codes are single use
+		if err := database.DeleteOAuthAuthorization(auth.Id); err != nil {
+			log.Printf("Failed to delete used OAuth authorization: %v", err)
+		}
+		if auth.IsExpired(time.Now()) {
+			oauthError(c, 400, "invalid_grant", "The authorization code has expired")
+			return
+		}
+		if redirectURI := apiParam(c, "redirect_uri"); redirectURI != "" && redirectURI != auth.RedirectURI {
+			oauthError(c, 400, "invalid_grant", "The redirect URI does not match the authorization request")
+			return
+		}
+		token.AccountId = auth.AccountId
+		token.Scopes = auth.Scopes
+	case "client_credentials":
+		scopes, err := validateOAuthScopes(apiParam(c, "scope"), app.Scopes)
+		if err != nil {
+			oauthError(c, 400, "invalid_scope", err.Error())
+			return
+		}
+		token.Scopes = scopes
+	default:
+		oauthError(c, 400, "unsupported_grant_type", "Only authorization_code and client_credentials are supported")
+		return
+	}
+
+	accessToken := generateOAuthSecret()
+	token.TokenHash = hashOAuthToken(accessToken)
+	if err := database.CreateOAuthToken(token); err != nil {
+		log.Printf("Failed to store OAuth token: %v", err)
+		oauthError(c, 500, "server_error", "Failed to issue token")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"scope":        strings.Join(token.Scopes, " "),
+		"created_at":   token.CreatedAt.Unix(),
+	})
+}
+
+// HandleOAuthRevoke revokes an access token (POST /oauth/revoke)
+func HandleOAuthRevoke(c *gin.Context, conf *util.AppConfig) {
+	app := authenticateOAuthClient(c)
+	if app == nil {
+		oauthError(c, 403, "unauthorized_client", "You are not authorized to revoke this token")
+		return
+	}
+
+	database := db.GetDB()
+	err, token := database.ReadOAuthTokenByHash(hashOAuthToken(apiParam(c, "token")))
+	// Unknown tokens are not an error (RFC 7009)
+	if err == nil && token != nil && token.AppId == app.Id {
+		if err := database.DeleteOAuthToken(token.Id); err != nil {
+			log.Printf("Failed to revoke OAuth token: %v", err)
+			oauthError(c, 500, "server_error", "Failed to revoke token")
+			return
+		}
+	}
+	c.JSON(200, gin.H{})
+}
@@ -7,14 +7,16 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/deemkeen/stegodon/activitypub"
 	"github.com/deemkeen/stegodon/db"
 	"github.com/deemkeen/stegodon/domain"
 	"github.com/deemkeen/stegodon/util"
 )
 
 // GetOutbox returns an ActivityPub OrderedCollection of a user's public posts
-// This allows remote servers to discover posts without following the user
-func GetOutbox(actor string, page int, conf *util.AppConfig) (error, string) {
+// This allows remote servers to discover posts without following the user.
+// Pages are addressed by cursor: page 1 starts at the newest post, max_id continues after before.
+func GetOutbox(actor string, page int, before domain.Cursor, conf *util.AppConfig) (error, string) {
 	// Verify the account exists
 	err, _ := db.GetDB().ReadAccByUsername(actor)
 	if err != nil {
@@ -26,17 +28,13 @@ func GetOutbox(actor string, page int, conf *util.AppConfig) (error, string) {
 	outboxURL := fmt.Sprintf("%s/users/%s/outbox", baseURL, actor)
 
 	// If no page parameter, return the collection metadata
-	if page == 0 {
+	if page == 0 && before.IsZero() {
 		// Count total public posts
-		err, notes := db.GetDB().ReadPublicNotesByUsername(actor, 999999, 0)
+		totalItems, err := db.GetDB().CountPublicNotesByUsername(actor)
 		if err != nil {
 			log.Printf("GetOutbox: Failed to count notes for %s: %v", actor, err)
 			return err, "{}"
 		}
-		totalItems := 0
-		if notes != nil {
-			totalItems = len(*notes)
-		}
 
 		collection := map[string]any{
 			"@context":   "https://www.w3.org/ns/activitystreams",
@@ -55,35 +53,42 @@ func GetOutbox(actor string, page int, conf *util.AppConfig) (error, string) {
 	}
 
 	// Return a paginated collection page
-	return getOutboxPage(actor, page, conf)
+	return getOutboxPage(actor, before, conf)
 }
 
-func getOutboxPage(actor string, page int, conf *util.AppConfig) (error, string) {
+func getOutboxPage(actor string, before domain.Cursor, conf *util.AppConfig) (error, string) {
 	itemsPerPage := 20
-	offset := (page - 1) * itemsPerPage
 
 	// Fetch notes for this page
-	err, notes := db.GetDB().ReadPublicNotesByUsername(actor, itemsPerPage+1, offset)
+	err, notes := db.GetDB().ReadPublicNotesByUsername(actor, before, itemsPerPage+1)
 	if err != nil {
-		log.Printf("GetOutbox: Failed to fetch notes page %d for %s: %v", page, actor, err)
+		log.Printf("GetOutbox: Failed to fetch notes after %q for %s: %v", before, actor, err)
 		return err, "{}"
 	}
 
 	baseURL := fmt.Sprintf("https://%s", conf.Conf.SslDomain)
 	outboxURL := fmt.Sprintf("%s/users/%s/outbox", baseURL, actor)
-	pageURL := fmt.Sprintf("%s?page=%d", outboxURL, page)
+	pageURL := fmt.Sprintf("%s?page=1", outboxURL)
+	if !before.IsZero() {
+		pageURL = fmt.Sprintf("%s?max_id=%s", outboxURL, before)
+	}
 
 	// Check if there are more items
 	hasMore := false
+	var last domain.Cursor
 	items := []any{}
 	hasHashtags := false
+	hasEmojis := false
+	localEmojis := localEmojiMap()
 
 	if notes != nil {
-		// Check if any notes have hashtags
+		// Check if any notes have hashtags or custom emoji
 		for _, note := range *notes {
 			if len(util.ParseHashtags(note.Message)) > 0 {
 				hasHashtags = true
-				break
+			}
+			if len(activitypub.EmojiTags(note.Message, baseURL, localEmojis)) > 0 {
+				hasEmojis = true
 			}
 		}
 
@@ -91,24 +96,15 @@ func getOutboxPage(actor string, page int, conf *util.AppConfig) (error, string)
 			hasMore = true
 			// Trim the extra item
 			pageNotes := (*notes)[:itemsPerPage]
-			items = makeNoteActivities(pageNotes, actor, conf)
+			last = pageNotes[len(pageNotes)-1].Cursor()
+			items = makeNoteActivities(pageNotes, actor, localEmojis, conf)
 		} else {
-			items = makeNoteActivities(*notes, actor, conf)
+			items = makeNoteActivities(*notes, actor, localEmojis, conf)
 		}
 	}
 
-	// Build context - include Hashtag definition if any notes have hashtags
-	var context any
-	if hasHashtags {
-		context = []any{
-			"https://www.w3.org/ns/activitystreams",
-			map[string]any{
-				"Hashtag": "as:Hashtag",
-			},
-		}
-	} else {
-		context = "https://www.w3.org/ns/activitystreams"
-	}
+	// Build context - include Hashtag/Emoji definitions if any notes use them
+	context := activitypub.NoteContext(hasHashtags, hasEmojis)
 
 	collectionPage := map[string]any{
 		"@context":     context,
@@ -120,12 +116,12 @@ func getOutboxPage(actor string, page int, conf *util.AppConfig) (error, string)
 
 	// Add next link if there are more pages
 	if hasMore {
-		collectionPage["next"] = fmt.Sprintf("%s?page=%d", outboxURL, page+1)
+		collectionPage["next"] = fmt.Sprintf("%s?max_id=%s", outboxURL, last)
 	}
 
-	// Add prev link if not first page
-	if page > 1 {
-		collectionPage["prev"] = fmt.Sprintf("%s?page=%d", outboxURL, page-1)
+	// Add prev link back to the newest page if not first page
+	if !before.IsZero() {
+		collectionPage["prev"] = fmt.Sprintf("%s?page=1", outboxURL)
 	}
 
 	jsonData, err := json.Marshal(collectionPage)
@@ -137,7 +133,7 @@ func getOutboxPage(actor string, page int, conf *util.AppConfig) (error, string)
 }
 
 // makeNoteActivities converts domain.Note objects to ActivityPub Create activities
-func makeNoteActivities(notes []domain.Note, actor string, conf *util.AppConfig) []any {
+func makeNoteActivities(notes []domain.Note, actor string, localEmojis map[string]string, conf *util.AppConfig) []any {
 	activities := make([]any, 0, len(notes))
 	baseURL := fmt.Sprintf("https://%s", conf.Conf.SslDomain)
 	database := db.GetDB()
@@ -218,6 +214,9 @@ func makeNoteActivities(notes []domain.Note, actor string, conf *util.AppConfig)
 			contentHTML = util.MentionsToActivityPubHTML(contentHTML, mentionURIs)
 		}
 
+		// Add Emoji tags for local custom emoji used in the note
+		tags = append(tags, activitypub.EmojiTags(note.Message, baseURL, localEmojis)...)
+
 		// Build the Note object
 		noteObj := map[string]any{
 			"id":           objectURI,
@@ -243,6 +242,9 @@ func makeNoteActivities(notes []domain.Note, actor string, conf *util.AppConfig)
 			noteObj["tag"] = tags
 		}
 
+		// Long-form notes are listed as Articles
+		activitypub.ApplyArticle(noteObj, &note)
+
 		// Build the Create activity wrapping the Note
 		// Use note URI with #activity fragment so activity ID resolves to the note
 		activityURI := fmt.Sprintf("%s/notes/%s#activity", baseURL, note.Id.String())
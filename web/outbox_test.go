@@ -39,7 +39,7 @@ func TestOutboxJSONStructure(t *testing.T) {
 	conf.Conf.SslDomain = "example.com"
 
 	// Test collection metadata (page 0)
-	_, outbox := GetOutbox("nonexistent", 0, conf)
+	_, outbox := GetOutbox("nonexistent", 0, domain.Cursor{}, conf)
 
 	// Should return valid JSON even for non-existent users
 	var data map[string]any
@@ -55,7 +55,7 @@ func TestMakeNoteActivities(t *testing.T) {
 	conf.Conf.SslDomain = "example.com"
 
 	// Empty notes should return empty array
-	activities := makeNoteActivities([]domain.Note{}, "testuser", conf)
+	activities := makeNoteActivities([]domain.Note{}, "testuser", nil, conf)
 	if len(activities) != 0 {
 		t.Errorf("makeNoteActivities with empty notes should return empty array, got %d items", len(activities))
 	}
@@ -98,7 +98,7 @@ func TestOutboxCollectionFields(t *testing.T) {
 	conf.Conf.SslDomain = "example.com"
 
 	// For a non-existent user, we should still get valid JSON
-	_, outbox := GetOutbox("testuser", 0, conf)
+	_, outbox := GetOutbox("testuser", 0, domain.Cursor{}, conf)
 
 	var data map[string]any
 	err := json.Unmarshal([]byte(outbox), &data)
@@ -0,0 +1,124 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/deemkeen/stegodon/activitypub"
+	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
+	"github.com/deemkeen/stegodon/util"
+	"github.com/google/uuid"
+)
+
+// ResolveResult is what a pasted URL, ActivityPub id or handle points to
+type ResolveResult struct {
+	Note          *domain.Note          // Local post
+	Account       *domain.Account       // Local account
+	Activity      *domain.Activity      // Remote post, stored so it can be liked, boosted and replied to
+	RemoteAccount *domain.RemoteAccount // Remote account, or the author of Activity
+}
+
+// IsPost reports whether the input resolved to a (local or remote) post
+func (r *ResolveResult) IsPost() bool {
+	return r.Note != nil || r.Activity != nil
+}
+
+// ResolveURL looks up a post or account by URL, ActivityPub id or @user@domain handle.
+// Remote objects are fetched with content negotiation and stored locally; profile URLs
+// that do not serve ActivityPub JSON are resolved through WebFinger instead.
+func ResolveURL(input string, conf *util.AppConfig) (*ResolveResult, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, fmt.Errorf("nothing to resolve")
+	}
+
+	// @user@domain handles
+	if !strings.Contains(input, "/") {
+		username, userDomain := splitHandle(input)
+		if username == "" || userDomain == "" {
+			return nil, fmt.Errorf("not a URL or @user@domain handle: %s", input)
+		}
+		if strings.EqualFold(userDomain, conf.Conf.SslDomain) {
+			return resolveLocalAccount(username)
+		}
+		return resolveRemoteHandle(username, userDomain, conf)
+	}
+
+	if !strings.HasPrefix(input, "http://") && !strings.HasPrefix(input, "https://") {
+		input = "https://" + input
+	}
+	parsed, err := url.Parse(input)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid URL: %s", input)
+	}
+
+	if strings.EqualFold(parsed.Host, conf.Conf.SslDomain) {
+		return resolveLocalURL(parsed.Path)
+	}
+	if !conf.Conf.WithAp {
+		return nil, fmt.Errorf("federation is disabled on this server")
+	}
+
+	resolved, err := activitypub.ResolveObject(input)
+	if err == nil {
+		return &ResolveResult{Activity: resolved.Activity, RemoteAccount: resolved.Actor}, nil
+	}
+
+	// Profile pages of servers without content negotiation
+	if username, userDomain, ok := util.ParseActivityPubURL(input); ok {
+		log.Printf("Resolve: Fetching %s failed (%v), trying WebFinger for %s@%s", input, err, username, userDomain)
+		return resolveRemoteHandle(username, userDomain, conf)
+	}
+	return nil, err
+}
+
+// resolveRemoteHandle looks up a remote account via WebFinger
+func resolveRemoteHandle(username, userDomain string, conf *util.AppConfig) (*ResolveResult, error) {
+	if !conf.Conf.WithAp {
+		return nil, fmt.Errorf("federation is disabled on this server")
+	}
+	actorURI, err := ResolveWebFinger(username, userDomain)
+	if err != nil {
+		return nil, fmt.Errorf("webfinger resolution failed: %w", err)
+	}
+	actor, err := activitypub.GetOrFetchActor(actorURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch account %s: %w", actorURI, err)
+	}
+	return &ResolveResult{RemoteAccount: actor}, nil
+}
+
+// resolveLocalURL maps a URL of this server (web UI or ActivityPub) to a post or account
+func resolveLocalURL(path string) (*ResolveResult, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case len(parts) == 2 && parts[0] == "notes",
+		len(parts) == 3 && parts[0] == "u":
+		id, err := uuid.Parse(parts[len(parts)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid post id: %s", parts[len(parts)-1])
+		}
+		err, note := db.GetDB().ReadNoteId(id)
+		if err != nil || note == nil {
+			return nil, fmt.Errorf("post not found")
+		}
+		return &ResolveResult{Note: note}, nil
+	case len(parts) == 2 && (parts[0] == "u" || parts[0] == "users"):
+		return resolveLocalAccount(parts[1])
+	case len(parts) == 1 && strings.HasPrefix(parts[0], "@"):
+		return resolveLocalAccount(strings.TrimPrefix(parts[0], "@"))
+	}
+	return nil, fmt.Errorf("not a post or profile URL: %s", path)
+}
+
+func resolveLocalAccount(username string) (*ResolveResult, error) {
+	err, account := db.GetDB().ReadAccByUsername(username)
+	if err != nil || account == nil {
+		return nil, fmt.Errorf("user not found: %s", username)
+	}
+	return &ResolveResult{Account: account}, nil
+}
@@ -0,0 +1,39 @@
+package web
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/deemkeen/stegodon/util"
+)
+
+func TestResolveURLRejectsInvalidInput(t *testing.T) {
+	conf := &util.AppConfig{}
+	conf.Conf.SslDomain = "example.com"
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "nothing to resolve"},
+		{"   ", "nothing to resolve"},
+		{"alice", "not a URL or @user@domain handle"},
+		{"@bob@remote.example", "federation is disabled"},
+		{"https://remote.example/@bob/123", "federation is disabled"},
+		{"https://", "invalid URL"},
+	}
+	for _, tt := range tests {
+		_, err := ResolveURL(tt.input, conf)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ResolveURL(%q) error = %v, want %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestResolveLocalURLRejectsUnknownPaths(t *testing.T) {
+	for _, path := range []string{"/", "/tags/go", "/u/alice/not-a-uuid", "/notes/123"} {
+		if _, err := resolveLocalURL(path); err == nil {
+			t.Errorf("Expected error for %s", path)
+		}
+	}
+}
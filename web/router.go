@@ -12,6 +12,7 @@ import (
 
 	"github.com/deemkeen/stegodon/activitypub"
 	"github.com/deemkeen/stegodon/db"
+	"github.com/deemkeen/stegodon/domain"
 	"github.com/deemkeen/stegodon/util"
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
@@ -30,7 +31,7 @@ var embeddedLogo []byte
 var embeddedCSS []byte
 
 // isActorFromRelay checks if an actor URI matches any relay subscription (exact or domain match)
-func isActorFromRelay(actorURI string, database *db.DB) bool {
+func isActorFromRelay(actorURI string, database db.Store) bool {
 	// First try exact match
 	err, relay := database.ReadRelayByActorURI(actorURI)
 	if err == nil && relay != nil {
@@ -84,7 +85,7 @@ func Router(conf *util.AppConfig) (*gin.Engine, error) {
 	gin.DefaultErrorWriter = util.GetLogWriter()
 
 	g := gin.New()
-	g.Use(gin.Logger(), gin.Recovery())
+	g.Use(gin.Logger(), MetricsMiddleware(), gin.Recovery())
 	g.Use(gzip.Gzip(gzip.DefaultCompression))
 
 	// Load HTML templates from embedded filesystem (must be before routes)
@@ -235,7 +236,7 @@ func Router(conf *util.AppConfig) (*gin.Engine, error) {
 			c.JSON(200, gin.H{"users": users})
 		})
 
-		// Avatar upload routes
+		// Avatar and custom emoji upload routes
 		g.GET("/upload/:token", func(c *gin.Context) {
 			HandleUploadForm(c, conf)
 		})
@@ -248,11 +249,22 @@ func Router(conf *util.AppConfig) (*gin.Engine, error) {
 			ServeAvatar(c, conf)
 		})
 
+		// Mastodon-compatible client API for phone and web apps
+		RegisterMastodonRoutes(g, conf)
+
 		log.Println("Web UI routes enabled")
 	} else {
 		log.Println("SSH-only mode: Web UI routes disabled")
 	}
 
+	// Health, readiness and admin status endpoints
+	RegisterHealthRoutes(g, db.GetDB())
+
+	// Serve custom emoji images (referenced by federated Emoji tags, so always enabled)
+	g.GET("/emojis/:filename", func(c *gin.Context) {
+		ServeEmoji(c, conf)
+	})
+
 	// RSS Feed
 	g.GET("/feed", func(c *gin.Context) {
 
@@ -477,10 +489,16 @@ func Router(conf *util.AppConfig) (*gin.Engine, error) {
 			actor := c.Param("actor")
 			pageStr := c.Query("page")
 			page := ParsePageParam(pageStr)
+			before, err := domain.ParseCursor(c.Query("max_id"))
+			if err != nil {
+				c.Header("Content-Type", "application/activity+json; charset=utf-8")
+				c.Render(400, render.String{Format: "{}"})
+				return
+			}
 
-			log.Printf("GET /users/%s/outbox (page=%d)", actor, page)
+			log.Printf("GET /users/%s/outbox (page=%d, max_id=%s)", actor, page, before)
 
-			err, outbox := GetOutbox(actor, page, conf)
+			err, outbox := GetOutbox(actor, page, before, conf)
 			if err != nil {
 				c.Header("Content-Type", "application/activity+json; charset=utf-8")
 				c.Render(404, render.String{Format: "{}"})
@@ -76,15 +76,16 @@ func GetRSS(conf *util.AppConfig, username string) (string, error) {
 			// Convert Markdown links and raw URLs to HTML for RSS feed
 			contentHTML := util.MarkdownLinksToHTML(note.Message)
 			contentHTML = util.LinkifyRawURLsHTML(contentHTML)
-			feedItems = append(feedItems,
-				&feeds.Item{
-					Id:      note.Id.String(),
-					Title:   note.CreatedAt.Format(util.DateTimeFormat()),
-					Link:    &feeds.Link{Href: buildURL(conf, fmt.Sprintf("/feed/%s", note.Id))},
-					Content: contentHTML,
-					Author:  &feeds.Author{Name: note.CreatedBy, Email: email},
-					Created: note.CreatedAt,
-				})
+			item := &feeds.Item{
+				Id:      note.Id.String(),
+				Title:   note.CreatedAt.Format(util.DateTimeFormat()),
+				Link:    &feeds.Link{Href: buildURL(conf, fmt.Sprintf("/feed/%s", note.Id))},
+				Content: contentHTML,
+				Author:  &feeds.Author{Name: note.CreatedBy, Email: email},
+				Created: note.CreatedAt,
+			}
+			applyArticleToRSSItem(item, &note)
+			feedItems = append(feedItems, item)
 		}
 	}
 
@@ -116,16 +117,28 @@ func GetRSSItem(conf *util.AppConfig, id uuid.UUID) (string, error) {
 	// Convert Markdown links to HTML for RSS feed
 	contentHTML := util.MarkdownLinksToHTML(note.Message)
 
-	feedItems = append(feedItems,
-		&feeds.Item{
-			Id:      note.Id.String(),
-			Title:   note.CreatedAt.Format(util.DateTimeFormat()),
-			Link:    &feeds.Link{Href: url},
-			Content: contentHTML,
-			Author:  &feeds.Author{Name: note.CreatedBy, Email: email},
-			Created: note.CreatedAt,
-		})
+	item := &feeds.Item{
+		Id:      note.Id.String(),
+		Title:   note.CreatedAt.Format(util.DateTimeFormat()),
+		Link:    &feeds.Link{Href: url},
+		Content: contentHTML,
+		Author:  &feeds.Author{Name: note.CreatedBy, Email: email},
+		Created: note.CreatedAt,
+	}
+	applyArticleToRSSItem(item, note)
+	feedItems = append(feedItems, item)
 
 	feed.Items = feedItems
 	return feed.ToRss()
 }
+
+// applyArticleToRSSItem publishes an article in full under its own title,
+// with its summary as the item description
+func applyArticleToRSSItem(item *feeds.Item, note *domain.Note) {
+	if !note.IsArticle() {
+		return
+	}
+	item.Title = note.Title
+	item.Description = util.ArticleSummary(note.Message, util.ArticleSummaryLength)
+	item.Content = util.ArticleToHTML(note.Message)
+}
@@ -5,8 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/deemkeen/stegodon/domain"
 	"github.com/deemkeen/stegodon/util"
 	"github.com/google/uuid"
+	"github.com/gorilla/feeds"
 )
 
 func TestGetRSSWithUsername(t *testing.T) {
@@ -308,3 +310,26 @@ func TestRSSMarkdownConversion(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyArticleToRSSItem(t *testing.T) {
+	created := time.Now()
+	item := &feeds.Item{Title: created.Format(util.DateTimeFormat()), Content: "short"}
+
+	// Short notes keep their date title and content
+	applyArticleToRSSItem(item, &domain.Note{Message: "short", CreatedAt: created})
+	if item.Content != "short" || item.Description != "" {
+		t.Errorf("Expected short note item unchanged, got %+v", item)
+	}
+
+	article := &domain.Note{Title: "Field Notes", Message: "First *day* out.\n\n## Day two\n\nRain.", CreatedAt: created}
+	applyArticleToRSSItem(item, article)
+	if item.Title != "Field Notes" {
+		t.Errorf("Expected article title, got %q", item.Title)
+	}
+	if !strings.Contains(item.Content, "<em>day</em>") || !strings.Contains(item.Content, "<h2") || !strings.Contains(item.Content, "Rain.") {
+		t.Errorf("Expected full rendered article content, got %q", item.Content)
+	}
+	if item.Description != "First day out. Day two Rain." {
+		t.Errorf("Expected summary description, got %q", item.Description)
+	}
+}
@@ -1,7 +1,6 @@
 package web
 
 import (
-	"encoding/json"
 	"fmt"
 	"html/template"
 	"log"
@@ -10,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/deemkeen/stegodon/actions"
 	"github.com/deemkeen/stegodon/db"
 	"github.com/deemkeen/stegodon/domain"
 	"github.com/deemkeen/stegodon/util"
@@ -26,10 +26,9 @@ type IndexPageData struct {
 	SSHPort       int
 	Version       string
 	Posts         []PostView
-	HasPrev       bool
-	HasNext       bool
-	PrevPage      int
-	NextPage      int
+	HasPrev       bool   // Not the newest page; links back to it
+	HasNext       bool   // Older posts exist
+	NextMaxId     string // Cursor of the last post, for ?max_id=
 	InfoBoxes     []InfoBoxView
 	ServerMessage *ServerMessageView
 }
@@ -58,8 +57,7 @@ type ProfilePageData struct {
 	TotalPosts    int
 	HasPrev       bool
 	HasNext       bool
-	PrevPage      int
-	NextPage      int
+	NextMaxId     string
 	InfoBoxes     []InfoBoxView
 	ServerMessage *ServerMessageView
 }
@@ -84,14 +82,16 @@ type PostView struct {
 	Message      string
 	MessageHTML  template.HTML // HTML-rendered message with clickable links
 	TimeAgo      string
-	CreatedAt    time.Time // For chronological sorting
-	InReplyToURI string    // URI of parent post if this is a reply
-	ReplyCount   int       // Number of replies to this post
-	LikeCount    int       // Number of likes on this post
-	BoostCount   int       // Number of boosts on this post
-	Likers       []string  // Usernames who liked this post
-	Boosters     []string  // Usernames who boosted this post
-	BoostedBy    string    // If non-empty, this post was boosted by this user
+	CreatedAt    time.Time              // For chronological sorting
+	InReplyToURI string                 // URI of parent post if this is a reply
+	ReplyCount   int                    // Number of replies to this post
+	LikeCount    int                    // Number of likes on this post
+	BoostCount   int                    // Number of boosts on this post
+	Reactions    []domain.ReactionCount // Emoji reactions grouped by emoji
+	Likers       []string               // Usernames who liked this post
+	Boosters     []string               // Usernames who boosted this post
+	BoostedBy    string                 // If non-empty, this post was boosted by this user
+	ArticleTitle string                 // Title if this post is a long-form article
 }
 
 // convertMarkdownToHTML converts markdown text to HTML
@@ -137,77 +137,14 @@ func formatTimeAgo(t time.Time) string {
 	}
 }
 
-// parseActivityContentForWeb extracts content and author info from an activity for web display
-// Returns content, username, domain, profileURL
-func parseActivityContentForWeb(activity *domain.Activity, database *db.DB) (content, username, userDomain, profileURL string) {
-	// Default to actor URI as fallback
-	username = activity.ActorURI
-	userDomain = ""
-	profileURL = activity.ActorURI
-
-	// Try to get better author info from cached remote account
-	err, remoteAcc := database.ReadRemoteAccountByActorURI(activity.ActorURI)
-	if err == nil && remoteAcc != nil {
-		username = remoteAcc.Username
-		userDomain = remoteAcc.Domain
-		profileURL = fmt.Sprintf("https://%s/@%s", remoteAcc.Domain, remoteAcc.Username)
-	} else {
-		// Parse username and domain from actor URI as fallback
-		// Format: https://domain.com/users/username or https://domain.com/@username
-		if strings.Contains(activity.ActorURI, "/users/") {
-			parts := strings.Split(activity.ActorURI, "/users/")
-			if len(parts) == 2 {
-				domainPart := strings.TrimPrefix(parts[0], "https://")
-				userDomain = domainPart
-				username = parts[1]
-				profileURL = fmt.Sprintf("https://%s/@%s", domainPart, parts[1])
-			}
-		} else if strings.Contains(activity.ActorURI, "/@") {
-			parts := strings.Split(activity.ActorURI, "/@")
-			if len(parts) == 2 {
-				domainPart := strings.TrimPrefix(parts[0], "https://")
-				userDomain = domainPart
-				username = parts[1]
-			}
-		}
-	}
-
-	// Parse content from raw JSON
-	if activity.RawJSON != "" {
-		var activityWrapper struct {
-			Type   string `json:"type"`
-			Object struct {
-				ID      string `json:"id"`
-				Content string `json:"content"`
-			} `json:"object"`
-		}
-
-		if err := json.Unmarshal([]byte(activity.RawJSON), &activityWrapper); err == nil {
-			content = util.StripHTMLTags(activityWrapper.Object.Content)
-		}
-	}
-
-	return content, username, userDomain, profileURL
-}
-
-// countTotalRepliesForWeb counts both local and remote replies to a note
-// When ActivityPub is enabled, it also counts remote activities that reply to this note
-func countTotalRepliesForWeb(database *db.DB, noteId uuid.UUID, sslDomain string, withAp bool) int {
-	// Count local replies first
-	localCount := 0
-	if count, err := database.CountRepliesByNoteId(noteId); err == nil {
-		localCount = count
-	}
-
-	// If ActivityPub is enabled, also count remote replies
-	if withAp && sslDomain != "" {
-		canonicalURI := fmt.Sprintf("https://%s/notes/%s", sslDomain, noteId.String())
-		if remoteCount, err := database.CountActivitiesByInReplyTo(canonicalURI); err == nil {
-			localCount += remoteCount
-		}
+// readReactionsForWeb returns the emoji reaction counts for a local note
+func readReactionsForWeb(database db.Store, noteId uuid.UUID) []domain.ReactionCount {
+	err, counts := database.ReadReactionCountsByNoteId(noteId)
+	if err != nil {
+		log.Printf("Failed to read reactions for note %s: %v", noteId, err)
+		return nil
 	}
-
-	return localCount
+	return counts
 }
 
 // loadServerMessageForWeb returns the server message with rendered HTML if web_enabled is true
@@ -231,53 +168,45 @@ func loadServerMessageForWeb() *ServerMessageView {
 	return nil
 }
 
+// webPostsPerPage is the page size of the paged web timelines
+const webPostsPerPage = 20
+
+// parseMaxId reads the ?max_id= cursor of a paged web timeline.
+// A missing or invalid cursor starts at the newest post.
+func parseMaxId(c *gin.Context) domain.Cursor {
+	before, err := domain.ParseCursor(c.Query("max_id"))
+	if err != nil {
+		return domain.Cursor{}
+	}
+	return before
+}
+
+// notesPage trims notes read with webPostsPerPage+1 to a page and returns the
+// cursor for the next page, empty on the last page
+func notesPage(notes []domain.Note) ([]domain.Note, string) {
+	if len(notes) <= webPostsPerPage {
+		return notes, ""
+	}
+	notes = notes[:webPostsPerPage]
+	return notes, notes[webPostsPerPage-1].Cursor().String()
+}
+
 func HandleIndex(c *gin.Context, conf *util.AppConfig) {
 	database := db.GetDB()
+	localEmojis := localEmojiMap()
 
 	// Pagination
-	page := 1
-	if pageStr := c.Query("page"); pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
-		}
-	}
-
-	postsPerPage := 20
-	offset := (page - 1) * postsPerPage
+	before := parseMaxId(c)
 
-	// Get all notes from all users (local timeline)
-	err, notes := database.ReadAllNotes()
+	// Get top-level notes from all users (local timeline), one extra to see if there is a next page
+	err, notes := database.ReadTopLevelNotes(uuid.Nil, before, webPostsPerPage+1)
 	if err != nil {
 		log.Printf("Failed to read notes: %v", err)
 		c.HTML(500, "base.html", gin.H{"Title": "Error", "Error": "Failed to load timeline"})
 		return
 	}
 
-	if notes == nil {
-		notes = &[]domain.Note{}
-	}
-
-	// Filter out replies (posts with InReplyToURI set)
-	var topLevelNotes []domain.Note
-	for _, note := range *notes {
-		if note.InReplyToURI == "" {
-			topLevelNotes = append(topLevelNotes, note)
-		}
-	}
-
-	totalPosts := len(topLevelNotes)
-
-	// Apply pagination
-	start := offset
-	end := offset + postsPerPage
-	if start > totalPosts {
-		start = totalPosts
-	}
-	if end > totalPosts {
-		end = totalPosts
-	}
-
-	paginatedNotes := topLevelNotes[start:end]
+	paginatedNotes, nextMaxId := notesPage(*notes)
 
 	// Convert to PostView
 	posts := make([]PostView, 0, len(paginatedNotes))
@@ -287,19 +216,27 @@ func HandleIndex(c *gin.Context, conf *util.AppConfig) {
 		messageHTML = util.LinkifyRawURLsHTML(messageHTML)
 		messageHTML = util.HighlightHashtagsHTML(messageHTML)
 		messageHTML = util.HighlightMentionsHTML(messageHTML, conf.Conf.SslDomain)
+		messageHTML = util.CustomEmojiToHTML(messageHTML, localEmojis)
+
+		// Articles are listed as a teaser; the full text is on their own page
+		if note.IsArticle() {
+			messageHTML = template.HTMLEscapeString(util.ArticleSummary(note.Message, util.ArticleSummaryLength))
+		}
 
 		// Get reply count for this post (including remote replies when AP is enabled)
-		replyCount := countTotalRepliesForWeb(database, note.Id, conf.Conf.SslDomain, conf.Conf.WithAp)
+		replyCount := actions.CountReplies(database, note.Id, conf.Conf.SslDomain, conf.Conf.WithAp)
 
 		posts = append(posts, PostView{
-			NoteId:      note.Id.String(),
-			Username:    note.CreatedBy,
-			Message:     note.Message,
-			MessageHTML: template.HTML(messageHTML),
-			TimeAgo:     formatTimeAgo(note.CreatedAt),
-			ReplyCount:  replyCount,
-			LikeCount:   note.LikeCount,
-			BoostCount:  note.BoostCount,
+			NoteId:       note.Id.String(),
+			Username:     note.CreatedBy,
+			Message:      note.Message,
+			MessageHTML:  template.HTML(messageHTML),
+			TimeAgo:      formatTimeAgo(note.CreatedAt),
+			ReplyCount:   replyCount,
+			LikeCount:    note.LikeCount,
+			BoostCount:   note.BoostCount,
+			Reactions:    readReactionsForWeb(database, note.Id),
+			ArticleTitle: note.Title,
 		})
 	}
 
@@ -337,10 +274,9 @@ func HandleIndex(c *gin.Context, conf *util.AppConfig) {
 		"SSHPort":       conf.Conf.SshPort,
 		"Version":       util.GetVersion(),
 		"Posts":         posts,
-		"HasPrev":       page > 1,
-		"HasNext":       end < totalPosts,
-		"PrevPage":      page - 1,
-		"NextPage":      page + 1,
+		"HasPrev":       !before.IsZero(),
+		"HasNext":       nextMaxId != "",
+		"NextMaxId":     nextMaxId,
 		"InfoBoxes":     infoBoxViews,
 		"ServerMessage": loadServerMessageForWeb(),
 		"ShowGlobal":    conf.Conf.ShowGlobal,
@@ -352,6 +288,7 @@ func HandleIndex(c *gin.Context, conf *util.AppConfig) {
 func HandleProfile(c *gin.Context, conf *util.AppConfig) {
 	username := c.Param("username")
 	database := db.GetDB()
+	localEmojis := localEmojiMap()
 
 	// Get user account
 	err, account := database.ReadAccByUsername(username)
@@ -362,49 +299,22 @@ func HandleProfile(c *gin.Context, conf *util.AppConfig) {
 	}
 
 	// Pagination
-	page := 1
-	if pageStr := c.Query("page"); pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
-		}
-	}
+	before := parseMaxId(c)
 
-	postsPerPage := 20
-	offset := (page - 1) * postsPerPage
-
-	// Get user's notes
-	err, notes := database.ReadNotesByUserId(account.Id)
+	// Get user's top-level notes, one extra to see if there is a next page
+	err, notes := database.ReadTopLevelNotes(account.Id, before, webPostsPerPage+1)
 	if err != nil {
 		log.Printf("Failed to read notes for user %s: %v", username, err)
 		c.HTML(500, "base.html", gin.H{"Title": "Error", "Error": "Failed to load user posts"})
 		return
 	}
 
-	if notes == nil {
-		notes = &[]domain.Note{}
-	}
-
-	// Filter out replies (posts with InReplyToURI set)
-	var topLevelNotes []domain.Note
-	for _, note := range *notes {
-		if note.InReplyToURI == "" {
-			topLevelNotes = append(topLevelNotes, note)
-		}
-	}
-
-	totalPosts := len(topLevelNotes)
-
-	// Apply pagination
-	start := offset
-	end := offset + postsPerPage
-	if start > totalPosts {
-		start = totalPosts
-	}
-	if end > totalPosts {
-		end = totalPosts
+	totalPosts, err := database.CountTopLevelNotesByUserId(account.Id)
+	if err != nil {
+		log.Printf("Failed to count notes for user %s: %v", username, err)
 	}
 
-	paginatedNotes := topLevelNotes[start:end]
+	paginatedNotes, nextMaxId := notesPage(*notes)
 
 	// Convert to PostView
 	posts := make([]PostView, 0, len(paginatedNotes))
@@ -414,19 +324,27 @@ func HandleProfile(c *gin.Context, conf *util.AppConfig) {
 		messageHTML = util.LinkifyRawURLsHTML(messageHTML)
 		messageHTML = util.HighlightHashtagsHTML(messageHTML)
 		messageHTML = util.HighlightMentionsHTML(messageHTML, conf.Conf.SslDomain)
+		messageHTML = util.CustomEmojiToHTML(messageHTML, localEmojis)
+
+		// Articles are listed as a teaser; the full text is on their own page
+		if note.IsArticle() {
+			messageHTML = template.HTMLEscapeString(util.ArticleSummary(note.Message, util.ArticleSummaryLength))
+		}
 
 		// Get reply count for this post (including remote replies when AP is enabled)
-		replyCount := countTotalRepliesForWeb(database, note.Id, conf.Conf.SslDomain, conf.Conf.WithAp)
+		replyCount := actions.CountReplies(database, note.Id, conf.Conf.SslDomain, conf.Conf.WithAp)
 
 		posts = append(posts, PostView{
-			NoteId:      note.Id.String(),
-			Username:    note.CreatedBy,
-			Message:     note.Message,
-			MessageHTML: template.HTML(messageHTML),
-			TimeAgo:     formatTimeAgo(note.CreatedAt),
-			ReplyCount:  replyCount,
-			LikeCount:   note.LikeCount,
-			BoostCount:  note.BoostCount,
+			NoteId:       note.Id.String(),
+			Username:     note.CreatedBy,
+			Message:      note.Message,
+			MessageHTML:  template.HTML(messageHTML),
+			TimeAgo:      formatTimeAgo(note.CreatedAt),
+			ReplyCount:   replyCount,
+			LikeCount:    note.LikeCount,
+			BoostCount:   note.BoostCount,
+			Reactions:    readReactionsForWeb(database, note.Id),
+			ArticleTitle: note.Title,
 		})
 	}
 
@@ -467,10 +385,9 @@ func HandleProfile(c *gin.Context, conf *util.AppConfig) {
 		},
 		Posts:         posts,
 		TotalPosts:    totalPosts,
-		HasPrev:       page > 1,
-		HasNext:       end < totalPosts,
-		PrevPage:      page - 1,
-		NextPage:      page + 1,
+		HasPrev:       !before.IsZero(),
+		HasNext:       nextMaxId != "",
+		NextMaxId:     nextMaxId,
 		InfoBoxes:     infoBoxViews,
 		ServerMessage: loadServerMessageForWeb(),
 	}
@@ -480,6 +397,7 @@ func HandleProfile(c *gin.Context, conf *util.AppConfig) {
 
 type SinglePostPageData struct {
 	Title         string
+	Description   string // Plain-text description for meta tags
 	Host          string
 	SSHPort       int
 	Version       string
@@ -499,6 +417,7 @@ type TagPageData struct {
 	Tag           string
 	Posts         []PostView
 	TotalPosts    int
+	Followers     int // local accounts following this tag
 	HasPrev       bool
 	HasNext       bool
 	PrevPage      int
@@ -511,6 +430,7 @@ func HandleSinglePost(c *gin.Context, conf *util.AppConfig) {
 	username := c.Param("username")
 	noteIdStr := c.Param("noteid")
 	database := db.GetDB()
+	localEmojis := localEmojiMap()
 
 	// Parse note ID
 	noteId, err := uuid.Parse(noteIdStr)
@@ -554,9 +474,19 @@ func HandleSinglePost(c *gin.Context, conf *util.AppConfig) {
 	messageHTML = util.LinkifyRawURLsHTML(messageHTML)
 	messageHTML = util.HighlightHashtagsHTML(messageHTML)
 	messageHTML = util.HighlightMentionsHTML(messageHTML, conf.Conf.SslDomain)
+	messageHTML = util.CustomEmojiToHTML(messageHTML, localEmojis)
+
+	// Articles get full markdown rendering and are titled and described by their own text
+	title := fmt.Sprintf("@%s - %s", username, formatTimeAgo(note.CreatedAt))
+	description := note.Message
+	if note.IsArticle() {
+		messageHTML = util.ArticleToHTML(note.Message)
+		title = note.Title
+		description = util.ArticleSummary(note.Message, util.ArticleSummaryLength)
+	}
 
 	// Get reply count for this post (including remote replies when AP is enabled)
-	replyCount := countTotalRepliesForWeb(database, noteId, conf.Conf.SslDomain, conf.Conf.WithAp)
+	replyCount := actions.CountReplies(database, noteId, conf.Conf.SslDomain, conf.Conf.WithAp)
 
 	// Get engagement info (who liked and boosted this post)
 	likers, _ := database.ReadLikersInfoByNoteId(noteId)
@@ -572,8 +502,10 @@ func HandleSinglePost(c *gin.Context, conf *util.AppConfig) {
 		ReplyCount:   replyCount,
 		LikeCount:    note.LikeCount,
 		BoostCount:   note.BoostCount,
+		Reactions:    readReactionsForWeb(database, noteId),
 		Likers:       likers,
 		Boosters:     boosters,
+		ArticleTitle: note.Title,
 	}
 
 	// Check if this is a reply and fetch parent post
@@ -586,9 +518,10 @@ func HandleSinglePost(c *gin.Context, conf *util.AppConfig) {
 			parentMessageHTML = util.LinkifyRawURLsHTML(parentMessageHTML)
 			parentMessageHTML = util.HighlightHashtagsHTML(parentMessageHTML)
 			parentMessageHTML = util.HighlightMentionsHTML(parentMessageHTML, conf.Conf.SslDomain)
+			parentMessageHTML = util.CustomEmojiToHTML(parentMessageHTML, localEmojis)
 
 			// Get reply count for parent post (including remote replies when AP is enabled)
-			parentReplyCount := countTotalRepliesForWeb(database, parentNote.Id, conf.Conf.SslDomain, conf.Conf.WithAp)
+			parentReplyCount := actions.CountReplies(database, parentNote.Id, conf.Conf.SslDomain, conf.Conf.WithAp)
 
 			parentPost = &PostView{
 				NoteId:      parentNote.Id.String(),
@@ -612,9 +545,10 @@ func HandleSinglePost(c *gin.Context, conf *util.AppConfig) {
 			replyMessageHTML = util.LinkifyRawURLsHTML(replyMessageHTML)
 			replyMessageHTML = util.HighlightHashtagsHTML(replyMessageHTML)
 			replyMessageHTML = util.HighlightMentionsHTML(replyMessageHTML, conf.Conf.SslDomain)
+			replyMessageHTML = util.CustomEmojiToHTML(replyMessageHTML, localEmojis)
 
 			// Get reply count for this reply (including remote replies when AP is enabled)
-			replyReplyCount := countTotalRepliesForWeb(database, replyNote.Id, conf.Conf.SslDomain, conf.Conf.WithAp)
+			replyReplyCount := actions.CountReplies(database, replyNote.Id, conf.Conf.SslDomain, conf.Conf.WithAp)
 
 			replies = append(replies, PostView{
 				NoteId:      replyNote.Id.String(),
@@ -654,13 +588,14 @@ func HandleSinglePost(c *gin.Context, conf *util.AppConfig) {
 				}
 
 				// Parse content and author info from activity
-				replyContent, replyUsername, replyDomain, replyProfileURL := parseActivityContentForWeb(&activity, database)
+				replyContent, replyUsername, replyDomain, replyProfileURL := actions.ActivityContent(&activity, database)
 
 				// Process content for display
 				replyMessageHTML := util.MarkdownLinksToHTML(replyContent)
 				replyMessageHTML = util.LinkifyRawURLsHTML(replyMessageHTML)
 				replyMessageHTML = util.HighlightHashtagsHTML(replyMessageHTML)
 				replyMessageHTML = util.HighlightMentionsHTML(replyMessageHTML, conf.Conf.SslDomain)
+				replyMessageHTML = util.CustomEmojiToHTML(replyMessageHTML, util.ExtractEmojiTagsFromJSON(activity.RawJSON))
 
 				// Get reply count for this remote reply (using object URI)
 				replyReplyCount := 0
@@ -717,11 +652,12 @@ func HandleSinglePost(c *gin.Context, conf *util.AppConfig) {
 	}
 
 	data := SinglePostPageData{
-		Title:   fmt.Sprintf("@%s - %s", username, formatTimeAgo(note.CreatedAt)),
-		Host:    host,
-		SSHPort: conf.Conf.SshPort,
-		Version: util.GetVersion(),
-		Post:    post,
+		Title:       title,
+		Description: description,
+		Host:        host,
+		SSHPort:     conf.Conf.SshPort,
+		Version:     util.GetVersion(),
+		Post:        post,
 		User: UserView{
 			Username:      account.Username,
 			DisplayName:   account.DisplayName,
@@ -743,18 +679,10 @@ func HandleGlobalTimeline(c *gin.Context, conf *util.AppConfig) {
 	database := db.GetDB()
 
 	// Pagination
-	page := 1
-	if pageStr := c.Query("page"); pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
-		}
-	}
+	before := parseMaxId(c)
 
-	postsPerPage := 20
-	offset := (page - 1) * postsPerPage
-
-	// Get global timeline posts (local + federated)
-	err, posts := database.ReadGlobalTimelinePosts(postsPerPage, offset)
+	// Get global timeline posts (local + federated), one extra to see if there is a next page
+	err, posts := database.ReadGlobalTimelinePosts(before, webPostsPerPage+1)
 	if err != nil {
 		log.Printf("Failed to read global timeline: %v", err)
 		c.HTML(500, "base.html", gin.H{"Title": "Error", "Error": "Failed to load global timeline"})
@@ -764,11 +692,10 @@ func HandleGlobalTimeline(c *gin.Context, conf *util.AppConfig) {
 		posts = &[]domain.GlobalTimelinePost{}
 	}
 
-	// Get total count for pagination
-	totalPosts, err := database.CountGlobalTimelinePosts()
-	if err != nil {
-		log.Printf("Failed to count global timeline posts: %v", err)
-		totalPosts = len(*posts)
+	nextMaxId := ""
+	if len(*posts) > webPostsPerPage {
+		*posts = (*posts)[:webPostsPerPage]
+		nextMaxId = (*posts)[webPostsPerPage-1].Cursor().String()
 	}
 
 	// Convert to PostView
@@ -779,6 +706,7 @@ func HandleGlobalTimeline(c *gin.Context, conf *util.AppConfig) {
 		messageHTML = util.LinkifyRawURLsHTML(messageHTML)
 		messageHTML = util.HighlightHashtagsHTML(messageHTML)
 		messageHTML = util.HighlightMentionsHTML(messageHTML, conf.Conf.SslDomain)
+		messageHTML = util.CustomEmojiToHTML(messageHTML, post.Emojis)
 
 		// Prefer ObjectURL (web-friendly) for display, fall back to ObjectURI
 		displayURL := post.ObjectURL
@@ -801,6 +729,7 @@ func HandleGlobalTimeline(c *gin.Context, conf *util.AppConfig) {
 			ReplyCount:  post.ReplyCount,
 			LikeCount:   post.LikeCount,
 			BoostCount:  post.BoostCount,
+			Reactions:   post.Reactions,
 			BoostedBy:   post.BoostedBy,
 		}
 		postViews = append(postViews, postView)
@@ -828,21 +757,15 @@ func HandleGlobalTimeline(c *gin.Context, conf *util.AppConfig) {
 		}
 	}
 
-	end := offset + postsPerPage
-	if end > totalPosts {
-		end = totalPosts
-	}
-
 	data := gin.H{
 		"Title":         "Global Timeline",
 		"Host":          host,
 		"SSHPort":       conf.Conf.SshPort,
 		"Version":       util.GetVersion(),
 		"Posts":         postViews,
-		"HasPrev":       page > 1,
-		"HasNext":       end < totalPosts,
-		"PrevPage":      page - 1,
-		"NextPage":      page + 1,
+		"HasPrev":       !before.IsZero(),
+		"HasNext":       nextMaxId != "",
+		"NextMaxId":     nextMaxId,
 		"InfoBoxes":     infoBoxViews,
 		"ServerMessage": loadServerMessageForWeb(),
 		"ShowGlobal":    conf.Conf.ShowGlobal,
@@ -854,6 +777,7 @@ func HandleGlobalTimeline(c *gin.Context, conf *util.AppConfig) {
 func HandleTagFeed(c *gin.Context, conf *util.AppConfig) {
 	tag := c.Param("tag")
 	database := db.GetDB()
+	localEmojis := localEmojiMap()
 
 	// Pagination
 	page := 1
@@ -873,6 +797,12 @@ func HandleTagFeed(c *gin.Context, conf *util.AppConfig) {
 		totalPosts = 0
 	}
 
+	followers, err := database.CountHashtagFollowers(tag)
+	if err != nil {
+		log.Printf("Failed to count followers for hashtag %s: %v", tag, err)
+		followers = 0
+	}
+
 	// Get notes with this hashtag
 	err, notes := database.ReadNotesByHashtag(tag, postsPerPage, offset)
 	if err != nil {
@@ -893,19 +823,27 @@ func HandleTagFeed(c *gin.Context, conf *util.AppConfig) {
 		messageHTML = util.LinkifyRawURLsHTML(messageHTML)
 		messageHTML = util.HighlightHashtagsHTML(messageHTML)
 		messageHTML = util.HighlightMentionsHTML(messageHTML, conf.Conf.SslDomain)
+		messageHTML = util.CustomEmojiToHTML(messageHTML, localEmojis)
+
+		// Articles are listed as a teaser; the full text is on their own page
+		if note.IsArticle() {
+			messageHTML = template.HTMLEscapeString(util.ArticleSummary(note.Message, util.ArticleSummaryLength))
+		}
 
 		// Get reply count for this post (including remote replies when AP is enabled)
-		replyCount := countTotalRepliesForWeb(database, note.Id, conf.Conf.SslDomain, conf.Conf.WithAp)
+		replyCount := actions.CountReplies(database, note.Id, conf.Conf.SslDomain, conf.Conf.WithAp)
 
 		posts = append(posts, PostView{
-			NoteId:      note.Id.String(),
-			Username:    note.CreatedBy,
-			Message:     note.Message,
-			MessageHTML: template.HTML(messageHTML),
-			TimeAgo:     formatTimeAgo(note.CreatedAt),
-			ReplyCount:  replyCount,
-			LikeCount:   note.LikeCount,
-			BoostCount:  note.BoostCount,
+			NoteId:       note.Id.String(),
+			Username:     note.CreatedBy,
+			Message:      note.Message,
+			MessageHTML:  template.HTML(messageHTML),
+			TimeAgo:      formatTimeAgo(note.CreatedAt),
+			ReplyCount:   replyCount,
+			LikeCount:    note.LikeCount,
+			BoostCount:   note.BoostCount,
+			Reactions:    readReactionsForWeb(database, note.Id),
+			ArticleTitle: note.Title,
 		})
 	}
 
@@ -944,6 +882,7 @@ func HandleTagFeed(c *gin.Context, conf *util.AppConfig) {
 		Tag:           tag,
 		Posts:         posts,
 		TotalPosts:    totalPosts,
+		Followers:     followers,
 		HasPrev:       page > 1,
 		HasNext:       end < totalPosts,
 		PrevPage:      page - 1,
@@ -116,15 +116,14 @@ func TestFormatTimeAgoEdgeCases(t *testing.T) {
 func TestIndexPageDataStructure(t *testing.T) {
 	// Test IndexPageData structure
 	data := IndexPageData{
-		Title:    "Home",
-		Host:     "example.com",
-		SSHPort:  23232,
-		Version:  "1.0.0",
-		Posts:    []PostView{},
-		HasPrev:  false,
-		HasNext:  true,
-		PrevPage: 0,
-		NextPage: 2,
+		Title:     "Home",
+		Host:      "example.com",
+		SSHPort:   23232,
+		Version:   "1.0.0",
+		Posts:     []PostView{},
+		HasPrev:   false,
+		HasNext:   true,
+		NextMaxId: "1760000000_00000000-0000-0000-0000-000000000001",
 	}
 
 	if data.Title != "Home" {
@@ -164,8 +163,6 @@ func TestProfilePageDataStructure(t *testing.T) {
 		TotalPosts: 0,
 		HasPrev:    false,
 		HasNext:    false,
-		PrevPage:   0,
-		NextPage:   2,
 	}
 
 	if data.Title != "@alice" {
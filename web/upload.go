@@ -96,9 +96,30 @@ func HandleUploadSubmit(c *gin.Context, conf *util.AppConfig) {
 		return
 	}
 
+	// Custom emoji uploads are admin-only
+	if tokenType == "emoji" && !account.IsAdmin {
+		log.Printf("Non-admin %s tried to upload a custom emoji", account.Username)
+		c.HTML(403, "upload.html", gin.H{
+			"Error": "Only admins can upload custom emoji.",
+		})
+		return
+	}
+
 	// Limit request body size
 	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadSize)
 
+	// Validate the shortcode before processing the image
+	shortcode := strings.Trim(strings.TrimSpace(c.PostForm("shortcode")), ":")
+	if tokenType == "emoji" && !isValidShortcode(shortcode) {
+		c.HTML(400, "upload.html", gin.H{
+			"Username":  account.Username,
+			"TokenType": tokenType,
+			"Token":     token,
+			"Error":     "Invalid shortcode. Use 2-64 letters, numbers or underscores.",
+		})
+		return
+	}
+
 	// Get the uploaded file
 	file, header, err := c.Request.FormFile("avatar")
 	if err != nil {
@@ -167,6 +188,33 @@ func HandleUploadSubmit(c *gin.Context, conf *util.AppConfig) {
 
 	log.Printf("Uploaded image format: %s, size: %dx%d", format, img.Bounds().Dx(), img.Bounds().Dy())
 
+	if tokenType == "emoji" {
+		if err := saveCustomEmoji(img, shortcode, accountId); err != nil {
+			log.Printf("Failed to save custom emoji: %v", err)
+			c.HTML(500, "upload.html", gin.H{
+				"Username":  account.Username,
+				"TokenType": tokenType,
+				"Token":     token,
+				"Error":     "Server error. Please try again later.",
+			})
+			return
+		}
+
+		// Delete the used token
+		if err := database.DeleteUploadToken(token); err != nil {
+			log.Printf("Warning: Failed to delete used upload token: %v", err)
+		}
+
+		log.Printf("Custom emoji :%s: uploaded by %s", shortcode, account.Username)
+
+		c.HTML(200, "upload.html", gin.H{
+			"Username":  account.Username,
+			"TokenType": tokenType,
+			"Success":   fmt.Sprintf("Emoji :%s: uploaded successfully! You can close this page.", shortcode),
+		})
+		return
+	}
+
 	// Resize image if needed
 	resized := resizeImage(img, maxAvatarSize)
 